@@ -16,7 +16,8 @@ type ActiveRequest struct {
 	ID                  int64   `json:"id"`
 	Model               string  `json:"model"`
 	ClientIP            string  `json:"client_ip"`
-	StartTime           int64   `json:"start_time"` // Unix毫秒
+	StartTime           int64   `json:"start_time"` // Unix毫秒（当前渠道尝试的开始时间，切换渠道/Key 时重置）
+	ElapsedMs           int64   `json:"elapsed_ms"` // 自请求进入以来的总耗时（毫秒，不随渠道切换重置）
 	Streaming           bool    `json:"is_streaming"`
 	ChannelID           int64   `json:"channel_id,omitempty"`
 	ChannelName         string  `json:"channel_name,omitempty"`
@@ -36,6 +37,7 @@ type activeRequest struct {
 	Model       string
 	ClientIP    string
 	StartTime   int64 // Unix毫秒
+	ReceivedAt  int64 // 请求进入时间（Unix毫秒），不随渠道切换重置
 	Streaming   bool
 	ChannelID   int64
 	ChannelName string
@@ -77,11 +79,12 @@ func newActiveRequestManager() *activeRequestManager {
 func (m *activeRequestManager) Register(startTime time.Time, model, clientIP string, streaming bool) int64 {
 	id := m.nextID.Add(1)
 	req := &activeRequest{
-		ID:        id,
-		Model:     model,
-		ClientIP:  clientIP,
-		StartTime: startTime.UnixMilli(),
-		Streaming: streaming,
+		ID:         id,
+		Model:      model,
+		ClientIP:   clientIP,
+		StartTime:  startTime.UnixMilli(),
+		ReceivedAt: startTime.UnixMilli(),
+		Streaming:  streaming,
 	}
 	m.mu.Lock()
 	m.requests[id] = req
@@ -97,7 +100,7 @@ func (m *activeRequestManager) Update(id int64, channelID int64, channelName, ch
 		req.ChannelID = channelID
 		req.ChannelName = channelName
 		req.ChannelType = channelType
-		req.APIKeyUsed = util.MaskAPIKey(apiKey)
+		req.APIKeyUsed = util.MaskLogAPIKey(apiKey)
 		req.TokenID = tokenID
 		req.CostMultiplier = costMultiplier
 		req.StartTime = time.Now().UnixMilli()
@@ -188,6 +191,7 @@ func (m *activeRequestManager) SetClientFirstByteTime(id int64, d time.Duration)
 
 // List 返回所有活跃请求的快照（按开始时间降序，最新的在前）
 func (m *activeRequestManager) List() []*ActiveRequest {
+	nowMs := time.Now().UnixMilli()
 	m.mu.RLock()
 	result := make([]*ActiveRequest, 0, len(m.requests))
 	for _, req := range m.requests {
@@ -196,6 +200,7 @@ func (m *activeRequestManager) List() []*ActiveRequest {
 			Model:             req.Model,
 			ClientIP:          req.ClientIP,
 			StartTime:         req.StartTime,
+			ElapsedMs:         max(nowMs-req.ReceivedAt, 0),
 			Streaming:         req.Streaming,
 			ChannelID:         req.ChannelID,
 			ChannelName:       req.ChannelName,
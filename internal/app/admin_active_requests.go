@@ -2,6 +2,7 @@ package app
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,19 @@ func (s *Server) HandleActiveRequests(c *gin.Context) {
 	RespondJSONWithCount(c, http.StatusOK, requests, len(requests))
 }
 
+// HandleInflightRequests 返回正在代理的请求，按总耗时降序（最久的在前，便于定位占用并发槽位的卡住请求）
+// GET /admin/inflight
+func (s *Server) HandleInflightRequests(c *gin.Context) {
+	var requests []*ActiveRequest
+	if s.activeRequests != nil {
+		requests = s.activeRequests.List()
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].ElapsedMs > requests[j].ElapsedMs
+	})
+	RespondJSONWithCount(c, http.StatusOK, requests, len(requests))
+}
+
 // HandleGetActiveRequestDebugLog 返回运行中请求的调试日志快照。
 // GET /admin/active-requests/:request_id/debug-log
 func (s *Server) HandleGetActiveRequestDebugLog(c *gin.Context) {
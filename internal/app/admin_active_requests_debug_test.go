@@ -125,3 +125,41 @@ func TestHandleGetActiveRequestDebugLog_ReturnsLiveSnapshot(t *testing.T) {
 		t.Fatalf("resp_body=%v, want partial snapshot", respPayload.Data["resp_body"])
 	}
 }
+
+func TestHandleInflightRequests_SortsByTotalElapsed(t *testing.T) {
+	t.Parallel()
+
+	srv := newInMemoryServer(t)
+	m := newActiveRequestManager()
+	now := time.Now()
+	stuck := m.Register(now.Add(-10*time.Minute), "claude-3-opus", "1.2.3.4", true)
+	fresh := m.Register(now.Add(-time.Second), "gpt-4o", "5.6.7.8", false)
+	// 切换渠道会重置 start_time，但总耗时仍从请求进入时算起
+	m.Update(stuck, 7, "ch-7", "anthropic", "sk-test-key-123456", 0, 1)
+	srv.activeRequests = m
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/inflight", nil))
+	srv.HandleInflightRequests(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp struct {
+		Data  []ActiveRequest `json:"data"`
+		Count int             `json:"count"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	if resp.Count != 2 || len(resp.Data) != 2 {
+		t.Fatalf("unexpected resp: %+v", resp)
+	}
+	first := resp.Data[0]
+	if first.ID != stuck || first.ChannelName != "ch-7" || !first.Streaming || first.Model != "claude-3-opus" {
+		t.Fatalf("expected stuck request first, got %+v", first)
+	}
+	if first.ElapsedMs < (10 * time.Minute).Milliseconds() {
+		t.Fatalf("elapsed_ms=%d should cover the whole request", first.ElapsedMs)
+	}
+	if resp.Data[1].ID != fresh || resp.Data[1].ElapsedMs >= first.ElapsedMs {
+		t.Fatalf("unexpected second entry: %+v", resp.Data[1])
+	}
+}
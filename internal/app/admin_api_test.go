@@ -9,6 +9,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"slices"
 	"strings"
 	"testing"
@@ -1134,3 +1135,168 @@ func TestHealthEndpoint(t *testing.T) {
 		t.Fatalf("期望 status='ok'，实际: %v", resp.Data.Status)
 	}
 }
+
+func newCSVImportContext(t *testing.T, target, csvContent string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "import.csv")
+	if err != nil {
+		t.Fatalf("创建表单文件字段失败: %v", err)
+	}
+	if _, err := io.WriteString(part, csvContent); err != nil {
+		t.Fatalf("写入CSV内容失败: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("关闭writer失败: %v", err)
+	}
+	req := newRequest(http.MethodPost, target, bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return newTestContext(t, req)
+}
+
+func TestAdminAPI_ImportChannelsCSV_UpdateOnlyMode(t *testing.T) {
+	server := newInMemoryServer(t)
+	ctx := context.Background()
+
+	created, err := server.store.CreateConfig(ctx, &model.Config{
+		Name:           "Update-Only-A",
+		URL:            "https://a.example.com",
+		Priority:       10,
+		RPMLimit:       30,
+		ModelEntries:   []model.ModelEntry{{Model: "model-a"}},
+		ChannelType:    "anthropic",
+		Enabled:        true,
+		MaxConcurrency: 5,
+	})
+	if err != nil {
+		t.Fatalf("创建现有渠道失败: %v", err)
+	}
+
+	csvContent := "name,priority,enabled\nUpdate-Only-A,99,false\nMissing-Channel,1,true\n"
+	c, w := newCSVImportContext(t, "/admin/channels/import?mode=update", csvContent)
+	server.HandleImportChannelsCSV(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 200, 实际 %d, 响应: %s", w.Code, w.Body.String())
+	}
+	var summary ChannelImportSummary
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if summary.Created != 0 || summary.Updated != 1 || summary.Skipped != 1 || summary.Processed != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(summary.Errors) != 1 || !strings.Contains(summary.Errors[0], "Missing-Channel") {
+		t.Fatalf("期望报告不存在的渠道，实际 %v", summary.Errors)
+	}
+
+	updated, err := server.store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("查询更新后的渠道失败: %v", err)
+	}
+	if updated.Priority != 99 || updated.Enabled {
+		t.Fatalf("期望 priority=99 enabled=false，实际 priority=%d enabled=%v", updated.Priority, updated.Enabled)
+	}
+	// 未出现的列保持原值
+	if updated.URL != "https://a.example.com" || updated.RPMLimit != 30 || updated.MaxConcurrency != 5 {
+		t.Fatalf("未出现的列不应被修改: %+v", updated)
+	}
+	if len(updated.ModelEntries) != 1 || updated.ModelEntries[0].Model != "model-a" {
+		t.Fatalf("模型不应被修改，实际 %+v", updated.ModelEntries)
+	}
+
+	configs, err := server.store.ListConfigs(ctx)
+	if err != nil {
+		t.Fatalf("ListConfigs失败: %v", err)
+	}
+	if len(configs) != 1 {
+		t.Fatalf("仅更新模式不应创建渠道，实际渠道数 %d", len(configs))
+	}
+}
+
+func TestAdminAPI_ImportChannelsCSV_UpdateOnlyModeRejectsUnsupportedColumns(t *testing.T) {
+	server := newInMemoryServer(t)
+
+	for _, csvContent := range []string{
+		"name,api_key\nA,sk-x\n",
+		"name\nA\n",
+		"priority\n1\n",
+	} {
+		c, w := newCSVImportContext(t, "/admin/channels/import?mode=update", csvContent)
+		server.HandleImportChannelsCSV(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("CSV %q 期望状态码 400, 实际 %d, 响应: %s", csvContent, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAdminAPI_ImportChannelsCSV_BatchedCommit(t *testing.T) {
+	server := newInMemoryServer(t)
+
+	var sb strings.Builder
+	sb.WriteString("name,url,models,api_key\n")
+	for i := range 5 {
+		fmt.Fprintf(&sb, "Batch-%d,https://b%d.example.com,m,sk-%d\n", i, i, i)
+	}
+	sb.WriteString("Bad-Row,,m,sk-x\n")
+
+	c, w := newCSVImportContext(t, "/admin/channels/import?batch_size=2", sb.String())
+	server.HandleImportChannelsCSV(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 200, 实际 %d, 响应: %s", w.Code, w.Body.String())
+	}
+	var summary ChannelImportSummary
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if summary.Created != 5 || summary.Skipped != 1 || summary.Processed != 6 || summary.Batches != 3 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	configs, err := server.store.ListConfigs(context.Background())
+	if err != nil {
+		t.Fatalf("ListConfigs失败: %v", err)
+	}
+	if len(configs) != 5 {
+		t.Fatalf("期望导入5个渠道，实际 %d", len(configs))
+	}
+}
+
+func TestAdminAPI_ImportChannelsCSV_ProgressStream(t *testing.T) {
+	server := newInMemoryServer(t)
+
+	csvContent := "name,url,models,api_key\nP-1,https://p1.example.com,m,sk-1\nP-2,https://p2.example.com,m,sk-2\nP-3,https://p3.example.com,m,sk-3\n"
+	c, w := newCSVImportContext(t, "/admin/channels/import?batch_size=2&progress=true", csvContent)
+	server.HandleImportChannelsCSV(c)
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("期望 SSE 响应，实际 Content-Type=%q", ct)
+	}
+	body := w.Body.String()
+	if got := strings.Count(body, "event: progress\n"); got != 2 {
+		t.Fatalf("期望2个progress事件，实际 %d: %s", got, body)
+	}
+	idx := strings.Index(body, "event: done\ndata: ")
+	if idx < 0 {
+		t.Fatalf("缺少done事件: %s", body)
+	}
+	var summary ChannelImportSummary
+	data := strings.TrimSpace(body[idx+len("event: done\ndata: "):])
+	mustUnmarshalJSON(t, []byte(data), &summary)
+	if summary.Created != 3 || summary.Batches != 2 {
+		t.Fatalf("unexpected final summary: %+v", summary)
+	}
+}
+
+func TestParseCSVImportBatchSize(t *testing.T) {
+	cases := map[string]int{
+		"":      defaultCSVImportBatchSize,
+		"abc":   defaultCSVImportBatchSize,
+		"0":     defaultCSVImportBatchSize,
+		"50":    50,
+		"99999": maxCSVImportBatchSize,
+	}
+	for raw, want := range cases {
+		if got := parseCSVImportBatchSize(raw); got != want {
+			t.Errorf("parseCSVImportBatchSize(%q) = %d, want %d", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== 管理操作审计 ====================
+// 记录谁（会话）、何时、对哪个渠道做了什么变更，供多管理员场景事后追溯
+
+const (
+	auditChangesContextKey   = "ccLoad.auditChanges"
+	auditChannelIDContextKey = "ccLoad.auditChannelID"
+
+	auditDefaultHours = 168
+	auditMaxHours     = 24 * 90
+	auditDefaultLimit = 200
+	auditMaxLimit     = 1000
+
+	// auditWriteTimeout 审计写入超时：同步写入，避免进程退出时丢失记录
+	auditWriteTimeout = 3 * time.Second
+)
+
+// adminReadOnlyRoutes 不改变配置的 POST 路由（测试/预览类）：不记录审计，维护模式下也放行
+var adminReadOnlyRoutes = map[string]bool{
+	"/admin/channels/check-duplicate":   true,
+	"/admin/channels/test-all":          true,
+	"/admin/channels/models/fetch":      true,
+	"/admin/channels/:id/test":          true,
+	"/admin/channels/:id/test-url":      true,
+	"/admin/channels/:id/chat":          true,
+	"/admin/debug-logs/merged-response": true,
+	"/admin/fingerprints/test":          true,
+}
+
+// auditDiffIgnoredFields 渠道 diff 中忽略的派生/运行时字段
+var auditDiffIgnoredFields = map[string]bool{
+	"created_at":           true,
+	"updated_at":           true,
+	"key_count":            true,
+	"cooldown_until":       true,
+	"cooldown_duration_ms": true,
+	"consecutive_failures": true,
+	"last_used_at":         true,
+}
+
+// auditMiddleware 在管理端写操作成功后记录审计日志（需挂在 RequireAdminAuth 之后）
+func (s *Server) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions ||
+			adminReadOnlyRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest || c.FullPath() == "" {
+			return
+		}
+		entry := &model.AuditLog{
+			Time:       model.JSONTime{Time: time.Now()},
+			Actor:      auditActor(c),
+			ClientIP:   c.ClientIP(),
+			Action:     method + " " + c.FullPath(),
+			ChannelID:  auditChannelID(c),
+			StatusCode: status,
+		}
+		if changes, ok := c.Get(auditChangesContextKey); ok {
+			entry.Changes, _ = changes.(map[string]model.AuditChange)
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditWriteTimeout)
+		defer cancel()
+		if err := s.store.AddAuditLog(ctx, entry); err != nil {
+			log.Printf("[WARN] 写入审计日志失败 (%s): %v", entry.Action, err)
+		}
+	}
+}
+
+// auditActor 操作者标识：admin:<会话哈希前缀>
+func auditActor(c *gin.Context) string {
+	identity, ok := WebIdentityFromContext(c)
+	if !ok {
+		return "unknown"
+	}
+	if identity.SessionID == "" {
+		return string(identity.Role)
+	}
+	return string(identity.Role) + ":" + identity.SessionID
+}
+
+// auditChannelID 优先使用处理器显式设置的渠道 ID，其次取 /admin/channels/:id 路由参数
+func auditChannelID(c *gin.Context) int64 {
+	if v, ok := c.Get(auditChannelIDContextKey); ok {
+		if id, ok := v.(int64); ok {
+			return id
+		}
+	}
+	if !strings.HasPrefix(c.FullPath(), "/admin/channels/:id") {
+		return 0
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// setAuditChanges 由处理器附加变更详情（与中间件记录的同一条审计日志关联）
+func setAuditChanges(c *gin.Context, channelID int64, changes map[string]model.AuditChange) {
+	if channelID > 0 {
+		c.Set(auditChannelIDContextKey, channelID)
+	}
+	if len(changes) > 0 {
+		c.Set(auditChangesContextKey, changes)
+	}
+}
+
+// setAuditChannelDiff 计算渠道配置前后差异并附加到审计日志；before/after 为 nil 分别表示新建/删除
+func setAuditChannelDiff(c *gin.Context, before, after *model.Config) map[string]model.AuditChange {
+	changes := diffConfigs(before, after)
+	channelID := int64(0)
+	if after != nil {
+		channelID = after.ID
+	} else if before != nil {
+		channelID = before.ID
+	}
+	setAuditChanges(c, channelID, changes)
+	return changes
+}
+
+// diffConfigs 按 JSON 字段比较两个渠道配置，返回变化字段的新旧值（不含 API Key）
+func diffConfigs(before, after *model.Config) map[string]model.AuditChange {
+	oldFields := configFieldMap(before)
+	newFields := configFieldMap(after)
+	changes := make(map[string]model.AuditChange)
+	for field, newValue := range newFields {
+		if auditDiffIgnoredFields[field] {
+			continue
+		}
+		oldValue, ok := oldFields[field]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = model.AuditChange{Old: oldValue, New: newValue}
+		}
+	}
+	for field, oldValue := range oldFields {
+		if auditDiffIgnoredFields[field] {
+			continue
+		}
+		if _, ok := newFields[field]; !ok {
+			changes[field] = model.AuditChange{Old: oldValue}
+		}
+	}
+	return changes
+}
+
+func configFieldMap(cfg *model.Config) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	data, err := sonic.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := sonic.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// HandleAuditLogs 查询管理操作审计日志
+// GET /admin/audit?hours=168&channel_id=1&limit=200
+func (s *Server) HandleAuditLogs(c *gin.Context) {
+	hours := auditDefaultHours
+	if raw := strings.TrimSpace(c.Query("hours")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > auditMaxHours {
+			RespondErrorMsg(c, http.StatusBadRequest, "hours must be between 1 and "+strconv.Itoa(auditMaxHours))
+			return
+		}
+		hours = n
+	}
+	var channelID int64
+	if raw := strings.TrimSpace(c.Query("channel_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			RespondErrorMsg(c, http.StatusBadRequest, "invalid channel_id")
+			return
+		}
+		channelID = id
+	}
+	limit := auditDefaultLimit
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > auditMaxLimit {
+			RespondErrorMsg(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(auditMaxLimit))
+			return
+		}
+		limit = n
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	entries, err := s.store.ListAuditLogs(c.Request.Context(), since, channelID, limit)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	RespondJSON(c, http.StatusOK, entries)
+}
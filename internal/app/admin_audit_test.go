@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newAuditTestEngine(srv *Server) *gin.Engine {
+	r := gin.New()
+	admin := r.Group("/admin")
+	admin.Use(func(c *gin.Context) {
+		c.Set(webIdentityContextKey, WebIdentity{Role: model.WebRoleAdmin, SessionID: webSessionID("session-token")})
+		c.Next()
+	}, srv.auditMiddleware())
+	admin.PUT("/channels/:id", srv.HandleChannelByID)
+	admin.POST("/channels/:id/test", func(c *gin.Context) { RespondJSON(c, http.StatusOK, gin.H{"ok": true}) })
+	admin.POST("/channels/:id/cooldown", srv.HandleSetChannelCooldown)
+	admin.GET("/audit", srv.HandleAuditLogs)
+	return r
+}
+
+func TestAdminAudit_RecordsChannelUpdateDiff(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name:         "audit-ch",
+		URL:          "https://a.example.com",
+		Priority:     1,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+	r := newAuditTestEngine(srv)
+	channelPath := "/admin/channels/" + strconv.FormatInt(cfg.ID, 10)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest(http.MethodPut, channelPath, strings.NewReader(`{"enabled":false}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// 只读测试路由与失败请求不记录
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest(http.MethodPost, channelPath+"/test", strings.NewReader(`{}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("test route status=%d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest(http.MethodPut, "/admin/channels/abc", strings.NewReader(`{"enabled":true}`)))
+	if w.Code < http.StatusBadRequest {
+		t.Fatalf("expected failure for invalid id, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest(http.MethodGet, "/admin/audit?hours=168", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("audit status=%d body=%s", w.Code, w.Body.String())
+	}
+	var entries []model.AuditLog
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &entries)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d: %+v", len(entries), entries)
+	}
+	entry := entries[0]
+	if entry.Action != "PUT /admin/channels/:id" || entry.ChannelID != cfg.ID || entry.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected audit entry: %+v", entry)
+	}
+	if entry.Actor != "admin:"+webSessionID("session-token") {
+		t.Fatalf("unexpected actor: %q", entry.Actor)
+	}
+	change, ok := entry.Changes["enabled"]
+	if !ok || change.Old != true || change.New != false {
+		t.Fatalf("expected enabled true->false diff, got %+v", entry.Changes)
+	}
+	if len(entry.Changes) != 1 {
+		t.Fatalf("diff should only contain changed fields, got %+v", entry.Changes)
+	}
+}
+
+func TestAdminAudit_ChannelFilterAndValidation(t *testing.T) {
+	srv := newInMemoryServer(t)
+	r := newAuditTestEngine(srv)
+
+	for _, id := range []string{"1", "2"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newRequest(http.MethodPost, "/admin/channels/"+id+"/cooldown", strings.NewReader(`{"duration_ms":60000}`)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("cooldown status=%d body=%s", w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest(http.MethodGet, "/admin/audit?channel_id=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("audit status=%d body=%s", w.Code, w.Body.String())
+	}
+	var entries []model.AuditLog
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &entries)
+	if len(entries) != 1 || entries[0].ChannelID != 2 {
+		t.Fatalf("expected only channel 2 entry, got %+v", entries)
+	}
+	if _, ok := entries[0].Changes["cooldown_until"]; !ok {
+		t.Fatalf("expected cooldown_until change, got %+v", entries[0].Changes)
+	}
+	if entries[0].Time.Before(time.Now().Add(-time.Minute)) {
+		t.Fatalf("unexpected audit time: %v", entries[0].Time)
+	}
+
+	for _, query := range []string{"hours=0", "hours=abc", "channel_id=-1", "limit=5000"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, newRequest(http.MethodGet, "/admin/audit?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("query %q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestDiffConfigs_IgnoresRuntimeFields(t *testing.T) {
+	t.Parallel()
+
+	before := &model.Config{ID: 1, Name: "a", Priority: 1, CooldownUntil: 100, KeyCount: 2}
+	after := &model.Config{ID: 1, Name: "a", Priority: 5, CooldownUntil: 0, KeyCount: 3}
+	changes := diffConfigs(before, after)
+	if len(changes) != 1 {
+		t.Fatalf("expected only priority change, got %+v", changes)
+	}
+	if c := changes["priority"]; c.Old != float64(1) || c.New != float64(5) {
+		t.Fatalf("unexpected priority change: %+v", c)
+	}
+
+	created := diffConfigs(nil, after)
+	if c, ok := created["name"]; !ok || c.Old != nil || c.New != "a" {
+		t.Fatalf("create diff should carry new values only, got %+v", created)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -161,6 +163,7 @@ func (s *Server) HandleCreateAuthToken(c *gin.Context) {
 		ChannelRestrictionMode string   `json:"channel_restriction_mode"` // allow|deny，默认 allow
 		CostLimitUSD           *float64 `json:"cost_limit_usd"`           // 费用上限（0=无限制）
 		MaxConcurrency         *int     `json:"max_concurrency"`          // 最大并发请求数（0=无限制）
+		RPMLimit               *int     `json:"rpm_limit"`                // 每分钟最大请求数（0=无限制）
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -175,6 +178,10 @@ func (s *Server) HandleCreateAuthToken(c *gin.Context) {
 		RespondErrorMsg(c, http.StatusBadRequest, "max_concurrency must be >= 0")
 		return
 	}
+	if req.RPMLimit != nil && *req.RPMLimit < 0 {
+		RespondErrorMsg(c, http.StatusBadRequest, "rpm_limit must be >= 0")
+		return
+	}
 	channelRestrictionMode, err := model.NormalizeChannelRestrictionMode(req.ChannelRestrictionMode)
 	if err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
@@ -213,6 +220,9 @@ func (s *Server) HandleCreateAuthToken(c *gin.Context) {
 	if req.MaxConcurrency != nil {
 		authToken.MaxConcurrency = *req.MaxConcurrency
 	}
+	if req.RPMLimit != nil {
+		authToken.RPMLimit = *req.RPMLimit
+	}
 	if err := authToken.ValidateUsageLimits(); err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
 		return
@@ -246,6 +256,7 @@ func (s *Server) HandleCreateAuthToken(c *gin.Context) {
 		"allowed_channel_ids":      authToken.AllowedChannelIDs,
 		"channel_restriction_mode": authToken.ChannelRestrictionMode,
 		"max_concurrency":          authToken.MaxConcurrency,
+		"rpm_limit":                authToken.RPMLimit,
 	})
 }
 
@@ -267,6 +278,7 @@ func (s *Server) HandleUpdateAuthToken(c *gin.Context) {
 		ChannelRestrictionMode *string           `json:"channel_restriction_mode"` // nil=不更新
 		CostLimitUSD           *float64          `json:"cost_limit_usd"`           // 费用上限（0=无限制）
 		MaxConcurrency         *int              `json:"max_concurrency"`          // 最大并发请求数（0=无限制）
+		RPMLimit               *int              `json:"rpm_limit"`                // 每分钟最大请求数（0=无限制）
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -281,6 +293,10 @@ func (s *Server) HandleUpdateAuthToken(c *gin.Context) {
 		RespondErrorMsg(c, http.StatusBadRequest, "max_concurrency must be >= 0")
 		return
 	}
+	if req.RPMLimit != nil && *req.RPMLimit < 0 {
+		RespondErrorMsg(c, http.StatusBadRequest, "rpm_limit must be >= 0")
+		return
+	}
 	var channelRestrictionMode string
 	if req.ChannelRestrictionMode != nil {
 		channelRestrictionMode, err = model.NormalizeChannelRestrictionMode(*req.ChannelRestrictionMode)
@@ -326,6 +342,9 @@ func (s *Server) HandleUpdateAuthToken(c *gin.Context) {
 	if req.MaxConcurrency != nil {
 		token.MaxConcurrency = *req.MaxConcurrency
 	}
+	if req.RPMLimit != nil {
+		token.RPMLimit = *req.RPMLimit
+	}
 	if err := token.ValidateUsageLimits(); err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
 		return
@@ -372,3 +391,121 @@ func (s *Server) HandleDeleteAuthToken(c *gin.Context) {
 
 	RespondJSON(c, http.StatusOK, gin.H{"id": id})
 }
+
+// AuthTokenModelUsage 令牌按模型聚合的用量
+type AuthTokenModelUsage struct {
+	Model               string  `json:"model"`
+	Requests            int64   `json:"requests"`
+	SuccessCount        int64   `json:"success_count"`
+	FailureCount        int64   `json:"failure_count"`
+	PromptTokens        int64   `json:"prompt_tokens"`
+	CompletionTokens    int64   `json:"completion_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	TotalCost           float64 `json:"total_cost"`
+	EffectiveCost       float64 `json:"effective_cost"`
+}
+
+// AuthTokenUsageResponse 单个令牌在时间范围内的用量汇总
+type AuthTokenUsageResponse struct {
+	TokenID     int64  `json:"token_id"`
+	Description string `json:"description"`
+	Range       string `json:"range"`
+	StartTime   int64  `json:"start_time"` // Unix毫秒
+	EndTime     int64  `json:"end_time"`   // Unix毫秒
+	Requests    int64  `json:"requests"`
+	model.AuthTokenRangeStats
+	Models []AuthTokenModelUsage `json:"models"`
+}
+
+// HandleGetAuthTokenUsage 返回单个令牌在时间范围内的聚合用量（请求数/Token/成本，含按模型拆分）
+// GET /admin/auth-tokens/:id/usage?range=today
+func (s *Server) HandleGetAuthTokenUsage(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	token, err := s.store.GetAuthToken(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrAuthTokenNotFound) {
+			RespondErrorMsg(c, http.StatusNotFound, "token not found")
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	params := ParsePaginationParams(c)
+	startTime, endTime := params.GetTimeRange()
+	isToday := params.Range == "today"
+
+	resp := AuthTokenUsageResponse{
+		TokenID:     token.ID,
+		Description: token.Description,
+		Range:       params.Range,
+		StartTime:   startTime.UnixMilli(),
+		EndTime:     endTime.UnixMilli(),
+		Models:      make([]AuthTokenModelUsage, 0),
+	}
+
+	rangeStats, err := s.store.GetAuthTokenStatsInRange(ctx, startTime, endTime)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if stat, ok := rangeStats[id]; ok {
+		if err := s.store.FillAuthTokenRPMStats(ctx, map[int64]*model.AuthTokenRangeStats{id: stat}, startTime, endTime, isToday); err != nil {
+			log.Printf("[WARN]  计算token RPM统计失败: %v", err)
+		}
+		resp.AuthTokenRangeStats = *stat
+		resp.Requests = stat.SuccessCount + stat.FailureCount
+	}
+
+	// 按模型拆分（GetStats 按渠道+模型分组，这里合并为按模型）
+	entries, err := s.store.GetStats(ctx, startTime, endTime, &model.LogFilter{AuthTokenID: &id}, isToday)
+	if err != nil {
+		log.Printf("[WARN]  查询令牌模型用量失败: %v", err)
+	}
+	byModel := make(map[string]*AuthTokenModelUsage)
+	for i := range entries {
+		e := &entries[i]
+		u, ok := byModel[e.Model]
+		if !ok {
+			u = &AuthTokenModelUsage{Model: e.Model}
+			byModel[e.Model] = u
+		}
+		u.Requests += int64(e.Total)
+		u.SuccessCount += int64(e.Success)
+		u.FailureCount += int64(e.Error)
+		u.PromptTokens += derefOrZero(e.TotalInputTokens)
+		u.CompletionTokens += derefOrZero(e.TotalOutputTokens)
+		u.CacheReadTokens += derefOrZero(e.TotalCacheReadInputTokens)
+		u.CacheCreationTokens += derefOrZero(e.TotalCacheCreationInputTokens)
+		u.TotalCost += derefOrZero(e.TotalCost)
+		u.EffectiveCost += derefOrZero(e.EffectiveCost)
+	}
+	for _, u := range byModel {
+		resp.Models = append(resp.Models, *u)
+	}
+	sort.Slice(resp.Models, func(i, j int) bool {
+		if resp.Models[i].Requests != resp.Models[j].Requests {
+			return resp.Models[i].Requests > resp.Models[j].Requests
+		}
+		return resp.Models[i].Model < resp.Models[j].Model
+	})
+
+	RespondJSON(c, http.StatusOK, resp)
+}
+
+func derefOrZero[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
 )
 
 func TestAuthToken_MaskToken(t *testing.T) {
@@ -605,3 +607,85 @@ func TestHandleListAuthTokens_RPMStats(t *testing.T) {
 		t.Error("Expected rpm_stats field in response")
 	}
 }
+
+func TestHandleGetAuthTokenUsage(t *testing.T) {
+	server := newInMemoryServer(t)
+	token := createTestToken(t, server, "usage-token")
+	other := createTestToken(t, server, "usage-other")
+
+	ctx := context.Background()
+	cfg, err := server.store.CreateConfig(ctx, &model.Config{
+		Name:         "usage-ch",
+		URL:          "https://test.com",
+		Priority:     100,
+		ModelEntries: []model.ModelEntry{{Model: "model-a"}, {Model: "model-b"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+
+	now := time.Now()
+	logs := []*model.LogEntry{
+		{Model: "model-a", StatusCode: 200, AuthTokenID: token.ID, InputTokens: 100, OutputTokens: 50, Cost: 0.01},
+		{Model: "model-a", StatusCode: 200, AuthTokenID: token.ID, InputTokens: 200, OutputTokens: 20, Cost: 0.02},
+		{Model: "model-b", StatusCode: 500, AuthTokenID: token.ID},
+		{Model: "model-a", StatusCode: 200, AuthTokenID: other.ID, InputTokens: 999, OutputTokens: 999, Cost: 1},
+	}
+	for i, entry := range logs {
+		entry.Time = model.JSONTime{Time: now.Add(-time.Duration(i+1) * time.Second)}
+		entry.ChannelID = cfg.ID
+		entry.Duration = 0.2
+		if err := server.store.AddLog(ctx, entry); err != nil {
+			t.Fatalf("AddLog failed: %v", err)
+		}
+	}
+
+	t.Run("aggregates only the requested token", func(t *testing.T) {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/auth-tokens/"+strconv.FormatInt(token.ID, 10)+"/usage?range=today", nil))
+		c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(token.ID, 10)}}
+		server.HandleGetAuthTokenUsage(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d, body=%s", w.Code, w.Body.String())
+		}
+		resp := mustParseAPIResponse[AuthTokenUsageResponse](t, w.Body.Bytes())
+		got := resp.Data
+		if got.TokenID != token.ID || got.Range != "today" {
+			t.Fatalf("unexpected header: %+v", got)
+		}
+		if got.Requests != 3 || got.SuccessCount != 2 || got.FailureCount != 1 {
+			t.Fatalf("unexpected counts: requests=%d success=%d failure=%d", got.Requests, got.SuccessCount, got.FailureCount)
+		}
+		if got.PromptTokens != 300 || got.CompletionTokens != 70 {
+			t.Fatalf("unexpected tokens: prompt=%d completion=%d", got.PromptTokens, got.CompletionTokens)
+		}
+		if math.Abs(got.TotalCost-0.03) > 1e-9 {
+			t.Fatalf("unexpected total cost: %f", got.TotalCost)
+		}
+		if len(got.Models) != 2 || got.Models[0].Model != "model-a" || got.Models[0].Requests != 2 {
+			t.Fatalf("unexpected model breakdown: %+v", got.Models)
+		}
+		if got.Models[1].Model != "model-b" || got.Models[1].FailureCount != 1 {
+			t.Fatalf("unexpected model-b usage: %+v", got.Models[1])
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/auth-tokens/abc/usage", nil))
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		server.HandleGetAuthTokenUsage(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/auth-tokens/99999/usage", nil))
+		c.Params = gin.Params{{Key: "id", Value: "99999"}}
+		server.HandleGetAuthTokenUsage(c)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Expected 404, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== 全量配置备份/恢复 ====================
+// CSV 导出只覆盖渠道主表，这里按 JSON 保留渠道 + Key + 系统设置的完整结构，用于灾备恢复
+
+// configBackupSchemaVersion 备份格式版本：新增字段保持向后兼容时不变，不兼容变更时递增
+const configBackupSchemaVersion = 1
+
+// configBackupMaxBytes 恢复请求体上限
+const configBackupMaxBytes = 64 << 20
+
+// HandleConfigBackup 导出全部渠道（含明文 Key）与系统设置
+// GET /admin/backup
+func (s *Server) HandleConfigBackup(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	configs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	keysByChannel, err := s.store.GetAllAPIKeys(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	settings, err := s.store.ListAllSettings(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	backup := ConfigBackup{
+		SchemaVersion: configBackupSchemaVersion,
+		CreatedAt:     time.Now().Unix(),
+		Channels:      make([]model.ChannelWithKeys, 0, len(configs)),
+		Settings:      make(map[string]string, len(settings)),
+	}
+	for _, cfg := range configs {
+		// 冷却/熔断属于运行时状态，不进入备份
+		cfg.CooldownUntil = 0
+		cfg.CooldownDurationMs = 0
+		cfg.ConsecutiveFailures = 0
+		cfg.LastUsedAt = 0
+		keys := make([]model.APIKey, 0, len(keysByChannel[cfg.ID]))
+		for _, key := range keysByChannel[cfg.ID] {
+			k := *key
+			k.CooldownUntil = 0
+			k.CooldownDurationMs = 0
+			keys = append(keys, k)
+		}
+		backup.Channels = append(backup.Channels, model.ChannelWithKeys{Config: cfg, APIKeys: keys})
+	}
+	for _, setting := range settings {
+		backup.Settings[setting.Key] = setting.Value
+	}
+
+	data, err := sonic.Marshal(backup)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	filename := fmt.Sprintf("ccload-backup-%s.json", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// HandleConfigRestore 从 GET /admin/backup 的输出恢复渠道、Key 与系统设置
+// POST /admin/restore
+// 按渠道 ID 覆盖（无 ID 时按名称匹配），备份中不存在的渠道保持不变；
+// 每个渠道的 Key 整体替换。系统设置写入后需重启生效（不自动重启）。
+func (s *Server) HandleConfigRestore(c *gin.Context) {
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, configBackupMaxBytes+1))
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(raw) > configBackupMaxBytes {
+		RespondErrorMsg(c, http.StatusRequestEntityTooLarge, "backup too large")
+		return
+	}
+	var backup ConfigBackup
+	if err := sonic.Unmarshal(raw, &backup); err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, fmt.Sprintf("invalid backup: %v", err))
+		return
+	}
+	if backup.SchemaVersion <= 0 {
+		RespondErrorMsg(c, http.StatusBadRequest, "missing schema_version")
+		return
+	}
+	if backup.SchemaVersion > configBackupSchemaVersion {
+		RespondErrorMsg(c, http.StatusBadRequest, fmt.Sprintf("unsupported schema_version %d (max %d)", backup.SchemaVersion, configBackupSchemaVersion))
+		return
+	}
+
+	// 先校验设置，避免渠道已写入后才发现设置非法
+	settingUpdates := make(map[string]string, len(backup.Settings))
+	summary := ConfigRestoreSummary{}
+	for key, value := range backup.Settings {
+		setting := s.configService.GetSetting(key)
+		if setting == nil {
+			// 旧版本备份中已移除的设置项：跳过而非失败
+			summary.SettingsSkipped = append(summary.SettingsSkipped, key)
+			continue
+		}
+		if err := validateSettingValue(key, setting.ValueType, value); err != nil {
+			RespondErrorMsg(c, http.StatusBadRequest, fmt.Sprintf("invalid value for %s: %v", key, err))
+			return
+		}
+		if setting.Value != value {
+			settingUpdates[key] = value
+		}
+	}
+	sort.Strings(summary.SettingsSkipped)
+
+	ctx := c.Request.Context()
+	existing, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	nameByID := make(map[int64]string, len(existing))
+	idByName := make(map[string]int64, len(existing))
+	for _, cfg := range existing {
+		nameByID[cfg.ID] = cfg.Name
+		idByName[cfg.Name] = cfg.ID
+	}
+
+	for i := range backup.Channels {
+		created, warning, errMsg := s.restoreChannel(ctx, &backup.Channels[i], nameByID, idByName)
+		if errMsg != "" {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("第%d个渠道: %s", i+1, errMsg))
+			continue
+		}
+		if warning != "" {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("第%d个渠道: %s", i+1, warning))
+		}
+		if created {
+			summary.ChannelsCreated++
+		} else {
+			summary.ChannelsUpdated++
+		}
+		summary.KeysRestored += len(backup.Channels[i].APIKeys)
+	}
+
+	if len(settingUpdates) > 0 {
+		if err := s.configService.BatchUpdateSettings(ctx, settingUpdates); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("恢复系统设置失败: %v", err))
+		} else {
+			summary.SettingsUpdated = len(settingUpdates)
+			summary.RestartRequired = true
+		}
+	}
+
+	// 混合存储：恢复后以主库为准重建本地缓存
+	if syncer, ok := s.store.(hybridSyncer); ok {
+		syncCtx, cancel := context.WithTimeout(ctx, storageResyncTimeout)
+		if err := syncer.ResyncConfig(syncCtx); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("存储同步失败: %v", err))
+		}
+		cancel()
+	}
+
+	s.InvalidateChannelListCache()
+	s.InvalidateAllAPIKeysCache()
+	s.invalidateCooldownCache()
+
+	log.Printf("[INFO] 配置恢复完成: 新建渠道=%d 更新渠道=%d Key=%d 设置=%d 错误=%d",
+		summary.ChannelsCreated, summary.ChannelsUpdated, summary.KeysRestored, summary.SettingsUpdated, len(summary.Errors))
+	RespondJSON(c, http.StatusOK, summary)
+}
+
+// restoreChannel 写入单个渠道及其 Key，返回是否新建；errMsg 非空表示该渠道被跳过，warning 为已写入但需关注的问题。
+// Key 数量超过单渠道上限时仍完整恢复（备份即既有数据），仅返回警告。
+func (s *Server) restoreChannel(ctx context.Context, entry *model.ChannelWithKeys, nameByID map[int64]string, idByName map[string]int64) (created bool, warning, errMsg string) {
+	cfg := entry.Config
+	if cfg == nil {
+		return false, "", "缺少 config"
+	}
+	cfg.Name = strings.TrimSpace(cfg.Name)
+	if cfg.Name == "" {
+		return false, "", "缺少渠道名称"
+	}
+	if cfg.ID < 0 {
+		return false, "", fmt.Sprintf("渠道 %s ID 无效: %d", cfg.Name, cfg.ID)
+	}
+	if cfg.ID == 0 {
+		cfg.ID = idByName[cfg.Name]
+	}
+	if otherID, ok := idByName[cfg.Name]; ok && otherID != cfg.ID {
+		return false, "", fmt.Sprintf("渠道名称 %s 已被 ID=%d 占用", cfg.Name, otherID)
+	}
+	if _, err := validateChannelURLs(cfg.URL); err != nil {
+		return false, "", fmt.Sprintf("渠道 %s URL 无效: %v", cfg.Name, err)
+	}
+	if err := s.checkChannelKeyLimit(len(entry.APIKeys), 0); err != nil {
+		warning = fmt.Sprintf("渠道 %s Key 数量超限: %v", cfg.Name, err)
+	}
+	cfg.CooldownUntil = 0
+	cfg.CooldownDurationMs = 0
+	cfg.ConsecutiveFailures = 0
+
+	_, existed := nameByID[cfg.ID]
+	var saved *model.Config
+	var err error
+	if existed {
+		saved, err = s.store.UpdateConfig(ctx, cfg.ID, cfg)
+	} else {
+		saved, err = s.store.CreateConfig(ctx, cfg)
+	}
+	if err != nil {
+		return false, "", fmt.Sprintf("渠道 %s 写入失败: %v", cfg.Name, err)
+	}
+	if oldName, ok := nameByID[saved.ID]; ok && oldName != saved.Name {
+		delete(idByName, oldName)
+	}
+	nameByID[saved.ID] = saved.Name
+	idByName[saved.Name] = saved.ID
+
+	keys := make([]*model.APIKey, 0, len(entry.APIKeys))
+	for i := range entry.APIKeys {
+		key := entry.APIKeys[i]
+		key.ID = 0
+		key.ChannelID = saved.ID
+		key.KeyIndex = i
+		key.CooldownUntil = 0
+		key.CooldownDurationMs = 0
+		if key.KeyStrategy == "" {
+			key.KeyStrategy = model.KeyStrategySequential
+		}
+		keys = append(keys, &key)
+	}
+	if err := s.store.ReplaceAPIKeys(ctx, saved.ID, keys); err != nil {
+		return !existed, "", fmt.Sprintf("渠道 %s Key 写入失败: %v", saved.Name, err)
+	}
+	return !existed, warning, ""
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestAdminAPI_ConfigBackupRestoreRoundTrip(t *testing.T) {
+	src := newInMemoryServer(t)
+	ctx := context.Background()
+
+	created, err := src.store.CreateConfig(ctx, &model.Config{
+		Name:           "Backup-A",
+		URL:            "https://a.example.com",
+		Priority:       7,
+		ModelEntries:   []model.ModelEntry{{Model: "gpt-4o", RedirectModel: "gpt-4o-2024"}},
+		ChannelType:    "codex",
+		Enabled:        true,
+		CostMultiplier: 0.5,
+		Cacheable:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+	if err := src.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: created.ID, KeyIndex: 0, APIKey: "sk-a-0", Note: "primary", KeyStrategy: model.KeyStrategyRoundRobin},
+		{ChannelID: created.ID, KeyIndex: 1, APIKey: "sk-a-1", Disabled: true, KeyStrategy: model.KeyStrategyRoundRobin},
+	}); err != nil {
+		t.Fatalf("CreateAPIKeysBatch failed: %v", err)
+	}
+	if err := src.store.UpdateSetting(ctx, "max_key_retries", "5"); err != nil {
+		t.Fatalf("UpdateSetting failed: %v", err)
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/backup", nil))
+	src.HandleConfigBackup(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("backup status=%d body=%s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "ccload-backup-") {
+		t.Fatalf("unexpected Content-Disposition: %q", w.Header().Get("Content-Disposition"))
+	}
+	var backup ConfigBackup
+	if err := json.Unmarshal(w.Body.Bytes(), &backup); err != nil {
+		t.Fatalf("unmarshal backup: %v", err)
+	}
+	if backup.SchemaVersion != configBackupSchemaVersion || len(backup.Channels) != 1 || backup.Settings["max_key_retries"] != "5" {
+		t.Fatalf("unexpected backup: %+v", backup)
+	}
+
+	// 恢复到一个全新实例
+	dst := newInMemoryServer(t)
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/admin/restore", bytes.NewReader(w.Body.Bytes())))
+	dst.HandleConfigRestore(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status=%d body=%s", w.Code, w.Body.String())
+	}
+	var summary ConfigRestoreSummary
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if summary.ChannelsCreated != 1 || summary.ChannelsUpdated != 0 || summary.KeysRestored != 2 ||
+		summary.SettingsUpdated != 1 || !summary.RestartRequired || len(summary.Errors) != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	restored, err := dst.store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetConfig after restore: %v", err)
+	}
+	if restored.Name != "Backup-A" || restored.Priority != 7 || restored.CostMultiplier != 0.5 || !restored.Cacheable ||
+		len(restored.ModelEntries) != 1 || restored.ModelEntries[0].RedirectModel != "gpt-4o-2024" {
+		t.Fatalf("restored channel mismatch: %+v", restored)
+	}
+	keys, err := dst.store.GetAPIKeys(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys after restore: %v", err)
+	}
+	if len(keys) != 2 || keys[0].APIKey != "sk-a-0" || keys[0].Note != "primary" || !keys[1].Disabled ||
+		keys[1].KeyStrategy != model.KeyStrategyRoundRobin {
+		t.Fatalf("restored keys mismatch: %+v", keys)
+	}
+	setting, err := dst.store.GetSetting(ctx, "max_key_retries")
+	if err != nil || setting.Value != "5" {
+		t.Fatalf("restored setting mismatch: %+v err=%v", setting, err)
+	}
+
+	// 重复恢复：按 ID 覆盖而非重复创建
+	payload, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatalf("marshal backup: %v", err)
+	}
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/admin/restore", bytes.NewReader(payload)))
+	dst.HandleConfigRestore(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("second restore status=%d body=%s", w.Code, w.Body.String())
+	}
+	summary = ConfigRestoreSummary{}
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if summary.ChannelsCreated != 0 || summary.ChannelsUpdated != 1 {
+		t.Fatalf("second restore should update in place: %+v", summary)
+	}
+	configs, err := dst.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("expected exactly 1 channel after repeated restore, got %d err=%v", len(configs), err)
+	}
+}
+
+func TestAdminAPI_ConfigRestore_RejectsBadSchemaAndSkipsUnknownSettings(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	for _, body := range []string{
+		`{"channels":[]}`,
+		`{"schema_version":99,"channels":[]}`,
+		`not-json`,
+	} {
+		c, w := newTestContext(t, newRequest(http.MethodPost, "/admin/restore", strings.NewReader(body)))
+		srv.HandleConfigRestore(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("body %q: expected 400, got %d (%s)", body, w.Code, w.Body.String())
+		}
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodPost, "/admin/restore",
+		strings.NewReader(`{"schema_version":1,"channels":[],"settings":{"removed_setting":"1"}}`)))
+	srv.HandleConfigRestore(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var summary ConfigRestoreSummary
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if len(summary.SettingsSkipped) != 1 || summary.SettingsSkipped[0] != "removed_setting" || summary.RestartRequired {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
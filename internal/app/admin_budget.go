@@ -0,0 +1,47 @@
+package app
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetBudget 获取模型月度预算使用情况
+// GET /admin/budget
+// 已消耗成本从日志实时汇总（不读内存缓存），与 /admin/stats 的 cost 口径一致（倍率后成本）。
+func (s *Server) HandleGetBudget(c *gin.Context) {
+	resp := ModelBudgetResponse{
+		MonthStart: monthStart(time.Now()),
+		Items:      []ModelBudgetItem{},
+	}
+	if s.modelBudget == nil || !s.modelBudget.Enabled() {
+		RespondJSON(c, http.StatusOK, resp)
+		return
+	}
+	resp.Enforce = s.modelBudget.enforce
+
+	costs, err := s.store.GetModelCosts(c.Request.Context(), resp.MonthStart)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	for name, budget := range s.modelBudget.Budgets() {
+		spent := costs[name]
+		resp.Items = append(resp.Items, ModelBudgetItem{
+			Model:     name,
+			Budget:    budget,
+			Spent:     spent,
+			Remaining: max(budget-spent, 0),
+			Exceeded:  spent >= budget,
+		})
+	}
+	slices.SortFunc(resp.Items, func(a, b ModelBudgetItem) int {
+		return strings.Compare(a.Model, b.Model)
+	})
+
+	RespondJSON(c, http.StatusOK, resp)
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,11 +12,13 @@ import (
 	"strings"
 	"time"
 
+	"ccLoad/internal/cooldown"
 	"ccLoad/internal/model"
 	"ccLoad/internal/util"
 
 	"github.com/bytedance/sonic"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // ==================== 渠道CRUD管理 ====================
@@ -129,6 +132,7 @@ func (s *Server) handleListChannels(c *gin.Context) {
 		channelCooldownsMap: allChannelCooldowns,
 		keyCooldownsMap:     allKeyCooldowns,
 		apiKeysMap:          allAPIKeys,
+		cooldownManager:     s.cooldownManager,
 	}
 	out := make([]ChannelWithCooldown, 0, len(cfgs))
 	for _, cfg := range cfgs {
@@ -152,7 +156,9 @@ func (s *Server) handleListChannels(c *gin.Context) {
 }
 
 // applyChannelListFilters 串联应用所有列表过滤条件：
-//   - type: 渠道类型（标准化比较）
+//   - type: 渠道类型（标准化比较，含协议转换暴露的协议）
+//   - channel_type: 渠道原生类型（标准化精确比较，不含协议转换）
+//   - enabled: true / false（布尔解析失败视为不过滤）
 //   - channel_name | search: 名称精确/模糊（互斥，channel_name 优先）
 //   - status: enabled / disabled / cooldown（cooldown 依赖 channelCooldownsMap）
 //   - model | model_like: 模型精确/模糊（互斥，model 优先）
@@ -167,6 +173,21 @@ func applyChannelListFilters(cfgs []*model.Config, c *gin.Context, channelCooldo
 		})
 	}
 
+	// channel_type
+	if t := strings.TrimSpace(c.Query("channel_type")); t != "" && t != "all" {
+		normalized := util.NormalizeChannelType(t)
+		cfgs = filterConfigs(cfgs, func(cfg *model.Config) bool {
+			return util.NormalizeChannelType(cfg.GetChannelType()) == normalized
+		})
+	}
+
+	// enabled
+	if enabled, err := strconv.ParseBool(strings.TrimSpace(c.Query("enabled"))); err == nil {
+		cfgs = filterConfigs(cfgs, func(cfg *model.Config) bool {
+			return cfg.Enabled == enabled
+		})
+	}
+
 	// channel_name | search（互斥）
 	if name := strings.TrimSpace(c.Query("channel_name")); name != "" {
 		cfgs = filterConfigs(cfgs, func(cfg *model.Config) bool {
@@ -288,17 +309,19 @@ type channelEnrichmentContext struct {
 	channelCooldownsMap map[int64]time.Time
 	keyCooldownsMap     map[int64]map[int]time.Time
 	apiKeysMap          map[int64][]*model.APIKey
+	cooldownManager     *cooldown.Manager // 熔断状态判定（nil 视为禁用）
 }
 
 // enrichChannel 把单个 cfg 拼装为 ChannelWithCooldown：
-// 渠道冷却剩余时间、健康度模式下的有效优先级与成功率、Key 策略与各 Key 冷却详情。
+// 渠道冷却剩余时间与熔断状态、健康度模式下的有效优先级与成功率、Key 策略与各 Key 冷却详情。
 func (ectx *channelEnrichmentContext) enrichChannel(cfg *model.Config) ChannelWithCooldown {
-	oc := ChannelWithCooldown{Config: cfg}
+	oc := ChannelWithCooldown{Config: cfg, NoAPIKeys: cfg.KeyCount == 0}
 
 	// 渠道级别冷却：使用批量查询结果（性能提升：N -> 1 次查询）
 	if until, cooled := ectx.channelCooldownsMap[cfg.ID]; cooled && until.After(ectx.now) {
 		oc.CooldownUntil = &until
 		oc.CooldownRemainingMS = int64(until.Sub(ectx.now) / time.Millisecond)
+		oc.CircuitOpen = ectx.cooldownManager.CircuitOpenAt(cfg.ConsecutiveFailures, until, ectx.now)
 	}
 
 	// 健康度模式：使用预计算的有效优先级和成功率
@@ -431,13 +454,76 @@ func (s *Server) HandleCheckDuplicateChannel(c *gin.Context) {
 	RespondJSON(c, http.StatusOK, CheckDuplicateResponse{Duplicates: duplicates})
 }
 
+// parseDefaultChannelPriority 解析 CCLOAD_DEFAULT_PRIORITY（新建渠道未指定 priority 时的默认值，非法值回退 0）
+func parseDefaultChannelPriority(raw string) int {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	val, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("[WARN] 无效的 CCLOAD_DEFAULT_PRIORITY=%s（必须为整数），使用默认值 0", raw)
+		return 0
+	}
+	return val
+}
+
+// parseDefaultKeyStrategy 解析 CCLOAD_DEFAULT_KEY_STRATEGY（新建渠道未指定 key_strategy 时的默认值，非法值回退 sequential）
+func parseDefaultKeyStrategy(raw string) string {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	if normalized == "" {
+		return model.KeyStrategySequential
+	}
+	if !model.IsValidKeyStrategy(normalized) {
+		log.Printf("[WARN] 无效的 CCLOAD_DEFAULT_KEY_STRATEGY=%s（可选 sequential/round_robin），使用默认值 %s", raw, model.KeyStrategySequential)
+		return model.KeyStrategySequential
+	}
+	return normalized
+}
+
+// normalizeChannelKeysForSave 规范化并去重请求中的 Key（currentCount 为渠道现有 Key 数，新建为 0）。
+// 默认移除重复项并返回警告；strict_keys=true 时存在重复直接拒绝；去重后超过单渠道上限且多于现有数量时拒绝。
+func (s *Server) normalizeChannelKeysForSave(c *gin.Context, req *ChannelRequest, currentCount int) ([]ChannelAPIKeyRequest, []ChannelLintWarning, error) {
+	keys, removed := dedupeAPIKeyRequests(req.normalizeAPIKeys())
+	if err := s.checkChannelKeyLimit(len(keys), currentCount); err != nil {
+		return nil, nil, err
+	}
+	if removed == 0 {
+		return keys, nil, nil
+	}
+	if util.ParseBoolDefault(c.Query("strict_keys"), false) {
+		return nil, nil, fmt.Errorf("duplicate api keys: %d duplicate(s) found", removed)
+	}
+	return keys, []ChannelLintWarning{{
+		Code:    "duplicate_keys_removed",
+		Message: fmt.Sprintf("已移除 %d 个重复 Key（保留首次出现）", removed),
+	}}, nil
+}
+
 // 创建新渠道
 func (s *Server) handleCreateChannel(c *gin.Context) {
 	var req ChannelRequest
-	if err := BindAndValidate(c, &req); err != nil {
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, "invalid request: "+err.Error())
 		return
 	}
+	// 未显式传 priority 时应用默认优先级（显式传 0 仍为 0）
+	var explicit struct {
+		Priority *int `json:"priority"`
+	}
+	if err := c.ShouldBindBodyWith(&explicit, binding.JSON); err == nil && explicit.Priority == nil {
+		req.Priority = s.defaultChannelPriority
+	}
+
+	apiKeyEntries, keyWarnings, err := s.normalizeChannelKeysForSave(c, &req, 0)
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// 创建渠道（不包含API Key）
 	created, err := s.store.CreateConfig(c.Request.Context(), req.ToConfig())
@@ -448,11 +534,13 @@ func (s *Server) handleCreateChannel(c *gin.Context) {
 
 	keyStrategy := strings.TrimSpace(req.KeyStrategy)
 	if keyStrategy == "" {
-		keyStrategy = model.KeyStrategySequential // 默认策略
+		keyStrategy = s.defaultKeyStrategy // 默认策略（CCLOAD_DEFAULT_KEY_STRATEGY）
+	}
+	if keyStrategy == "" {
+		keyStrategy = model.KeyStrategySequential
 	}
 
 	now := time.Now()
-	apiKeyEntries := req.normalizeAPIKeys()
 	keysToCreate := make([]*model.APIKey, 0, len(apiKeyEntries))
 	for i, entry := range apiKeyEntries {
 		keysToCreate = append(keysToCreate, &model.APIKey{
@@ -460,11 +548,24 @@ func (s *Server) handleCreateChannel(c *gin.Context) {
 			KeyIndex:    i,
 			APIKey:      entry.APIKey,
 			Note:        entry.Note,
+			KeyGroup:    entry.KeyGroup,
+			RPMLimit:    entry.RPMLimit,
 			KeyStrategy: keyStrategy,
 			CreatedAt:   model.JSONTime{Time: now},
 			UpdatedAt:   model.JSONTime{Time: now},
 		})
 	}
+
+	// validate=true：逐个 Key 预检，失败的 Key 以禁用状态入库（批量导入不传此参数即可跳过）
+	validate := util.ParseBoolDefault(c.Query("validate"), false)
+	var keyValidation []ChannelKeyPreflightResult
+	warnings := keyWarnings
+	if validate {
+		var preflightWarnings []ChannelLintWarning
+		keyValidation, preflightWarnings = s.preflightChannelKeys(c.Request.Context(), created, keysToCreate)
+		warnings = append(warnings, preflightWarnings...)
+	}
+
 	if len(keysToCreate) > 0 {
 		if err := s.store.CreateAPIKeysBatch(c.Request.Context(), keysToCreate); err != nil {
 			log.Printf("[WARN] 批量创建API Key失败 (channel=%d): %v", created.ID, err)
@@ -474,7 +575,17 @@ func (s *Server) handleCreateChannel(c *gin.Context) {
 	// 新增渠道后，失效渠道列表缓存使选择器立即可见
 	s.InvalidateChannelListCache()
 
-	RespondJSON(c, http.StatusCreated, created)
+	changes := setAuditChannelDiff(c, nil, created)
+	if len(keysToCreate) > 0 {
+		changes["api_keys"] = model.AuditChange{New: len(keysToCreate)}
+		setAuditChanges(c, created.ID, changes)
+	}
+
+	RespondJSON(c, http.StatusCreated, ChannelCreateResponse{
+		Config:        created,
+		KeyValidation: keyValidation,
+		Warnings:      warnings,
+	})
 }
 
 // HandleChannelByID 处理单个渠道的CRUD操作
@@ -584,6 +695,59 @@ func (s *Server) HandleChannelModelStats(c *gin.Context) {
 	RespondJSON(c, http.StatusOK, result)
 }
 
+const (
+	// channelRecentErrorsDefaultLimit / channelRecentErrorsMaxLimit 最近错误样本条数默认值与上限
+	channelRecentErrorsDefaultLimit = 10
+	channelRecentErrorsMaxLimit     = 100
+)
+
+// HandleChannelRecentErrors 返回渠道最近 N 条错误日志（状态码非 2xx，含检测日志），用于定位冷却原因。
+// GET /admin/channels/:id/recent-errors?limit=10
+func (s *Server) HandleChannelRecentErrors(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel id")
+		return
+	}
+	limit := channelRecentErrorsDefaultLimit
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > channelRecentErrorsMaxLimit {
+			RespondErrorMsg(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(channelRecentErrorsMaxLimit))
+			return
+		}
+		limit = n
+	}
+	if _, err := s.store.GetConfig(c.Request.Context(), id); err != nil {
+		RespondErrorMsg(c, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	filter := &model.LogFilter{
+		ChannelID:  &id,
+		LogSource:  model.LogSourceAll,
+		ErrorsOnly: true,
+	}
+	logs, err := s.store.ListLogs(c.Request.Context(), time.Time{}, limit, 0, filter)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := make([]ChannelRecentError, 0, len(logs))
+	for _, entry := range logs {
+		result = append(result, ChannelRecentError{
+			Time:       entry.Time,
+			StatusCode: entry.StatusCode,
+			Model:      entry.Model,
+			Message:    entry.Message,
+			LogSource:  entry.LogSource,
+			RequestID:  entry.RequestID,
+		})
+	}
+	RespondJSON(c, http.StatusOK, result)
+}
+
 // HandleChannelURLStats 返回多URL渠道各URL的实时状态（延迟、冷却）
 // GET /admin/channels/:id/url-stats
 func (s *Server) HandleChannelURLStats(c *gin.Context) {
@@ -726,6 +890,9 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 		return
 	}
 
+	// 审计用旧配置快照（查询失败时按新建处理 diff，不影响更新）
+	before, _ := s.store.GetConfig(c.Request.Context(), id)
+
 	// 检查是否为简单的enabled字段更新
 	if len(rawReq) == 1 {
 		if enabled, ok := rawReq["enabled"].(bool); ok {
@@ -740,6 +907,7 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 			}
 			// enabled 状态变更影响渠道选择，必须立即失效缓存
 			s.InvalidateChannelListCache()
+			setAuditChannelDiff(c, before, upd)
 			RespondJSON(c, http.StatusOK, upd)
 			return
 		}
@@ -770,7 +938,11 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 		oldKeys = []*model.APIKey{}
 	}
 
-	newKeys := req.normalizeAPIKeys()
+	newKeys, keyWarnings, err := s.normalizeChannelKeysForSave(c, &req, len(oldKeys))
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	keyStrategy := strings.TrimSpace(req.KeyStrategy)
 	if keyStrategy == "" {
 		keyStrategy = model.KeyStrategySequential
@@ -788,14 +960,24 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 	}
 
 	notesByIndex := make(map[int]string)
+	groupsByIndex := make(map[int]string)
+	limitsByIndex := make(map[int]int)
 	if !keyChanged {
 		for i, oldKey := range oldKeys {
 			if oldKey.Note != newKeys[i].Note {
 				notesByIndex[oldKey.KeyIndex] = newKeys[i].Note
 			}
+			if oldKey.KeyGroup != newKeys[i].KeyGroup {
+				groupsByIndex[oldKey.KeyIndex] = newKeys[i].KeyGroup
+			}
+			if oldKey.RPMLimit != newKeys[i].RPMLimit {
+				limitsByIndex[oldKey.KeyIndex] = newKeys[i].RPMLimit
+			}
 		}
 	}
 	noteChanged := len(notesByIndex) > 0
+	groupChanged := len(groupsByIndex) > 0
+	limitChanged := len(limitsByIndex) > 0
 
 	// [INFO] 修复 (2025-10-11): 检测策略变化
 	strategyChanged := false
@@ -814,35 +996,30 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 		return
 	}
 
-	// Key或策略变化时更新API Keys
+	// Key 列表变化时，需在重置冷却前读取旧 Key 的冷却状态（以存储为准，缓存中的冷却时间可能滞后）
 	if keyChanged {
-		disabledByAPIKey := make(map[string]bool, len(oldKeys))
-		for _, oldKey := range oldKeys {
-			if oldKey.Disabled {
-				disabledByAPIKey[oldKey.APIKey] = true
-			}
+		if fresh, err := s.store.GetAPIKeys(c.Request.Context(), id); err == nil {
+			oldKeys = fresh
 		}
+	}
 
-		// Key内容/数量变化：删除旧Key并重建
-		_ = s.store.DeleteAllAPIKeys(c.Request.Context(), id)
-
-		// 批量创建新的API Keys（优化：单次事务插入替代循环单条插入）
-		now := time.Now()
-		apiKeys := make([]*model.APIKey, 0, len(newKeys))
-		for i, key := range newKeys {
-			apiKeys = append(apiKeys, &model.APIKey{
-				ChannelID:   id,
-				KeyIndex:    i,
-				APIKey:      key.APIKey,
-				Note:        key.Note,
-				KeyStrategy: keyStrategy,
-				Disabled:    disabledByAPIKey[key.APIKey],
-				CreatedAt:   model.JSONTime{Time: now},
-				UpdatedAt:   model.JSONTime{Time: now},
-			})
+	// 清除渠道、Key 和模型冷却状态（编辑保存后重置冷却）
+	// 设计原则: 清除失败不应影响渠道更新成功，但需要记录用于监控
+	if s.cooldownManager != nil {
+		if err := s.cooldownManager.ClearAllCooldowns(c.Request.Context(), id); err != nil {
+			log.Printf("[WARN] 清除渠道全部冷却状态失败 (channel=%d): %v", id, err)
 		}
-		if err := s.store.CreateAPIKeysBatch(c.Request.Context(), apiKeys); err != nil {
-			log.Printf("[WARN] 批量创建API Keys失败 (channel=%d, count=%d): %v", id, len(apiKeys), err)
+	}
+
+	// Key或策略变化时更新API Keys
+	if keyChanged {
+		// 按明文对比新旧 Key：保留的 Key 沿用禁用状态与重置前的冷却（仅追加一个 Key 不应清空其余 Key 的冷却），
+		// 保留的 Key 原地更新（行 ID 不变），仅新增/移除变化的 Key；单事务完成，避免先删后建之间的空窗
+		apiKeys, removed := rebuildAPIKeysRetainingState(id, oldKeys, newKeys, keyStrategy)
+		if err := s.store.ReplaceAPIKeys(c.Request.Context(), id, apiKeys); err != nil {
+			log.Printf("[WARN] 更新API Keys失败 (channel=%d, count=%d): %v", id, len(apiKeys), err)
+		} else {
+			log.Printf("[INFO] 渠道 %d Key 已更新: %d -> %d 个（移除 %d 个）", id, len(oldKeys), len(apiKeys), removed)
 		}
 	} else {
 		// Key内容未变化：策略和备注都是独立元数据，不能重建 Key 导致禁用状态丢失。
@@ -856,15 +1033,18 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 				log.Printf("[WARN] 批量更新API Key备注失败 (channel=%d): %v", id, err)
 			}
 		}
-	}
-
-	// 清除渠道、Key 和模型冷却状态（编辑保存后重置冷却）
-	// 设计原则: 清除失败不应影响渠道更新成功，但需要记录用于监控
-	if s.cooldownManager != nil {
-		if err := s.cooldownManager.ClearAllCooldowns(c.Request.Context(), id); err != nil {
-			log.Printf("[WARN] 清除渠道全部冷却状态失败 (channel=%d): %v", id, err)
+		if groupChanged {
+			if err := s.store.UpdateAPIKeyGroups(c.Request.Context(), id, groupsByIndex); err != nil {
+				log.Printf("[WARN] 批量更新API Key分组失败 (channel=%d): %v", id, err)
+			}
+		}
+		if limitChanged {
+			if err := s.store.UpdateAPIKeyRPMLimits(c.Request.Context(), id, limitsByIndex); err != nil {
+				log.Printf("[WARN] 批量更新API Key RPM限制失败 (channel=%d): %v", id, err)
+			}
 		}
 	}
+
 	// 冷却状态可能被更新，必须失效冷却缓存，避免前端立即刷新仍读到旧冷却状态
 	s.invalidateCooldownCache()
 
@@ -881,11 +1061,22 @@ func (s *Server) handleUpdateChannel(c *gin.Context, id int64) {
 	// 同步清理数据库中已移除URL的禁用状态记录
 	s.cleanupOrphanedURLStates(c.Request.Context(), id, upd.GetURLs())
 
-	RespondJSON(c, http.StatusOK, upd)
+	// 审计：Key 只记录数量与策略变化，不落明文
+	changes := setAuditChannelDiff(c, before, upd)
+	if keyChanged {
+		changes["api_keys"] = model.AuditChange{Old: len(oldKeys), New: len(newKeys)}
+	}
+	if strategyChanged {
+		changes["key_strategy"] = model.AuditChange{Old: oldKeys[0].KeyStrategy, New: keyStrategy}
+	}
+	setAuditChanges(c, id, changes)
+
+	RespondJSON(c, http.StatusOK, ChannelCreateResponse{Config: upd, Warnings: keyWarnings})
 }
 
 // 删除渠道
 func (s *Server) handleDeleteChannel(c *gin.Context, id int64) {
+	before, _ := s.store.GetConfig(c.Request.Context(), id)
 	deleted, err := s.deleteChannelByID(c.Request.Context(), id)
 	if err != nil {
 		RespondError(c, http.StatusInternalServerError, err)
@@ -900,6 +1091,9 @@ func (s *Server) handleDeleteChannel(c *gin.Context, id int64) {
 	// 删除渠道后必须同步失效该渠道的 API Keys 缓存，
 	// 否则若后续以同 ID 重新创建渠道（显式主键路径，例如混合存储恢复），可能读到旧 keys。
 	s.InvalidateAPIKeysCache(id)
+	if before != nil {
+		setAuditChannelDiff(c, before, nil)
+	}
 	RespondJSON(c, http.StatusOK, gin.H{"id": id})
 }
 
@@ -973,6 +1167,10 @@ func (s *Server) HandleDeleteAPIKey(c *gin.Context) {
 	// 失效缓存
 	s.InvalidateAPIKeysCache(channelID)
 	s.invalidateCooldownCache()
+	// 删除最后一个 Key 后渠道变为零 Key：刷新渠道列表中的 key_count
+	if remaining == 0 {
+		s.InvalidateChannelListCache()
+	}
 
 	RespondJSON(c, http.StatusOK, gin.H{
 		"remaining_keys": remaining,
@@ -981,6 +1179,7 @@ func (s *Server) HandleDeleteAPIKey(c *gin.Context) {
 
 // HandleAddModels 添加模型到渠道（去重）
 // POST /admin/channels/:id/models
+// 在存储层单事务内追加，不做整渠道读改写，多个管理员/脚本并发添加互不覆盖
 func (s *Server) HandleAddModels(c *gin.Context) {
 	channelID, err := ParseInt64Param(c, "id")
 	if err != nil {
@@ -996,13 +1195,6 @@ func (s *Server) HandleAddModels(c *gin.Context) {
 		return
 	}
 
-	ctx := c.Request.Context()
-	cfg, err := s.store.GetConfig(ctx, channelID)
-	if err != nil {
-		RespondError(c, http.StatusNotFound, err)
-		return
-	}
-
 	// 验证模型条目（DRY: 使用 ModelEntry.Validate()）
 	for i := range req.Models {
 		if err := req.Models[i].Validate(); err != nil {
@@ -1011,21 +1203,9 @@ func (s *Server) HandleAddModels(c *gin.Context) {
 		}
 	}
 
-	// 去重合并（大小写不敏感，兼容 MySQL utf8mb4_general_ci 排序规则）
-	existing := make(map[string]bool)
-	for _, e := range cfg.ModelEntries {
-		existing[strings.ToLower(e.Model)] = true
-	}
-	for _, e := range req.Models {
-		key := strings.ToLower(e.Model)
-		if !existing[key] {
-			cfg.ModelEntries = append(cfg.ModelEntries, e)
-			existing[key] = true
-		}
-	}
-
-	if _, err := s.store.UpdateConfig(ctx, channelID, cfg); err != nil {
-		RespondError(c, http.StatusInternalServerError, err)
+	cfg, err := s.store.AddChannelModels(c.Request.Context(), channelID, req.Models)
+	if err != nil {
+		respondChannelModelsError(c, err)
 		return
 	}
 
@@ -1036,12 +1216,6 @@ func (s *Server) HandleAddModels(c *gin.Context) {
 // HandleDeleteModels 删除渠道中的指定模型
 // DELETE /admin/channels/:id/models
 func (s *Server) HandleDeleteModels(c *gin.Context) {
-	channelID, err := ParseInt64Param(c, "id")
-	if err != nil {
-		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel id")
-		return
-	}
-
 	var req struct {
 		Models []string `json:"models" binding:"required,min=1"` // 只需要模型名称列表
 	}
@@ -1049,34 +1223,44 @@ func (s *Server) HandleDeleteModels(c *gin.Context) {
 		RespondErrorMsg(c, http.StatusBadRequest, "invalid request")
 		return
 	}
+	s.removeChannelModels(c, req.Models)
+}
 
-	ctx := c.Request.Context()
-	cfg, err := s.store.GetConfig(ctx, channelID)
-	if err != nil {
-		RespondError(c, http.StatusNotFound, err)
+// HandleDeleteModel 删除渠道中的单个模型（模型名可含 /，如 openai/gpt-4o）
+// DELETE /admin/channels/:id/models/*model
+func (s *Server) HandleDeleteModel(c *gin.Context) {
+	modelName := strings.TrimSpace(strings.TrimPrefix(c.Param("model"), "/"))
+	if modelName == "" {
+		RespondErrorMsg(c, http.StatusBadRequest, "model cannot be empty")
 		return
 	}
+	s.removeChannelModels(c, []string{modelName})
+}
 
-	// 过滤掉要删除的模型（大小写不敏感，兼容 MySQL utf8mb4_general_ci）
-	toDelete := make(map[string]bool)
-	for _, m := range req.Models {
-		toDelete[strings.ToLower(m)] = true
-	}
-	remaining := make([]model.ModelEntry, 0, len(cfg.ModelEntries))
-	for _, e := range cfg.ModelEntries {
-		if !toDelete[strings.ToLower(e.Model)] {
-			remaining = append(remaining, e)
-		}
+// removeChannelModels 删除模型（大小写不敏感，兼容 MySQL utf8mb4_general_ci）并返回剩余数量
+func (s *Server) removeChannelModels(c *gin.Context, models []string) {
+	channelID, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel id")
+		return
 	}
 
-	cfg.ModelEntries = remaining
-	if _, err := s.store.UpdateConfig(ctx, channelID, cfg); err != nil {
-		RespondError(c, http.StatusInternalServerError, err)
+	cfg, err := s.store.RemoveChannelModels(c.Request.Context(), channelID, models)
+	if err != nil {
+		respondChannelModelsError(c, err)
 		return
 	}
 
 	s.InvalidateChannelListCache()
-	RespondJSON(c, http.StatusOK, gin.H{"remaining": len(remaining)})
+	RespondJSON(c, http.StatusOK, gin.H{"remaining": len(cfg.ModelEntries)})
+}
+
+func respondChannelModelsError(c *gin.Context, err error) {
+	if errors.Is(err, model.ErrChannelNotFound) {
+		RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	RespondError(c, http.StatusInternalServerError, err)
 }
 
 // HandleBatchUpdatePriority 批量更新渠道优先级
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCloneChannel 复制渠道配置（可选连同 API Key）
+// POST /admin/channels/:id/clone
+// 冷却、熔断等运行时状态不复制；新渠道名称必须唯一。
+func (s *Server) HandleCloneChannel(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel id")
+		return
+	}
+
+	var req CloneChannelRequest
+	if c.Request.ContentLength != 0 {
+		if err := BindAndValidate(c, &req); err != nil {
+			RespondErrorMsg(c, http.StatusBadRequest, "invalid request: "+err.Error())
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	src, err := s.store.GetConfig(ctx, id)
+	if err != nil {
+		RespondErrorMsg(c, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	configs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	names := make(map[string]struct{}, len(configs))
+	for _, cfg := range configs {
+		names[cfg.Name] = struct{}{}
+	}
+
+	name := req.Name
+	if name != "" {
+		if _, exists := names[name]; exists {
+			RespondErrorMsg(c, http.StatusConflict, "channel name already exists: "+name)
+			return
+		}
+	} else {
+		name = uniqueCloneName(src.Name, names)
+	}
+
+	clone := src.Clone()
+	clone.ID = 0
+	clone.Name = name
+	clone.CooldownUntil = 0
+	clone.CooldownDurationMs = 0
+	clone.ConsecutiveFailures = 0
+	clone.LastUsedAt = 0
+	clone.KeyCount = 0
+	created, err := s.store.CreateConfig(ctx, clone)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !req.WithoutKeys {
+		srcKeys, err := s.store.GetAPIKeys(ctx, id)
+		if err != nil {
+			log.Printf("[WARN] 读取源渠道API Key失败 (channel=%d): %v", id, err)
+		}
+		now := time.Now()
+		keys := make([]*model.APIKey, 0, len(srcKeys))
+		for _, k := range srcKeys {
+			keys = append(keys, &model.APIKey{
+				ChannelID:   created.ID,
+				KeyIndex:    k.KeyIndex,
+				APIKey:      k.APIKey,
+				Note:        k.Note,
+				KeyGroup:    k.KeyGroup,
+				RPMLimit:    k.RPMLimit,
+				KeyStrategy: k.KeyStrategy,
+				Disabled:    k.Disabled,
+				CreatedAt:   model.JSONTime{Time: now},
+				UpdatedAt:   model.JSONTime{Time: now},
+			})
+		}
+		if len(keys) > 0 {
+			if err := s.store.CreateAPIKeysBatch(ctx, keys); err != nil {
+				log.Printf("[WARN] 复制API Key失败 (channel=%d): %v", created.ID, err)
+			}
+		}
+	}
+
+	s.InvalidateChannelListCache()
+
+	RespondJSON(c, http.StatusCreated, created)
+}
+
+// uniqueCloneName 生成不与现有渠道重名的副本名称："name (copy)"、"name (copy 2)"…
+func uniqueCloneName(base string, existing map[string]struct{}) string {
+	candidate := base + " (copy)"
+	for i := 2; ; i++ {
+		if _, taken := existing[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (copy %d)", base, i)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleCloneChannel(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	src, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "region-us",
+		URL:          "https://us.example.com",
+		Priority:     7,
+		ModelEntries: []model.ModelEntry{{Model: "m1"}, {Model: "m2", RedirectModel: "m2-upstream"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: src.ID, KeyIndex: 0, APIKey: "sk-a", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: src.ID, KeyIndex: 1, APIKey: "sk-b", KeyStrategy: model.KeyStrategySequential, Disabled: true},
+	}); err != nil {
+		t.Fatalf("CreateAPIKeysBatch: %v", err)
+	}
+
+	clone := func(body any) (*model.Config, int) {
+		t.Helper()
+		var req *http.Request
+		if body == nil {
+			req = newRequest(http.MethodPost, "/admin/channels/1/clone", nil)
+		} else {
+			req = newJSONRequest(t, http.MethodPost, "/admin/channels/1/clone", body)
+		}
+		c, w := newTestContext(t, req)
+		c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(src.ID, 10)}}
+		server.HandleCloneChannel(c)
+		if w.Code != http.StatusCreated {
+			return nil, w.Code
+		}
+		var resp struct {
+			Data *model.Config `json:"data"`
+		}
+		mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+		return resp.Data, w.Code
+	}
+
+	first, code := clone(nil)
+	if first == nil {
+		t.Fatalf("clone status=%d", code)
+	}
+	if first.Name != "region-us (copy)" || first.URL != src.URL || first.Priority != 7 || len(first.ModelEntries) != 2 {
+		t.Fatalf("unexpected clone: %+v", first)
+	}
+	keys, err := store.GetAPIKeys(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].APIKey != "sk-a" || keys[1].APIKey != "sk-b" || !keys[1].Disabled {
+		t.Fatalf("keys not copied: %+v", keys)
+	}
+
+	second, _ := clone(map[string]any{"without_keys": true})
+	if second == nil || second.Name != "region-us (copy 2)" {
+		t.Fatalf("expected deduplicated name, got %+v", second)
+	}
+	keys, err = store.GetAPIKeys(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("without_keys should skip keys, got %d", len(keys))
+	}
+
+	named, _ := clone(map[string]any{"name": "region-eu"})
+	if named == nil || named.Name != "region-eu" {
+		t.Fatalf("expected explicit name, got %+v", named)
+	}
+	if _, code := clone(map[string]any{"name": "region-us"}); code != http.StatusConflict {
+		t.Fatalf("duplicate name status=%d, want 409", code)
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleReplaceChannelKeys 原子替换渠道的全部 API Keys（Key 轮换）
+// PUT /admin/channels/:id/keys
+// Key 按提交顺序从 0 连续编号；仍保留的 Key（按明文匹配）原地更新，沿用行 ID、禁用与冷却状态，被移除 Key 的冷却随记录一并清除。
+// 不修改渠道其他配置，也不像整渠道更新那样重置全部冷却。
+func (s *Server) HandleReplaceChannelKeys(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel id")
+		return
+	}
+
+	var req ReplaceChannelKeysRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := s.store.GetConfig(ctx, id); err != nil {
+		RespondErrorMsg(c, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	oldKeys, err := s.store.GetAPIKeys(ctx, id)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.checkChannelKeyLimit(len(req.Keys), len(oldKeys)); err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	keyStrategy := req.KeyStrategy
+	if keyStrategy == "" {
+		keyStrategy = channelKeyStrategy(oldKeys)
+	}
+
+	newKeys, removed := rebuildAPIKeysRetainingState(id, oldKeys, req.Keys, keyStrategy)
+
+	if err := s.store.ReplaceAPIKeys(ctx, id, newKeys); err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	log.Printf("[INFO] 渠道 %d Key 已轮换: %d -> %d 个（移除 %d 个）", id, len(oldKeys), len(newKeys), removed)
+
+	// Key 数量与冷却状态变化：渠道列表（KeyCount）、Key 缓存与冷却缓存都需失效
+	s.InvalidateChannelListCache()
+	s.InvalidateAPIKeysCache(id)
+	s.invalidateCooldownCache()
+
+	RespondJSON(c, http.StatusOK, maskAPIKeys(newKeys))
+}
+
+// rebuildAPIKeysRetainingState 按提交顺序从 0 连续编号重建 Key 列表，返回新列表与被移除的旧 Key 数
+// 仍保留的 Key（按明文匹配）沿用禁用、冷却与最近使用时间，与位置无关：追加、删除中间项或重新排序都不会丢失其冷却；
+// 仅追加时已有 Key 的 key_index 不变。ReplaceAPIKeys 按明文原地更新保留的记录，行 ID 不变，Key 级 RPM 计数不会被重置。
+func rebuildAPIKeysRetainingState(channelID int64, oldKeys []*model.APIKey, keys []ChannelAPIKeyRequest, keyStrategy string) ([]*model.APIKey, int) {
+	retained := make(map[string]*model.APIKey, len(oldKeys))
+	for _, k := range oldKeys {
+		if _, dup := retained[k.APIKey]; !dup {
+			retained[k.APIKey] = k
+		}
+	}
+
+	now := time.Now()
+	newKeys := make([]*model.APIKey, 0, len(keys))
+	for i, key := range keys {
+		apiKey := &model.APIKey{
+			ChannelID:   channelID,
+			KeyIndex:    i,
+			APIKey:      key.APIKey,
+			Note:        key.Note,
+			KeyGroup:    key.KeyGroup,
+			RPMLimit:    key.RPMLimit,
+			KeyStrategy: keyStrategy,
+			CreatedAt:   model.JSONTime{Time: now},
+			UpdatedAt:   model.JSONTime{Time: now},
+		}
+		if old, ok := retained[key.APIKey]; ok {
+			apiKey.Disabled = old.Disabled
+			apiKey.CooldownUntil = old.CooldownUntil
+			apiKey.CooldownDurationMs = old.CooldownDurationMs
+			apiKey.LastUsedAt = old.LastUsedAt
+			delete(retained, key.APIKey) // 同一明文重复提交时仅第一个继承状态
+		}
+		newKeys = append(newKeys, apiKey)
+	}
+	return newKeys, len(retained)
+}
+
+// maskAPIKeys 返回脱敏副本（文件引用仅为路径，原样返回）
+func maskAPIKeys(keys []*model.APIKey) []*model.APIKey {
+	masked := make([]*model.APIKey, 0, len(keys))
+	for _, k := range keys {
+		cp := *k
+		if !util.IsAPIKeyFileRef(cp.APIKey) {
+			cp.APIKey = util.MaskAPIKey(cp.APIKey)
+		}
+		masked = append(masked, &cp)
+	}
+	return masked
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleReplaceChannelKeys(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name: "rotate", URL: "https://api.example.com", ChannelType: "openai", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: cfg.ID, KeyIndex: 0, APIKey: "sk-old-removed", KeyStrategy: model.KeyStrategyRoundRobin},
+		{ChannelID: cfg.ID, KeyIndex: 1, APIKey: "sk-kept-key-1", KeyStrategy: model.KeyStrategyRoundRobin, Disabled: true},
+	}); err != nil {
+		t.Fatalf("create keys: %v", err)
+	}
+	until := time.Now().Add(10 * time.Minute)
+	if err := srv.store.SetKeyCooldown(ctx, cfg.ID, 0, until); err != nil {
+		t.Fatalf("cooldown removed key: %v", err)
+	}
+	if err := srv.store.SetKeyCooldown(ctx, cfg.ID, 1, until); err != nil {
+		t.Fatalf("cooldown kept key: %v", err)
+	}
+
+	replace := func(body any) {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+strconv.FormatInt(cfg.ID, 10)+"/keys", body))
+		c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(cfg.ID, 10)}}
+		srv.HandleReplaceChannelKeys(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		resp := mustParseAPIResponse[[]*model.APIKey](t, w.Body.Bytes())
+		if len(resp.Data) != 2 || resp.Data[0].APIKey == "sk-kept-key-1" {
+			t.Fatalf("expected 2 masked keys, got %+v", resp.Data)
+		}
+	}
+
+	replace(ReplaceChannelKeysRequest{Keys: []ChannelAPIKeyRequest{
+		{APIKey: "sk-kept-key-1"},
+		{APIKey: "sk-brand-new", Note: "rotated"},
+	}})
+
+	keys, err := srv.store.GetAPIKeys(ctx, cfg.ID)
+	if err != nil {
+		t.Fatalf("get keys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("keys=%d, want 2", len(keys))
+	}
+	kept, added := keys[0], keys[1]
+	if kept.APIKey != "sk-kept-key-1" || kept.KeyIndex != 0 {
+		t.Fatalf("kept key not reindexed to 0: %+v", kept)
+	}
+	if !kept.Disabled || kept.CooldownUntil != until.Unix() {
+		t.Fatalf("kept key should retain disabled/cooldown: %+v", kept)
+	}
+	if added.APIKey != "sk-brand-new" || added.KeyIndex != 1 || added.CooldownUntil != 0 || added.Note != "rotated" {
+		t.Fatalf("new key should start clean: %+v", added)
+	}
+	if added.KeyStrategy != model.KeyStrategyRoundRobin {
+		t.Fatalf("strategy should be inherited, got %q", added.KeyStrategy)
+	}
+
+	t.Run("rejects empty key list", func(t *testing.T) {
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/1/keys", ReplaceChannelKeysRequest{}))
+		c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(cfg.ID, 10)}}
+		srv.HandleReplaceChannelKeys(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status=%d, want 400", w.Code)
+		}
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/9999/keys", ReplaceChannelKeysRequest{
+			Keys: []ChannelAPIKeyRequest{{APIKey: "sk-x"}},
+		}))
+		c.Params = gin.Params{{Key: "id", Value: "9999"}}
+		srv.HandleReplaceChannelKeys(c)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status=%d, want 404", w.Code)
+		}
+	})
+}
@@ -174,6 +174,36 @@ func TestHandleAddAndDeleteModels(t *testing.T) {
 			t.Fatalf("unexpected remaining models: %#v", updated.ModelEntries)
 		}
 	})
+
+	t.Run("delete single model with slash", func(t *testing.T) {
+		if _, err := store.AddChannelModels(ctx, cfg.ID, []model.ModelEntry{{Model: "openai/gpt-4o"}}); err != nil {
+			t.Fatalf("AddChannelModels failed: %v", err)
+		}
+		c, w := newTestContext(t, newRequest(http.MethodDelete, "/admin/channels/1/models/openai/GPT-4o", nil))
+		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "model", Value: "/openai/GPT-4o"}}
+
+		server.HandleDeleteModel(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d, want %d, body=%s", w.Code, http.StatusOK, w.Body.String())
+		}
+		updated, err := store.GetConfig(ctx, cfg.ID)
+		if err != nil {
+			t.Fatalf("GetConfig failed: %v", err)
+		}
+		if len(updated.ModelEntries) != 1 || updated.ModelEntries[0].Model != "m1" {
+			t.Fatalf("unexpected remaining models: %#v", updated.ModelEntries)
+		}
+	})
+
+	t.Run("missing channel returns 404", func(t *testing.T) {
+		c, w := newTestContext(t, newRequest(http.MethodDelete, "/admin/channels/999/models/m1", nil))
+		c.Params = gin.Params{{Key: "id", Value: "999"}, {Key: "model", Value: "/m1"}}
+
+		server.HandleDeleteModel(c)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status=%d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
 }
 
 func TestHandleBatchUpdatePriority(t *testing.T) {
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/testutil"
+)
+
+// channelPreflightConcurrency 创建渠道时 Key 预检的并发上限
+const channelPreflightConcurrency = 4
+
+// ChannelKeyPreflightResult 单个 Key 的预检结果
+type ChannelKeyPreflightResult struct {
+	KeyIndex   int    `json:"key_index"`
+	Valid      bool   `json:"valid"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ChannelCreateResponse 渠道创建/更新响应（validate=true 创建时附带 Key 预检结果；
+// key_validation 与 warnings 为空时与渠道配置 JSON 一致）
+type ChannelCreateResponse struct {
+	*model.Config
+	KeyValidation []ChannelKeyPreflightResult `json:"key_validation,omitempty"`
+	Warnings      []ChannelLintWarning        `json:"warnings,omitempty"`
+}
+
+// preflightChannelKeys 创建渠道时逐个 Key 发起轻量测试请求（复用 testChannelAPI）。
+// 失败的 Key 直接标记为禁用；不更新冷却状态，也不写检测日志。
+func (s *Server) preflightChannelKeys(ctx context.Context, cfg *model.Config, keys []*model.APIKey) ([]ChannelKeyPreflightResult, []ChannelLintWarning) {
+	if len(keys) == 0 {
+		return []ChannelKeyPreflightResult{}, nil
+	}
+	modelName, skipReason := selectScheduledCheckModel(cfg)
+	if skipReason != "" {
+		return []ChannelKeyPreflightResult{}, []ChannelLintWarning{{
+			Code:    "key_validation_skipped",
+			Message: "跳过 Key 预检：" + skipReason,
+		}}
+	}
+
+	results := make([]ChannelKeyPreflightResult, len(keys))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, channelPreflightConcurrency)
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.preflightChannelKey(ctx, cfg, key, modelName)
+		}()
+	}
+	wg.Wait()
+
+	var warnings []ChannelLintWarning
+	for i, res := range results {
+		if res.Valid {
+			continue
+		}
+		keys[i].Disabled = true
+		warnings = append(warnings, ChannelLintWarning{
+			Code:    "key_validation_failed",
+			Message: fmt.Sprintf("Key #%d 预检失败，已禁用：%s", res.KeyIndex, res.Error),
+		})
+	}
+	return results, warnings
+}
+
+func (s *Server) preflightChannelKey(ctx context.Context, cfg *model.Config, key *model.APIKey, modelName string) ChannelKeyPreflightResult {
+	res := ChannelKeyPreflightResult{KeyIndex: key.KeyIndex}
+	apiKey, err := resolveAPIKey(key.APIKey)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	testReq := &testutil.TestChannelRequest{
+		Model:       modelName,
+		ChannelType: cfg.GetChannelType(),
+	}
+	result := s.testChannelAPI(ctx, cfg, apiKey, testReq)
+	res.Valid, _ = result["success"].(bool)
+	res.StatusCode = getResultIntOrDefault(result, "status_code", 0)
+	if !res.Valid {
+		res.Error = strings.TrimSpace(getResultString(result, "error"))
+		if res.Error == "" {
+			res.Error = "unknown error"
+		}
+	}
+	return res
+}
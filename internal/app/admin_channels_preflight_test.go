@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestHandleCreateChannel_ValidateDisablesFailingKeys(t *testing.T) {
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer sk-good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"invalid api key"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-test","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer upstream.Close()
+
+	srv := newInMemoryServer(t)
+	payload := ChannelRequest{
+		Name:        "preflight-channel",
+		APIKey:      "sk-good,sk-bad",
+		URL:         upstream.URL,
+		ChannelType: "openai",
+		Models:      []model.ModelEntry{{Model: "gpt-4o-mini"}},
+		Enabled:     true,
+	}
+
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels?validate=true", payload))
+	srv.handleCreateChannel(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status=%d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			ID            int64                       `json:"id"`
+			KeyValidation []ChannelKeyPreflightResult `json:"key_validation"`
+			Warnings      []ChannelLintWarning        `json:"warnings"`
+		} `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	if len(resp.Data.KeyValidation) != 2 || !resp.Data.KeyValidation[0].Valid || resp.Data.KeyValidation[1].Valid {
+		t.Fatalf("unexpected key_validation: %+v", resp.Data.KeyValidation)
+	}
+	if resp.Data.KeyValidation[1].StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status_code=%d, want 401", resp.Data.KeyValidation[1].StatusCode)
+	}
+	if len(resp.Data.Warnings) != 1 || resp.Data.Warnings[0].Code != "key_validation_failed" {
+		t.Fatalf("unexpected warnings: %+v", resp.Data.Warnings)
+	}
+
+	keys, err := srv.store.GetAPIKeys(context.Background(), resp.Data.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].Disabled || !keys[1].Disabled {
+		t.Fatalf("unexpected key disabled state: %+v, %+v", keys[0], keys[1])
+	}
+}
+
+func TestHandleCreateChannel_WithoutValidateSkipsPreflight(t *testing.T) {
+	hits := 0
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer upstream.Close()
+
+	srv := newInMemoryServer(t)
+	payload := ChannelRequest{
+		Name:        "no-preflight-channel",
+		APIKey:      "sk-bad",
+		URL:         upstream.URL,
+		ChannelType: "openai",
+		Models:      []model.ModelEntry{{Model: "gpt-4o-mini"}},
+		Enabled:     true,
+	}
+
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels", payload))
+	srv.handleCreateChannel(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status=%d, body=%s", w.Code, w.Body.String())
+	}
+	if hits != 0 {
+		t.Fatalf("upstream hits=%d, want 0", hits)
+	}
+}
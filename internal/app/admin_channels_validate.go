@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	channelLintDNSTimeout     = 3 * time.Second // 单个主机名解析超时
+	channelLintDNSConcurrency = 8               // 并发解析上限
+)
+
+// channelLintLookupHost DNS 解析函数（测试可替换）
+var channelLintLookupHost = net.DefaultResolver.LookupHost
+
+// channelLintHostHints 官方域名与渠道类型的对应关系（用于类型/URL 不匹配检查）
+var channelLintHostHints = []struct {
+	suffix      string
+	channelType []string
+}{
+	{"anthropic.com", []string{util.ChannelTypeAnthropic}},
+	{"openai.com", []string{util.ChannelTypeOpenAI, util.ChannelTypeCodex}},
+	{"googleapis.com", []string{util.ChannelTypeGemini}},
+	{"openai.azure.com", []string{util.ChannelTypeAzure}},
+}
+
+// channelLintEndpointSuffixes URL 末尾误带的完整端点路径（渠道 URL 应为 base URL）
+var channelLintEndpointSuffixes = []string{
+	"/v1/messages", "/v1/chat/completions", "/v1/responses", "/v1/completions", ":generateContent",
+}
+
+// HandleValidateChannels 检查所有渠道的常见配置问题（只读，不修改任何数据）
+// GET /admin/channels/validate?dns=false
+// 检查项：URL 格式与主机名 DNS 解析、无可用 Key、重复模型、重定向目标不受支持、重定向链、渠道类型与 URL 不匹配。
+func (s *Server) HandleValidateChannels(c *gin.Context) {
+	ctx := c.Request.Context()
+	cfgs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var dnsErrors map[string]error
+	if c.DefaultQuery("dns", "true") != "false" {
+		dnsErrors = resolveChannelHosts(ctx, cfgs)
+	}
+
+	resp := ChannelLintResponse{Checked: len(cfgs), Channels: []ChannelLintResult{}}
+	for _, cfg := range cfgs {
+		warnings := lintChannelConfig(cfg, dnsErrors)
+		if len(warnings) == 0 {
+			continue
+		}
+		resp.Channels = append(resp.Channels, ChannelLintResult{
+			ID:          cfg.ID,
+			Name:        cfg.Name,
+			ChannelType: cfg.GetChannelType(),
+			Warnings:    warnings,
+		})
+	}
+
+	RespondJSON(c, http.StatusOK, resp)
+}
+
+// lintChannelConfig 检查单个渠道配置；dnsErrors 为 nil 时跳过 DNS 检查
+func lintChannelConfig(cfg *model.Config, dnsErrors map[string]error) []ChannelLintWarning {
+	var warnings []ChannelLintWarning
+	warn := func(code, format string, args ...any) {
+		warnings = append(warnings, ChannelLintWarning{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	channelType := cfg.GetChannelType()
+	urls := cfg.GetURLs()
+	if len(urls) == 0 {
+		warn("empty_url", "channel has no URL")
+	}
+	for _, raw := range urls {
+		u, err := neturl.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			warn("invalid_url", "invalid URL %q", raw)
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if err, ok := dnsErrors[host]; ok && err != nil {
+			warn("dns_unresolvable", "hostname %q cannot be resolved: %v", host, err)
+		}
+		for _, suffix := range channelLintEndpointSuffixes {
+			if strings.HasSuffix(strings.TrimRight(u.Path, "/"), suffix) {
+				warn("url_has_endpoint_path", "URL %q ends with endpoint path %q; use the base URL", raw, suffix)
+				break
+			}
+		}
+		for _, hint := range channelLintHostHints {
+			if (host == hint.suffix || strings.HasSuffix(host, "."+hint.suffix)) && !slices.Contains(hint.channelType, channelType) {
+				warn("channel_type_mismatch", "URL host %q looks like %s but channel_type is %q", host, strings.Join(hint.channelType, "/"), channelType)
+			}
+		}
+	}
+
+	if cfg.KeyCount == 0 {
+		warn("no_api_keys", "channel has no enabled API keys")
+	}
+
+	if len(cfg.ModelEntries) == 0 {
+		warn("no_models", "channel has no models")
+	}
+	seen := make(map[string]struct{}, len(cfg.ModelEntries))
+	redirects := make(map[string]string, len(cfg.ModelEntries))
+	for _, e := range cfg.ModelEntries {
+		key := strings.ToLower(strings.TrimSpace(e.Model))
+		if _, dup := seen[key]; dup {
+			warn("duplicate_model", "model %q is listed more than once", e.Model)
+		}
+		seen[key] = struct{}{}
+		if e.RedirectModel != "" && e.RedirectModel != e.Model {
+			redirects[e.Model] = e.RedirectModel
+		}
+	}
+	for _, e := range cfg.ModelEntries {
+		to, ok := redirects[e.Model]
+		if !ok {
+			continue
+		}
+		// 重定向目标须是渠道支持的模型：在模型列表中且未被 blocked_models 屏蔽
+		if cfg.BlocksModel(to) {
+			warn("redirect_target_unsupported", "model %q redirects to %q, which is blocked by this channel", e.Model, to)
+		} else if _, listed := seen[strings.ToLower(strings.TrimSpace(to))]; !listed {
+			warn("redirect_target_unsupported", "model %q redirects to %q, which is not in this channel's models", e.Model, to)
+		}
+		// 重定向只解析一次：目标若本身又被重定向，实际发往上游的仍是目标名，通常不是预期行为
+		if next, chained := redirects[to]; chained {
+			warn("redirect_chain", "model %q redirects to %q, which itself redirects to %q (redirects are not transitive)", e.Model, to, next)
+		}
+	}
+
+	return warnings
+}
+
+// resolveChannelHosts 并发解析所有渠道 URL 的主机名（去重，IP 字面量跳过），返回 host → 解析错误
+func resolveChannelHosts(ctx context.Context, cfgs []*model.Config) map[string]error {
+	hosts := make(map[string]struct{})
+	for _, cfg := range cfgs {
+		for _, raw := range cfg.GetURLs() {
+			u, err := neturl.Parse(raw)
+			if err != nil || u.Hostname() == "" {
+				continue
+			}
+			host := strings.ToLower(u.Hostname())
+			if net.ParseIP(host) != nil {
+				continue
+			}
+			hosts[host] = struct{}{}
+		}
+	}
+
+	results := make(map[string]error, len(hosts))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, channelLintDNSConcurrency)
+	for host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lookupCtx, cancel := context.WithTimeout(ctx, channelLintDNSTimeout)
+			defer cancel()
+			_, err := channelLintLookupHost(lookupCtx, host)
+			mu.Lock()
+			results[host] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
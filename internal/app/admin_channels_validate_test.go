@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func lintCodes(warnings []ChannelLintWarning) map[string]int {
+	codes := make(map[string]int, len(warnings))
+	for _, w := range warnings {
+		codes[w.Code]++
+	}
+	return codes
+}
+
+func TestLintChannelConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean channel has no warnings", func(t *testing.T) {
+		cfg := &model.Config{
+			ChannelType:  "anthropic",
+			URL:          "https://api.anthropic.com",
+			KeyCount:     1,
+			ModelEntries: []model.ModelEntry{{Model: "claude-sonnet-4"}},
+		}
+		if warnings := lintChannelConfig(cfg, nil); len(warnings) != 0 {
+			t.Fatalf("unexpected warnings: %+v", warnings)
+		}
+	})
+
+	t.Run("reports common misconfigurations", func(t *testing.T) {
+		cfg := &model.Config{
+			ChannelType: "anthropic",
+			URL:         "https://api.openai.com/v1/chat/completions\nftp://bad",
+			KeyCount:    0,
+			ModelEntries: []model.ModelEntry{
+				{Model: "gpt-4o"},
+				{Model: "GPT-4o"},
+				{Model: "alias", RedirectModel: "mid"},
+				{Model: "mid", RedirectModel: "gpt-4o"},
+			},
+		}
+		codes := lintCodes(lintChannelConfig(cfg, nil))
+		for _, want := range []string{"url_has_endpoint_path", "channel_type_mismatch", "invalid_url", "no_api_keys", "duplicate_model", "redirect_chain"} {
+			if codes[want] == 0 {
+				t.Errorf("missing warning %q in %v", want, codes)
+			}
+		}
+		if codes["dns_unresolvable"] != 0 {
+			t.Errorf("dns check should be skipped when dnsErrors is nil")
+		}
+	})
+
+	t.Run("reports unsupported redirect targets", func(t *testing.T) {
+		cfg := &model.Config{
+			ChannelType: "openai",
+			URL:         "https://api.example.com",
+			KeyCount:    1,
+			ModelEntries: []model.ModelEntry{
+				{Model: "gpt-4o"},
+				{Model: "gpt-4o-mini"},
+				{Model: "fast", RedirectModel: "GPT-4o-mini"}, // 列表中（不区分大小写）：正常
+				{Model: "smart", RedirectModel: "gpt-5"},      // 不在模型列表
+				{Model: "cheap", RedirectModel: "gpt-4o"},     // 被屏蔽
+			},
+			BlockedModels: []string{"gpt-4o"},
+		}
+		warnings := lintChannelConfig(cfg, nil)
+		if codes := lintCodes(warnings); codes["redirect_target_unsupported"] != 2 || codes["redirect_chain"] != 0 {
+			t.Fatalf("expected 2 redirect_target_unsupported warnings, got %+v", warnings)
+		}
+	})
+
+	t.Run("codex accepts openai host", func(t *testing.T) {
+		cfg := &model.Config{
+			ChannelType:  "codex",
+			URL:          "https://api.openai.com",
+			KeyCount:     1,
+			ModelEntries: []model.ModelEntry{{Model: "gpt-5-codex"}},
+		}
+		if codes := lintCodes(lintChannelConfig(cfg, nil)); codes["channel_type_mismatch"] != 0 {
+			t.Fatalf("codex on openai.com should not be a mismatch: %v", codes)
+		}
+	})
+}
+
+func TestHandleValidateChannels_ReportsDNSFailures(t *testing.T) {
+	origLookup := channelLintLookupHost
+	t.Cleanup(func() { channelLintLookupHost = origLookup })
+	channelLintLookupHost = func(_ context.Context, host string) ([]string, error) {
+		if host == "missing.invalid" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"127.0.0.1"}, nil
+	}
+
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+	good, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name: "good", URL: "https://ok.example.com", ChannelType: "openai", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	})
+	if err != nil {
+		t.Fatalf("create good: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{{ChannelID: good.ID, KeyIndex: 0, APIKey: "sk-1", KeyStrategy: model.KeyStrategySequential}}); err != nil {
+		t.Fatalf("create keys: %v", err)
+	}
+	bad, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name: "bad", URL: "https://missing.invalid", ChannelType: "openai", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	})
+	if err != nil {
+		t.Fatalf("create bad: %v", err)
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/channels/validate", nil))
+	srv.HandleValidateChannels(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	resp := mustParseAPIResponse[ChannelLintResponse](t, w.Body.Bytes())
+	if resp.Data.Checked != 2 {
+		t.Fatalf("checked=%d, want 2", resp.Data.Checked)
+	}
+	if len(resp.Data.Channels) != 1 || resp.Data.Channels[0].ID != bad.ID {
+		t.Fatalf("expected only bad channel in report, got %+v", resp.Data.Channels)
+	}
+	codes := lintCodes(resp.Data.Channels[0].Warnings)
+	if codes["dns_unresolvable"] != 1 || codes["no_api_keys"] != 1 {
+		t.Fatalf("unexpected warnings: %+v", resp.Data.Channels[0].Warnings)
+	}
+}
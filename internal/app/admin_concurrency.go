@@ -0,0 +1,41 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyStatus 全局并发槽位使用情况
+type ConcurrencyStatus struct {
+	Capacity         int     `json:"capacity"`                     // 槽位容量（CCLOAD_MAX_CONCURRENCY，含优先槽位）
+	InFlight         int     `json:"in_flight"`                    // 占用槽位的代理请求数（含优先槽位）
+	Waiting          int64   `json:"waiting"`                      // 正在等待槽位的请求数
+	WaitedTotal      int64   `json:"waited_total"`                 // 启动以来因槽位已满而等待的请求总数
+	Utilization      float64 `json:"utilization"`                  // in_flight / capacity
+	PriorityCapacity int     `json:"priority_capacity,omitempty"`  // 优先请求预留槽位数（CCLOAD_PRIORITY_CONCURRENCY）
+	PriorityInFlight int     `json:"priority_in_flight,omitempty"` // 占用优先槽位的请求数
+}
+
+// HandleConcurrencyStatus 查询全局并发槽位使用情况（用于容量规划与饱和监控）
+// GET /admin/concurrency
+func (s *Server) HandleConcurrencyStatus(c *gin.Context) {
+	RespondJSON(c, http.StatusOK, s.concurrencyStatus())
+}
+
+func (s *Server) concurrencyStatus() ConcurrencyStatus {
+	capacity := cap(s.concurrencySem) + cap(s.prioritySem)
+	inFlight := len(s.concurrencySem) + len(s.prioritySem)
+	status := ConcurrencyStatus{
+		Capacity:         capacity,
+		InFlight:         inFlight,
+		Waiting:          s.concurrencyWaiting.Load(),
+		WaitedTotal:      s.concurrencyWaitedTotal.Load(),
+		PriorityCapacity: cap(s.prioritySem),
+		PriorityInFlight: len(s.prioritySem),
+	}
+	if capacity > 0 {
+		status.Utilization = float64(inFlight) / float64(capacity)
+	}
+	return status
+}
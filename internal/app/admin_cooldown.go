@@ -1,11 +1,18 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"ccLoad/internal/cooldown"
+	"ccLoad/internal/model"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,7 +40,9 @@ func (s *Server) HandleSetChannelCooldown(c *gin.Context) {
 		return
 	}
 
-	// 精确计数(手动设置渠道冷却
+	setAuditChanges(c, id, map[string]model.AuditChange{
+		"cooldown_until": {New: until.Unix()},
+	})
 
 	RespondJSON(c, http.StatusOK, gin.H{"message": fmt.Sprintf("渠道已冷却 %d 毫秒", req.DurationMs)})
 }
@@ -69,5 +78,122 @@ func (s *Server) HandleSetKeyCooldown(c *gin.Context) {
 	// [INFO] 修复：使API Keys缓存失效，确保前端能立即看到冷却状态
 	s.InvalidateAPIKeysCache(id)
 
+	setAuditChanges(c, id, map[string]model.AuditChange{
+		"key_" + keyIndexStr + ".cooldown_until": {New: until.Unix()},
+	})
+
 	RespondJSON(c, http.StatusOK, gin.H{"message": fmt.Sprintf("Key #%d 已冷却 %d 毫秒", keyIndex+1, req.DurationMs)})
 }
+
+// HandleSetChannelMaintenance 切换渠道维护模式
+// POST /admin/channels/:id/maintenance
+// 维护中的渠道不参与选择，冷却状态冻结（不再累加退避）；退出维护时清空渠道、Key 和模型冷却，以全新状态回到轮换
+func (s *Server) HandleSetChannelMaintenance(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	var req ChannelMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	before, err := s.store.GetConfig(ctx, id)
+	if err != nil {
+		RespondErrorMsg(c, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	maintenance := *req.Maintenance
+	upd, err := s.store.UpdateChannelMaintenance(ctx, id, maintenance)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if before.Maintenance && !maintenance && s.cooldownManager != nil {
+		if err := s.cooldownManager.ClearAllCooldowns(ctx, id); err != nil {
+			log.Printf("[WARN] 退出维护时清除渠道全部冷却状态失败 (channel=%d): %v", id, err)
+		}
+		// 重新读取，返回清空冷却后的渠道状态
+		if fresh, err := s.store.GetConfig(ctx, id); err == nil {
+			upd = fresh
+		}
+	}
+
+	// 维护状态影响渠道选择，冷却可能已清空：立即失效相关缓存
+	s.InvalidateChannelListCache()
+	s.invalidateCooldownCache()
+	s.InvalidateAPIKeysCache(id)
+
+	if before.Maintenance != maintenance {
+		log.Printf("[INFO] 渠道 %d 已%s维护模式", id, map[bool]string{true: "进入", false: "退出"}[maintenance])
+	}
+	setAuditChannelDiff(c, before, upd)
+	RespondJSON(c, http.StatusOK, upd)
+}
+
+// HandleSimulateChannelError 模拟一次上游错误，走与真实请求相同的冷却决策（仅供测试环境验证冷却与告警链路）
+// POST /admin/channels/:id/simulate-error
+func (s *Server) HandleSimulateChannelError(c *gin.Context) {
+	id, err := ParseInt64Param(c, "id")
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid channel ID")
+		return
+	}
+
+	var req SimulateErrorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	cfg, err := s.store.GetConfig(ctx, id)
+	if err != nil {
+		RespondErrorMsg(c, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	keyIndex := cooldown.NoKeyIndex
+	if req.KeyIndex != nil {
+		// KeyIndex 可能不连续，按渠道实际 Key 校验而非 KeyCount
+		apiKeys, err := s.store.GetAPIKeys(ctx, id)
+		if err != nil {
+			RespondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if !slices.ContainsFunc(apiKeys, func(k *model.APIKey) bool { return k.KeyIndex == *req.KeyIndex }) {
+			RespondErrorMsg(c, http.StatusBadRequest, "invalid key index")
+			return
+		}
+		keyIndex = *req.KeyIndex
+	}
+
+	in := httpErrorInputFromParts(id, keyIndex, req.StatusCode, []byte(req.Body), nil)
+	in.Model = strings.TrimSpace(req.Model)
+	action := s.applyCooldownDecision(ctx, cfg, in)
+
+	log.Printf("[INFO] 模拟上游错误: 渠道=%d Key=%d 状态码=%d 决策=%s", id, keyIndex, req.StatusCode, attemptDecision(action))
+
+	setAuditChanges(c, id, map[string]model.AuditChange{
+		"simulated_error": {New: req.StatusCode},
+	})
+
+	resp := gin.H{
+		"channel_id":  id,
+		"status_code": req.StatusCode,
+		"decision":    attemptDecision(action),
+	}
+	if updated, err := s.store.GetConfig(ctx, id); err == nil {
+		resp["cooldown_until"] = updated.CooldownUntil
+		resp["cooldown_duration_ms"] = updated.CooldownDurationMs
+	}
+	RespondJSON(c, http.StatusOK, resp)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"ccLoad/internal/model"
@@ -341,3 +343,192 @@ func TestSetKeyCooldown_Integration(t *testing.T) {
 		t.Error("期望Key被冷却, 但 CooldownUntil=0")
 	}
 }
+
+func TestHandleSimulateChannelError(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name:         "simulate-channel",
+		URL:          "http://test.example.com",
+		Priority:     1,
+		ModelEntries: []model.ModelEntry{{Model: "test-model"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建测试渠道失败: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: cfg.ID, KeyIndex: 0, APIKey: "k0", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: cfg.ID, KeyIndex: 1, APIKey: "k1", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: cfg.ID, KeyIndex: 7, APIKey: "k7", KeyStrategy: model.KeyStrategySequential}, // 非连续 KeyIndex
+	}); err != nil {
+		t.Fatalf("创建API Key失败: %v", err)
+	}
+	channelID := strconv.FormatInt(cfg.ID, 10)
+
+	simulate := func(id string, body map[string]any) (*httptest.ResponseRecorder, map[string]any) {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels/"+id+"/simulate-error", body))
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		srv.HandleSimulateChannelError(c)
+		var data map[string]any
+		if w.Code == http.StatusOK {
+			data = mustParseAPIResponse[map[string]any](t, w.Body.Bytes()).Data
+		}
+		return w, data
+	}
+
+	// Key 级错误：只冷却该 Key，不冷却渠道
+	w, data := simulate(channelID, map[string]any{"status_code": 401, "key_index": 1})
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200, 实际 %d: %s", w.Code, w.Body.String())
+	}
+	if data["decision"] != "retry_key" {
+		t.Fatalf("期望 retry_key, 实际 %v", data["decision"])
+	}
+	key, err := srv.store.GetAPIKey(ctx, cfg.ID, 1)
+	if err != nil || key.CooldownUntil == 0 {
+		t.Fatalf("期望 Key#1 进入冷却: %+v, err=%v", key, err)
+	}
+
+	// 非连续 KeyIndex（超出 KeyCount）按实际 Key 校验
+	if w, _ = simulate(channelID, map[string]any{"status_code": 401, "key_index": 7}); w.Code != http.StatusOK {
+		t.Fatalf("非连续 KeyIndex 期望 200, 实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	// 渠道级错误：冷却整个渠道
+	w, data = simulate(channelID, map[string]any{"status_code": 502})
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200, 实际 %d: %s", w.Code, w.Body.String())
+	}
+	if data["decision"] != "retry_channel" {
+		t.Fatalf("期望 retry_channel, 实际 %v", data["decision"])
+	}
+	if until, _ := data["cooldown_until"].(float64); until == 0 {
+		t.Fatalf("期望响应包含渠道冷却时间, 实际 %v", data)
+	}
+
+	for name, tc := range map[string]struct {
+		id   string
+		body map[string]any
+		want int
+	}{
+		"无效渠道ID":  {id: "abc", body: map[string]any{"status_code": 500}, want: http.StatusBadRequest},
+		"缺少状态码":   {id: channelID, body: map[string]any{}, want: http.StatusBadRequest},
+		"非错误状态码":  {id: channelID, body: map[string]any{"status_code": 200}, want: http.StatusBadRequest},
+		"Key索引越界": {id: channelID, body: map[string]any{"status_code": 401, "key_index": 5}, want: http.StatusBadRequest},
+		"渠道不存在":   {id: "9999", body: map[string]any{"status_code": 500}, want: http.StatusNotFound},
+	} {
+		if w, _ := simulate(tc.id, tc.body); w.Code != tc.want {
+			t.Errorf("%s: 期望 %d, 实际 %d", name, tc.want, w.Code)
+		}
+	}
+}
+
+func TestHandleSetChannelMaintenance(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name:         "maintenance-channel",
+		URL:          "http://test.example.com",
+		Priority:     1,
+		ModelEntries: []model.ModelEntry{{Model: "test-model"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建测试渠道失败: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: cfg.ID, KeyIndex: 0, APIKey: "k0", KeyStrategy: model.KeyStrategySequential},
+	}); err != nil {
+		t.Fatalf("创建API Key失败: %v", err)
+	}
+	channelID := strconv.FormatInt(cfg.ID, 10)
+
+	setMaintenance := func(id string, body map[string]any) (*httptest.ResponseRecorder, *model.Config) {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels/"+id+"/maintenance", body))
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		srv.HandleSetChannelMaintenance(c)
+		if w.Code != http.StatusOK {
+			return w, nil
+		}
+		return w, mustParseAPIResponse[*model.Config](t, w.Body.Bytes()).Data
+	}
+	simulate := func(body map[string]any) {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels/"+channelID+"/simulate-error", body))
+		c.Params = gin.Params{{Key: "id", Value: channelID}}
+		srv.HandleSimulateChannelError(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("模拟错误失败: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	// 进入维护前已有的渠道冷却
+	simulate(map[string]any{"status_code": 502})
+	before, err := srv.store.GetConfig(ctx, cfg.ID)
+	if err != nil || before.CooldownUntil == 0 {
+		t.Fatalf("期望渠道进入冷却: %+v, err=%v", before, err)
+	}
+
+	w, upd := setMaintenance(channelID, map[string]any{"maintenance": true})
+	if w.Code != http.StatusOK || !upd.Maintenance {
+		t.Fatalf("进入维护失败: %d %s", w.Code, w.Body.String())
+	}
+
+	// 维护中：不参与选择
+	filtered, err := srv.filterCooldownChannels(ctx, []*model.Config{upd}, "test-model", "")
+	if err != nil || len(filtered) != 0 {
+		t.Fatalf("维护中的渠道不应参与选择: %v, err=%v", filtered, err)
+	}
+
+	// 维护中：冷却冻结（渠道与 Key 均不累加）
+	simulate(map[string]any{"status_code": 502})
+	simulate(map[string]any{"status_code": 401, "key_index": 0})
+	frozen, err := srv.store.GetConfig(ctx, cfg.ID)
+	if err != nil {
+		t.Fatalf("读取渠道失败: %v", err)
+	}
+	if frozen.CooldownUntil != before.CooldownUntil || frozen.CooldownDurationMs != before.CooldownDurationMs || frozen.ConsecutiveFailures != before.ConsecutiveFailures {
+		t.Fatalf("维护中冷却不应变化: before=%+v after=%+v", before, frozen)
+	}
+	if key, err := srv.store.GetAPIKey(ctx, cfg.ID, 0); err != nil || key.CooldownUntil != 0 {
+		t.Fatalf("维护中 Key 不应进入冷却: %+v, err=%v", key, err)
+	}
+
+	// 编辑渠道不会清除维护标记
+	frozen.Priority = 2
+	if edited, err := srv.store.UpdateConfig(ctx, cfg.ID, frozen); err != nil || !edited.Maintenance {
+		t.Fatalf("UpdateConfig 不应改变维护标记: %+v, err=%v", edited, err)
+	}
+
+	// 退出维护：冷却清零，重新参与选择
+	w, upd = setMaintenance(channelID, map[string]any{"maintenance": false})
+	if w.Code != http.StatusOK || upd.Maintenance {
+		t.Fatalf("退出维护失败: %d %s", w.Code, w.Body.String())
+	}
+	if upd.CooldownUntil != 0 || upd.CooldownDurationMs != 0 || upd.ConsecutiveFailures != 0 {
+		t.Fatalf("退出维护应清空冷却: %+v", upd)
+	}
+	filtered, err = srv.filterCooldownChannels(ctx, []*model.Config{upd}, "test-model", "")
+	if err != nil || len(filtered) != 1 {
+		t.Fatalf("退出维护后应重新参与选择: %v, err=%v", filtered, err)
+	}
+
+	for name, tc := range map[string]struct {
+		id   string
+		body map[string]any
+		want int
+	}{
+		"无效渠道ID": {id: "abc", body: map[string]any{"maintenance": true}, want: http.StatusBadRequest},
+		"缺少字段":   {id: channelID, body: map[string]any{}, want: http.StatusBadRequest},
+		"渠道不存在":  {id: "9999", body: map[string]any{"maintenance": true}, want: http.StatusNotFound},
+	} {
+		if w, _ := setMaintenance(tc.id, tc.body); w.Code != tc.want {
+			t.Errorf("%s: 期望 %d, 实际 %d", name, tc.want, w.Code)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"math"
 	"net/http"
+	"net/http/httptest"
 	"slices"
 	"sort"
 	"strconv"
@@ -249,6 +250,51 @@ func TestHandleListChannelsTypeFilterIncludesProtocolTransforms(t *testing.T) {
 	}
 }
 
+func TestHandleListChannelsEnabledAndChannelTypeFilters(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	fixtures := []*model.Config{
+		{Name: "gemini-on", URL: "https://g1.example.com", ChannelType: "gemini", ModelEntries: []model.ModelEntry{{Model: "m"}}, Enabled: true},
+		{Name: "gemini-off", URL: "https://g2.example.com", ChannelType: "gemini", ModelEntries: []model.ModelEntry{{Model: "m"}}, Enabled: false},
+		{Name: "openai-off", URL: "https://o1.example.com", ChannelType: "openai", ModelEntries: []model.ModelEntry{{Model: "m"}}, Enabled: false},
+		{Name: "anthropic-to-gemini", URL: "https://a1.example.com", ChannelType: "anthropic", ProtocolTransforms: []string{"gemini"}, ModelEntries: []model.ModelEntry{{Model: "m"}}, Enabled: true},
+	}
+	for _, fixture := range fixtures {
+		if _, err := store.CreateConfig(ctx, fixture); err != nil {
+			t.Fatalf("CreateConfig(%s) failed: %v", fixture.Name, err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"enabled=false", []string{"gemini-off", "openai-off"}},
+		{"enabled=true", []string{"anthropic-to-gemini", "gemini-on"}},
+		{"channel_type=gemini", []string{"gemini-off", "gemini-on"}},
+		{"channel_type=gemini&enabled=false", []string{"gemini-off"}},
+		{"enabled=maybe", []string{"anthropic-to-gemini", "gemini-off", "gemini-on", "openai-off"}},
+	}
+	for _, tt := range tests {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/channels?"+tt.query, nil))
+		server.handleListChannels(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status=%d body=%s", tt.query, w.Code, w.Body.String())
+		}
+		resp := mustParseAPIResponse[[]ChannelWithCooldown](t, w.Body.Bytes())
+		gotNames := make([]string, 0, len(resp.Data))
+		for _, item := range resp.Data {
+			gotNames = append(gotNames, item.Name)
+		}
+		sort.Strings(gotNames)
+		if !slices.Equal(gotNames, tt.want) {
+			t.Fatalf("%s: names=%v, want %v", tt.query, gotNames, tt.want)
+		}
+	}
+}
+
 func TestHandleChannelsFilterOptionsTypeFilterIncludesProtocolTransforms(t *testing.T) {
 	server, store, cleanup := setupAdminTestServer(t)
 	defer cleanup()
@@ -766,6 +812,72 @@ func TestHandleChannelModelStatsReturnsTodayStatsForRequestedChannel(t *testing.
 	}
 }
 
+func TestHandleChannelRecentErrorsReturnsLatestNon2xxLogs(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "recent-errors-channel",
+		URL:          "https://api.example.com",
+		ModelEntries: []model.ModelEntry{{Model: "m"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建测试渠道失败: %v", err)
+	}
+	other, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "other-recent-errors-channel",
+		URL:          "https://other.example.com",
+		ModelEntries: []model.ModelEntry{{Model: "m"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建其他渠道失败: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	entries := []*model.LogEntry{
+		{Time: model.JSONTime{Time: now.Add(-5 * time.Minute)}, ChannelID: created.ID, Model: "m", StatusCode: http.StatusTooManyRequests, Message: "rate limited", LogSource: model.LogSourceProxy},
+		{Time: model.JSONTime{Time: now.Add(-4 * time.Minute)}, ChannelID: created.ID, Model: "m", StatusCode: http.StatusOK, LogSource: model.LogSourceProxy},
+		{Time: model.JSONTime{Time: now.Add(-3 * time.Minute)}, ChannelID: created.ID, Model: "m", StatusCode: http.StatusBadGateway, Message: "bad gateway", LogSource: model.LogSourceScheduledCheck},
+		{Time: model.JSONTime{Time: now.Add(-2 * time.Minute)}, ChannelID: other.ID, Model: "m", StatusCode: http.StatusInternalServerError, Message: "other", LogSource: model.LogSourceProxy},
+		{Time: model.JSONTime{Time: now.Add(-1 * time.Minute)}, ChannelID: created.ID, Model: "m", StatusCode: http.StatusServiceUnavailable, Message: "overloaded", LogSource: model.LogSourceProxy},
+	}
+	for _, entry := range entries {
+		if err := store.AddLog(ctx, entry); err != nil {
+			t.Fatalf("写入测试日志失败: %v", err)
+		}
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		t.Helper()
+		path := "/admin/channels/" + strconv.FormatInt(created.ID, 10) + "/recent-errors" + query
+		c, w := newTestContext(t, newRequest(http.MethodGet, path, nil))
+		c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(created.ID, 10)}}
+		server.HandleChannelRecentErrors(c)
+		return w
+	}
+
+	w := get("?limit=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want %d body=%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	resp := mustParseAPIResponse[[]ChannelRecentError](t, w.Body.Bytes())
+	if len(resp.Data) != 2 || resp.Data[0].Message != "overloaded" || resp.Data[1].StatusCode != http.StatusBadGateway {
+		t.Fatalf("recent errors=%+v, want [overloaded, bad gateway]", resp.Data)
+	}
+
+	resp = mustParseAPIResponse[[]ChannelRecentError](t, get("").Body.Bytes())
+	if len(resp.Data) != 3 {
+		t.Fatalf("default limit: got %d errors, want 3", len(resp.Data))
+	}
+
+	if w := get("?limit=0"); w.Code != http.StatusBadRequest {
+		t.Fatalf("limit=0: status=%d, want 400", w.Code)
+	}
+}
+
 // TestHandleUpdateChannel 测试更新渠道
 func TestHandleUpdateChannel(t *testing.T) {
 	server, store, cleanup := setupAdminTestServer(t)
@@ -1245,6 +1357,111 @@ func TestHandleUpdateChannelPreservesDisabledKeysWhenRebuilding(t *testing.T) {
 	assertKey(2, "sk-new", false)
 }
 
+func TestHandleUpdateChannel_KeyChangesPreserveRetainedKeyCooldowns(t *testing.T) {
+	cases := []struct {
+		name      string
+		apiKey    string
+		wantOrder []string
+	}{
+		{name: "append", apiKey: "sk-a,sk-b,sk-c,sk-d", wantOrder: []string{"sk-a", "sk-b", "sk-c", "sk-d"}},
+		{name: "remove middle", apiKey: "sk-a,sk-c", wantOrder: []string{"sk-a", "sk-c"}},
+		{name: "reorder", apiKey: "sk-c,sk-a,sk-b", wantOrder: []string{"sk-c", "sk-a", "sk-b"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, store, cleanup := setupAdminTestServer(t)
+			defer cleanup()
+			server.cooldownManager = cooldown.NewManager(store, server)
+
+			ctx := context.Background()
+			created, err := store.CreateConfig(ctx, &model.Config{
+				Name:         "key-diff",
+				URL:          "https://api.example.com",
+				Priority:     10,
+				ModelEntries: []model.ModelEntry{{Model: "model-1"}},
+				Enabled:      true,
+			})
+			if err != nil {
+				t.Fatalf("创建测试渠道失败: %v", err)
+			}
+			if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+				{ChannelID: created.ID, KeyIndex: 0, APIKey: "sk-a", KeyStrategy: model.KeyStrategySequential},
+				{ChannelID: created.ID, KeyIndex: 1, APIKey: "sk-b", KeyStrategy: model.KeyStrategySequential},
+				{ChannelID: created.ID, KeyIndex: 2, APIKey: "sk-c", KeyStrategy: model.KeyStrategySequential},
+			}); err != nil {
+				t.Fatalf("创建测试 keys 失败: %v", err)
+			}
+			before, err := store.GetAPIKeys(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("查询初始 keys 失败: %v", err)
+			}
+			idByKey := make(map[string]int64, len(before))
+			for _, k := range before {
+				idByKey[k.APIKey] = k.ID
+			}
+			// sk-a / sk-c 冷却中，sk-b 正常
+			cooldownUntil := time.Now().Add(15 * time.Minute).Truncate(time.Second)
+			for _, idx := range []int{0, 2} {
+				if err := store.SetKeyCooldown(ctx, created.ID, idx, cooldownUntil); err != nil {
+					t.Fatalf("设置 Key 冷却失败: %v", err)
+				}
+			}
+			if err := store.SetChannelCooldown(ctx, created.ID, time.Now().Add(2*time.Minute)); err != nil {
+				t.Fatalf("设置渠道冷却失败: %v", err)
+			}
+
+			payload := ChannelRequest{
+				Name:     "key-diff",
+				APIKey:   tc.apiKey,
+				URL:      "https://api.example.com",
+				Priority: 10,
+				Models:   []model.ModelEntry{{Model: "model-1"}},
+				Enabled:  true,
+			}
+			c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+strconv.FormatInt(created.ID, 10), payload))
+			c.Params = gin.Params{{Key: "id", Value: strconv.FormatInt(created.ID, 10)}}
+			server.handleUpdateChannel(c, created.ID)
+			if w.Code != http.StatusOK {
+				t.Fatalf("更新渠道失败: %d body=%s", w.Code, w.Body.String())
+			}
+
+			keys, err := store.GetAPIKeys(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("查询更新后 keys 失败: %v", err)
+			}
+			if len(keys) != len(tc.wantOrder) {
+				t.Fatalf("期望 %d 个 key，实际 %d", len(tc.wantOrder), len(keys))
+			}
+			for i, want := range tc.wantOrder {
+				if keys[i].KeyIndex != i || keys[i].APIKey != want {
+					t.Fatalf("keys[%d] = {index:%d api_key:%q}, want {index:%d api_key:%q}", i, keys[i].KeyIndex, keys[i].APIKey, i, want)
+				}
+				// 保留的 Key 原地更新：行 ID 不变（Key 级 RPM 计数按 ID 记录）
+				if oldID, ok := idByKey[want]; ok && keys[i].ID != oldID {
+					t.Fatalf("%s id=%d, want retained id %d", want, keys[i].ID, oldID)
+				}
+				wantCooldown := int64(0)
+				if want == "sk-a" || want == "sk-c" {
+					wantCooldown = cooldownUntil.Unix()
+				}
+				if keys[i].CooldownUntil != wantCooldown {
+					t.Fatalf("%s cooldown_until=%d, want %d", want, keys[i].CooldownUntil, wantCooldown)
+				}
+			}
+
+			// 渠道级冷却仍按"保存即重置"清除
+			cfg, err := store.GetConfig(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("查询渠道失败: %v", err)
+			}
+			if cfg.CooldownUntil != 0 {
+				t.Fatalf("channel cooldown_until=%d, want cleared", cfg.CooldownUntil)
+			}
+		})
+	}
+}
+
 func TestHandleChannelAPIKeyNotesCreateReadAndUpdate(t *testing.T) {
 	server, store, cleanup := setupAdminTestServer(t)
 	defer cleanup()
@@ -1942,3 +2159,208 @@ func TestChannelRequestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleListChannels_ReportsCircuitOpen(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	server.cooldownManager = cooldown.NewManager(store, server)
+	server.cooldownManager.SetCircuitBreaker(2, time.Hour)
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "Breaker-Channel",
+		URL:          "https://api.example.com",
+		Priority:     10,
+		ModelEntries: []model.ModelEntry{{Model: "model-1"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("创建测试渠道失败: %v", err)
+	}
+
+	for range 2 {
+		if err := store.SetChannelCooldown(ctx, created.ID, time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("过期冷却失败: %v", err)
+		}
+		server.cooldownManager.HandleError(ctx, cooldown.ErrorInput{ChannelID: created.ID, KeyIndex: -1, StatusCode: 502})
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/channels", nil))
+	server.handleListChannels(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("列表请求失败: %d", w.Code)
+	}
+	resp := mustParseAPIResponse[[]ChannelWithCooldown](t, w.Body.Bytes())
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected 1 channel, got %d", len(resp.Data))
+	}
+	got := resp.Data[0]
+	if !got.CircuitOpen || got.ConsecutiveFailures != 2 {
+		t.Fatalf("expected open circuit with 2 failures, got circuit_open=%v failures=%d", got.CircuitOpen, got.ConsecutiveFailures)
+	}
+	if got.CooldownRemainingMS < int64(59*time.Minute/time.Millisecond) {
+		t.Fatalf("cooldown_remaining_ms=%d, want ~1h", got.CooldownRemainingMS)
+	}
+
+	// 熔断冷却到期后（计数未清零）不再报告熔断
+	ectx := &channelEnrichmentContext{
+		now:                 time.Now().Add(2 * time.Hour),
+		channelCooldownsMap: map[int64]time.Time{created.ID: *got.CooldownUntil},
+		cooldownManager:     server.cooldownManager,
+	}
+	if expired := ectx.enrichChannel(got.Config); expired.CircuitOpen || expired.CooldownUntil != nil {
+		t.Fatalf("expected closed circuit after reset timeout, got circuit_open=%v", expired.CircuitOpen)
+	}
+}
+
+func TestHandleCreateChannel_AppliesConfiguredDefaults(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+	server.defaultChannelPriority = 50
+	server.defaultKeyStrategy = model.KeyStrategyRoundRobin
+
+	create := func(payload map[string]any) *model.Config {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels", payload))
+		server.handleCreateChannel(c)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("期望状态码 %d，实际 %d，响应体: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data *model.Config `json:"data"`
+		}
+		mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+		return resp.Data
+	}
+	base := func(name string) map[string]any {
+		return map[string]any{
+			"name":    name,
+			"api_key": "sk-" + name,
+			"url":     "https://" + name + ".example.com",
+			"models":  []map[string]any{{"model": "gpt-4o"}},
+			"enabled": true,
+		}
+	}
+
+	// 未指定：使用配置的默认值
+	defaulted := create(base("defaulted"))
+	if defaulted.Priority != 50 {
+		t.Fatalf("priority=%d, want default 50", defaulted.Priority)
+	}
+	keys, err := store.GetAPIKeys(context.Background(), defaulted.ID)
+	if err != nil || len(keys) != 1 || keys[0].KeyStrategy != model.KeyStrategyRoundRobin {
+		t.Fatalf("expected default round_robin key strategy, got %+v err=%v", keys, err)
+	}
+
+	// 显式值（包括 0）优先
+	payload := base("explicit")
+	payload["priority"] = 0
+	payload["key_strategy"] = model.KeyStrategySequential
+	explicit := create(payload)
+	if explicit.Priority != 0 {
+		t.Fatalf("explicit priority 0 must be kept, got %d", explicit.Priority)
+	}
+	keys, err = store.GetAPIKeys(context.Background(), explicit.ID)
+	if err != nil || len(keys) != 1 || keys[0].KeyStrategy != model.KeyStrategySequential {
+		t.Fatalf("explicit key strategy must be kept, got %+v err=%v", keys, err)
+	}
+}
+
+func TestParseNewChannelDefaults(t *testing.T) {
+	if got := parseDefaultChannelPriority(" 20 "); got != 20 {
+		t.Fatalf("parseDefaultChannelPriority=%d, want 20", got)
+	}
+	if got := parseDefaultChannelPriority("high"); got != 0 {
+		t.Fatalf("invalid priority should fall back to 0, got %d", got)
+	}
+	if got := parseDefaultKeyStrategy("ROUND_ROBIN"); got != model.KeyStrategyRoundRobin {
+		t.Fatalf("parseDefaultKeyStrategy=%q", got)
+	}
+	for _, raw := range []string{"", "random"} {
+		if got := parseDefaultKeyStrategy(raw); got != model.KeyStrategySequential {
+			t.Fatalf("parseDefaultKeyStrategy(%q)=%q, want sequential", raw, got)
+		}
+	}
+}
+
+func TestHandleCreateAndUpdateChannel_DeduplicatesKeys(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	payload := map[string]any{
+		"name":    "dup-keys",
+		"api_key": "sk-a, sk-b, sk-a, sk-b, sk-c",
+		"url":     "https://dup.example.com",
+		"models":  []map[string]any{{"model": "gpt-4o"}},
+		"enabled": true,
+	}
+
+	// strict_keys=true：存在重复直接拒绝
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels?strict_keys=true", payload))
+	server.handleCreateChannel(c)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("strict mode status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// 默认：去重并返回警告
+	c, w = newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels", payload))
+	server.handleCreateChannel(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			ID       int64                `json:"id"`
+			Warnings []ChannelLintWarning `json:"warnings"`
+		} `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	if len(resp.Data.Warnings) != 1 || resp.Data.Warnings[0].Code != "duplicate_keys_removed" ||
+		!strings.Contains(resp.Data.Warnings[0].Message, "2") {
+		t.Fatalf("unexpected warnings: %+v", resp.Data.Warnings)
+	}
+	keys, err := store.GetAPIKeys(ctx, resp.Data.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if got := keyStrings(keys); strings.Join(got, ",") != "sk-a,sk-b,sk-c" {
+		t.Fatalf("keys after dedupe = %v", got)
+	}
+
+	// 更新同样去重，且保留首次出现的备注
+	id := strconv.FormatInt(resp.Data.ID, 10)
+	update := ChannelRequest{
+		Name: "dup-keys",
+		URL:  "https://dup.example.com",
+		APIKeys: []ChannelAPIKeyRequest{
+			{APIKey: "sk-x", Note: "first"},
+			{APIKey: "sk-x", Note: "second"},
+		},
+		Models:  []model.ModelEntry{{Model: "gpt-4o"}},
+		Enabled: true,
+	}
+	c, w = newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+id+"?strict_keys=true", update))
+	server.handleUpdateChannel(c, resp.Data.ID)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("strict update status=%d body=%s", w.Code, w.Body.String())
+	}
+	c, w = newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+id, update))
+	server.handleUpdateChannel(c, resp.Data.ID)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "duplicate_keys_removed") {
+		t.Fatalf("update status=%d body=%s", w.Code, w.Body.String())
+	}
+	keys, err = store.GetAPIKeys(ctx, resp.Data.ID)
+	if err != nil || len(keys) != 1 || keys[0].APIKey != "sk-x" || keys[0].Note != "first" {
+		t.Fatalf("keys after update = %+v err=%v", keys, err)
+	}
+}
+
+func keyStrings(keys []*model.APIKey) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.APIKey
+	}
+	return out
+}
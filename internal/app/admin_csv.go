@@ -2,11 +2,14 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -123,7 +126,8 @@ func (s *Server) HandleExportChannelsCSV(c *gin.Context) {
 }
 
 // HandleImportChannelsCSV 导入渠道CSV
-// POST /admin/channels/import
+// POST /admin/channels/import[?mode=update]
+// mode=update 为仅更新模式：按名称匹配已有渠道，只更新CSV中出现的列
 func (s *Server) HandleImportChannelsCSV(c *gin.Context) {
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
@@ -152,6 +156,10 @@ func (s *Server) HandleImportChannelsCSV(c *gin.Context) {
 	}
 
 	columnIndex := buildCSVColumnIndex(headerRow)
+	if c.Query("mode") == csvImportModeUpdate {
+		s.importChannelsCSVUpdateOnly(c, reader, columnIndex)
+		return
+	}
 	required := []string{"name", "api_key", "url", "models"}
 	for _, key := range required {
 		if _, ok := columnIndex[key]; !ok {
@@ -164,24 +172,72 @@ func (s *Server) HandleImportChannelsCSV(c *gin.Context) {
 	_, hasScheduledCheckModelColumn := columnIndex["scheduled_check_model"]
 	existingScheduledCheckByName := make(map[string]bool)
 	existingScheduledCheckModelByName := make(map[string]string)
-	if !hasScheduledCheckColumn || !hasScheduledCheckModelColumn {
-		existingConfigs, err := s.store.ListConfigs(c.Request.Context())
-		if err != nil {
-			RespondError(c, http.StatusInternalServerError, err)
-			return
+	existingConfigs, err := s.store.ListConfigs(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	existingKeys, err := s.store.GetAllAPIKeys(c.Request.Context())
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	// 导入按 id（若提供）或名称 upsert：更新已有渠道时以其当前 Key 数作为上限校验基准
+	// （与编辑保存一致，上限下调前已超限的渠道仍可导入不增加 Key 的修改）
+	existingKeyCountByID := make(map[int64]int, len(existingConfigs))
+	existingKeyCountByName := make(map[string]int, len(existingConfigs))
+	for _, cfg := range existingConfigs {
+		existingScheduledCheckByName[cfg.Name] = cfg.ScheduledCheckEnabled
+		existingScheduledCheckModelByName[cfg.Name] = cfg.ScheduledCheckModel
+		existingKeyCountByID[cfg.ID] = len(existingKeys[cfg.ID])
+		existingKeyCountByName[cfg.Name] = len(existingKeys[cfg.ID])
+	}
+	currentKeyCount := func(cfg *model.Config) int {
+		if cfg.ID > 0 {
+			return existingKeyCountByID[cfg.ID]
 		}
-		for _, cfg := range existingConfigs {
-			existingScheduledCheckByName[cfg.Name] = cfg.ScheduledCheckEnabled
-			existingScheduledCheckModelByName[cfg.Name] = cfg.ScheduledCheckModel
+		return existingKeyCountByName[cfg.Name]
+	}
+
+	batchSize := parseCSVImportBatchSize(c.Query("batch_size"))
+	var progress func(ChannelImportSummary) bool
+	if util.ParseBoolDefault(c.Query("progress"), false) {
+		if progress = s.startCSVImportProgressStream(c); progress == nil {
+			return
 		}
 	}
 
 	summary := ChannelImportSummary{}
 	lineNo := 1
 
-	// 批量收集有效记录,最后一次性导入(减少数据库往返)
-	validChannels := make([]*model.ChannelWithKeys, 0, 100) // 预分配容量,减少扩容
+	// 边读边导入：每满 batchSize 条有效记录提交一个事务，内存占用与文件大小无关
+	batch := make([]*model.ChannelWithKeys, 0, batchSize)
+	imported := false
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, updated, err := s.importChannelCSVBatch(c.Request.Context(), batch)
+		if err != nil {
+			return err
+		}
+		imported = true
+		summary.Created += created
+		summary.Updated += updated
+		summary.Batches++
+		batch = batch[:0]
+		summary.Processed = summary.Created + summary.Updated + summary.Skipped
+		if summary.Batches%10 == 0 {
+			log.Printf("[INFO] CSV导入进度: 第%d行, 已提交%d批 (新建=%d 更新=%d 跳过=%d)",
+				lineNo, summary.Batches, summary.Created, summary.Updated, summary.Skipped)
+		}
+		if progress != nil && !progress(summary) {
+			return c.Request.Context().Err()
+		}
+		return nil
+	}
 
+	var importErr error
 	for {
 		record, err := reader.Read()
 		if err == io.EOF {
@@ -195,7 +251,7 @@ func (s *Server) HandleImportChannelsCSV(c *gin.Context) {
 			continue
 		}
 
-		channel, errMsg, skip := s.parseChannelImportRow(
+		channel, errMsg, skip := parseChannelImportRow(
 			record,
 			columnIndex,
 			lineNo,
@@ -211,60 +267,276 @@ func (s *Server) HandleImportChannelsCSV(c *gin.Context) {
 			summary.Skipped++
 			continue
 		}
+		if err := s.checkChannelKeyLimit(len(channel.APIKeys), currentKeyCount(channel.Config)); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("第%d行Key数量超限: %v", lineNo, err))
+			summary.Skipped++
+			continue
+		}
 
-		// 收集有效记录
-		validChannels = append(validChannels, channel)
+		batch = append(batch, channel)
+		if len(batch) >= batchSize {
+			if importErr = flush(); importErr != nil {
+				break
+			}
+		}
+	}
+	if importErr == nil {
+		importErr = flush()
 	}
 
-	// 批量导入所有有效记录(单事务 + 预编译语句)
-	if len(validChannels) > 0 {
-		created, updated, err := s.store.ImportChannelBatch(c.Request.Context(), validChannels)
-		if err != nil {
-			summary.Errors = append(summary.Errors, fmt.Sprintf("批量导入失败: %v", err))
-			RespondErrorWithData(c, http.StatusInternalServerError, err.Error(), summary)
+	summary.Processed = summary.Created + summary.Updated + summary.Skipped
+
+	if imported {
+		s.InvalidateChannelListCache()
+		s.InvalidateAllAPIKeysCache()
+		s.invalidateCooldownCache()
+	}
+
+	if importErr != nil {
+		// 已提交的批次保留，summary 反映实际写入的数量
+		summary.Errors = append(summary.Errors, fmt.Sprintf("第%d批导入失败: %v", summary.Batches+1, importErr))
+		if progress != nil {
+			writeCSVImportEvent(c, "error", summary)
 			return
 		}
-		summary.Created = created
-		summary.Updated = updated
-
-		// 导入会更新渠道URL，立即清理 URLSelector 中失效URL状态，避免旧状态长期残留。
-		if s.urlSelector != nil {
-			seenIDs := make(map[int64]struct{}, len(validChannels))
-			for _, channel := range validChannels {
-				if channel == nil || channel.Config == nil || channel.Config.ID <= 0 {
-					continue
-				}
-				seenIDs[channel.Config.ID] = struct{}{}
+		RespondErrorWithData(c, http.StatusInternalServerError, importErr.Error(), summary)
+		return
+	}
+
+	if progress != nil {
+		writeCSVImportEvent(c, "done", summary)
+		return
+	}
+	RespondJSON(c, http.StatusOK, summary)
+}
+
+const (
+	// defaultCSVImportBatchSize 每个导入事务提交的渠道数
+	defaultCSVImportBatchSize = 500
+	maxCSVImportBatchSize     = 5000
+)
+
+// parseCSVImportBatchSize 解析 batch_size 查询参数（空值或非法值使用默认值，超过上限截断）
+func parseCSVImportBatchSize(raw string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n <= 0 {
+		return defaultCSVImportBatchSize
+	}
+	return min(n, maxCSVImportBatchSize)
+}
+
+// importChannelCSVBatch 单事务导入一批渠道，并清理被改写URL的渠道的URL状态
+func (s *Server) importChannelCSVBatch(ctx context.Context, batch []*model.ChannelWithKeys) (created, updated int, err error) {
+	created, updated, err = s.store.ImportChannelBatch(ctx, batch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 导入会更新渠道URL，立即清理 URLSelector 中失效URL状态，避免旧状态长期残留。
+	if s.urlSelector != nil {
+		seenIDs := make(map[int64]struct{}, len(batch))
+		for _, channel := range batch {
+			if channel == nil || channel.Config == nil || channel.Config.ID <= 0 {
+				continue
 			}
-			for channelID := range seenIDs {
-				cfg, getErr := s.store.GetConfig(c.Request.Context(), channelID)
-				if getErr != nil || cfg == nil {
-					continue
-				}
-				s.urlSelector.PruneChannel(channelID, cfg.GetURLs())
-				// 同步清理数据库中已移除URL的禁用状态记录
-				s.cleanupOrphanedURLStates(c.Request.Context(), channelID, cfg.GetURLs())
+			seenIDs[channel.Config.ID] = struct{}{}
+		}
+		for channelID := range seenIDs {
+			cfg, getErr := s.store.GetConfig(ctx, channelID)
+			if getErr != nil || cfg == nil {
+				continue
 			}
+			s.urlSelector.PruneChannel(channelID, cfg.GetURLs())
+			// 同步清理数据库中已移除URL的禁用状态记录
+			s.cleanupOrphanedURLStates(ctx, channelID, cfg.GetURLs())
 		}
 	}
+	return created, updated, nil
+}
 
-	summary.Processed = summary.Created + summary.Updated + summary.Skipped
+// startCSVImportProgressStream 切换为 SSE 响应，返回每批提交后推送 progress 事件的回调（不支持流式时返回 nil）
+func (s *Server) startCSVImportProgressStream(c *gin.Context) func(ChannelImportSummary) bool {
+	if _, ok := c.Writer.(http.Flusher); !ok {
+		RespondErrorMsg(c, http.StatusInternalServerError, "streaming unsupported")
+		return nil
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	disableResponseWriteTimeout(c.Writer, "CSV导入进度")
+
+	return func(summary ChannelImportSummary) bool {
+		// 进度事件只带计数，错误明细在最终 done/error 事件中返回
+		summary.Errors = nil
+		return writeCSVImportEvent(c, "progress", summary)
+	}
+}
+
+// writeCSVImportEvent 写出一个 SSE 事件并立即刷新
+func writeCSVImportEvent(c *gin.Context, event string, summary ChannelImportSummary) bool {
+	data, err := sonic.Marshal(summary)
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return false
+	}
+	if flusher, ok := c.Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return true
+}
+
+// csvImportModeUpdate 仅更新模式：不创建渠道，只改写已有渠道中CSV出现的列
+const csvImportModeUpdate = "update"
+
+// csvUpdatableColumns 仅更新模式支持的列（均为标量字段，无需重建Key/模型）
+var csvUpdatableColumns = []string{"priority", "enabled", "rpm_limit", "max_concurrency", "scheduled_check_enabled"}
 
-	if len(validChannels) > 0 {
+// importChannelsCSVUpdateOnly 仅更新模式导入：按 name 匹配已有渠道并更新出现的列，
+// 名称不存在的行计入 Skipped
+func (s *Server) importChannelsCSVUpdateOnly(c *gin.Context, reader *csv.Reader, columnIndex map[string]int) {
+	if _, ok := columnIndex["name"]; !ok {
+		RespondErrorMsg(c, http.StatusBadRequest, "缺少必需列: name")
+		return
+	}
+	var unsupported []string
+	for col := range columnIndex {
+		if col != "name" && !slices.Contains(csvUpdatableColumns, col) {
+			unsupported = append(unsupported, col)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		RespondErrorMsg(c, http.StatusBadRequest, fmt.Sprintf("仅更新模式不支持列: %s（支持: %s）",
+			strings.Join(unsupported, ", "), strings.Join(csvUpdatableColumns, ", ")))
+		return
+	}
+	if len(columnIndex) < 2 {
+		RespondErrorMsg(c, http.StatusBadRequest, "仅更新模式至少需要一个待更新列")
+		return
+	}
+
+	ctx := c.Request.Context()
+	existingConfigs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	configByName := make(map[string]*model.Config, len(existingConfigs))
+	for _, cfg := range existingConfigs {
+		configByName[cfg.Name] = cfg
+	}
+
+	summary := ChannelImportSummary{}
+	lineNo := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		lineNo++
+
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("第%d行读取失败: %v", lineNo, err))
+			summary.Skipped++
+			continue
+		}
+		if isCSVRecordEmpty(record) {
+			summary.Skipped++
+			continue
+		}
+
+		fetch := func(key string) string {
+			idx, ok := columnIndex[key]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		name := fetch("name")
+		existing, ok := configByName[name]
+		if !ok {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("第%d行渠道不存在: %s", lineNo, name))
+			summary.Skipped++
+			continue
+		}
+
+		upd := existing.Clone()
+		if errMsg := applyChannelCSVUpdate(upd, fetch, lineNo); errMsg != "" {
+			summary.Errors = append(summary.Errors, errMsg)
+			summary.Skipped++
+			continue
+		}
+		updated, err := s.store.UpdateConfig(ctx, existing.ID, upd)
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("第%d行更新失败: %v", lineNo, err))
+			summary.Skipped++
+			continue
+		}
+		// 同名多行时后一行基于前一行的结果继续更新
+		configByName[name] = updated
+		summary.Updated++
+	}
+
+	summary.Processed = summary.Updated + summary.Skipped
+
+	if summary.Updated > 0 {
 		s.InvalidateChannelListCache()
-		s.InvalidateAllAPIKeysCache()
-		s.invalidateCooldownCache()
 	}
 
 	RespondJSON(c, http.StatusOK, summary)
 }
 
+// applyChannelCSVUpdate 将CSV行中非空的可更新列写入 cfg，空单元格保持原值
+// 返回非空 errMsg 表示该行格式错误
+func applyChannelCSVUpdate(cfg *model.Config, fetch func(string) string, lineNo int) string {
+	if raw := fetch("priority"); raw != "" {
+		p, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Sprintf("第%d行优先级格式错误: %v", lineNo, err)
+		}
+		cfg.Priority = p
+	}
+	if raw := fetch("rpm_limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return fmt.Sprintf("第%d行RPM限制格式错误: %s", lineNo, raw)
+		}
+		cfg.RPMLimit = parsed
+	}
+	if raw := fetch("max_concurrency"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return fmt.Sprintf("第%d行并发限制格式错误: %s", lineNo, raw)
+		}
+		cfg.MaxConcurrency = parsed
+	}
+	if raw := fetch("enabled"); raw != "" {
+		val, ok := parseImportEnabled(raw)
+		if !ok {
+			return fmt.Sprintf("第%d行启用状态格式错误: %s", lineNo, raw)
+		}
+		cfg.Enabled = val
+	}
+	if raw := fetch("scheduled_check_enabled"); raw != "" {
+		val, ok := parseImportEnabled(raw)
+		if !ok {
+			return fmt.Sprintf("第%d行定时检测开关格式错误: %s", lineNo, raw)
+		}
+		cfg.ScheduledCheckEnabled = val
+	}
+	return ""
+}
+
 // parseChannelImportRow 解析单行 CSV 记录为渠道配置。
 // 返回三态：
 //   - skip=true,  errMsg=="": 空行,调用方仅累加 Skipped
 //   - skip=true,  errMsg!="": 解析错误,调用方追加 errors 并 Skipped++
 //   - skip=false, channel!=nil: 解析成功,调用方追加 validChannels
-func (s *Server) parseChannelImportRow(
+func parseChannelImportRow(
 	record []string,
 	columnIndex map[string]int,
 	lineNo int,
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drainPollInterval 排空阶段轮询进行中请求数的间隔
+const drainPollInterval = 100 * time.Millisecond
+
+// DrainStatus 排空状态
+type DrainStatus struct {
+	Draining bool `json:"draining"`
+	InFlight int  `json:"in_flight"` // 占用并发槽位的代理请求数
+}
+
+// StartDrain 进入排空模式：新代理请求直接返回 503，进行中请求继续完成。
+// 返回 false 表示此前已处于排空模式。不可撤销，排空后应重启或关闭进程。
+func (s *Server) StartDrain() bool {
+	return s.draining.CompareAndSwap(false, true)
+}
+
+// IsDraining 是否处于排空模式
+func (s *Server) IsDraining() bool {
+	return s.draining.Load()
+}
+
+// InFlightProxyRequests 返回当前占用并发槽位的代理请求数（含优先槽位）
+func (s *Server) InFlightProxyRequests() int {
+	return len(s.concurrencySem) + len(s.prioritySem)
+}
+
+// WaitDrained 等待进行中的代理请求全部完成，ctx 超时返回 ctx.Err()
+func (s *Server) WaitDrained(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for s.InFlightProxyRequests() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// HandleDrain 手动触发排空（滚动发布时先摘流量再关闭）
+// POST /admin/drain
+// 排空后 /health 返回 503，负载均衡器据此摘除实例。
+func (s *Server) HandleDrain(c *gin.Context) {
+	s.StartDrain()
+	RespondJSON(c, http.StatusOK, DrainStatus{Draining: true, InFlight: s.InFlightProxyRequests()})
+}
+
+// HandleDrainStatus 查询排空状态
+// GET /admin/drain
+func (s *Server) HandleDrainStatus(c *gin.Context) {
+	RespondJSON(c, http.StatusOK, DrainStatus{Draining: s.IsDraining(), InFlight: s.InFlightProxyRequests()})
+}
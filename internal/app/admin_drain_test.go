@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrain_RejectsNewProxyRequests(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	if !env.server.StartDrain() {
+		t.Fatal("first StartDrain should return true")
+	}
+	if env.server.StartDrain() {
+		t.Fatal("second StartDrain should return false")
+	}
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+	if got := hits.Load(); got != 0 {
+		t.Fatalf("upstream hits=%d, want 0", got)
+	}
+}
+
+func TestDrain_WaitDrainedWaitsForInFlight(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	// 模拟一个进行中请求占用槽位
+	srv.concurrencySem <- struct{}{}
+	srv.StartDrain()
+
+	shortCtx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if err := srv.WaitDrained(shortCtx); err == nil {
+		t.Fatal("WaitDrained should time out while a request is in flight")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-srv.concurrencySem
+	}()
+	ctx, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel2()
+	if err := srv.WaitDrained(ctx); err != nil {
+		t.Fatalf("WaitDrained failed: %v", err)
+	}
+}
+
+func TestHandleDrain_StatusAndHealth(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/drain", nil))
+	srv.HandleDrainStatus(c)
+	status := mustParseAPIResponse[DrainStatus](t, w.Body.Bytes())
+	if status.Data.Draining {
+		t.Fatal("should not be draining initially")
+	}
+
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/admin/drain", nil))
+	srv.HandleDrain(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	status = mustParseAPIResponse[DrainStatus](t, w.Body.Bytes())
+	if !status.Data.Draining || !srv.IsDraining() {
+		t.Fatal("expected draining after POST /admin/drain")
+	}
+
+	c, w = newTestContext(t, newRequest(http.MethodGet, "/health", nil))
+	srv.HandleHealth(c)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("health while draining: status=%d body=%s", w.Code, w.Body.String())
+	}
+}
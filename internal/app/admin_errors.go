@@ -0,0 +1,71 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorBreakdownDefaultHours 错误分布默认统计窗口
+const errorBreakdownDefaultHours = 24
+
+// HandleErrorBreakdown 按状态码聚合最近 N 小时的错误请求
+// GET /admin/errors/breakdown?hours=24&group_by=channel
+// 仅统计代理请求中非 2xx 的日志；group_by=channel 时按 (状态码, 渠道) 分组。
+func (s *Server) HandleErrorBreakdown(c *gin.Context) {
+	hours := errorBreakdownDefaultHours
+	if raw := strings.TrimSpace(c.Query("hours")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > logExportMaxHours {
+			RespondErrorMsg(c, http.StatusBadRequest, "hours must be between 1 and "+strconv.Itoa(logExportMaxHours))
+			return
+		}
+		hours = n
+	}
+
+	var byChannel bool
+	switch strings.TrimSpace(c.Query("group_by")) {
+	case "", "status_code":
+	case "channel":
+		byChannel = true
+	default:
+		RespondErrorMsg(c, http.StatusBadRequest, "group_by must be status_code or channel")
+		return
+	}
+
+	ctx := c.Request.Context()
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	items, err := s.store.GetErrorBreakdown(ctx, since, byChannel)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if byChannel && len(items) > 0 {
+		configs, err := s.store.ListConfigs(ctx)
+		if err != nil {
+			log.Printf("[WARN] 查询渠道名称失败: %v", err)
+		}
+		names := make(map[int64]string, len(configs))
+		for _, cfg := range configs {
+			names[cfg.ID] = cfg.Name
+		}
+		for i := range items {
+			items[i].ChannelName = names[items[i].ChannelID]
+		}
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Count
+	}
+	RespondJSON(c, http.StatusOK, ErrorBreakdownResponse{
+		Hours: hours,
+		Total: total,
+		Items: items,
+	})
+}
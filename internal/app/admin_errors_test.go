@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestHandleErrorBreakdown_GroupsByChannelWithNames(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "breakdown-channel",
+		URL:          "https://api.example.com",
+		ModelEntries: []model.ModelEntry{{Model: "m"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: cfg.ID, StatusCode: 401, Message: "auth"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: cfg.ID, StatusCode: 401, Message: "auth"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: cfg.ID, StatusCode: 200, Message: "ok"},
+	}); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/errors/breakdown?hours=1&group_by=channel", nil))
+	server.HandleErrorBreakdown(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data ErrorBreakdownResponse `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	if resp.Data.Hours != 1 || resp.Data.Total != 2 || len(resp.Data.Items) != 1 {
+		t.Fatalf("unexpected response: %+v", resp.Data)
+	}
+	item := resp.Data.Items[0]
+	if item.StatusCode != 401 || item.ChannelID != cfg.ID || item.ChannelName != "breakdown-channel" || item.Count != 2 {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestHandleErrorBreakdown_RejectsInvalidParams(t *testing.T) {
+	server, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	for _, query := range []string{"hours=0", "hours=abc", "group_by=model"} {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/errors/breakdown?"+query, nil))
+		server.HandleErrorBreakdown(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d, want 400", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestHandleErrors_KeysetCursorWalksAllLogs(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().Add(-time.Minute)
+	entries := make([]*model.LogEntry, 0, 5)
+	for i := range 5 {
+		// 两两同一毫秒，覆盖 time 相同、按 id 决胜的场景
+		entries = append(entries, &model.LogEntry{
+			Time:       model.JSONTime{Time: now.Add(time.Duration(i/2) * time.Millisecond)},
+			Model:      "cursor-model",
+			StatusCode: 200,
+			Message:    fmt.Sprintf("log-%d", i),
+		})
+	}
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+
+	fetch := func(query string) CursorResponse[[]*model.LogEntry, LogPageCursor] {
+		t.Helper()
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/logs?range=today&limit=2"+query, nil))
+		server.HandleErrors(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		var resp CursorResponse[[]*model.LogEntry, LogPageCursor]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	seen := map[string]bool{}
+	resp := fetch(fmt.Sprintf("&before_time=%d", time.Now().UnixMilli()))
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("cursor did not terminate")
+		}
+		for _, e := range resp.Data {
+			if seen[e.Message] {
+				t.Fatalf("duplicate log %q across pages", e.Message)
+			}
+			seen[e.Message] = true
+		}
+		if resp.NextCursor == nil {
+			break
+		}
+		resp = fetch(fmt.Sprintf("&before_time=%d&before_id=%d", resp.NextCursor.BeforeTime, resp.NextCursor.BeforeID))
+	}
+	if len(seen) != len(entries) {
+		t.Fatalf("walked %d logs, want %d", len(seen), len(entries))
+	}
+
+	t.Run("before_id only resolves time from the log", func(t *testing.T) {
+		logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 1, 0, nil)
+		if err != nil || len(logs) != 1 {
+			t.Fatalf("ListLogs: %v (%d)", err, len(logs))
+		}
+		resp := fetch(fmt.Sprintf("&before_id=%d", logs[0].ID))
+		if got := len(resp.Data); got != 2 {
+			t.Fatalf("page size=%d, want 2", got)
+		}
+		for _, e := range resp.Data {
+			if e.ID == logs[0].ID {
+				t.Fatal("cursor row must be excluded")
+			}
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, q := range []string{"&before_time=abc", "&before_id=-1", "&before_id=999999"} {
+			c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/logs?range=today"+q, nil))
+			server.HandleErrors(c)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("%s: status=%d, want 400", q, w.Code)
+			}
+		}
+	})
+}
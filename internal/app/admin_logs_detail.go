@@ -0,0 +1,35 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetLog 查询单条日志
+// GET /admin/logs/:id?full=true
+// full=true 时附带 error_detail（错误日志中被 message 截断的完整上游错误，最多8KB）。
+// API Key 在写入时已脱敏，这里无法也不会返回明文。
+func (s *Server) HandleGetLog(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		RespondErrorMsg(c, http.StatusBadRequest, "invalid log id")
+		return
+	}
+	full, _ := strconv.ParseBool(c.DefaultQuery("full", "false"))
+
+	entry, err := s.store.GetLog(c.Request.Context(), id, full)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondErrorMsg(c, http.StatusNotFound, "log not found")
+			return
+		}
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	RespondJSON(c, http.StatusOK, entry)
+}
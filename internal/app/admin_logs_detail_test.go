@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleGetLog(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name: "log-detail", URL: "https://api.example.com", ChannelType: "openai", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	detail := "upstream status 502: " + strings.Repeat("x", 1000)
+	if err := srv.store.AddLog(ctx, &model.LogEntry{
+		Time: model.JSONTime{Time: time.Now()}, Model: "gpt-4o", ChannelID: cfg.ID, StatusCode: 502,
+		Message: detail[:512], ErrorDetail: detail,
+	}); err != nil {
+		t.Fatalf("add log: %v", err)
+	}
+	logs, err := srv.store.ListLogs(ctx, time.Now().Add(-time.Hour), 1, 0, nil)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("list logs: %v", err)
+	}
+	id := logs[0].ID
+
+	get := func(path string, idParam string) (int, []byte) {
+		c, w := newTestContext(t, newRequest(http.MethodGet, path, nil))
+		c.Params = gin.Params{{Key: "id", Value: idParam}}
+		srv.HandleGetLog(c)
+		return w.Code, w.Body.Bytes()
+	}
+
+	idStr := strconv.FormatInt(id, 10)
+	code, body := get("/admin/logs/"+idStr, idStr)
+	if code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", code, body)
+	}
+	resp := mustParseAPIResponse[model.LogEntry](t, body)
+	if resp.Data.ErrorDetail != "" || resp.Data.ChannelName != "log-detail" {
+		t.Fatalf("unexpected entry without full=true: %+v", resp.Data)
+	}
+
+	code, body = get("/admin/logs/"+idStr+"?full=true", idStr)
+	if code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", code, body)
+	}
+	resp = mustParseAPIResponse[model.LogEntry](t, body)
+	if resp.Data.ErrorDetail != detail {
+		t.Fatalf("error_detail len=%d, want %d", len(resp.Data.ErrorDetail), len(detail))
+	}
+
+	if code, _ := get("/admin/logs/999999", "999999"); code != http.StatusNotFound {
+		t.Fatalf("missing log status=%d, want 404", code)
+	}
+	if code, _ := get("/admin/logs/abc", "abc"); code != http.StatusBadRequest {
+		t.Fatalf("invalid id status=%d, want 400", code)
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ccLoad/internal/model"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+)
+
+// logExportPageSize 单页游标查询行数：每页查询后立即写出并 Flush，内存占用与总行数无关
+const logExportPageSize = 1000
+
+// logExportMaxHours hours 参数上限（90天），防止误传超大范围拖垮数据库
+const logExportMaxHours = 90 * 24
+
+var logExportCSVHeader = []string{
+	"id", "time", "model", "actual_model", "log_source", "channel_id", "channel_name", "status_code", "message",
+	"duration", "is_streaming", "first_byte_time", "api_key_used", "auth_token_id", "client_ip", "base_url",
+	"service_tier", "thinking_effort", "input_tokens", "output_tokens", "reasoning_tokens",
+	"cache_read_input_tokens", "cache_creation_input_tokens", "cost", "cost_multiplier", "request_id",
+	"attempt_number", "channel_attempt", "path",
+}
+
+// HandleExportLogs 流式导出日志（CSV/NDJSON）
+// GET /admin/logs/export?format=csv&hours=168&channel_id=1&model=xxx
+// 过滤参数与 /admin/logs 一致（BuildLogFilter）；hours 优先于 range。
+// 按 time 键集游标分页读取并逐页写出，不把全部结果加载进内存。
+func (s *Server) HandleExportLogs(c *gin.Context) {
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "csv")))
+	if format != "csv" && format != "ndjson" {
+		RespondErrorMsg(c, http.StatusBadRequest, "format must be csv or ndjson")
+		return
+	}
+
+	since, until, err := parseLogExportTimeRange(c, time.Now())
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	lf := BuildLogFilter(c)
+	ctx := c.Request.Context()
+
+	// 首页在写响应头之前查询：失败时仍可返回标准 JSON 错误
+	page, err := s.store.ListLogsByCursor(ctx, since, until, nil, logExportPageSize, &lf)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("logs-%s.%s", time.Now().Format("20060102-150405"), format)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+
+	var writeRow func(*model.LogEntry) error
+	var flush func() error
+	if format == "csv" {
+		// 添加 UTF-8 BOM，兼容 Excel 等工具（与渠道导出一致）
+		if _, err := c.Writer.WriteString("\ufeff"); err != nil {
+			return
+		}
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write(logExportCSVHeader); err != nil {
+			return
+		}
+		writeRow = func(e *model.LogEntry) error { return writer.Write(logExportCSVRecord(e)) }
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	} else {
+		writeRow = func(e *model.LogEntry) error {
+			line, err := sonic.Marshal(e)
+			if err != nil {
+				return err
+			}
+			line = append(line, '\n')
+			_, err = c.Writer.Write(line)
+			return err
+		}
+		flush = func() error { return nil }
+	}
+
+	for {
+		for _, e := range page {
+			if err := writeRow(e); err != nil {
+				log.Printf("[WARN] 日志导出写入失败: %v", err)
+				return
+			}
+		}
+		if err := flush(); err != nil {
+			log.Printf("[WARN] 日志导出写入失败: %v", err)
+			return
+		}
+		c.Writer.Flush()
+
+		if len(page) < logExportPageSize {
+			return
+		}
+		last := page[len(page)-1]
+		cursor := &model.LogCursor{TimeMs: last.Time.UnixMilli(), ID: last.ID}
+		page, err = s.store.ListLogsByCursor(ctx, since, until, cursor, logExportPageSize, &lf)
+		if err != nil {
+			// 响应头已发送，只能截断输出并记录日志
+			log.Printf("[ERROR] 日志导出分页查询失败: %v", err)
+			return
+		}
+	}
+}
+
+// parseLogExportTimeRange 解析导出时间范围：hours=N 表示最近 N 小时，否则沿用 range/start_time/end_time
+func parseLogExportTimeRange(c *gin.Context, now time.Time) (since, until time.Time, err error) {
+	if raw := strings.TrimSpace(c.Query("hours")); raw != "" {
+		hours, convErr := strconv.Atoi(raw)
+		if convErr != nil || hours <= 0 || hours > logExportMaxHours {
+			return time.Time{}, time.Time{}, fmt.Errorf("hours must be between 1 and %d", logExportMaxHours)
+		}
+		return now.Add(-time.Duration(hours) * time.Hour), now, nil
+	}
+	since, until = ParsePaginationParams(c).GetTimeRangeAt(now)
+	return since, until, nil
+}
+
+// logExportCSVRecord 将日志条目转换为 CSV 行（列顺序与 logExportCSVHeader 对齐）
+// APIKeyUsed 在存储层读取时已脱敏，这里直接输出。
+func logExportCSVRecord(e *model.LogEntry) []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		e.Time.Format(time.RFC3339),
+		e.Model,
+		e.ActualModel,
+		e.LogSource,
+		strconv.FormatInt(e.ChannelID, 10),
+		e.ChannelName,
+		strconv.Itoa(e.StatusCode),
+		e.Message,
+		strconv.FormatFloat(e.Duration, 'f', -1, 64),
+		strconv.FormatBool(e.IsStreaming),
+		strconv.FormatFloat(e.FirstByteTime, 'f', -1, 64),
+		e.APIKeyUsed,
+		strconv.FormatInt(e.AuthTokenID, 10),
+		e.ClientIP,
+		e.BaseURL,
+		e.ServiceTier,
+		e.ThinkingEffort,
+		strconv.Itoa(e.InputTokens),
+		strconv.Itoa(e.OutputTokens),
+		strconv.Itoa(e.ReasoningTokens),
+		strconv.Itoa(e.CacheReadInputTokens),
+		strconv.Itoa(e.CacheCreationInputTokens),
+		strconv.FormatFloat(e.Cost, 'f', -1, 64),
+		strconv.FormatFloat(e.CostMultiplier, 'f', -1, 64),
+		e.RequestID,
+		strconv.Itoa(e.AttemptNumber),
+		strconv.Itoa(e.ChannelAttempt),
+		e.Path,
+	}
+}
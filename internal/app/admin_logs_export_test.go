@@ -0,0 +1,128 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func seedExportLogs(t *testing.T, srv *Server, n int) int64 {
+	t.Helper()
+	ctx := context.Background()
+	cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name:         "export-channel",
+		URL:          "http://export.example.com",
+		Priority:     1,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	now := time.Now().Add(-time.Minute)
+	entries := make([]*model.LogEntry, 0, n)
+	for i := range n {
+		entries = append(entries, &model.LogEntry{
+			Time:       model.JSONTime{Time: now.Add(-time.Duration(i) * time.Millisecond)},
+			Model:      "gpt-4",
+			ChannelID:  cfg.ID,
+			StatusCode: http.StatusOK,
+			Message:    "ok, \"quoted\"",
+			APIKeyUsed: "sk-secret-key-1234567890",
+		})
+	}
+	if err := srv.store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+	return cfg.ID
+}
+
+func TestHandleExportLogs_CSVStreamsAllPages(t *testing.T) {
+	srv := newInMemoryServer(t)
+	total := logExportPageSize + 5
+	seedExportLogs(t, srv, total)
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/logs/export?format=csv&hours=1", nil))
+	srv.HandleExportLogs(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("content-type=%q", ct)
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(w.Body.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != total+1 {
+		t.Fatalf("rows=%d, want %d", len(records)-1, total)
+	}
+	seen := make(map[string]bool, total)
+	for _, rec := range records[1:] {
+		if seen[rec[0]] {
+			t.Fatalf("duplicate id %s", rec[0])
+		}
+		seen[rec[0]] = true
+		if rec[12] == "sk-secret-key-1234567890" {
+			t.Fatalf("api key exported unmasked")
+		}
+		if rec[6] != "export-channel" {
+			t.Fatalf("channel_name=%q", rec[6])
+		}
+	}
+}
+
+func TestHandleExportLogs_NDJSONAppliesFilter(t *testing.T) {
+	srv := newInMemoryServer(t)
+	channelID := seedExportLogs(t, srv, 3)
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/logs/export?format=ndjson&hours=1&model=other", nil))
+	srv.HandleExportLogs(c)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "" {
+		t.Fatalf("filtered export: status=%d body=%q", w.Code, w.Body.String())
+	}
+
+	c, w = newTestContext(t, newRequest(http.MethodGet, "/admin/logs/export?format=ndjson&hours=1&model=gpt-4", nil))
+	srv.HandleExportLogs(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	lines := 0
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for scanner.Scan() {
+		var entry model.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("unmarshal line: %v", err)
+		}
+		if entry.ChannelID != channelID || entry.APIKeyUsed == "sk-secret-key-1234567890" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Fatalf("lines=%d, want 3", lines)
+	}
+}
+
+func TestHandleExportLogs_RejectsInvalidParams(t *testing.T) {
+	srv := newInMemoryServer(t)
+	for _, target := range []string{
+		"/admin/logs/export?format=xml",
+		"/admin/logs/export?hours=0",
+		"/admin/logs/export?hours=abc",
+	} {
+		c, w := newTestContext(t, newRequest(http.MethodGet, target, nil))
+		srv.HandleExportLogs(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d, want 400", target, w.Code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceAllowedRoutes 维护模式下仍允许的管理端写操作（切换维护模式本身与排空）
+var maintenanceAllowedRoutes = map[string]bool{
+	"/admin/maintenance/enable":  true,
+	"/admin/maintenance/disable": true,
+	"/admin/drain":               true,
+}
+
+// MaintenanceStatus 维护模式状态
+type MaintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// IsMaintenance 是否处于维护（只读）模式
+func (s *Server) IsMaintenance() bool {
+	return s.maintenance.Load()
+}
+
+// SetMaintenance 切换维护模式并持久化到系统设置（自重启后保持）
+// 持久化失败时不改变内存状态
+func (s *Server) SetMaintenance(ctx context.Context, enabled bool) error {
+	if err := s.configService.UpdateSetting(ctx, "maintenance_mode", strconv.FormatBool(enabled)); err != nil {
+		return err
+	}
+	if s.maintenance.Swap(enabled) != enabled {
+		log.Printf("[INFO] 维护模式已%s", map[bool]string{true: "开启", false: "关闭"}[enabled])
+	}
+	return nil
+}
+
+// maintenanceGuard 维护模式下拒绝管理端写操作（代理请求不经过此中间件，照常服务）
+func (s *Server) maintenanceGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.IsMaintenance() {
+			c.Next()
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		path := c.FullPath()
+		if maintenanceAllowedRoutes[path] || adminReadOnlyRoutes[path] {
+			c.Next()
+			return
+		}
+		RespondErrorMsg(c, http.StatusServiceUnavailable, "maintenance mode: admin changes are disabled")
+		c.Abort()
+	}
+}
+
+// HandleMaintenanceStatus 查询维护模式
+// GET /admin/maintenance
+func (s *Server) HandleMaintenanceStatus(c *gin.Context) {
+	RespondJSON(c, http.StatusOK, MaintenanceStatus{Enabled: s.IsMaintenance()})
+}
+
+// HandleEnableMaintenance 开启维护模式
+// POST /admin/maintenance/enable
+func (s *Server) HandleEnableMaintenance(c *gin.Context) {
+	s.handleSetMaintenance(c, true)
+}
+
+// HandleDisableMaintenance 关闭维护模式
+// POST /admin/maintenance/disable
+func (s *Server) HandleDisableMaintenance(c *gin.Context) {
+	s.handleSetMaintenance(c, false)
+}
+
+func (s *Server) handleSetMaintenance(c *gin.Context, enabled bool) {
+	if err := s.SetMaintenance(c.Request.Context(), enabled); err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	RespondJSON(c, http.StatusOK, MaintenanceStatus{Enabled: enabled})
+}
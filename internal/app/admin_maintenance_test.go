@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newMaintenanceTestEngine(srv *Server) *gin.Engine {
+	r := gin.New()
+	admin := r.Group("/admin")
+	admin.Use(srv.maintenanceGuard())
+	admin.GET("/channels", srv.HandleChannels)
+	admin.POST("/channels", srv.HandleChannels)
+	admin.POST("/channels/:id/test", func(c *gin.Context) { RespondJSON(c, http.StatusOK, gin.H{"ok": true}) })
+	admin.GET("/maintenance", srv.HandleMaintenanceStatus)
+	admin.POST("/maintenance/enable", srv.HandleEnableMaintenance)
+	admin.POST("/maintenance/disable", srv.HandleDisableMaintenance)
+	return r
+}
+
+func TestMaintenance_BlocksAdminMutationsUntilDisabled(t *testing.T) {
+	srv := newInMemoryServer(t)
+	r := newMaintenanceTestEngine(srv)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	createBody := `{"name":"m-ch","url":"https://a.example.com","api_key":"sk-1","models":[{"model":"gpt-4o"}]}`
+
+	if w := serve(http.MethodPost, "/admin/maintenance/enable", ""); w.Code != http.StatusOK {
+		t.Fatalf("enable status=%d body=%s", w.Code, w.Body.String())
+	}
+	if !srv.IsMaintenance() {
+		t.Fatal("expected maintenance mode on")
+	}
+	setting, err := srv.store.GetSetting(context.Background(), "maintenance_mode")
+	if err != nil || setting.Value != "true" {
+		t.Fatalf("maintenance_mode should be persisted, got %+v err=%v", setting, err)
+	}
+
+	if w := serve(http.MethodPost, "/admin/channels", createBody); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("create during maintenance: expected 503, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/admin/channels", ""); w.Code != http.StatusOK {
+		t.Fatalf("reads must stay available, got %d", w.Code)
+	}
+	if w := serve(http.MethodPost, "/admin/channels/1/test", "{}"); w.Code != http.StatusOK {
+		t.Fatalf("read-only POST routes must stay available, got %d", w.Code)
+	}
+
+	w := serve(http.MethodGet, "/admin/maintenance", "")
+	var status MaintenanceStatus
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &status)
+	if !status.Enabled {
+		t.Fatalf("status should report enabled: %s", w.Body.String())
+	}
+
+	if w := serve(http.MethodPost, "/admin/maintenance/disable", ""); w.Code != http.StatusOK {
+		t.Fatalf("disable status=%d body=%s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, "/admin/channels", createBody); w.Code != http.StatusCreated {
+		t.Fatalf("create after maintenance: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestMaintenance_RestoredFromSettingsOnStartup(t *testing.T) {
+	store, err := storage.CreateSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("CreateSQLiteStore failed: %v", err)
+	}
+	if err := store.UpdateSetting(context.Background(), "maintenance_mode", "true"); err != nil {
+		t.Fatalf("UpdateSetting failed: %v", err)
+	}
+
+	srv := NewServer(store)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+	if !srv.IsMaintenance() {
+		t.Fatal("maintenance mode should survive a restart")
+	}
+}
+
+func TestMaintenance_ProxyKeepsServing(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+	env.server.maintenance.Store(true)
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("proxy should keep serving in maintenance mode, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+// parseSSEEvents 解析 "event: x\ndata: y\n\n" 格式的事件流
+func parseSSEEvents(t *testing.T, body string) (names []string, payloads [][]model.MetricPoint) {
+	t.Helper()
+	for block := range strings.SplitSeq(strings.TrimSpace(body), "\n\n") {
+		if block == "" {
+			continue
+		}
+		var name, data string
+		for line := range strings.SplitSeq(block, "\n") {
+			if v, ok := strings.CutPrefix(line, "event: "); ok {
+				name = v
+			}
+			if v, ok := strings.CutPrefix(line, "data: "); ok {
+				data = v
+			}
+		}
+		var pts []model.MetricPoint
+		if err := json.Unmarshal([]byte(data), &pts); err != nil {
+			t.Fatalf("unmarshal %s event: %v (%s)", name, err, data)
+		}
+		names = append(names, name)
+		payloads = append(payloads, pts)
+	}
+	return names, payloads
+}
+
+func TestHandleMetricsStream_HistoricalRangeSendsSnapshotOnly(t *testing.T) {
+	server, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/metrics/stream?range=yesterday&bucket_min=60&snapshot=false", nil))
+	server.HandleMetricsStream(c)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type=%q", ct)
+	}
+	names, payloads := parseSSEEvents(t, w.Body.String())
+	if len(names) != 1 || names[0] != "snapshot" || len(payloads[0]) < 24 {
+		t.Fatalf("expected one full-day snapshot, got %v", names)
+	}
+}
+
+func TestHandleMetricsStream_LivePushesLatestBuckets(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg, err := store.CreateConfig(ctx, &model.Config{Name: "stream-ch", URL: "https://a.example.com", Priority: 1, Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
+	defer cancel()
+	req := newRequest(http.MethodGet, "/admin/metrics/stream?range=today&bucket_min=1&interval_sec=1", nil).WithContext(reqCtx)
+	c, w := newTestContext(t, req)
+
+	// snapshot 之后写入的日志应出现在 update 事件中
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = store.AddLog(ctx, &model.LogEntry{Time: model.JSONTime{Time: time.Now()}, Model: "gpt-4", ChannelID: cfg.ID, StatusCode: 200, LogSource: model.LogSourceProxy})
+	}()
+	server.HandleMetricsStream(c) // 请求 ctx 超时后返回
+
+	names, payloads := parseSSEEvents(t, w.Body.String())
+	if len(names) < 2 || names[0] != "snapshot" || names[1] != "update" {
+		t.Fatalf("expected snapshot followed by update, got %v", names)
+	}
+	update := payloads[1]
+	if len(update) == 0 || len(update) > 3 {
+		t.Fatalf("update should only cover the latest buckets, got %d points", len(update))
+	}
+	success := 0
+	for _, p := range update {
+		success += p.Success
+	}
+	if success != 1 {
+		t.Fatalf("update success=%d, want 1 (%+v)", success, update)
+	}
+}
+
+func TestLatestMetricBucketsStart(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 15, 10, 7, 30, 0, time.UTC)
+	if got := latestMetricBucketsStart(now, 5*time.Minute); !got.Equal(time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("5m buckets: got %v", got)
+	}
+	if got := latestMetricBucketsStart(now, 0); !got.Equal(time.Date(2026, 10, 15, 10, 6, 0, 0, time.UTC)) {
+		t.Fatalf("min bucket: got %v", got)
+	}
+}
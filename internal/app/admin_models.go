@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -73,45 +74,135 @@ type BatchRefreshModelsItem struct {
 //
 // 设计模式: 适配器模式(Adapter Pattern) + 策略模式(Strategy Pattern)
 func (s *Server) HandleFetchModels(c *gin.Context) {
-	// 1. 解析路径参数
+	// 1. 查询渠道配置与第一个已启用的 API Key（用于调用 Models API）
+	channel, apiKey, ok := s.loadChannelForModelsFetch(c)
+	if !ok {
+		return
+	}
+
+	// 2. 根据渠道配置执行模型抓取（支持query参数覆盖渠道类型）
+	channelType := c.Query("channel_type")
+	if channelType == "" {
+		channelType = channel.ChannelType
+	}
+	response, err := s.fetchModelsWithURLFallback(c.Request.Context(), channel.ID, channel.GetURLs(), channelType, apiKey)
+	if err != nil {
+		// [INFO] 修复：统一返回200，通过success字段区分成功/失败（上游错误是预期内的）
+		RespondErrorMsg(c, http.StatusOK, err.Error())
+		return
+	}
+
+	RespondJSON(c, http.StatusOK, response)
+}
+
+// RemoteModelsResponse 上游实际提供的模型列表与渠道已配置模型的对比结果
+type RemoteModelsResponse struct {
+	ChannelID        int64    `json:"channel_id"`
+	ChannelType      string   `json:"channel_type"`
+	RemoteModels     []string `json:"remote_models"`     // 上游 Models API 返回的模型
+	ConfiguredModels []string `json:"configured_models"` // 渠道配置的上游模型名（重定向后）
+	MissingUpstream  []string `json:"missing_upstream"`  // 已配置但上游未提供（可能拼写错误或已下线）
+	NotConfigured    []string `json:"not_configured"`    // 上游提供但渠道未配置（如新发布的模型）
+}
+
+// HandleRemoteModels 调用上游 Models API 并与渠道已配置模型对比
+// 路由: GET /admin/channels/:id/remote-models
+// 仅支持提供模型列表接口的渠道类型（OpenAI/Gemini/Anthropic/Codex），其余类型没有可对比的上游数据
+func (s *Server) HandleRemoteModels(c *gin.Context) {
+	channel, apiKey, ok := s.loadChannelForModelsFetch(c)
+	if !ok {
+		return
+	}
+
+	channelType := channel.GetChannelType()
+	if determineSource(channelType) != "api" {
+		RespondErrorMsg(c, http.StatusBadRequest, fmt.Sprintf("渠道类型:%s 不提供模型列表接口", channelType))
+		return
+	}
+
+	fetched, err := s.fetchModelsWithURLFallback(c.Request.Context(), channel.ID, channel.GetURLs(), channelType, apiKey)
+	if err != nil {
+		// 与 HandleFetchModels 一致：上游错误属于预期内情况，返回200并通过success字段区分
+		RespondErrorMsg(c, http.StatusOK, err.Error())
+		return
+	}
+
+	remote := make([]string, 0, len(fetched.Models))
+	for _, entry := range fetched.Models {
+		remote = append(remote, entry.Model)
+	}
+	RespondJSON(c, http.StatusOK, diffRemoteModels(channel, remote))
+}
+
+// diffRemoteModels 以上游模型名（存在重定向时取 redirect_model）对比，结果保持输入顺序并去重
+func diffRemoteModels(channel *model.Config, remote []string) *RemoteModelsResponse {
+	configured := make([]string, 0, len(channel.ModelEntries))
+	configuredSet := make(map[string]struct{}, len(channel.ModelEntries))
+	for _, entry := range channel.ModelEntries {
+		name := entry.RedirectModel
+		if name == "" {
+			name = entry.Model
+		}
+		if _, dup := configuredSet[name]; dup || name == "" {
+			continue
+		}
+		configuredSet[name] = struct{}{}
+		configured = append(configured, name)
+	}
+
+	remoteSet := make(map[string]struct{}, len(remote))
+	resp := &RemoteModelsResponse{
+		ChannelID:        channel.ID,
+		ChannelType:      channel.GetChannelType(),
+		RemoteModels:     make([]string, 0, len(remote)),
+		ConfiguredModels: configured,
+		MissingUpstream:  []string{},
+		NotConfigured:    []string{},
+	}
+	for _, name := range remote {
+		if _, dup := remoteSet[name]; dup || name == "" {
+			continue
+		}
+		remoteSet[name] = struct{}{}
+		resp.RemoteModels = append(resp.RemoteModels, name)
+		if _, ok := configuredSet[name]; !ok {
+			resp.NotConfigured = append(resp.NotConfigured, name)
+		}
+	}
+	for _, name := range configured {
+		if _, ok := remoteSet[name]; !ok {
+			resp.MissingUpstream = append(resp.MissingUpstream, name)
+		}
+	}
+	return resp
+}
+
+// loadChannelForModelsFetch 解析路径中的渠道ID，返回渠道配置与第一个已启用的 API Key
+// 失败时已写入错误响应，调用方直接返回
+func (s *Server) loadChannelForModelsFetch(c *gin.Context) (*model.Config, string, bool) {
 	channelID, err := ParseInt64Param(c, "id")
 	if err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, "无效的渠道ID")
-		return
+		return nil, "", false
 	}
 
-	// 2. 查询渠道配置
 	channel, err := s.channelCache.GetConfig(c.Request.Context(), channelID)
 	if err != nil {
 		RespondErrorMsg(c, http.StatusNotFound, "渠道不存在")
-		return
+		return nil, "", false
 	}
 
-	// 3. 获取第一个已启用的 API Key（用于调用 Models API）
 	keys, err := s.store.GetAPIKeys(c.Request.Context(), channelID)
 	if err != nil {
 		RespondErrorMsg(c, http.StatusBadRequest, "该渠道没有可用的API Key")
-		return
+		return nil, "", false
 	}
 	apiKey := firstEnabledAPIKey(keys)
 	if apiKey == "" {
 		RespondErrorMsg(c, http.StatusBadRequest, "该渠道没有已启用的API Key")
-		return
+		return nil, "", false
 	}
-
-	// 4. 根据渠道配置执行模型抓取（支持query参数覆盖渠道类型）
-	channelType := c.Query("channel_type")
-	if channelType == "" {
-		channelType = channel.ChannelType
-	}
-	response, err := s.fetchModelsWithURLFallback(c.Request.Context(), channel.ID, channel.GetURLs(), channelType, apiKey)
-	if err != nil {
-		// [INFO] 修复：统一返回200，通过success字段区分成功/失败（上游错误是预期内的）
-		RespondErrorMsg(c, http.StatusOK, err.Error())
-		return
-	}
-
-	RespondJSON(c, http.StatusOK, response)
+	return channel, apiKey, true
 }
 
 // HandleFetchModelsPreview 支持未保存的渠道配置直接测试模型列表
@@ -300,7 +391,12 @@ func firstEnabledAPIKey(keys []*model.APIKey) string {
 		if key == nil || key.Disabled {
 			continue
 		}
-		if apiKey := strings.TrimSpace(key.APIKey); apiKey != "" {
+		apiKey, err := resolveAPIKey(strings.TrimSpace(key.APIKey))
+		if err != nil {
+			log.Printf("[WARN] 解析渠道 Key 文件失败: %v", err)
+			continue
+		}
+		if apiKey != "" {
 			return apiKey
 		}
 	}
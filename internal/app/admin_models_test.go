@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 	"time"
@@ -759,3 +760,78 @@ func TestAdminModels_HandleBatchRefreshModels(t *testing.T) {
 		}
 	})
 }
+
+func TestAdminModels_HandleRemoteModels(t *testing.T) {
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"gpt-5"},{"id":"gpt-4o"}]}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+	server.channelCache = storage.NewChannelCache(store, time.Minute)
+
+	ctx := context.Background()
+	create := func(name, channelType string, entries []model.ModelEntry) *model.Config {
+		t.Helper()
+		cfg, err := store.CreateConfig(ctx, &model.Config{
+			Name: name, URL: upstream.URL, Priority: 1, ChannelType: channelType, ModelEntries: entries, Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("CreateConfig failed: %v", err)
+		}
+		if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+			{ChannelID: cfg.ID, KeyIndex: 0, APIKey: "sk-test", KeyStrategy: model.KeyStrategySequential},
+		}); err != nil {
+			t.Fatalf("CreateAPIKeysBatch failed: %v", err)
+		}
+		return cfg
+	}
+
+	openai := create("openai", "openai", []model.ModelEntry{
+		{Model: "gpt-4o"},
+		{Model: "fast", RedirectModel: "gpt-4o-mnii"}, // 拼写错误的重定向目标
+	})
+	other := create("other", "kimi", []model.ModelEntry{{Model: "m1"}})
+
+	call := func(id int64) *httptest.ResponseRecorder {
+		c, w := newTestContext(t, newRequest(http.MethodGet, fmt.Sprintf("/admin/channels/%d/remote-models", id), nil))
+		c.Params = gin.Params{{Key: "id", Value: fmt.Sprint(id)}}
+		server.HandleRemoteModels(c)
+		return w
+	}
+
+	w := call(openai.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Success bool                 `json:"success"`
+		Data    RemoteModelsResponse `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	if !resp.Success {
+		t.Fatalf("unexpected resp: %s", w.Body.String())
+	}
+	if !reflect.DeepEqual(resp.Data.RemoteModels, []string{"gpt-4o", "gpt-5"}) {
+		t.Fatalf("remote_models=%v", resp.Data.RemoteModels)
+	}
+	if !reflect.DeepEqual(resp.Data.MissingUpstream, []string{"gpt-4o-mnii"}) {
+		t.Fatalf("missing_upstream=%v", resp.Data.MissingUpstream)
+	}
+	if !reflect.DeepEqual(resp.Data.NotConfigured, []string{"gpt-5"}) {
+		t.Fatalf("not_configured=%v", resp.Data.NotConfigured)
+	}
+
+	if w := call(other.ID); w.Code != http.StatusBadRequest {
+		t.Fatalf("channel type without models API: status=%d, want 400", w.Code)
+	}
+	if w := call(9999); w.Code != http.StatusNotFound {
+		t.Fatalf("missing channel: status=%d, want 404", w.Code)
+	}
+}
@@ -252,6 +252,10 @@ func validateSettingValue(key, valueType, value string) error {
 			if value != "edit" && value != "navigate" {
 				return fmt.Errorf("log_channel_click_action must be edit or navigate")
 			}
+		case "model_monthly_budgets":
+			if _, err := parseModelBudgets(value); err != nil {
+				return fmt.Errorf("model_monthly_budgets %w", err)
+			}
 		}
 
 	default:
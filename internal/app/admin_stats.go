@@ -2,9 +2,12 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"ccLoad/internal/util"
 	"ccLoad/internal/version"
 
+	"github.com/bytedance/sonic"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,28 +24,97 @@ import (
 
 // HandleErrors 获取日志列表
 // GET /admin/logs?range=today&limit=100&offset=0
+// GET /admin/logs?range=today&limit=100&before_time=1700000000000&before_id=123（键集分页）
+// 传入 before_time/before_id 时改用键集游标：不统计总数、忽略 offset，响应附带 next_cursor。
+// 深分页（无限滚动）推荐使用游标；OFFSET 模式保留用于页码跳转兼容。
 func (s *Server) HandleErrors(c *gin.Context) {
 	params := ParsePaginationParams(c)
 	lf := BuildLogFilter(c)
 	since, until := params.GetTimeRange()
+	ctx := c.Request.Context()
+
+	cursor, useCursor, err := s.parseLogPageCursor(ctx, c, &lf)
+	if err != nil {
+		RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	logs, total, err := s.store.ListLogsRangeWithCount(c.Request.Context(), since, until, params.Limit, params.Offset, &lf)
+	var (
+		logs  []*model.LogEntry
+		total int
+	)
+	if useCursor {
+		logs, err = s.store.ListLogsByCursor(ctx, since, until, cursor, params.Limit, &lf)
+	} else {
+		logs, total, err = s.store.ListLogsRangeWithCount(ctx, since, until, params.Limit, params.Offset, &lf)
+	}
 	if err != nil {
 		RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	var data any = logs
 	if isAPITokenWebRequest(c) {
-		channels, err := s.tokenLogChannels(c.Request.Context(), logs)
+		channels, err := s.tokenLogChannels(ctx, logs)
 		if err != nil {
 			log.Printf("[ERROR] 加载 API Token 日志脱敏元数据失败: %v", err)
 			RespondErrorMsg(c, http.StatusInternalServerError, "读取日志脱敏元数据失败")
 			return
 		}
-		RespondJSONWithCount(c, http.StatusOK, projectTokenLogs(logs, channels), total)
+		data = projectTokenLogs(logs, channels)
+	}
+
+	if useCursor {
+		var next *LogPageCursor
+		if len(logs) == params.Limit {
+			last := logs[len(logs)-1]
+			next = &LogPageCursor{BeforeTime: last.Time.UnixMilli(), BeforeID: last.ID}
+		}
+		RespondCursorPage(c, http.StatusOK, data, len(logs), next)
 		return
 	}
-	RespondJSONWithCount(c, http.StatusOK, logs, total)
+	RespondJSONWithCount(c, http.StatusOK, data, total)
+}
+
+// LogPageCursor 日志键集分页游标（指向上一页最后一行）
+type LogPageCursor struct {
+	BeforeTime int64 `json:"before_time"` // 毫秒时间戳
+	BeforeID   int64 `json:"before_id"`
+}
+
+// parseLogPageCursor 解析 before_time/before_id 游标参数
+// - 仅 before_time：返回早于该时间的日志
+// - 仅 before_id：按该日志的时间定位（须满足当前筛选，避免越权探测其他日志）
+func (s *Server) parseLogPageCursor(ctx context.Context, c *gin.Context, lf *model.LogFilter) (*model.LogCursor, bool, error) {
+	rawTime := strings.TrimSpace(c.Query("before_time"))
+	rawID := strings.TrimSpace(c.Query("before_id"))
+	if rawTime == "" && rawID == "" {
+		return nil, false, nil
+	}
+
+	cursor := &model.LogCursor{}
+	if rawID != "" {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, false, errors.New("invalid before_id")
+		}
+		cursor.ID = id
+	}
+	if rawTime != "" {
+		ms, err := strconv.ParseInt(rawTime, 10, 64)
+		if err != nil || ms <= 0 {
+			return nil, false, errors.New("invalid before_time")
+		}
+		cursor.TimeMs = ms
+		return cursor, true, nil
+	}
+
+	entry, err := s.store.GetLog(ctx, cursor.ID, false)
+	if err != nil || (lf.AuthTokenID != nil && entry.AuthTokenID != *lf.AuthTokenID) {
+		return nil, false, errors.New("before_id not found")
+	}
+	cursor.TimeMs = entry.Time.UnixMilli()
+	return cursor, true, nil
 }
 
 func (s *Server) tokenLogChannels(ctx context.Context, logs []*model.LogEntry) (map[int64]tokenLogChannelMetadata, error) {
@@ -85,6 +158,18 @@ func (s *Server) tokenLogChannels(ctx context.Context, logs []*model.LogEntry) (
 // HandleMetrics 获取聚合指标数据
 // GET /admin/metrics?range=today&bucket_min=5&channel_type=anthropic&model=claude-3-5-sonnet-20241022&channel_id=1&channel_name_like=xxx
 func (s *Server) HandleMetrics(c *gin.Context) {
+	since, until, bucket, lf := parseMetricsQuery(c, time.Now())
+	pts, err := s.store.AggregateRangeWithFilter(c.Request.Context(), since, until, bucket, &lf)
+
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	RespondJSON(c, http.StatusOK, pts)
+}
+
+// parseMetricsQuery 解析 /metrics 与 /metrics/stream 共用的时间范围、分桶与筛选参数
+func parseMetricsQuery(c *gin.Context, now time.Time) (since, until time.Time, bucket time.Duration, lf model.LogFilter) {
 	params := ParsePaginationParams(c)
 	bucketMin, _ := strconv.Atoi(c.DefaultQuery("bucket_min", "5"))
 	if bucketMin <= 0 {
@@ -92,17 +177,105 @@ func (s *Server) HandleMetrics(c *gin.Context) {
 	}
 
 	// 使用统一的筛选参数构建器（支持 channel_type、channel_id、channel_name_like、model、auth_token_id）
-	lf := BuildLogFilter(c)
+	lf = BuildLogFilter(c)
 	lf.LogSource = model.LogSourceProxy
 
-	since, until := params.GetTimeRange()
-	pts, err := s.store.AggregateRangeWithFilter(c.Request.Context(), since, until, time.Duration(bucketMin)*time.Minute, &lf)
+	since, until = params.GetTimeRangeAt(now)
+	return since, until, time.Duration(bucketMin) * time.Minute, lf
+}
 
-	if err != nil {
-		RespondError(c, http.StatusInternalServerError, err)
+const (
+	defaultMetricsStreamInterval = 5 * time.Second
+	maxMetricsStreamInterval     = time.Minute
+)
+
+// HandleMetricsStream SSE 推送聚合指标（仪表盘实时刷新，替代轮询 /metrics）
+// GET /admin/metrics/stream?range=today&bucket_min=5&interval_sec=5&snapshot=false（其余筛选参数同 /admin/metrics）
+// 首个 snapshot 事件为完整时间序列（snapshot=false 跳过）；此后每 interval_sec 秒推送 update 事件，
+// 仅重新聚合最近两个桶（上一个桶补齐异步刷盘延迟到达的日志），前端按 ts 合并。
+// 时间范围不包含当前时刻（如 yesterday）时推送 snapshot 后结束。
+func (s *Server) HandleMetricsStream(c *gin.Context) {
+	now := time.Now()
+	since, until, bucket, lf := parseMetricsQuery(c, now)
+	interval := defaultMetricsStreamInterval
+	if sec, err := strconv.Atoi(c.Query("interval_sec")); err == nil && sec > 0 {
+		interval = min(time.Duration(sec)*time.Second, maxMetricsStreamInterval)
+	}
+	live := !until.Before(now.Add(-time.Second))
+	sendSnapshot := util.ParseBoolDefault(c.Query("snapshot"), true)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	disableResponseWriteTimeout(c.Writer, "指标流式")
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		RespondErrorMsg(c, http.StatusInternalServerError, "streaming unsupported")
 		return
 	}
-	RespondJSON(c, http.StatusOK, pts)
+
+	ctx := c.Request.Context()
+	writeEvent := func(event string, pts []model.MetricPoint) bool {
+		data, err := sonic.Marshal(pts)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	aggregate := func(from, to time.Time) ([]model.MetricPoint, bool) {
+		pts, err := s.store.AggregateRangeWithFilter(ctx, from, to, bucket, &lf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[WARN] 指标流聚合失败: %v", err)
+			}
+			return nil, false
+		}
+		return pts, true
+	}
+
+	if sendSnapshot || !live {
+		pts, ok := aggregate(since, until)
+		if !ok || !writeEvent("snapshot", pts) {
+			return
+		}
+	}
+	if !live {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-ticker.C:
+			from := latestMetricBucketsStart(tick, bucket)
+			if from.Before(since) {
+				from = since
+			}
+			pts, ok := aggregate(from, tick)
+			if !ok {
+				continue
+			}
+			if !writeEvent("update", pts) {
+				return
+			}
+		}
+	}
+}
+
+// latestMetricBucketsStart 返回包含 now 的桶的上一个桶起点（桶按 Unix 分钟对齐，与 AggregateRangeWithFilter 一致）
+func latestMetricBucketsStart(now time.Time, bucket time.Duration) time.Time {
+	bucketMinutes := max(int64(bucket/time.Minute), 1)
+	current := now.Unix() / 60 / bucketMinutes * bucketMinutes
+	return time.Unix((current-bucketMinutes)*60, 0)
 }
 
 // HandleStats 获取渠道和模型统计
@@ -147,6 +320,8 @@ func (s *Server) HandleStats(c *gin.Context) {
 		"duration_seconds": durationSeconds,
 		"rpm_stats":        rpmStats,
 		"is_today":         isToday,
+		// 成功日志采样率 <1 时，统计中的成功请求数仅为采样值（前端可据此换算）
+		"log_success_sample_rate": s.logService.SuccessSampleRate(),
 	})
 }
 
@@ -454,6 +629,12 @@ func (s *Server) HandleGetModels(c *gin.Context) {
 // GET /health
 // 仅检查数据库连接是否活跃（适用于K8s liveness/readiness probe）
 func (s *Server) HandleHealth(c *gin.Context) {
+	// 排空模式返回 503，让负载均衡器摘除实例
+	if s.IsDraining() {
+		RespondErrorMsg(c, http.StatusServiceUnavailable, "draining")
+		return
+	}
+
 	// 设置100ms超时，避免慢查询阻塞healthcheck
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 100*time.Millisecond)
 	defer cancel()
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// statsHistoryDefaultDays 长期趋势默认查询天数
+	statsHistoryDefaultDays = 90
+	// statsHistoryMaxDays 长期趋势最大查询天数（约三年）
+	statsHistoryMaxDays = 1095
+)
+
+// HandleStatsHistory 读取 stats_daily 每日汇总（不受日志保留期影响）
+// GET /admin/stats/history?days=90
+// days 包含今天；返回按日期、渠道、模型升序的明细，由前端自行聚合。
+func (s *Server) HandleStatsHistory(c *gin.Context) {
+	days := statsHistoryDefaultDays
+	if raw := strings.TrimSpace(c.Query("days")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > statsHistoryMaxDays {
+			RespondErrorMsg(c, http.StatusBadRequest, "days must be between 1 and "+strconv.Itoa(statsHistoryMaxDays))
+			return
+		}
+		days = n
+	}
+
+	ctx := c.Request.Context()
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	items, err := s.store.ListDailyStats(ctx, since)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(items) > 0 {
+		configs, err := s.store.ListConfigs(ctx)
+		if err != nil {
+			log.Printf("[WARN] 查询渠道名称失败: %v", err)
+		}
+		names := make(map[int64]string, len(configs))
+		for _, cfg := range configs {
+			names[cfg.ID] = cfg.Name
+		}
+		for i := range items {
+			items[i].ChannelName = names[items[i].ChannelID]
+		}
+	}
+
+	RespondJSON(c, http.StatusOK, StatsHistoryResponse{
+		Days:  days,
+		Since: since,
+		Items: items,
+	})
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestHandleStatsHistory_ReadsRollupWithChannelNames(t *testing.T) {
+	server, store, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "history-channel",
+		URL:          "https://api.example.com",
+		ModelEntries: []model.ModelEntry{{Model: "m"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: cfg.ID, StatusCode: 200, InputTokens: 7},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: cfg.ID, StatusCode: 500},
+	}); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+	if err := store.RollupDailyStats(ctx, now); err != nil {
+		t.Fatalf("RollupDailyStats: %v", err)
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/stats/history?days=1", nil))
+	server.HandleStatsHistory(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, body=%s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data StatsHistoryResponse `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	today := now.Format("2006-01-02")
+	if resp.Data.Days != 1 || resp.Data.Since != today || len(resp.Data.Items) != 1 {
+		t.Fatalf("unexpected response: %+v", resp.Data)
+	}
+	item := resp.Data.Items[0]
+	if item.Day != today || item.ChannelName != "history-channel" || item.SuccessCount != 1 || item.ErrorCount != 1 || item.InputTokens != 7 {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestHandleStatsHistory_RejectsInvalidDays(t *testing.T) {
+	server, _, cleanup := setupAdminTestServer(t)
+	defer cleanup()
+
+	for _, query := range []string{"days=0", "days=abc", "days=100000"} {
+		c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/stats/history?"+query, nil))
+		server.HandleStatsHistory(c)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status=%d, want 400", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"ccLoad/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// storageResyncTimeout 手动重同步超时（配置表全量恢复通常 <1 秒）
+const storageResyncTimeout = 2 * time.Minute
+
+// hybridSyncer 混合存储（主库 + SQLite 缓存）的同步能力；纯 SQLite/MySQL 模式下不实现
+type hybridSyncer interface {
+	SyncStatus(ctx context.Context) storage.HybridSyncStatus
+	ResyncConfig(ctx context.Context) error
+}
+
+// HandleStorageSyncStatus 查询混合存储同步状态（主库连通性、渠道数对比、最近同步时间/错误）
+// GET /admin/storage/sync
+func (s *Server) HandleStorageSyncStatus(c *gin.Context) {
+	syncer, ok := s.store.(hybridSyncer)
+	if !ok {
+		RespondJSON(c, http.StatusOK, storage.HybridSyncStatus{})
+		return
+	}
+	RespondJSON(c, http.StatusOK, syncer.SyncStatus(c.Request.Context()))
+}
+
+// HandleStorageResync 同步执行一次配置表全量恢复（主库 → SQLite 缓存），返回恢复后的状态。
+// POST /admin/storage/sync
+// 用于迁移/下线实例前确认本地缓存与主库一致。
+func (s *Server) HandleStorageResync(c *gin.Context) {
+	syncer, ok := s.store.(hybridSyncer)
+	if !ok {
+		RespondErrorMsg(c, http.StatusConflict, "hybrid storage is not enabled")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), storageResyncTimeout)
+	defer cancel()
+	if err := syncer.ResyncConfig(ctx); err != nil {
+		RespondError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	// 缓存行已整体替换：丢弃所有内存缓存，按新数据重新加载
+	s.InvalidateChannelListCache()
+	s.InvalidateAllAPIKeysCache()
+	s.invalidateCooldownCache()
+	if err := s.authService.ReloadAuthTokens(); err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	RespondJSON(c, http.StatusOK, syncer.SyncStatus(ctx))
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/storage"
+	sqlstore "ccLoad/internal/storage/sql"
+)
+
+func TestStorageSync_NonHybridStore(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/storage/sync", nil))
+	srv.HandleStorageSyncStatus(c)
+	var resp APIResponse[storage.HybridSyncStatus]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if w.Code != http.StatusOK || resp.Data.Enabled {
+		t.Fatalf("status=%d enabled=%v, want 200/false", w.Code, resp.Data.Enabled)
+	}
+
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/admin/storage/sync", nil))
+	srv.HandleStorageResync(c)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("resync status=%d, want 409", w.Code)
+	}
+}
+
+func TestStorageSync_ResyncReloadsCacheFromPrimary(t *testing.T) {
+	openSQLite := func(name string) *sqlstore.SQLStore {
+		store, err := storage.CreateSQLiteStore(filepath.Join(t.TempDir(), name))
+		if err != nil {
+			t.Fatalf("CreateSQLiteStore: %v", err)
+		}
+		return store.(*sqlstore.SQLStore)
+	}
+	primary := openSQLite("primary.db") // 用 SQLite 模拟主库
+	cache := openSQLite("cache.db")
+
+	srv := NewServer(storage.NewHybridStore(cache, primary))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+		_ = primary.Close()
+	})
+
+	ctx := context.Background()
+	if _, err := primary.CreateConfig(ctx, &model.Config{
+		Name: "primary-only", ChannelType: "openai", URL: "https://api.example.com", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+
+	fetch := func(method string) storage.HybridSyncStatus {
+		t.Helper()
+		c, w := newTestContext(t, newRequest(method, "/admin/storage/sync", nil))
+		if method == http.MethodPost {
+			srv.HandleStorageResync(c)
+		} else {
+			srv.HandleStorageSyncStatus(c)
+		}
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s status=%d body=%s", method, w.Code, w.Body.String())
+		}
+		var resp APIResponse[storage.HybridSyncStatus]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp.Data
+	}
+
+	before := fetch(http.MethodGet)
+	if !before.Enabled || !before.PrimaryReachable || before.PrimaryChannels != 1 || before.CacheChannels != 0 {
+		t.Fatalf("unexpected status before resync: %+v", before)
+	}
+
+	after := fetch(http.MethodPost)
+	if after.CacheChannels != 1 || after.LastSyncAt.IsZero() || after.LastSyncError != "" {
+		t.Fatalf("unexpected status after resync: %+v", after)
+	}
+	configs, err := srv.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 || configs[0].Name != "primary-only" {
+		t.Fatalf("channel should be readable from cache after resync: %v %+v", err, configs)
+	}
+}
@@ -300,6 +300,7 @@ func (s *Server) buildChannelTestRequestPlan(
 	}
 
 	if clientProtocol == upstreamProtocol {
+		applyAzureTestPlan(plan, cfgForBuild, apiKey, testReq.Model)
 		return plan, nil
 	}
 	if s == nil || s.protocolRegistry == nil {
@@ -345,9 +346,25 @@ func (s *Server) buildChannelTestRequestPlan(
 	plan.fullURL = upstreamURL
 	plan.headers = cloneHeaders(upstreamHeaders)
 	plan.requestBody = translatedBody
+	applyAzureTestPlan(plan, cfgForBuild, apiKey, testReq.Model)
 	return plan, nil
 }
 
+// applyAzureTestPlan Azure OpenAI 渠道：测试请求改写为部署路径并改用 api-key 认证
+// OpenAITester 固定请求 /v1/chat/completions，部署名取已重定向的测试模型
+func applyAzureTestPlan(plan *channelTestRequestPlan, cfg *model.Config, apiKey, deployment string) {
+	urls := cfg.GetURLs()
+	if len(urls) == 0 || !usesAzureOpenAI(cfg, plan.upstreamProtocol, urls[0]) {
+		return
+	}
+	azureURL, ok := buildAzureUpstreamURL(urls[0], "/v1/chat/completions", "", deployment)
+	if !ok {
+		return
+	}
+	plan.fullURL = azureURL
+	injectAzureAPIKeyHeader(plan.headers, apiKey)
+}
+
 func parseTestStreamResponseBytes(
 	raw []byte,
 	parseProtocol string,
@@ -481,9 +498,13 @@ type channelTestKeySelection struct {
 func (s *Server) selectChannelTestKey(apiKeys []*model.APIKey, requestedKeyIndex int, requestAPIKey string) (channelTestKeySelection, error) {
 	if requestAPIKey != "" {
 		matchedKey, ok := findAPIKeyByIndex(apiKeys, requestedKeyIndex)
+		resolved, err := resolveAPIKey(requestAPIKey)
+		if err != nil {
+			return channelTestKeySelection{}, err
+		}
 		return channelTestKeySelection{
 			keyIndex:                requestedKeyIndex,
-			apiKey:                  requestAPIKey,
+			apiKey:                  resolved,
 			updatePersistedCooldown: ok && matchedKey.APIKey == requestAPIKey,
 		}, nil
 	}
@@ -495,9 +516,13 @@ func (s *Server) selectChannelTestKey(apiKeys []*model.APIKey, requestedKeyIndex
 	if !ok {
 		return channelTestKeySelection{}, fmt.Errorf("未找到 Key #%d", requestedKeyIndex)
 	}
+	resolved, err := resolveAPIKey(requestedKey.APIKey)
+	if err != nil {
+		return channelTestKeySelection{}, err
+	}
 	return channelTestKeySelection{
 		keyIndex:                requestedKey.KeyIndex,
-		apiKey:                  requestedKey.APIKey,
+		apiKey:                  resolved,
 		updatePersistedCooldown: true,
 	}, nil
 }
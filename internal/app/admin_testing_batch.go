@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testAllDefaultConcurrency = 4
+	testAllMaxConcurrency     = 16
+)
+
+// TestAllChannelsRequest 批量测试请求（请求体可省略）
+type TestAllChannelsRequest struct {
+	Concurrency int `json:"concurrency,omitempty"` // 并发测试的渠道数，默认4，最大16
+}
+
+// TestAllChannelsItem 单个渠道的测试结果
+type TestAllChannelsItem struct {
+	ChannelID   int64  `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	Model       string `json:"model,omitempty"`
+	KeyIndex    int    `json:"key_index"`
+	Status      string `json:"status"` // passed / failed / skipped
+	StatusCode  int    `json:"status_code,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// TestAllChannelsResponse 批量测试汇总
+type TestAllChannelsResponse struct {
+	Total   int                   `json:"total"`
+	Passed  int                   `json:"passed"`
+	Failed  int                   `json:"failed"`
+	Skipped int                   `json:"skipped"`
+	Results []TestAllChannelsItem `json:"results"`
+}
+
+// HandleTestAllChannels 并发测试所有启用渠道（每个渠道用首个支持模型 + 首个启用 Key）
+// 路由: POST /admin/channels/test-all
+// 与单渠道测试一致：成功清除渠道/Key/模型冷却，失败按错误分类写入冷却。
+func (s *Server) HandleTestAllChannels(c *gin.Context) {
+	var req TestAllChannelsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondErrorMsg(c, http.StatusBadRequest, "参数无效: "+err.Error())
+			return
+		}
+	}
+	workers := req.Concurrency
+	if workers <= 0 {
+		workers = testAllDefaultConcurrency
+	}
+	workers = min(workers, testAllMaxConcurrency)
+
+	ctx := c.Request.Context()
+	configs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	apiKeysByChannel, err := s.store.GetAllAPIKeys(ctx)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	enabled := make([]*model.Config, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg != nil && cfg.Enabled {
+			enabled = append(enabled, cfg)
+		}
+	}
+
+	results := make([]TestAllChannelsItem, len(enabled))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(enabled)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.testChannelForBatch(ctx, enabled[i], apiKeysByChannel[enabled[i].ID], c.ClientIP())
+			}
+		}()
+	}
+	for i := range enabled {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	resp := TestAllChannelsResponse{Total: len(enabled), Results: make([]TestAllChannelsItem, 0, len(enabled))}
+	for _, item := range results {
+		if item.Status == "" {
+			// 客户端断开后未执行的渠道
+			continue
+		}
+		switch item.Status {
+		case "passed":
+			resp.Passed++
+		case "failed":
+			resp.Failed++
+		default:
+			resp.Skipped++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	RespondJSON(c, http.StatusOK, resp)
+}
+
+// testChannelForBatch 测试单个渠道（首个支持模型 + 首个启用 Key）
+func (s *Server) testChannelForBatch(ctx context.Context, cfg *model.Config, apiKeys []*model.APIKey, clientIP string) TestAllChannelsItem {
+	item := TestAllChannelsItem{ChannelID: cfg.ID, ChannelName: cfg.Name, KeyIndex: -1}
+
+	models := cfg.GetModels()
+	if len(models) == 0 {
+		item.Status = "skipped"
+		item.Error = "渠道未配置模型"
+		return item
+	}
+	item.Model = models[0]
+
+	keyIndex, apiKey, skipReason := firstEnabledTestKey(apiKeys)
+	if skipReason != "" {
+		item.Status = "skipped"
+		item.Error = skipReason
+		return item
+	}
+	item.KeyIndex = keyIndex
+
+	testReq := &testutil.TestChannelRequest{
+		Model:       item.Model,
+		ChannelType: cfg.GetChannelType(),
+	}
+	result := s.executeChannelTest(ctx, cfg, keyIndex, apiKey, testReq)
+	s.persistDetectionLog(ctx, detectionLogFromResult(cfg, model.LogSourceManualTest, item.Model, testReq.Model, apiKey, clientIP, testReq.ThinkingEffort, result))
+
+	item.StatusCode, _ = getResultInt(result["status_code"])
+	item.DurationMs, _ = getResultInt64(result["duration_ms"])
+	if success, _ := result["success"].(bool); success {
+		item.Status = "passed"
+		return item
+	}
+	item.Status = "failed"
+	item.Error, _ = result["error"].(string)
+	if strings.TrimSpace(item.Error) == "" {
+		item.Error = "unknown error"
+	}
+	return item
+}
+
+// firstEnabledTestKey 返回首个启用 Key 的索引与解析后的明文
+func firstEnabledTestKey(apiKeys []*model.APIKey) (int, string, string) {
+	reason := "渠道未配置有效的 API Key"
+	for _, key := range apiKeys {
+		if key == nil || key.Disabled {
+			continue
+		}
+		resolved, err := resolveAPIKey(strings.TrimSpace(key.APIKey))
+		if err != nil {
+			reason = err.Error()
+			continue
+		}
+		if resolved != "" {
+			return key.KeyIndex, resolved, ""
+		}
+	}
+	return -1, "", reason
+}
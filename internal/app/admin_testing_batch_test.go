@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestHandleTestAllChannels(t *testing.T) {
+	okUpstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_test","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"model":"claude-3-5-sonnet","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer okUpstream.Close()
+	failUpstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"type":"authentication_error","message":"invalid api key"}}`))
+	}))
+	defer failUpstream.Close()
+
+	srv := newInMemoryServer(t)
+	srv.client = okUpstream.Client()
+	ctx := context.Background()
+
+	mustCreate := func(name, url string, enabled bool, withKey bool) *model.Config {
+		t.Helper()
+		cfg, err := srv.store.CreateConfig(ctx, &model.Config{
+			Name: name, URL: url, Priority: 1, Enabled: enabled,
+			ModelEntries: []model.ModelEntry{{Model: "claude-3-5-sonnet"}, {Model: "claude-3-haiku"}},
+		})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if withKey {
+			if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+				{ChannelID: cfg.ID, KeyIndex: 0, APIKey: "sk-disabled", Disabled: true},
+				{ChannelID: cfg.ID, KeyIndex: 1, APIKey: "sk-" + name},
+			}); err != nil {
+				t.Fatalf("create keys for %s: %v", name, err)
+			}
+		}
+		return cfg
+	}
+	good := mustCreate("good", okUpstream.URL, true, true)
+	bad := mustCreate("bad", failUpstream.URL, true, true)
+	noKey := mustCreate("no-key", okUpstream.URL, true, false)
+	mustCreate("disabled", failUpstream.URL, false, true)
+
+	if err := srv.store.SetChannelCooldown(ctx, good.ID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("set channel cooldown: %v", err)
+	}
+
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels/test-all", map[string]any{"concurrency": 2}))
+	srv.HandleTestAllChannels(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	resp := mustParseAPIResponse[TestAllChannelsResponse](t, w.Body.Bytes())
+	if resp.Data.Total != 3 || resp.Data.Passed != 1 || resp.Data.Failed != 1 || resp.Data.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", resp.Data)
+	}
+	byID := make(map[int64]TestAllChannelsItem, len(resp.Data.Results))
+	for _, item := range resp.Data.Results {
+		byID[item.ChannelID] = item
+	}
+	if item := byID[good.ID]; item.Status != "passed" || item.KeyIndex != 1 || item.Model != "claude-3-5-sonnet" {
+		t.Fatalf("good channel: %+v", item)
+	}
+	if item := byID[bad.ID]; item.Status != "failed" || item.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("bad channel: %+v", item)
+	}
+	if item := byID[noKey.ID]; item.Status != "skipped" {
+		t.Fatalf("no-key channel: %+v", item)
+	}
+
+	cfg, err := srv.store.GetConfig(ctx, good.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if cfg.CooldownUntil != 0 {
+		t.Fatalf("passing channel cooldown should be reset, got %d", cfg.CooldownUntil)
+	}
+}
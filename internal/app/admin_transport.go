@@ -0,0 +1,38 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransportSettings 上游 HTTP Transport 生效配置
+type TransportSettings struct {
+	MaxIdleConns        int     `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int     `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int     `json:"max_conns_per_host"`
+	IdleConnTimeoutSec  float64 `json:"idle_conn_timeout_seconds"`
+	ForceHTTP2          bool    `json:"force_http2"`
+	InsecureSkipVerify  bool    `json:"insecure_skip_verify"`
+}
+
+// HandleTransportSettings 查询全局上游 Transport 生效配置（用于核对连接池环境变量）
+// GET /admin/transport
+func (s *Server) HandleTransportSettings(c *gin.Context) {
+	var t *http.Transport
+	if s.client != nil {
+		t, _ = s.client.Transport.(*http.Transport)
+	}
+	if t == nil {
+		RespondErrorMsg(c, http.StatusServiceUnavailable, "upstream transport is not an *http.Transport")
+		return
+	}
+	RespondJSON(c, http.StatusOK, TransportSettings{
+		MaxIdleConns:        t.MaxIdleConns,
+		MaxIdleConnsPerHost: t.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     t.MaxConnsPerHost,
+		IdleConnTimeoutSec:  t.IdleConnTimeout.Seconds(),
+		ForceHTTP2:          t.ForceAttemptHTTP2,
+		InsecureSkipVerify:  s.skipTLSVerify,
+	})
+}
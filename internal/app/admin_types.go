@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	neturl "net/url"
 	"slices"
 	"strings"
@@ -36,16 +37,38 @@ type ChannelRequest struct {
 	DailyCostLimit        float64                   `json:"daily_cost_limit"` // 每日成本限额（美元），0表示无限制
 	CostMultiplier        float64                   `json:"cost_multiplier"`  // 成本倍率（默认1，0=免费，>=0）
 	CustomRequestRules    *model.CustomRequestRules `json:"custom_request_rules,omitempty"`
-	ProxyURL              string                    `json:"proxy_url,omitempty"` // 渠道级代理（http/https/socks5/socks5h）
+	ProxyURL              string                    `json:"proxy_url,omitempty"`              // 渠道级代理（http/https/socks5/socks5h）
+	AllowedMethods        []string                  `json:"allowed_methods,omitempty"`        // 允许的客户端HTTP方法，空=不限制
+	RedirectRoutingOnly   bool                      `json:"redirect_routing_only"`            // 重定向仅用于路由，上游保留原始模型名
+	MaxInputTokens        int                       `json:"max_input_tokens"`                 // 估算输入 token 上限，0=无限制
+	MaxOutputTokens       int                       `json:"max_output_tokens"`                // 输出 token 上限（封顶 max_tokens），0=无限制
+	Cacheable             bool                      `json:"cacheable"`                        // 启用上游响应缓存（仅非流式 POST）
+	RestoreResponseModel  bool                      `json:"restore_response_model"`           // 模型重定向时将响应 model 还原为请求模型
+	SuccessCodes          string                    `json:"success_codes,omitempty"`          // 视为成功的状态码范围（如 200-299,404），空=2xx
+	ActiveSchedule        string                    `json:"active_schedule,omitempty"`        // 启用时段（如 Mon-Fri 22:00-08:00 Asia/Shanghai），空=全天
+	BlockedModels         []string                  `json:"blocked_models,omitempty"`         // 屏蔽的模型（不参与这些模型的选择），空=不屏蔽
+	StripHeaders          []string                  `json:"strip_headers,omitempty"`          // 额外剥离的客户端请求头（支持 X-Foo-* 前缀），空=仅全局默认
+	RetryErrorSubstrings  []string                  `json:"retry_error_substrings,omitempty"` // 上游错误体命中任一子串时强制切换渠道重试，空=不启用
 }
 
 // ChannelAPIKeyRequest describes one submitted API key and its admin-only note.
 type ChannelAPIKeyRequest struct {
-	APIKey string `json:"api_key"`
-	Note   string `json:"note,omitempty"`
+	APIKey   string `json:"api_key"`
+	Note     string `json:"note,omitempty"`
+	KeyGroup string `json:"key_group,omitempty"` // 跨渠道共享冷却分组
+	RPMLimit int    `json:"rpm_limit,omitempty"` // Key级每分钟请求上限，0=不限
 }
 
-const maxAPIKeyNoteLength = 512
+const (
+	maxAPIKeyNoteLength  = 512
+	maxAPIKeyGroupLength = 64
+	// maxBlockedModelsLength 与 channels.blocked_models 列宽一致（逗号分隔存储）
+	maxBlockedModelsLength = 1024
+	// maxStripHeadersLength 与 channels.strip_headers 列宽一致（逗号分隔存储）
+	maxStripHeadersLength = 1024
+	// maxRetryErrorSubstringsLength 与 channels.retry_error_substrings 列宽一致（换行分隔存储）
+	maxRetryErrorSubstringsLength = 1024
+)
 
 func (cr *ChannelRequest) normalizeAPIKeys() []ChannelAPIKeyRequest {
 	if len(cr.APIKeys) > 0 {
@@ -56,8 +79,10 @@ func (cr *ChannelRequest) normalizeAPIKeys() []ChannelAPIKeyRequest {
 				continue
 			}
 			keys = append(keys, ChannelAPIKeyRequest{
-				APIKey: apiKey,
-				Note:   strings.TrimSpace(item.Note),
+				APIKey:   apiKey,
+				Note:     strings.TrimSpace(item.Note),
+				KeyGroup: strings.TrimSpace(item.KeyGroup),
+				RPMLimit: item.RPMLimit,
 			})
 		}
 		return keys
@@ -71,6 +96,20 @@ func (cr *ChannelRequest) normalizeAPIKeys() []ChannelAPIKeyRequest {
 	return keys
 }
 
+// dedupeAPIKeyRequests 移除重复 Key（按明文比较，保留首次出现及其备注/分组），返回去重结果与移除数量
+func dedupeAPIKeyRequests(keys []ChannelAPIKeyRequest) ([]ChannelAPIKeyRequest, int) {
+	seen := make(map[string]struct{}, len(keys))
+	result := keys[:0:0]
+	for _, key := range keys {
+		if _, ok := seen[key.APIKey]; ok {
+			continue
+		}
+		seen[key.APIKey] = struct{}{}
+		result = append(result, key)
+	}
+	return result, len(keys) - len(result)
+}
+
 func apiKeyStrings(keys []ChannelAPIKeyRequest) []string {
 	values := make([]string, 0, len(keys))
 	for _, key := range keys {
@@ -79,6 +118,73 @@ func apiKeyStrings(keys []ChannelAPIKeyRequest) []string {
 	return values
 }
 
+// validateAPIKeyRequests 校验 Key 明文、文件引用、备注、分组与RPM上限（入参须已 normalize）
+func validateAPIKeyRequests(keys []ChannelAPIKeyRequest) error {
+	for i, key := range keys {
+		if strings.ContainsAny(key.APIKey, "\x00\r\n") {
+			return fmt.Errorf("api_keys[%d].api_key contains illegal characters", i)
+		}
+		if util.IsAPIKeyFileRef(key.APIKey) {
+			if err := util.ValidateAPIKeyFileRef(key.APIKey); err != nil {
+				return fmt.Errorf("api_keys[%d]: %w", i, err)
+			}
+		}
+		if len(key.Note) > maxAPIKeyNoteLength {
+			return fmt.Errorf("api_keys[%d].note is too long (max %d bytes)", i, maxAPIKeyNoteLength)
+		}
+		if strings.Contains(key.Note, "\x00") {
+			return fmt.Errorf("api_keys[%d].note contains illegal characters", i)
+		}
+		if len(key.KeyGroup) > maxAPIKeyGroupLength {
+			return fmt.Errorf("api_keys[%d].key_group is too long (max %d bytes)", i, maxAPIKeyGroupLength)
+		}
+		if strings.ContainsAny(key.KeyGroup, "\x00\r\n\t") {
+			return fmt.Errorf("api_keys[%d].key_group contains illegal characters", i)
+		}
+		if key.RPMLimit < 0 {
+			return fmt.Errorf("api_keys[%d].rpm_limit must be >= 0", i)
+		}
+	}
+	return nil
+}
+
+// CloneChannelRequest 复制渠道请求（请求体可省略）
+type CloneChannelRequest struct {
+	Name        string `json:"name,omitempty"`         // 新渠道名称，空值=原名称追加 " (copy)" 后缀并自动去重
+	WithoutKeys bool   `json:"without_keys,omitempty"` // 不复制 API Key（复制后再单独配置）
+}
+
+// Validate 实现RequestValidator接口
+func (r *CloneChannelRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if len(r.Name) > 191 {
+		return fmt.Errorf("name too long (max 191)")
+	}
+	return nil
+}
+
+// ReplaceChannelKeysRequest 原子替换渠道全部 Key 的请求
+type ReplaceChannelKeysRequest struct {
+	Keys        []ChannelAPIKeyRequest `json:"keys"`
+	KeyStrategy string                 `json:"key_strategy,omitempty"` // 空值=沿用渠道当前策略
+}
+
+// Validate 实现RequestValidator接口
+func (r *ReplaceChannelKeysRequest) Validate() error {
+	r.Keys = (&ChannelRequest{APIKeys: r.Keys}).normalizeAPIKeys()
+	if len(r.Keys) == 0 {
+		return fmt.Errorf("keys cannot be empty")
+	}
+	if err := validateAPIKeyRequests(r.Keys); err != nil {
+		return err
+	}
+	r.KeyStrategy = strings.ToLower(strings.TrimSpace(r.KeyStrategy))
+	if r.KeyStrategy != "" && !model.IsValidKeyStrategy(r.KeyStrategy) {
+		return fmt.Errorf("invalid key_strategy: %q (allowed: sequential, round_robin)", r.KeyStrategy)
+	}
+	return nil
+}
+
 func validateChannelBaseURL(raw string) (string, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -161,16 +267,8 @@ func (cr *ChannelRequest) Validate() error {
 	if len(apiKeys) == 0 {
 		return fmt.Errorf("api_key cannot be empty")
 	}
-	for i, key := range apiKeys {
-		if strings.ContainsAny(key.APIKey, "\x00\r\n") {
-			return fmt.Errorf("api_keys[%d].api_key contains illegal characters", i)
-		}
-		if len(key.Note) > maxAPIKeyNoteLength {
-			return fmt.Errorf("api_keys[%d].note is too long (max %d bytes)", i, maxAPIKeyNoteLength)
-		}
-		if strings.Contains(key.Note, "\x00") {
-			return fmt.Errorf("api_keys[%d].note contains illegal characters", i)
-		}
+	if err := validateAPIKeyRequests(apiKeys); err != nil {
+		return err
 	}
 	cr.APIKeys = apiKeys
 	cr.APIKey = strings.Join(apiKeyStrings(apiKeys), ",")
@@ -265,12 +363,65 @@ func (cr *ChannelRequest) Validate() error {
 		}
 	}
 
+	cr.AllowedMethods = model.NormalizeHTTPMethods(cr.AllowedMethods)
+	for _, m := range cr.AllowedMethods {
+		if !slices.Contains(allowedChannelHTTPMethods, m) {
+			return fmt.Errorf("invalid allowed_methods entry: %q (allowed: %s)", m, strings.Join(allowedChannelHTTPMethods, ", "))
+		}
+	}
+
+	cr.SuccessCodes = strings.Join(strings.Fields(cr.SuccessCodes), "")
+	if _, err := model.ParseStatusCodeRanges(cr.SuccessCodes); err != nil {
+		return fmt.Errorf("invalid success_codes: %w", err)
+	}
+
+	cr.ActiveSchedule = strings.Join(strings.Fields(cr.ActiveSchedule), " ")
+	if _, err := model.ParseActiveSchedule(cr.ActiveSchedule); err != nil {
+		return fmt.Errorf("invalid active_schedule: %w", err)
+	}
+
+	cr.BlockedModels = model.NormalizeBlockedModels(cr.BlockedModels)
+	for _, m := range cr.BlockedModels {
+		if strings.ContainsAny(m, ",\x00\r\n") {
+			return fmt.Errorf("invalid blocked_models entry: %q", m)
+		}
+	}
+	if n := len(strings.Join(cr.BlockedModels, ",")); n > maxBlockedModelsLength {
+		return fmt.Errorf("blocked_models is too long (max %d bytes, got %d)", maxBlockedModelsLength, n)
+	}
+
+	cr.StripHeaders = model.NormalizeStripHeaders(cr.StripHeaders)
+	for _, h := range cr.StripHeaders {
+		if !isValidStripHeaderPattern(h) {
+			return fmt.Errorf("invalid strip_headers entry: %q", h)
+		}
+	}
+	if n := len(strings.Join(cr.StripHeaders, ",")); n > maxStripHeadersLength {
+		return fmt.Errorf("strip_headers is too long (max %d bytes, got %d)", maxStripHeadersLength, n)
+	}
+
+	cr.RetryErrorSubstrings = model.NormalizeRetryErrorSubstrings(cr.RetryErrorSubstrings)
+	for _, sub := range cr.RetryErrorSubstrings {
+		if strings.ContainsAny(sub, "\x00\r\n") {
+			return fmt.Errorf("invalid retry_error_substrings entry: %q", sub)
+		}
+	}
+	if n := len(strings.Join(cr.RetryErrorSubstrings, "\n")); n > maxRetryErrorSubstringsLength {
+		return fmt.Errorf("retry_error_substrings is too long (max %d bytes, got %d)", maxRetryErrorSubstringsLength, n)
+	}
+
 	if cr.RPMLimit < 0 {
 		return fmt.Errorf("rpm_limit must be >= 0 (got %d)", cr.RPMLimit)
 	}
 	if cr.MaxConcurrency < 0 {
 		return fmt.Errorf("max_concurrency must be >= 0 (got %d)", cr.MaxConcurrency)
 	}
+	if cr.MaxInputTokens < 0 {
+		return fmt.Errorf("max_input_tokens must be >= 0 (got %d)", cr.MaxInputTokens)
+	}
+	if cr.MaxOutputTokens < 0 {
+		return fmt.Errorf("max_output_tokens must be >= 0 (got %d)", cr.MaxOutputTokens)
+	}
 
 	// CostMultiplier: 未传视为默认 1；0 表示免费渠道；负数拒绝
 	if cr.CostMultiplier == 0 {
@@ -311,15 +462,49 @@ func (cr *ChannelRequest) ToConfig() *model.Config {
 		CostMultiplier:        cr.CostMultiplier,
 		CustomRequestRules:    cr.CustomRequestRules,
 		ProxyURL:              cr.ProxyURL,
+		AllowedMethods:        append([]string(nil), cr.AllowedMethods...),
+		RedirectRoutingOnly:   cr.RedirectRoutingOnly,
+		MaxInputTokens:        cr.MaxInputTokens,
+		MaxOutputTokens:       cr.MaxOutputTokens,
+		Cacheable:             cr.Cacheable,
+		RestoreResponseModel:  cr.RestoreResponseModel,
+		SuccessCodes:          cr.SuccessCodes,
+		ActiveSchedule:        cr.ActiveSchedule,
+		BlockedModels:         append([]string(nil), cr.BlockedModels...),
+		StripHeaders:          append([]string(nil), cr.StripHeaders...),
+		RetryErrorSubstrings:  append([]string(nil), cr.RetryErrorSubstrings...),
 	}
 }
 
+// allowedChannelHTTPMethods 渠道 allowed_methods 可选值
+var allowedChannelHTTPMethods = []string{
+	http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions,
+	http.MethodPatch, http.MethodPost, http.MethodPut,
+}
+
 const (
 	maxCustomRuleEntries = 32
 	maxCustomRuleValue   = 8 * 1024
 	maxCustomRuleName    = 256
 )
 
+// isValidStripHeaderPattern 请求头名仅允许 RFC 7230 token 字符，"*" 只能作为非空前缀的结尾
+func isValidStripHeaderPattern(pattern string) bool {
+	name, _ := strings.CutSuffix(pattern, "*")
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // validateCustomRequestRules 校验渠道自定义请求规则；副作用：修剪名称/路径空白并丢弃 remove 规则的 value。
 func validateCustomRequestRules(r *model.CustomRequestRules) error {
 	if r == nil {
@@ -364,8 +549,8 @@ func validateCustomRequestRules(r *model.CustomRequestRules) error {
 	for i := range r.Body {
 		b := &r.Body[i]
 		action := strings.ToLower(strings.TrimSpace(b.Action))
-		if action != model.RuleActionRemove && action != model.RuleActionOverride {
-			return fmt.Errorf("custom_request_rules.body[%d]: invalid action %q (allowed: remove, override)", i, b.Action)
+		if action != model.RuleActionRemove && action != model.RuleActionOverride && action != model.RuleActionDefault {
+			return fmt.Errorf("custom_request_rules.body[%d]: invalid action %q (allowed: remove, override, default)", i, b.Action)
 		}
 		b.Action = action
 
@@ -380,13 +565,17 @@ func validateCustomRequestRules(r *model.CustomRequestRules) error {
 			return fmt.Errorf("custom_request_rules.body[%d]: path contains illegal characters (allowed: letters, digits, _, -, .)", i)
 		}
 		b.Path = path
+		// 流式标志决定响应处理方式，只能由客户端决定，默认值注入会导致响应解析错乱
+		if action == model.RuleActionDefault && path == "stream" {
+			return fmt.Errorf("custom_request_rules.body[%d]: default cannot target stream", i)
+		}
 
 		if action == model.RuleActionRemove {
 			b.Value = nil
 			continue
 		}
 		if len(b.Value) == 0 {
-			return fmt.Errorf("custom_request_rules.body[%d]: override requires value", i)
+			return fmt.Errorf("custom_request_rules.body[%d]: %s requires value", i, action)
 		}
 		if len(b.Value) > maxCustomRuleValue {
 			return fmt.Errorf("custom_request_rules.body[%d]: value too long (max %d bytes)", i, maxCustomRuleValue)
@@ -502,12 +691,24 @@ type ChannelModelStats struct {
 	AvgDurationSeconds      *float64 `json:"avg_duration_seconds,omitempty"`
 }
 
+// ChannelRecentError 渠道最近一次错误请求的摘要
+type ChannelRecentError struct {
+	Time       model.JSONTime `json:"time"`
+	StatusCode int            `json:"status_code"`
+	Model      string         `json:"model"`
+	Message    string         `json:"message"`
+	LogSource  string         `json:"log_source,omitempty"`
+	RequestID  string         `json:"request_id,omitempty"`
+}
+
 // ChannelWithCooldown 带冷却状态的渠道响应结构
 type ChannelWithCooldown struct {
 	*model.Config
 	KeyStrategy         string              `json:"key_strategy,omitempty"` // [INFO] 修复 (2025-10-11): 添加key_strategy字段
 	CooldownUntil       *time.Time          `json:"cooldown_until,omitempty"`
 	CooldownRemainingMS int64               `json:"cooldown_remaining_ms,omitempty"`
+	CircuitOpen         bool                `json:"circuit_open,omitempty"` // 熔断中（连续冷却周期无成功）
+	NoAPIKeys           bool                `json:"no_api_keys,omitempty"`  // 没有可用（已启用）的 API Key，请求必然失败
 	KeyCooldowns        []KeyCooldownInfo   `json:"key_cooldowns,omitempty"`
 	ModelCooldowns      []ModelCooldownInfo `json:"model_cooldowns,omitempty"`
 	EffectivePriority   *float64            `json:"effective_priority,omitempty"` // 健康度模式下的有效优先级
@@ -520,14 +721,48 @@ type ChannelImportSummary struct {
 	Updated   int      `json:"updated"`
 	Skipped   int      `json:"skipped"`
 	Processed int      `json:"processed"`
+	Batches   int      `json:"batches,omitempty"` // 已提交的导入事务数（仅完整导入模式）
 	Errors    []string `json:"errors,omitempty"`
 }
 
+// ConfigBackup 全量配置备份（GET /admin/backup 输出，POST /admin/restore 输入）
+type ConfigBackup struct {
+	SchemaVersion int                     `json:"schema_version"` // 备份格式版本
+	CreatedAt     int64                   `json:"created_at"`     // 备份时间（Unix秒）
+	Channels      []model.ChannelWithKeys `json:"channels"`       // 渠道及其 Key（明文）
+	Settings      map[string]string       `json:"settings"`       // 系统设置 key → value
+}
+
+// ConfigRestoreSummary 配置恢复结果统计
+type ConfigRestoreSummary struct {
+	ChannelsCreated int      `json:"channels_created"`
+	ChannelsUpdated int      `json:"channels_updated"`
+	KeysRestored    int      `json:"keys_restored"`
+	SettingsUpdated int      `json:"settings_updated"`
+	SettingsSkipped []string `json:"settings_skipped,omitempty"` // 当前版本不存在的设置项
+	RestartRequired bool     `json:"restart_required"`           // 系统设置有变更，需重启生效
+	Warnings        []string `json:"warnings,omitempty"`         // 已恢复但需关注的问题（如 Key 数量超过单渠道上限）
+	Errors          []string `json:"errors,omitempty"`
+}
+
 // CooldownRequest 冷却设置请求
 type CooldownRequest struct {
 	DurationMs int64 `json:"duration_ms" binding:"required,min=1000"` // 最少1秒
 }
 
+// ChannelMaintenanceRequest 渠道维护模式切换请求
+type ChannelMaintenanceRequest struct {
+	Maintenance *bool `json:"maintenance" binding:"required"`
+}
+
+// SimulateErrorRequest 模拟上游错误请求（仅用于测试冷却/告警链路，不发起真实请求）
+type SimulateErrorRequest struct {
+	StatusCode int    `json:"status_code" binding:"required,min=400,max=599"`
+	KeyIndex   *int   `json:"key_index,omitempty"` // 缺省表示与特定Key无关
+	Model      string `json:"model,omitempty"`     // 可选：模拟模型级错误时的上游模型名
+	Body       string `json:"body,omitempty"`      // 可选：模拟的上游错误体（影响错误分类）
+}
+
 // SettingUpdateRequest 系统配置更新请求
 type SettingUpdateRequest struct {
 	Value string `json:"value" binding:"required"`
@@ -554,3 +789,53 @@ type DuplicateChannelInfo struct {
 type CheckDuplicateResponse struct {
 	Duplicates []DuplicateChannelInfo `json:"duplicates"`
 }
+
+// ModelBudgetItem 单个模型的月度预算使用情况
+type ModelBudgetItem struct {
+	Model     string  `json:"model"`
+	Budget    float64 `json:"budget"`
+	Spent     float64 `json:"spent"`
+	Remaining float64 `json:"remaining"`
+	Exceeded  bool    `json:"exceeded"`
+}
+
+// ModelBudgetResponse 模型月度预算报告
+type ModelBudgetResponse struct {
+	MonthStart time.Time         `json:"month_start"`
+	Enforce    bool              `json:"enforce"`
+	Items      []ModelBudgetItem `json:"items"`
+}
+
+// ChannelLintWarning 渠道配置检查警告
+type ChannelLintWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ChannelLintResult 单个渠道的检查结果（仅包含有警告的渠道）
+type ChannelLintResult struct {
+	ID          int64                `json:"id"`
+	Name        string               `json:"name"`
+	ChannelType string               `json:"channel_type"`
+	Warnings    []ChannelLintWarning `json:"warnings"`
+}
+
+// ChannelLintResponse 渠道配置检查报告
+type ChannelLintResponse struct {
+	Checked  int                 `json:"checked"`
+	Channels []ChannelLintResult `json:"channels"`
+}
+
+// ErrorBreakdownResponse 错误分布统计
+type ErrorBreakdownResponse struct {
+	Hours int                         `json:"hours"`
+	Total int64                       `json:"total"`
+	Items []model.ErrorBreakdownEntry `json:"items"`
+}
+
+// StatsHistoryResponse 每日汇总历史
+type StatsHistoryResponse struct {
+	Days  int               `json:"days"`
+	Since string            `json:"since"` // 起始日期（含），YYYY-MM-DD
+	Items []model.DailyStat `json:"items"`
+}
@@ -208,3 +208,170 @@ func TestChannelRequestValidate_RejectsInvalidProtocolTransformMode(t *testing.T
 		t.Fatalf("expected invalid mode error, got %v", err)
 	}
 }
+
+func TestChannelRequestValidate_AllowedMethods(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:           "test",
+		APIKey:         "sk-test",
+		URL:            "https://example.com",
+		Models:         []model.ModelEntry{{Model: "test-model"}},
+		AllowedMethods: []string{"post", " POST "},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.AllowedMethods) != 1 || req.AllowedMethods[0] != "POST" {
+		t.Fatalf("allowed_methods not normalized: %v", req.AllowedMethods)
+	}
+	if cfg := req.ToConfig(); !cfg.AllowsMethod("POST") || cfg.AllowsMethod("GET") {
+		t.Fatalf("ToConfig lost allowed_methods: %v", cfg.AllowedMethods)
+	}
+
+	req.AllowedMethods = []string{"CONNECT"}
+	err := req.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid allowed_methods") {
+		t.Fatalf("expected invalid allowed_methods error, got %v", err)
+	}
+}
+
+func TestChannelRequestValidate_SuccessCodes(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:         "test",
+		APIKey:       "sk-test",
+		URL:          "https://example.com",
+		Models:       []model.ModelEntry{{Model: "test-model"}},
+		SuccessCodes: " 200-299, 404 ",
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.SuccessCodes != "200-299,404" {
+		t.Fatalf("success_codes not normalized: %q", req.SuccessCodes)
+	}
+	if cfg := req.ToConfig(); !cfg.IsSuccessStatus(404) || cfg.IsSuccessStatus(500) {
+		t.Fatalf("ToConfig lost success_codes: %q", cfg.SuccessCodes)
+	}
+
+	req.SuccessCodes = "299-200"
+	err := req.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid success_codes") {
+		t.Fatalf("expected invalid success_codes error, got %v", err)
+	}
+}
+
+func TestChannelRequestValidate_ActiveSchedule(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:           "test",
+		APIKey:         "sk-test",
+		URL:            "https://example.com",
+		Models:         []model.ModelEntry{{Model: "test-model"}},
+		ActiveSchedule: "  Mon-Fri   22:00-08:00 ",
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.ActiveSchedule != "Mon-Fri 22:00-08:00" {
+		t.Fatalf("active_schedule not normalized: %q", req.ActiveSchedule)
+	}
+	if cfg := req.ToConfig(); cfg.ActiveSchedule != req.ActiveSchedule {
+		t.Fatalf("ToConfig lost active_schedule: %q", cfg.ActiveSchedule)
+	}
+
+	req.ActiveSchedule = "Mon-Fri"
+	err := req.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid active_schedule") {
+		t.Fatalf("expected invalid active_schedule error, got %v", err)
+	}
+}
+
+func TestChannelRequestValidate_BlockedModels(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:          "test",
+		APIKey:        "sk-test",
+		URL:           "https://example.com",
+		Models:        []model.ModelEntry{{Model: "test-model"}},
+		BlockedModels: []string{" old-model ", "", "OLD-MODEL"},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.BlockedModels) != 1 || req.BlockedModels[0] != "old-model" {
+		t.Fatalf("blocked_models not normalized: %v", req.BlockedModels)
+	}
+	if cfg := req.ToConfig(); len(cfg.BlockedModels) != 1 {
+		t.Fatalf("ToConfig lost blocked_models: %v", cfg.BlockedModels)
+	}
+
+	req.BlockedModels = []string{"a,b"}
+	err := req.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid blocked_models") {
+		t.Fatalf("expected invalid blocked_models error, got %v", err)
+	}
+}
+
+func TestChannelRequestValidate_RetryErrorSubstrings(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:                 "test",
+		APIKey:               "sk-test",
+		URL:                  "https://example.com",
+		Models:               []model.ModelEntry{{Model: "test-model"}},
+		RetryErrorSubstrings: []string{" overloaded ", "", "OVERLOADED", "try again, later"},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.RetryErrorSubstrings) != 2 || req.RetryErrorSubstrings[1] != "try again, later" {
+		t.Fatalf("retry_error_substrings not normalized: %v", req.RetryErrorSubstrings)
+	}
+	if cfg := req.ToConfig(); len(cfg.RetryErrorSubstrings) != 2 {
+		t.Fatalf("ToConfig lost retry_error_substrings: %v", cfg.RetryErrorSubstrings)
+	}
+
+	req.RetryErrorSubstrings = []string{"a\nb"}
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "invalid retry_error_substrings") {
+		t.Fatalf("expected invalid retry_error_substrings error, got %v", err)
+	}
+	req.RetryErrorSubstrings = []string{strings.Repeat("x", maxRetryErrorSubstringsLength+1)}
+	if err := req.Validate(); err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Fatalf("expected too long error, got %v", err)
+	}
+}
+
+func TestChannelRequestValidate_StripHeaders(t *testing.T) {
+	t.Parallel()
+
+	req := ChannelRequest{
+		Name:         "test",
+		APIKey:       "sk-test",
+		URL:          "https://example.com",
+		Models:       []model.ModelEntry{{Model: "test-model"}},
+		StripHeaders: []string{" Cookie ", "", "cookie", "X-Internal-*"},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.StripHeaders) != 2 || req.StripHeaders[0] != "Cookie" {
+		t.Fatalf("strip_headers not normalized: %v", req.StripHeaders)
+	}
+	if cfg := req.ToConfig(); len(cfg.StripHeaders) != 2 {
+		t.Fatalf("ToConfig lost strip_headers: %v", cfg.StripHeaders)
+	}
+
+	for _, bad := range []string{"*", "Bad Header", "a,b", "X-*-Foo"} {
+		req.StripHeaders = []string{bad}
+		err := req.Validate()
+		if err == nil || !strings.Contains(err.Error(), "invalid strip_headers") {
+			t.Fatalf("%q: expected invalid strip_headers error, got %v", bad, err)
+		}
+	}
+}
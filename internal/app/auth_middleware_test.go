@@ -50,6 +50,37 @@ func TestRequireAPIAuth_XAPIKey(t *testing.T) {
 	}
 }
 
+func TestRequireAPIAuth_XAPIKeyWithEmptyOrForeignAuthorization(t *testing.T) {
+	t.Parallel()
+	svc := newTestAuthService(t)
+	injectAPIToken(svc, "key-native", 0, 2)
+
+	for _, authHeader := range []string{"Bearer ", "Bearer   ", "Basic dXNlcjpwYXNz"} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", authHeader)
+		req.Header.Set("x-api-key", "key-native")
+
+		w := runMiddleware(t, svc.RequireAPIAuth(), req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Authorization=%q: expected x-api-key fallback 200, got %d: %s", authHeader, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRequireAPIAuth_BearerSchemeCaseInsensitive(t *testing.T) {
+	t.Parallel()
+	svc := newTestAuthService(t)
+	injectAPIToken(svc, "sk-lower", 0, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "bearer sk-lower")
+
+	w := runMiddleware(t, svc.RequireAPIAuth(), req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestRequireAPIAuth_GoogleKey(t *testing.T) {
 	t.Parallel()
 	svc := newTestAuthService(t)
@@ -241,6 +272,52 @@ func TestRequireAPIAuth_TokenConcurrencyLimit(t *testing.T) {
 	}
 }
 
+func TestRequireAPIAuth_TokenRPMLimit(t *testing.T) {
+	t.Parallel()
+	svc := newTestAuthService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.tokenRPMLimiter = newChannelRPMLimiter(func() time.Time { return now })
+	injectAPIToken(svc, "rpm-token", 0, 31)
+	injectAPIToken(svc, "rpm-other", 0, 32)
+
+	svc.authTokensMux.Lock()
+	svc.authTokenRPMLimits[model.HashToken("rpm-token")] = 2
+	svc.authTokensMux.Unlock()
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return runMiddleware(t, svc.RequireAPIAuth(), req)
+	}
+
+	for i := range 2 {
+		if w := send("rpm-token"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d: %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	now = now.Add(15 * time.Second)
+	w := send("rpm-token")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "token_rate_limit_exceeded") {
+		t.Fatalf("expected token_rate_limit_exceeded in response: %s", w.Body.String())
+	}
+	if got := w.Header().Get("Retry-After"); got != "45" {
+		t.Fatalf("Retry-After=%q, want 45", got)
+	}
+
+	if w := send("rpm-other"); w.Code != http.StatusOK {
+		t.Fatalf("expected other token to pass, got %d: %s", w.Code, w.Body.String())
+	}
+
+	now = now.Add(46 * time.Second)
+	if w := send("rpm-token"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after window slides, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestRequireAPIAuth_TokenConcurrencyLimit_AppliesImmediatelyAfterUpdate(t *testing.T) {
 	t.Parallel()
 
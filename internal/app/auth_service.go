@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,7 @@ type AuthService struct {
 	authTokenCostLimits map[string]tokenCostLimit           // Token哈希 → 费用限额状态（仅限额>0的令牌）
 	authTokenMaxConns   map[string]int                      // Token哈希 → 最大并发请求数（0=无限制）
 	authTokenActiveReqs map[string]int                      // Token哈希 → 当前进行中请求数
+	authTokenRPMLimits  map[string]int                      // Token哈希 → 每分钟最大请求数（0=无限制）
 	authTokensMux       sync.RWMutex                        // 并发保护（支持热更新）
 
 	// 数据库依赖（用于热更新令牌）
@@ -53,6 +55,7 @@ type AuthService struct {
 	// 速率限制（防暴力破解）
 	loginRateLimiter       *util.LoginRateLimiter
 	apiTokenSessionLimiter *apiTokenSessionLimiter
+	tokenRPMLimiter        *channelRPMLimiter // 按 Token ID 计数，复用渠道 RPM 滑动窗口
 
 	// 异步更新 last_used_at（受控 worker，避免 goroutine 泄漏）
 	lastUsedCh chan string    // tokenHash 更新队列
@@ -93,8 +96,10 @@ func NewAuthService(
 		authTokenCostLimits:    make(map[string]tokenCostLimit),
 		authTokenMaxConns:      make(map[string]int),
 		authTokenActiveReqs:    make(map[string]int),
+		authTokenRPMLimits:     make(map[string]int),
 		loginRateLimiter:       loginRateLimiter,
 		apiTokenSessionLimiter: newAPITokenSessionLimiter(nil),
+		tokenRPMLimiter:        newChannelRPMLimiter(nil),
 		store:                  store,
 		lastUsedCh:             make(chan string, 256), // 带缓冲，避免阻塞请求
 		done:                   make(chan struct{}),
@@ -244,6 +249,7 @@ func (s *AuthService) isActiveAuthTokenID(tokenID int64) bool {
 func (s *AuthService) CleanExpiredTokens() {
 	now := time.Now()
 	s.apiTokenSessionLimiter.cleanup()
+	s.tokenRPMLimiter.CleanupExpired()
 
 	// 使用快照模式避免长时间持锁
 	s.tokensMux.RLock()
@@ -302,6 +308,21 @@ func (s *AuthService) RequireWebAuth() gin.HandlerFunc {
 	}
 }
 
+// IsAdminSession 判断 token 是否为有效的管理员 Web 会话
+func (s *AuthService) IsAdminSession(token string) bool {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return false
+	}
+	session, ok := s.webSession(token)
+	return ok && session.Role == model.WebRoleAdmin
+}
+
+// webSessionID 返回会话 Token 哈希的短前缀，作为审计日志中的会话标识
+func webSessionID(token string) string {
+	return model.HashToken(token)[:12]
+}
+
 // RequireAdminAuth accepts only administrator web sessions.
 func (s *AuthService) RequireAdminAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -323,7 +344,7 @@ func (s *AuthService) RequireAdminAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		c.Set(webIdentityContextKey, WebIdentity{Role: session.Role})
+		c.Set(webIdentityContextKey, WebIdentity{Role: session.Role, SessionID: webSessionID(token)})
 		c.Next()
 	}
 }
@@ -349,6 +370,17 @@ func (s *AuthService) RequireWebAPITokenProxyAuth() gin.HandlerFunc {
 			return
 		}
 
+		if reservation, limit := s.reserveTokenRPM(tokenHash, identity.AuthTokenID); !reservation.allowed {
+			setRetryAfterHeader(c, reservation.retryAfter)
+			RespondErrorWithData(c, http.StatusTooManyRequests, "Token rate limit exceeded", gin.H{
+				"message": fmt.Sprintf("Token rate limit exceeded: %d requests per minute", limit),
+				"type":    "rate_limit_error",
+				"code":    "token_rate_limit_exceeded",
+			})
+			c.Abort()
+			return
+		}
+
 		releaseTokenSlot, activeConns, maxConns, acquired := s.prepareAPIIdentity(c, tokenHash, identity.AuthTokenID)
 		if !acquired {
 			RespondErrorWithData(c, http.StatusTooManyRequests, "Token concurrency limit exceeded", gin.H{
@@ -397,6 +429,16 @@ func (s *AuthService) resolveAuthToken(token string) (tokenHash string, expiresA
 	return tokenHash, expiresAt, tokenID, exists
 }
 
+// parseBearerToken 从 Authorization 头解析 Bearer 令牌，值为空时返回 false
+func parseBearerToken(authHeader string) (string, bool) {
+	scheme, value, ok := strings.Cut(strings.TrimSpace(authHeader), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	value = strings.TrimSpace(value)
+	return value, value != ""
+}
+
 // RequireAPIAuth API 认证中间件（代理 API 使用）
 // [FIX] 2025-12: 添加过期时间校验，支持懒惰剔除过期令牌
 func (s *AuthService) RequireAPIAuth() gin.HandlerFunc {
@@ -415,19 +457,16 @@ func (s *AuthService) RequireAPIAuth() gin.HandlerFunc {
 		var token string
 		var tokenFound bool
 
-		// 检查 Authorization 头（Bearer token）
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			const prefix = "Bearer "
-			if strings.HasPrefix(authHeader, prefix) {
-				token = strings.TrimPrefix(authHeader, prefix)
-				tokenFound = true
-			}
+		// 检查 Authorization 头（Bearer token，scheme 大小写不敏感）
+		// 空 Bearer 值视为未提供，继续回退到 x-api-key（部分 Anthropic SDK 会同时带空 Authorization）
+		if bearer, ok := parseBearerToken(c.GetHeader("Authorization")); ok {
+			token = bearer
+			tokenFound = true
 		}
 
-		// 检查 X-API-Key 头
+		// 检查 X-API-Key 头（Anthropic 原生客户端格式）
 		if !tokenFound {
-			apiKey := c.GetHeader("X-API-Key")
+			apiKey := strings.TrimSpace(c.GetHeader("X-API-Key"))
 			if apiKey != "" {
 				token = apiKey
 				tokenFound = true
@@ -478,6 +517,7 @@ func (s *AuthService) RequireAPIAuth() gin.HandlerFunc {
 			delete(s.authTokenChannels, tokenHash)
 			delete(s.authTokenCostLimits, tokenHash)
 			delete(s.authTokenMaxConns, tokenHash)
+			delete(s.authTokenRPMLimits, tokenHash)
 			s.authTokensMux.Unlock()
 			if tokenID > 0 {
 				if err := s.revokeWebSessions([]int64{tokenID}); err != nil {
@@ -490,6 +530,19 @@ func (s *AuthService) RequireAPIAuth() gin.HandlerFunc {
 			return
 		}
 
+		if reservation, limit := s.reserveTokenRPM(tokenHash, tokenID); !reservation.allowed {
+			setRetryAfterHeader(c, reservation.retryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": gin.H{
+					"message": fmt.Sprintf("Token rate limit exceeded: %d requests per minute", limit),
+					"type":    "rate_limit_error",
+					"code":    "token_rate_limit_exceeded",
+				},
+			})
+			c.Abort()
+			return
+		}
+
 		releaseTokenSlot, activeConns, maxConns, acquired := s.prepareAPIIdentity(c, tokenHash, tokenID)
 		if !acquired {
 			c.JSON(http.StatusTooManyRequests, gin.H{
@@ -556,8 +609,7 @@ func (s *AuthService) HandleLogin(c *gin.Context) {
 		}
 		allowed, retryAfter := s.apiTokenSessionLimiter.allow(tokenID)
 		if !allowed {
-			retryAfterSeconds := int((retryAfter + time.Second - 1) / time.Second)
-			c.Header("Retry-After", fmt.Sprintf("%d", retryAfterSeconds))
+			retryAfterSeconds := setRetryAfterHeader(c, retryAfter)
 			RespondErrorWithData(c, http.StatusTooManyRequests, "Too many API Token web sessions", gin.H{
 				"message":             fmt.Sprintf("API Token web session limit exceeded. Please retry in %d seconds.", retryAfterSeconds),
 				"retry_after_seconds": retryAfterSeconds,
@@ -665,6 +717,7 @@ func (s *AuthService) ReloadAuthTokens() error {
 	newTokenChannels := make(map[string]model.ChannelRestriction, len(tokens))
 	newTokenCostLimits := make(map[string]tokenCostLimit, len(tokens))
 	newTokenMaxConns := make(map[string]int, len(tokens))
+	newTokenRPMLimits := make(map[string]int, len(tokens))
 	for _, t := range tokens {
 		if err := t.ValidateUsageLimits(); err != nil {
 			return fmt.Errorf("invalid auth token %d: %w", t.ID, err)
@@ -699,6 +752,9 @@ func (s *AuthService) ReloadAuthTokens() error {
 		if t.MaxConcurrency > 0 {
 			newTokenMaxConns[t.Token] = t.MaxConcurrency
 		}
+		if t.RPMLimit > 0 {
+			newTokenRPMLimits[t.Token] = t.RPMLimit
+		}
 	}
 
 	// 原子替换（避免读写竞争）
@@ -729,6 +785,7 @@ func (s *AuthService) ReloadAuthTokens() error {
 	s.authTokenChannels = newTokenChannels
 	s.authTokenCostLimits = newTokenCostLimits
 	s.authTokenMaxConns = newTokenMaxConns
+	s.authTokenRPMLimits = newTokenRPMLimits
 	s.authTokensMux.Unlock()
 	if err := s.revokeWebSessions(revokedTokenIDs); err != nil {
 		return fmt.Errorf("revoke web sessions: %w", err)
@@ -848,6 +905,21 @@ func (s *AuthService) IsChannelAllowed(tokenHash string, channelID int64) bool {
 	return restriction.Allows(channelID)
 }
 
+// setRetryAfterHeader 写入 Retry-After（向上取整到秒，至少 1 秒），返回写入的秒数
+func setRetryAfterHeader(c *gin.Context, retryAfter time.Duration) int {
+	seconds := max(int((retryAfter+time.Second-1)/time.Second), 1)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	return seconds
+}
+
+// reserveTokenRPM 在令牌 RPM 窗口内预占一次请求；返回预占结果与生效的限制值
+func (s *AuthService) reserveTokenRPM(tokenHash string, tokenID int64) (channelRPMReservation, int) {
+	s.authTokensMux.RLock()
+	limit := s.authTokenRPMLimits[tokenHash]
+	s.authTokensMux.RUnlock()
+	return s.tokenRPMLimiter.reserve(tokenID, limit), limit
+}
+
 func (s *AuthService) acquireTokenConcurrencySlot(tokenHash string) (release func(), active, limit int, ok bool) {
 	if tokenHash == "" {
 		return func() {}, 0, 0, true
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxKeysPerChannel 单渠道 Key 数量默认上限（防止误粘贴超大 Key 列表拖慢选择）
+const defaultMaxKeysPerChannel = 100
+
+// parseMaxKeysPerChannel 解析 CCLOAD_MAX_KEYS_PER_CHANNEL（0=不限；空值或非法值使用默认值）
+func parseMaxKeysPerChannel(raw string) int {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultMaxKeysPerChannel
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_MAX_KEYS_PER_CHANNEL=%s（必须为非负整数），使用默认值 %d", raw, defaultMaxKeysPerChannel)
+		return defaultMaxKeysPerChannel
+	}
+	return n
+}
+
+// channelKeyLimitError 校验单渠道 Key 数量（limit=0 不限）。
+// 仅在超过上限且多于当前数量时拒绝：上限下调前已超限的渠道仍可保存不增加 Key 的修改。
+func channelKeyLimitError(count, current, limit int) error {
+	if limit > 0 && count > limit && count > current {
+		return fmt.Errorf("too many api keys: %d (max %d per channel, see CCLOAD_MAX_KEYS_PER_CHANNEL)", count, limit)
+	}
+	return nil
+}
+
+// checkChannelKeyLimit 校验渠道 Key 数量由 current 变为 count 是否超过上限（新建渠道 current=0）
+func (s *Server) checkChannelKeyLimit(count, current int) error {
+	return channelKeyLimitError(count, current, s.maxKeysPerChannel)
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseMaxKeysPerChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw  string
+		want int
+	}{
+		{"", defaultMaxKeysPerChannel},
+		{"  ", defaultMaxKeysPerChannel},
+		{"0", 0},
+		{"500", 500},
+		{"-1", defaultMaxKeysPerChannel},
+		{"abc", defaultMaxKeysPerChannel},
+	}
+	for _, tt := range tests {
+		if got := parseMaxKeysPerChannel(tt.raw); got != tt.want {
+			t.Fatalf("parseMaxKeysPerChannel(%q) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestChannelKeyLimit_CreateAndReplace(t *testing.T) {
+	srv := newInMemoryServer(t)
+	srv.maxKeysPerChannel = 2
+	ctx := context.Background()
+
+	payload := ChannelRequest{
+		Name:        "too-many-keys",
+		APIKey:      "sk-1,sk-2,sk-3",
+		URL:         "https://api.example.com",
+		ChannelType: "openai",
+		Models:      []model.ModelEntry{{Model: "gpt-4o"}},
+		Enabled:     true,
+	}
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels", payload))
+	srv.handleCreateChannel(c)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "too many api keys") {
+		t.Fatalf("create over limit: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// 去重后未超限：允许创建
+	payload.APIKey = "sk-1,sk-2,sk-1"
+	c, w = newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/channels", payload))
+	srv.handleCreateChannel(c)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create within limit: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	configs, err := srv.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d configs)", err, len(configs))
+	}
+	id := strconv.FormatInt(configs[0].ID, 10)
+	c, w = newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+id+"/keys", ReplaceChannelKeysRequest{
+		Keys: []ChannelAPIKeyRequest{{APIKey: "sk-a"}, {APIKey: "sk-b"}, {APIKey: "sk-c"}},
+	}))
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	srv.HandleReplaceChannelKeys(c)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "too many api keys") {
+		t.Fatalf("replace over limit: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// 0=不限
+	srv.maxKeysPerChannel = 0
+	if err := srv.checkChannelKeyLimit(10000, 0); err != nil {
+		t.Fatalf("unlimited: %v", err)
+	}
+}
+
+func TestChannelKeyLimit_ExistingOverLimitChannel(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	// 上限下调前创建的渠道：3 个 Key
+	created, err := srv.store.CreateConfig(ctx, &model.Config{
+		Name:         "legacy-many-keys",
+		URL:          "https://api.example.com",
+		ChannelType:  "openai",
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+		Enabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: created.ID, KeyIndex: 0, APIKey: "sk-1", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: created.ID, KeyIndex: 1, APIKey: "sk-2", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: created.ID, KeyIndex: 2, APIKey: "sk-3", KeyStrategy: model.KeyStrategySequential},
+	}); err != nil {
+		t.Fatalf("CreateAPIKeysBatch: %v", err)
+	}
+	srv.maxKeysPerChannel = 2
+	id := strconv.FormatInt(created.ID, 10)
+
+	update := func(apiKey string) *httptest.ResponseRecorder {
+		t.Helper()
+		c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+id, ChannelRequest{
+			Name:        "legacy-many-keys",
+			APIKey:      apiKey,
+			URL:         "https://api.example.com",
+			ChannelType: "openai",
+			Models:      []model.ModelEntry{{Model: "gpt-4o"}},
+			Enabled:     true,
+		}))
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		srv.handleUpdateChannel(c, created.ID)
+		return w
+	}
+
+	// 不增加 Key 数量的修改允许保存
+	if w := update("sk-1,sk-2,sk-3"); w.Code != http.StatusOK {
+		t.Fatalf("update keeping key count: status=%d body=%s", w.Code, w.Body.String())
+	}
+	if w := update("sk-1,sk-2,sk-3,sk-4"); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "too many api keys") {
+		t.Fatalf("update growing key count: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPut, "/admin/channels/"+id+"/keys", ReplaceChannelKeysRequest{
+		Keys: []ChannelAPIKeyRequest{{APIKey: "sk-a"}, {APIKey: "sk-b"}, {APIKey: "sk-c"}},
+	}))
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	srv.HandleReplaceChannelKeys(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("rotate keeping key count: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// CSV 导入更新同名渠道：同样以当前 Key 数为基准
+	importCSV := func(apiKey string) ChannelImportSummary {
+		t.Helper()
+		csvContent := "name,url,models,channel_type,api_key\nlegacy-many-keys,https://api.example.com,gpt-4o,openai,\"" + apiKey + "\"\n"
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "import.csv")
+		if err != nil {
+			t.Fatalf("CreateFormFile: %v", err)
+		}
+		if _, err := io.WriteString(part, csvContent); err != nil {
+			t.Fatalf("write csv: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("close writer: %v", err)
+		}
+		req := newRequest(http.MethodPost, "/admin/channels/import", bytes.NewReader(body.Bytes()))
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		c, w := newTestContext(t, req)
+		srv.HandleImportChannelsCSV(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("import: status=%d body=%s", w.Code, w.Body.String())
+		}
+		var summary ChannelImportSummary
+		mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+		return summary
+	}
+	if summary := importCSV("sk-x,sk-y,sk-z"); summary.Updated != 1 || summary.Skipped != 0 {
+		t.Fatalf("csv import keeping key count should update: %+v", summary)
+	}
+	if summary := importCSV("sk-x,sk-y,sk-z,sk-w"); summary.Updated != 0 || summary.Skipped != 1 {
+		t.Fatalf("csv import growing key count should be skipped: %+v", summary)
+	}
+}
+
+func TestChannelKeyLimit_RestoreWarnsInsteadOfDropping(t *testing.T) {
+	srv := newInMemoryServer(t)
+	srv.maxKeysPerChannel = 2
+
+	backup := ConfigBackup{
+		SchemaVersion: configBackupSchemaVersion,
+		Channels: []model.ChannelWithKeys{{
+			Config: &model.Config{
+				Name:         "restored-many-keys",
+				URL:          "https://api.example.com",
+				ChannelType:  "openai",
+				ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+				Enabled:      true,
+			},
+			APIKeys: []model.APIKey{{APIKey: "sk-1"}, {APIKey: "sk-2"}, {APIKey: "sk-3"}},
+		}},
+	}
+	c, w := newTestContext(t, newJSONRequest(t, http.MethodPost, "/admin/restore", backup))
+	srv.HandleConfigRestore(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status=%d body=%s", w.Code, w.Body.String())
+	}
+	var summary ConfigRestoreSummary
+	mustUnmarshalAPIResponseData(t, w.Body.Bytes(), &summary)
+	if summary.ChannelsCreated != 1 || summary.KeysRestored != 3 || len(summary.Errors) != 0 || len(summary.Warnings) != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+const (
+	// selectionRoundRobin 默认：同优先级渠道按 KeyCount 平滑加权轮询
+	selectionRoundRobin = "round_robin"
+	// selectionLRU 同优先级渠道中优先选择最久未使用的渠道
+	selectionLRU = "lru"
+)
+
+// parseSelectionStrategy 解析 CCLOAD_SELECTION（空值或非法值回退轮询）
+func parseSelectionStrategy(raw string) string {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case "", selectionRoundRobin:
+		return selectionRoundRobin
+	case selectionLRU:
+		return selectionLRU
+	default:
+		log.Printf("[WARN] 无效的 CCLOAD_SELECTION=%s（可选 round_robin/lru），使用默认轮询", raw)
+		return selectionRoundRobin
+	}
+}
+
+// channelLastUsedTracker 在内存中记录渠道最后使用时间。
+// latest 供 LRU 选择实时读取（选中即更新，避免并发请求在成功返回前扎堆同一渠道）；
+// pending 仅记录成功使用，由后台协程批量写入 channels.last_used_at，重启后从渠道配置恢复。
+type channelLastUsedTracker struct {
+	mu      sync.Mutex
+	latest  map[int64]int64 // 渠道ID → Unix毫秒
+	pending map[int64]int64
+}
+
+func newChannelLastUsedTracker() *channelLastUsedTracker {
+	return &channelLastUsedTracker{
+		latest:  make(map[int64]int64),
+		pending: make(map[int64]int64),
+	}
+}
+
+// Touch 记录渠道的成功使用时间并加入待落库队列（nil 安全）
+func (t *channelLastUsedTracker) Touch(channelID int64, at time.Time) {
+	if t == nil || channelID <= 0 {
+		return
+	}
+	ms := at.UnixMilli()
+	t.mu.Lock()
+	if ms > t.latest[channelID] {
+		t.latest[channelID] = ms
+	}
+	if ms > t.pending[channelID] {
+		t.pending[channelID] = ms
+	}
+	t.mu.Unlock()
+}
+
+// MarkSelected 记录渠道被选中发往上游的时间（仅更新内存，不落库；nil 安全）
+func (t *channelLastUsedTracker) MarkSelected(channelID int64, at time.Time) {
+	if t == nil || channelID <= 0 {
+		return
+	}
+	ms := at.UnixMilli()
+	t.mu.Lock()
+	if ms > t.latest[channelID] {
+		t.latest[channelID] = ms
+	}
+	t.mu.Unlock()
+}
+
+// LastUsed 返回渠道最后使用时间：内存记录与持久化值取较新者（cfg.LastUsedAt 可能来自缓存，存在滞后）
+func (t *channelLastUsedTracker) LastUsed(cfg *model.Config) int64 {
+	if cfg == nil {
+		return 0
+	}
+	if t == nil {
+		return cfg.LastUsedAt
+	}
+	t.mu.Lock()
+	ms := t.latest[cfg.ID]
+	t.mu.Unlock()
+	return max(ms, cfg.LastUsedAt)
+}
+
+// drain 取出并清空待落库的更新
+func (t *channelLastUsedTracker) drain() map[int64]int64 {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) == 0 {
+		return nil
+	}
+	pending := t.pending
+	t.pending = make(map[int64]int64, len(pending))
+	return pending
+}
+
+// flushChannelLastUsed 将内存中的渠道使用时间批量写入数据库（失败仅记录日志，不重试）
+func (s *Server) flushChannelLastUsed(ctx context.Context) {
+	updates := s.channelLastUsed.drain()
+	if len(updates) == 0 {
+		return
+	}
+	if err := s.store.UpdateChannelsLastUsed(ctx, updates); err != nil {
+		log.Printf("[WARN] 批量更新渠道最后使用时间失败（%d 条）: %v", len(updates), err)
+	}
+}
+
+// sortByLeastRecentlyUsed 同优先级组内按最后使用时间升序排列（从未使用的最先，时间相同保持原顺序）
+func (s *Server) sortByLeastRecentlyUsed(group []*model.Config) {
+	lastUsed := make(map[int64]int64, len(group))
+	for _, cfg := range group {
+		lastUsed[cfg.ID] = s.channelLastUsed.LastUsed(cfg)
+	}
+	sort.SliceStable(group, func(i, j int) bool {
+		return lastUsed[group[i].ID] < lastUsed[group[j].ID]
+	})
+}
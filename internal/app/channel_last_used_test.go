@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestParseSelectionStrategy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":            selectionRoundRobin,
+		"round_robin": selectionRoundRobin,
+		" LRU ":       selectionLRU,
+		"random":      selectionRoundRobin,
+	}
+	for raw, want := range cases {
+		if got := parseSelectionStrategy(raw); got != want {
+			t.Errorf("parseSelectionStrategy(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestBalanceSamePriorityChannels_LRUPrefersLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{
+		channelBalancer:   NewSmoothWeightedRR(),
+		channelLastUsed:   newChannelLastUsedTracker(),
+		selectionStrategy: selectionLRU,
+	}
+	base := time.Now()
+	server.channelLastUsed.Touch(1, base)
+	server.channelLastUsed.Touch(2, base.Add(time.Second))
+
+	channels := []*model.Config{
+		{ID: 1, Name: "a", Priority: 10, KeyCount: 1},
+		{ID: 2, Name: "b", Priority: 10, KeyCount: 1},
+		{ID: 3, Name: "c", Priority: 10, KeyCount: 1, LastUsedAt: base.Add(-time.Hour).UnixMilli()}, // 仅有持久化值
+		{ID: 4, Name: "d", Priority: 10, KeyCount: 1},                                               // 从未使用
+		{ID: 5, Name: "low", Priority: 5, KeyCount: 1},
+	}
+
+	got := server.balanceSamePriorityChannels(channels, nil, base)
+	want := []string{"d", "c", "a", "b", "low"}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Fatalf("order = %v, want %v", configNames(got), want)
+		}
+	}
+
+	// 使用 d 后，它应排到同优先级组末尾
+	server.channelLastUsed.Touch(4, base.Add(2*time.Second))
+	got = server.balanceSamePriorityChannels(channels, nil, base)
+	if got[0].Name != "c" || got[3].Name != "d" {
+		t.Fatalf("order after touch = %v", configNames(got))
+	}
+}
+
+func TestSortChannelsByHealth_LRUWithinEffectivePriorityGroup(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{
+		healthCache: &HealthCache{
+			config: model.HealthScoreConfig{Enabled: true},
+		},
+		channelBalancer:   NewSmoothWeightedRR(),
+		channelLastUsed:   newChannelLastUsedTracker(),
+		selectionStrategy: selectionLRU,
+	}
+	empty := make(map[int64]model.ChannelHealthStats)
+	server.healthCache.healthStats.Store(&empty)
+	server.channelLastUsed.Touch(1, time.Now())
+
+	channels := []*model.Config{
+		{ID: 1, Name: "recent", Priority: 10, KeyCount: 10},
+		{ID: 2, Name: "idle", Priority: 10, KeyCount: 1},
+	}
+	for range 5 {
+		if got := server.sortChannelsByHealth(channels, nil, time.Now()); got[0].Name != "idle" {
+			t.Fatalf("expected least recently used channel first, got %v", configNames(got))
+		}
+	}
+}
+
+func TestProxy_SuccessTouchesChannelLastUsed(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	before := time.Now().UnixMilli()
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	configs, err := env.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	if got := env.server.channelLastUsed.LastUsed(configs[0]); got < before {
+		t.Fatalf("expected in-memory last used >= %d, got %d", before, got)
+	}
+
+	env.server.flushChannelLastUsed(ctx)
+	cfg, err := env.store.GetConfig(ctx, configs[0].ID)
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if cfg.LastUsedAt < before {
+		t.Fatalf("expected persisted last_used_at >= %d, got %d", before, cfg.LastUsedAt)
+	}
+}
+
+func TestProxy_LRUMarksChannelOnDispatch(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad request"}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+	env.server.selectionStrategy = selectionLRU
+
+	before := time.Now().UnixMilli()
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code == http.StatusOK {
+		t.Fatalf("expected upstream failure, got 200")
+	}
+
+	configs, err := env.store.ListConfigs(context.Background())
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	if got := env.server.channelLastUsed.LastUsed(configs[0]); got < before {
+		t.Fatalf("expected dispatch to mark last used >= %d, got %d", before, got)
+	}
+	// 未成功的派发只影响内存中的 LRU 顺序，不落库
+	if pending := env.server.channelLastUsed.drain(); len(pending) != 0 {
+		t.Fatalf("failed dispatch must not be persisted, pending=%v", pending)
+	}
+}
+
+func configNames(configs []*model.Config) []string {
+	names := make([]string, len(configs))
+	for i, cfg := range configs {
+		names[i] = cfg.Name
+	}
+	return names
+}
@@ -47,6 +47,25 @@ func (l *channelRPMLimiter) allow(channelID int64, limit int) bool {
 	return l.reserve(channelID, limit).allowed
 }
 
+// exceeded 仅检查最近一分钟内的请求数是否已达上限（不记录本次请求）
+func (l *channelRPMLimiter) exceeded(id int64, limit int) bool {
+	if l == nil || id <= 0 || limit <= 0 {
+		return false
+	}
+	cutoff := l.now().Add(-time.Minute)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := 0
+	for _, ts := range l.requests[id] {
+		if ts.After(cutoff) {
+			count++
+		}
+	}
+	return count >= limit
+}
+
 func (l *channelRPMLimiter) RemoveChannel(channelID int64) {
 	if l == nil || channelID <= 0 {
 		return
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	modelpkg "ccLoad/internal/model"
+)
+
+// parseScheduleLocation 解析 CCLOAD_SCHEDULE_TZ（渠道启用时段未写时区时使用），空值或非法值回退服务器本地时区
+func parseScheduleLocation(raw string) *time.Location {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(raw)
+	if err != nil {
+		log.Printf("[WARN] 无效的 CCLOAD_SCHEDULE_TZ=%s，使用服务器本地时区", raw)
+		return time.Local
+	}
+	return loc
+}
+
+// filterInactiveScheduleChannels 过滤不在启用时段（active_schedule）内的渠道
+func (s *Server) filterInactiveScheduleChannels(channels []*modelpkg.Config, now time.Time) []*modelpkg.Config {
+	if s.scheduleLocation != nil {
+		now = now.In(s.scheduleLocation)
+	}
+	filtered := channels[:0:0]
+	for _, ch := range channels {
+		if ch.IsActiveAt(now) {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestParseScheduleLocation(t *testing.T) {
+	t.Parallel()
+
+	if got := parseScheduleLocation(""); got != time.Local {
+		t.Fatalf("empty = %v, want Local", got)
+	}
+	if got := parseScheduleLocation("Asia/Shanghai"); got.String() != "Asia/Shanghai" {
+		t.Fatalf("got %v", got)
+	}
+	if got := parseScheduleLocation("Nowhere/City"); got != time.Local {
+		t.Fatalf("invalid = %v, want Local", got)
+	}
+}
+
+func TestSelectCandidates_SkipsChannelsOutsideActiveSchedule(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	server := &Server{store: store, channelBalancer: NewSmoothWeightedRR(), scheduleLocation: time.UTC}
+	ctx := context.Background()
+
+	hour := time.Now().UTC().Hour()
+	active := fmt.Sprintf("%02d:00-%02d:00", (hour+23)%24, (hour+2)%24) // 前后留余量，避免跨整点抖动
+	inactive := fmt.Sprintf("%02d:00-%02d:00", (hour+6)%24, (hour+8)%24)
+	for _, cfg := range []*model.Config{
+		{Name: "always", URL: "https://a.example.com", Priority: 10, Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+		{Name: "now", URL: "https://b.example.com", Priority: 10, Enabled: true, ActiveSchedule: active, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+		{Name: "later", URL: "https://c.example.com", Priority: 10, Enabled: true, ActiveSchedule: inactive, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+	} {
+		if _, err := store.CreateConfig(ctx, cfg); err != nil {
+			t.Fatalf("CreateConfig: %v", err)
+		}
+	}
+
+	candidates, err := server.selectCandidatesByModelAndType(ctx, "gpt-4", "")
+	if err != nil {
+		t.Fatalf("selectCandidatesByModelAndType: %v", err)
+	}
+	names := configNames(candidates)
+	if len(names) != 2 || slices.Contains(names, "later") {
+		t.Fatalf("candidates = %v, want always+now", names)
+	}
+}
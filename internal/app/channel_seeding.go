@@ -0,0 +1,187 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/storage"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// EnvSeedChannels 启动时预置渠道：CSV/JSON 文件路径，或以 [ 开头的内联 JSON 数组
+	EnvSeedChannels = "CCLOAD_SEED_CHANNELS"
+
+	channelSeedTimeout = 30 * time.Second
+)
+
+// ChannelSeedResult 启动渠道预置结果
+type ChannelSeedResult struct {
+	Configured int
+	Created    int
+	Skipped    []string // 已存在（按名称）而跳过的渠道
+}
+
+// SeedChannelsFromEnv 按 CCLOAD_SEED_CHANNELS 预置渠道（未设置时为空操作）
+func SeedChannelsFromEnv(ctx context.Context, store storage.Store, maxKeysPerChannel int) (ChannelSeedResult, error) {
+	return SeedChannels(ctx, store, os.Getenv(EnvSeedChannels), maxKeysPerChannel)
+}
+
+// SeedChannels 幂等预置渠道：名称已存在的渠道一律跳过，不覆盖管理员后续的修改。
+// 字段与 CSV 导入一致（name/api_key/url/models 必填），复用 parseChannelImportRow 校验，
+// 经 ImportChannelBatch 单事务写入。任一条目无效即整体失败，避免部署时静默缺渠道。
+// 种子中的 id 会被忽略，防止与现有渠道 ID 冲突时覆盖其他渠道。
+// Key 数量超过 maxKeysPerChannel（0=不限）的条目同样视为无效。
+func SeedChannels(ctx context.Context, store storage.Store, raw string, maxKeysPerChannel int) (ChannelSeedResult, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ChannelSeedResult{}, nil
+	}
+
+	header, records, firstLine, err := loadChannelSeedRows(raw)
+	if err != nil {
+		return ChannelSeedResult{}, err
+	}
+	columnIndex := buildCSVColumnIndex(header)
+	for _, key := range []string{"name", "api_key", "url", "models"} {
+		if _, ok := columnIndex[key]; !ok {
+			return ChannelSeedResult{}, fmt.Errorf("missing required field: %s", key)
+		}
+	}
+
+	existing, err := store.ListConfigs(ctx)
+	if err != nil {
+		return ChannelSeedResult{}, fmt.Errorf("list channels: %w", err)
+	}
+	names := make(map[string]struct{}, len(existing))
+	for _, cfg := range existing {
+		names[cfg.Name] = struct{}{}
+	}
+
+	result := ChannelSeedResult{}
+	toCreate := make([]*model.ChannelWithKeys, 0, len(records))
+	for i, record := range records {
+		channel, errMsg, skip := parseChannelImportRow(record, columnIndex, firstLine+i, true, true, nil, nil)
+		if skip {
+			if errMsg != "" {
+				return result, fmt.Errorf("%s", errMsg)
+			}
+			continue
+		}
+		if err := channelKeyLimitError(len(channel.APIKeys), 0, maxKeysPerChannel); err != nil {
+			return result, fmt.Errorf("seed channel %s: %w", channel.Config.Name, err)
+		}
+		result.Configured++
+		if _, ok := names[channel.Config.Name]; ok {
+			result.Skipped = append(result.Skipped, channel.Config.Name)
+			continue
+		}
+		names[channel.Config.Name] = struct{}{}
+		channel.Config.ID = 0
+		toCreate = append(toCreate, channel)
+	}
+
+	if len(toCreate) == 0 {
+		return result, nil
+	}
+	created, _, err := store.ImportChannelBatch(ctx, toCreate)
+	if err != nil {
+		return result, fmt.Errorf("import seed channels: %w", err)
+	}
+	result.Created = created
+	return result, nil
+}
+
+// loadChannelSeedRows 读取种子数据并统一为 CSV 表头+记录；firstLine 为首条记录在错误信息中的行号
+func loadChannelSeedRows(raw string) (header []string, records [][]string, firstLine int, err error) {
+	data := []byte(raw)
+	isCSV := false
+	if !strings.HasPrefix(raw, "[") {
+		data, err = os.ReadFile(raw)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("read %s: %w", EnvSeedChannels, err)
+		}
+		isCSV = strings.EqualFold(filepath.Ext(raw), ".csv")
+	}
+
+	if isCSV {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("parse seed csv: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, nil, 0, fmt.Errorf("seed csv is empty")
+		}
+		return rows[0], rows[1:], 2, nil
+	}
+
+	var items []map[string]any
+	if err := sonic.Unmarshal(data, &items); err != nil {
+		return nil, nil, 0, fmt.Errorf("parse seed json: %w", err)
+	}
+	header, records = channelSeedJSONToRows(items)
+	return header, records, 1, nil
+}
+
+// channelSeedJSONToRows JSON 对象数组转为 CSV 形式（键即 CSV 列名）
+// 数组按逗号拼接（models: ["a","b"]），对象序列化为 JSON（model_redirects）
+func channelSeedJSONToRows(items []map[string]any) (header []string, records [][]string) {
+	seen := make(map[string]struct{})
+	for _, item := range items {
+		for key := range item {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				header = append(header, key)
+			}
+		}
+	}
+	slices.Sort(header)
+
+	records = make([][]string, 0, len(items))
+	for _, item := range items {
+		record := make([]string, len(header))
+		for i, key := range header {
+			record[i] = channelSeedValueString(item[key])
+		}
+		records = append(records, record)
+	}
+	return header, records
+}
+
+func channelSeedValueString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, elem := range val {
+			parts = append(parts, channelSeedValueString(elem))
+		}
+		return strings.Join(parts, ",")
+	default:
+		encoded, err := sonic.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/storage"
+)
+
+func newSeedTestStore(t *testing.T) storage.Store {
+	t.Helper()
+	store, err := storage.CreateSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("CreateSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSeedChannels_InlineJSONIsIdempotent(t *testing.T) {
+	store := newSeedTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.CreateConfig(ctx, &model.Config{
+		Name: "existing", URL: "https://old.example.com", ChannelType: "anthropic", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "claude-3"}},
+	}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+
+	raw := `[
+		{"id": 1, "name": "existing", "api_key": "sk-x", "url": "https://new.example.com", "models": "claude-3"},
+		{"name": "seeded", "api_key": ["sk-a", "sk-b"], "url": "https://api.example.com", "models": ["gpt-4o", "gpt-4o-mini"],
+		 "model_redirects": {"gpt-4o": "gpt-4o-2024-11-20"}, "channel_type": "openai", "priority": 5, "key_strategy": "round_robin"}
+	]`
+	result, err := SeedChannels(ctx, store, raw, defaultMaxKeysPerChannel)
+	if err != nil {
+		t.Fatalf("SeedChannels: %v", err)
+	}
+	if result.Configured != 2 || result.Created != 1 || len(result.Skipped) != 1 || result.Skipped[0] != "existing" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	configs, err := store.ListConfigs(ctx)
+	if err != nil {
+		t.Fatalf("ListConfigs: %v", err)
+	}
+	byName := map[string]*model.Config{}
+	for _, cfg := range configs {
+		byName[cfg.Name] = cfg
+	}
+	if byName["existing"].URL != "https://old.example.com" {
+		t.Fatalf("existing channel must not be overwritten, url=%q", byName["existing"].URL)
+	}
+	seeded := byName["seeded"]
+	if seeded == nil || seeded.ChannelType != "openai" || seeded.Priority != 5 || len(seeded.ModelEntries) != 2 {
+		t.Fatalf("seeded channel mismatch: %+v", seeded)
+	}
+	if seeded.ModelEntries[0].RedirectModel != "gpt-4o-2024-11-20" {
+		t.Fatalf("redirect not applied: %+v", seeded.ModelEntries)
+	}
+	keys, err := store.GetAPIKeys(ctx, seeded.ID)
+	if err != nil || len(keys) != 2 || keys[0].KeyStrategy != model.KeyStrategyRoundRobin {
+		t.Fatalf("seeded keys: %v %+v", err, keys)
+	}
+
+	result, err = SeedChannels(ctx, store, raw, defaultMaxKeysPerChannel)
+	if err != nil {
+		t.Fatalf("second SeedChannels: %v", err)
+	}
+	if result.Created != 0 || len(result.Skipped) != 2 {
+		t.Fatalf("second run should skip everything: %+v", result)
+	}
+}
+
+func TestSeedChannels_CSVFile(t *testing.T) {
+	store := newSeedTestStore(t)
+	path := filepath.Join(t.TempDir(), "channels.csv")
+	content := "\ufeffname,api_key,url,models,channel_type\n" +
+		"csv-one,sk-1,https://a.example.com,claude-3,anthropic\n" +
+		"\n" +
+		"csv-two,sk-2,https://b.example.com,gemini-2.5-pro,gemini\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	result, err := SeedChannels(context.Background(), store, path, defaultMaxKeysPerChannel)
+	if err != nil {
+		t.Fatalf("SeedChannels: %v", err)
+	}
+	if result.Configured != 2 || result.Created != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestSeedChannels_RejectsInvalidSeed(t *testing.T) {
+	store := newSeedTestStore(t)
+	for name, raw := range map[string]string{
+		"bad json":         `[{"name": `,
+		"missing column":   `[{"name": "x", "api_key": "sk", "url": "https://a.example.com"}]`,
+		"invalid url":      `[{"name": "x", "api_key": "sk", "url": "not a url", "models": "m"}]`,
+		"missing file":     filepath.Join(t.TempDir(), "absent.json"),
+		"bad channel type": `[{"name": "x", "api_key": "sk", "url": "https://a.example.com", "models": "m", "channel_type": "bogus"}]`,
+		"too many keys":    `[{"name": "x", "api_key": "sk-1,sk-2,sk-3", "url": "https://a.example.com", "models": "m"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := SeedChannels(context.Background(), store, raw, 2); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	configs, err := store.ListConfigs(context.Background())
+	if err != nil || len(configs) != 0 {
+		t.Fatalf("invalid seeds must not create channels: %v %d", err, len(configs))
+	}
+}
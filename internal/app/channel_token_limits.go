@@ -0,0 +1,150 @@
+package app
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/protocol"
+
+	"github.com/bytedance/sonic"
+)
+
+// estimateRequestInputTokens 复用 count_tokens 的估算逻辑计算请求输入 token 数。
+// 解析 messages/system/tools（Claude 与 OpenAI Chat 格式兼容）；
+// 无 messages 的请求（Responses/Gemini 等）按整个请求体文本保守估算。
+func estimateRequestInputTokens(body []byte) int {
+	var req CountTokensRequest
+	if err := sonic.Unmarshal(body, &req); err == nil && len(req.Messages) > 0 {
+		return estimateTokens(&req)
+	}
+	return estimateTextTokens(string(body))
+}
+
+// filterChannelsByInputTokens 跳过 max_input_tokens 小于估算输入的渠道。
+// 无渠道配置上限时不做估算；estimated 仅在实际估算时非零。
+func filterChannelsByInputTokens(cands []*model.Config, body []byte) (filtered []*model.Config, estimated int) {
+	limited := false
+	for _, cfg := range cands {
+		if cfg.MaxInputTokens > 0 {
+			limited = true
+			break
+		}
+	}
+	if !limited {
+		return cands, 0
+	}
+
+	estimated = estimateRequestInputTokens(body)
+	filtered = make([]*model.Config, 0, len(cands))
+	for _, cfg := range cands {
+		if cfg.MaxInputTokens > 0 && estimated > cfg.MaxInputTokens {
+			continue
+		}
+		filtered = append(filtered, cfg)
+	}
+	return filtered, estimated
+}
+
+// outputTokenSpec 客户端协议中表示输出上限的字段
+type outputTokenSpec struct {
+	fields []string
+	inject bool // 全部缺省时是否注入 fields[0]
+}
+
+// outputTokenFields 各客户端协议的输出上限字段。
+// OpenAI 仅封顶已携带的字段：max_tokens 与 max_completion_tokens 的支持因模型而异，注入任一都可能被上游拒绝。
+var outputTokenFields = map[protocol.Protocol]outputTokenSpec{
+	protocol.Anthropic: {fields: []string{"max_tokens"}, inject: true},
+	protocol.OpenAI:    {fields: []string{"max_tokens", "max_completion_tokens"}},
+	protocol.Codex:     {fields: []string{"max_output_tokens"}, inject: true},
+}
+
+// generationPathSuffixes 会产生输出 token 的生成类端点（embeddings/countTokens 等不在其列）
+var generationPathSuffixes = []string{
+	"/chat/completions",
+	"/responses",
+	"/messages",
+	":generateContent",
+	":streamGenerateContent",
+}
+
+// isGenerationPath 判断请求路径是否为生成类端点
+func isGenerationPath(requestPath string) bool {
+	for _, suffix := range generationPathSuffixes {
+		if strings.HasSuffix(requestPath, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// capMaxOutputTokens 按渠道 max_output_tokens 封顶生成类请求体中的输出上限：
+// 已有字段超限时改写为上限，未携带时按协议注入上限。Gemini 位于 generationConfig.maxOutputTokens。
+// 在协议转换前作用于客户端格式的请求体，由转换器映射到上游字段。
+func capMaxOutputTokens(body []byte, clientProtocol protocol.Protocol, requestPath string, limit int) ([]byte, bool) {
+	if limit <= 0 || !isGenerationPath(requestPath) {
+		return nil, false
+	}
+	var reqData map[string]json.RawMessage
+	if err := sonic.Unmarshal(body, &reqData); err != nil || reqData == nil {
+		return nil, false
+	}
+
+	changed := false
+	if clientProtocol == protocol.Gemini {
+		var genCfg map[string]json.RawMessage
+		if raw, ok := reqData["generationConfig"]; ok {
+			if err := sonic.Unmarshal(raw, &genCfg); err != nil {
+				return nil, false
+			}
+		}
+		if genCfg == nil {
+			genCfg = make(map[string]json.RawMessage, 1)
+		}
+		if !capOutputTokenFields(genCfg, outputTokenSpec{fields: []string{"maxOutputTokens"}, inject: true}, limit) {
+			return nil, false
+		}
+		raw, err := sonic.Marshal(genCfg)
+		if err != nil {
+			return nil, false
+		}
+		reqData["generationConfig"] = raw
+		changed = true
+	} else if spec, ok := outputTokenFields[clientProtocol]; ok {
+		changed = capOutputTokenFields(reqData, spec, limit)
+	}
+	if !changed {
+		return nil, false
+	}
+
+	modified, err := sonic.Marshal(reqData)
+	if err != nil {
+		return nil, false
+	}
+	return modified, true
+}
+
+// capOutputTokenFields 封顶已存在的字段；全部缺省且允许注入时注入 fields[0]。返回是否有改动。
+func capOutputTokenFields(data map[string]json.RawMessage, spec outputTokenSpec, limit int) bool {
+	limitRaw := json.RawMessage(strconv.Itoa(limit))
+	present, changed := false, false
+	for _, field := range spec.fields {
+		raw, ok := data[field]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		present = true
+		var value float64
+		if err := sonic.Unmarshal(raw, &value); err != nil || value > float64(limit) {
+			data[field] = limitRaw
+			changed = true
+		}
+	}
+	if !present && spec.inject {
+		data[spec.fields[0]] = limitRaw
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/protocol"
+)
+
+func TestProxy_ChannelTokenLimitsSkipAndCap(t *testing.T) {
+	t.Parallel()
+
+	var smallCalls atomic.Int64
+	var largeMaxTokens atomic.Value
+	small := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		smallCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"small","choices":[{"message":{"content":"small"}}]}`))
+	}))
+	defer small.Close()
+	large := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		largeMaxTokens.Store(body["max_tokens"])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"large","choices":[{"message":{"content":"large"}}]}`))
+	}))
+	defer large.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "small-ctx", models: "gpt-4"},
+		{name: "large-ctx", models: "gpt-4"},
+	}, map[int]string{0: small.URL, 1: large.URL})
+
+	ctx := context.Background()
+	configs, err := env.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 2 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	for _, cfg := range configs {
+		switch cfg.Name {
+		case "small-ctx":
+			cfg.MaxInputTokens = 50
+		case "large-ctx":
+			cfg.MaxInputTokens = 100000
+			cfg.MaxOutputTokens = 256
+		}
+		if _, err := env.store.UpdateConfig(ctx, cfg.ID, cfg); err != nil {
+			t.Fatalf("UpdateConfig: %v", err)
+		}
+	}
+	env.server.InvalidateChannelListCache()
+
+	longPrompt := strings.Repeat("hello world ", 200)
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":      "gpt-4",
+		"max_tokens": 4096,
+		"messages":   []map[string]string{{"role": "user", "content": longPrompt}},
+	}, nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "large") {
+		t.Fatalf("expected large channel response, got %d: %s", w.Code, w.Body.String())
+	}
+	if smallCalls.Load() != 0 {
+		t.Fatalf("small channel must be skipped, calls=%d", smallCalls.Load())
+	}
+	if got, _ := largeMaxTokens.Load().(float64); got != 256 {
+		t.Fatalf("upstream max_tokens=%v, want capped 256", largeMaxTokens.Load())
+	}
+
+	w = doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": strings.Repeat(longPrompt, 200)}},
+	}, nil)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "input_tokens_exceeded") {
+		t.Fatalf("expected 400 input_tokens_exceeded, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFilterChannelsByInputTokens_NoLimitsSkipsEstimate(t *testing.T) {
+	t.Parallel()
+
+	cands := []*model.Config{{ID: 1}, {ID: 2}}
+	got, estimated := filterChannelsByInputTokens(cands, []byte(`{"messages":[{"role":"user","content":"hi"}]}`))
+	if len(got) != 2 || estimated != 0 {
+		t.Fatalf("unexpected filter result: %d channels, estimated=%d", len(got), estimated)
+	}
+}
+
+func TestCapMaxOutputTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     string
+		protocol protocol.Protocol
+		path     string
+		want     string // 期望出现在结果中的片段；空表示不改写
+	}{
+		{"anthropic caps", `{"max_tokens":8192}`, protocol.Anthropic, "/v1/messages", `"max_tokens":1000`},
+		{"anthropic within limit", `{"max_tokens":500}`, protocol.Anthropic, "/v1/messages", ""},
+		{"anthropic injects", `{"model":"m"}`, protocol.Anthropic, "/v1/messages", `"max_tokens":1000`},
+		{"openai caps max_tokens", `{"max_tokens":5000}`, protocol.OpenAI, "/v1/chat/completions", `"max_tokens":1000`},
+		{"openai completion tokens", `{"max_completion_tokens":5000}`, protocol.OpenAI, "/v1/chat/completions", `"max_completion_tokens":1000`},
+		{"openai does not inject", `{"model":"m"}`, protocol.OpenAI, "/v1/chat/completions", ""},
+		{"codex injects", `{"input":"hi"}`, protocol.Codex, "/v1/responses", `"max_output_tokens":1000`},
+		{"gemini nested", `{"generationConfig":{"temperature":0.5,"maxOutputTokens":9000}}`, protocol.Gemini, "/v1beta/models/g:generateContent", `"maxOutputTokens":1000`},
+		{"gemini stream injects", `{"contents":[]}`, protocol.Gemini, "/v1beta/models/g:streamGenerateContent", `"maxOutputTokens":1000`},
+		{"openai embeddings untouched", `{"model":"m","input":"hi"}`, protocol.OpenAI, "/v1/embeddings", ""},
+		{"anthropic count_tokens untouched", `{"model":"m","messages":[]}`, protocol.Anthropic, "/v1/messages/count_tokens", ""},
+		{"gemini countTokens untouched", `{"contents":[]}`, protocol.Gemini, "/v1beta/models/g:countTokens", ""},
+		{"gemini embedContent untouched", `{"content":{}}`, protocol.Gemini, "/v1beta/models/g:embedContent", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, ok := capMaxOutputTokens([]byte(tt.body), tt.protocol, tt.path, 1000)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("expected no rewrite, got %s", out)
+				}
+				return
+			}
+			if !ok || !strings.Contains(string(out), tt.want) {
+				t.Fatalf("rewrite=%v body=%s, want %s", ok, out, tt.want)
+			}
+		})
+	}
+}
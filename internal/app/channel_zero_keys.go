@@ -0,0 +1,16 @@
+package app
+
+import (
+	modelpkg "ccLoad/internal/model"
+)
+
+// filterZeroKeyChannels 过滤没有可用（已启用）Key 的渠道
+func filterZeroKeyChannels(channels []*modelpkg.Config) []*modelpkg.Config {
+	filtered := channels[:0:0]
+	for _, ch := range channels {
+		if ch.KeyCount > 0 {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
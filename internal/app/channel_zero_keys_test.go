@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestSelectCandidates_SkipZeroKeyChannels(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, cfg := range []*model.Config{
+		{Name: "with-key", URL: "https://a.example.com", Priority: 10, Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+		{Name: "no-key", URL: "https://b.example.com", Priority: 10, Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+	} {
+		created, err := store.CreateConfig(ctx, cfg)
+		if err != nil {
+			t.Fatalf("CreateConfig: %v", err)
+		}
+		if cfg.Name == "with-key" {
+			if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{{ChannelID: created.ID, KeyIndex: 0, APIKey: "sk-a", KeyStrategy: model.KeyStrategySequential}}); err != nil {
+				t.Fatalf("CreateAPIKeysBatch: %v", err)
+			}
+		}
+	}
+
+	// 默认不跳过：零 Key 渠道仍参与选择（保持原有行为）
+	server := &Server{store: store, channelBalancer: NewSmoothWeightedRR()}
+	candidates, err := server.selectCandidatesByModelAndType(ctx, "gpt-4", "")
+	if err != nil || len(candidates) != 2 {
+		t.Fatalf("default candidates = %v, err=%v", configNames(candidates), err)
+	}
+
+	server.skipZeroKeyChannels = true
+	candidates, err = server.selectCandidatesByModelAndType(ctx, "gpt-4", "")
+	if err != nil {
+		t.Fatalf("selectCandidatesByModelAndType: %v", err)
+	}
+	if names := configNames(candidates); len(names) != 1 || !slices.Contains(names, "with-key") {
+		t.Fatalf("candidates = %v, want with-key only", names)
+	}
+}
+
+func TestAdminChannels_ListMarksZeroKeyChannels(t *testing.T) {
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+
+	withKey, err := srv.store.CreateConfig(ctx, &model.Config{Name: "keyed", URL: "https://a.example.com", Enabled: true, ModelEntries: []model.ModelEntry{{Model: "m"}}})
+	if err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	if err := srv.store.CreateAPIKeysBatch(ctx, []*model.APIKey{{ChannelID: withKey.ID, KeyIndex: 0, APIKey: "sk-a", KeyStrategy: model.KeyStrategySequential}}); err != nil {
+		t.Fatalf("CreateAPIKeysBatch: %v", err)
+	}
+	if _, err := srv.store.CreateConfig(ctx, &model.Config{Name: "keyless", URL: "https://b.example.com", Enabled: true, ModelEntries: []model.ModelEntry{{Model: "m"}}}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/channels", nil))
+	srv.HandleChannels(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []struct {
+			Name      string `json:"name"`
+			NoAPIKeys bool   `json:"no_api_keys"`
+		} `json:"data"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &resp)
+	got := map[string]bool{}
+	for _, ch := range resp.Data {
+		got[ch.Name] = ch.NoAPIKeys
+	}
+	if len(got) != 2 || got["keyed"] || !got["keyless"] {
+		t.Fatalf("no_api_keys flags = %v", got)
+	}
+}
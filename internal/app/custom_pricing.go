@@ -0,0 +1,33 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"ccLoad/internal/util"
+)
+
+// loadCustomPricingFile 加载 CCLOAD_PRICING_FILE 指定的自定义定价 JSON（覆盖内置价格或补充私有模型）
+// 文件缺失或格式错误时告警并继续使用内置定价，不阻止启动
+func loadCustomPricingFile(path string) int {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return 0
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("[WARN] 读取自定义定价文件失败，使用内置定价: %v", err)
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	pricing, err := util.ParseCustomModelPricing(f)
+	if err != nil {
+		log.Printf("[WARN] 自定义定价文件 %s 无效，使用内置定价: %v", path, err)
+		return 0
+	}
+	util.SetCustomModelPricing(pricing)
+	log.Printf("[CONFIG] 已加载自定义定价: %s（%d 个模型，优先于内置价格）", path, len(pricing))
+	return len(pricing)
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ccLoad/internal/util"
+)
+
+func TestLoadCustomPricingFile(t *testing.T) {
+	t.Cleanup(func() { util.SetCustomModelPricing(nil) })
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "pricing.json")
+	if err := os.WriteFile(valid, []byte(`{"ccload-app-pricing-test": {"input": 1, "output": 2}}`), 0o600); err != nil {
+		t.Fatalf("write pricing file: %v", err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"m": {"input": -1}}`), 0o600); err != nil {
+		t.Fatalf("write pricing file: %v", err)
+	}
+
+	if n := loadCustomPricingFile(""); n != 0 {
+		t.Fatalf("empty path loaded %d models", n)
+	}
+	if n := loadCustomPricingFile(filepath.Join(dir, "missing.json")); n != 0 {
+		t.Fatalf("missing file loaded %d models", n)
+	}
+	if n := loadCustomPricingFile(valid); n != 1 || !util.HasModelPricing("ccload-app-pricing-test") {
+		t.Fatalf("valid file: loaded=%d", n)
+	}
+	// 无效文件不覆盖已加载的定价
+	if n := loadCustomPricingFile(invalid); n != 0 || !util.HasModelPricing("ccload-app-pricing-test") {
+		t.Fatalf("invalid file should be ignored: loaded=%d", n)
+	}
+}
@@ -113,6 +113,24 @@ func applyBodyRules(contentType string, body []byte, rules []model.CustomBodyRul
 				root = next
 				changed = true
 			}
+		case model.RuleActionDefault:
+			// 客户端已设置（含 null 与对象）则整体保留，不做深合并；stream 由客户端决定
+			if (len(segs) == 1 && segs[0] == "stream") || hasJSONPath(root, segs) {
+				continue
+			}
+			var parsed any
+			if err := sonic.Unmarshal(rule.Value, &parsed); err != nil {
+				slog.Warn("custom_request_rules: body default value not JSON",
+					"rule_index", idx, "path", rule.Path, "error", err.Error())
+				continue
+			}
+			if next, ok := setJSONPath(root, segs, parsed); ok {
+				root = next
+				changed = true
+			} else {
+				slog.Warn("custom_request_rules: body default path conflict",
+					"rule_index", idx, "path", rule.Path)
+			}
 		case model.RuleActionOverride:
 			var parsed any
 			if len(rule.Value) == 0 {
@@ -161,6 +179,11 @@ func resolveModelAfterBodyRules(modelName string, rules []model.CustomBodyRule)
 		switch rule.Action {
 		case model.RuleActionRemove:
 			resolved = ""
+		case model.RuleActionDefault:
+			var value string
+			if resolved == "" && sonic.Unmarshal(rule.Value, &value) == nil {
+				resolved = strings.TrimSpace(value)
+			}
 		case model.RuleActionOverride:
 			var value string
 			if err := sonic.Unmarshal(rule.Value, &value); err != nil {
@@ -250,6 +273,30 @@ func setJSONPath(root any, segs []string, value any) (any, bool) {
 	}
 }
 
+// hasJSONPath 判断嵌套路径是否已存在（值为 null 也视为存在）
+func hasJSONPath(root any, segs []string) bool {
+	node := root
+	for _, seg := range segs {
+		switch n := node.(type) {
+		case map[string]any:
+			child, exists := n[seg]
+			if !exists {
+				return false
+			}
+			node = child
+		case []any:
+			idx, ok := parseArrayIndex(seg)
+			if !ok || idx >= len(n) {
+				return false
+			}
+			node = n[idx]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // removeJSONPath 删除嵌套路径上的节点；路径不存在时 ok=false（静默忽略）。
 func removeJSONPath(root any, segs []string) (any, bool) {
 	if len(segs) == 0 {
@@ -233,6 +233,84 @@ func TestApplyBodyRules_OverrideWithObjectValue(t *testing.T) {
 	}
 }
 
+func TestApplyBodyRules_DefaultOnlyFillsMissingFields(t *testing.T) {
+	body := []byte(`{"model":"x","stream":false,"temperature":0.2,"metadata":{"user_id":"u1"}}`)
+	rules := []model.CustomBodyRule{
+		{Action: model.RuleActionDefault, Path: "temperature", Value: json.RawMessage("0.7")},
+		{Action: model.RuleActionDefault, Path: "max_tokens", Value: json.RawMessage("4096")},
+		{Action: model.RuleActionDefault, Path: "metadata", Value: json.RawMessage(`{"tier":"gold"}`)},
+		{Action: model.RuleActionDefault, Path: "stream", Value: json.RawMessage("true")},
+	}
+
+	out := applyBodyRules("application/json", body, rules)
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if v, _ := got["temperature"].(float64); v != 0.2 {
+		t.Errorf("client temperature must win, got %v", got["temperature"])
+	}
+	if v, _ := got["max_tokens"].(float64); v != 4096 {
+		t.Errorf("max_tokens should be defaulted to 4096, got %v", got["max_tokens"])
+	}
+	metadata, _ := got["metadata"].(map[string]any)
+	if metadata["user_id"] != "u1" || metadata["tier"] != nil {
+		t.Errorf("existing object must be kept as-is (no deep merge), got %v", metadata)
+	}
+	if got["stream"] != false {
+		t.Errorf("stream flag must not be touched, got %v", got["stream"])
+	}
+}
+
+func TestApplyBodyRules_DefaultWithoutStreamField(t *testing.T) {
+	out := applyBodyRules("application/json", []byte(`{"model":"x"}`), []model.CustomBodyRule{
+		{Action: model.RuleActionDefault, Path: "stream", Value: json.RawMessage("true")},
+		{Action: model.RuleActionDefault, Path: "thinking.budget_tokens", Value: json.RawMessage("1024")},
+	})
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, ok := got["stream"]; ok {
+		t.Errorf("stream must never be injected, got %v", got["stream"])
+	}
+	thinking, _ := got["thinking"].(map[string]any)
+	if v, _ := thinking["budget_tokens"].(float64); v != 1024 {
+		t.Errorf("nested default expected 1024, got %v", got["thinking"])
+	}
+}
+
+func TestValidateCustomRequestRules_DefaultAction(t *testing.T) {
+	rules := &model.CustomRequestRules{Body: []model.CustomBodyRule{
+		{Action: " Default ", Path: "temperature", Value: json.RawMessage("0.7")},
+	}}
+	if err := validateCustomRequestRules(rules); err != nil {
+		t.Fatalf("default action should be valid: %v", err)
+	}
+	if rules.Body[0].Action != model.RuleActionDefault {
+		t.Fatalf("action not normalized: %q", rules.Body[0].Action)
+	}
+
+	for name, rule := range map[string]model.CustomBodyRule{
+		"stream":        {Action: model.RuleActionDefault, Path: "stream", Value: json.RawMessage("true")},
+		"missing value": {Action: model.RuleActionDefault, Path: "temperature"},
+	} {
+		if err := validateCustomRequestRules(&model.CustomRequestRules{Body: []model.CustomBodyRule{rule}}); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestResolveModelAfterBodyRules_DefaultOnlyWhenMissing(t *testing.T) {
+	rules := []model.CustomBodyRule{{Action: model.RuleActionDefault, Path: "model", Value: json.RawMessage(`"fallback"`)}}
+	if got := resolveModelAfterBodyRules("client-model", rules); got != "client-model" {
+		t.Errorf("client model must win, got %q", got)
+	}
+	if got := resolveModelAfterBodyRules("", rules); got != "fallback" {
+		t.Errorf("missing model should resolve to default, got %q", got)
+	}
+}
+
 func TestApplyBodyRules_RemoveExisting(t *testing.T) {
 	body := []byte(`{"a":1,"b":2,"c":{"d":3}}`)
 	rules := []model.CustomBodyRule{
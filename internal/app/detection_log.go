@@ -83,6 +83,7 @@ func (s *Server) persistDetectionLog(ctx context.Context, entry *model.LogEntry)
 	if s == nil || s.store == nil || entry == nil {
 		return
 	}
+	truncateLogEntryMessage(entry)
 	if err := s.store.AddLog(ctx, entry); err != nil {
 		log.Printf("[WARN] 检测日志写入失败: %v", err)
 	}
@@ -522,7 +522,10 @@ func (m *FingerprintJobManager) runSampling(
 	if keyIndex < 0 || keyIndex >= len(keys) {
 		keyIndex = 0
 	}
-	apiKey := keys[keyIndex].APIKey
+	apiKey, err := resolveAPIKey(keys[keyIndex].APIKey)
+	if err != nil {
+		return nil, false, err
+	}
 
 	temp := 1.0
 	workCh := make(chan struct{}, iterations)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"ccLoad/internal/model"
+	"ccLoad/internal/util"
 
 	"github.com/gin-gonic/gin"
 )
@@ -208,6 +209,25 @@ func RespondPaginated[T any](c *gin.Context, code int, data T, count int) {
 	})
 }
 
+// CursorResponse 键集分页响应：count 为本页条数，next_cursor 为 null 表示没有下一页
+type CursorResponse[T, C any] struct {
+	Success    bool   `json:"success"`
+	Data       T      `json:"data"`
+	Count      int    `json:"count"`
+	NextCursor *C     `json:"next_cursor"`
+	Error      string `json:"error"`
+}
+
+// RespondCursorPage 发送键集分页 JSON 响应
+func RespondCursorPage[T, C any](c *gin.Context, code int, data T, count int, next *C) {
+	c.JSON(code, CursorResponse[T, C]{
+		Success:    true,
+		Data:       data,
+		Count:      count,
+		NextCursor: next,
+	})
+}
+
 // RespondError 发送错误响应
 func RespondError(c *gin.Context, code int, err error) {
 	var errMsg string
@@ -296,6 +316,7 @@ func BindAndValidate(c *gin.Context, obj RequestValidator) error {
 // - channel_name_like: 模糊匹配渠道名称
 // - model: 精确匹配模型名称
 // - model_like: 模糊匹配模型名称
+// - path_like: 模糊匹配请求路径
 func BuildLogFilter(c *gin.Context) model.LogFilter {
 	var lf model.LogFilter
 
@@ -345,6 +366,22 @@ func BuildLogFilter(c *gin.Context) model.LogFilter {
 		}
 	}
 
+	// 请求ID精确匹配（X-Request-Id）
+	if rid := strings.TrimSpace(c.Query("request_id")); rid != "" {
+		lf.RequestID = rid
+	}
+
+	// 请求路径模糊匹配
+	if pl := strings.TrimSpace(c.Query("path_like")); pl != "" {
+		lf.PathLike = pl
+	}
+
+	// 耗时范围过滤（毫秒），用于定位慢请求
+	lf.MinDurationMs = parseNonNegativeInt64Query(c, "min_duration_ms")
+	lf.MaxDurationMs = parseNonNegativeInt64Query(c, "max_duration_ms")
+	lf.MinFirstByteMs = parseNonNegativeInt64Query(c, "min_first_byte_ms")
+	lf.SlowOnly = util.ParseBoolDefault(c.Query("slow_only"), false)
+
 	switch strings.TrimSpace(c.Query("log_source")) {
 	case "", model.LogSourceProxy:
 		lf.LogSource = model.LogSourceProxy
@@ -364,3 +401,16 @@ func BuildLogFilter(c *gin.Context) model.LogFilter {
 
 	return lf
 }
+
+// parseNonNegativeInt64Query 解析非负整数查询参数，缺失或非法时返回 nil（与其他过滤参数一致，忽略非法值）
+func parseNonNegativeInt64Query(c *gin.Context, key string) *int64 {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v < 0 {
+		return nil
+	}
+	return &v
+}
@@ -379,6 +379,39 @@ func TestBuildLogFilter(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "duration_range",
+			query: "min_duration_ms=1500&max_duration_ms=30000&min_first_byte_ms=800",
+			check: func(t *testing.T, lf model.LogFilter) {
+				if lf.MinDurationMs == nil || *lf.MinDurationMs != 1500 {
+					t.Error("expected MinDurationMs=1500")
+				}
+				if lf.MaxDurationMs == nil || *lf.MaxDurationMs != 30000 {
+					t.Error("expected MaxDurationMs=30000")
+				}
+				if lf.MinFirstByteMs == nil || *lf.MinFirstByteMs != 800 {
+					t.Error("expected MinFirstByteMs=800")
+				}
+			},
+		},
+		{
+			name:  "slow_only",
+			query: "slow_only=true",
+			check: func(t *testing.T, lf model.LogFilter) {
+				if !lf.SlowOnly {
+					t.Error("expected SlowOnly=true")
+				}
+			},
+		},
+		{
+			name:  "invalid_duration_ignored",
+			query: "min_duration_ms=-1&max_duration_ms=abc",
+			check: func(t *testing.T, lf model.LogFilter) {
+				if lf.MinDurationMs != nil || lf.MaxDurationMs != nil {
+					t.Error("expected nil duration filters for invalid input")
+				}
+			},
+		},
 		{
 			name:  "combined_filters",
 			query: "channel_id=1&model=gpt-4&status_code=500",
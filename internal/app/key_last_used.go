@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"ccLoad/internal/config"
+	"ccLoad/internal/model"
+)
+
+// keyLastUsedRef 标识单个 Key（渠道ID + key_index）
+type keyLastUsedRef struct {
+	channelID int64
+	keyIndex  int
+}
+
+// keyLastUsedTracker 在内存中聚合 Key 最后成功使用时间，由后台协程批量写入 api_keys.last_used_at。
+// 成功路径只做一次 map 写入，避免每个请求一次 UPDATE。
+type keyLastUsedTracker struct {
+	mu      sync.Mutex
+	pending map[keyLastUsedRef]int64 // Unix毫秒
+}
+
+func newKeyLastUsedTracker() *keyLastUsedTracker {
+	return &keyLastUsedTracker{pending: make(map[keyLastUsedRef]int64)}
+}
+
+// Touch 记录 Key 的使用时间（nil 安全）
+func (t *keyLastUsedTracker) Touch(channelID int64, keyIndex int, at time.Time) {
+	if t == nil || channelID <= 0 || keyIndex < 0 {
+		return
+	}
+	ms := at.UnixMilli()
+	ref := keyLastUsedRef{channelID: channelID, keyIndex: keyIndex}
+	t.mu.Lock()
+	if ms > t.pending[ref] {
+		t.pending[ref] = ms
+	}
+	t.mu.Unlock()
+}
+
+// drain 取出并清空待落库的更新
+func (t *keyLastUsedTracker) drain() []model.KeyLastUsed {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	pending := t.pending
+	if len(pending) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	t.pending = make(map[keyLastUsedRef]int64, len(pending))
+	t.mu.Unlock()
+
+	updates := make([]model.KeyLastUsed, 0, len(pending))
+	for ref, ms := range pending {
+		updates = append(updates, model.KeyLastUsed{ChannelID: ref.channelID, KeyIndex: ref.keyIndex, LastUsedAt: ms})
+	}
+	return updates
+}
+
+// flushKeyLastUsed 将内存中的 Key 使用时间批量写入数据库（失败仅记录日志，不重试）
+func (s *Server) flushKeyLastUsed(ctx context.Context) {
+	updates := s.keyLastUsed.drain()
+	if len(updates) == 0 {
+		return
+	}
+	if err := s.store.UpdateAPIKeysLastUsed(ctx, updates); err != nil {
+		log.Printf("[WARN] 批量更新 Key 最后使用时间失败（%d 条）: %v", len(updates), err)
+	}
+}
+
+// keyLastUsedFlushLoop 定期落库 Key/渠道最后使用时间；关闭时做最后一次落库
+func (s *Server) keyLastUsedFlushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(config.KeyLastUsedFlushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.flushKeyLastUsed(ctx)
+		s.flushChannelLastUsed(ctx)
+	}
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
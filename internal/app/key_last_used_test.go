@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestKeyLastUsedTracker_KeepsLatestAndDrains(t *testing.T) {
+	t.Parallel()
+
+	tr := newKeyLastUsedTracker()
+	base := time.UnixMilli(10_000)
+	tr.Touch(1, 0, base.Add(time.Second))
+	tr.Touch(1, 0, base) // 乱序到达的旧时间戳不回退
+	tr.Touch(1, 1, base)
+	tr.Touch(0, 0, base) // 非法渠道ID忽略
+
+	updates := tr.drain()
+	if len(updates) != 2 {
+		t.Fatalf("updates=%+v, want 2 entries", updates)
+	}
+	for _, u := range updates {
+		want := base.UnixMilli()
+		if u.KeyIndex == 0 {
+			want = base.Add(time.Second).UnixMilli()
+		}
+		if u.ChannelID != 1 || u.LastUsedAt != want {
+			t.Fatalf("unexpected update %+v", u)
+		}
+	}
+	if again := tr.drain(); len(again) != 0 {
+		t.Fatalf("drain after drain=%+v, want empty", again)
+	}
+
+	var nilTracker *keyLastUsedTracker
+	nilTracker.Touch(1, 0, base)
+	if nilTracker.drain() != nil {
+		t.Fatal("nil tracker drain should return nil")
+	}
+}
+
+func TestProxySuccess_RecordsKeyLastUsed(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4o", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	before := time.Now().UnixMilli()
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4o",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d: %s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	env.server.flushKeyLastUsed(ctx)
+
+	configs, err := env.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	keys, err := env.store.GetAPIKeys(ctx, configs[0].ID)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("GetAPIKeys: %v (%d)", err, len(keys))
+	}
+	if keys[0].LastUsedAt < before {
+		t.Fatalf("last_used_at=%d, want >= %d", keys[0].LastUsedAt, before)
+	}
+}
@@ -1,12 +1,14 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"ccLoad/internal/model"
+	"ccLoad/internal/util"
 )
 
 // KeySelector 负责从渠道的多个API Key中选择可用的Key
@@ -18,6 +20,9 @@ type KeySelector struct {
 	// 渠道删除时需要清理对应计数器，避免rrCounters无界增长。
 	rrCounters map[int64]*rrCounter
 	rrMutex    sync.RWMutex
+
+	// keyRPM Key级RPM滑动窗口（APIKey.ID -> 最近一分钟的请求时间），仅内存，重启清零
+	keyRPM *channelRPMLimiter
 }
 
 // rrCounter 轮询计数器（简化版）
@@ -30,14 +35,60 @@ type rrCounter struct {
 func NewKeySelector() *KeySelector {
 	return &KeySelector{
 		rrCounters: make(map[int64]*rrCounter),
+		keyRPM:     newChannelRPMLimiter(nil),
 	}
 }
 
 // SelectAvailableKey 返回 (keyIndex, apiKey, error)
 // 策略: sequential顺序尝试 | round_robin轮询选择
 // excludeKeys: 避免同一请求内重复尝试
+// 每次调用视为一个新请求的首次选择；请求内重试使用 SelectAvailableKeyWithStrategy 传入 prevKeyIndex
 // 移除store依赖，apiKeys由调用方传入，避免重复查询
+// 文件引用型 Key（file:/path）在此解析为文件内容，返回值始终是明文 Key
+// 配置了 rpm_limit 的 Key 在最近一分钟内达到上限时跳过；选中后计入该 Key 的窗口
 func (ks *KeySelector) SelectAvailableKey(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool) (int, string, error) {
+	return resolveSelectedKey(ks.selectAndReserveKey(channelID, apiKeys, excludeKeys, "", -1))
+}
+
+// SelectAvailableKeyWithStrategy 与 SelectAvailableKey 相同，但 strategyOverride 非空时
+// 以其替代渠道配置的 Key 策略（仅作用于本次选择，不修改渠道配置）
+// prevKeyIndex 为本请求上一次选中的 KeyIndex（首次选择传 -1）：轮询策略下请求内重试从其后继续，不推进渠道游标
+func (ks *KeySelector) SelectAvailableKeyWithStrategy(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool, strategyOverride string, prevKeyIndex int) (int, string, error) {
+	return resolveSelectedKey(ks.selectAndReserveKey(channelID, apiKeys, excludeKeys, strategyOverride, prevKeyIndex))
+}
+
+// selectAndReserveKey 选 Key 后占用其RPM配额；并发下配额被抢占时排除该 Key 重新选择
+func (ks *KeySelector) selectAndReserveKey(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool, strategyOverride string, prevKeyIndex int) (int, string, error) {
+	var raced map[int]bool
+	for {
+		keyIndex, apiKey, err := ks.selectAvailableKey(channelID, apiKeys, excludeKeys, strategyOverride, prevKeyIndex)
+		if err != nil {
+			if raced != nil && !errors.Is(err, ErrKeyRPMExceeded) {
+				err = fmt.Errorf("%w: %v", ErrKeyRPMExceeded, err)
+			}
+			return -1, "", err
+		}
+		key, ok := findAPIKeyByIndex(apiKeys, keyIndex)
+		if !ok || key.RPMLimit <= 0 || ks.keyRPM.reserve(key.ID, key.RPMLimit).allowed {
+			return keyIndex, apiKey, nil
+		}
+		if raced == nil {
+			raced = make(map[int]bool, len(excludeKeys)+1)
+			for idx, tried := range excludeKeys {
+				raced[idx] = tried
+			}
+			excludeKeys = raced
+		}
+		raced[keyIndex] = true
+	}
+}
+
+// keyRPMExceeded 判断 Key 最近一分钟的请求数是否已达到其 rpm_limit
+func (ks *KeySelector) keyRPMExceeded(apiKey *model.APIKey) bool {
+	return apiKey.RPMLimit > 0 && ks.keyRPM.exceeded(apiKey.ID, apiKey.RPMLimit)
+}
+
+func (ks *KeySelector) selectAvailableKey(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool, strategyOverride string, prevKeyIndex int) (int, string, error) {
 	if len(apiKeys) == 0 {
 		return -1, "", fmt.Errorf("no API keys configured for channel %d", channelID)
 	}
@@ -59,18 +110,24 @@ func (ks *KeySelector) SelectAvailableKey(channelID int64, apiKeys []*model.APIK
 				keyIndex,
 				time.Unix(apiKeys[0].CooldownUntil, 0).Format("2006-01-02 15:04:05"))
 		}
+		if ks.keyRPMExceeded(apiKeys[0]) {
+			return -1, "", fmt.Errorf("%w: single key (index=%d) reached rpm limit %d", ErrKeyRPMExceeded, keyIndex, apiKeys[0].RPMLimit)
+		}
 		return keyIndex, apiKeys[0].APIKey, nil
 	}
 
 	// 多Key场景:根据策略选择
 	strategy := apiKeys[0].KeyStrategy
+	if strategyOverride != "" {
+		strategy = strategyOverride
+	}
 	if strategy == "" {
 		strategy = model.KeyStrategySequential
 	}
 
 	switch strategy {
 	case model.KeyStrategyRoundRobin:
-		return ks.selectRoundRobin(channelID, apiKeys, excludeKeys)
+		return ks.selectRoundRobin(channelID, apiKeys, excludeKeys, prevKeyIndex)
 	case model.KeyStrategySequential:
 		return ks.selectSequential(apiKeys, excludeKeys)
 	default:
@@ -81,6 +138,10 @@ func (ks *KeySelector) SelectAvailableKey(channelID int64, apiKeys []*model.APIK
 // SelectCooldownFallbackKey 在“全冷却兜底”路径中选择最早恢复的冷却Key。
 // 只给兜底候选使用；普通请求仍必须走 SelectAvailableKey 的严格冷却过滤。
 func (ks *KeySelector) SelectCooldownFallbackKey(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool) (int, string, error) {
+	return resolveSelectedKey(ks.selectCooldownFallbackKey(channelID, apiKeys, excludeKeys))
+}
+
+func (ks *KeySelector) selectCooldownFallbackKey(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool) (int, string, error) {
 	if len(apiKeys) == 0 {
 		return -1, "", fmt.Errorf("no API keys configured for channel %d", channelID)
 	}
@@ -114,8 +175,24 @@ func (ks *KeySelector) SelectCooldownFallbackKey(channelID int64, apiKeys []*mod
 	return -1, "", fmt.Errorf("all API keys are already tried")
 }
 
+// resolveAPIKey 解析文件引用型 Key（测试可替换为指向临时密钥目录的缓存）
+var resolveAPIKey = util.ResolveAPIKey
+
+// resolveSelectedKey 将选中的文件引用型 Key 解析为明文（读取失败视为选 Key 失败）
+func resolveSelectedKey(keyIndex int, apiKey string, err error) (int, string, error) {
+	if err != nil || !util.IsAPIKeyFileRef(apiKey) {
+		return keyIndex, apiKey, err
+	}
+	resolved, err := resolveAPIKey(apiKey)
+	if err != nil {
+		return -1, "", fmt.Errorf("key (index=%d): %w", keyIndex, err)
+	}
+	return keyIndex, resolved, nil
+}
+
 func (ks *KeySelector) selectSequential(apiKeys []*model.APIKey, excludeKeys map[int]bool) (int, string, error) {
 	now := time.Now()
+	rpmLimited := false
 
 	for _, apiKey := range apiKeys {
 		keyIndex := apiKey.KeyIndex
@@ -132,10 +209,23 @@ func (ks *KeySelector) selectSequential(apiKeys []*model.APIKey, excludeKeys map
 			continue
 		}
 
+		if ks.keyRPMExceeded(apiKey) {
+			rpmLimited = true
+			continue
+		}
+
 		return keyIndex, apiKey.APIKey, nil
 	}
 
-	return -1, "", fmt.Errorf("all API keys are in cooldown or already tried")
+	return -1, "", noAvailableKeyError(rpmLimited)
+}
+
+// noAvailableKeyError 多Key均不可用；存在仅因RPM上限被跳过的Key时包装 ErrKeyRPMExceeded（不应触发冷却）
+func noAvailableKeyError(rpmLimited bool) error {
+	if rpmLimited {
+		return fmt.Errorf("%w: remaining API keys reached their rpm limit", ErrKeyRPMExceeded)
+	}
+	return fmt.Errorf("all API keys are in cooldown or already tried")
 }
 
 // getOrCreateCounter 获取或创建渠道的轮询计数器（双重检查锁定）
@@ -189,46 +279,86 @@ func (ks *KeySelector) CleanupInactiveCounters(maxIdleTime time.Duration) {
 		}
 	}
 	ks.rrMutex.Unlock()
+
+	ks.keyRPM.CleanupExpired()
 }
 
-// selectRoundRobin 轮询选择可用Key
+// selectRoundRobin 轮询选择可用Key（确定性游标）
 // [FIX] 按 slice 索引轮询，返回真实 KeyIndex，不再假设 KeyIndex 连续
-func (ks *KeySelector) selectRoundRobin(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool) (int, string, error) {
+//
+// 游标语义：counter 存放"下一个请求的起始 slice 索引"。从起点开始按顺序跳过禁用/已尝试/冷却的Key。
+// 请求的首次选择（prevKeyIndex < 0）用 CAS 把游标推进到所选Key的下一位，每个请求只推进一次；
+// 请求内重试从本请求上一次选中的Key之后按顺序走，不读写游标，并发请求互不干扰。
+// 旧实现先 Add(1) 再跳过不可用Key，冷却段之后的第一个Key会被重复命中；
+// 现在游标只越过实际服务的Key，长期来看可用Key的使用次数严格均匀。
+func (ks *KeySelector) selectRoundRobin(channelID int64, apiKeys []*model.APIKey, excludeKeys map[int]bool, prevKeyIndex int) (int, string, error) {
 	keyCount := len(apiKeys)
 	now := time.Now()
 
 	counter := ks.getOrCreateCounter(channelID)
 	counter.lastAccess.Store(now.UnixNano())
-	startIdx := int(counter.counter.Add(1) % uint32(keyCount)) //nolint:gosec // G115: keyCount 来自 API Keys 切片长度，不可能溢出
 
-	// 从startIdx开始轮询，最多尝试keyCount次
-	for i := range keyCount {
-		sliceIdx := (startIdx + i) % keyCount
-		selectedKey := apiKeys[sliceIdx]
-		if selectedKey == nil {
-			continue
+	if prevKeyIndex >= 0 {
+		startIdx := 0
+		for i, apiKey := range apiKeys {
+			if apiKey != nil && apiKey.KeyIndex == prevKeyIndex {
+				startIdx = (i + 1) % keyCount
+				break
+			}
 		}
+		selectedIdx, rpmLimited := ks.scanRoundRobin(apiKeys, excludeKeys, startIdx, now)
+		if selectedIdx < 0 {
+			return -1, "", noAvailableKeyError(rpmLimited)
+		}
+		return apiKeys[selectedIdx].KeyIndex, apiKeys[selectedIdx].APIKey, nil
+	}
 
-		if selectedKey.Disabled {
-			continue
+	for {
+		cursor := counter.counter.Load()
+		startIdx := int(cursor % uint32(keyCount)) //nolint:gosec // G115: keyCount 来自 API Keys 切片长度，不可能溢出
+
+		selectedIdx, rpmLimited := ks.scanRoundRobin(apiKeys, excludeKeys, startIdx, now)
+		if selectedIdx < 0 {
+			return -1, "", noAvailableKeyError(rpmLimited)
 		}
 
-		keyIndex := selectedKey.KeyIndex // 真实 KeyIndex，可能不连续
+		next := uint32((selectedIdx + 1) % keyCount) //nolint:gosec // G115: 同上
+		if counter.counter.CompareAndSwap(cursor, next) {
+			// 返回真实 KeyIndex，而非 slice 索引
+			selectedKey := apiKeys[selectedIdx]
+			return selectedKey.KeyIndex, selectedKey.APIKey, nil
+		}
+		// 并发请求已推进游标：基于最新游标重新扫描，保证每个游标位置只被消费一次
+	}
+}
 
-		// 检查排除集合（使用真实 KeyIndex）
-		if excludeKeys != nil && excludeKeys[keyIndex] {
+// scanRoundRobin 从 startIdx 起按顺序查找第一个可用Key的 slice 索引（未找到返回 -1）
+func (ks *KeySelector) scanRoundRobin(apiKeys []*model.APIKey, excludeKeys map[int]bool, startIdx int, now time.Time) (int, bool) {
+	rpmLimited := false
+	for i := range apiKeys {
+		sliceIdx := (startIdx + i) % len(apiKeys)
+		if !isRoundRobinCandidate(apiKeys[sliceIdx], excludeKeys, now) {
 			continue
 		}
-
-		if selectedKey.IsCoolingDown(now) {
+		if ks.keyRPMExceeded(apiKeys[sliceIdx]) {
+			rpmLimited = true
 			continue
 		}
-
-		// 返回真实 KeyIndex，而非 slice 索引
-		return keyIndex, selectedKey.APIKey, nil
+		return sliceIdx, rpmLimited
 	}
+	return -1, rpmLimited
+}
 
-	return -1, "", fmt.Errorf("all API keys are in cooldown or already tried")
+// isRoundRobinCandidate 判断Key是否可参与本次轮询（未禁用、本请求未尝试、未冷却）
+func isRoundRobinCandidate(apiKey *model.APIKey, excludeKeys map[int]bool, now time.Time) bool {
+	if apiKey == nil || apiKey.Disabled {
+		return false
+	}
+	// 检查排除集合（使用真实 KeyIndex）
+	if excludeKeys != nil && excludeKeys[apiKey.KeyIndex] {
+		return false
+	}
+	return !apiKey.IsCoolingDown(now)
 }
 
 // KeySelector 专注于Key选择逻辑，冷却管理已移至 cooldownManager
@@ -2,12 +2,17 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"ccLoad/internal/model"
 	"ccLoad/internal/testutil"
+	"ccLoad/internal/util"
 )
 
 // testContextKey 用于测试的 context key 类型
@@ -306,18 +311,20 @@ func TestSelectAvailableKey_RoundRobin(t *testing.T) {
 	})
 
 	t.Run("排除当前Key后跳到下一个", func(t *testing.T) {
-		// [INFO] 内存化后无需重置索引
+		// 确定性游标：同一请求内重试时，排除刚用过的Key应顺序落到下一个Key
+		current, _, err := selector.SelectAvailableKey(cfg.ID, apiKeys, nil)
+		if err != nil {
+			t.Fatalf("SelectAvailableKey失败: %v", err)
+		}
 
-		// 第一次排除Key0
-		excludeKeys := map[int]bool{0: true}
+		excludeKeys := map[int]bool{current: true}
 		keyIndex, _, err := selector.SelectAvailableKey(cfg.ID, apiKeys, excludeKeys)
-
 		if err != nil {
 			t.Fatalf("SelectAvailableKey失败: %v", err)
 		}
 
-		if keyIndex != 1 {
-			t.Errorf("排除Key0后应返回keyIndex=1，实际%d", keyIndex)
+		if want := (current + 1) % 3; keyIndex != want {
+			t.Errorf("排除Key%d后应返回keyIndex=%d，实际%d", current, want, keyIndex)
 		}
 	})
 }
@@ -788,3 +795,248 @@ func TestKeySelector_CleanupInactiveCounters(t *testing.T) {
 		t.Fatalf("expected channel=200 counter to remain")
 	}
 }
+
+// TestSelectAvailableKey_RoundRobin_EvenWithCooledKeys 验证部分Key冷却时轮询仍严格均匀
+// [REGRESSION] 旧实现先推进计数再跳过冷却Key，冷却段后的第一个Key会被多次命中
+func TestSelectAvailableKey_RoundRobin_EvenWithCooledKeys(t *testing.T) {
+	selector := NewKeySelector()
+	cooldownUntil := time.Now().Add(time.Hour).Unix()
+
+	apiKeys := make([]*model.APIKey, 6)
+	for i := range apiKeys {
+		apiKeys[i] = &model.APIKey{
+			ChannelID:   1,
+			KeyIndex:    i,
+			APIKey:      fmt.Sprintf("sk-even-%d", i),
+			KeyStrategy: model.KeyStrategyRoundRobin,
+		}
+	}
+	// 连续冷却两段，制造"冷却段后第一个Key"偏斜场景
+	apiKeys[0].CooldownUntil = cooldownUntil
+	apiKeys[1].CooldownUntil = cooldownUntil
+	apiKeys[3].CooldownUntil = cooldownUntil
+
+	const requests = 300
+	counts := make(map[int]int)
+	for i := range requests {
+		keyIndex, _, err := selector.SelectAvailableKey(1, apiKeys, nil)
+		if err != nil {
+			t.Fatalf("第%d次SelectAvailableKey失败: %v", i+1, err)
+		}
+		counts[keyIndex]++
+	}
+
+	available := []int{2, 4, 5}
+	for _, idx := range []int{0, 1, 3} {
+		if counts[idx] != 0 {
+			t.Fatalf("冷却Key%d不应被选中，counts=%v", idx, counts)
+		}
+	}
+	for _, idx := range available {
+		if counts[idx] != requests/len(available) {
+			t.Fatalf("可用Key分布不均匀: counts=%v, 期望每个=%d", counts, requests/len(available))
+		}
+	}
+}
+
+// TestSelectAvailableKey_RoundRobin_RetryWalksInOrder 验证同一请求内重试按顺序走Key，跳过冷却Key
+func TestSelectAvailableKey_RoundRobin_RetryWalksInOrder(t *testing.T) {
+	selector := NewKeySelector()
+	apiKeys := make([]*model.APIKey, 4)
+	for i := range apiKeys {
+		apiKeys[i] = &model.APIKey{ChannelID: 2, KeyIndex: i, APIKey: fmt.Sprintf("sk-walk-%d", i), KeyStrategy: model.KeyStrategyRoundRobin}
+	}
+	apiKeys[2].CooldownUntil = time.Now().Add(time.Hour).Unix()
+
+	tried := make(map[int]bool)
+	var order []int
+	for range 3 {
+		keyIndex, _, err := selector.SelectAvailableKey(2, apiKeys, tried)
+		if err != nil {
+			t.Fatalf("SelectAvailableKey失败: %v", err)
+		}
+		tried[keyIndex] = true
+		order = append(order, keyIndex)
+	}
+	if want := []int{0, 1, 3}; fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Fatalf("重试顺序=%v, 期望%v", order, want)
+	}
+	if _, _, err := selector.SelectAvailableKey(2, apiKeys, tried); err == nil {
+		t.Fatal("所有可用Key已尝试时应返回错误")
+	}
+
+	// 下一个请求从上次服务Key之后继续（Key3之后回绕到Key0）
+	keyIndex, _, err := selector.SelectAvailableKey(2, apiKeys, nil)
+	if err != nil {
+		t.Fatalf("SelectAvailableKey失败: %v", err)
+	}
+	if keyIndex != 0 {
+		t.Fatalf("下一个请求应从Key0开始，实际Key%d", keyIndex)
+	}
+}
+
+// TestSelectAvailableKey_RoundRobin_AdvancesOncePerRequest 验证每个请求无论重试多少次只推进一次游标
+func TestSelectAvailableKey_RoundRobin_AdvancesOncePerRequest(t *testing.T) {
+	selector := NewKeySelector()
+	apiKeys := make([]*model.APIKey, 4)
+	for i := range apiKeys {
+		apiKeys[i] = &model.APIKey{ChannelID: 3, KeyIndex: i, APIKey: fmt.Sprintf("sk-once-%d", i), KeyStrategy: model.KeyStrategyRoundRobin}
+	}
+
+	// 每个请求首个Key失败后再重试一次：首次选中的Key应依次轮转，不因重试被跳过
+	var firsts []int
+	for range 8 {
+		first, _, err := selector.SelectAvailableKeyWithStrategy(3, apiKeys, nil, "", -1)
+		if err != nil {
+			t.Fatalf("SelectAvailableKey失败: %v", err)
+		}
+		retry, _, err := selector.SelectAvailableKeyWithStrategy(3, apiKeys, map[int]bool{first: true}, "", first)
+		if err != nil {
+			t.Fatalf("重试SelectAvailableKey失败: %v", err)
+		}
+		if want := (first + 1) % 4; retry != want {
+			t.Fatalf("重试应落到Key%d，实际Key%d", want, retry)
+		}
+		firsts = append(firsts, first)
+	}
+	if want := []int{0, 1, 2, 3, 0, 1, 2, 3}; fmt.Sprint(firsts) != fmt.Sprint(want) {
+		t.Fatalf("各请求首个Key=%v, 期望%v", firsts, want)
+	}
+
+	// 并发交错：请求A首选后，请求B首选，A的重试不影响B之后请求的起点
+	a, _, _ := selector.SelectAvailableKeyWithStrategy(3, apiKeys, nil, "", -1)
+	b, _, _ := selector.SelectAvailableKeyWithStrategy(3, apiKeys, nil, "", -1)
+	if _, _, err := selector.SelectAvailableKeyWithStrategy(3, apiKeys, map[int]bool{a: true}, "", a); err != nil {
+		t.Fatalf("请求A重试失败: %v", err)
+	}
+	c, _, _ := selector.SelectAvailableKeyWithStrategy(3, apiKeys, nil, "", -1)
+	if a != 0 || b != 1 || c != 2 {
+		t.Fatalf("交错请求首个Key a=%d b=%d c=%d, 期望 0 1 2", a, b, c)
+	}
+}
+
+func TestSelectAvailableKey_ResolvesFileReference(t *testing.T) {
+	dir := t.TempDir()
+	orig := resolveAPIKey
+	resolveAPIKey = util.NewSecretFileCache(dir, time.Second).Resolve
+	t.Cleanup(func() { resolveAPIKey = orig })
+
+	path := filepath.Join(dir, "key1")
+	if err := os.WriteFile(path, []byte("sk-secret-from-file\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	selector := NewKeySelector()
+	apiKeys := []*model.APIKey{{ChannelID: 1, KeyIndex: 0, APIKey: util.APIKeyFilePrefix + path}}
+	idx, key, err := selector.SelectAvailableKey(1, apiKeys, nil)
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if idx != 0 || key != "sk-secret-from-file" {
+		t.Fatalf("idx=%d key=%q", idx, key)
+	}
+
+	missing := []*model.APIKey{{ChannelID: 2, KeyIndex: 0, APIKey: util.APIKeyFilePrefix + filepath.Join(dir, "missing")}}
+	if _, _, err := selector.SelectAvailableKey(2, missing, nil); err == nil {
+		t.Fatal("expected error for missing key file")
+	}
+}
+
+func TestSelectAvailableKeyWithStrategy_OverridesChannelStrategy(t *testing.T) {
+	t.Parallel()
+
+	selector := NewKeySelector()
+	apiKeys := []*model.APIKey{
+		{ChannelID: 1, KeyIndex: 0, APIKey: "sk-0", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: 1, KeyIndex: 1, APIKey: "sk-1", KeyStrategy: model.KeyStrategySequential},
+	}
+
+	// 渠道配置为顺序：不覆盖时始终选第一个Key
+	for range 3 {
+		idx, _, err := selector.SelectAvailableKeyWithStrategy(1, apiKeys, nil, "", -1)
+		if err != nil || idx != 0 {
+			t.Fatalf("sequential: idx=%d err=%v, want 0", idx, err)
+		}
+	}
+
+	// 覆盖为轮询：连续两次选择应覆盖两个Key
+	seen := map[int]bool{}
+	for range 2 {
+		idx, _, err := selector.SelectAvailableKeyWithStrategy(1, apiKeys, nil, model.KeyStrategyRoundRobin, -1)
+		if err != nil {
+			t.Fatalf("round_robin override: %v", err)
+		}
+		seen[idx] = true
+	}
+	if len(seen) != 2 {
+		t.Fatalf("round_robin override should rotate keys, got %v", seen)
+	}
+}
+
+func TestSelectAvailableKey_KeyRPMLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, strategy := range []string{model.KeyStrategySequential, model.KeyStrategyRoundRobin} {
+		selector := NewKeySelector()
+		apiKeys := []*model.APIKey{
+			{ID: 11, ChannelID: 1, KeyIndex: 0, APIKey: "sk-0", KeyStrategy: strategy, RPMLimit: 2},
+			{ID: 12, ChannelID: 1, KeyIndex: 1, APIKey: "sk-1", KeyStrategy: strategy, RPMLimit: 1},
+		}
+
+		counts := make(map[int]int)
+		for i := range 3 {
+			idx, _, err := selector.SelectAvailableKey(1, apiKeys, nil)
+			if err != nil {
+				t.Fatalf("%s: select #%d: %v", strategy, i+1, err)
+			}
+			counts[idx]++
+		}
+		if counts[0] != 2 || counts[1] != 1 {
+			t.Fatalf("%s: counts=%v, want key0=2 key1=1", strategy, counts)
+		}
+
+		// 两个Key均达到上限：返回 ErrKeyRPMExceeded（而非普通冷却错误）
+		_, _, err := selector.SelectAvailableKey(1, apiKeys, nil)
+		if !errors.Is(err, ErrKeyRPMExceeded) {
+			t.Fatalf("%s: err=%v, want ErrKeyRPMExceeded", strategy, err)
+		}
+	}
+}
+
+func TestSelectAvailableKey_KeyRPMLimitSingleKeyAndWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	selector := NewKeySelector()
+	selector.keyRPM = newChannelRPMLimiter(func() time.Time { return now })
+	apiKeys := []*model.APIKey{{ID: 7, ChannelID: 1, KeyIndex: 0, APIKey: "sk-only", RPMLimit: 1}}
+
+	if _, _, err := selector.SelectAvailableKey(1, apiKeys, nil); err != nil {
+		t.Fatalf("first select: %v", err)
+	}
+	if _, _, err := selector.SelectAvailableKey(1, apiKeys, nil); !errors.Is(err, ErrKeyRPMExceeded) {
+		t.Fatalf("second select err=%v, want ErrKeyRPMExceeded", err)
+	}
+
+	// 滑动窗口过期后恢复
+	now = now.Add(time.Minute + time.Second)
+	if _, _, err := selector.SelectAvailableKey(1, apiKeys, nil); err != nil {
+		t.Fatalf("select after window: %v", err)
+	}
+}
+
+func TestParseKeyStrategyOverride(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":             "",
+		"round_robin":  model.KeyStrategyRoundRobin,
+		" Sequential ": model.KeyStrategySequential,
+		"random":       "",
+	}
+	for raw, want := range cases {
+		if got := parseKeyStrategyOverride(raw); got != want {
+			t.Fatalf("parseKeyStrategyOverride(%q)=%q, want %q", raw, got, want)
+		}
+	}
+}
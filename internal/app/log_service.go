@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"log"
+	"math/rand/v2"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -30,6 +31,14 @@ type LogService struct {
 	logWorkers   int
 	logDropCount atomic.Uint64
 
+	// 成功日志采样率（1=全部记录；错误日志始终记录）
+	successSampleRate float64
+	sampledOutCount   atomic.Uint64
+
+	// 实时用量计数（采样前累加，随日志 Worker 定期刷入 stats_daily）
+	usageMu      sync.Mutex
+	pendingUsage map[dailyUsageKey]*model.DailyStat
+
 	// 日志保留天数（启动时确定，修改后重启生效）
 	retentionDays int
 
@@ -39,6 +48,13 @@ type LogService struct {
 	wg             *sync.WaitGroup
 }
 
+// dailyUsageKey 实时用量计数的聚合维度（与 stats_daily 主键一致）
+type dailyUsageKey struct {
+	day       string
+	channelID int64
+	model     string
+}
+
 // NewLogService 创建日志服务实例
 func NewLogService(
 	store storage.Store,
@@ -50,13 +66,14 @@ func NewLogService(
 	wg *sync.WaitGroup,
 ) *LogService {
 	return &LogService{
-		store:          store,
-		logChan:        make(chan *model.LogEntry, logBufferSize),
-		logWorkers:     logWorkers,
-		retentionDays:  retentionDays,
-		shutdownCh:     shutdownCh,
-		isShuttingDown: isShuttingDown,
-		wg:             wg,
+		store:             store,
+		logChan:           make(chan *model.LogEntry, logBufferSize),
+		logWorkers:        logWorkers,
+		retentionDays:     retentionDays,
+		successSampleRate: 1,
+		shutdownCh:        shutdownCh,
+		isShuttingDown:    isShuttingDown,
+		wg:                wg,
 	}
 }
 
@@ -89,6 +106,7 @@ func (s *LogService) logWorker() {
 				case entry, ok := <-s.logChan:
 					if !ok {
 						s.flushIfNeeded(batch)
+						s.flushUsage()
 						return
 					}
 					batch = append(batch, entry)
@@ -98,6 +116,7 @@ func (s *LogService) logWorker() {
 					}
 				default:
 					s.flushIfNeeded(batch)
+					s.flushUsage()
 					return
 				}
 			}
@@ -106,6 +125,7 @@ func (s *LogService) logWorker() {
 			if !ok {
 				// logChan已关闭，flush剩余日志并退出
 				s.flushIfNeeded(batch)
+				s.flushUsage()
 				return
 			}
 
@@ -124,6 +144,7 @@ func (s *LogService) logWorker() {
 			// - shutdown信号在select中优先级最高，保证快速响应
 			s.flushIfNeeded(batch)
 			batch = batch[:0]
+			s.flushUsage()
 		}
 	}
 }
@@ -205,6 +226,14 @@ func (s *LogService) AddLogAsync(entry *model.LogEntry) {
 		return
 	}
 
+	// 用量计数先于采样，保证成本/计数汇总不受采样影响
+	s.recordUsage(entry)
+
+	if s.sampleOut(entry) {
+		s.sampledOutCount.Add(1)
+		return
+	}
+
 	select {
 	case s.logChan <- entry:
 		// 成功放入队列
@@ -219,6 +248,115 @@ func (s *LogService) AddLogAsync(entry *model.LogEntry) {
 	}
 }
 
+// SetSuccessSampleRate 设置成功日志采样率（启动时调用，取值 [0,1]）
+func (s *LogService) SetSuccessSampleRate(rate float64) {
+	s.successSampleRate = min(max(rate, 0), 1)
+}
+
+// SuccessSampleRate 当前成功日志采样率（未初始化时视为全量记录）
+func (s *LogService) SuccessSampleRate() float64 {
+	if s == nil {
+		return 1
+	}
+	return s.successSampleRate
+}
+
+// SampledOutCount 因采样未写入的成功日志累计数
+func (s *LogService) SampledOutCount() uint64 {
+	return s.sampledOutCount.Load()
+}
+
+// sampleOut 判断是否按采样率丢弃该日志
+// 仅采样代理请求的 2xx 日志；错误、测试、检测日志始终写入
+func (s *LogService) sampleOut(entry *model.LogEntry) bool {
+	if s.successSampleRate >= 1 || entry == nil {
+		return false
+	}
+	if entry.LogSource != model.LogSourceProxy || entry.StatusCode < 200 || entry.StatusCode >= 300 {
+		return false
+	}
+	return rand.Float64() >= s.successSampleRate //nolint:gosec // G404: 采样无需密码学随机
+}
+
+// recordUsage 将代理日志累加到内存用量计数（口径与 RollupDailyStats 一致：499 不计错误，成本为倍率后成本）
+func (s *LogService) recordUsage(entry *model.LogEntry) {
+	if entry == nil || entry.ChannelID <= 0 || model.NormalizeStoredLogSource(entry.LogSource) != model.LogSourceProxy {
+		return
+	}
+	t := entry.Time.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	multiplier := entry.CostMultiplier
+	if multiplier < 0 {
+		multiplier = 1
+	}
+	key := dailyUsageKey{day: t.Format("2006-01-02"), channelID: entry.ChannelID, model: entry.Model}
+
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	if s.pendingUsage == nil {
+		s.pendingUsage = make(map[dailyUsageKey]*model.DailyStat)
+	}
+	st := s.pendingUsage[key]
+	if st == nil {
+		st = &model.DailyStat{Day: key.day, ChannelID: key.channelID, Model: key.model}
+		s.pendingUsage[key] = st
+	}
+	switch {
+	case entry.StatusCode >= 200 && entry.StatusCode < 300:
+		st.SuccessCount++
+	case entry.StatusCode != 499:
+		st.ErrorCount++
+	}
+	st.InputTokens += int64(entry.InputTokens)
+	st.OutputTokens += int64(entry.OutputTokens)
+	st.CacheReadInputTokens += int64(entry.CacheReadInputTokens)
+	st.CacheCreationInputTokens += int64(entry.CacheCreationInputTokens)
+	st.Cost += entry.Cost * multiplier
+}
+
+// flushUsage 将内存用量计数刷入 stats_daily；失败时合并回内存，下次重试
+func (s *LogService) flushUsage() {
+	s.usageMu.Lock()
+	pending := s.pendingUsage
+	s.pendingUsage = nil
+	s.usageMu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	deltas := make([]model.DailyStat, 0, len(pending))
+	for _, st := range pending {
+		deltas = append(deltas, *st)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.LogFlushTimeoutMs)*time.Millisecond)
+	defer cancel()
+	if err := s.store.AddDailyStats(ctx, deltas); err != nil {
+		log.Printf("[WARN] 用量计数写入失败，下次重试 (rows=%d): %v", len(deltas), err)
+		s.usageMu.Lock()
+		defer s.usageMu.Unlock()
+		if s.pendingUsage == nil {
+			s.pendingUsage = pending
+			return
+		}
+		for key, st := range pending {
+			cur := s.pendingUsage[key]
+			if cur == nil {
+				s.pendingUsage[key] = st
+				continue
+			}
+			cur.SuccessCount += st.SuccessCount
+			cur.ErrorCount += st.ErrorCount
+			cur.InputTokens += st.InputTokens
+			cur.OutputTokens += st.OutputTokens
+			cur.CacheReadInputTokens += st.CacheReadInputTokens
+			cur.CacheCreationInputTokens += st.CacheCreationInputTokens
+			cur.Cost += st.Cost
+		}
+	}
+}
+
 // ============================================================================
 // 日志清理
 // ============================================================================
@@ -277,20 +415,38 @@ func (s *LogService) cleanupOldLogsLoop() {
 	debugTicker := time.NewTicker(config.DebugLogCleanupInterval)
 	defer debugTicker.Stop()
 
+	// purgedBefore 已清理日志的截止时间：早于它的自然日日志已不完整，不能再重算汇总
+	// 启动时无法得知上次清理时间，按当前保留期保守估计
+	var purgedBefore time.Time
+	if s.retentionDays > 0 {
+		purgedBefore = time.Now().AddDate(0, 0, -s.retentionDays)
+	}
+
+	// 启动时补一次汇总（覆盖停机期间跨日的情况）
+	func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		s.rollupDailyStats(ctx, purgedBefore)
+	}()
+
 	for {
 		select {
 		case <-logTicker.C:
-			if s.retentionDays > 0 {
-				// 使用带超时的context，避免日志清理阻塞关闭流程。
-				// [FIX] P0-4: WithTimeout 的 cancel 必须在每次循环内执行，不能在循环里 defer 到 goroutine 退出。
-				func() {
-					ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-					defer cancel()
+			// 使用带超时的context，避免日志清理阻塞关闭流程。
+			// [FIX] P0-4: WithTimeout 的 cancel 必须在每次循环内执行，不能在循环里 defer 到 goroutine 退出。
+			func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
 
+				// 先汇总再清理，保证被清理的日志已计入 stats_daily
+				s.rollupDailyStats(ctx, purgedBefore)
+				if s.retentionDays > 0 {
 					cutoff := time.Now().AddDate(0, 0, -s.retentionDays)
-					_ = s.store.CleanupLogsBefore(ctx, cutoff)
-				}()
-			}
+					if err := s.store.CleanupLogsBefore(ctx, cutoff); err == nil {
+						purgedBefore = cutoff
+					}
+				}
+			}()
 
 		case <-debugTicker.C:
 			func() {
@@ -315,3 +471,24 @@ func (s *LogService) cleanupOldLogsLoop() {
 		}
 	}
 }
+
+// rollupDailyStats 用日志回填保留期内缺失的 stats_daily 汇总（今天之前、有日志但无汇总行的所有自然日）
+// 今天起由实时用量计数维护；已有汇总的日期跳过（采样后日志不完整，重算会覆盖准确计数）；
+// 自然日起点早于 purgedBefore 的日期跳过，避免用已被部分清理的日志覆盖完整汇总
+func (s *LogService) rollupDailyStats(ctx context.Context, purgedBefore time.Time) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days, err := s.store.ListMissingDailyStatDays(ctx, purgedBefore, today)
+	if err != nil {
+		log.Printf("[WARN] 查询缺失的每日统计失败: %v", err)
+		return
+	}
+	for _, day := range days {
+		if err := s.store.RollupDailyStats(ctx, day); err != nil {
+			log.Printf("[WARN] 汇总每日统计失败（%s）: %v", day.Format("2006-01-02"), err)
+		}
+	}
+	if len(days) > 0 {
+		log.Printf("[INFO] 已回填 %d 天缺失的每日统计", len(days))
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"ccLoad/internal/model"
 	"ccLoad/internal/storage"
+	"ccLoad/internal/testutil"
 )
 
 type retryTrackingStore struct {
@@ -221,3 +222,116 @@ func TestFlushLogs_ShutdownInterruptsBackoff(t *testing.T) {
 		t.Fatalf("shutdown 应快速中断退避，实际耗时=%v", elapsed)
 	}
 }
+
+// TestAddLogAsync_SuccessSampling 验证成功日志按采样率丢弃，错误与非代理日志始终写入
+func TestAddLogAsync_SuccessSampling(t *testing.T) {
+	shutdownCh := make(chan struct{})
+	isShuttingDown := &atomic.Bool{}
+	var wg sync.WaitGroup
+
+	svc := NewLogService(nil, 10, 0, 3, shutdownCh, isShuttingDown, &wg)
+	svc.SetSuccessSampleRate(0)
+
+	svc.AddLogAsync(&model.LogEntry{LogSource: model.LogSourceProxy, StatusCode: 200})
+	svc.AddLogAsync(&model.LogEntry{LogSource: model.LogSourceProxy, StatusCode: 502})
+	svc.AddLogAsync(&model.LogEntry{LogSource: model.LogSourceManualTest, StatusCode: 200})
+
+	if got := len(svc.logChan); got != 2 {
+		t.Fatalf("期望 2 条日志入队（错误+测试），实际=%d", got)
+	}
+	if got := svc.SampledOutCount(); got != 1 {
+		t.Fatalf("期望采样丢弃 1 条，实际=%d", got)
+	}
+
+	svc.SetSuccessSampleRate(1)
+	svc.AddLogAsync(&model.LogEntry{LogSource: model.LogSourceProxy, StatusCode: 200})
+	if got := len(svc.logChan); got != 3 {
+		t.Fatalf("采样率=1 时成功日志应全部入队，实际队列长度=%d", got)
+	}
+}
+
+// TestAddLogAsync_SamplingKeepsUsageTotalsExact 验证采样开启时，成本/计数汇总仍覆盖全部请求
+func TestAddLogAsync_SamplingKeepsUsageTotalsExact(t *testing.T) {
+	store, cleanup := testutil.SetupTestStore(t)
+	defer cleanup()
+
+	shutdownCh := make(chan struct{})
+	isShuttingDown := &atomic.Bool{}
+	var wg sync.WaitGroup
+	svc := NewLogService(store, 100, 0, 3, shutdownCh, isShuttingDown, &wg)
+	svc.SetSuccessSampleRate(0)
+
+	now := time.Now()
+	for range 10 {
+		svc.AddLogAsync(&model.LogEntry{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 200,
+			LogSource: model.LogSourceProxy, InputTokens: 10, OutputTokens: 5, Cost: 0.5, CostMultiplier: 2})
+	}
+	svc.AddLogAsync(&model.LogEntry{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 502, LogSource: model.LogSourceProxy})
+	svc.AddLogAsync(&model.LogEntry{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 200, Cost: 99, LogSource: model.LogSourceManualTest})
+	if got := svc.SampledOutCount(); got != 10 {
+		t.Fatalf("期望采样丢弃 10 条，实际=%d", got)
+	}
+	svc.flushUsage()
+
+	ctx := context.Background()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	channelCosts, err := store.GetTodayChannelCosts(ctx, dayStart)
+	if err != nil {
+		t.Fatalf("GetTodayChannelCosts: %v", err)
+	}
+	if channelCosts[1] != 10 {
+		t.Fatalf("渠道成本=%v，期望 10（采样不影响汇总）", channelCosts[1])
+	}
+	modelCosts, err := store.GetModelCosts(ctx, dayStart)
+	if err != nil {
+		t.Fatalf("GetModelCosts: %v", err)
+	}
+	if modelCosts["m"] != 10 {
+		t.Fatalf("模型成本=%v，期望 10", modelCosts["m"])
+	}
+	stats, err := store.ListDailyStats(ctx, dayStart.Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("ListDailyStats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].SuccessCount != 10 || stats[0].ErrorCount != 1 || stats[0].InputTokens != 100 || stats[0].OutputTokens != 50 {
+		t.Fatalf("stats_daily=%+v，期望 success=10 error=1 input=100 output=50", stats)
+	}
+}
+
+// TestRollupDailyStats_BackfillsMultiDayGap 验证停机多日后启动汇总回填保留期内所有缺失日期
+func TestRollupDailyStats_BackfillsMultiDayGap(t *testing.T) {
+	store, cleanup := testutil.SetupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var entries []*model.LogEntry
+	for _, offset := range []int{-10, -5, -4, -3} {
+		entries = append(entries, &model.LogEntry{Time: model.JSONTime{Time: today.AddDate(0, 0, offset).Add(time.Hour)},
+			Model: "m", ChannelID: 1, StatusCode: 200, Cost: 1, CostMultiplier: 1, LogSource: model.LogSourceProxy})
+	}
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+
+	svc := NewLogService(store, 10, 0, 7, make(chan struct{}), &atomic.Bool{}, &sync.WaitGroup{})
+	svc.rollupDailyStats(ctx, now.AddDate(0, 0, -7))
+
+	stats, err := store.ListDailyStats(ctx, today.AddDate(0, 0, -30).Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("ListDailyStats: %v", err)
+	}
+	var days []string
+	for _, st := range stats {
+		days = append(days, st.Day)
+	}
+	want := []string{
+		today.AddDate(0, 0, -5).Format("2006-01-02"),
+		today.AddDate(0, 0, -4).Format("2006-01-02"),
+		today.AddDate(0, 0, -3).Format("2006-01-02"),
+	}
+	if fmt.Sprint(days) != fmt.Sprint(want) {
+		t.Fatalf("回填日期=%v，期望 %v（保留期外的日期不回填）", days, want)
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ccLoad/internal/util"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// always200Header 请求级兼容开关：非 2xx 响应改写为 HTTP 200 + JSON 错误信封（非标准行为）
+	always200Header = "X-CCLoad-Always-200"
+	// originalStatusHeader 改写后回显真实状态码
+	originalStatusHeader = "X-CCLoad-Original-Status"
+)
+
+// always200Writer 缓冲错误响应，请求结束时以 200 + 信封写出；2xx/3xx 原样透传
+type always200Writer struct {
+	gin.ResponseWriter
+	status    int // 真实状态码（>=400 时进入缓冲模式）
+	buffering bool
+	buf       []byte
+}
+
+// Unwrap 暴露底层 writer，供 http.ResponseController（SetWriteDeadline）使用
+func (w *always200Writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *always200Writer) WriteHeader(code int) {
+	if code >= http.StatusBadRequest && !w.ResponseWriter.Written() {
+		w.status = code
+		w.buffering = true
+		// 让内层压缩中间件旁路，保证缓冲到的是可解析的明文；finish 时移除
+		w.Header().Set("Content-Encoding", "identity")
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *always200Writer) WriteHeaderNow() {
+	if w.buffering {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *always200Writer) Write(data []byte) (int, error) {
+	if w.buffering {
+		w.buf = append(w.buf, data...)
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *always200Writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 错误响应不需要实时下发，缓冲模式下忽略
+func (w *always200Writer) Flush() {
+	if w.buffering {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Status 返回真实状态码，保证日志/中间件看到的仍是原始错误码
+func (w *always200Writer) Status() int {
+	if w.buffering {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *always200Writer) Written() bool {
+	return w.buffering || w.ResponseWriter.Written()
+}
+
+func (w *always200Writer) Size() int {
+	if w.buffering {
+		return len(w.buf)
+	}
+	return w.ResponseWriter.Size()
+}
+
+// finish 写出 200 + 错误信封：原响应为 JSON 对象时追加 status_code 字段，否则包装为 {"error":{"message":...}}
+func (w *always200Writer) finish() {
+	if !w.buffering {
+		return
+	}
+	body := buildAlways200Envelope(w.buf, w.status)
+
+	h := w.Header()
+	h.Del("Content-Encoding")
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set(originalStatusHeader, strconv.Itoa(w.status))
+	w.ResponseWriter.WriteHeader(http.StatusOK)
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+func buildAlways200Envelope(raw []byte, status int) []byte {
+	var obj map[string]any
+	if err := sonic.Unmarshal(raw, &obj); err != nil || obj == nil {
+		msg := strings.TrimSpace(string(raw))
+		if msg == "" {
+			msg = http.StatusText(status)
+		}
+		obj = map[string]any{"error": map[string]any{"message": msg}}
+	}
+	obj["status_code"] = status
+	out, err := sonic.Marshal(obj)
+	if err != nil {
+		return []byte(`{"error":{"message":"internal error"},"status_code":` + strconv.Itoa(status) + `}`)
+	}
+	return out
+}
+
+// Always200Middleware 客户端带 X-CCLoad-Always-200: true 时，将非 2xx 响应改写为 HTTP 200 + JSON 错误信封。
+// 仅为兼容无法处理代理错误码的 SDK；日志与统计仍记录真实状态码。
+// 必须注册在认证中间件之前，才能覆盖 401/403 等认证失败响应。
+func Always200Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !util.ParseBoolDefault(c.GetHeader(always200Header), false) {
+			c.Next()
+			return
+		}
+
+		w := &always200Writer{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish()
+
+		c.Next()
+	}
+}
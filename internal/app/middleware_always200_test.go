@@ -0,0 +1,133 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAlways200_WrapsUpstreamErrorAndKeepsLoggedStatus(t *testing.T) {
+	t.Parallel()
+
+	var gotHeader atomic.Value
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader.Store(r.Header.Get(always200Header))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad prompt","type":"invalid_request_error"}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "always200-model", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "always200-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, map[string]string{always200Header: "true"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want 200: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(originalStatusHeader); got != "400" {
+		t.Fatalf("%s=%q, want 400", originalStatusHeader, got)
+	}
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		StatusCode int `json:"status_code"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &body)
+	if body.StatusCode != http.StatusBadRequest || body.Error.Message != "bad prompt" {
+		t.Fatalf("unexpected envelope: %s", w.Body.String())
+	}
+	if h, _ := gotHeader.Load().(string); h != "" {
+		t.Fatalf("always-200 header leaked upstream: %q", h)
+	}
+
+	entry := waitForProxyLog(t, env, "always200-model")
+	if entry.StatusCode != http.StatusBadRequest {
+		t.Fatalf("logged status=%d, want 400", entry.StatusCode)
+	}
+}
+
+func TestAlways200_CoversAuthFailureAndIsOptIn(t *testing.T) {
+	t.Parallel()
+
+	env := setupProxyTestEnv(t, nil, nil)
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{"model": "m"},
+		map[string]string{"Authorization": "Bearer wrong-key", always200Header: "1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d, want 200: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		StatusCode int `json:"status_code"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &body)
+	if body.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status_code=%d, want 401: %s", body.StatusCode, w.Body.String())
+	}
+
+	// 未带兼容头时保持标准错误码
+	w = doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{"model": "m"},
+		map[string]string{"Authorization": "Bearer wrong-key"})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status=%d, want 401", w.Code)
+	}
+}
+
+func TestBuildAlways200Envelope_WrapsNonJSONBody(t *testing.T) {
+	t.Parallel()
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		StatusCode int `json:"status_code"`
+	}
+	mustUnmarshalJSON(t, buildAlways200Envelope([]byte("upstream exploded\n"), http.StatusBadGateway), &body)
+	if body.StatusCode != http.StatusBadGateway || body.Error.Message != "upstream exploded" {
+		t.Fatalf("unexpected envelope: %+v", body)
+	}
+
+	mustUnmarshalJSON(t, buildAlways200Envelope(nil, http.StatusServiceUnavailable), &body)
+	if body.Error.Message != "Service Unavailable" {
+		t.Fatalf("empty body message=%q", body.Error.Message)
+	}
+}
+
+func TestAlways200_BypassesInnerCompressionForErrors(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Always200Middleware(), ProxyCompressionMiddleware())
+	large := strings.Repeat("x", proxyCompressMinBytes*2)
+	engine.GET("/v1/err", func(c *gin.Context) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": large})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/err", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set(always200Header, "true")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("status=%d content-encoding=%q", w.Code, w.Header().Get("Content-Encoding"))
+	}
+	var body struct {
+		Error      string `json:"error"`
+		StatusCode int    `json:"status_code"`
+	}
+	mustUnmarshalJSON(t, w.Body.Bytes(), &body)
+	if body.StatusCode != http.StatusTooManyRequests || body.Error != large {
+		t.Fatalf("unexpected envelope status=%d len=%d", body.StatusCode, len(body.Error))
+	}
+}
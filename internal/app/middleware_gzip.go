@@ -0,0 +1,208 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+)
+
+// proxyCompressMinBytes 非流式响应达到该大小才压缩（小响应压缩收益低于 CPU 开销）
+const proxyCompressMinBytes = 1024
+
+// resettableCompressor gzip/zlib writer 的公共能力（支持池化复用与按事件刷新）
+type resettableCompressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var (
+	gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	zlibWriterPool = sync.Pool{New: func() any { return zlib.NewWriter(nil) }}
+)
+
+// proxyCompressState 压缩决策状态
+type proxyCompressState int
+
+const (
+	compressUndecided proxyCompressState = iota // 尚未决定（缓冲中）
+	compressActive                              // 已开始压缩
+	compressBypass                              // 原样透传
+)
+
+// proxyCompressWriter 代理响应压缩 writer
+// - 非流式：缓冲至 proxyCompressMinBytes 再决定，过小的响应原样返回
+// - SSE：首次写入即开始压缩，每次 Flush 刷新压缩器，保证事件实时送达
+// - 上游已带 Content-Encoding、已压缩类型、204/304 一律旁路
+type proxyCompressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	comp     resettableCompressor
+	state    proxyCompressState
+	buf      []byte
+}
+
+// Unwrap 暴露底层 writer，供 http.ResponseController（SetWriteDeadline）使用
+func (w *proxyCompressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *proxyCompressWriter) WriteHeader(code int) {
+	if w.state == compressUndecided && (code == http.StatusNoContent || code == http.StatusNotModified) {
+		w.state = compressBypass
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow 头部即将发出，必须立即决定是否压缩
+func (w *proxyCompressWriter) WriteHeaderNow() {
+	if w.state == compressUndecided {
+		if isEventStream(w.Header()) && !shouldBypassProxyCompression(w.Header()) {
+			w.begin()
+		} else {
+			w.bypass()
+		}
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *proxyCompressWriter) Write(data []byte) (int, error) {
+	switch w.state {
+	case compressActive:
+		return w.comp.Write(data)
+	case compressBypass:
+		return w.ResponseWriter.Write(data)
+	}
+
+	if shouldBypassProxyCompression(w.Header()) {
+		if err := w.bypass(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	if isEventStream(w.Header()) {
+		w.begin()
+		return w.comp.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= proxyCompressMinBytes {
+		w.begin()
+		buffered := w.buf
+		w.buf = nil
+		if _, err := w.comp.Write(buffered); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *proxyCompressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush SSE 每个事件后调用：刷新压缩器输出完整 deflate 块；未决定时视为流式小包，直接旁路
+func (w *proxyCompressWriter) Flush() {
+	switch w.state {
+	case compressActive:
+		_ = w.comp.Flush()
+	case compressUndecided:
+		_ = w.bypass()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// begin 开始压缩：设置编码头并挂接压缩器
+func (w *proxyCompressWriter) begin() {
+	h := w.Header()
+	h.Set("Content-Encoding", w.encoding)
+	addVaryAcceptEncoding(h)
+	h.Del("Content-Length")
+	w.comp = w.pool.Get().(resettableCompressor)
+	w.comp.Reset(w.ResponseWriter)
+	w.state = compressActive
+}
+
+// bypass 放弃压缩，写出已缓冲的数据
+func (w *proxyCompressWriter) bypass() error {
+	w.state = compressBypass
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buffered := w.buf
+	w.buf = nil
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// finish 请求结束：关闭压缩器（写出尾部）或写出剩余缓冲
+func (w *proxyCompressWriter) finish() {
+	switch w.state {
+	case compressActive:
+		_ = w.comp.Close()
+		w.comp.Reset(io.Discard)
+		w.pool.Put(w.comp)
+		w.comp = nil
+	case compressUndecided:
+		_ = w.bypass()
+	}
+}
+
+// isEventStream 判断响应是否为 SSE
+func isEventStream(h http.Header) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(h.Get("Content-Type"))), "text/event-stream")
+}
+
+// shouldBypassProxyCompression 上游已编码或已压缩类型时不再压缩（SSE 不旁路，与 zstd 中间件不同）
+func shouldBypassProxyCompression(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" {
+		return true
+	}
+	if isEventStream(h) {
+		return false
+	}
+	return shouldBypassResponse(h)
+}
+
+// negotiateProxyEncoding 按 Accept-Encoding 选择压缩算法（优先 gzip）
+func negotiateProxyEncoding(header string) (string, *sync.Pool) {
+	switch {
+	case acceptsEncoding(header, "gzip"):
+		return "gzip", &gzipWriterPool
+	case acceptsEncoding(header, "deflate"):
+		return "deflate", &zlibWriterPool
+	}
+	return "", nil
+}
+
+// ProxyCompressionMiddleware 代理响应压缩中间件（CCLOAD_COMPRESS_RESPONSES=1 启用）
+// 上游响应已被 Transport 解压，按客户端 Accept-Encoding 重新以 gzip/deflate 压缩
+func ProxyCompressionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		encoding, pool := negotiateProxyEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		w := &proxyCompressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			pool:           pool,
+		}
+		c.Writer = w
+		defer w.finish()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newProxyCompressEngine(handler gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Group("/v1", ProxyCompressionMiddleware()).POST("/chat/completions", handler)
+	return r
+}
+
+func TestProxyCompression_LargeJSONIsGzipped(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"text":"` + strings.Repeat("a", 4096) + `"}`
+	r := newProxyCompressEngine(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(payload))
+	})
+
+	req := newRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := serveHTTP(t, r, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding=%q, want gzip", got)
+	}
+	if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+		t.Fatalf("Vary=%q, want Accept-Encoding", w.Header().Get("Vary"))
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if string(got) != payload {
+		t.Fatalf("decompressed body mismatch (len=%d)", len(got))
+	}
+}
+
+func TestProxyCompression_DeflateNegotiation(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("b", 2048)
+	r := newProxyCompressEngine(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(payload))
+	})
+
+	req := newRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0, deflate")
+	w := serveHTTP(t, r, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "deflate" {
+		t.Fatalf("Content-Encoding=%q, want deflate", got)
+	}
+	zr, err := zlib.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("zlib.NewReader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if string(got) != payload {
+		t.Fatalf("decompressed body mismatch (len=%d)", len(got))
+	}
+}
+
+func TestProxyCompression_SmallResponseNotCompressed(t *testing.T) {
+	t.Parallel()
+
+	r := newProxyCompressEngine(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(`{"ok":true}`))
+	})
+
+	req := newRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := serveHTTP(t, r, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding=%q, want empty", got)
+	}
+	if w.Body.String() != `{"ok":true}` {
+		t.Fatalf("body=%q", w.Body.String())
+	}
+}
+
+func TestProxyCompression_NotAcceptedOrAlreadyEncoded(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("c", 2048)
+	r := newProxyCompressEngine(func(c *gin.Context) {
+		if c.Query("encoded") == "1" {
+			c.Header("Content-Encoding", "br")
+		}
+		c.Data(http.StatusOK, "application/json", []byte(payload))
+	})
+
+	req := newRequest(http.MethodPost, "/v1/chat/completions", nil)
+	w := serveHTTP(t, r, req)
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("no Accept-Encoding: Content-Encoding=%q, want empty", got)
+	}
+	if w.Body.String() != payload {
+		t.Fatal("no Accept-Encoding: body should be passed through unchanged")
+	}
+
+	req = newRequest(http.MethodPost, "/v1/chat/completions?encoded=1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = serveHTTP(t, r, req)
+	if got := w.Header().Get("Content-Encoding"); got != "br" {
+		t.Fatalf("upstream encoding: Content-Encoding=%q, want br", got)
+	}
+	if w.Body.String() != payload {
+		t.Fatal("upstream encoding: body should be passed through unchanged")
+	}
+}
+
+func TestProxyCompression_SSEFlushesEachEvent(t *testing.T) {
+	t.Parallel()
+
+	events := []string{"data: {\"n\":1}\n\n", "data: {\"n\":2}\n\n", "data: [DONE]\n\n"}
+	var snapshots []int
+	r := newProxyCompressEngine(func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		for _, ev := range events {
+			_, _ = c.Writer.WriteString(ev)
+			c.Writer.Flush()
+			snapshots = append(snapshots, c.Writer.(*proxyCompressWriter).ResponseWriter.Size())
+		}
+	})
+
+	req := newRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := serveHTTP(t, r, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding=%q, want gzip", got)
+	}
+	for i := 1; i < len(snapshots); i++ {
+		if snapshots[i] <= snapshots[i-1] {
+			t.Fatalf("event %d was not flushed to the client: sizes=%v", i, snapshots)
+		}
+	}
+
+	// 首个事件刷新后的字节即可独立解出该事件
+	zr, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()[:snapshots[0]]))
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	first := make([]byte, len(events[0]))
+	if _, err := io.ReadFull(zr, first); err != nil {
+		t.Fatalf("read first event: %v", err)
+	}
+	if string(first) != events[0] {
+		t.Fatalf("first event=%q, want %q", first, events[0])
+	}
+
+	zr, err = gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	all, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if string(all) != strings.Join(events, "") {
+		t.Fatalf("stream=%q", all)
+	}
+}
@@ -156,6 +156,11 @@ func shouldBypassResponse(h http.Header) bool {
 
 // acceptsZstd 按 token 解析 Accept-Encoding 头，识别 zstd 支持并处理 q=0 显式拒绝。
 func acceptsZstd(header string) bool {
+	return acceptsEncoding(header, "zstd")
+}
+
+// acceptsEncoding 按 token 解析 Accept-Encoding 头，判断是否接受指定编码（q=0 视为显式拒绝）。
+func acceptsEncoding(header, encoding string) bool {
 	if header == "" {
 		return false
 	}
@@ -165,7 +170,7 @@ func acceptsZstd(header string) bool {
 			continue
 		}
 		name, params, _ := strings.Cut(tok, ";")
-		if !strings.EqualFold(strings.TrimSpace(name), "zstd") {
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
 			continue
 		}
 		rejected := false
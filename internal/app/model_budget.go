@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"ccLoad/internal/storage"
+
+	"github.com/bytedance/sonic"
+)
+
+// ModelBudget 模型月度预算
+// 预算来自系统设置 model_monthly_budgets（启动时加载，修改后重启生效）；
+// 本月已消耗成本启动时从数据库加载，请求完成后累加，跨月自动重置
+type ModelBudget struct {
+	budgets map[string]float64 // model -> 月度预算（USD，倍率后成本）
+	enforce bool               // 超预算时拒绝请求（402）
+
+	mu         sync.Mutex
+	spent      map[string]float64 // model -> 本月已消耗成本
+	monthStart time.Time          // 当前统计周期的月初0点
+}
+
+// NewModelBudget 创建模型预算（budgets 为空时不追踪任何模型）
+func NewModelBudget(budgets map[string]float64, enforce bool) *ModelBudget {
+	if budgets == nil {
+		budgets = map[string]float64{}
+	}
+	return &ModelBudget{
+		budgets:    budgets,
+		enforce:    enforce,
+		spent:      make(map[string]float64),
+		monthStart: monthStart(time.Now()),
+	}
+}
+
+// monthStart 返回给定时间当月1日0点
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// parseModelBudgets 解析 model_monthly_budgets（JSON 对象：模型名 -> 预算USD，必须 > 0）
+func parseModelBudgets(raw string) (map[string]float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return map[string]float64{}, nil
+	}
+	var parsed map[string]float64
+	if err := sonic.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, fmt.Errorf("must be a JSON object of model to USD budget")
+	}
+	budgets := make(map[string]float64, len(parsed))
+	for name, budget := range parsed {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("model name cannot be empty")
+		}
+		if math.IsNaN(budget) || math.IsInf(budget, 0) || budget <= 0 {
+			return nil, fmt.Errorf("budget for %s must be > 0", name)
+		}
+		budgets[name] = budget
+	}
+	return budgets, nil
+}
+
+// checkAndResetIfNewMonth 检查是否跨月，如果是则重置已消耗成本
+// 调用方必须持有锁
+func (b *ModelBudget) checkAndResetIfNewMonth(now time.Time) {
+	month := monthStart(now)
+	if !month.Equal(b.monthStart) {
+		b.spent = make(map[string]float64)
+		b.monthStart = month
+	}
+}
+
+// Enabled 是否配置了任何模型预算
+func (b *ModelBudget) Enabled() bool {
+	return len(b.budgets) > 0
+}
+
+// Budgets 返回预算配置副本
+func (b *ModelBudget) Budgets() map[string]float64 {
+	result := make(map[string]float64, len(b.budgets))
+	for k, v := range b.budgets {
+		result[k] = v
+	}
+	return result
+}
+
+// Add 累加模型成本（请求完成后调用；未配置预算的模型不追踪）
+func (b *ModelBudget) Add(modelName string, cost float64) {
+	if cost <= 0 {
+		return
+	}
+	if _, ok := b.budgets[modelName]; !ok {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkAndResetIfNewMonth(time.Now())
+	b.spent[modelName] += cost
+}
+
+// Exceeded 检查模型是否已超出月度预算（仅 enforce 开启时生效）
+func (b *ModelBudget) Exceeded(modelName string) (spent, budget float64, exceeded bool) {
+	if !b.enforce {
+		return 0, 0, false
+	}
+	budget, ok := b.budgets[modelName]
+	if !ok {
+		return 0, 0, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkAndResetIfNewMonth(time.Now())
+	spent = b.spent[modelName]
+	return spent, budget, spent >= budget
+}
+
+// Load 加载本月已消耗成本（启动时调用）
+func (b *ModelBudget) Load(costs map[string]float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.monthStart = monthStart(time.Now())
+	b.spent = make(map[string]float64, len(b.budgets))
+	for name := range b.budgets {
+		if v, ok := costs[name]; ok {
+			b.spent[name] = v
+		}
+	}
+}
+
+// MonthStart 返回当前统计周期的月初0点（用于查询数据库）
+func (b *ModelBudget) MonthStart() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkAndResetIfNewMonth(time.Now())
+
+	return b.monthStart
+}
+
+// loadModelBudget 从 ConfigService 读取模型预算配置，无效配置仅告警并禁用预算
+func loadModelBudget(cs *ConfigService) *ModelBudget {
+	budgets, err := parseModelBudgets(cs.GetString("model_monthly_budgets", "{}"))
+	if err != nil {
+		log.Printf("[WARN] 无效的 model_monthly_budgets: %v，已禁用模型预算", err)
+		budgets = nil
+	}
+	return NewModelBudget(budgets, cs.GetBool("model_budget_enforce", false))
+}
+
+// bootstrapModelBudget 启动时从数据库恢复本月各模型成本，失败仅记录 WARN
+func bootstrapModelBudget(store storage.Store, budget *ModelBudget) {
+	if !budget.Enabled() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	costs, err := store.GetModelCosts(ctx, budget.MonthStart())
+	if err != nil {
+		log.Printf("[WARN] 加载本月模型成本失败: %v（模型预算检查可能不准确）", err)
+		return
+	}
+	budget.Load(costs)
+	log.Printf("[INFO] 已加载本月模型成本（%d个模型配置了预算）", len(budget.budgets))
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestParseModelBudgets(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseModelBudgets(`{"gpt-4o": 100, "claude-sonnet-4": 50.5}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got["gpt-4o"] != 100 || got["claude-sonnet-4"] != 50.5 {
+		t.Fatalf("unexpected budgets: %v", got)
+	}
+
+	if got, err := parseModelBudgets(""); err != nil || len(got) != 0 {
+		t.Fatalf("empty: got=%v err=%v", got, err)
+	}
+
+	for _, raw := range []string{`[1,2]`, `{"gpt-4o": 0}`, `{"gpt-4o": -1}`, `{"": 10}`, `{"gpt-4o": "10"}`} {
+		if _, err := parseModelBudgets(raw); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
+
+func TestValidateSettingValue_ModelMonthlyBudgets(t *testing.T) {
+	t.Parallel()
+
+	if err := validateSettingValue("model_monthly_budgets", "string", `{"gpt-4o": 10}`); err != nil {
+		t.Fatalf("valid budgets rejected: %v", err)
+	}
+	if err := validateSettingValue("model_monthly_budgets", "string", `{"gpt-4o": -10}`); err == nil {
+		t.Fatal("negative budget accepted")
+	}
+}
+
+func TestModelBudget_AddAndExceeded(t *testing.T) {
+	t.Parallel()
+
+	b := NewModelBudget(map[string]float64{"gpt-4o": 1}, true)
+	b.Add("gpt-4o", 0.6)
+	b.Add("other-model", 5) // 未配置预算的模型不追踪
+	if _, _, exceeded := b.Exceeded("gpt-4o"); exceeded {
+		t.Fatal("should not exceed at 0.6/1")
+	}
+	b.Add("gpt-4o", 0.4)
+	spent, budget, exceeded := b.Exceeded("gpt-4o")
+	if !exceeded || spent != 1 || budget != 1 {
+		t.Fatalf("spent=%v budget=%v exceeded=%v", spent, budget, exceeded)
+	}
+	if _, _, exceeded := b.Exceeded("other-model"); exceeded {
+		t.Fatal("model without budget must never exceed")
+	}
+
+	// 未开启 enforce 时只统计不拦截
+	report := NewModelBudget(map[string]float64{"gpt-4o": 1}, false)
+	report.Add("gpt-4o", 2)
+	if _, _, exceeded := report.Exceeded("gpt-4o"); exceeded {
+		t.Fatal("enforce disabled should not block")
+	}
+}
+
+func TestModelBudget_ResetsOnNewMonth(t *testing.T) {
+	t.Parallel()
+
+	b := NewModelBudget(map[string]float64{"gpt-4o": 1}, true)
+	b.Add("gpt-4o", 2)
+	b.mu.Lock()
+	b.monthStart = b.monthStart.AddDate(0, -1, 0)
+	b.mu.Unlock()
+
+	if _, _, exceeded := b.Exceeded("gpt-4o"); exceeded {
+		t.Fatal("spent should reset when month changes")
+	}
+}
+
+func TestProxy_ModelBudgetExceeded_Returns402(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	env.server.modelBudget = NewModelBudget(map[string]float64{"gpt-4": 0.5}, true)
+	env.server.modelBudget.Add("gpt-4", 0.5)
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+
+	if w.Code != http.StatusPaymentRequired {
+		t.Fatalf("expected 402, got %d: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, "model_budget_exceeded") {
+		t.Fatalf("expected 'model_budget_exceeded' in body: %s", body)
+	}
+}
+
+func TestHandleGetBudget_ReportsSpendFromUsageCounters(t *testing.T) {
+	srv := newInMemoryServer(t)
+	srv.modelBudget = NewModelBudget(map[string]float64{"gpt-4": 1, "claude-sonnet-4": 10}, false)
+
+	now := time.Now()
+	entries := []*model.LogEntry{
+		{Time: model.JSONTime{Time: now}, Model: "gpt-4", ChannelID: 1, StatusCode: 200, Cost: 0.8, CostMultiplier: 1, LogSource: model.LogSourceProxy},
+		{Time: model.JSONTime{Time: now}, Model: "gpt-4", ChannelID: 1, StatusCode: 200, Cost: 0.2, CostMultiplier: 2, LogSource: model.LogSourceProxy},
+		{Time: model.JSONTime{Time: now}, Model: "claude-sonnet-4", ChannelID: 1, StatusCode: 200, Cost: 3, CostMultiplier: 1, LogSource: model.LogSourceProxy},
+		// 检测日志不计入预算
+		{Time: model.JSONTime{Time: now}, Model: "claude-sonnet-4", ChannelID: 1, StatusCode: 200, Cost: 100, CostMultiplier: 1, LogSource: model.LogSourceScheduledCheck},
+	}
+	for _, e := range entries {
+		srv.logService.AddLogAsync(e)
+	}
+	srv.logService.flushUsage()
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/budget", nil))
+	srv.HandleGetBudget(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	resp := mustParseAPIResponse[ModelBudgetResponse](t, w.Body.Bytes())
+	if len(resp.Data.Items) != 2 {
+		t.Fatalf("items=%+v", resp.Data.Items)
+	}
+	claude, gpt := resp.Data.Items[0], resp.Data.Items[1]
+	if claude.Model != "claude-sonnet-4" || claude.Spent != 3 || claude.Remaining != 7 || claude.Exceeded {
+		t.Fatalf("claude item=%+v", claude)
+	}
+	if gpt.Model != "gpt-4" || gpt.Spent < 1.19 || gpt.Spent > 1.21 || gpt.Remaining != 0 || !gpt.Exceeded {
+		t.Fatalf("gpt item=%+v", gpt)
+	}
+}
+
+func TestHandleGetBudget_NoBudgetsConfigured(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/budget", nil))
+	srv.HandleGetBudget(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	resp := mustParseAPIResponse[ModelBudgetResponse](t, w.Body.Bytes())
+	if len(resp.Data.Items) != 0 {
+		t.Fatalf("expected no items, got %+v", resp.Data.Items)
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/util"
+)
+
+// azureDefaultAPIVersion 渠道 URL 与客户端均未指定 api-version 时使用的 GA 版本
+const azureDefaultAPIVersion = "2024-10-21"
+
+// azureDeploymentEndpoints 需要改写为 /openai/deployments/{deployment}/... 的 OpenAI 端点
+var azureDeploymentEndpoints = []string{
+	"/chat/completions",
+	"/completions",
+	"/embeddings",
+	"/images/generations",
+	"/audio/transcriptions",
+	"/audio/translations",
+	"/audio/speech",
+}
+
+// usesAzureOpenAI 本次请求是否按 Azure OpenAI 方式转发：azure 类型渠道且上游为 OpenAI 协议
+// 精确 URL（# 标记）由管理员自行拼好完整路径，不做改写
+func usesAzureOpenAI(cfg *model.Config, upstreamProtocol, baseURL string) bool {
+	if cfg == nil || cfg.GetChannelType() != util.ChannelTypeAzure {
+		return false
+	}
+	return upstreamProtocol == util.ChannelTypeOpenAI && !model.HasExactUpstreamURLMarker(baseURL)
+}
+
+// buildAzureUpstreamURL 将 OpenAI 路径改写为 Azure 部署路径
+// /v1/chat/completions → /openai/deployments/{deployment}/chat/completions?api-version=...
+// deployment 取重定向后的上游模型名（ModelRedirects 即模型→部署名映射）。
+// api-version 优先级：客户端查询参数 > 渠道 URL 查询参数 > azureDefaultAPIVersion。
+// 非部署类端点返回 ok=false，调用方按普通 OpenAI 路径处理。
+func buildAzureUpstreamURL(baseURL, requestPath, rawQuery, deployment string) (string, bool) {
+	endpoint := strings.TrimPrefix(requestPath, "/v1")
+	if deployment == "" || !slices.Contains(azureDeploymentEndpoints, endpoint) {
+		return "", false
+	}
+	base, err := neturl.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return "", false
+	}
+
+	query, _ := neturl.ParseQuery(rawQuery)
+	query.Del("key")
+	if query.Get("api-version") == "" {
+		apiVersion := base.Query().Get("api-version")
+		if apiVersion == "" {
+			apiVersion = azureDefaultAPIVersion
+		}
+		query.Set("api-version", apiVersion)
+	}
+
+	basePath := strings.TrimRight(base.Path, "/")
+	basePath = strings.TrimSuffix(basePath, "/openai")
+	base.Path = basePath + "/openai/deployments/" + neturl.PathEscape(deployment) + endpoint
+	base.RawPath = ""
+	base.RawQuery = query.Encode()
+	base.Fragment = ""
+	return base.String(), true
+}
+
+// stripURLQuery 去除 URL 的查询串与片段
+func stripURLQuery(raw string) string {
+	if i := strings.IndexAny(raw, "?#"); i >= 0 {
+		return raw[:i]
+	}
+	return raw
+}
+
+// injectAzureAPIKeyHeader Azure OpenAI 使用 api-key 头认证，不接受 Bearer
+func injectAzureAPIKeyHeader(h http.Header, apiKey string) {
+	h.Del("Authorization")
+	h.Del("x-api-key")
+	h.Set("api-key", apiKey)
+}
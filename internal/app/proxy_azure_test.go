@@ -0,0 +1,169 @@
+package app
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"ccLoad/internal/model"
+	"ccLoad/internal/util"
+)
+
+func TestBuildAzureUpstreamURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name, base, path, query, deployment string
+		want                                string
+		ok                                  bool
+	}{
+		{
+			name: "chat completions with default api-version",
+			base: "https://res.openai.azure.com", path: "/v1/chat/completions", deployment: "gpt4o-prod",
+			want: "https://res.openai.azure.com/openai/deployments/gpt4o-prod/chat/completions?api-version=" + azureDefaultAPIVersion,
+			ok:   true,
+		},
+		{
+			name: "api-version from channel url, trailing /openai trimmed",
+			base: "https://res.openai.azure.com/openai/?api-version=2025-01-01-preview", path: "/v1/embeddings", deployment: "embed",
+			want: "https://res.openai.azure.com/openai/deployments/embed/embeddings?api-version=2025-01-01-preview",
+			ok:   true,
+		},
+		{
+			name: "client api-version wins and key param is dropped",
+			base: "https://res.openai.azure.com?api-version=2024-06-01", path: "/v1/chat/completions", query: "api-version=2024-10-21&key=secret", deployment: "d",
+			want: "https://res.openai.azure.com/openai/deployments/d/chat/completions?api-version=2024-10-21",
+			ok:   true,
+		},
+		{
+			name: "non deployment endpoint falls back",
+			base: "https://res.openai.azure.com", path: "/v1/models", deployment: "d",
+		},
+		{
+			name: "missing deployment falls back",
+			base: "https://res.openai.azure.com", path: "/v1/chat/completions",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := buildAzureUpstreamURL(tc.base, tc.path, tc.query, tc.deployment)
+			if ok != tc.ok || got != tc.want {
+				t.Fatalf("got (%q, %v), want (%q, %v)", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestUsesAzureOpenAI(t *testing.T) {
+	t.Parallel()
+
+	azure := &model.Config{ChannelType: util.ChannelTypeAzure}
+	openai := &model.Config{ChannelType: util.ChannelTypeOpenAI}
+	cases := []struct {
+		name     string
+		cfg      *model.Config
+		protocol string
+		base     string
+		want     bool
+	}{
+		{"azure channel on custom gateway host", azure, util.ChannelTypeOpenAI, "https://gateway.example.com", true},
+		{"openai channel on azure host", openai, util.ChannelTypeOpenAI, "https://res.openai.azure.com", false},
+		{"azure channel with non-openai upstream", azure, util.ChannelTypeAnthropic, "https://res.openai.azure.com", false},
+		{"azure channel with exact url", azure, util.ChannelTypeOpenAI, "https://res.openai.azure.com/openai/deployments/d/chat/completions#", false},
+	}
+	for _, tc := range cases {
+		if got := usesAzureOpenAI(tc.cfg, tc.protocol, tc.base); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProxy_AzureOpenAIRewritesPathAndAuth(t *testing.T) {
+	// 非 azure.com 域名（如企业网关）同样按渠道类型改写
+	const host = "ccload-test-azure-gateway.example"
+
+	var (
+		mu      sync.Mutex
+		gotPath string
+		gotVer  string
+		gotKey  string
+		gotAuth string
+	)
+	testHTTPServerRegistry.Store(host, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		gotPath = r.URL.Path
+		gotVer = r.URL.Query().Get("api-version")
+		gotKey = r.Header.Get("api-key")
+		gotAuth = r.Header.Get("Authorization")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(func() { testHTTPServerRegistry.Delete(host) })
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "azure", channelType: util.ChannelTypeAzure, models: "gpt-4o", apiKey: "azure-secret"},
+	}, map[int]string{0: "http://" + host})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4o",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotPath != "/openai/deployments/gpt-4o/chat/completions" {
+		t.Fatalf("upstream path=%q", gotPath)
+	}
+	if gotVer != azureDefaultAPIVersion {
+		t.Fatalf("api-version=%q", gotVer)
+	}
+	if gotKey != "azure-secret" || gotAuth != "" {
+		t.Fatalf("auth headers: api-key=%q Authorization=%q", gotKey, gotAuth)
+	}
+}
+
+func TestProxy_OpenAIChannelOnAzureHostNotRewritten(t *testing.T) {
+	const host = "ccload-test.openai.azure.com"
+
+	var (
+		mu      sync.Mutex
+		gotPath string
+		gotKey  string
+		gotAuth string
+	)
+	testHTTPServerRegistry.Store(host, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("api-key")
+		gotAuth = r.Header.Get("Authorization")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(func() { testHTTPServerRegistry.Delete(host) })
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "openai-compatible", channelType: util.ChannelTypeOpenAI, models: "gpt-4o", apiKey: "sk-plain"},
+	}, map[int]string{0: "http://" + host})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4o",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotPath != "/v1/chat/completions" {
+		t.Fatalf("upstream path=%q, want unchanged OpenAI path", gotPath)
+	}
+	if gotKey != "" || gotAuth != "Bearer sk-plain" {
+		t.Fatalf("auth headers: api-key=%q Authorization=%q", gotKey, gotAuth)
+	}
+}
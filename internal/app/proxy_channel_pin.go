@@ -0,0 +1,34 @@
+package app
+
+import (
+	"strconv"
+
+	"ccLoad/internal/model"
+)
+
+// channelPinHeader 请求级渠道固定头（值为渠道 ID 或名称），仅用于本服务选路，不透传上游
+const channelPinHeader = "X-CCLoad-Channel"
+
+// pickPinnedChannel 从候选中找出被固定的渠道（按 ID 或名称精确匹配）
+// 候选已完成模型/协议/冷却/成本过滤；全冷却兜底产生的候选不算可用，固定请求不会落到冷却中的渠道
+func pickPinnedChannel(cands []*model.Config, pin string) (*model.Config, bool) {
+	id, err := strconv.ParseInt(pin, 10, 64)
+	isID := err == nil
+	for _, cfg := range cands {
+		if cfg == nil || cfg.CooldownFallback {
+			continue
+		}
+		if (isID && cfg.ID == id) || cfg.Name == pin {
+			return cfg, true
+		}
+	}
+	return nil, false
+}
+
+// channelPinFallbackEnabled 固定渠道不可用时是否回退正常选路（默认关闭，直接返回 503）
+func (s *Server) channelPinFallbackEnabled() bool {
+	if s.configService == nil {
+		return false
+	}
+	return s.configService.GetBool("channel_pin_fallback", false)
+}
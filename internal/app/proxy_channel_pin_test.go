@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestProxy_ChannelPinHeader(t *testing.T) {
+	var hitsA, hitsB atomic.Int32
+	var leakedPin atomic.Bool
+	newUpstream := func(hits *atomic.Int32) *testHTTPServer {
+		return newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			if r.Header.Get(channelPinHeader) != "" {
+				leakedPin.Store(true)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+		}))
+	}
+	upA := newUpstream(&hitsA)
+	defer upA.Close()
+	upB := newUpstream(&hitsB)
+	defer upB.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "primary", channelType: "openai", models: "gpt-4", apiKey: "sk-a"},
+		{name: "secondary", channelType: "openai", models: "gpt-4", apiKey: "sk-b"},
+	}, map[int]string{0: upA.URL, 1: upB.URL})
+
+	body := map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}
+
+	t.Run("pin by name bypasses priority", func(t *testing.T) {
+		w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{channelPinHeader: "secondary"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		if hitsA.Load() != 0 || hitsB.Load() != 1 {
+			t.Fatalf("hits primary=%d secondary=%d, want 0/1", hitsA.Load(), hitsB.Load())
+		}
+		if leakedPin.Load() {
+			t.Fatal("pin header must not be forwarded upstream")
+		}
+	})
+
+	t.Run("pin by id", func(t *testing.T) {
+		cfgs, err := env.store.ListConfigs(context.Background())
+		if err != nil {
+			t.Fatalf("list configs: %v", err)
+		}
+		var primaryID int64
+		for _, cfg := range cfgs {
+			if cfg.Name == "primary" {
+				primaryID = cfg.ID
+			}
+		}
+		w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{channelPinHeader: strconv.FormatInt(primaryID, 10)})
+		if w.Code != http.StatusOK || hitsA.Load() != 1 {
+			t.Fatalf("status=%d primary hits=%d body=%s", w.Code, hitsA.Load(), w.Body.String())
+		}
+	})
+
+	t.Run("unavailable pin returns 503", func(t *testing.T) {
+		before := hitsA.Load() + hitsB.Load()
+		w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{channelPinHeader: "missing"})
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		if hitsA.Load()+hitsB.Load() != before {
+			t.Fatal("unsatisfied pin must not reach any upstream")
+		}
+	})
+
+	t.Run("fallback setting routes normally", func(t *testing.T) {
+		// 配置修改后重启生效，测试直接写入已加载的配置缓存
+		cs := env.server.configService
+		cs.mu.Lock()
+		cs.cache["channel_pin_fallback"] = &model.SystemSetting{Key: "channel_pin_fallback", Value: "true"}
+		cs.mu.Unlock()
+		w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{channelPinHeader: "missing"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+	})
+}
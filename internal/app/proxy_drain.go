@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// getDrainOnDisconnect 延迟解析 CCLOAD_DRAIN_ON_DISCONNECT（秒，默认0=关闭）
+// 开启后流式请求的客户端中途断开时，不再立即关闭上游连接，而是在后台继续读取并丢弃剩余响应，
+// 最长排空该时长，让上游正常结束（部分供应商会对频繁的连接重置计费异常或限流）
+var getDrainOnDisconnect = sync.OnceValue(func() time.Duration {
+	raw := os.Getenv("CCLOAD_DRAIN_ON_DISCONNECT")
+	if raw == "" {
+		return 0
+	}
+	sec, err := strconv.Atoi(raw)
+	if err != nil || sec < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_DRAIN_ON_DISCONNECT=%s（必须为非负整数秒），已关闭断开后排空", raw)
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+})
+
+// disconnectDrain 单次流式转发的断开后排空控制
+// 上游请求使用不随客户端取消的独立 context；仅当客户端断开（非首字节超时、非关停）时转入排空，
+// 其余情况与未开启时一致：立即关闭响应体并取消上游请求
+type disconnectDrain struct {
+	s         *Server
+	clientCtx context.Context
+	reqCtx    *requestContext
+	timeout   time.Duration
+	cancel    context.CancelFunc
+	stopLink  func() bool
+	body      io.ReadCloser // 原始上游响应体（attach 后非 nil）
+	attached  atomic.Bool
+	draining  atomic.Bool
+}
+
+// newDisconnectDrain 为流式请求准备断开后排空；未开启或非流式时返回 nil
+// 替换 reqCtx.upstreamCtx：收到响应头前客户端断开仍立即取消上游请求
+func (s *Server) newDisconnectDrain(clientCtx context.Context, reqCtx *requestContext) *disconnectDrain {
+	timeout := getDrainOnDisconnect()
+	if timeout <= 0 || !reqCtx.isStreaming {
+		return nil
+	}
+	upstreamCtx, cancel := context.WithCancel(context.WithoutCancel(reqCtx.ctx))
+	d := &disconnectDrain{
+		s:         s,
+		clientCtx: clientCtx,
+		reqCtx:    reqCtx,
+		timeout:   timeout,
+		cancel:    cancel,
+	}
+	d.stopLink = context.AfterFunc(reqCtx.ctx, func() {
+		if !d.attached.Load() || !d.shouldDrain() {
+			cancel()
+		}
+	})
+	reqCtx.upstreamCtx = upstreamCtx
+	return d
+}
+
+// shouldDrain 仅客户端主动断开时排空；首字节超时与服务关停仍立即中断上游
+func (d *disconnectDrain) shouldDrain() bool {
+	return d.clientCtx.Err() != nil && !d.reqCtx.firstByteTimeoutTriggered() && !d.s.isShuttingDown.Load()
+}
+
+// attach 包装原始响应体：客户端断开引发的 Close 转为排空标记，由 finish 在读取方退出后接管
+func (d *disconnectDrain) attach(body io.ReadCloser) io.ReadCloser {
+	d.body = body
+	d.attached.Store(true)
+	return &drainOnCloseBody{ReadCloser: body, drain: d}
+}
+
+// finish 转发结束时调用（此时已无其他读取方）：需要排空则启动受 s.wg 管理的后台 goroutine，否则取消上游请求
+func (d *disconnectDrain) finish() {
+	if d == nil {
+		return
+	}
+	d.stopLink()
+	if !d.draining.Load() || d.body == nil || d.s.isShuttingDown.Load() {
+		if d.body != nil {
+			_ = d.body.Close()
+		}
+		d.cancel()
+		return
+	}
+	d.s.wg.Add(1)
+	go d.run()
+}
+
+func (d *disconnectDrain) run() {
+	defer d.s.wg.Done()
+	defer d.cancel()
+
+	// 超时或服务关停时关闭响应体，打断阻塞的 Read
+	timer := time.AfterFunc(d.timeout, func() { _ = d.body.Close() })
+	stopOnShutdown := context.AfterFunc(d.s.baseCtx, func() { _ = d.body.Close() })
+	start := time.Now()
+	n, err := io.Copy(io.Discard, d.body)
+	timedOut := !timer.Stop()
+	stopOnShutdown()
+	_ = d.body.Close()
+
+	if err != nil || timedOut {
+		log.Printf("[INFO] [断开后排空] 客户端已断开，排空上游 %d 字节后中止（耗时=%v, 上限=%v, err=%v）", n, time.Since(start).Round(time.Millisecond), d.timeout, err)
+		return
+	}
+	log.Printf("[INFO] [断开后排空] 客户端已断开，上游响应已读完 %d 字节（耗时=%v）", n, time.Since(start).Round(time.Millisecond))
+}
+
+// drainOnCloseBody 客户端断开后的 Close 不关闭上游连接，仅标记进入排空
+type drainOnCloseBody struct {
+	io.ReadCloser
+	drain *disconnectDrain
+}
+
+func (b *drainOnCloseBody) Close() error {
+	if b.drain.shouldDrain() {
+		b.drain.draining.Store(true)
+		return nil
+	}
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+// runDrainTestForward 发起一次流式转发，待上游发出首块后取消客户端 context，返回转发错误
+func runDrainTestForward(t *testing.T, srv *Server, upstreamURL string, upstreamStarted <-chan struct{}) error {
+	t.Helper()
+
+	cfg := &model.Config{ID: 1, Name: "drain", URL: upstreamURL}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := srv.forwardOnceAsync(ctx, cfg, "sk-test", http.MethodPost,
+			mustBuildTestTransformPlan(t, cfg, []byte(`{"stream":true}`)), http.Header{}, "", cfg.URL, newRecorder(), nil)
+		done <- err
+	}()
+
+	select {
+	case <-upstreamStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream did not start streaming")
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("forwardOnceAsync did not return after client cancel")
+	}
+	return nil
+}
+
+// assertDrainGoroutinesExit 排空 goroutine 纳入 s.wg，Shutdown 能在期限内完成即说明无残留
+func assertDrainGoroutinesExit(t *testing.T, srv *Server) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func overrideDrainOnDisconnect(t *testing.T, d time.Duration) {
+	t.Helper()
+	orig := getDrainOnDisconnect
+	getDrainOnDisconnect = func() time.Duration { return d }
+	t.Cleanup(func() { getDrainOnDisconnect = orig })
+}
+
+func TestDisconnectDrain_ReadsUpstreamToEnd(t *testing.T) {
+	overrideDrainOnDisconnect(t, 5*time.Second)
+
+	upstreamStarted := make(chan struct{})
+	upstreamResult := make(chan error, 1)
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		_, _ = w.Write([]byte("data: chunk1\n\n"))
+		flusher.Flush()
+		close(upstreamStarted)
+		for i := 2; i <= 10; i++ {
+			time.Sleep(20 * time.Millisecond)
+			if _, err := fmt.Fprintf(w, "data: chunk%d\n\n", i); err != nil {
+				upstreamResult <- err
+				return
+			}
+			flusher.Flush()
+		}
+		_, err := w.Write([]byte("data: [DONE]\n\n"))
+		flusher.Flush()
+		upstreamResult <- err
+	}))
+
+	srv := newInMemoryServer(t)
+	if err := runDrainTestForward(t, srv, upstream.URL, upstreamStarted); !isClientDisconnectError(err) {
+		t.Fatalf("expected client disconnect error, got %v", err)
+	}
+
+	select {
+	case err := <-upstreamResult:
+		if err != nil {
+			t.Fatalf("upstream connection should be drained, got write error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("upstream did not finish while draining")
+	}
+	assertDrainGoroutinesExit(t, srv)
+}
+
+func TestDisconnectDrain_BoundedByTimeout(t *testing.T) {
+	overrideDrainOnDisconnect(t, 100*time.Millisecond)
+
+	upstreamStarted := make(chan struct{})
+	upstreamClosed := make(chan struct{})
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		_, _ = w.Write([]byte("data: chunk1\n\n"))
+		flusher.Flush()
+		close(upstreamStarted)
+		for i := 2; i <= 200; i++ {
+			time.Sleep(20 * time.Millisecond)
+			if _, err := fmt.Fprintf(w, "data: chunk%d\n\n", i); err != nil {
+				close(upstreamClosed)
+				return
+			}
+			flusher.Flush()
+		}
+		t.Error("upstream finished sending; drain was not bounded")
+	}))
+
+	srv := newInMemoryServer(t)
+	_ = runDrainTestForward(t, srv, upstream.URL, upstreamStarted)
+
+	select {
+	case <-upstreamClosed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream connection not closed after drain timeout")
+	}
+	assertDrainGoroutinesExit(t, srv)
+}
+
+func TestDisconnectDrain_DisabledForNonStreaming(t *testing.T) {
+	overrideDrainOnDisconnect(t, time.Second)
+
+	srv := newInMemoryServer(t)
+	reqCtx := srv.newRequestContext(context.Background(), "/v1/chat/completions", []byte(`{"stream":false}`))
+	defer reqCtx.cleanup()
+	if d := srv.newDisconnectDrain(context.Background(), reqCtx); d != nil {
+		t.Fatal("non-streaming requests must not drain")
+	}
+	if reqCtx.upstreamContext() != reqCtx.ctx {
+		t.Fatal("upstream context should default to request context")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"log"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +26,35 @@ var cooldownClearChannelFailCount atomic.Uint64
 var cooldownClearKeyFailCount atomic.Uint64
 var cooldownClearModelFailCount atomic.Uint64
 
+// getNoCooldownStatusCodes 延迟解析 CCLOAD_NO_COOLDOWN_STATUS（逗号分隔状态码，默认空）。
+// 命中的上游错误照常切换候选，但不写入 Key/模型/渠道冷却，避免瞬时 500/529 把渠道挤出轮换。
+var getNoCooldownStatusCodes = sync.OnceValue(func() map[int]struct{} {
+	raw := strings.TrimSpace(os.Getenv("CCLOAD_NO_COOLDOWN_STATUS"))
+	if raw == "" {
+		return nil
+	}
+	codes := make(map[int]struct{})
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			log.Printf("[WARN] 忽略无效的 CCLOAD_NO_COOLDOWN_STATUS 项: %q（必须为 100-599 的状态码）", part)
+			continue
+		}
+		codes[code] = struct{}{}
+	}
+	return codes
+})
+
+// isNoCooldownStatus 状态码是否配置为"切换候选但不冷却"
+func isNoCooldownStatus(status int) bool {
+	_, ok := getNoCooldownStatusCodes()[status]
+	return ok
+}
+
 func cooldownWriteContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	// 断开请求取消链，但保留 ctx.Value（例如 trace ID）。
 	// 避免客户端取消/首字节超时导致冷却写入或清理被短路，从而出现“坏 Key/渠道反复被打爆”或“冷却未清除”的假象。
@@ -39,10 +71,15 @@ func (s *Server) applyCooldownDecision(
 
 	in = s.completeCooldownInput(cfg, in)
 
+	// 维护中的渠道冻结冷却：仅决策下一步，不写入任何冷却状态
+	if cfg.Maintenance {
+		return s.cooldownManager.DecideAction(ctx, in)
+	}
+
 	action := s.cooldownManager.HandleError(cooldownCtx, in)
 
 	if action == cooldown.ActionRetryKey || action == cooldown.ActionRetryModel || action == cooldown.ActionRetryChannel {
-		s.invalidateChannelRelatedCache(cfg.ID)
+		s.invalidateKeyRelatedCache(ctx, cfg.ID, in.KeyIndex)
 	}
 
 	return action
@@ -59,6 +96,9 @@ func (s *Server) decideCooldownAction(
 
 func (s *Server) completeCooldownInput(cfg *model.Config, in cooldown.ErrorInput) cooldown.ErrorInput {
 	in.ChannelType = cfg.ChannelType
+	if !in.IsNetworkError && cfg.MatchesRetryError(in.ErrorBody) {
+		in.ForceRetryChannel = true
+	}
 	if strings.TrimSpace(in.Model) != "" && len(in.ChannelModels) == 0 {
 		in.ChannelModels = s.channelModelCooldownKeys(cfg)
 	}
@@ -158,6 +198,9 @@ func (s *Server) logProxyResult(
 		DebugData:      reqCtx.debugData,
 		CostMultiplier: cfg.CostMultiplier,
 		ThinkingEffort: reqCtx.thinkingEffort,
+		RequestID:      reqCtx.requestID,
+		AttemptNumber:  reqCtx.attemptNumber,
+		ChannelAttempt: reqCtx.channelAttempt,
 	}))
 }
 
@@ -229,6 +272,11 @@ func (s *Server) handleNetworkError(
 
 	input := cooldownInputForModel(networkErrorInput(cfg.ID, keyIndex, statusCode), actualModel)
 	input.ModelScoped = util.IsModelScopedNetworkError(err)
+	if isNoCooldownStatus(statusCode) {
+		action := s.decideCooldownAction(ctx, cfg, input)
+		failure.nextAction = action
+		return failure, action
+	}
 	if deferChannelCooldown {
 		action := s.decideCooldownAction(ctx, cfg, input)
 		if action == cooldown.ActionRetryChannel {
@@ -452,7 +500,12 @@ func (s *Server) handleProxySuccess(
 	}
 
 	// 冷却状态已恢复，刷新相关缓存避免下次命中过期数据
-	s.invalidateChannelRelatedCache(cfg.ID)
+	s.invalidateKeyRelatedCache(ctx, cfg.ID, keyIndex)
+
+	// 记录 Key/渠道最后使用时间（仅写内存，后台批量落库）
+	now := time.Now()
+	s.keyLastUsed.Touch(cfg.ID, keyIndex, now)
+	s.channelLastUsed.Touch(cfg.ID, now)
 
 	// 记录成功日志
 	s.logProxyResult(reqCtx, cfg, actualModel, selectedKey, res.Status, duration, res, "")
@@ -545,6 +598,12 @@ func (s *Server) handleProxyErrorResponse(
 	}
 
 	input := cooldownInputForModel(httpErrorInput(cfg.ID, keyIndex, res), actualModel)
+	if isNoCooldownStatus(res.Status) {
+		// 仅决策下一步（换 Key/渠道或返回客户端），不写入任何冷却状态
+		action := s.decideCooldownAction(ctx, cfg, input)
+		failure.nextAction = action
+		return failure, action
+	}
 	if deferChannelCooldown {
 		action := s.decideCooldownAction(ctx, cfg, input)
 		if action == cooldown.ActionRetryChannel {
@@ -91,6 +91,22 @@ func Test_HandleProxyError_Basic(t *testing.T) {
 	}
 }
 
+func TestDecideCooldownAction_RetryErrorSubstrings(t *testing.T) {
+	srv := newInMemoryServer(t)
+
+	cfg := &model.Config{ID: 1, Name: "test", URL: "http://test.example.com", Enabled: true}
+	res := &fwResult{Status: 406, Body: []byte(`{"error":"Upstream Overloaded"}`), Header: make(http.Header)}
+	in := cooldownInputForModel(httpErrorInput(cfg.ID, 0, res), "test-model")
+
+	if action := srv.decideCooldownAction(context.Background(), cfg, in); action != cooldown.ActionReturnClient {
+		t.Fatalf("无子串配置: 期望 ActionReturnClient, 实际=%v", action)
+	}
+	cfg.RetryErrorSubstrings = []string{"overloaded"}
+	if action := srv.decideCooldownAction(context.Background(), cfg, in); action != cooldown.ActionRetryChannel {
+		t.Fatalf("命中子串: 期望 ActionRetryChannel, 实际=%v", action)
+	}
+}
+
 type failingTokenStatsStore struct {
 	storage.Store
 	err error
@@ -3,12 +3,16 @@ package app
 import (
 	"bufio"
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -86,8 +90,18 @@ func (s *Server) buildProxyRequest(
 	rawQuery, requestPath string,
 	baseURL string,
 ) (*http.Request, error) {
-	// 1. 构建完整 URL
-	upstreamURL := buildUpstreamURL(baseURL, requestPath, rawQuery)
+	// 1. 构建完整 URL（Azure OpenAI 改写为部署路径）
+	azure := usesAzureOpenAI(cfg, runtimeUpstreamProtocol(reqCtx, cfg), baseURL)
+	upstreamURL, ok := "", false
+	if azure {
+		upstreamURL, ok = buildAzureUpstreamURL(baseURL, requestPath, rawQuery, reqCtx.transformPlan.RequestModel())
+	}
+	if !ok {
+		if azure {
+			baseURL = stripURLQuery(baseURL) // 渠道 URL 上的 api-version 仅用于部署路径
+		}
+		upstreamURL = buildUpstreamURL(baseURL, requestPath, rawQuery)
+	}
 
 	// 1.5 anyrouter Anthropic thinking 兜底归一
 	body = normalizeAnyrouterAdaptiveThinking(cfg, requestPath, body)
@@ -106,16 +120,19 @@ func (s *Server) buildProxyRequest(
 	}
 
 	// 2. 创建带上下文的请求
-	req, err := buildUpstreamRequest(reqCtx.ctx, method, upstreamURL, body)
+	req, err := buildUpstreamRequest(reqCtx.upstreamContext(), method, upstreamURL, body)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. 复制请求头
-	copyRequestHeaders(req, hdr)
+	copyRequestHeaders(req, hdr, cfg)
 
 	// 4. 注入认证头
 	injectAPIKeyHeaders(req, apiKey, runtimeUpstreamProtocol(reqCtx, cfg))
+	if azure {
+		injectAzureAPIKeyHeader(req.Header, apiKey)
+	}
 
 	// 5. anyrouter渠道：确保anthropic-beta包含context-1m
 	if cfg.GetChannelType() == util.ChannelTypeAnthropic &&
@@ -158,7 +175,7 @@ func runtimeUpstreamProtocol(reqCtx *requestContext, cfg *model.Config) string {
 	if cfg == nil {
 		return ""
 	}
-	return cfg.GetChannelType()
+	return cfg.GetProtocol()
 }
 
 // ============================================================================
@@ -696,6 +713,9 @@ func (s *Server) handleSuccessResponse(
 		if detectedProtocol != "" {
 			channelType = string(detectedProtocol)
 		}
+		if isUpstreamDecompressionError(resp, err) {
+			return upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, err)
+		}
 		if err != nil {
 			return &fwResult{
 				Status:        resp.StatusCode,
@@ -714,6 +734,9 @@ func (s *Server) handleSuccessResponse(
 		if detectedProtocol != "" {
 			channelType = string(detectedProtocol)
 		}
+		if isUpstreamDecompressionError(resp, err) {
+			return upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, err)
+		}
 		if err != nil {
 			return &fwResult{
 				Status:        resp.StatusCode,
@@ -762,8 +785,17 @@ func (s *Server) handleSuccessResponse(
 
 	// 流式传输并解析usage
 	contentType := resp.Header.Get("Content-Type")
+	body := resp.Body
+	if reqCtx.restoreModel && resp.Header.Get("Content-Encoding") == "" {
+		// 透传路径：将上游返回的重定向模型名还原为客户端请求的模型名（协议转换路径已由转换器处理）
+		if strings.Contains(contentType, "text/event-stream") || reqCtx.isStreaming {
+			body = newModelRestoreReader(body, reqCtx.originalModel, true)
+		} else if strings.Contains(contentType, "json") {
+			body = newModelRestoreReader(body, reqCtx.originalModel, false)
+		}
+	}
 	parser, streamErr := streamAndParseResponse(
-		reqCtx.ctx, resp.Body, streamWriter, contentType, channelType, reqCtx.isStreaming,
+		reqCtx.ctx, body, streamWriter, contentType, channelType, reqCtx.isStreaming,
 		func(parser usageParser) error {
 			if deferredWriter == nil || deferredWriter.Committed() {
 				return nil
@@ -774,12 +806,19 @@ func (s *Server) handleSuccessResponse(
 			if parser.GetLastError() != nil {
 				return errAbortStreamBeforeWrite
 			}
-			if parser.HasStreamOutput() {
+			if hasCommittableStreamOutput(parser, readStats) {
 				return deferredWriter.Commit()
 			}
 			return nil
 		},
 	)
+	if isUpstreamDecompressionError(resp, streamErr) {
+		if deferredWriter != nil && !deferredWriter.Committed() {
+			return upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, streamErr)
+		}
+		streamErr = committedDecompressionError(resp, streamErr)
+	}
+
 	abortedBeforeCommit := errors.Is(streamErr, errAbortStreamBeforeWrite)
 	if abortedBeforeCommit {
 		streamErr = nil
@@ -852,6 +891,9 @@ func (s *Server) handleTranslatedNonStreamSuccessResponse(
 	readStats *streamReadStats,
 ) (*fwResult, float64, error) {
 	rawBody, err := io.ReadAll(resp.Body)
+	if isUpstreamDecompressionError(resp, err) {
+		return upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, err)
+	}
 	if err != nil {
 		return &fwResult{
 			Status:        resp.StatusCode,
@@ -954,7 +996,7 @@ func (s *Server) handleTranslatedStreamSuccessResponse(
 			if !deferredWriter.Committed() && parser.GetLastError() != nil {
 				return errAbortStreamBeforeWrite
 			}
-			if !deferredWriter.Committed() && parser.HasStreamOutput() {
+			if !deferredWriter.Committed() && hasCommittableStreamOutput(parser, readStats) {
 				return deferredWriter.Commit()
 			}
 			return nil
@@ -983,6 +1025,13 @@ func (s *Server) handleTranslatedStreamSuccessResponse(
 		},
 	)
 
+	if isUpstreamDecompressionError(resp, streamErr) {
+		if !deferredWriter.Committed() {
+			return upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, streamErr)
+		}
+		streamErr = committedDecompressionError(resp, streamErr)
+	}
+
 	abortedBeforeCommit := errors.Is(streamErr, errAbortStreamBeforeWrite)
 	if abortedBeforeCommit {
 		streamErr = nil
@@ -1080,6 +1129,7 @@ func looksLikeSSE(data []byte) bool {
 func attachFirstByteDetector(
 	reqCtx *requestContext,
 	resp *http.Response,
+	success bool,
 	readStats *streamReadStats,
 	observer *ForwardObserver,
 ) {
@@ -1087,7 +1137,7 @@ func attachFirstByteDetector(
 		ReadCloser: resp.Body,
 		stats:      readStats,
 		onFirstRead: func() {
-			if reqCtx.isStreaming && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			if reqCtx.isStreaming && success {
 				return
 			}
 			if reqCtx.isStreaming {
@@ -1226,10 +1276,77 @@ func emptyOKResponseResult(reqCtx *requestContext, resp *http.Response, hdrClone
 	}, duration, err
 }
 
+// isUpstreamDecompressionError 判断读取响应体的错误是否来自 Transport 透明解压 gzip（响应体损坏或截断）
+// 仅 resp.Uncompressed=true 时判定：未经解压的响应中 io.ErrUnexpectedEOF 属于普通网络截断
+func isUpstreamDecompressionError(resp *http.Response, err error) bool {
+	if err == nil || resp == nil || !resp.Uncompressed {
+		return false
+	}
+	var corrupt flate.CorruptInputError
+	return errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, gzip.ErrChecksum) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &corrupt)
+}
+
+// upstreamDecompressionResult 客户端尚未收到任何字节时的解压失败：丢弃已读数据，按渠道级错误切换下一个候选
+func upstreamDecompressionResult(reqCtx *requestContext, resp *http.Response, hdrClone http.Header, readStats *streamReadStats, cause error) (*fwResult, float64, error) {
+	err := fmt.Errorf("%w (status %d, gzip body): %v", util.ErrUpstreamDecompression, resp.StatusCode, cause)
+	log.Printf("[WARN]  [上游解压失败] host=%s, 已读字节=%d, 尚未写入客户端，切换下一个候选: %v",
+		upstreamHost(resp), readStats.totalBytes, cause)
+	return &fwResult{
+		Status:        resp.StatusCode,
+		Header:        hdrClone,
+		Body:          []byte(err.Error()),
+		FirstByteTime: readStats.firstByteSec,
+		BytesReceived: readStats.totalBytes,
+	}, reqCtx.Duration().Seconds(), err
+}
+
+// committedDecompressionError 响应已开始写入客户端时的解压失败：无法重试，仅单独记录并标记错误类型
+func committedDecompressionError(resp *http.Response, cause error) error {
+	log.Printf("[WARN]  [上游解压失败] host=%s, 响应已写入客户端，无法切换候选: %v", upstreamHost(resp), cause)
+	return fmt.Errorf("%w: %w", util.ErrUpstreamDecompression, cause)
+}
+
+func upstreamHost(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+	return resp.Request.URL.Host
+}
+
+// getMinStreamBytes 延迟解析 CCLOAD_MIN_STREAM_BYTES（0=关闭，默认关闭）。
+// 流式响应体（上游原始字节）不足该值即视为软失败：冷却并切换下一个候选。
+var getMinStreamBytes = sync.OnceValue(func() int64 {
+	raw := os.Getenv("CCLOAD_MIN_STREAM_BYTES")
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_MIN_STREAM_BYTES=%s（必须为非负整数），最小流字节检测已关闭", raw)
+		return 0
+	}
+	return n
+})
+
+// hasCommittableStreamOutput 是否可以提交响应：已有可见输出，且累计字节达到最小阈值。
+// 未达阈值前继续缓冲，流结束时仍不足则由 isEmptyStreamOutput 判为空响应（客户端尚未收到任何字节）。
+func hasCommittableStreamOutput(parser usageParser, readStats *streamReadStats) bool {
+	if !parser.HasStreamOutput() {
+		return false
+	}
+	return readStats == nil || readStats.totalBytes >= getMinStreamBytes()
+}
+
 func isEmptyStreamOutput(parser usageParser, readStats *streamReadStats) bool {
 	if readStats == nil || readStats.totalBytes == 0 {
 		return true
 	}
+	if readStats.totalBytes < getMinStreamBytes() {
+		return true
+	}
 	return parser != nil && !parser.HasStreamOutput()
 }
 
@@ -1237,6 +1354,9 @@ func emptyStreamDetail(readStats *streamReadStats) string {
 	if readStats == nil || readStats.totalBytes == 0 {
 		return "without response body"
 	}
+	if minBytes := getMinStreamBytes(); readStats.totalBytes < minBytes {
+		return fmt.Sprintf("with only %d stream bytes (< CCLOAD_MIN_STREAM_BYTES=%d)", readStats.totalBytes, minBytes)
+	}
 	return "without response content"
 }
 
@@ -1265,6 +1385,10 @@ func probeEmptyOKResponse(reqCtx *requestContext, resp *http.Response, hdrClone
 		res, duration, err := emptyOKResponseResult(reqCtx, resp, hdrClone, readStats, "without response body")
 		return true, res, duration, err
 	}
+	if isUpstreamDecompressionError(resp, readErr) {
+		res, duration, err := upstreamDecompressionResult(reqCtx, resp, hdrClone, readStats, readErr)
+		return true, res, duration, err
+	}
 	return false, nil, 0, nil
 }
 
@@ -1285,9 +1409,11 @@ func (s *Server) handleResponse(
 	hdrClone := resp.Header.Clone()
 	readStats := &streamReadStats{}
 
-	attachFirstByteDetector(reqCtx, resp, readStats, observer)
+	// 成功判定遵循渠道 success_codes（默认 2xx）
+	success := cfg.IsSuccessStatus(resp.StatusCode)
+	attachFirstByteDetector(reqCtx, resp, success, readStats, observer)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+	if !success {
 		return s.handleErrorResponse(reqCtx, resp, hdrClone, readStats)
 	}
 
@@ -1319,6 +1445,7 @@ func (s *Server) forwardOnceAsync(ctx context.Context, cfg *model.Config, apiKey
 	reqCtx.originalBody = plan.OriginalBody
 	reqCtx.translatedBody = plan.TranslatedBody
 	reqCtx.originalModel = plan.ResponseModel()
+	reqCtx.restoreModel = cfg.RestoreResponseModel && plan.RequestModel() != plan.ResponseModel()
 	defer reqCtx.cleanup() // [INFO] 统一清理：定时器 + context（总是安全）
 
 	if s.protocolRegistry != nil && plan.NeedsTransform {
@@ -1341,6 +1468,10 @@ func (s *Server) forwardOnceAsync(ctx context.Context, cfg *model.Config, apiKey
 		reqCtx.translatedBody = translatedBody
 	}
 
+	// 1.5 客户端断开后排空（CCLOAD_DRAIN_ON_DISCONNECT，仅流式）
+	drain := s.newDisconnectDrain(ctx, reqCtx)
+	defer drain.finish()
+
 	// 2. 构建上游请求
 	req, err := s.buildProxyRequest(reqCtx, cfg, apiKey, method, reqCtx.transformPlan.TranslatedBody, hdr, rawQuery, reqCtx.transformPlan.UpstreamPath, baseURL)
 	if err != nil {
@@ -1366,6 +1497,10 @@ func (s *Server) forwardOnceAsync(ctx context.Context, cfg *model.Config, apiKey
 	//   - HTTP/2: 发送 RST_STREAM 帧 → 取消当前 stream（不影响同连接的其他请求）
 	// 效果：避免 AI 流式生成场景下，用户点"停止"后上游仍生成数千 tokens 的浪费
 	if resp != nil {
+		if drain != nil {
+			resp.Body = drain.attach(resp.Body)
+		}
+
 		// Debug捕获：在 resp.Body 被其他层包装前，用 TeeReader 旁路捕获响应体
 		dc.wrapResponseBody(resp)
 
@@ -1441,6 +1576,11 @@ func markIncompleteStreamForwardResult(res *fwResult) {
 	res.Status = util.StatusStreamIncomplete
 }
 
+func markRejectedSuccessStatusResult(res *fwResult) {
+	res.StreamDiagMsg = fmt.Sprintf("upstream status %d not in channel success_codes", res.Status)
+	res.Status = http.StatusBadGateway
+}
+
 func (s *Server) handleCommittedAwareProxyError(
 	ctx context.Context,
 	cfg *model.Config,
@@ -1510,6 +1650,7 @@ func (s *Server) forwardAttempt(
 ) (*proxyResult, cooldown.Action, error) {
 	// 记录渠道尝试开始时间（用于日志记录，每次渠道/Key切换时更新）
 	reqCtx.attemptStartTime = time.Now()
+	reqCtx.attemptNumber++
 	reqCtx.baseURL = baseURL
 
 	// 转发请求（传递实际的API Key字符串和观测回调）
@@ -1524,7 +1665,7 @@ func (s *Server) forwardAttempt(
 		reqCtx.body,
 		bodyToSend,
 		reqCtx.originalModel,
-		actualModel,
+		upstreamRequestModel(cfg, reqCtx.originalModel, actualModel),
 		reqCtx.isStreaming,
 	)
 	if err != nil {
@@ -1561,7 +1702,7 @@ func (s *Server) forwardAttempt(
 		if res != nil && res.DebugData != nil {
 			reqCtx.debugData = res.DebugData
 		}
-		if err == nil && res != nil && res.Status >= 200 && res.Status < 300 {
+		if err == nil && res != nil && cfg.IsSuccessStatus(res.Status) {
 			res.RetryStrategy = strings.Join(retryStrategies, ",")
 			break
 		}
@@ -1596,8 +1737,8 @@ func (s *Server) forwardAttempt(
 		return result, action, nil
 	}
 
-	// 处理成功响应（仅当err==nil且状态码2xx时）
-	if res.Status >= 200 && res.Status < 300 {
+	// 处理成功响应（仅当err==nil且状态码命中渠道 success_codes，默认2xx）
+	if cfg.IsSuccessStatus(res.Status) {
 		if result, action, handled := s.handleSuccessfulForwardAnomaly(
 			ctx, cfg, keyIndex, actualModel, selectedKey, res, duration, reqCtx, deferChannelCooldown,
 		); handled {
@@ -1608,6 +1749,11 @@ func (s *Server) forwardAttempt(
 		return result, action, nil
 	}
 
+	// 被 success_codes 排除的 2xx 按渠道级错误处理，否则分类器会视为无错误直接返回客户端
+	if res.Status >= 200 && res.Status < 300 {
+		markRejectedSuccessStatusResult(res)
+	}
+
 	// 处理错误响应
 	result, action := s.handleProxyErrorResponse(
 		ctx, cfg, keyIndex, actualModel, selectedKey, res, duration, reqCtx, deferChannelCooldown, forceReturnClient,
@@ -2048,10 +2194,15 @@ func buildCtxDoneResult(cfg *model.Config, ctxErr error) *proxyResult {
 	}
 }
 
-// selectKeyWithFallback 在 triedKeys 之外选 Key：先 SelectAvailableKey，
+// selectKeyWithFallback 在 triedKeys 之外选 Key：先 SelectAvailableKeyWithStrategy（strategyOverride 为请求级覆盖，
+// prevKeyIndex 为本请求上一次选中的Key，首次选择为 -1），
 // 启用 cooldown fallback 时再 SelectCooldownFallbackKey；全部失败包装 ErrAllKeysUnavailable。
-func (s *Server) selectKeyWithFallback(cfg *model.Config, apiKeys []*model.APIKey, triedKeys map[int]bool) (int, string, error) {
-	keyIndex, selectedKey, selectErr := s.keySelector.SelectAvailableKey(cfg.ID, apiKeys, triedKeys)
+// Key级RPM限制导致的失败原样返回 ErrKeyRPMExceeded（仅跳过渠道，不走兜底也不触发冷却）。
+func (s *Server) selectKeyWithFallback(cfg *model.Config, apiKeys []*model.APIKey, triedKeys map[int]bool, strategyOverride string, prevKeyIndex int) (int, string, error) {
+	keyIndex, selectedKey, selectErr := s.keySelector.SelectAvailableKeyWithStrategy(cfg.ID, apiKeys, triedKeys, strategyOverride, prevKeyIndex)
+	if selectErr != nil && errors.Is(selectErr, ErrKeyRPMExceeded) {
+		return 0, "", selectErr
+	}
 	if selectErr != nil && cfg.CooldownFallback {
 		keyIndex, selectedKey, selectErr = s.keySelector.SelectCooldownFallbackKey(cfg.ID, apiKeys, triedKeys)
 	}
@@ -2116,6 +2267,7 @@ func (s *Server) attemptKeyAcrossURLs(
 		if attemptErr != nil {
 			return nil, nil, attemptErr
 		}
+		reqCtx.trace.recordAttempt(reqCtx, cfg, keyIndex, result, nextAction)
 
 		if result != nil && result.succeeded {
 			// 成功：记录TTFB到URLSelector（仅多URL场景）
@@ -2161,6 +2313,7 @@ func (s *Server) attemptKeyAcrossURLs(
 
 func (s *Server) tryChannelWithKeys(ctx context.Context, cfg *model.Config, reqCtx *proxyRequestContext, w http.ResponseWriter) (*proxyResult, error) {
 	reqCtx.channelStartTime = time.Now()
+	reqCtx.channelAttempt++
 
 	// Fail-fast：ctx 已结束（客户端断开/请求超时）时不要再做任何 I/O（查库、选Key、发请求）。
 	if ctxErr := ctx.Err(); ctxErr != nil {
@@ -2181,7 +2334,13 @@ func (s *Server) tryChannelWithKeys(ctx context.Context, cfg *model.Config, reqC
 
 	maxKeyRetries := min(s.maxKeyRetries, actualKeyCount)
 
+	// LRU 选择：派发即记录使用时间，使并发请求立即轮转到其他同优先级渠道
+	if s.selectionStrategy == selectionLRU {
+		s.channelLastUsed.MarkSelected(cfg.ID, reqCtx.channelStartTime)
+	}
+
 	triedKeys := make(map[int]bool) // 本次请求内已尝试过的Key
+	prevKeyIndex := -1              // 本次请求上一次选中的Key（轮询重试从其后继续）
 
 	var lastFailure *proxyResult
 
@@ -2192,7 +2351,7 @@ func (s *Server) tryChannelWithKeys(ctx context.Context, cfg *model.Config, reqC
 	// [FIX] 2026-01: 模型名变更时同步替换 URL 路径
 	// 场景：Gemini API 的模型名在 URL 路径中（如 /v1beta/models/gemini-3-flash:streamGenerateContent）
 	// 如果模糊匹配将 gemini-3-flash 改为 gemini-3-flash-preview，URL 路径也需要同步更新
-	requestPath := replaceModelInPath(reqCtx.requestPath, reqCtx.originalModel, actualModel)
+	requestPath := replaceModelInPath(reqCtx.requestPath, reqCtx.originalModel, upstreamRequestModel(cfg, reqCtx.originalModel, actualModel))
 
 	// 获取渠道URL列表（单URL时退化为单元素切片）
 	urls := cfg.GetURLs()
@@ -2217,13 +2376,14 @@ func (s *Server) tryChannelWithKeys(ctx context.Context, cfg *model.Config, reqC
 		}
 
 		// 选择可用的API Key（直接传入apiKeys，避免重复查询）
-		keyIndex, selectedKey, selectErr := s.selectKeyWithFallback(cfg, apiKeys, triedKeys)
+		keyIndex, selectedKey, selectErr := s.selectKeyWithFallback(cfg, apiKeys, triedKeys, reqCtx.keyStrategy, prevKeyIndex)
 		if selectErr != nil {
 			return nil, selectErr
 		}
 
 		// 标记Key为已尝试
 		triedKeys[keyIndex] = true
+		prevKeyIndex = keyIndex
 
 		// 更新活跃请求的渠道信息（用于前端显示）
 		if reqCtx.activeReqID > 0 {
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -580,3 +582,28 @@ func TestHandleErrorResponse_MergesBodyReadErrorIntoResult(t *testing.T) {
 		t.Fatalf("expected StreamDiagMsg to include upstream error, got %q", res.StreamDiagMsg)
 	}
 }
+
+func TestIsUpstreamDecompressionError(t *testing.T) {
+	t.Parallel()
+
+	decoded := &http.Response{Uncompressed: true}
+	cases := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{name: "bad header", resp: decoded, err: gzip.ErrHeader, want: true},
+		{name: "bad checksum", resp: decoded, err: gzip.ErrChecksum, want: true},
+		{name: "corrupt deflate", resp: decoded, err: flate.CorruptInputError(12), want: true},
+		{name: "truncated gzip", resp: decoded, err: io.ErrUnexpectedEOF, want: true},
+		{name: "not decoded", resp: &http.Response{}, err: io.ErrUnexpectedEOF, want: false},
+		{name: "other error", resp: decoded, err: errors.New("connection reset by peer"), want: false},
+		{name: "nil error", resp: decoded, err: nil, want: false},
+	}
+	for _, tc := range cases {
+		if got := isUpstreamDecompressionError(tc.resp, tc.err); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
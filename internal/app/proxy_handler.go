@@ -11,8 +11,10 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"ccLoad/internal/config"
@@ -37,6 +39,9 @@ var ErrAllKeysExhausted = errors.New("all keys exhausted")
 // ErrChannelRPMExceeded 表示渠道RPM限制已达到
 var ErrChannelRPMExceeded = errors.New("channel rpm limit exceeded")
 
+// ErrKeyRPMExceeded 表示渠道剩余可用Key均已达到Key级RPM限制（跳过渠道，不触发冷却）
+var ErrKeyRPMExceeded = errors.New("key rpm limit exceeded")
+
 // ErrChannelConcurrencyExceeded 表示渠道并发限制已达到
 var ErrChannelConcurrencyExceeded = errors.New("channel concurrency limit exceeded")
 
@@ -44,12 +49,43 @@ var ErrChannelConcurrencyExceeded = errors.New("channel concurrency limit exceed
 // 并发控制
 // ============================================================================
 
+// priorityHeader 请求级优先级头（真值时可使用预留的优先槽位），仅用于本服务调度，不透传上游
+const priorityHeader = "X-CCLoad-Priority"
+
 // acquireConcurrencySlot 获取并发槽位，返回release函数和状态
-// ok=false 表示客户端已取消请求
+// 启用优先槽位（CCLOAD_PRIORITY_CONCURRENCY）时为两级信号量：普通请求只用普通槽位，
+// 带 X-CCLoad-Priority 的请求优先占用预留槽位，预留槽位满时再与普通请求共享普通槽位
+// ok=false 表示客户端已取消请求或服务处于排空模式（已写响应）
 func (s *Server) acquireConcurrencySlot(c *gin.Context) (release func(), ok bool) {
+	if s.draining.Load() {
+		rejectDraining(c)
+		return nil, false
+	}
+	// prioritySem 为 nil 时对其发送永远阻塞，select 自然退化为单信号量
+	var prioritySem chan struct{}
+	if s.prioritySem != nil && util.ParseBoolDefault(c.GetHeader(priorityHeader), false) {
+		prioritySem = s.prioritySem
+	}
+	select {
+	case prioritySem <- struct{}{}:
+		return s.onConcurrencySlotAcquired(c, prioritySem)
+	default:
+	}
+	select {
+	case s.concurrencySem <- struct{}{}:
+		return s.onConcurrencySlotAcquired(c, s.concurrencySem)
+	default:
+	}
+
+	// 槽位已满：计入等待数，保持原有的等待或取消语义
+	s.concurrencyWaiting.Add(1)
+	s.concurrencyWaitedTotal.Add(1)
+	defer s.concurrencyWaiting.Add(-1)
 	select {
+	case prioritySem <- struct{}{}:
+		return s.onConcurrencySlotAcquired(c, prioritySem)
 	case s.concurrencySem <- struct{}{}:
-		return func() { <-s.concurrencySem }, true
+		return s.onConcurrencySlotAcquired(c, s.concurrencySem)
 	case <-c.Request.Context().Done():
 		ctxErr := c.Request.Context().Err()
 		if errors.Is(ctxErr, context.DeadlineExceeded) {
@@ -61,6 +97,23 @@ func (s *Server) acquireConcurrencySlot(c *gin.Context) (release func(), ok bool
 	}
 }
 
+// onConcurrencySlotAcquired 已占用 sem 中槽位后的收尾：等待槽位期间可能进入排空，
+// 此时归还槽位，避免排空阶段接收新请求
+func (s *Server) onConcurrencySlotAcquired(c *gin.Context, sem chan struct{}) (release func(), ok bool) {
+	if s.draining.Load() {
+		<-sem
+		rejectDraining(c)
+		return nil, false
+	}
+	return func() { <-sem }, true
+}
+
+// rejectDraining 排空模式下拒绝新请求（503 + Retry-After，客户端可重试到其他实例）
+func rejectDraining(c *gin.Context) {
+	c.Header("Retry-After", "1")
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server is draining"})
+}
+
 // ============================================================================
 // 请求解析
 // ============================================================================
@@ -191,11 +244,145 @@ func (s *Server) selectRouteCandidates(ctx context.Context, c *gin.Context, orig
 	if channelType == "" {
 		return nil, errUnknownChannelType
 	}
+	var (
+		cands []*model.Config
+		err   error
+	)
 	if requestFamily == protocol.RequestFamilyAlphaSearch {
-		return s.selectAlphaSearchCandidates(ctx, originalModel)
+		cands, err = s.selectAlphaSearchCandidates(ctx, originalModel)
+	} else {
+		cands, err = s.selectCandidatesByModelAndType(ctx, originalModel, channelType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 模型→渠道类型规则：即使模型误配到其他类型的渠道，也不会跨供应商路由
+	return s.filterModelChannelTypes(cands, originalModel), nil
+}
+
+// getDefaultModel 延迟解析 CCLOAD_DEFAULT_MODEL（无渠道支持请求模型时回退使用的模型，默认空=禁用）
+var getDefaultModel = sync.OnceValue(func() string {
+	defaultModel := strings.TrimSpace(os.Getenv("CCLOAD_DEFAULT_MODEL"))
+	if defaultModel != "" {
+		log.Printf("[CONFIG] 已启用默认模型回退：无渠道支持请求模型时改用 %s", defaultModel)
+	}
+	return defaultModel
+})
+
+// fallbackToDefaultModel 无渠道支持请求模型时，改用 CCLOAD_DEFAULT_MODEL 重新选择渠道
+// 仅处理 JSON 请求体携带 model 的请求；令牌不允许默认模型或默认模型也无可用渠道时返回 false（按无可用渠道处理）
+func (s *Server) fallbackToDefaultModel(ctx context.Context, c *gin.Context, tokenHash, originalModel, channelType string, body []byte) ([]*model.Config, []byte, bool) {
+	defaultModel := getDefaultModel()
+	if defaultModel == "" || strings.EqualFold(defaultModel, originalModel) {
+		return nil, nil, false
+	}
+	if tokenHash != "" && !s.authService.IsModelAllowed(tokenHash, defaultModel) {
+		return nil, nil, false
+	}
+	// 模型来自 URL 路径（如 Gemini）时不回退，避免请求体与路径模型不一致
+	var reqModel struct {
+		Model string `json:"model"`
+	}
+	if err := sonic.Unmarshal(body, &reqModel); err != nil || reqModel.Model == "" {
+		return nil, nil, false
+	}
+	newBody, ok := replaceModelInBody(body, defaultModel)
+	if !ok {
+		return nil, nil, false
+	}
+	cands, err := s.selectRouteCandidates(ctx, c, defaultModel, channelType)
+	if err != nil || len(cands) == 0 {
+		return nil, nil, false
+	}
+	log.Printf("[INFO] 无渠道支持模型 %s，已回退到默认模型 %s（%d个候选渠道）", originalModel, defaultModel, len(cands))
+	return cands, newBody, true
+}
+
+// unknownModelListLimit 404 响应中列出的可用模型数量上限
+const unknownModelListLimit = 20
+
+// unknownModelMessage 判断模型是否从未配置在任何渠道上（含已禁用渠道，忽略冷却与成本限额）。
+// 未配置时返回包含可用模型列表的错误消息；查询失败时按已配置处理（保持 503）。
+func (s *Server) unknownModelMessage(c *gin.Context, modelName, channelType string) (string, bool) {
+	configs, err := s.store.ListConfigs(c.Request.Context())
+	if err != nil {
+		return "", false
+	}
+	normalizedType := normalizeOptionalChannelType(channelType)
+	enabled := make([]*model.Config, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg == nil || (normalizedType != "" && !cfg.SupportsProtocol(normalizedType)) {
+			continue
+		}
+		if s.configSupportsModelWithFuzzyMatch(cfg, modelName) {
+			return "", false
+		}
+		if cfg.Enabled {
+			enabled = append(enabled, cfg)
+		}
+	}
+
+	models := s.filterVisibleModelsForRequest(c, channelType, modelNamesFromChannels(enabled))
+	sort.Strings(models)
+	available := "none"
+	if len(models) > 0 {
+		if len(models) > unknownModelListLimit {
+			models = append(models[:unknownModelListLimit:unknownModelListLimit], fmt.Sprintf("... (%d more)", len(models)-unknownModelListLimit))
+		}
+		available = strings.Join(models, ", ")
+	}
+	return fmt.Sprintf("model %q is not configured on any channel; available models: %s", modelName, available), true
+}
+
+// requestIDHeader 请求ID头（客户端传入则透传，否则由服务端生成）
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength 客户端请求ID最大长度（与 logs.request_id 列宽一致）
+const maxRequestIDLength = 64
+
+// ensureRequestID 确定本次请求的ID：客户端 X-Request-Id 合法（可打印 ASCII、≤64 字节）时沿用，否则生成 UUID。
+// 结果写回请求头（随 copyRequestHeaders 透传上游）并设置到响应头。
+func ensureRequestID(c *gin.Context) string {
+	requestID := strings.TrimSpace(c.Request.Header.Get(requestIDHeader))
+	if !isValidRequestID(requestID) {
+		requestID = util.NewUUIDv4()
+	}
+	c.Request.Header.Set(requestIDHeader, requestID)
+	c.Header(requestIDHeader, requestID)
+	return requestID
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
 	}
+	return true
+}
 
-	return s.selectCandidatesByModelAndType(ctx, originalModel, channelType)
+// filterChannelsByMethod 过滤掉不允许当前 HTTP 方法的渠道（allowed_methods 为空的渠道不受限）
+// 全部允许时直接返回原切片，避免热路径分配
+func filterChannelsByMethod(cands []*model.Config, method string) []*model.Config {
+	var filtered []*model.Config
+	for i, cfg := range cands {
+		if cfg.AllowsMethod(method) {
+			if filtered != nil {
+				filtered = append(filtered, cfg)
+			}
+			continue
+		}
+		if filtered == nil {
+			filtered = append(make([]*model.Config, 0, len(cands)), cands[:i]...)
+		}
+	}
+	if filtered == nil {
+		return cands
+	}
+	return filtered
 }
 
 // ============================================================================
@@ -238,6 +425,9 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		return
 	}
 
+	// 请求ID：沿用客户端 X-Request-Id（非法则重新生成），透传上游并回显给客户端
+	requestID := ensureRequestID(c)
+
 	requestMethod := c.Request.Method
 
 	incoming, err := parseIncomingRequest(c)
@@ -258,6 +448,12 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if s.validateRequests {
+		if err := validateRequestBodySchema(effectiveRequestPath, all); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 	if protocol.DetectRequestFamily(effectiveRequestPath) == protocol.RequestFamilyAlphaSearch {
 		all = sanitizeCodexAlphaSearchBody(all)
 	}
@@ -278,6 +474,32 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 	if !s.enforceTokenLimits(c, tokenHashStr, incoming.authorizationModel()) {
 		return
 	}
+	if !s.enforceModelBudget(c, originalModel) {
+		return
+	}
+
+	// Idempotency-Key 去重：同一令牌的重试请求直接重放首次成功响应，避免重复调用上游与重复计费
+	var idemKey string
+	if s.idempotencyCache != nil {
+		idemKey = idempotencyCacheKey(c, tokenHashStr, effectiveRequestPath, all)
+	}
+	if idemKey != "" {
+		if entry, ok := s.idempotencyCache.Get(idemKey); ok {
+			s.serveIdempotentReplay(c, entry, originalModel, tokenIDInt64, effectiveRequestPath, requestID, startTime)
+			return
+		}
+		// 同键请求仍在处理中：直接返回 409，由客户端稍后重试（届时命中缓存重放）
+		if !s.idempotencyInflight.acquire(idemKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "a request with the same Idempotency-Key is still in progress"})
+			return
+		}
+		defer s.idempotencyInflight.release(idemKey)
+		// 占用前首个请求可能恰好完成，再查一次缓存
+		if entry, ok := s.idempotencyCache.Get(idemKey); ok {
+			s.serveIdempotentReplay(c, entry, originalModel, tokenIDInt64, effectiveRequestPath, requestID, startTime)
+			return
+		}
+	}
 
 	// 注册活跃请求（内存状态，用于前端实时显示）
 	activeID := s.activeRequests.Register(startTime, originalModel, c.ClientIP(), isStreaming)
@@ -302,19 +524,76 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		return
 	}
 
+	if len(cands) == 0 && incoming.hasModel {
+		if fbCands, fbBody, ok := s.fallbackToDefaultModel(ctx, c, tokenHashStr, originalModel, string(clientProtocol), all); ok {
+			defaultModel := getDefaultModel()
+			if !s.enforceModelBudget(c, defaultModel) {
+				return
+			}
+			c.Header("X-Model-Fallback", defaultModel)
+			cands, all, originalModel = fbCands, fbBody, defaultModel
+		}
+	}
+
+	// 请求级渠道固定：只使用指定渠道，不做故障切换
+	if pin := strings.TrimSpace(c.GetHeader(channelPinHeader)); pin != "" {
+		if pinned, ok := pickPinnedChannel(cands, pin); ok {
+			cands = []*model.Config{pinned}
+		} else if s.channelPinFallbackEnabled() {
+			log.Printf("[INFO] 固定渠道 %q 不可用（模型=%s），回退正常选路", pin, originalModel)
+		} else {
+			msg := fmt.Sprintf("pinned channel %q is unavailable (not found, disabled, not serving model %q, or cooling down)", pin, originalModel)
+			s.AddLogAsync(&model.LogEntry{
+				Time:           model.JSONTime{Time: time.Now()},
+				Model:          originalModel,
+				LogSource:      model.LogSourceProxy,
+				AuthTokenID:    tokenIDInt64,
+				StatusCode:     503,
+				Message:        msg,
+				IsStreaming:    isStreaming,
+				ClientIP:       c.ClientIP(),
+				ThinkingEffort: thinkingEffort,
+				RequestID:      requestID,
+			})
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": msg})
+			return
+		}
+	}
+
 	if len(cands) == 0 {
+		// 模型从未配置在任何启用渠道上 → 404（配置错误）；有渠道但全部不可用 → 503（临时故障）
+		if incoming.hasModel {
+			if msg, unknown := s.unknownModelMessage(c, originalModel, string(clientProtocol)); unknown {
+				s.AddLogAsync(&model.LogEntry{
+					Time:           model.JSONTime{Time: time.Now()},
+					Model:          originalModel,
+					LogSource:      model.LogSourceProxy,
+					AuthTokenID:    tokenIDInt64,
+					StatusCode:     http.StatusNotFound,
+					Message:        msg,
+					IsStreaming:    isStreaming,
+					ClientIP:       c.ClientIP(),
+					ThinkingEffort: thinkingEffort,
+					RequestID:      requestID,
+				})
+				c.JSON(http.StatusNotFound, gin.H{"error": msg})
+				return
+			}
+		}
+		exhaustedStatus := s.exhaustedClientStatus()
 		s.AddLogAsync(&model.LogEntry{
 			Time:           model.JSONTime{Time: time.Now()},
 			Model:          originalModel,
 			LogSource:      model.LogSourceProxy,
 			AuthTokenID:    tokenIDInt64,
-			StatusCode:     503,
+			StatusCode:     exhaustedStatus,
 			Message:        "no available upstream (all cooled or none)",
 			IsStreaming:    isStreaming,
 			ClientIP:       c.ClientIP(),
 			ThinkingEffort: thinkingEffort,
+			RequestID:      requestID,
 		})
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "no available upstream (all cooled or none)"})
+		c.JSON(exhaustedStatus, gin.H{"error": "no available upstream (all cooled or none)"})
 		return
 	}
 
@@ -331,6 +610,27 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		}
 	}
 
+	// 渠道级 HTTP 方法白名单：全部候选都不允许该方法时返回 405
+	if cands = filterChannelsByMethod(cands, requestMethod); len(cands) == 0 {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{
+			"error": fmt.Sprintf("method %s is not allowed for matched upstream channels", requestMethod),
+		})
+		return
+	}
+
+	// 渠道级输入 token 上限：估算超限的渠道直接跳过，避免转发后收到上游上下文超长错误
+	var estimatedInputTokens int
+	if cands, estimatedInputTokens = filterChannelsByInputTokens(cands, all); len(cands) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"message": fmt.Sprintf("estimated input tokens (%d) exceed max_input_tokens of all matched upstream channels", estimatedInputTokens),
+				"type":    "invalid_request_error",
+				"code":    "input_tokens_exceeded",
+			},
+		})
+		return
+	}
+
 	reqCtx := &proxyRequestContext{
 		originalModel:  originalModel,
 		clientProtocol: clientProtocol,
@@ -347,6 +647,8 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		activeReqID:    activeID,
 		startTime:      startTime,
 		thinkingEffort: thinkingEffort,
+		requestID:      requestID,
+		keyStrategy:    parseKeyStrategyOverride(c.GetHeader(keyStrategyHeader)),
 	}
 	reqCtx.observer = &ForwardObserver{
 		OnBytesRead: func(n int64) {
@@ -360,14 +662,58 @@ func (s *Server) HandleProxyRequest(c *gin.Context) {
 		},
 	}
 
+	// 管理员调试：成功时链路随 Trailer 返回，失败时在最终响应前写入响应头
+	if s.debugTraceRequested(c) {
+		reqCtx.trace = &attemptTrace{}
+		defer reqCtx.trace.flush(c.Writer)
+	}
+
+	var idemWriter *idempotencyCaptureWriter
+	if idemKey != "" {
+		idemWriter = newIdempotencyCaptureWriter(c.Writer)
+		c.Writer = idemWriter
+	}
+
 	lastResult, succeeded := s.runProxyAttemptLoop(ctx, cands, reqCtx, c.Writer)
 	if succeeded {
+		s.storeIdempotentResponse(idemKey, idemWriter)
+		return
+	}
+	fallbackResult, recovered := s.tryNonStreamFallback(ctx, reqCtx, lastResult, c.Writer)
+	if recovered {
+		s.storeIdempotentResponse(idemKey, idemWriter)
 		return
 	}
+	if fallbackResult != nil {
+		lastResult = fallbackResult
+	}
 
+	reqCtx.trace.flush(c.Writer)
 	s.writeFinalProxyResponse(c, reqCtx, originalModel, isStreaming, lastResult, len(cands))
 }
 
+// parseExhaustedStatus 解析 CCLOAD_EXHAUSTED_STATUS（仅接受 4xx/5xx，空值或非法值回退 503）
+func parseExhaustedStatus(raw string) int {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return http.StatusServiceUnavailable
+	}
+	status, err := strconv.Atoi(raw)
+	if err != nil || status < 400 || status > 599 {
+		log.Printf("[WARN] 无效的 CCLOAD_EXHAUSTED_STATUS=%s（必须为 400-599），使用默认值 503", raw)
+		return http.StatusServiceUnavailable
+	}
+	return status
+}
+
+// exhaustedClientStatus 所有候选渠道都没有产生上游响应时返回给客户端的状态码
+func (s *Server) exhaustedClientStatus() int {
+	if s.exhaustedStatus == 0 {
+		return http.StatusServiceUnavailable
+	}
+	return s.exhaustedStatus
+}
+
 func determineFinalClientStatus(lastResult *proxyResult) int {
 	if lastResult == nil || lastResult.status == 0 {
 		return http.StatusServiceUnavailable
@@ -437,6 +783,37 @@ func (s *Server) enforceTokenLimits(c *gin.Context, tokenHash, originalModel str
 	return true
 }
 
+// enforceModelBudget 检查模型月度预算（model_budget_enforce 开启时）。
+// 与令牌费用限额相同：请求开始时预检，完成后记账，并发请求可能少量超额。
+// 超预算时已写 402 响应并返回 false。
+func (s *Server) enforceModelBudget(c *gin.Context, originalModel string) bool {
+	if s.modelBudget == nil || originalModel == "" {
+		return true
+	}
+	spent, budget, exceeded := s.modelBudget.Exceeded(originalModel)
+	if !exceeded {
+		return true
+	}
+	c.JSON(http.StatusPaymentRequired, gin.H{
+		"error": gin.H{
+			"message": fmt.Sprintf("Monthly budget exceeded for model '%s': $%.2f used of $%.2f budget", originalModel, spent, budget),
+			"type":    "insufficient_quota",
+			"code":    "model_budget_exceeded",
+		},
+	})
+	return false
+}
+
+// writeHiddenUpstreamError 以通用错误替代上游错误体（CCLOAD_HIDE_UPSTREAM_ERRORS）。
+// 仅保留状态码与 Retry-After，上游原始错误已记录在渠道级日志中。
+func writeHiddenUpstreamError(c *gin.Context, status int, hdr http.Header) {
+	disableResponseWriteTimeout(c.Writer, "最终响应")
+	if retryAfter := hdr.Get("Retry-After"); retryAfter != "" {
+		c.Header("Retry-After", retryAfter)
+	}
+	c.JSON(status, gin.H{"error": fmt.Sprintf("upstream request failed (status %d)", status)})
+}
+
 // runProxyAttemptLoop 按优先级遍历候选渠道。
 // 返回最后一次结果（可能 nil），调用方据此决定是否兜底响应。
 // succeeded 时内部已写响应，调用方应停止后续 writeFinal 步骤。
@@ -447,7 +824,24 @@ func (s *Server) runProxyAttemptLoop(
 	w gin.ResponseWriter,
 ) (lastResult *proxyResult, succeeded bool) {
 	for _, cfg := range cands {
-		result, err := s.tryChannelWithKeys(ctx, cfg, reqCtx, w)
+		// 响应缓存：cacheable 渠道的非流式 POST 请求先查缓存，未命中则边转发边捕获
+		var attemptWriter http.ResponseWriter = w
+		var capture *responseCaptureWriter
+		var cacheKey string
+		if s.responseCache.eligible(cfg, reqCtx) {
+			cacheKey = responseCacheKey(cfg.ID, reqCtx)
+			if entry, ok := s.responseCache.Get(cacheKey); ok {
+				s.serveCachedResponse(w, cfg, reqCtx, entry)
+				return nil, true
+			}
+			capture = &responseCaptureWriter{ResponseWriter: w}
+			attemptWriter = capture
+		}
+
+		result, err := s.tryChannelWithKeys(ctx, cfg, reqCtx, attemptWriter)
+		if err != nil {
+			reqCtx.trace.recordSkip(cfg, err)
+		}
 
 		// 所有Key冷却：触发渠道级冷却(503)，防止后续请求重复尝试
 		// 使用 cooldownManager.HandleError 统一处理（DRY原则）
@@ -468,6 +862,11 @@ func (s *Server) runProxyAttemptLoop(
 			continue
 		}
 
+		if err != nil && errors.Is(err, ErrKeyRPMExceeded) {
+			log.Printf("[INFO] 渠道 %s (ID=%d) 可用Key均已达到RPM限制，跳过该渠道", cfg.Name, cfg.ID)
+			continue
+		}
+
 		if err != nil && errors.Is(err, ErrChannelConcurrencyExceeded) {
 			log.Printf("[INFO] 渠道 %s (ID=%d) 已达到并发限制，跳过该渠道", cfg.Name, cfg.ID)
 			continue
@@ -475,10 +874,16 @@ func (s *Server) runProxyAttemptLoop(
 
 		if result != nil {
 			if result.succeeded {
+				if capture != nil && capture.cacheable() {
+					s.responseCache.Set(cacheKey, capture.status, capture.Header().Get("Content-Type"), capture.buf.Bytes())
+				}
 				return nil, true
 			}
 
 			lastResult = result
+			if result.status == util.StatusFirstByteTimeout && reqCtx.firstByteTimeoutCfg == nil {
+				reqCtx.firstByteTimeoutCfg = cfg
+			}
 
 			// 客户端已取消：别再浪费资源“重试”了。
 			if result.isClientCanceled {
@@ -506,6 +911,10 @@ func (s *Server) writeFinalProxyResponse(
 ) {
 	// 所有渠道都失败：返回“最后一次实际失败”的状态码（并映射内部状态码），避免一律伪装成503。
 	finalStatus := determineFinalClientStatus(lastResult)
+	exhausted := lastResult == nil || lastResult.status == 0
+	if exhausted {
+		finalStatus = s.exhaustedClientStatus()
+	}
 
 	msg := "exhausted backends"
 	if lastResult != nil && lastResult.isClientCanceled {
@@ -513,7 +922,7 @@ func (s *Server) writeFinalProxyResponse(
 	} else if lastResult != nil && lastResult.status == 499 && finalStatus != 499 {
 		// 上游返回 499 没有任何“客户端取消”的语义价值：对外统一视为网关错误。
 		msg = "upstream returned 499 (mapped)"
-	} else if finalStatus != http.StatusServiceUnavailable {
+	} else if !exhausted && finalStatus != http.StatusServiceUnavailable {
 		msg = fmt.Sprintf("upstream status %d", finalStatus)
 	}
 
@@ -533,10 +942,15 @@ func (s *Server) writeFinalProxyResponse(
 			Duration:    time.Since(reqCtx.startTime).Seconds(),
 			IsStreaming: isStreaming,
 			ClientIP:    reqCtx.clientIP,
+			RequestID:   reqCtx.requestID,
 		})
 	}
 
 	if lastResult != nil && lastResult.status != 0 {
+		if s.hideUpstreamErrors && finalStatus >= http.StatusBadRequest {
+			writeHiddenUpstreamError(c, finalStatus, lastResult.header)
+			return
+		}
 		// 透明代理原则：透传所有上游响应（状态码+header+body）
 		writeResponseWithHeaders(c.Writer, finalStatus, lastResult.header, lastResult.body)
 		return
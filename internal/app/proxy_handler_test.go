@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -262,6 +263,43 @@ func TestAcquireConcurrencySlot_DeadlineExceeded_Returns504(t *testing.T) {
 	}
 }
 
+func TestAcquireConcurrencySlot_TracksWaitingAndStatus(t *testing.T) {
+	srv := &Server{
+		concurrencySem: make(chan struct{}, 1),
+	}
+	srv.concurrencySem <- struct{}{} // 填满槽位，迫使走等待分支
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, _ := newTestContext(t, newRequest(http.MethodPost, "/test", nil).WithContext(ctx))
+
+	done := make(chan bool, 1)
+	go func() {
+		_, acquired := srv.acquireConcurrencySlot(c)
+		done <- acquired
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.concurrencyWaiting.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("waiting counter did not reach 1")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	status := srv.concurrencyStatus()
+	if status.Capacity != 1 || status.InFlight != 1 || status.Waiting != 1 || status.Utilization != 1 {
+		t.Fatalf("unexpected status while saturated: %+v", status)
+	}
+
+	cancel()
+	if <-done {
+		t.Fatal("预期取消后获取失败")
+	}
+	status = srv.concurrencyStatus()
+	if status.Waiting != 0 || status.WaitedTotal != 1 {
+		t.Fatalf("unexpected status after cancel: %+v", status)
+	}
+}
+
 func TestDetermineFinalClientStatus(t *testing.T) {
 	t.Parallel()
 
@@ -298,6 +336,56 @@ func TestDetermineFinalClientStatus(t *testing.T) {
 	}
 }
 
+func TestParseExhaustedStatus(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]int{
+		"":      http.StatusServiceUnavailable,
+		" 529 ": 529,
+		"429":   http.StatusTooManyRequests,
+		"200":   http.StatusServiceUnavailable,
+		"600":   http.StatusServiceUnavailable,
+		"abc":   http.StatusServiceUnavailable,
+	}
+	for raw, want := range cases {
+		if got := parseExhaustedStatus(raw); got != want {
+			t.Errorf("parseExhaustedStatus(%q) = %d, want %d", raw, got, want)
+		}
+	}
+}
+
+func TestWriteFinalProxyResponse_ExhaustedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{exhaustedStatus: 529}
+	reqCtx := &proxyRequestContext{startTime: time.Now()}
+
+	// 没有任何上游响应：使用配置的状态码
+	c, w := newTestContext(t, newRequest(http.MethodPost, "/v1/messages", nil))
+	server.writeFinalProxyResponse(c, reqCtx, "m", false, nil, 1)
+	if w.Code != 529 {
+		t.Fatalf("expected configured exhausted status 529, got %d", w.Code)
+	}
+
+	// 上游真实返回的 503 仍然透传，便于与"无容量"区分
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/v1/messages", nil))
+	server.writeFinalProxyResponse(c, reqCtx, "m", false, &proxyResult{
+		status: http.StatusServiceUnavailable,
+		header: http.Header{},
+		body:   []byte(`{"error":"overloaded"}`),
+	}, 1)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected upstream 503 passthrough, got %d", w.Code)
+	}
+
+	// 未配置时保持 503
+	c, w = newTestContext(t, newRequest(http.MethodPost, "/v1/messages", nil))
+	(&Server{}).writeFinalProxyResponse(c, reqCtx, "m", false, nil, 1)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected default 503, got %d", w.Code)
+	}
+}
+
 func TestShouldStopTryingChannels(t *testing.T) {
 	t.Parallel()
 
@@ -496,3 +584,78 @@ func TestParseIncomingRequest_ImagesLargerBodyAllowed(t *testing.T) {
 		t.Fatalf("模型名应为 gpt-image-1, 实际: %s", incoming.originalModel)
 	}
 }
+
+func TestIsValidRequestID(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []string{"abc-123", "0f8fad5b-d9cb-469f-a165-70867728950e", strings.Repeat("a", maxRequestIDLength)} {
+		if !isValidRequestID(id) {
+			t.Errorf("expected %q to be valid", id)
+		}
+	}
+	for _, id := range []string{"", "has space", "bad\nline", strings.Repeat("a", maxRequestIDLength+1), "中文"} {
+		if isValidRequestID(id) {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
+}
+
+func TestAcquireConcurrencySlot_PriorityReservedSlots(t *testing.T) {
+	srv := &Server{
+		concurrencySem: make(chan struct{}, 1),
+		prioritySem:    make(chan struct{}, 1),
+	}
+	srv.concurrencySem <- struct{}{} // 普通槽位已满
+
+	// 普通请求不能使用预留槽位：进入等待，取消后返回 499
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c, w := newTestContext(t, newRequest(http.MethodPost, "/test", nil).WithContext(ctx))
+	if _, acquired := srv.acquireConcurrencySlot(c); acquired {
+		t.Fatal("普通请求不应占用优先槽位")
+	}
+	if w.Code != StatusClientClosedRequest {
+		t.Fatalf("预期状态码%d，实际%d", StatusClientClosedRequest, w.Code)
+	}
+
+	// 优先请求使用预留槽位
+	req := newRequest(http.MethodPost, "/test", nil)
+	req.Header.Set(priorityHeader, "true")
+	c, _ = newTestContext(t, req)
+	release, acquired := srv.acquireConcurrencySlot(c)
+	if !acquired {
+		t.Fatal("优先请求应获取预留槽位")
+	}
+	if status := srv.concurrencyStatus(); status.Capacity != 2 || status.InFlight != 2 || status.PriorityInFlight != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if srv.InFlightProxyRequests() != 2 {
+		t.Fatalf("预期在途请求2，实际%d", srv.InFlightProxyRequests())
+	}
+
+	// 释放归还到预留槽位，不影响普通槽位
+	release()
+	if len(srv.prioritySem) != 0 || len(srv.concurrencySem) != 1 {
+		t.Fatalf("释放后槽位异常: priority=%d normal=%d", len(srv.prioritySem), len(srv.concurrencySem))
+	}
+}
+
+func TestAcquireConcurrencySlot_PriorityFallsBackToSharedSlots(t *testing.T) {
+	srv := &Server{
+		concurrencySem: make(chan struct{}, 1),
+		prioritySem:    make(chan struct{}, 1),
+	}
+	srv.prioritySem <- struct{}{} // 预留槽位已满
+
+	req := newRequest(http.MethodPost, "/test", nil)
+	req.Header.Set(priorityHeader, "1")
+	c, _ := newTestContext(t, req)
+	release, acquired := srv.acquireConcurrencySlot(c)
+	if !acquired {
+		t.Fatal("预留槽位满时优先请求应回退到普通槽位")
+	}
+	release()
+	if len(srv.concurrencySem) != 0 || len(srv.prioritySem) != 1 {
+		t.Fatalf("释放应归还普通槽位: priority=%d normal=%d", len(srv.prioritySem), len(srv.concurrencySem))
+	}
+}
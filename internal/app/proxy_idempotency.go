@@ -0,0 +1,143 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyKeyHeader 客户端重试时携带相同值，命中后直接重放首次成功响应，不再调用上游
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader 重放缓存响应时回写给客户端的标记头
+	idempotentReplayHeader = "X-CCLoad-Idempotent-Replay"
+
+	// maxIdempotencyKeyLen 超长的 Idempotency-Key 视为无效，不参与去重
+	maxIdempotencyKeyLen = 255
+)
+
+// newIdempotencyCacheFromEnv 解析 CCLOAD_IDEMPOTENCY_TTL（秒，默认0=关闭），容量沿用 CCLOAD_RESPONSE_CACHE_MAX_MB 的默认值
+// 复用 responseCache 的内存 LRU + TTL + 总字节上限实现
+func newIdempotencyCacheFromEnv() *responseCache {
+	var ttl time.Duration
+	if raw := os.Getenv("CCLOAD_IDEMPOTENCY_TTL"); raw != "" {
+		if sec, err := strconv.Atoi(raw); err == nil && sec >= 0 {
+			ttl = time.Duration(sec) * time.Second
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_IDEMPOTENCY_TTL=%s（必须为非负整数秒），请求去重保持关闭", raw)
+		}
+	}
+	return newResponseCache(ttl, defaultResponseCacheMaxBytes)
+}
+
+// idempotencyInflight 记录正在处理中的去重键；首个请求完成前，同键的并发请求不再调用上游
+type idempotencyInflight struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// acquire 标记 key 为处理中；已被其他请求占用时返回 false
+func (f *idempotencyInflight) acquire(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, busy := f.keys[key]; busy {
+		return false
+	}
+	if f.keys == nil {
+		f.keys = make(map[string]struct{})
+	}
+	f.keys[key] = struct{}{}
+	return true
+}
+
+// release 请求结束（成功响应已写入去重缓存）后释放 key
+func (f *idempotencyInflight) release(key string) {
+	f.mu.Lock()
+	delete(f.keys, key)
+	f.mu.Unlock()
+}
+
+// idempotencyCacheKey 去重键：令牌 + 方法 + 路径/查询 + Idempotency-Key + 请求体的 SHA-256
+// 不同令牌、不同请求体即使使用相同 Idempotency-Key 也互不命中；未携带或超长时返回空串
+func idempotencyCacheKey(c *gin.Context, tokenHash, requestPath string, body []byte) string {
+	key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return ""
+	}
+	h := sha256.New()
+	for _, part := range []string{tokenHash, c.Request.Method, requestPath, c.Request.URL.RawQuery, key} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyCaptureWriter 透传写入客户端的同时捕获最终响应（包括响应缓存命中与非流式兜底）
+type idempotencyCaptureWriter struct {
+	gin.ResponseWriter
+	capture responseCaptureWriter
+}
+
+func newIdempotencyCaptureWriter(w gin.ResponseWriter) *idempotencyCaptureWriter {
+	return &idempotencyCaptureWriter{
+		ResponseWriter: w,
+		capture:        responseCaptureWriter{ResponseWriter: w},
+	}
+}
+
+func (w *idempotencyCaptureWriter) WriteHeader(code int) {
+	w.capture.WriteHeader(code)
+}
+
+func (w *idempotencyCaptureWriter) Write(p []byte) (int, error) {
+	return w.capture.Write(p)
+}
+
+func (w *idempotencyCaptureWriter) WriteString(s string) (int, error) {
+	return w.capture.Write([]byte(s))
+}
+
+// Unwrap 暴露底层 writer，供 http.ResponseController（SetWriteDeadline）使用
+func (w *idempotencyCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// storeIdempotentResponse 成功响应写入去重缓存（非 2xx、超过单条上限或已压缩的响应不缓存）
+func (s *Server) storeIdempotentResponse(key string, w *idempotencyCaptureWriter) {
+	if key == "" || w == nil || !w.capture.cacheable() {
+		return
+	}
+	s.idempotencyCache.Set(key, w.capture.status, w.Header().Get("Content-Type"), w.capture.buf.Bytes())
+}
+
+// serveIdempotentReplay 命中去重缓存时直接重放响应，并记录一条区别于上游成功的日志
+func (s *Server) serveIdempotentReplay(c *gin.Context, entry *responseCacheEntry, originalModel string, tokenID int64, requestPath, requestID string, startTime time.Time) {
+	if entry.contentType != "" {
+		c.Header("Content-Type", entry.contentType)
+	}
+	c.Header(idempotentReplayHeader, "true")
+	c.Status(entry.status)
+	_, _ = c.Writer.Write(entry.body)
+
+	logEntry := buildLogEntry(logEntryParams{
+		RequestModel: originalModel,
+		RequestPath:  requestPath,
+		StatusCode:   entry.status,
+		Duration:     time.Since(startTime).Seconds(),
+		AuthTokenID:  tokenID,
+		ClientIP:     c.ClientIP(),
+		StartTime:    startTime,
+		RequestID:    requestID,
+	})
+	logEntry.Message = "idempotent replay"
+	s.AddLogAsync(logEntry)
+}
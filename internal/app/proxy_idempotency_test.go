@@ -0,0 +1,168 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProxy_IdempotencyKeyReplaysSuccessfulResponse(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"call-` + strings.Repeat("x", int(n)) + `","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "idem", models: "gpt-4", apiKey: "sk-idem"},
+	}, map[int]string{0: upstream.URL})
+	env.server.idempotencyCache = newResponseCache(time.Minute, defaultResponseCacheMaxBytes)
+
+	body := map[string]any{"model": "gpt-4", "messages": []map[string]string{{"role": "user", "content": "hi"}}}
+	headers := map[string]string{idempotencyKeyHeader: "retry-123"}
+
+	first := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers)
+	if first.Code != http.StatusOK || first.Header().Get(idempotentReplayHeader) != "" {
+		t.Fatalf("first request: status=%d replay=%q", first.Code, first.Header().Get(idempotentReplayHeader))
+	}
+
+	second := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers)
+	if second.Code != http.StatusOK || second.Header().Get(idempotentReplayHeader) != "true" {
+		t.Fatalf("retry should be replayed: status=%d replay=%q", second.Code, second.Header().Get(idempotentReplayHeader))
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("replayed body mismatch: %s vs %s", second.Body.String(), first.Body.String())
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream hit, got %d", got)
+	}
+
+	// 无 Idempotency-Key、不同请求体均不命中
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, nil); w.Header().Get(idempotentReplayHeader) != "" {
+		t.Fatal("request without Idempotency-Key must not be replayed")
+	}
+	other := map[string]any{"model": "gpt-4", "messages": []map[string]string{{"role": "user", "content": "other"}}}
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", other, headers); w.Header().Get(idempotentReplayHeader) != "" {
+		t.Fatal("different body must not be replayed")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("expected 3 upstream hits, got %d", got)
+	}
+}
+
+func TestProxy_IdempotencyKeyDoesNotCacheFailures(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad request"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"ok","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "idem-fail", models: "gpt-4", apiKey: "sk-idem"},
+	}, map[int]string{0: upstream.URL})
+	env.server.idempotencyCache = newResponseCache(time.Minute, defaultResponseCacheMaxBytes)
+
+	body := map[string]any{"model": "gpt-4", "messages": []map[string]string{{"role": "user", "content": "hi"}}}
+	headers := map[string]string{idempotencyKeyHeader: "retry-fail"}
+
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers); w.Code != http.StatusBadRequest {
+		t.Fatalf("first request status=%d, want 400", w.Code)
+	}
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers)
+	if w.Code != http.StatusOK || w.Header().Get(idempotentReplayHeader) != "" {
+		t.Fatalf("failed response must not be cached: status=%d replay=%q", w.Code, w.Header().Get(idempotentReplayHeader))
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("expected 2 upstream hits, got %d", got)
+	}
+}
+
+func TestProxy_IdempotencyKeyConcurrentDuplicateGetsConflict(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	entered := make(chan struct{})
+	releaseUpstream := make(chan struct{})
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			close(entered)
+			<-releaseUpstream
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"only-once","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "idem-concurrent", models: "gpt-4", apiKey: "sk-idem"},
+	}, map[int]string{0: upstream.URL})
+	env.server.idempotencyCache = newResponseCache(time.Minute, defaultResponseCacheMaxBytes)
+
+	body := map[string]any{"model": "gpt-4", "messages": []map[string]string{{"role": "user", "content": "hi"}}}
+	headers := map[string]string{idempotencyKeyHeader: "retry-concurrent"}
+
+	firstDone := make(chan int, 1)
+	go func() {
+		firstDone <- doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers).Code
+	}()
+	<-entered
+
+	// 首个请求仍在上游处理中：同键请求返回 409，不调用上游
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers); w.Code != http.StatusConflict {
+		t.Fatalf("concurrent duplicate status=%d, want 409: %s", w.Code, w.Body.String())
+	}
+
+	close(releaseUpstream)
+	if code := <-firstDone; code != http.StatusOK {
+		t.Fatalf("first request status=%d, want 200", code)
+	}
+
+	// 首个请求完成后重试命中缓存重放
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers); w.Header().Get(idempotentReplayHeader) != "true" {
+		t.Fatalf("retry after completion should be replayed: status=%d", w.Code)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream hit, got %d", got)
+	}
+}
+
+func TestIdempotencyCaptureWriter_StreamingClearsWriteDeadline(t *testing.T) {
+	t.Parallel()
+
+	rec := &deadlineRecorderResponseWriter{}
+	c, _ := gin.CreateTestContext(rec)
+	w := newIdempotencyCaptureWriter(c.Writer)
+	c.Writer = w
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	disableResponseWriteTimeout(c.Writer, "流式")
+	_, _ = w.WriteString("data: {\"id\":\"1\"}\n\n")
+	c.Writer.Flush()
+
+	if !rec.deadlineCalled {
+		t.Fatal("SetWriteDeadline was not reached through idempotencyCaptureWriter")
+	}
+	if !rec.writeDeadline.IsZero() {
+		t.Fatalf("writeDeadline=%v, want zero time", rec.writeDeadline)
+	}
+	if !strings.Contains(rec.body.String(), "data:") {
+		t.Fatalf("stream chunk not forwarded: %q", rec.body.String())
+	}
+}
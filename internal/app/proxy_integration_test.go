@@ -2,6 +2,7 @@ package app
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -33,6 +34,8 @@ type testChannel struct {
 	protocolTransformMode string
 	protocolTransforms    []string
 	customRequestRules    *model.CustomRequestRules
+	allowedMethods        []string
+	successCodes          string
 	models                string // 逗号分隔的模型列表
 	apiKey                string
 	priority              int
@@ -88,6 +91,8 @@ func setupProxyTestEnv(t testing.TB, channels []testChannel, upstreamURLs map[in
 			ProtocolTransformMode: ch.protocolTransformMode,
 			ProtocolTransforms:    ch.protocolTransforms,
 			CustomRequestRules:    ch.customRequestRules,
+			AllowedMethods:        ch.allowedMethods,
+			SuccessCodes:          ch.successCodes,
 			Priority:              priority,
 			Enabled:               true,
 			ModelEntries:          modelEntries,
@@ -742,8 +747,8 @@ func TestProxy_NoAvailableUpstreamLogKeepsAuthTokenID(t *testing.T) {
 		"model":    "no-upstream-model",
 		"messages": []map[string]string{{"role": "user", "content": "hi"}},
 	}, nil)
-	if w.Code != http.StatusServiceUnavailable {
-		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
 	}
 
 	entry := waitForProxyLog(t, env, "no-upstream-model")
@@ -752,6 +757,113 @@ func TestProxy_NoAvailableUpstreamLogKeepsAuthTokenID(t *testing.T) {
 	}
 }
 
+func TestProxy_DefaultModelFallback(t *testing.T) {
+	orig := getDefaultModel
+	getDefaultModel = func() string { return "gpt-4o-mini" }
+	t.Cleanup(func() { getDefaultModel = orig })
+
+	var gotModel atomic.Value
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotModel.Store(req.Model)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4o-mini", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "unsupported-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got, _ := gotModel.Load().(string); got != "gpt-4o-mini" {
+		t.Fatalf("upstream model=%q, want gpt-4o-mini", got)
+	}
+	if h := w.Header().Get("X-Model-Fallback"); h != "gpt-4o-mini" {
+		t.Fatalf("X-Model-Fallback=%q", h)
+	}
+}
+
+func TestProxy_DefaultModelFallback_UnavailableReturns404(t *testing.T) {
+	orig := getDefaultModel
+	getDefaultModel = func() string { return "also-unsupported" }
+	t.Cleanup(func() { getDefaultModel = orig })
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4o-mini", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "unsupported-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+	if h := w.Header().Get("X-Model-Fallback"); h != "" {
+		t.Fatalf("unexpected X-Model-Fallback=%q", h)
+	}
+}
+
+func TestProxy_RequestIDPropagatedEchoedAndLogged(t *testing.T) {
+	t.Parallel()
+
+	var upstreamRequestID atomic.Value
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamRequestID.Store(r.Header.Get("X-Request-Id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "upstream-generated")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	body := map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{"X-Request-Id": "client-req-123"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got, _ := upstreamRequestID.Load().(string); got != "client-req-123" {
+		t.Fatalf("upstream X-Request-Id=%q", got)
+	}
+	if got := w.Header().Values("X-Request-Id"); len(got) != 1 || got[0] != "client-req-123" {
+		t.Fatalf("response X-Request-Id=%v", got)
+	}
+	if entry := waitForProxyLog(t, env, "gpt-4"); entry.RequestID != "client-req-123" {
+		t.Fatalf("log request_id=%q", entry.RequestID)
+	}
+
+	// 未携带时由服务端生成
+	w = doProxyRequest(t, env.engine, "/v1/chat/completions", body, nil)
+	generated := w.Header().Get("X-Request-Id")
+	if generated == "" || generated == "client-req-123" {
+		t.Fatalf("expected generated request id, got %q", generated)
+	}
+	if got, _ := upstreamRequestID.Load().(string); got != generated {
+		t.Fatalf("upstream X-Request-Id=%q, want %q", got, generated)
+	}
+}
+
 func TestProxy_LogsAnthropicBudgetAsThinkingEffort(t *testing.T) {
 	t.Parallel()
 
@@ -864,6 +976,157 @@ func TestProxy_LogsThinkingEffortFromRequestAndSSEOverride(t *testing.T) {
 	}
 }
 
+func TestProxy_LogsAttemptNumberAcrossChannelFallback(t *testing.T) {
+	t.Parallel()
+
+	failing := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":"boom"}`))
+	}))
+	defer failing.Close()
+	healthy := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ok","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer healthy.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "attempt-primary", models: "attempt-model", apiKey: "sk-primary", channelType: util.ChannelTypeOpenAI, priority: 10},
+		{name: "attempt-fallback", models: "attempt-model", apiKey: "sk-fallback", channelType: util.ChannelTypeOpenAI, priority: 1},
+	}, map[int]string{0: failing.URL, 1: healthy.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "attempt-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+	deadline := time.Now().Add(2 * time.Second)
+	var logs []*model.LogEntry
+	for time.Now().Before(deadline) {
+		var err error
+		logs, err = env.store.ListLogs(ctx, since, 20, 0, &model.LogFilter{LogSource: model.LogSourceProxy})
+		if err != nil {
+			t.Fatalf("ListLogs failed: %v", err)
+		}
+		if len(logs) >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 proxy logs, got %d", len(logs))
+	}
+	for _, entry := range logs {
+		switch entry.StatusCode {
+		case http.StatusOK:
+			if entry.AttemptNumber != 2 || entry.ChannelAttempt != 2 {
+				t.Fatalf("success log attempt=%d channel_attempt=%d, want 2/2", entry.AttemptNumber, entry.ChannelAttempt)
+			}
+		default:
+			if entry.AttemptNumber != 1 || entry.ChannelAttempt != 1 {
+				t.Fatalf("failed log attempt=%d channel_attempt=%d, want 1/1", entry.AttemptNumber, entry.ChannelAttempt)
+			}
+		}
+	}
+}
+
+func TestProxy_SuccessCodes_NonStandardCodeTreatedAsSuccess(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"id":"quirky","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer upstream.Close()
+	fallback := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fallback channel must not be tried when 404 is a success code")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fallback.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "quirky", models: "quirky-model", successCodes: "200-299,404", priority: 10},
+		{name: "quirky-fallback", models: "quirky-model", priority: 1},
+	}, map[int]string{0: upstream.URL, 1: fallback.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "quirky-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), `"quirky"`) {
+		t.Fatalf("expected upstream 404 body passed through, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream hit, got %d", got)
+	}
+
+	cfgs, err := env.store.ListConfigs(context.Background())
+	if err != nil {
+		t.Fatalf("ListConfigs failed: %v", err)
+	}
+	for _, cfg := range cfgs {
+		if cfg.CooldownUntil != 0 {
+			t.Fatalf("channel %s should not be cooled down: %+v", cfg.Name, cfg)
+		}
+	}
+}
+
+func TestProxy_SuccessCodes_Excluded2xxFallsBackToNextChannel(t *testing.T) {
+	t.Parallel()
+
+	accepted := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"error":"queued"}`))
+	}))
+	defer accepted.Close()
+	healthy := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ok","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer healthy.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "strict", models: "strict-model", successCodes: "200", priority: 10},
+		{name: "strict-fallback", models: "strict-model", priority: 1},
+	}, map[int]string{0: accepted.URL, 1: healthy.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "strict-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"ok"`) {
+		t.Fatalf("expected fallback success, got %d: %s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		logs, err := env.store.ListLogs(ctx, since, 20, 0, &model.LogFilter{LogSource: model.LogSourceProxy})
+		if err != nil {
+			t.Fatalf("ListLogs failed: %v", err)
+		}
+		for _, entry := range logs {
+			if entry.StatusCode == http.StatusBadGateway && strings.Contains(entry.Message, "not in channel success_codes") {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rejected 2xx log not found: %+v", logs)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
 func waitForProxyLog(t testing.TB, env *proxyTestEnv, modelName string) *model.LogEntry {
 	t.Helper()
 
@@ -953,6 +1216,67 @@ func TestProxy_SkipsChannelAfterRPMLimitExceeded(t *testing.T) {
 	}
 }
 
+func TestProxy_SkipsChannelWhenKeyRPMLimitExceeded(t *testing.T) {
+	t.Parallel()
+
+	var limitedHits, fallbackHits atomic.Int64
+	newUpstream := func(hits *atomic.Int64, id string) *testHTTPServer {
+		return newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"` + id + `","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+		}))
+	}
+	limitedUpstream := newUpstream(&limitedHits, "from-limited")
+	defer limitedUpstream.Close()
+	fallbackUpstream := newUpstream(&fallbackHits, "from-fallback")
+	defer fallbackUpstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "limited", models: "gpt-4", apiKey: "sk-limited", priority: 100},
+		{name: "fallback", models: "gpt-4", apiKey: "sk-fallback", priority: 90},
+	}, map[int]string{0: limitedUpstream.URL, 1: fallbackUpstream.URL})
+
+	ctx := context.Background()
+	cfgs, err := env.store.ListConfigs(ctx)
+	if err != nil {
+		t.Fatalf("ListConfigs failed: %v", err)
+	}
+	var limitedID int64
+	for _, cfg := range cfgs {
+		if cfg.Name == "limited" {
+			limitedID = cfg.ID
+		}
+	}
+	if err := env.store.UpdateAPIKeyRPMLimits(ctx, limitedID, map[int]int{0: 1}); err != nil {
+		t.Fatalf("UpdateAPIKeyRPMLimits failed: %v", err)
+	}
+	env.server.InvalidateChannelListCache()
+	env.server.InvalidateAPIKeysCache(limitedID)
+
+	requestBody := map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}
+	for i := range 2 {
+		if w := doProxyRequest(t, env.engine, "/v1/chat/completions", requestBody, nil); w.Code != http.StatusOK {
+			t.Fatalf("request #%d status=%d body=%s", i+1, w.Code, w.Body.String())
+		}
+	}
+	if limitedHits.Load() != 1 || fallbackHits.Load() != 1 {
+		t.Fatalf("hits limited=%d fallback=%d, want 1/1", limitedHits.Load(), fallbackHits.Load())
+	}
+
+	// Key级RPM限流只跳过渠道，不应触发渠道冷却
+	cooldowns, err := env.store.GetAllChannelCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("GetAllChannelCooldowns failed: %v", err)
+	}
+	if _, ok := cooldowns[limitedID]; ok {
+		t.Fatalf("key rpm limit should not cool down channel %d", limitedID)
+	}
+}
+
 func TestProxy_SkipsChannelAfterConcurrencyLimitExceeded(t *testing.T) {
 	t.Parallel()
 
@@ -3709,6 +4033,121 @@ func TestProxy_StreamingEmpty200RetriesNextChannel(t *testing.T) {
 	}
 }
 
+func TestProxy_CorruptGzip200RetriesNextChannel(t *testing.T) {
+	t.Parallel()
+
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%v", stream), func(t *testing.T) {
+			t.Parallel()
+
+			var corruptCalls atomic.Int32
+			upstreamCorrupt := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				corruptCalls.Add(1)
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.Header().Set("Content-Encoding", "gzip")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("definitely not gzip data"))
+			}))
+			defer upstreamCorrupt.Close()
+
+			upstreamOK := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if stream {
+					w.Header().Set("Content-Type", "text/event-stream")
+					_, _ = fmt.Fprint(w, "data: {\"id\":\"from-ch2\",\"choices\":[{\"delta\":{\"content\":\"ok\"},\"finish_reason\":null}]}\n\n")
+					_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"from-ch2","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+			}))
+			defer upstreamOK.Close()
+
+			env := setupProxyTestEnv(t, []testChannel{
+				{name: "ch-corrupt", models: "gpt-4", apiKey: "sk-corrupt", priority: 100},
+				{name: "ch-ok", models: "gpt-4", apiKey: "sk-ok", priority: 50},
+			}, map[int]string{
+				0: upstreamCorrupt.URL,
+				1: upstreamOK.URL,
+			})
+			env.server.client = &http.Client{Transport: transparentGzipTransport(sharedTestHTTPClient.Transport)}
+
+			w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+				"model":    "gpt-4",
+				"stream":   stream,
+				"messages": []map[string]string{{"role": "user", "content": "hi"}},
+			}, nil)
+
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "from-ch2") {
+				t.Fatalf("expected response from second channel, got %d: %s", w.Code, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), "not gzip") {
+				t.Fatalf("corrupt upstream bytes leaked to client: %s", w.Body.String())
+			}
+			if got := corruptCalls.Load(); got != 1 {
+				t.Fatalf("corrupt upstream calls=%d, want 1", got)
+			}
+
+			// 失败尝试单独记录为解压错误（502）
+			ctx := context.Background()
+			since := time.Now().Add(-time.Minute)
+			deadline := time.Now().Add(2 * time.Second)
+			var failed *model.LogEntry
+			for failed == nil && time.Now().Before(deadline) {
+				logs, err := env.store.ListLogs(ctx, since, 20, 0, &model.LogFilter{LogSource: model.LogSourceProxy})
+				if err != nil {
+					t.Fatalf("ListLogs failed: %v", err)
+				}
+				for _, entry := range logs {
+					if entry.StatusCode != http.StatusOK {
+						failed = entry
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			if failed == nil || failed.StatusCode != http.StatusBadGateway || !strings.Contains(failed.Message, "decompression failed") {
+				t.Fatalf("expected 502 decompression log, got %+v", failed)
+			}
+		})
+	}
+}
+
+// transparentGzipTransport 模拟 http.Transport 的透明 gzip 解压（内存测试传输不解压）：
+// 移除 Content-Encoding、标记 Uncompressed，首次 Read 时才解析 gzip 头
+func transparentGzipTransport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := base.RoundTrip(req)
+		if err != nil || resp.Header.Get("Content-Encoding") != "gzip" {
+			return resp, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+		resp.Body = &lazyGzipBody{body: resp.Body}
+		return resp, nil
+	})
+}
+
+type lazyGzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *lazyGzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *lazyGzipBody) Close() error {
+	return b.body.Close()
+}
+
 func TestProxy_StreamingPingOnly200RetriesNextChannel(t *testing.T) {
 	t.Parallel()
 
@@ -4459,8 +4898,8 @@ func TestProxy_ChannelRestriction_PreservesNoCandidateResponse(t *testing.T) {
 		"messages": []map[string]string{{"role": "user", "content": "hi"}},
 	}, nil)
 
-	if w.Code != http.StatusServiceUnavailable {
-		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
 	}
 	if got := upstreamHits.Load(); got != 0 {
 		t.Fatalf("upstream hits=%d, want 0", got)
@@ -4504,7 +4943,7 @@ func TestProxy_CostLimitExceeded_Returns429(t *testing.T) {
 	}
 }
 
-func TestProxy_NoChannels_Returns503(t *testing.T) {
+func TestProxy_NoChannels_Returns404(t *testing.T) {
 	t.Parallel()
 
 	// 创建没有渠道的环境
@@ -4520,8 +4959,8 @@ func TestProxy_NoChannels_Returns503(t *testing.T) {
 		"messages": []map[string]string{{"role": "user", "content": "hi"}},
 	}, nil)
 
-	if w.Code != http.StatusServiceUnavailable {
-		t.Fatalf("expected 503, got %d: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
 	}
 }
 
@@ -4748,3 +5187,224 @@ func TestProxy_SSEErrorEventBeforeClientOutput_RetriesNextChannel(t *testing.T)
 		t.Fatalf("expected second channel to be tried once, got %d", secondCalls.Load())
 	}
 }
+
+func TestProxy_AllowedMethods_RejectsWith405(t *testing.T) {
+	t.Parallel()
+
+	var upstreamHits atomic.Int32
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "get-only", models: "gpt-4", apiKey: "sk-1", allowedMethods: []string{http.MethodGet}},
+	}, map[int]string{0: upstream.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := upstreamHits.Load(); got != 0 {
+		t.Fatalf("upstream hits=%d, want 0", got)
+	}
+}
+
+func TestProxy_AllowedMethods_SkipsRestrictedChannel(t *testing.T) {
+	t.Parallel()
+
+	var restrictedHits, openHits atomic.Int32
+	restricted := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restrictedHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer restricted.Close()
+	open := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openHits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","choices":[]}`))
+	}))
+	defer open.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "get-only", models: "gpt-4", apiKey: "sk-1", priority: 100, allowedMethods: []string{http.MethodGet}},
+		{name: "open", models: "gpt-4", apiKey: "sk-2", priority: 1},
+	}, map[int]string{0: restricted.URL, 1: open.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if restrictedHits.Load() != 0 || openHits.Load() != 1 {
+		t.Fatalf("hits restricted=%d open=%d, want 0/1", restrictedHits.Load(), openHits.Load())
+	}
+}
+
+func TestProxy_MinStreamBytesRetriesTruncatedStream(t *testing.T) {
+	orig := getMinStreamBytes
+	getMinStreamBytes = func() int64 { return 256 }
+	t.Cleanup(func() { getMinStreamBytes = orig })
+
+	truncated := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, "data: "+`{"id":"c1","choices":[{"delta":{"role":"assistant"}}]}`+"\n\n")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer truncated.Close()
+
+	var fullChunk strings.Builder
+	fullChunk.WriteString("data: " + `{"id":"c2","choices":[{"delta":{"content":"`)
+	fullChunk.WriteString(strings.Repeat("hello ", 60))
+	fullChunk.WriteString(`"}}]}` + "\n\n")
+	healthy := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, fullChunk.String())
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer healthy.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "min-bytes-primary", models: "min-bytes-model", apiKey: "sk-primary", channelType: util.ChannelTypeOpenAI, priority: 10},
+		{name: "min-bytes-fallback", models: "min-bytes-model", apiKey: "sk-fallback", channelType: util.ChannelTypeOpenAI, priority: 1},
+	}, map[int]string{0: truncated.URL, 1: healthy.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "min-bytes-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+		"stream":   true,
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if strings.Contains(body, `"id":"c1"`) {
+		t.Fatalf("truncated stream must not reach the client, body=%q", body)
+	}
+	if !strings.Contains(body, `"id":"c2"`) {
+		t.Fatalf("expected fallback stream, body=%q", body)
+	}
+}
+
+func TestEmptyStreamOutput_MinStreamBytesThreshold(t *testing.T) {
+	orig := getMinStreamBytes
+	t.Cleanup(func() { getMinStreamBytes = orig })
+
+	parser := newSSEUsageParser(util.ChannelTypeOpenAI)
+	if err := parser.Feed([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\n")); err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	stats := &streamReadStats{totalBytes: 48}
+
+	getMinStreamBytes = func() int64 { return 0 }
+	if isEmptyStreamOutput(parser, stats) || !hasCommittableStreamOutput(parser, stats) {
+		t.Fatal("threshold disabled: short reply must be treated as valid output")
+	}
+
+	getMinStreamBytes = func() int64 { return 64 }
+	if !isEmptyStreamOutput(parser, stats) || hasCommittableStreamOutput(parser, stats) {
+		t.Fatal("below threshold: stream must be held back and classified as empty")
+	}
+	if detail := emptyStreamDetail(stats); !strings.Contains(detail, "CCLOAD_MIN_STREAM_BYTES=64") {
+		t.Fatalf("detail=%q", detail)
+	}
+
+	stats.totalBytes = 64
+	if isEmptyStreamOutput(parser, stats) || !hasCommittableStreamOutput(parser, stats) {
+		t.Fatal("at threshold: stream must be committed")
+	}
+}
+
+func TestProxy_RedirectRoutingOnlySendsOriginalModel(t *testing.T) {
+	t.Parallel()
+
+	var upstreamModel atomic.Value
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		upstreamModel.Store(body["model"])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ok","choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "routing-only", models: "alias-model"},
+	}, map[int]string{0: upstream.URL})
+
+	ctx := context.Background()
+	configs, err := env.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	cfg := configs[0]
+	cfg.ModelEntries = []model.ModelEntry{{Model: "alias-model", RedirectModel: "canonical-model"}}
+	cfg.RedirectRoutingOnly = true
+	if _, err := env.store.UpdateConfig(ctx, cfg.ID, cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	env.server.InvalidateChannelListCache()
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "alias-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := upstreamModel.Load(); got != "alias-model" {
+		t.Fatalf("upstream model = %v, want original alias-model", got)
+	}
+
+	entry := waitForProxyLog(t, env, "alias-model")
+	if entry.ActualModel != "canonical-model" {
+		t.Fatalf("log actual_model = %q, want redirect target", entry.ActualModel)
+	}
+}
+
+func TestProxy_HideUpstreamErrorsReplacesRelayedBody(t *testing.T) {
+	t.Parallel()
+
+	const secret = "provider-internal-quota-detail"
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"` + secret + `"}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "hidden-errors", models: "hidden-model"},
+	}, map[int]string{0: upstream.URL})
+	env.server.hideUpstreamErrors = true
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "hidden-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status=%d, want 429; body=%s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), secret) {
+		t.Fatalf("upstream error body leaked to client: %s", w.Body.String())
+	}
+	if w.Header().Get("Retry-After") != "7" {
+		t.Fatalf("Retry-After=%q, want 7", w.Header().Get("Retry-After"))
+	}
+
+	entry := waitForProxyLog(t, env, "hidden-model")
+	if !strings.Contains(entry.Message, secret) {
+		t.Fatalf("server-side log should keep upstream error, message=%q", entry.Message)
+	}
+}
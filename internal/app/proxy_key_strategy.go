@@ -0,0 +1,19 @@
+package app
+
+import (
+	"strings"
+
+	"ccLoad/internal/model"
+)
+
+// keyStrategyHeader 请求级 Key 策略覆盖头（sequential / round_robin），仅用于本服务选 Key，不透传上游
+const keyStrategyHeader = "X-CCLoad-Key-Strategy"
+
+// parseKeyStrategyOverride 解析 Key 策略覆盖头；空值或未知策略返回空串（沿用渠道配置）
+func parseKeyStrategyOverride(raw string) string {
+	strategy := strings.ToLower(strings.TrimSpace(raw))
+	if strategy == "" || !model.IsValidKeyStrategy(strategy) {
+		return ""
+	}
+	return strategy
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// modelRestoreReader 将上游响应中的 model 字段改写为客户端请求的模型名（渠道 restore_response_model 开启且发生模型重定向时使用）
+// 流式（SSE）：逐行改写 data: 事件；非流式 JSON：读完整个响应体后改写一次
+// 覆盖的字段：顶层 model（OpenAI / Anthropic 非流式）、message.model（Anthropic message_start）、response.model（Codex）
+type modelRestoreReader struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	model   string
+	sse     bool
+	pending []byte
+	err     error
+}
+
+// newModelRestoreReader 包装上游响应体；sse=false 时按单个 JSON 文档处理
+func newModelRestoreReader(body io.ReadCloser, model string, sse bool) io.ReadCloser {
+	return &modelRestoreReader{
+		src:    bufio.NewReaderSize(body, SSEBufferSize),
+		closer: body,
+		model:  model,
+		sse:    sse,
+	}
+}
+
+func (r *modelRestoreReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *modelRestoreReader) Close() error {
+	return r.closer.Close()
+}
+
+// fill 读取下一段数据（SSE 为一行，JSON 为整个响应体）并改写
+func (r *modelRestoreReader) fill() {
+	if !r.sse {
+		data, err := io.ReadAll(r.src)
+		r.pending = rewriteResponseModel(data, r.model)
+		r.err = err
+		if r.err == nil {
+			r.err = io.EOF
+		}
+		return
+	}
+
+	line, err := r.src.ReadBytes('\n')
+	r.err = err
+	if len(line) == 0 {
+		return
+	}
+	r.pending = rewriteSSEDataLine(line, r.model)
+}
+
+// rewriteSSEDataLine 改写单行 "data: {...}"；非 data 行、[DONE] 与无法解析的行原样返回
+func rewriteSSEDataLine(line []byte, model string) []byte {
+	if !bytes.HasPrefix(line, []byte("data:")) || !bytes.Contains(line, []byte(`"model"`)) {
+		return line
+	}
+	payload := line[len("data:"):]
+	body := bytes.TrimSpace(payload)
+	if len(body) == 0 || body[0] != '{' {
+		return line
+	}
+	rewritten := rewriteResponseModel(body, model)
+	if bytes.Equal(rewritten, body) {
+		return line
+	}
+	// 保留原有的 "data:" 后空格与行尾换行
+	lead := payload[:len(payload)-len(bytes.TrimLeft(payload, " "))]
+	tail := payload[len(bytes.TrimRight(payload, "\r\n")):]
+	out := make([]byte, 0, len("data:")+len(lead)+len(rewritten)+len(tail))
+	out = append(out, "data:"...)
+	out = append(out, lead...)
+	out = append(out, rewritten...)
+	return append(out, tail...)
+}
+
+// modelFieldPaths 需要改写的模型字段路径
+var modelFieldPaths = []string{"model", "message.model", "response.model"}
+
+// rewriteResponseModel 原地替换 JSON 对象中的模型字段值，其余字节（字段顺序、数字精度、空白）保持不变；
+// 不含模型字段或不是 JSON 对象时原样返回
+func rewriteResponseModel(data []byte, model string) []byte {
+	if !bytes.Contains(data, []byte(`"model"`)) || !gjson.ValidBytes(data) {
+		return data
+	}
+	out := data
+	for _, path := range modelFieldPaths {
+		current := gjson.GetBytes(out, path)
+		if current.Type != gjson.String || current.Str == model {
+			continue
+		}
+		replaced, err := sjson.SetBytes(out, path, model)
+		if err != nil {
+			return data
+		}
+		out = replaced
+	}
+	return out
+}
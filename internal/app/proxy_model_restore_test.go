@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestRewriteSSEDataLine(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		line string
+		want []string // 期望包含的片段
+		same bool     // 期望原样返回
+	}{
+		{name: "openai chunk", line: "data: {\"id\":\"c1\",\"model\":\"upstream-x\",\"choices\":[]}\n", want: []string{`"model":"client-x"`, "data: {", "}\n"}},
+		{name: "anthropic message_start", line: "data: {\"type\":\"message_start\",\"message\":{\"id\":\"m\",\"model\":\"upstream-x\"}}\r\n", want: []string{`"model":"client-x"`, "}\r\n"}},
+		{name: "codex response", line: "data:{\"type\":\"response.created\",\"response\":{\"model\":\"upstream-x\"}}\n", want: []string{`"model":"client-x"`, "data:{"}},
+		{name: "event line", line: "event: message_start\n", same: true},
+		{name: "done", line: "data: [DONE]\n", same: true},
+		{name: "no model", line: "data: {\"type\":\"ping\"}\n", same: true},
+		{name: "model in content only", line: "data: {\"delta\":{\"text\":\"\\\"model\\\"\"}}\n", same: true},
+	}
+	for _, tc := range cases {
+		got := string(rewriteSSEDataLine([]byte(tc.line), "client-x"))
+		if tc.same {
+			if got != tc.line {
+				t.Errorf("%s: expected unchanged line, got %q", tc.name, got)
+			}
+			continue
+		}
+		for _, frag := range tc.want {
+			if !strings.Contains(got, frag) {
+				t.Errorf("%s: %q missing %q", tc.name, got, frag)
+			}
+		}
+		if strings.Contains(got, "upstream-x") {
+			t.Errorf("%s: upstream model not rewritten: %q", tc.name, got)
+		}
+	}
+}
+
+func TestRewriteResponseModel_ReplacesFieldInPlace(t *testing.T) {
+	t.Parallel()
+
+	// 字段顺序、大整数精度、空白与转义均需保持原样，仅 model 值被替换
+	body := `{"z":1, "id":12345678901234567890,"model" : "upstream-x","message":{"model":"upstream-x","text":"a\u00e9"},"a":[1.50]}`
+	want := `{"z":1, "id":12345678901234567890,"model" : "client-x","message":{"model":"client-x","text":"a\u00e9"},"a":[1.50]}`
+	if got := string(rewriteResponseModel([]byte(body), "client-x")); got != want {
+		t.Fatalf("rewriteResponseModel()=\n%s\nwant\n%s", got, want)
+	}
+
+	for _, same := range []string{
+		`{"model":"client-x"}`,
+		`{"model":42}`,
+		`{"model":"upstream-x"`,
+		`["model"]`,
+	} {
+		if got := string(rewriteResponseModel([]byte(same), "client-x")); got != same {
+			t.Errorf("rewriteResponseModel(%s)=%s, want unchanged", same, got)
+		}
+	}
+}
+
+func TestModelRestoreReader_JSONAndSSE(t *testing.T) {
+	t.Parallel()
+
+	jsonBody := io.NopCloser(strings.NewReader(`{"id":"msg","type":"message","model":"upstream-x","usage":{"input_tokens":1}}`))
+	data, err := io.ReadAll(newModelRestoreReader(jsonBody, "client-x", false))
+	if err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	if !strings.Contains(string(data), `"model":"client-x"`) || !strings.Contains(string(data), `"input_tokens":1`) {
+		t.Fatalf("unexpected json rewrite: %s", data)
+	}
+
+	stream := "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"model\":\"upstream-x\"}}\n\ndata: {\"model\":\"upstream-x\"}" // 末行无换行
+	data, err = io.ReadAll(newModelRestoreReader(io.NopCloser(strings.NewReader(stream)), "client-x", true))
+	if err != nil {
+		t.Fatalf("read sse: %v", err)
+	}
+	got := string(data)
+	if strings.Contains(got, "upstream-x") || strings.Count(got, "client-x") != 2 {
+		t.Fatalf("unexpected sse rewrite: %q", got)
+	}
+	if !strings.HasPrefix(got, "event: message_start\n") || !strings.Contains(got, "}\n\ndata: {") {
+		t.Fatalf("sse framing should be preserved: %q", got)
+	}
+}
+
+func TestProxy_RestoreResponseModel(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"stream":true`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"id\":\"c\",\"model\":\"canonical-model\",\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","model":"canonical-model","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "restore", models: "alias-model"},
+	}, map[int]string{0: upstream.URL})
+
+	ctx := context.Background()
+	configs, err := env.store.ListConfigs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("ListConfigs: %v (%d)", err, len(configs))
+	}
+	cfg := configs[0]
+	cfg.ModelEntries = []model.ModelEntry{{Model: "alias-model", RedirectModel: "canonical-model"}}
+
+	request := func(stream bool) string {
+		t.Helper()
+		w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+			"model":    "alias-model",
+			"stream":   stream,
+			"messages": []map[string]string{{"role": "user", "content": "hi"}},
+		}, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	// 未开启：透传上游模型名
+	if _, err := env.store.UpdateConfig(ctx, cfg.ID, cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	env.server.InvalidateChannelListCache()
+	if body := request(false); !strings.Contains(body, `"model":"canonical-model"`) {
+		t.Fatalf("flag off should pass upstream model through: %s", body)
+	}
+
+	cfg.RestoreResponseModel = true
+	if _, err := env.store.UpdateConfig(ctx, cfg.ID, cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	env.server.InvalidateChannelListCache()
+
+	for _, stream := range []bool{false, true} {
+		body := request(stream)
+		if strings.Contains(body, "canonical-model") || !strings.Contains(body, `"model":"alias-model"`) {
+			t.Fatalf("stream=%v: expected restored model, got %s", stream, body)
+		}
+	}
+}
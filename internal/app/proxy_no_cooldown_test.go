@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProxy_NoCooldownStatusRetriesWithoutCooldown(t *testing.T) {
+	orig := getNoCooldownStatusCodes
+	getNoCooldownStatusCodes = func() map[int]struct{} { return map[int]struct{}{529: {}} }
+	t.Cleanup(func() { getNoCooldownStatusCodes = orig })
+
+	overloaded := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(529)
+		_, _ = fmt.Fprint(w, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+	}))
+	defer overloaded.Close()
+	healthy := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id":"chatcmpl-1","choices":[{"message":{"content":"from-healthy"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`)
+	}))
+	defer healthy.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch-overloaded", models: "gpt-4", apiKey: "sk-1", priority: 10},
+		{name: "ch-healthy", models: "gpt-4", apiKey: "sk-2", priority: 1},
+	}, map[int]string{0: overloaded.URL, 1: healthy.URL})
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "from-healthy") {
+		t.Fatalf("expected fallback to healthy channel, got %d: %s", w.Code, w.Body.String())
+	}
+
+	ctx := context.Background()
+	channelCooldowns, err := env.store.GetAllChannelCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("GetAllChannelCooldowns: %v", err)
+	}
+	keyCooldowns, err := env.store.GetAllKeyCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("GetAllKeyCooldowns: %v", err)
+	}
+	if len(channelCooldowns) != 0 || len(keyCooldowns) != 0 {
+		t.Fatalf("529 must not cool down: channels=%v keys=%v", channelCooldowns, keyCooldowns)
+	}
+}
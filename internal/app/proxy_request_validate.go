@@ -0,0 +1,49 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ccLoad/internal/protocol"
+
+	"github.com/bytedance/sonic"
+)
+
+// requiredBodyArrayField 返回请求族要求的非空数组字段（空串表示不校验）
+// chat/completions 与 messages 需要 messages，Gemini generateContent 需要 contents
+func requiredBodyArrayField(family protocol.RequestFamily) string {
+	switch family {
+	case protocol.RequestFamilyChatCompletions, protocol.RequestFamilyMessages:
+		return "messages"
+	case protocol.RequestFamilyGenerateContent:
+		return "contents"
+	default:
+		return ""
+	}
+}
+
+// validateRequestBodySchema 对请求体做最小结构校验（CCLOAD_VALIDATE_REQUESTS 开启时），
+// 明显错误的请求直接返回 400，不浪费上游尝试与配额
+func validateRequestBodySchema(requestPath string, body []byte) error {
+	field := requiredBodyArrayField(protocol.DetectRequestFamily(requestPath))
+	if field == "" {
+		return nil
+	}
+
+	var root map[string]json.RawMessage
+	if err := sonic.Unmarshal(body, &root); err != nil || root == nil {
+		return fmt.Errorf("invalid request body: must be a JSON object")
+	}
+	raw, ok := root[field]
+	if !ok {
+		return fmt.Errorf("invalid request body: missing required field %q", field)
+	}
+	var items []json.RawMessage
+	if err := sonic.Unmarshal(raw, &items); err != nil || items == nil {
+		return fmt.Errorf("invalid request body: field %q must be an array", field)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("invalid request body: field %q must not be empty", field)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateRequestBodySchema(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		path    string
+		body    string
+		wantErr string
+	}{
+		{name: "chat ok", path: "/v1/chat/completions", body: `{"model":"m","messages":[{"role":"user","content":"hi"}]}`},
+		{name: "chat missing", path: "/v1/chat/completions", body: `{"model":"m","prompt":"hi"}`, wantErr: `missing required field "messages"`},
+		{name: "chat empty", path: "/v1/chat/completions", body: `{"model":"m","messages":[]}`, wantErr: "must not be empty"},
+		{name: "chat not array", path: "/v1/chat/completions", body: `{"model":"m","messages":"hi"}`, wantErr: "must be an array"},
+		{name: "anthropic null", path: "/v1/messages", body: `{"model":"m","messages":null}`, wantErr: "must be an array"},
+		{name: "not object", path: "/v1/messages", body: `[1]`, wantErr: "must be a JSON object"},
+		{name: "gemini ok", path: "/v1beta/models/gemini-pro:generateContent", body: `{"contents":[{"parts":[{"text":"hi"}]}]}`},
+		{name: "gemini missing", path: "/v1beta/models/gemini-pro:streamGenerateContent", body: `{"messages":[{}]}`, wantErr: `missing required field "contents"`},
+		{name: "responses skipped", path: "/v1/responses", body: `{"model":"m"}`},
+		{name: "embeddings skipped", path: "/v1/embeddings", body: `{"model":"m","input":"x"}`},
+	}
+	for _, tc := range cases {
+		err := validateRequestBodySchema(tc.path, []byte(tc.body))
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: err=%v, want containing %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestProxy_ValidateRequestsRejectsMalformedBody(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch1", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+
+	malformed := map[string]any{"model": "gpt-4", "prompt": "hi"}
+
+	// 未开启：原样转发
+	if w := doProxyRequest(t, env.engine, "/v1/chat/completions", malformed, nil); w.Code != http.StatusOK {
+		t.Fatalf("validation off: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	env.server.validateRequests = true
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", malformed, nil)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "messages") {
+		t.Fatalf("validation on: status=%d body=%s", w.Code, w.Body.String())
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("upstream hits=%d, want 1 (rejected request must not be forwarded)", hits.Load())
+	}
+}
@@ -74,6 +74,8 @@ func (r *firstByteDetector) Read(p []byte) (n int, err error) {
 func streamCopyWithBufferSize(ctx context.Context, src io.Reader, dst http.ResponseWriter, onData func([]byte) error, bufSize int) error {
 	stopCloseOnCancel := closeReaderOnContextCancel(ctx, src)
 	defer stopCloseOnCancel()
+	dst, stopKeepalive := startSSEKeepalive(ctx, dst, getSSEKeepaliveInterval())
+	defer stopKeepalive()
 
 	buf := make([]byte, bufSize)
 	for {
@@ -249,6 +251,8 @@ func streamTransformSSEEventsUntil(
 ) error {
 	stopCloseOnCancel := closeReaderOnContextCancel(ctx, src)
 	defer stopCloseOnCancel()
+	dst, stopKeepalive := startSSEKeepalive(ctx, dst, getSSEKeepaliveInterval())
+	defer stopKeepalive()
 
 	reader := bufio.NewReader(src)
 	var eventBuf bytes.Buffer
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"ccLoad/internal/util"
+
+	"github.com/bytedance/sonic"
+)
+
+// tryNonStreamFallback 流式请求以首字节超时告终时，以 stream=false 重试首个超时渠道一次
+// （CCLOAD_STREAM_FALLBACK_NONSTREAM）。慢但可用的渠道往往能在非流式超时内完成，
+// 完整响应一次性返回给客户端，避免直接 503。
+// 仅处理请求体携带 stream 字段的协议（Claude/OpenAI）；Gemini 以路径区分流式，不做兜底。
+// ok=true 表示兜底成功且已写响应；失败时返回兜底结果供最终响应使用（可能为 nil）。
+func (s *Server) tryNonStreamFallback(ctx context.Context, reqCtx *proxyRequestContext, lastResult *proxyResult, w http.ResponseWriter) (*proxyResult, bool) {
+	if !s.streamFallbackNonStream || !reqCtx.isStreaming || reqCtx.firstByteTimeoutCfg == nil {
+		return nil, false
+	}
+	if lastResult == nil || lastResult.status != util.StatusFirstByteTimeout {
+		return nil, false
+	}
+	if strings.Contains(reqCtx.requestPath, ":streamGenerateContent") || ctx.Err() != nil {
+		return nil, false
+	}
+	body, ok := disableStreamInBody(reqCtx.body)
+	if !ok {
+		return nil, false
+	}
+
+	cfg := reqCtx.firstByteTimeoutCfg
+	log.Printf("[INFO] 流式请求全部首字节超时，以非流式兜底重试渠道 %s (ID=%d)", cfg.Name, cfg.ID)
+	reqCtx.body = body
+	reqCtx.translatedBody = body
+	reqCtx.isStreaming = false
+
+	result, err := s.tryChannelWithKeys(ctx, cfg, reqCtx, w)
+	if err != nil || result == nil {
+		return nil, false
+	}
+	if result.succeeded {
+		return nil, true
+	}
+	return result, false
+}
+
+// disableStreamInBody 将请求体的 stream 置为 false，并移除仅流式有效的 stream_options
+// （OpenAI 会拒绝非流式请求携带 stream_options）。其他字段保留 RawMessage。
+func disableStreamInBody(body []byte) ([]byte, bool) {
+	var reqData map[string]json.RawMessage
+	if err := sonic.Unmarshal(body, &reqData); err != nil || reqData == nil {
+		return nil, false
+	}
+	if _, ok := reqData["stream"]; !ok {
+		return nil, false
+	}
+	reqData["stream"] = json.RawMessage("false")
+	delete(reqData, "stream_options")
+	modified, err := sonic.Marshal(reqData)
+	if err != nil {
+		return nil, false
+	}
+	return modified, true
+}
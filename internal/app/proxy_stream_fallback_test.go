@@ -0,0 +1,97 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProxy_StreamFallbackNonStreamAfterFirstByteTimeouts(t *testing.T) {
+	t.Parallel()
+
+	var streamCalls, fallbackCalls atomic.Int64
+	var fallbackBody atomic.Value
+	slowStreamUpstream := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var req map[string]any
+			_ = json.Unmarshal(body, &req)
+			if req["stream"] == true {
+				streamCalls.Add(1)
+				select {
+				case <-r.Context().Done():
+				case <-time.After(2 * time.Second):
+				}
+				return
+			}
+			fallbackCalls.Add(1)
+			fallbackBody.Store(string(body))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"id":"chatcmpl-1","object":"chat.completion","choices":[{"message":{"role":"assistant","content":"from-%s"}}]}`, name)
+		})
+	}
+	upstreamA := newTestHTTPServer(t, slowStreamUpstream("a"))
+	defer upstreamA.Close()
+	upstreamB := newTestHTTPServer(t, slowStreamUpstream("b"))
+	defer upstreamB.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ch-a", models: "gpt-4", apiKey: "sk-a"},
+		{name: "ch-b", models: "gpt-4", apiKey: "sk-b"},
+	}, map[int]string{0: upstreamA.URL, 1: upstreamB.URL})
+	env.server.firstByteTimeout = 50 * time.Millisecond
+
+	reqBody := map[string]any{
+		"model":          "gpt-4",
+		"stream":         true,
+		"stream_options": map[string]any{"include_usage": true},
+		"messages":       []map[string]string{{"role": "user", "content": "hi"}},
+	}
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", reqBody, nil)
+	if w.Code == http.StatusOK {
+		t.Fatalf("fallback disabled: expected failure, got 200: %s", w.Body.String())
+	}
+	if fallbackCalls.Load() != 0 {
+		t.Fatalf("fallback disabled: unexpected non-stream calls=%d", fallbackCalls.Load())
+	}
+
+	env.server.streamFallbackNonStream = true
+	w = doProxyRequest(t, env.engine, "/v1/chat/completions", reqBody, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from non-stream fallback, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "from-a") {
+		t.Fatalf("expected fallback to retry the first timed-out channel, body=%s", w.Body.String())
+	}
+	if got := fallbackCalls.Load(); got != 1 {
+		t.Fatalf("fallback calls=%d, want 1", got)
+	}
+	if sent, _ := fallbackBody.Load().(string); strings.Contains(sent, "stream_options") {
+		t.Fatalf("stream_options must be dropped in fallback body: %s", sent)
+	}
+}
+
+func TestDisableStreamInBody(t *testing.T) {
+	t.Parallel()
+
+	out, ok := disableStreamInBody([]byte(`{"model":"m","stream":true,"stream_options":{"include_usage":true},"n":12345678901234567890}`))
+	if !ok {
+		t.Fatal("expected body rewrite")
+	}
+	got := string(out)
+	if !strings.Contains(got, `"stream":false`) || strings.Contains(got, "stream_options") || !strings.Contains(got, "12345678901234567890") {
+		t.Fatalf("unexpected rewritten body: %s", got)
+	}
+
+	for _, body := range []string{`{"model":"m"}`, `not json`} {
+		if _, ok := disableStreamInBody([]byte(body)); ok {
+			t.Fatalf("expected no rewrite for %s", body)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sseKeepaliveComment SSE 注释行，客户端解析器会忽略，仅用于保持连接活跃
+const sseKeepaliveComment = ": keepalive\n\n"
+
+// getSSEKeepaliveInterval 延迟解析 CCLOAD_SSE_KEEPALIVE_MS（0=关闭，默认关闭）。
+// 与 getHostOverrides 相同：必须等 .env 加载后再读取环境变量。
+var getSSEKeepaliveInterval = sync.OnceValue(func() time.Duration {
+	raw := os.Getenv("CCLOAD_SSE_KEEPALIVE_MS")
+	if raw == "" {
+		return 0
+	}
+	ms, err := strconv.Atoi(raw)
+	if err != nil || ms < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_SSE_KEEPALIVE_MS=%s（必须为非负整数毫秒），SSE 保活已关闭", raw)
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+})
+
+// sseKeepaliveWriter 在上游长时间无数据时向客户端注入 SSE 注释行
+// - 仅在事件边界（已写出内容以空行结尾）注入，不会切断半个事件
+// - 首次真实写出之前不注入：响应头/延迟提交仍由正常数据流决定
+// - 所有 Write/Flush 由互斥锁串行化，保活 goroutine 与复制循环不会交错写入
+type sseKeepaliveWriter struct {
+	http.ResponseWriter
+	interval time.Duration
+
+	mu         sync.Mutex
+	lastWrite  time.Time
+	started    bool
+	atBoundary bool
+	prev, last byte // 最近两个非 \r 字节，用于判断事件边界
+	failed     bool
+}
+
+// startSSEKeepalive 为 SSE 响应启动保活注入，返回包装后的 writer 与停止函数。
+// interval<=0 或响应不是 text/event-stream 时原样返回 dst。
+// 停止函数会等待保活 goroutine 退出，保证返回后不再有并发写入。
+func startSSEKeepalive(ctx context.Context, dst http.ResponseWriter, interval time.Duration) (http.ResponseWriter, func()) {
+	if interval <= 0 || !isEventStream(dst.Header()) {
+		return dst, func() {}
+	}
+	w := &sseKeepaliveWriter{ResponseWriter: dst, interval: interval, lastWrite: time.Now()}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+				w.injectIfIdle()
+			}
+		}
+	}()
+	return w, func() {
+		close(stop)
+		<-done
+	}
+}
+
+func (w *sseKeepaliveWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.ResponseWriter.Write(p)
+	if n > 0 {
+		w.started = true
+		w.lastWrite = time.Now()
+		w.trackBoundary(p[:n])
+	}
+	return n, err
+}
+
+func (w *sseKeepaliveWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap 暴露底层 writer，供 http.ResponseController 使用
+func (w *sseKeepaliveWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// trackBoundary 记录写出内容是否停在事件边界（\n\n，忽略 \r）
+func (w *sseKeepaliveWriter) trackBoundary(p []byte) {
+	for _, b := range p {
+		if b == '\r' {
+			continue
+		}
+		w.prev, w.last = w.last, b
+	}
+	w.atBoundary = w.prev == '\n' && w.last == '\n'
+}
+
+// injectIfIdle 距上次写出已超过间隔且处于事件边界时注入一次保活注释
+func (w *sseKeepaliveWriter) injectIfIdle() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.started || !w.atBoundary || w.failed || time.Since(w.lastWrite) < w.interval {
+		return
+	}
+	// 延迟提交的 writer 尚未提交时，注入内容只会进缓冲区，没有保活意义
+	if c, ok := w.ResponseWriter.(interface{ Committed() bool }); ok && !c.Committed() {
+		return
+	}
+	if _, err := w.ResponseWriter.Write([]byte(sseKeepaliveComment)); err != nil {
+		w.failed = true // 客户端已断开：交给复制循环在下次写入时处理
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	w.lastWrite = time.Now()
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEKeepalive_InjectsOnlyAtEventBoundary(t *testing.T) {
+	t.Parallel()
+
+	const interval = 20 * time.Millisecond
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/event-stream")
+
+	ctx := context.Background()
+	w, stop := startSSEKeepalive(ctx, rec, interval)
+	if _, ok := w.(*sseKeepaliveWriter); !ok {
+		t.Fatalf("expected keepalive writer for event-stream response, got %T", w)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("data: a\n\n"))
+		time.Sleep(5 * interval) // 事件边界后静默：应注入保活
+		_, _ = pw.Write([]byte("data: b"))
+		time.Sleep(5 * interval) // 半个事件：不得注入
+		_, _ = pw.Write([]byte("\n\n"))
+		_ = pw.Close()
+	}()
+
+	if err := streamCopySSE(ctx, pr, w, nil); err != nil {
+		t.Fatalf("streamCopySSE: %v", err)
+	}
+	stop()
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "data: a\n\n"+sseKeepaliveComment) {
+		t.Fatalf("expected keepalive after first event, body=%q", body)
+	}
+	if !strings.HasSuffix(body, "data: b\n\n") {
+		t.Fatalf("keepalive must not split an event, body=%q", body)
+	}
+}
+
+func TestSSEKeepalive_NoopBeforeFirstWriteAndForNonSSE(t *testing.T) {
+	t.Parallel()
+
+	const interval = 10 * time.Millisecond
+
+	plain := httptest.NewRecorder()
+	plain.Header().Set("Content-Type", "application/json")
+	if w, stop := startSSEKeepalive(context.Background(), plain, interval); w != plain {
+		t.Fatalf("non-SSE response should not be wrapped, got %T", w)
+	} else {
+		stop()
+	}
+
+	sse := httptest.NewRecorder()
+	sse.Header().Set("Content-Type", "text/event-stream")
+	_, stop := startSSEKeepalive(context.Background(), sse, interval)
+	time.Sleep(5 * interval)
+	stop()
+	if sse.Body.Len() != 0 {
+		t.Fatalf("keepalive must wait for first upstream write, body=%q", sse.Body.String())
+	}
+
+	if w, stop := startSSEKeepalive(context.Background(), sse, 0); w != sse {
+		t.Fatalf("interval 0 should disable keepalive, got %T", w)
+	} else {
+		stop()
+	}
+}
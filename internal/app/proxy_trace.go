@@ -0,0 +1,118 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"ccLoad/internal/cooldown"
+	"ccLoad/internal/model"
+	"ccLoad/internal/util"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// debugTraceHeader 请求级尝试链路追踪开关（真值 + 有效管理员会话时生效），不透传上游
+	debugTraceHeader = "X-CCLoad-Debug"
+	// debugAdminTokenHeader 携带管理员 Web 会话 Token（代理请求的 Authorization 为 API 令牌），不透传上游
+	debugAdminTokenHeader = "X-CCLoad-Admin-Token"
+	// attemptTraceHeader 返回给调用方的尝试链路（JSON 数组）。响应已开始写出时以 HTTP Trailer 发送
+	attemptTraceHeader = "X-CCLoad-Trace"
+)
+
+// attemptTraceEntry 单次上游尝试（或跳过的渠道）的决策记录
+type attemptTraceEntry struct {
+	Attempt     int    `json:"attempt,omitempty"` // 第几次上游尝试；跳过的渠道为 0
+	ChannelID   int64  `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	KeyIndex    *int   `json:"key_index,omitempty"`
+	BaseURL     string `json:"base_url,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+	Decision    string `json:"decision"` // success | retry_key | retry_model | retry_channel | return_client | skipped: <原因>
+}
+
+// attemptTrace 单个代理请求的尝试链路；nil 表示未开启追踪（所有方法 nil 安全）
+// 尝试循环是串行的，无需加锁
+type attemptTrace struct {
+	entries []attemptTraceEntry
+	flushed bool
+}
+
+// debugTraceRequested 调用方是否请求并有权查看尝试链路
+func (s *Server) debugTraceRequested(c *gin.Context) bool {
+	if !util.ParseBoolDefault(c.GetHeader(debugTraceHeader), false) || s.authService == nil {
+		return false
+	}
+	return s.authService.IsAdminSession(c.GetHeader(debugAdminTokenHeader))
+}
+
+// recordAttempt 记录一次上游尝试及其后续决策
+func (t *attemptTrace) recordAttempt(reqCtx *proxyRequestContext, cfg *model.Config, keyIndex int, result *proxyResult, action cooldown.Action) {
+	if t == nil {
+		return
+	}
+	entry := attemptTraceEntry{
+		Attempt:     reqCtx.attemptNumber,
+		ChannelID:   cfg.ID,
+		ChannelName: cfg.Name,
+		KeyIndex:    &keyIndex,
+		BaseURL:     reqCtx.baseURL,
+		DurationMs:  time.Since(reqCtx.attemptStartTime).Milliseconds(),
+		Decision:    attemptDecision(action),
+	}
+	if result != nil {
+		entry.Status = result.status
+		if result.succeeded {
+			entry.Decision = "success"
+		}
+	}
+	t.entries = append(t.entries, entry)
+}
+
+// recordSkip 记录未发起上游请求即跳过的渠道（Key 全部冷却、RPM/并发限制等）
+func (t *attemptTrace) recordSkip(cfg *model.Config, reason error) {
+	if t == nil {
+		return
+	}
+	t.entries = append(t.entries, attemptTraceEntry{
+		ChannelID:   cfg.ID,
+		ChannelName: cfg.Name,
+		Decision:    "skipped: " + reason.Error(),
+	})
+}
+
+// flush 将尝试链路写入响应：未写出时作为响应头，已写出时作为 Trailer（仅写一次）
+func (t *attemptTrace) flush(w gin.ResponseWriter) {
+	if t == nil || t.flushed {
+		return
+	}
+	t.flushed = true
+	entries := t.entries
+	if entries == nil {
+		entries = []attemptTraceEntry{}
+	}
+	data, err := sonic.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if w.Written() {
+		w.Header().Set(http.TrailerPrefix+attemptTraceHeader, string(data))
+		return
+	}
+	w.Header().Set(attemptTraceHeader, string(data))
+}
+
+func attemptDecision(action cooldown.Action) string {
+	switch action {
+	case cooldown.ActionRetryKey:
+		return "retry_key"
+	case cooldown.ActionRetryModel:
+		return "retry_model"
+	case cooldown.ActionRetryChannel:
+		return "retry_channel"
+	default:
+		return "return_client"
+	}
+}
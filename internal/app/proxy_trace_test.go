@@ -0,0 +1,125 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func injectAdminWebSession(svc *AuthService, token string) {
+	tokenHash := model.HashToken(token)
+	svc.tokensMux.Lock()
+	svc.validTokens[tokenHash] = model.WebSession{TokenHash: tokenHash, Role: model.WebRoleAdmin, ExpiresAt: time.Now().Add(time.Hour)}
+	svc.tokensMux.Unlock()
+}
+
+func TestProxy_AttemptTrace_AdminDebugExplainsFailover(t *testing.T) {
+	t.Parallel()
+
+	var sawDebugHeaders atomic.Bool
+	failing := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(debugTraceHeader) != "" || r.Header.Get(debugAdminTokenHeader) != "" {
+			sawDebugHeaders.Store(true)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":"boom"}`))
+	}))
+	defer failing.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "primary", models: "gpt-4", apiKey: "sk-1"},
+		{name: "secondary", models: "gpt-4", apiKey: "sk-2"},
+	}, map[int]string{0: failing.URL, 1: failing.URL})
+	injectAdminWebSession(env.server.authService, "admin-session")
+
+	body := map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{
+		debugTraceHeader:      "true",
+		debugAdminTokenHeader: "admin-session",
+	})
+	if w.Code < http.StatusInternalServerError {
+		t.Fatalf("expected upstream failure status, got %d", w.Code)
+	}
+	if sawDebugHeaders.Load() {
+		t.Fatal("debug headers must not be forwarded upstream")
+	}
+
+	raw := w.Header().Get(attemptTraceHeader)
+	if raw == "" {
+		t.Fatal("expected attempt trace header on failed request")
+	}
+	var entries []attemptTraceEntry
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		t.Fatalf("unmarshal trace: %v (%s)", err, raw)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 attempts, got %+v", entries)
+	}
+	if entries[0].ChannelName != "primary" || entries[0].Attempt != 1 || entries[0].Status != http.StatusInternalServerError ||
+		entries[0].KeyIndex == nil || *entries[0].KeyIndex != 0 || !strings.HasPrefix(entries[0].Decision, "retry_") {
+		t.Fatalf("unexpected first attempt: %+v", entries[0])
+	}
+	if entries[1].ChannelName != "secondary" || entries[1].Attempt != 2 {
+		t.Fatalf("unexpected second attempt: %+v", entries[1])
+	}
+}
+
+func TestProxy_AttemptTrace_SuccessUsesTrailerAndRequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","choices":[]}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "ok", models: "gpt-4", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+	injectAdminWebSession(env.server.authService, "admin-session")
+
+	body := map[string]any{
+		"model":    "gpt-4",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", body, map[string]string{
+		debugTraceHeader:      "true",
+		debugAdminTokenHeader: "admin-session",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	raw := w.Result().Trailer.Get(attemptTraceHeader)
+	if raw == "" {
+		t.Fatal("expected attempt trace trailer on successful request")
+	}
+	var entries []attemptTraceEntry
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		t.Fatalf("unmarshal trace: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Decision != "success" || entries[0].Status != http.StatusOK {
+		t.Fatalf("unexpected trace: %+v", entries)
+	}
+
+	// 非管理员（无会话或会话无效）不返回链路
+	for _, headers := range []map[string]string{
+		{debugTraceHeader: "true"},
+		{debugTraceHeader: "true", debugAdminTokenHeader: "not-a-session"},
+	} {
+		w = doProxyRequest(t, env.engine, "/v1/chat/completions", body, headers)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+		}
+		if w.Header().Get(attemptTraceHeader) != "" || w.Result().Trailer.Get(attemptTraceHeader) != "" {
+			t.Fatalf("trace must not be returned without an admin session (headers=%v)", headers)
+		}
+	}
+}
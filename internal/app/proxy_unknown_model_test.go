@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestProxy_UnknownModelReturns404VsUnavailable503(t *testing.T) {
+	t.Parallel()
+
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "active", models: "gpt-4o,gpt-4o-mini", apiKey: "sk-1"},
+	}, map[int]string{0: upstream.URL})
+	if _, err := env.store.CreateConfig(context.Background(), &model.Config{
+		Name:         "paused",
+		URL:          upstream.URL,
+		ChannelType:  "openai",
+		Priority:     10,
+		Enabled:      false,
+		ModelEntries: []model.ModelEntry{{Model: "paused-model"}},
+	}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+
+	w := doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "missing-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown model: status=%d, want 404: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "gpt-4o, gpt-4o-mini") || strings.Contains(body, "paused-model") {
+		t.Fatalf("404 body should list enabled models only: %s", body)
+	}
+
+	// 模型已配置但渠道不可用（此处为禁用）→ 仍按临时不可用返回 503
+	w = doProxyRequest(t, env.engine, "/v1/chat/completions", map[string]any{
+		"model":    "paused-model",
+		"messages": []map[string]string{{"role": "user", "content": "hi"}},
+	}, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("configured model: status=%d, want 503: %s", w.Code, w.Body.String())
+	}
+}
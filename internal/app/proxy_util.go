@@ -6,12 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	neturl "net/url"
+	"os"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"ccLoad/internal/config"
 	"ccLoad/internal/cooldown"
 	"ccLoad/internal/model"
 	"ccLoad/internal/protocol"
@@ -150,9 +155,16 @@ type proxyRequestContext struct {
 	startTime        time.Time            // 请求开始时间（用于统计）
 	channelStartTime time.Time            // 当前渠道尝试开始时间（每次切换渠道时重置）
 	attemptStartTime time.Time            // 渠道内单次 Key/URL 尝试开始时间
+	attemptNumber    int                  // 本请求第几次上游尝试（跨渠道/Key/URL 累计，从1开始）
+	channelAttempt   int                  // 本请求第几个渠道（从1开始）
 	baseURL          string               // 当前尝试使用的上游URL（多URL场景）
 	debugData        *model.DebugLogEntry // Debug日志数据（debug开启时填充）
 	thinkingEffort   string
+	requestID        string        // 请求ID（X-Request-Id，透传上游并写入日志）
+	keyStrategy      string        // 请求级 Key 策略覆盖（X-CCLoad-Key-Strategy，空=沿用渠道配置）
+	trace            *attemptTrace // 尝试链路追踪（X-CCLoad-Debug + 管理员会话时非 nil）
+
+	firstByteTimeoutCfg *model.Config // 首个首字节超时的渠道（非流式兜底的重试目标）
 }
 
 // proxyResult 代理请求结果
@@ -175,40 +187,84 @@ type proxyResult struct {
 // 请求检测工具函数
 // ============================================================================
 
+// getStreamingPathPatterns 延迟解析 CCLOAD_STREAMING_PATHS（逗号分隔，支持 path.Match 通配符，默认空）。
+// 命中的路径一律按流式请求处理（首字节超时、SSE usage 解析），用于语义非标准的流式端点。
+var getStreamingPathPatterns = sync.OnceValue(func() []string {
+	raw := strings.TrimSpace(os.Getenv("CCLOAD_STREAMING_PATHS"))
+	if raw == "" {
+		return nil
+	}
+	var patterns []string
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if _, err := path.Match(part, ""); err != nil || !strings.HasPrefix(part, "/") {
+			log.Printf("[WARN] 忽略无效的 CCLOAD_STREAMING_PATHS 项: %q（必须以 / 开头的合法通配模式）", part)
+			continue
+		}
+		patterns = append(patterns, part)
+	}
+	return patterns
+})
+
+// matchStreamingPath 路径是否命中 CCLOAD_STREAMING_PATHS
+func matchStreamingPath(requestPath string) bool {
+	for _, pattern := range getStreamingPathPatterns() {
+		if ok, _ := path.Match(pattern, requestPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // isStreamingRequest 检测是否为流式请求
 // 支持多种API的流式标识方式：
 // - Gemini: 路径包含 :streamGenerateContent
-// - Claude/OpenAI: 请求体中 stream=true
-func isStreamingRequest(path string, body []byte) bool {
+// - CCLOAD_STREAMING_PATHS 配置的路径
+// - Claude/OpenAI: 请求体中 stream=true，或 stream_options.include_usage=true（仅流式请求有效）
+func isStreamingRequest(requestPath string, body []byte) bool {
 	// Gemini流式请求特征：路径包含 :streamGenerateContent
-	if strings.Contains(path, ":streamGenerateContent") {
+	if strings.Contains(requestPath, ":streamGenerateContent") {
+		return true
+	}
+	if matchStreamingPath(requestPath) {
 		return true
 	}
 
-	// 快速短路：body 不含 "stream" 字段时直接返回 false，
+	// 快速短路：body 不含 "stream 前缀字段（stream / stream_options）时直接返回 false，
 	// 避免 Gemini :generateContent 等非 chat 请求的全量 Unmarshal。
 	// 误判（user content 含 "stream" 子串）只会进入慢路径，最终结果仍正确。
-	if !bytes.Contains(body, []byte(`"stream"`)) {
+	if !bytes.Contains(body, []byte(`"stream`)) {
 		return false
 	}
 
-	// Claude/OpenAI流式请求特征：请求体中 stream=true
+	// Claude/OpenAI流式请求特征：请求体中 stream=true；显式 stream=false 优先于 stream_options
 	var reqModel struct {
-		Stream util.FlexibleBool `json:"stream"`
+		Stream        *util.FlexibleBool `json:"stream"`
+		StreamOptions *struct {
+			IncludeUsage util.FlexibleBool `json:"include_usage"`
+		} `json:"stream_options"`
 	}
 	_ = sonic.Unmarshal(body, &reqModel)
-	return reqModel.Stream.Bool()
+	if reqModel.Stream != nil {
+		return reqModel.Stream.Bool()
+	}
+	return reqModel.StreamOptions != nil && reqModel.StreamOptions.IncludeUsage.Bool()
 }
 
 // ============================================================================
 // URL和请求构建工具函数
 // ============================================================================
 
-// buildUpstreamURL 构建上游完整URL（KISS）
+// buildUpstreamURL 构建上游完整URL
+// 基础URL可带路径前缀（如 https://host/proxy）；拼接时去除多余斜杠，
+// 基础URL自带的查询参数（精确URL模式常见，如 ?api-version=...）与客户端查询参数合并。
 func buildUpstreamURL(baseURL string, requestPath, rawQuery string) string {
-	upstreamURL := model.StripExactUpstreamURLMarker(baseURL)
+	upstreamURL, baseQuery := splitUpstreamBaseURL(model.StripExactUpstreamURLMarker(baseURL))
 	if !model.HasExactUpstreamURLMarker(baseURL) {
-		upstreamURL = strings.TrimRight(upstreamURL, "/") + requestPath
+		upstreamURL = joinUpstreamPath(upstreamURL, requestPath)
 	}
 
 	// 移除 key 参数（Gemini API 认证格式），避免泄露到上游
@@ -219,12 +275,38 @@ func buildUpstreamURL(baseURL string, requestPath, rawQuery string) string {
 		}
 	}
 
-	if rawQuery != "" {
+	switch {
+	case baseQuery != "" && rawQuery != "":
+		upstreamURL += "?" + baseQuery + "&" + rawQuery
+	case baseQuery != "":
+		upstreamURL += "?" + baseQuery
+	case rawQuery != "":
 		upstreamURL += "?" + rawQuery
 	}
 	return upstreamURL
 }
 
+// splitUpstreamBaseURL 拆出基础URL的查询串（丢弃片段），返回不含查询的部分
+func splitUpstreamBaseURL(raw string) (base, query string) {
+	if i := strings.IndexByte(raw, '#'); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '?'); i >= 0 {
+		return raw[:i], raw[i+1:]
+	}
+	return raw, ""
+}
+
+// joinUpstreamPath 拼接基础URL与请求路径，保证两者之间恰好一个斜杠
+func joinUpstreamPath(base, requestPath string) string {
+	base = strings.TrimRight(base, "/")
+	requestPath = strings.TrimLeft(requestPath, "/")
+	if requestPath == "" {
+		return base
+	}
+	return base + "/" + requestPath
+}
+
 // buildUpstreamRequest 创建带上下文的HTTP请求
 func buildUpstreamRequest(ctx context.Context, method, upstreamURL string, body []byte) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -289,8 +371,8 @@ func shouldSkipHopByHopHeader(headerName string, connTokens map[string]struct{})
 	return false
 }
 
-// copyRequestHeaders 复制请求头，跳过认证相关（DRY）
-func copyRequestHeaders(dst *http.Request, src http.Header) {
+// copyRequestHeaders 复制请求头，跳过认证相关（DRY）；cfg 非 nil 时额外跳过渠道 strip_headers 命中的头
+func copyRequestHeaders(dst *http.Request, src http.Header, cfg *model.Config) {
 	connTokens := connectionHeaderTokens(src)
 	for k, vs := range src {
 		// 剥离 hop-by-hop headers（以及 Connection 显式声明的 hop-by-hop 字段）
@@ -301,14 +383,24 @@ func copyRequestHeaders(dst *http.Request, src http.Header) {
 		// 不透传认证头（由上游注入）
 		if strings.EqualFold(k, "Authorization") ||
 			strings.EqualFold(k, "X-Api-Key") ||
+			strings.EqualFold(k, "Api-Key") ||
 			strings.EqualFold(k, "x-goog-api-key") {
 			continue
 		}
+		// 渠道固定头、Key 策略覆盖头、Always-200 兼容头与调试追踪头仅供本服务使用
+		if strings.EqualFold(k, channelPinHeader) || strings.EqualFold(k, keyStrategyHeader) || strings.EqualFold(k, always200Header) ||
+			strings.EqualFold(k, priorityHeader) || strings.EqualFold(k, debugTraceHeader) || strings.EqualFold(k, debugAdminTokenHeader) {
+			continue
+		}
 		// 不透传 Accept-Encoding，避免上游返回 br/gzip 压缩导致错误体乱码
 		// 让 Go Transport 自动设置并透明解压 gzip（DisableCompression=false）
 		if strings.EqualFold(k, "Accept-Encoding") {
 			continue
 		}
+		// 渠道级额外剥离（如 Cookie、内部链路追踪头），避免泄露给外部上游
+		if cfg != nil && cfg.StripsHeader(k) {
+			continue
+		}
 		for _, v := range vs {
 			dst.Header.Add(k, v)
 		}
@@ -458,6 +550,39 @@ func anthropicBudgetToEffort(budget int) string {
 	}
 }
 
+// getResponseHeaderAllowlist 延迟解析 CCLOAD_RESPONSE_HEADER_ALLOWLIST（逗号分隔，"*" 结尾表示前缀匹配，默认空）。
+// 配置后仅白名单内的上游响应头透传给客户端（Content-Type/Content-Encoding 始终保留），避免泄露上游内部元数据；
+// 未配置时保持现有行为：除 hop-by-hop 等头外全部透传。
+var getResponseHeaderAllowlist = sync.OnceValue(func() []string {
+	raw := strings.TrimSpace(os.Getenv("CCLOAD_RESPONSE_HEADER_ALLOWLIST"))
+	if raw == "" {
+		return nil
+	}
+	var patterns []string
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "*" {
+			continue
+		}
+		patterns = append(patterns, strings.ToLower(part))
+	}
+	return patterns
+})
+
+// responseHeaderAllowed 响应头是否允许透传（未配置白名单时全部允许）
+func responseHeaderAllowed(name string) bool {
+	allowlist := getResponseHeaderAllowlist()
+	if allowlist == nil || strings.EqualFold(name, "Content-Type") || strings.EqualFold(name, "Content-Encoding") {
+		return true
+	}
+	for _, pattern := range allowlist {
+		if util.MatchPrefixPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // filterAndWriteResponseHeaders 过滤并写回响应头（DRY）
 // Go Transport 仅自动解压 gzip（当 DisableCompression=false 且请求无 Accept-Encoding 时）
 // 对于 br/deflate 等其他编码，必须保留 Content-Encoding 让客户端自行解压
@@ -481,6 +606,13 @@ func filterAndWriteResponseHeaders(w http.ResponseWriter, hdr http.Header) {
 		if strings.EqualFold(k, "Content-Encoding") && skipContentEncoding {
 			continue
 		}
+		// 已回显本服务的请求ID时，不再追加上游的同名头（避免出现多个值）
+		if strings.EqualFold(k, requestIDHeader) && w.Header().Get(requestIDHeader) != "" {
+			continue
+		}
+		if !responseHeaderAllowed(k) {
+			continue
+		}
 		for _, v := range vs {
 			w.Header().Add(k, v)
 		}
@@ -606,23 +738,49 @@ func (s *Server) prepareRequestBody(cfg *model.Config, reqCtx *proxyRequestConte
 	bodyToSend = reqCtx.body
 
 	// 如果模型发生变更，修改请求体
-	if actualModel != reqCtx.originalModel {
-		var reqData map[string]json.RawMessage
-		if err := sonic.Unmarshal(reqCtx.body, &reqData); err == nil {
-			modelRaw, err := sonic.Marshal(actualModel)
-			if err != nil {
-				return actualModel, bodyToSend
-			}
-			reqData["model"] = modelRaw
-			if modifiedBody, err := sonic.Marshal(reqData); err == nil {
-				bodyToSend = modifiedBody
-			}
+	if upstreamModel := upstreamRequestModel(cfg, reqCtx.originalModel, actualModel); upstreamModel != reqCtx.originalModel {
+		if modifiedBody, ok := replaceModelInBody(reqCtx.body, upstreamModel); ok {
+			bodyToSend = modifiedBody
 		}
 	}
 
+	// 渠道输出上限：封顶/注入 max_tokens 等字段
+	if modifiedBody, ok := capMaxOutputTokens(bodyToSend, reqCtx.clientProtocol, reqCtx.requestPath, cfg.MaxOutputTokens); ok {
+		bodyToSend = modifiedBody
+	}
+
 	return actualModel, bodyToSend
 }
 
+// upstreamRequestModel 返回写入上游请求（body / URL 路径 / 协议转换）的模型名。
+// redirect_routing_only 渠道保留客户端原始模型名（custom_request_rules 仍会在发送前生效），
+// actualModel 仍用于日志、计费与模型冷却。
+func upstreamRequestModel(cfg *model.Config, originalModel, actualModel string) string {
+	if cfg != nil && cfg.RedirectRoutingOnly {
+		return originalModel
+	}
+	return actualModel
+}
+
+// replaceModelInBody 替换 JSON 请求体的 model 字段（其余字段保留 RawMessage）
+// 请求体不是 JSON 对象时返回 false
+func replaceModelInBody(body []byte, modelName string) ([]byte, bool) {
+	var reqData map[string]json.RawMessage
+	if err := sonic.Unmarshal(body, &reqData); err != nil || reqData == nil {
+		return nil, false
+	}
+	modelRaw, err := sonic.Marshal(modelName)
+	if err != nil {
+		return nil, false
+	}
+	reqData["model"] = modelRaw
+	modifiedBody, err := sonic.Marshal(reqData)
+	if err != nil {
+		return nil, false
+	}
+	return modifiedBody, true
+}
+
 // stripAnthropicBillingHeaders 从 Anthropic /v1/messages 请求体的 system 数组中
 // 移除固定注入格式的 x-anthropic-billing-header 条目（上游计费元数据，不应转发）
 // 注意：仅解析/重建 system 字段，其他字段保留 RawMessage，避免大整数精度丢失。
@@ -839,6 +997,9 @@ type logEntryParams struct {
 	DebugData      *model.DebugLogEntry // Debug日志数据
 	CostMultiplier float64              // 渠道成本倍率快照（0=免费，<0 视为 1）
 	ThinkingEffort string
+	RequestID      string
+	AttemptNumber  int // 第几次上游尝试（1=首次）
+	ChannelAttempt int // 第几个渠道（1=首选渠道）
 }
 
 // resolveProxyBillingModel 选择代理请求的计费模型。
@@ -850,6 +1011,25 @@ func resolveProxyBillingModel(requestPath, actualModel, requestModel string) str
 	return util.ResolveBillingModel(actualModel, requestModel)
 }
 
+// getSlowRequestThresholdMs 延迟解析 CCLOAD_SLOW_REQUEST_MS（毫秒，默认 0 表示不标记慢请求）
+var getSlowRequestThresholdMs = sync.OnceValue(func() int64 {
+	raw := strings.TrimSpace(os.Getenv("CCLOAD_SLOW_REQUEST_MS"))
+	if raw == "" {
+		return 0
+	}
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || ms < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_SLOW_REQUEST_MS=%q（必须为非负整数毫秒），不标记慢请求", raw)
+		return 0
+	}
+	return ms
+})
+
+// isSlowDuration 判断总耗时（秒）是否超过慢请求阈值（毫秒）；阈值 <= 0 表示关闭
+func isSlowDuration(durationSec float64, thresholdMs int64) bool {
+	return thresholdMs > 0 && durationSec*1000 > float64(thresholdMs)
+}
+
 // buildLogEntry 构建日志条目（消除重复代码，遵循DRY原则）
 func buildLogEntry(p logEntryParams) *model.LogEntry {
 	logTime := p.StartTime
@@ -874,7 +1054,13 @@ func buildLogEntry(p logEntryParams) *model.LogEntry {
 		AuthTokenID: p.AuthTokenID,
 		ClientIP:    p.ClientIP,
 		BaseURL:     p.BaseURL,
+		RequestID:   p.RequestID,
+		Path:        truncateLogPath(p.RequestPath),
+
+		AttemptNumber:  p.AttemptNumber,
+		ChannelAttempt: p.ChannelAttempt,
 	}
+	entry.IsSlow = isSlowDuration(p.Duration, getSlowRequestThresholdMs())
 	entry.ThinkingEffort = normalizeThinkingEffort(p.ThinkingEffort)
 
 	// 成本倍率快照：0 表示免费渠道；负数兜底为 1（保护存量数据）
@@ -894,6 +1080,9 @@ func buildLogEntry(p logEntryParams) *model.LogEntry {
 		// 场景：流式请求中途取消，此时已有 FirstByteTime 和 BytesReceived
 		// 将字节数追加到 message 中便于诊断
 		msg := truncateErr(p.ErrMsg)
+		if !isSuccessLogStatus(p.StatusCode) {
+			entry.ErrorDetail = errorDetailIfTruncated(p.ErrMsg)
+		}
 		if p.Result != nil && p.IsStreaming {
 			if p.Result.FirstByteTime > 0 {
 				entry.FirstByteTime = p.Result.FirstByteTime
@@ -915,14 +1104,19 @@ func buildLogEntry(p logEntryParams) *model.LogEntry {
 			entry.Message = appendRetryStrategyToMessage(entry.Message, res.RetryStrategy)
 		} else {
 			msg := fmt.Sprintf("upstream status %d", p.StatusCode)
+			fullMsg := msg
 			// 诊断信息优先：body 已存于 fwResult.Body 可随时查阅，但 diag 仅记录在 Message
 			if res.StreamDiagMsg != "" {
 				msg = fmt.Sprintf("%s [%s]", msg, truncateErr(res.StreamDiagMsg))
+				fullMsg = fmt.Sprintf("%s [%s]", fullMsg, res.StreamDiagMsg)
 			}
 			if len(res.Body) > 0 {
-				msg = fmt.Sprintf("%s: %s", msg, truncateErr(safeBodyToString(res.Body)))
+				body := safeBodyToString(res.Body)
+				msg = fmt.Sprintf("%s: %s", msg, truncateErr(body))
+				fullMsg = fmt.Sprintf("%s: %s", fullMsg, body)
 			}
 			entry.Message = truncateErr(msg)
+			entry.ErrorDetail = errorDetailIfTruncated(fullMsg)
 		}
 
 		// 流式请求记录首字节响应时间
@@ -994,16 +1188,73 @@ func computeRequestCost(model string, serviceTier string, res *fwResult) float64
 	) * util.OpenAIServiceTierMultiplier(model, serviceTier)
 }
 
-// truncateErr 截断错误信息到512字符（防止日志过长）
+const (
+	maxErrorDetailLen = config.MaxLogMessageMaxLen // 错误日志 error_detail 截断长度
+	maxLogPathLen     = 512                        // 日志 path 截断长度（与 logs.path 列宽一致）
+)
+
+// getLogMessageMaxLen 延迟解析 CCLOAD_LOG_MSG_MAX_LEN（日志 message 截断长度，默认512）。
+// 与 getHostOverrides 相同：必须等 .env 加载后再读取环境变量。
+var getLogMessageMaxLen = sync.OnceValue(func() int {
+	raw := os.Getenv("CCLOAD_LOG_MSG_MAX_LEN")
+	if raw == "" {
+		return config.DefaultLogMessageMaxLen
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < config.MinLogMessageMaxLen || n > config.MaxLogMessageMaxLen {
+		log.Printf("[WARN] 无效的 CCLOAD_LOG_MSG_MAX_LEN=%s（必须为 %d-%d 的整数），使用默认值 %d",
+			raw, config.MinLogMessageMaxLen, config.MaxLogMessageMaxLen, config.DefaultLogMessageMaxLen)
+		return config.DefaultLogMessageMaxLen
+	}
+	return n
+})
+
+// truncateLogPath 截断请求路径，避免超长路径写入失败
+func truncateLogPath(s string) string {
+	if len(s) > maxLogPathLen {
+		return s[:maxLogPathLen]
+	}
+	return s
+}
+
+// truncateErr 截断错误信息到 CCLOAD_LOG_MSG_MAX_LEN（默认512字符，防止日志过长）
 func truncateErr(s string) string {
-	const maxLen = 512
 	s = strings.TrimSpace(s)
-	if len(s) > maxLen {
+	if maxLen := getLogMessageMaxLen(); len(s) > maxLen {
 		return s[:maxLen]
 	}
 	return s
 }
 
+// truncateLogEntryMessage 入库前统一截断 message（单条 AddLog 与批量 BatchAddLogs 两条路径共用）。
+// 非 2xx 日志被截断且尚无 error_detail 时保留更长版本，与 buildLogEntry 行为一致；成功日志只截断。
+func truncateLogEntryMessage(entry *model.LogEntry) {
+	if entry == nil || len(entry.Message) <= getLogMessageMaxLen() {
+		return
+	}
+	if entry.ErrorDetail == "" && !isSuccessLogStatus(entry.StatusCode) {
+		entry.ErrorDetail = errorDetailIfTruncated(entry.Message)
+	}
+	entry.Message = truncateErr(entry.Message)
+}
+
+// isSuccessLogStatus 2xx 日志不保留 error_detail
+func isSuccessLogStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// errorDetailIfTruncated 错误信息超过 truncateErr 上限时保留更长版本（最多8KB），否则返回空串
+func errorDetailIfTruncated(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= getLogMessageMaxLen() {
+		return ""
+	}
+	if len(s) > maxErrorDetailLen {
+		return s[:maxErrorDetailLen]
+	}
+	return s
+}
+
 // formatBytes 格式化字节数为人类可读的格式（KB/MB）
 func formatBytes(b int64) string {
 	const (
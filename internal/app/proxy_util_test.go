@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -231,6 +232,32 @@ func TestExtractThinkingEffortReadsCodexReasoningEffort(t *testing.T) {
 	}
 }
 
+func TestBuildLogEntry_ErrorDetailKeepsUntruncatedError(t *testing.T) {
+	body := `{"error":"` + strings.Repeat("e", 1500) + `"}`
+	entry := buildLogEntry(logEntryParams{
+		RequestModel: "gpt-4",
+		ChannelID:    1,
+		StatusCode:   502,
+		Result:       &fwResult{Status: 502, Body: []byte(body)},
+	})
+	if len(entry.Message) != getLogMessageMaxLen() {
+		t.Fatalf("message len=%d, want %d", len(entry.Message), getLogMessageMaxLen())
+	}
+	if !strings.HasSuffix(entry.ErrorDetail, body) {
+		t.Fatalf("error_detail should contain the full body, got len=%d", len(entry.ErrorDetail))
+	}
+
+	short := buildLogEntry(logEntryParams{RequestModel: "gpt-4", ChannelID: 1, StatusCode: 502, ErrMsg: "boom"})
+	if short.ErrorDetail != "" {
+		t.Fatalf("short errors should not store error_detail, got %q", short.ErrorDetail)
+	}
+
+	ok := buildLogEntry(logEntryParams{RequestModel: "gpt-4", ChannelID: 1, StatusCode: 200, Result: &fwResult{Status: 200, StreamDiagMsg: strings.Repeat("d", 1000)}})
+	if ok.ErrorDetail != "" {
+		t.Fatal("successful requests should not store error_detail")
+	}
+}
+
 func TestBuildLogEntry_CopiesReasoningTokens(t *testing.T) {
 	t.Parallel()
 
@@ -249,6 +276,40 @@ func TestBuildLogEntry_CopiesReasoningTokens(t *testing.T) {
 	}
 }
 
+func TestBuildLogEntry_RecordsTruncatedRequestPath(t *testing.T) {
+	t.Parallel()
+
+	entry := buildLogEntry(logEntryParams{RequestModel: "gpt-4", RequestPath: "/v1/responses", StatusCode: http.StatusOK})
+	if entry.Path != "/v1/responses" {
+		t.Fatalf("path=%q, want /v1/responses", entry.Path)
+	}
+
+	long := buildLogEntry(logEntryParams{RequestModel: "gpt-4", RequestPath: "/" + strings.Repeat("p", 1000), StatusCode: http.StatusOK})
+	if len(long.Path) != maxLogPathLen {
+		t.Fatalf("path len=%d, want %d", len(long.Path), maxLogPathLen)
+	}
+}
+
+func TestIsSlowDuration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		seconds   float64
+		threshold int64
+		want      bool
+	}{
+		{seconds: 30, threshold: 0, want: false}, // 阈值 0 表示关闭
+		{seconds: 5, threshold: 5000, want: false},
+		{seconds: 5.001, threshold: 5000, want: true},
+		{seconds: 0.2, threshold: 100, want: true},
+	}
+	for _, tc := range cases {
+		if got := isSlowDuration(tc.seconds, tc.threshold); got != tc.want {
+			t.Errorf("isSlowDuration(%v, %d) = %v, want %v", tc.seconds, tc.threshold, got, tc.want)
+		}
+	}
+}
+
 func TestComputeRequestCost_ServiceTierAppliesOnlyAsOpenAIPriceMultiplier(t *testing.T) {
 	t.Parallel()
 
@@ -336,7 +397,7 @@ func TestCopyRequestHeaders_StripsHopByHopAndAuth(t *testing.T) {
 	src.Set("Accept-Encoding", "br")
 	src.Set("X-Pass", "ok")
 
-	copyRequestHeaders(req, src)
+	copyRequestHeaders(req, src, nil)
 
 	if got := req.Header.Get("X-Pass"); got != "ok" {
 		t.Fatalf("expected X-Pass=ok, got %q", got)
@@ -364,6 +425,36 @@ func TestCopyRequestHeaders_StripsHopByHopAndAuth(t *testing.T) {
 	}
 }
 
+func TestCopyRequestHeaders_ChannelStripHeaders(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := http.Header{}
+	src.Set("Cookie", "session=1")
+	src.Set("X-Internal-Trace", "abc")
+	src.Set("X-Internal-Span", "def")
+	src.Set("X-Internals", "kept-by-prefix-boundary")
+	src.Set("Anthropic-Version", "2023-06-01")
+	src.Set("Authorization", "Bearer client-token")
+
+	cfg := &model.Config{StripHeaders: []string{"cookie", "X-Internal-*"}}
+	copyRequestHeaders(req, src, cfg)
+
+	for _, k := range []string{"Cookie", "X-Internal-Trace", "X-Internal-Span", "Authorization"} {
+		if v := req.Header.Get(k); v != "" {
+			t.Fatalf("expected header %q stripped, got %q", k, v)
+		}
+	}
+	if got := req.Header.Get("Anthropic-Version"); got != "2023-06-01" {
+		t.Fatalf("expected Anthropic-Version passthrough, got %q", got)
+	}
+	if got := req.Header.Get("X-Internals"); got == "" {
+		t.Fatal("expected X-Internals to pass (prefix is X-Internal-)")
+	}
+}
+
 func TestFilterAndWriteResponseHeaders_StripsHopByHop(t *testing.T) {
 	w := newRecorder()
 
@@ -400,6 +491,36 @@ func TestFilterAndWriteResponseHeaders_StripsHopByHop(t *testing.T) {
 	}
 }
 
+func TestFilterAndWriteResponseHeaders_Allowlist(t *testing.T) {
+	orig := getResponseHeaderAllowlist
+	getResponseHeaderAllowlist = func() []string { return []string{"x-request-id", "anthropic-ratelimit-*"} }
+	t.Cleanup(func() { getResponseHeaderAllowlist = orig })
+
+	w := newRecorder()
+	hdr := http.Header{}
+	hdr.Set("Content-Type", "text/event-stream")
+	hdr.Set("Content-Encoding", "br")
+	hdr.Set("X-Request-Id", "req-1")
+	hdr.Set("Anthropic-Ratelimit-Requests-Remaining", "99")
+	hdr.Set("X-Internal-Route", "pool-b")
+	hdr.Set("Openai-Organization", "org-secret")
+
+	filterAndWriteResponseHeaders(w, hdr)
+
+	for k, want := range map[string]string{
+		"Content-Type":                           "text/event-stream",
+		"Content-Encoding":                       "br",
+		"X-Request-Id":                           "req-1",
+		"Anthropic-Ratelimit-Requests-Remaining": "99",
+		"X-Internal-Route":                       "",
+		"Openai-Organization":                    "",
+	} {
+		if got := w.Header().Get(k); got != want {
+			t.Fatalf("header %q = %q, want %q", k, got, want)
+		}
+	}
+}
+
 func TestSafeBodyToString(t *testing.T) {
 	t.Parallel()
 
@@ -711,6 +832,33 @@ func TestPrepareRequestBody_PreservesLargeIntegersOnModelRewrite(t *testing.T) {
 	}
 }
 
+func TestPrepareRequestBody_RedirectRoutingOnlyKeepsOriginalModel(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{}
+	cfg := &model.Config{
+		RedirectRoutingOnly: true,
+		ModelEntries: []model.ModelEntry{
+			{Model: "claude-alias", RedirectModel: "claude-sonnet-4-5"},
+		},
+	}
+	reqCtx := &proxyRequestContext{
+		originalModel: "claude-alias",
+		body:          []byte(`{"model":"claude-alias","messages":[]}`),
+	}
+
+	actualModel, bodyToSend := s.prepareRequestBody(cfg, reqCtx)
+	if actualModel != "claude-sonnet-4-5" {
+		t.Fatalf("actualModel = %q, want redirect target for logging", actualModel)
+	}
+	if !bytes.Equal(bodyToSend, reqCtx.body) {
+		t.Fatalf("body must not be rewritten, got %s", bodyToSend)
+	}
+	if got := upstreamRequestModel(cfg, reqCtx.originalModel, actualModel); got != "claude-alias" {
+		t.Fatalf("upstreamRequestModel = %q, want original", got)
+	}
+}
+
 func TestStripAnthropicBillingHeaders(t *testing.T) {
 	t.Parallel()
 
@@ -1031,3 +1179,94 @@ func TestNormalizeAnyrouterAdaptiveThinking(t *testing.T) {
 		}
 	})
 }
+
+func TestTruncateLogEntryMessage_UsesConfiguredMaxLen(t *testing.T) {
+	orig := getLogMessageMaxLen
+	getLogMessageMaxLen = func() int { return 64 }
+	t.Cleanup(func() { getLogMessageMaxLen = orig })
+
+	long := strings.Repeat("x", 100)
+	entry := &model.LogEntry{Message: long}
+	truncateLogEntryMessage(entry)
+	if len(entry.Message) != 64 {
+		t.Fatalf("message len=%d, want 64", len(entry.Message))
+	}
+	if entry.ErrorDetail != long {
+		t.Fatalf("error_detail should keep the untruncated message, got len=%d", len(entry.ErrorDetail))
+	}
+
+	success := &model.LogEntry{StatusCode: 200, Message: long}
+	truncateLogEntryMessage(success)
+	if len(success.Message) != 64 || success.ErrorDetail != "" {
+		t.Fatalf("2xx log should be truncated without error_detail, got len=%d detail_len=%d", len(success.Message), len(success.ErrorDetail))
+	}
+
+	short := &model.LogEntry{Message: "ok"}
+	truncateLogEntryMessage(short)
+	if short.Message != "ok" || short.ErrorDetail != "" {
+		t.Fatalf("short message should be untouched: %+v", short)
+	}
+}
+
+func TestIsStreamingRequest_StreamOptionsAndConfiguredPaths(t *testing.T) {
+	orig := getStreamingPathPatterns
+	getStreamingPathPatterns = func() []string { return []string{"/v1/responses", "/custom/*/events"} }
+	t.Cleanup(func() { getStreamingPathPatterns = orig })
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want bool
+	}{
+		{"stream true", "/v1/chat/completions", `{"stream":true}`, true},
+		{"stream false", "/v1/chat/completions", `{"stream":false}`, false},
+		{"no stream field", "/v1/chat/completions", `{"model":"gpt-4"}`, false},
+		{"include_usage without stream", "/v1/chat/completions", `{"stream_options":{"include_usage":true}}`, true},
+		{"explicit stream false wins", "/v1/chat/completions", `{"stream":false,"stream_options":{"include_usage":true}}`, false},
+		{"include_usage false", "/v1/chat/completions", `{"stream_options":{"include_usage":false}}`, false},
+		{"gemini stream path", "/v1beta/models/g:streamGenerateContent", `{}`, true},
+		{"configured exact path", "/v1/responses", `{"model":"gpt-5"}`, true},
+		{"configured glob path", "/custom/abc/events", `{}`, true},
+		{"glob does not cross segments", "/custom/a/b/events", `{}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStreamingRequest(tt.path, []byte(tt.body)); got != tt.want {
+				t.Fatalf("isStreamingRequest(%q, %s)=%v, want %v", tt.path, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUpstreamURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		base     string
+		path     string
+		rawQuery string
+		want     string
+	}{
+		{"根路径", "https://api.example.com", "/v1/chat/completions", "", "https://api.example.com/v1/chat/completions"},
+		{"根路径带尾斜杠", "https://api.example.com/", "/v1/chat/completions", "", "https://api.example.com/v1/chat/completions"},
+		{"路径前缀", "https://host/proxy", "/v1/chat/completions", "", "https://host/proxy/v1/chat/completions"},
+		{"路径前缀带多个尾斜杠", "https://host/proxy//", "/v1/messages", "", "https://host/proxy/v1/messages"},
+		{"请求路径无前导斜杠", "https://host/proxy", "v1/messages", "", "https://host/proxy/v1/messages"},
+		{"请求路径双斜杠", "https://host/proxy/", "//v1/messages", "", "https://host/proxy/v1/messages"},
+		{"空请求路径", "https://host/proxy/", "", "", "https://host/proxy"},
+		{"查询参数且移除key", "https://host/proxy", "/v1beta/models", "key=secret&alt=sse", "https://host/proxy/v1beta/models?alt=sse"},
+		{"基础URL查询参数合并", "https://host/proxy?tenant=a", "/v1/messages", "beta=true", "https://host/proxy/v1/messages?tenant=a&beta=true"},
+		{"精确URL保留路径", "https://host/openai/deployments/x/chat/completions?api-version=2024-06-01" + model.ExactUpstreamURLMarker, "/v1/chat/completions", "", "https://host/openai/deployments/x/chat/completions?api-version=2024-06-01"},
+		{"精确URL合并查询参数", "https://host/exact?api-version=1" + model.ExactUpstreamURLMarker, "/v1/chat/completions", "stream=true", "https://host/exact?api-version=1&stream=true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := buildUpstreamURL(tt.base, tt.path, tt.rawQuery); got != tt.want {
+				t.Fatalf("buildUpstreamURL(%q, %q, %q) = %q, want %q", tt.base, tt.path, tt.rawQuery, got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 // 补充首字节超时管控（可选）
 type requestContext struct {
 	ctx               context.Context
+	upstreamCtx       context.Context    // 上游请求使用的 context，nil 时沿用 ctx（断开后排空时不随客户端取消）
 	cancel            context.CancelFunc // [INFO] 总是非 nil（即使是 noop），调用方无需检查
 	startTime         time.Time
 	isStreaming       bool
@@ -20,6 +21,7 @@ type requestContext struct {
 	clientProtocol    protocol.Protocol
 	upstreamProtocol  protocol.Protocol
 	originalModel     string
+	restoreModel      bool // 响应中的 model 改写回 originalModel（渠道 restore_response_model 且发生重定向）
 	originalBody      []byte
 	translatedBody    []byte
 	firstByteTimeout  time.Duration
@@ -88,6 +90,14 @@ func (rc *requestContext) firstByteTimeoutTriggered() bool {
 	return rc.firstByteTimedOut.Load()
 }
 
+// upstreamContext 返回构建上游请求使用的 context
+func (rc *requestContext) upstreamContext() context.Context {
+	if rc.upstreamCtx != nil {
+		return rc.upstreamCtx
+	}
+	return rc.ctx
+}
+
 // Duration 返回从请求开始到现在的时间
 func (rc *requestContext) Duration() time.Duration {
 	return time.Since(rc.startTime)
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+const (
+	// responseCacheHeader 命中缓存时回写给客户端的标记头
+	responseCacheHeader = "X-CCLoad-Cache"
+
+	defaultResponseCacheTTL      = 5 * time.Minute
+	defaultResponseCacheMaxBytes = 64 << 20
+	// responseCacheMaxEntryBytes 单条响应上限，超过则不缓存（避免大响应挤占整个缓存）
+	responseCacheMaxEntryBytes = 4 << 20
+)
+
+// responseCacheEntry 缓存的上游成功响应（仅保留重放所需的头）
+type responseCacheEntry struct {
+	key         string
+	status      int
+	contentType string
+	body        []byte
+	expireAt    time.Time
+}
+
+// responseCache 渠道级上游响应缓存（内存 LRU + TTL + 总字节上限）
+// 仅用于 cacheable 渠道的非流式 POST 请求；nil 表示未启用
+type responseCache struct {
+	ttl      time.Duration
+	maxBytes int
+
+	mu    sync.Mutex
+	lru   *list.List // 前端为最近使用
+	items map[string]*list.Element
+	bytes int
+}
+
+func newResponseCache(ttl time.Duration, maxBytes int) *responseCache {
+	if ttl <= 0 || maxBytes <= 0 {
+		return nil
+	}
+	return &responseCache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// newResponseCacheFromEnv 解析 CCLOAD_RESPONSE_CACHE_TTL（秒，0=关闭）与 CCLOAD_RESPONSE_CACHE_MAX_MB
+func newResponseCacheFromEnv() *responseCache {
+	ttl := defaultResponseCacheTTL
+	if raw := os.Getenv("CCLOAD_RESPONSE_CACHE_TTL"); raw != "" {
+		if sec, err := strconv.Atoi(raw); err == nil && sec >= 0 {
+			ttl = time.Duration(sec) * time.Second
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_RESPONSE_CACHE_TTL=%s（必须为非负整数秒），使用默认值 %s", raw, ttl)
+		}
+	}
+	maxBytes := defaultResponseCacheMaxBytes
+	if raw := os.Getenv("CCLOAD_RESPONSE_CACHE_MAX_MB"); raw != "" {
+		if mb, err := strconv.Atoi(raw); err == nil && mb > 0 {
+			maxBytes = mb << 20
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_RESPONSE_CACHE_MAX_MB=%s（必须为正整数），使用默认值 %d", raw, maxBytes>>20)
+		}
+	}
+	return newResponseCache(ttl, maxBytes)
+}
+
+// responseCacheKey 缓存键：渠道 + 方法 + 路径/查询 + 模型 + 请求体的 SHA-256
+func responseCacheKey(channelID int64, reqCtx *proxyRequestContext) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatInt(channelID, 10)))
+	for _, part := range []string{reqCtx.requestMethod, reqCtx.requestPath, reqCtx.rawQuery, reqCtx.originalModel} {
+		h.Write([]byte{0})
+		h.Write([]byte(part))
+	}
+	h.Write([]byte{0})
+	h.Write(reqCtx.body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// eligible 是否对该渠道/请求启用响应缓存
+func (rc *responseCache) eligible(cfg *model.Config, reqCtx *proxyRequestContext) bool {
+	return rc != nil && cfg != nil && cfg.Cacheable &&
+		!reqCtx.isStreaming && reqCtx.requestMethod == http.MethodPost
+}
+
+// Get 查询未过期的缓存条目，命中时移到 LRU 前端
+func (rc *responseCache) Get(key string) (*responseCacheEntry, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	elem, ok := rc.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*responseCacheEntry)
+	if time.Now().After(entry.expireAt) {
+		rc.removeElement(elem)
+		return nil, false
+	}
+	rc.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// Set 写入缓存条目，超出总字节上限时从 LRU 尾部淘汰
+func (rc *responseCache) Set(key string, status int, contentType string, body []byte) {
+	if rc == nil || len(body) > responseCacheMaxEntryBytes || len(body) > rc.maxBytes {
+		return
+	}
+	entry := &responseCacheEntry{
+		key:         key,
+		status:      status,
+		contentType: contentType,
+		body:        body,
+		expireAt:    time.Now().Add(rc.ttl),
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.items[key]; ok {
+		rc.removeElement(elem)
+	}
+	rc.items[key] = rc.lru.PushFront(entry)
+	rc.bytes += len(body)
+	for rc.bytes > rc.maxBytes {
+		rc.removeElement(rc.lru.Back())
+	}
+}
+
+// Len 当前缓存条目数
+func (rc *responseCache) Len() int {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.lru.Len()
+}
+
+func (rc *responseCache) removeElement(elem *list.Element) {
+	entry := rc.lru.Remove(elem).(*responseCacheEntry)
+	delete(rc.items, entry.key)
+	rc.bytes -= len(entry.body)
+}
+
+// responseCaptureWriter 透传写入的同时捕获状态码与响应体（超过单条上限后停止捕获）
+type responseCaptureWriter struct {
+	http.ResponseWriter
+	status   int
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func (w *responseCaptureWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseCaptureWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if w.buf.Len()+len(p) > responseCacheMaxEntryBytes {
+			w.overflow = true
+			w.buf = bytes.Buffer{}
+		} else {
+			w.buf.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseCaptureWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接（写超时控制等）
+func (w *responseCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// cacheable 捕获结果是否可写入缓存：2xx、完整、未压缩
+func (w *responseCaptureWriter) cacheable() bool {
+	if w.overflow || w.status < 200 || w.status >= 300 || w.buf.Len() == 0 {
+		return false
+	}
+	enc := w.Header().Get("Content-Encoding")
+	return enc == "" || enc == "identity"
+}
+
+// serveCachedResponse 命中缓存时直接写回响应并记录一条区别于上游成功的日志
+func (s *Server) serveCachedResponse(w http.ResponseWriter, cfg *model.Config, reqCtx *proxyRequestContext, entry *responseCacheEntry) {
+	if entry.contentType != "" {
+		w.Header().Set("Content-Type", entry.contentType)
+	}
+	w.Header().Set(responseCacheHeader, "HIT")
+	w.WriteHeader(entry.status)
+	_, _ = w.Write(entry.body)
+
+	logEntry := buildLogEntry(logEntryParams{
+		RequestModel:   reqCtx.originalModel,
+		RequestPath:    reqCtx.requestPath,
+		ChannelID:      cfg.ID,
+		StatusCode:     entry.status,
+		Duration:       time.Since(reqCtx.startTime).Seconds(),
+		AuthTokenID:    reqCtx.tokenID,
+		ClientIP:       reqCtx.clientIP,
+		StartTime:      reqCtx.startTime,
+		CostMultiplier: cfg.CostMultiplier,
+		ThinkingEffort: reqCtx.thinkingEffort,
+		RequestID:      reqCtx.requestID,
+	})
+	logEntry.Message = "response cache hit"
+	s.AddLogAsync(logEntry)
+}
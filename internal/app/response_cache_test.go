@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCache_TTLAndLRUEviction(t *testing.T) {
+	t.Parallel()
+
+	rc := newResponseCache(time.Hour, 10)
+	rc.Set("a", 200, "application/json", []byte("aaaa"))
+	rc.Set("b", 200, "application/json", []byte("bbbb"))
+	if _, ok := rc.Get("a"); !ok { // a 变为最近使用
+		t.Fatal("expected hit for a")
+	}
+	rc.Set("c", 200, "application/json", []byte("cccc")) // 超出 10 字节，淘汰最久未用的 b
+	if _, ok := rc.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok := rc.Get("a"); !ok {
+		t.Fatal("expected a to survive eviction")
+	}
+	if rc.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", rc.Len())
+	}
+
+	rc.Set("too-big", 200, "", []byte("0123456789ab"))
+	if _, ok := rc.Get("too-big"); ok {
+		t.Fatal("entries larger than the cache must not be stored")
+	}
+
+	expiring := newResponseCache(time.Millisecond, 1<<10)
+	expiring.Set("k", 200, "", []byte("v"))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.Get("k"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+	if expiring.Len() != 0 {
+		t.Fatalf("expired entry should be removed, len=%d", expiring.Len())
+	}
+
+	if newResponseCache(0, 1<<10) != nil {
+		t.Fatal("ttl=0 should disable the cache")
+	}
+}
+
+func TestProxy_ResponseCache_CacheableChannelServesRepeatFromCache(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"embedding":[0.1,0.2]}],"usage":{"prompt_tokens":2,"total_tokens":2}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "embed", models: "text-embedding-3-small", apiKey: "sk-embed"},
+	}, map[int]string{0: upstream.URL})
+
+	ctx := context.Background()
+	cfgs, err := env.store.ListConfigs(ctx)
+	if err != nil {
+		t.Fatalf("ListConfigs failed: %v", err)
+	}
+	cfgs[0].Cacheable = true
+	if _, err := env.store.UpdateConfig(ctx, cfgs[0].ID, cfgs[0]); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	env.server.InvalidateChannelListCache()
+
+	body := map[string]any{"model": "text-embedding-3-small", "input": "hello"}
+	first := doProxyRequest(t, env.engine, "/v1/embeddings", body, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first request status=%d body=%s", first.Code, first.Body.String())
+	}
+	if first.Header().Get(responseCacheHeader) != "" {
+		t.Fatal("first request must not be a cache hit")
+	}
+
+	second := doProxyRequest(t, env.engine, "/v1/embeddings", body, nil)
+	if second.Code != http.StatusOK {
+		t.Fatalf("second request status=%d body=%s", second.Code, second.Body.String())
+	}
+	if second.Header().Get(responseCacheHeader) != "HIT" {
+		t.Fatalf("expected cache hit header, got %q", second.Header().Get(responseCacheHeader))
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("cached body mismatch: %s vs %s", second.Body.String(), first.Body.String())
+	}
+	if !strings.HasPrefix(second.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("expected cached content type, got %q", second.Header().Get("Content-Type"))
+	}
+
+	// 请求体不同则不命中
+	third := doProxyRequest(t, env.engine, "/v1/embeddings", map[string]any{"model": "text-embedding-3-small", "input": "other"}, nil)
+	if third.Code != http.StatusOK || third.Header().Get(responseCacheHeader) != "" {
+		t.Fatalf("different body should miss the cache: status=%d header=%q", third.Code, third.Header().Get(responseCacheHeader))
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("expected 2 upstream hits, got %d", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		logs, err := env.store.ListLogs(ctx, time.Now().Add(-time.Minute), 20, 0, nil)
+		if err != nil {
+			t.Fatalf("ListLogs failed: %v", err)
+		}
+		found := false
+		for _, entry := range logs {
+			if entry.Message == "response cache hit" {
+				found = true
+				if entry.InputTokens != 0 || entry.Cost != 0 {
+					t.Fatalf("cache hit should not bill tokens: %+v", entry)
+				}
+			}
+		}
+		if found {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cache hit log not found")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestProxy_ResponseCache_NonCacheableChannelAlwaysForwards(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	upstream := newTestHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object":"list","data":[],"usage":{"prompt_tokens":1,"total_tokens":1}}`))
+	}))
+	defer upstream.Close()
+
+	env := setupProxyTestEnv(t, []testChannel{
+		{name: "embed", models: "text-embedding-3-small", apiKey: "sk-embed"},
+	}, map[int]string{0: upstream.URL})
+
+	body := map[string]any{"model": "text-embedding-3-small", "input": "hello"}
+	for range 2 {
+		w := doProxyRequest(t, env.engine, "/v1/embeddings", body, nil)
+		if w.Code != http.StatusOK || w.Header().Get(responseCacheHeader) != "" {
+			t.Fatalf("unexpected response: status=%d header=%q", w.Code, w.Header().Get(responseCacheHeader))
+		}
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("expected 2 upstream hits, got %d", got)
+	}
+}
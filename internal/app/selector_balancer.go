@@ -1,8 +1,10 @@
 package app
 
 import (
+	"log"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	modelpkg "ccLoad/internal/model"
@@ -14,6 +16,26 @@ const (
 	effPriorityPrecision = 10
 )
 
+const (
+	// routingPriority 默认：严格按优先级，仅同优先级渠道之间负载均衡
+	routingPriority = "priority"
+	// routingBalanced 忽略优先级分组：所有可用渠道一起轮询，优先级仅决定失败回退顺序
+	routingBalanced = "balanced"
+)
+
+// parseRoutingMode 解析 CCLOAD_ROUTING（空值或非法值回退严格优先级）
+func parseRoutingMode(raw string) string {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case "", routingPriority:
+		return routingPriority
+	case routingBalanced:
+		return routingBalanced
+	default:
+		log.Printf("[WARN] 无效的 CCLOAD_ROUTING=%s（可选 priority/balanced），使用默认优先级路由", raw)
+		return routingPriority
+	}
+}
+
 func effPriorityBucket(p float64) int64 {
 	scaled := p * float64(effPriorityPrecision)
 	// 浮点误差修正：避免 5.1*10 得到 50.999999... 被截断到 50
@@ -74,10 +96,12 @@ func (s *Server) sortChannelsByHealth(
 	// 同有效优先级内按 KeyCount 平滑加权轮询（负载均衡）
 	// 说明：healthCache 开启后仍需按 Key 数量分流。
 	// 这里仅把“本轮选中的渠道”移动到组首，确保首选渠道按权重分布；其余顺序保持稳定，便于失败回退时可预测。
+	// balanced 路由：全部渠道视为一组
+	balanced := s.routingMode == routingBalanced
 	result := make([]*modelpkg.Config, len(scored))
 	groupStart := 0
 	for i := 1; i <= len(scored); i++ {
-		if i == len(scored) || effPriorityBucket(scored[i].effPriority) != effPriorityBucket(scored[groupStart].effPriority) {
+		if i == len(scored) || (!balanced && effPriorityBucket(scored[i].effPriority) != effPriorityBucket(scored[groupStart].effPriority)) {
 			if i-groupStart > 1 {
 				s.balanceScoredChannelsInPlace(scored[groupStart:i], keyCooldowns, now)
 			}
@@ -162,8 +186,8 @@ func medianFloat64(values []float64) float64 {
 	return (cp[mid-1] + cp[mid]) / 2
 }
 
-// balanceSamePriorityChannels 按优先级分组，组内使用平滑加权轮询
-// 用于 healthCache 关闭时的场景，确保确定性分流
+// balanceSamePriorityChannels 按优先级分组，组内使用平滑加权轮询（CCLOAD_SELECTION=lru 时按最久未使用排序）
+// 用于 healthCache 关闭时的场景，确保确定性分流；CCLOAD_ROUTING=balanced 时所有渠道视为一组
 func (s *Server) balanceSamePriorityChannels(
 	channels []*modelpkg.Config,
 	keyCooldowns map[int64]map[int]time.Time,
@@ -187,13 +211,18 @@ func (s *Server) balanceSamePriorityChannels(
 	})
 
 	// 按优先级分组，组内使用平滑加权轮询
+	balancedRouting := s.routingMode == routingBalanced
 	groupStart := 0
 	for i := 1; i <= n; i++ {
-		if i == n || result[i].Priority != result[groupStart].Priority {
+		if i == n || (!balancedRouting && result[i].Priority != result[groupStart].Priority) {
 			if i-groupStart > 1 {
 				group := result[groupStart:i]
-				balanced := s.channelBalancer.SelectWithCooldown(group, keyCooldowns, now)
-				copy(result[groupStart:i], balanced)
+				if s.selectionStrategy == selectionLRU {
+					s.sortByLeastRecentlyUsed(group)
+				} else {
+					balanced := s.channelBalancer.SelectWithCooldown(group, keyCooldowns, now)
+					copy(result[groupStart:i], balanced)
+				}
 			}
 			groupStart = i
 		}
@@ -214,6 +243,18 @@ func (s *Server) balanceScoredChannelsInPlace(
 		return
 	}
 
+	// LRU 模式：组内整体按最后使用时间升序，不经过轮询器
+	if s.selectionStrategy == selectionLRU {
+		lastUsed := make(map[int64]int64, n)
+		for _, item := range items {
+			lastUsed[item.config.ID] = s.channelLastUsed.LastUsed(item.config)
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return lastUsed[items[i].config.ID] < lastUsed[items[j].config.ID]
+		})
+		return
+	}
+
 	// channelBalancer 在 Init() 中无条件初始化，nil 表示初始化错误
 	if s.channelBalancer == nil {
 		panic("channelBalancer is nil: server not properly initialized")
@@ -3,6 +3,7 @@ package app
 import (
 	"math"
 	"testing"
+	"time"
 
 	modelpkg "ccLoad/internal/model"
 )
@@ -118,3 +119,78 @@ func TestCalculateEffectivePriority_TTFBPenalty(t *testing.T) {
 		t.Fatalf("no median: got %v want 100", got)
 	}
 }
+
+func TestParseRoutingMode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":           routingPriority,
+		"priority":   routingPriority,
+		" Balanced ": routingBalanced,
+		"random":     routingPriority,
+	}
+	for raw, want := range cases {
+		if got := parseRoutingMode(raw); got != want {
+			t.Errorf("parseRoutingMode(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestBalanceSamePriorityChannels_BalancedRoutingIgnoresPriority(t *testing.T) {
+	t.Parallel()
+
+	channels := []*modelpkg.Config{
+		{ID: 1, Name: "high", Priority: 100, KeyCount: 1},
+		{ID: 2, Name: "mid", Priority: 50, KeyCount: 1},
+		{ID: 3, Name: "low", Priority: 10, KeyCount: 1},
+	}
+	now := time.Now()
+
+	strict := &Server{channelBalancer: NewSmoothWeightedRR(), routingMode: routingPriority}
+	for range 3 {
+		if got := strict.balanceSamePriorityChannels(channels, nil, now); got[0].Name != "high" {
+			t.Fatalf("priority routing should always pick highest priority, got %v", configNames(got))
+		}
+	}
+
+	balanced := &Server{channelBalancer: NewSmoothWeightedRR(), routingMode: routingBalanced}
+	firsts := make(map[string]int)
+	for range 6 {
+		got := balanced.balanceSamePriorityChannels(channels, nil, now)
+		firsts[got[0].Name]++
+		// 回退顺序仍按优先级
+		rest := got[1:]
+		if rest[0].Priority < rest[1].Priority {
+			t.Fatalf("fallback order should follow priority, got %v", configNames(got))
+		}
+	}
+	for _, name := range []string{"high", "mid", "low"} {
+		if firsts[name] != 2 {
+			t.Fatalf("expected even distribution across channels, got %v", firsts)
+		}
+	}
+}
+
+func TestSortChannelsByHealth_BalancedRoutingIgnoresPriority(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{
+		healthCache:     &HealthCache{config: modelpkg.HealthScoreConfig{Enabled: true}},
+		channelBalancer: NewSmoothWeightedRR(),
+		routingMode:     routingBalanced,
+	}
+	empty := make(map[int64]modelpkg.ChannelHealthStats)
+	server.healthCache.healthStats.Store(&empty)
+
+	channels := []*modelpkg.Config{
+		{ID: 1, Name: "high", Priority: 100, KeyCount: 1},
+		{ID: 2, Name: "low", Priority: 10, KeyCount: 1},
+	}
+	firsts := make(map[string]int)
+	for range 4 {
+		firsts[server.sortChannelsByHealth(channels, nil, time.Now())[0].Name]++
+	}
+	if firsts["high"] != 2 || firsts["low"] != 2 {
+		t.Fatalf("expected alternating selection, got %v", firsts)
+	}
+}
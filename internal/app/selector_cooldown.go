@@ -40,6 +40,36 @@ func (s *Server) filterCooldownChannelsInternal(ctx context.Context, channels []
 
 	now := time.Now()
 
+	// === 维护模式过滤（维护中的渠道不参与选择，也不进入全冷却兜底）===
+	channels = filterMaintenanceChannels(channels)
+	if len(channels) == 0 {
+		log.Print("[INFO] 所有候选渠道均处于维护模式")
+		return nil, nil
+	}
+
+	// === 零 Key 渠道过滤（CCLOAD_SKIP_ZERO_KEY_CHANNELS，避免每次请求都在无 Key 渠道上失败）===
+	if s.skipZeroKeyChannels {
+		channels = filterZeroKeyChannels(channels)
+		if len(channels) == 0 {
+			log.Print("[INFO] 所有候选渠道均没有可用的 API Key")
+			return nil, nil
+		}
+	}
+
+	// === 启用时段过滤（时段外的渠道不参与选择，也不进入全冷却兜底）===
+	channels = s.filterInactiveScheduleChannels(channels, now)
+	if len(channels) == 0 {
+		log.Print("[INFO] 所有候选渠道均不在启用时段内")
+		return nil, nil
+	}
+
+	// === 模型屏蔽过滤（渠道 blocked_models 命中请求模型时直接排除）===
+	channels = filterBlockedModelChannels(channels, requestModel)
+	if len(channels) == 0 {
+		log.Printf("[INFO] 所有候选渠道均屏蔽了模型 %s", requestModel)
+		return nil, nil
+	}
+
 	// === 成本限额过滤（在冷却过滤之前）===
 	channels = s.filterCostLimitExceededChannels(channels)
 	if len(channels) == 0 {
@@ -284,6 +314,31 @@ func (s *Server) modelCooldownUntil(
 	return until, ok
 }
 
+// filterMaintenanceChannels 过滤处于维护模式的渠道
+func filterMaintenanceChannels(channels []*modelpkg.Config) []*modelpkg.Config {
+	filtered := channels[:0:0]
+	for _, ch := range channels {
+		if !ch.Maintenance {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
+
+// filterBlockedModelChannels 过滤 blocked_models 包含请求模型的渠道（"*" 通配请求不受影响）
+func filterBlockedModelChannels(channels []*modelpkg.Config, requestModel string) []*modelpkg.Config {
+	if requestModel == "" || requestModel == "*" {
+		return channels
+	}
+	filtered := channels[:0:0]
+	for _, ch := range channels {
+		if !ch.BlocksModel(requestModel) {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
+
 // filterCostLimitExceededChannels 过滤超过每日成本限额的渠道
 func (s *Server) filterCostLimitExceededChannels(channels []*modelpkg.Config) []*modelpkg.Config {
 	if s.costCache == nil {
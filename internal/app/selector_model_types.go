@@ -0,0 +1,84 @@
+package app
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	modelpkg "ccLoad/internal/model"
+	"ccLoad/internal/util"
+)
+
+// modelTypeRule 模型名 → 允许的渠道类型（CCLOAD_MODEL_CHANNEL_TYPES 的一条规则）
+type modelTypeRule struct {
+	pattern string   // 小写；"*" 结尾表示前缀匹配
+	types   []string // 允许的渠道类型
+}
+
+// parseModelTypeRules 解析 CCLOAD_MODEL_CHANNEL_TYPES（如 "gpt-*=openai|codex,claude-*=anthropic"）
+// 规则按顺序匹配，首条命中的规则生效；非法项告警后忽略
+func parseModelTypeRules(raw string) []modelTypeRule {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	var rules []modelTypeRule
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pattern, typeList, ok := strings.Cut(part, "=")
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if !ok || pattern == "" {
+			log.Printf("[WARN] 忽略无效的 CCLOAD_MODEL_CHANNEL_TYPES 项: %q（格式: 模型前缀*=类型1|类型2）", part)
+			continue
+		}
+		var types []string
+		for t := range strings.SplitSeq(typeList, "|") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if !util.IsValidChannelType(t) {
+				log.Printf("[WARN] CCLOAD_MODEL_CHANNEL_TYPES 项 %q 包含无效渠道类型: %q", part, t)
+				types = nil
+				break
+			}
+			types = append(types, t)
+		}
+		if len(types) == 0 {
+			continue
+		}
+		rules = append(rules, modelTypeRule{pattern: pattern, types: types})
+	}
+	return rules
+}
+
+// allowedChannelTypesForModel 返回模型允许的渠道类型；无规则命中时返回 nil（不限制）
+func (s *Server) allowedChannelTypesForModel(modelName string) []string {
+	if len(s.modelTypeRules) == 0 || modelName == "" || modelName == "*" {
+		return nil
+	}
+	for _, rule := range s.modelTypeRules {
+		if util.MatchPrefixPattern(rule.pattern, modelName) {
+			return rule.types
+		}
+	}
+	return nil
+}
+
+// filterModelChannelTypes 按模型→渠道类型规则过滤候选，避免模型误配到其他供应商的渠道上
+func (s *Server) filterModelChannelTypes(channels []*modelpkg.Config, modelName string) []*modelpkg.Config {
+	types := s.allowedChannelTypesForModel(modelName)
+	if types == nil {
+		return channels
+	}
+	filtered := channels[:0:0]
+	for _, ch := range channels {
+		if slices.Contains(types, ch.GetChannelType()) {
+			filtered = append(filtered, ch)
+		}
+	}
+	if dropped := len(channels) - len(filtered); dropped > 0 {
+		log.Printf("[INFO] 模型 %s 仅允许渠道类型 %s，已排除 %d 个其他类型的候选渠道", modelName, strings.Join(types, "/"), dropped)
+	}
+	return filtered
+}
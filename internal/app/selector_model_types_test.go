@@ -0,0 +1,61 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	modelpkg "ccLoad/internal/model"
+)
+
+func TestParseModelTypeRules(t *testing.T) {
+	t.Parallel()
+
+	rules := parseModelTypeRules(" gpt-*=openai|Codex , claude-*=anthropic, bad, o3=unknown, =openai, gemini-2.5-pro=gemini")
+	want := []modelTypeRule{
+		{pattern: "gpt-*", types: []string{"openai", "codex"}},
+		{pattern: "claude-*", types: []string{"anthropic"}},
+		{pattern: "gemini-2.5-pro", types: []string{"gemini"}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("rules = %#v, want %#v", rules, want)
+	}
+	if got := parseModelTypeRules("  "); got != nil {
+		t.Fatalf("empty config should yield nil, got %#v", got)
+	}
+}
+
+func TestFilterModelChannelTypes(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{modelTypeRules: parseModelTypeRules("gpt-*=openai|codex,claude-*=anthropic,gpt-image-1=gemini")}
+	channels := []*modelpkg.Config{
+		{ID: 1, ChannelType: "anthropic"},
+		{ID: 2, ChannelType: "openai"},
+		{ID: 3, ChannelType: "codex"},
+		{ID: 4, ChannelType: "gemini"},
+	}
+	ids := func(cs []*modelpkg.Config) []int64 {
+		out := []int64{}
+		for _, c := range cs {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	for model, want := range map[string][]int64{
+		"GPT-4o":          {2, 3},
+		"claude-sonnet-4": {1},
+		"gpt-image-1":     {2, 3}, // 按顺序首条命中的规则生效
+		"deepseek-chat":   {1, 2, 3, 4},
+		"*":               {1, 2, 3, 4},
+		"":                {1, 2, 3, 4},
+	} {
+		if got := ids(s.filterModelChannelTypes(channels, model)); !reflect.DeepEqual(got, want) {
+			t.Errorf("model %q: got %v, want %v", model, got, want)
+		}
+	}
+
+	if got := (&Server{}).filterModelChannelTypes(channels, "gpt-4o"); len(got) != len(channels) {
+		t.Fatalf("no rules should not filter, got %d", len(got))
+	}
+}
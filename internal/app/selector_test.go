@@ -1717,3 +1717,38 @@ func TestFilterCostLimitExceededChannels(t *testing.T) {
 		}
 	})
 }
+
+func TestSelectCandidates_SkipsChannelsBlockingModel(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	server := &Server{store: store, channelBalancer: NewSmoothWeightedRR()}
+	ctx := context.Background()
+
+	for _, cfg := range []*model.Config{
+		{Name: "open", URL: "https://a.example.com", Priority: 10, Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}, {Model: "gpt-4-0314"}}},
+		{Name: "blocked", URL: "https://b.example.com", Priority: 20, Enabled: true, BlockedModels: []string{"gpt-4-0314"}, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}, {Model: "gpt-4-0314"}}},
+	} {
+		if _, err := store.CreateConfig(ctx, cfg); err != nil {
+			t.Fatalf("CreateConfig: %v", err)
+		}
+	}
+
+	candidates, err := server.selectCandidatesByModelAndType(ctx, "gpt-4-0314", "")
+	if err != nil {
+		t.Fatalf("selectCandidatesByModelAndType: %v", err)
+	}
+	if names := configNames(candidates); len(names) != 1 || names[0] != "open" {
+		t.Fatalf("candidates = %v, want [open]", names)
+	}
+
+	// 未屏蔽的模型不受影响
+	candidates, err = server.selectCandidatesByModelAndType(ctx, "gpt-4", "")
+	if err != nil {
+		t.Fatalf("selectCandidatesByModelAndType: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("candidates = %v, want both channels", configNames(candidates))
+	}
+}
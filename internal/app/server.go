@@ -10,6 +10,7 @@ import (
 	neturl "net/url"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -44,16 +45,35 @@ type Server struct {
 	cooldownManager               *cooldown.Manager          // 统一冷却管理器
 	healthCache                   *HealthCache               // 渠道健康度缓存
 	costCache                     *CostCache                 // 渠道每日成本缓存
+	modelBudget                   *ModelBudget               // 模型月度预算
 	channelRPMLimiter             *channelRPMLimiter         // 渠道RPM限制器（内存滑动窗口）
 	channelConcurrencyLimiter     *channelConcurrencyLimiter // 渠道并发限制器（内存计数）
 	statsCache                    *StatsCache                // 统计结果缓存层
 	channelBalancer               *SmoothWeightedRR          // 渠道负载均衡器（平滑加权轮询）
 	urlSelector                   *URLSelector               // URL选择器（多URL场景的延迟追踪与冷却）
 	protocolRegistry              *protocol.Registry
-	client                        *http.Client          // HTTP客户端（全局默认）
-	proxyTransports               sync.Map              // proxyURL → *http.Transport（渠道级代理缓存）
-	skipTLSVerify                 bool                  // 透传给渠道级 Transport
-	activeRequests                *activeRequestManager // 进行中请求（内存状态，不持久化）
+	client                        *http.Client            // HTTP客户端（全局默认）
+	proxyTransports               sync.Map                // proxyURL → *http.Transport（渠道级代理缓存）
+	skipTLSVerify                 bool                    // 透传给渠道级 Transport
+	compressResponses             bool                    // 代理响应 gzip/deflate 压缩（CCLOAD_COMPRESS_RESPONSES）
+	hideUpstreamErrors            bool                    // 失败时不向客户端透传上游错误体（CCLOAD_HIDE_UPSTREAM_ERRORS）
+	skipZeroKeyChannels           bool                    // 选择时跳过没有可用 Key 的渠道（CCLOAD_SKIP_ZERO_KEY_CHANNELS）
+	maxKeysPerChannel             int                     // 单渠道 Key 数量上限（CCLOAD_MAX_KEYS_PER_CHANNEL，0=不限）
+	streamFallbackNonStream       bool                    // 流式全部首字节超时后以非流式兜底一次（CCLOAD_STREAM_FALLBACK_NONSTREAM）
+	validateRequests              bool                    // 转发前校验请求体必需字段（CCLOAD_VALIDATE_REQUESTS）
+	exhaustedStatus               int                     // 无可用上游时返回的状态码（CCLOAD_EXHAUSTED_STATUS，0 表示默认 503）
+	activeRequests                *activeRequestManager   // 进行中请求（内存状态，不持久化）
+	keyLastUsed                   *keyLastUsedTracker     // Key 最后使用时间（内存聚合，定期批量落库）
+	channelLastUsed               *channelLastUsedTracker // 渠道最后使用时间（LRU 选择 + 定期批量落库）
+	selectionStrategy             string                  // 同优先级渠道选择策略（CCLOAD_SELECTION：round_robin/lru）
+	routingMode                   string                  // 渠道路由模式（CCLOAD_ROUTING：priority/balanced）
+	defaultChannelPriority        int                     // 新建渠道未指定 priority 时的默认值（CCLOAD_DEFAULT_PRIORITY）
+	defaultKeyStrategy            string                  // 新建渠道未指定 key_strategy 时的默认值（CCLOAD_DEFAULT_KEY_STRATEGY）
+	scheduleLocation              *time.Location          // 渠道启用时段的默认时区（CCLOAD_SCHEDULE_TZ，nil=按传入时间的时区）
+	modelTypeRules                []modelTypeRule         // 模型→允许的渠道类型（CCLOAD_MODEL_CHANNEL_TYPES，空=不限制）
+	responseCache                 *responseCache          // cacheable 渠道的上游响应缓存（nil=关闭）
+	idempotencyCache              *responseCache          // Idempotency-Key 请求去重缓存（nil=关闭）
+	idempotencyInflight           idempotencyInflight     // 处理中的 Idempotency-Key（同键并发请求返回409）
 	scheduledChannelChecksRunning atomic.Bool
 
 	// 异步统计（有界队列，避免每请求起goroutine）
@@ -72,8 +92,11 @@ type Server struct {
 	loginRateLimiter *util.LoginRateLimiter
 
 	// 并发控制
-	concurrencySem chan struct{} // 信号量：限制最大并发请求数（防止goroutine爆炸）
-	maxConcurrency int           // 最大并发数（默认1000）
+	concurrencySem         chan struct{} // 信号量：限制最大并发请求数（防止goroutine爆炸）
+	prioritySem            chan struct{} // 优先请求预留槽位（CCLOAD_PRIORITY_CONCURRENCY，nil=未启用）
+	maxConcurrency         int           // 最大并发数（默认1000）
+	concurrencyWaiting     atomic.Int64  // 正在等待槽位的请求数
+	concurrencyWaitedTotal atomic.Int64  // 累计因槽位已满而等待的请求数（启动以来）
 
 	// 优雅关闭机制
 	baseCtx                 context.Context    // server生命周期context，Shutdown时取消
@@ -81,6 +104,8 @@ type Server struct {
 	shutdownCh              chan struct{}      // 关闭信号channel
 	shutdownDone            chan struct{}      // Shutdown完成信号（幂等）
 	isShuttingDown          atomic.Bool        // shutdown标志，防止向已关闭channel写入
+	draining                atomic.Bool        // 排空模式：拒绝新代理请求，等待进行中请求完成
+	maintenance             atomic.Bool        // 维护模式：代理照常，管理端写操作返回 503
 	modelCatalogSyncMu      sync.Mutex         // 串行化模型目录启动和关闭，保护 WaitGroup
 	modelCatalogSyncStarted atomic.Bool
 	wg                      sync.WaitGroup // 等待所有后台goroutine结束
@@ -124,6 +149,26 @@ func NewServer(store storage.Store) *Server {
 	}
 	log.Print("[INFO] API访问令牌将从数据库动态加载（支持Web界面管理与环境变量预置）")
 
+	// 单渠道 Key 数量上限（仅环境变量，默认 100）
+	maxKeysPerChannel := parseMaxKeysPerChannel(os.Getenv("CCLOAD_MAX_KEYS_PER_CHANNEL"))
+	if maxKeysPerChannel != defaultMaxKeysPerChannel {
+		log.Printf("[CONFIG] 单渠道 Key 数量上限: %d（0=不限）", maxKeysPerChannel)
+	}
+
+	// 渠道预置（CCLOAD_SEED_CHANNELS）：在迁移之后、缓存加载之前执行，已存在的同名渠道不覆盖
+	seedCtx, seedCancel := context.WithTimeout(context.Background(), channelSeedTimeout)
+	seedResult, err := SeedChannelsFromEnv(seedCtx, store, maxKeysPerChannel)
+	seedCancel()
+	if err != nil {
+		log.Fatalf("[FATAL] 渠道预置失败: %v", err)
+	}
+	if seedResult.Configured > 0 {
+		log.Printf("[INFO] 渠道预置完成（配置: %d, 新增: %d, 已存在跳过: %d）", seedResult.Configured, seedResult.Created, len(seedResult.Skipped))
+		if len(seedResult.Skipped) > 0 {
+			log.Printf("[INFO] 预置跳过的渠道: %s", strings.Join(seedResult.Skipped, ", "))
+		}
+	}
+
 	// 从ConfigService读取运行时配置（启动时加载一次，修改后重启生效）
 	runtimeCfg := loadServerRuntimeConfig(configService)
 
@@ -132,8 +177,26 @@ func NewServer(store storage.Store) *Server {
 	if concEnv := os.Getenv("CCLOAD_MAX_CONCURRENCY"); concEnv != "" {
 		if val, err := strconv.Atoi(concEnv); err == nil && val > 0 {
 			maxConcurrency = val
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_MAX_CONCURRENCY=%s（必须为正整数），使用默认值 %d", concEnv, maxConcurrency)
 		}
 	}
+	log.Printf("[CONFIG] 最大并发请求数: %d", maxConcurrency)
+
+	// 优先请求预留槽位：从总并发中划出，至少为普通请求保留 1 个槽位
+	priorityConcurrency := 0
+	if prioEnv := os.Getenv("CCLOAD_PRIORITY_CONCURRENCY"); prioEnv != "" {
+		if val, err := strconv.Atoi(prioEnv); err == nil && val >= 0 && val < maxConcurrency {
+			priorityConcurrency = val
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_PRIORITY_CONCURRENCY=%s（必须为小于最大并发数 %d 的非负整数），已禁用优先槽位", prioEnv, maxConcurrency)
+		}
+	}
+	var prioritySem chan struct{}
+	if priorityConcurrency > 0 {
+		prioritySem = make(chan struct{}, priorityConcurrency)
+		log.Printf("[CONFIG] 优先槽位: %d（带 %s 头的请求专用，普通请求可用 %d）", priorityConcurrency, priorityHeader, maxConcurrency-priorityConcurrency)
+	}
 
 	// TLS证书验证配置（仅环境变量）
 	// 这是一个危险开关：一旦关闭证书校验，上游 HTTPS 等同明文 + 任意中间人。
@@ -142,9 +205,106 @@ func NewServer(store storage.Store) *Server {
 		log.Print("[WARN] 已禁用上游 TLS 证书校验（InsecureSkipVerify=true）：仅用于临时排障/受控内网环境")
 	}
 
+	// 代理响应压缩（仅环境变量，默认关闭）
+	compressResponses := os.Getenv("CCLOAD_COMPRESS_RESPONSES") == "1"
+	if compressResponses {
+		log.Print("[CONFIG] 代理响应压缩已启用（按 Accept-Encoding 协商 gzip/deflate）")
+	}
+
+	// 隐藏上游错误详情（仅环境变量，默认关闭）
+	hideUpstreamErrors := util.ParseBoolDefault(os.Getenv("CCLOAD_HIDE_UPSTREAM_ERRORS"), false)
+	if hideUpstreamErrors {
+		log.Print("[CONFIG] 已隐藏上游错误详情：客户端仅收到通用错误信息，完整错误体仍记录在日志中")
+	}
+
+	// 跳过零 Key 渠道（仅环境变量，默认关闭）
+	skipZeroKeyChannels := util.ParseBoolDefault(os.Getenv("CCLOAD_SKIP_ZERO_KEY_CHANNELS"), false)
+	if skipZeroKeyChannels {
+		log.Print("[CONFIG] 零 Key 渠道将在选择时跳过（无可用 Key 的渠道不参与路由）")
+	}
+
+	// 流式首字节超时的非流式兜底（仅环境变量，默认关闭）
+	streamFallbackNonStream := util.ParseBoolDefault(os.Getenv("CCLOAD_STREAM_FALLBACK_NONSTREAM"), false)
+	if streamFallbackNonStream {
+		log.Print("[CONFIG] 流式非流式兜底已启用：流式请求全部首字节超时后，以 stream=false 重试首个超时渠道一次")
+	}
+
+	// 请求体结构校验（仅环境变量，默认关闭）
+	validateRequests := util.ParseBoolDefault(os.Getenv("CCLOAD_VALIDATE_REQUESTS"), false)
+	if validateRequests {
+		log.Print("[CONFIG] 请求体校验已启用：缺少 messages/contents 的请求直接返回 400")
+	}
+
+	// 无可用上游时的状态码（仅环境变量，默认 503）
+	exhaustedStatus := parseExhaustedStatus(os.Getenv("CCLOAD_EXHAUSTED_STATUS"))
+	if exhaustedStatus != http.StatusServiceUnavailable {
+		log.Printf("[CONFIG] 无可用上游时返回状态码: %d", exhaustedStatus)
+	}
+
+	// 同优先级渠道选择策略（仅环境变量，默认轮询）
+	selectionStrategy := parseSelectionStrategy(os.Getenv("CCLOAD_SELECTION"))
+	if selectionStrategy == selectionLRU {
+		log.Print("[CONFIG] 渠道选择策略: lru（同优先级渠道中优先选择最久未使用的渠道）")
+	}
+
+	// 渠道路由模式（仅环境变量，默认严格优先级）
+	routingMode := parseRoutingMode(os.Getenv("CCLOAD_ROUTING"))
+	if routingMode == routingBalanced {
+		log.Print("[CONFIG] 渠道路由模式: balanced（所有可用渠道参与均衡，优先级仅决定回退顺序）")
+	}
+
+	// 自定义模型定价（仅环境变量，默认使用内置定价表与 models.dev 目录）
+	loadCustomPricingFile(os.Getenv("CCLOAD_PRICING_FILE"))
+
+	// 模型→渠道类型路由规则（仅环境变量，默认不限制）
+	modelTypeRules := parseModelTypeRules(os.Getenv("CCLOAD_MODEL_CHANNEL_TYPES"))
+	if len(modelTypeRules) > 0 {
+		log.Printf("[CONFIG] 模型渠道类型规则: %s", os.Getenv("CCLOAD_MODEL_CHANNEL_TYPES"))
+	}
+
+	// 日志 message 截断长度（启动时解析并校验，非法值回退默认）
+	if maxLen := getLogMessageMaxLen(); maxLen != config.DefaultLogMessageMaxLen {
+		log.Printf("[CONFIG] 日志 message 截断长度: %d 字节", maxLen)
+	}
+
+	// 上游响应缓存（仅对 cacheable 渠道生效，TTL=0 关闭）
+	responseCache := newResponseCacheFromEnv()
+	if responseCache == nil {
+		log.Print("[CONFIG] 上游响应缓存已关闭（CCLOAD_RESPONSE_CACHE_TTL=0）")
+	} else if os.Getenv("CCLOAD_RESPONSE_CACHE_TTL") != "" || os.Getenv("CCLOAD_RESPONSE_CACHE_MAX_MB") != "" {
+		log.Printf("[CONFIG] 上游响应缓存: TTL=%s 容量=%dMB（仅对 cacheable 渠道的非流式 POST 请求生效）", responseCache.ttl, responseCache.maxBytes>>20)
+	}
+
+	// Idempotency-Key 请求去重（默认关闭，设置 CCLOAD_IDEMPOTENCY_TTL>0 启用）
+	idempotencyCache := newIdempotencyCacheFromEnv()
+	if idempotencyCache != nil {
+		log.Printf("[CONFIG] Idempotency-Key 请求去重: TTL=%s（仅缓存成功响应，同键并发请求返回409）", idempotencyCache.ttl)
+	}
+
+	if codes := getNoCooldownStatusCodes(); len(codes) > 0 {
+		log.Printf("[CONFIG] 免冷却状态码: %s（切换候选但不冷却 Key/渠道）", os.Getenv("CCLOAD_NO_COOLDOWN_STATUS"))
+	}
+
+	if patterns := getStreamingPathPatterns(); len(patterns) > 0 {
+		log.Printf("[CONFIG] 强制流式路径: %s（按流式请求处理首字节超时与 usage 解析）", strings.Join(patterns, ", "))
+	}
+
+	if interval := getSSEKeepaliveInterval(); interval > 0 {
+		log.Printf("[CONFIG] SSE 保活已启用：上游空闲超过 %s 时向客户端注入注释行", interval)
+	}
+	if minBytes := getMinStreamBytes(); minBytes > 0 {
+		log.Printf("[CONFIG] 最小流字节检测已启用：流式响应不足 %d 字节视为空响应并切换渠道", minBytes)
+	}
+
 	// 构建HTTP Transport（使用统一函数，消除DRY违反）
 	transport := buildHTTPTransport(skipTLSVerify)
-	log.Print("[INFO] HTTP/2已启用（头部压缩+多路复用，HTTPS自动协商）")
+	if transport.ForceAttemptHTTP2 {
+		log.Print("[INFO] HTTP/2已启用（头部压缩+多路复用，HTTPS自动协商）")
+	} else {
+		log.Print("[CONFIG] HTTP/2已禁用（CCLOAD_HTTP_FORCE_HTTP2=0），上游仅使用 HTTP/1.1")
+	}
+	log.Printf("[CONFIG] 上游连接池: 单host空闲=%d 单host最大=%d 空闲超时=%s",
+		transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout)
 	logHostOverrides(getHostOverrides())
 
 	baseCtx, baseCancel := context.WithCancel(context.Background())
@@ -167,10 +327,25 @@ func NewServer(store storage.Store) *Server {
 			Transport: transport,
 			Timeout:   0, // 不设置全局超时，避免中断长时间任务
 		},
-		skipTLSVerify: skipTLSVerify,
+		skipTLSVerify:       skipTLSVerify,
+		compressResponses:   compressResponses,
+		hideUpstreamErrors:  hideUpstreamErrors,
+		skipZeroKeyChannels: skipZeroKeyChannels,
+		maxKeysPerChannel:   maxKeysPerChannel,
+
+		streamFallbackNonStream: streamFallbackNonStream,
+		validateRequests:        validateRequests,
+		exhaustedStatus:         exhaustedStatus,
+		selectionStrategy:       selectionStrategy,
+		routingMode:             routingMode,
+		defaultChannelPriority:  parseDefaultChannelPriority(os.Getenv("CCLOAD_DEFAULT_PRIORITY")),
+		defaultKeyStrategy:      parseDefaultKeyStrategy(os.Getenv("CCLOAD_DEFAULT_KEY_STRATEGY")),
+		scheduleLocation:        parseScheduleLocation(os.Getenv("CCLOAD_SCHEDULE_TZ")),
+		modelTypeRules:          modelTypeRules,
 
 		// 并发控制：使用信号量限制最大并发请求数
-		concurrencySem: make(chan struct{}, maxConcurrency),
+		concurrencySem: make(chan struct{}, maxConcurrency-priorityConcurrency),
+		prioritySem:    prioritySem,
 		maxConcurrency: maxConcurrency,
 
 		// 初始化优雅关闭机制
@@ -183,10 +358,17 @@ func NewServer(store storage.Store) *Server {
 		tokenStatsCh: make(chan tokenStatsUpdate, config.DefaultTokenStatsBufferSize),
 
 		activeRequests:            newActiveRequestManager(),
+		keyLastUsed:               newKeyLastUsedTracker(),
+		channelLastUsed:           newChannelLastUsedTracker(),
+		responseCache:             responseCache,
+		idempotencyCache:          idempotencyCache,
 		channelRPMLimiter:         newChannelRPMLimiter(time.Now),
 		channelConcurrencyLimiter: newChannelConcurrencyLimiter(),
 	}
 
+	// 维护模式持久化在系统设置中，设置变更触发的自重启后仍保持
+	s.maintenance.Store(configService.GetBool("maintenance_mode", false))
+
 	reg := protocol.NewRegistry()
 	protocolbuiltin.Register(reg)
 	s.protocolRegistry = reg
@@ -197,6 +379,7 @@ func NewServer(store storage.Store) *Server {
 	// 初始化冷却管理器（统一管理渠道级和Key级冷却）
 	// 传入Server作为configGetter，利用缓存层查询渠道配置
 	s.cooldownManager = cooldown.NewManager(store, s)
+	s.cooldownManager.SetCircuitBreaker(runtimeCfg.BreakerThreshold, runtimeCfg.BreakerReset)
 
 	// 初始化Key选择器（移除store依赖，避免重复查询）
 	s.keySelector = NewKeySelector()
@@ -219,6 +402,10 @@ func NewServer(store storage.Store) *Server {
 	s.costCache = NewCostCache()
 	bootstrapCostAndURLStats(store, s.costCache, s.urlSelector)
 
+	// 初始化模型月度预算（启动时从数据库加载本月成本）
+	s.modelBudget = loadModelBudget(configService)
+	bootstrapModelBudget(store, s.modelBudget)
+
 	// 初始化统计缓存层（减少重复聚合查询）
 	s.statsCache = NewStatsCache(store)
 	log.Print("[INFO] 统计缓存已启用（智能 TTL，减少数据库聚合查询）")
@@ -237,6 +424,17 @@ func NewServer(store storage.Store) *Server {
 		&s.isShuttingDown,
 		&s.wg,
 	)
+	// 成功日志采样（仅环境变量，默认 1=全部记录）
+	if raw := os.Getenv("CCLOAD_LOG_SUCCESS_SAMPLE_RATE"); raw != "" {
+		if rate, err := strconv.ParseFloat(raw, 64); err == nil && rate >= 0 && rate <= 1 {
+			s.logService.SetSuccessSampleRate(rate)
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_LOG_SUCCESS_SAMPLE_RATE=%s（必须为 0~1 之间的小数），使用默认值 1", raw)
+		}
+	}
+	if rate := s.logService.SuccessSampleRate(); rate < 1 {
+		log.Printf("[CONFIG] 成功日志采样率: %.4g（错误日志全量记录；基于日志的统计中成功请求数约为实际的 %.4g 倍）", rate, rate)
+	}
 	// 启动日志 Workers
 	s.logService.StartWorkers()
 
@@ -254,6 +452,9 @@ func NewServer(store storage.Store) *Server {
 	// 启动后台 worker（Token 统计 / Token 清理 / 状态清理）
 	s.startBackgroundWorkers()
 
+	// 上游连接预热（仅环境变量，默认关闭）
+	s.startUpstreamWarmerLoop(parseWarmUpstreamsInterval(os.Getenv("CCLOAD_WARM_UPSTREAMS")))
+
 	channelCheckIntervalHours := normalizeChannelCheckIntervalHours(
 		configService.GetFloat("channel_check_interval_hours", defaultChannelCheckIntervalHours),
 	)
@@ -318,6 +519,8 @@ type serverRuntimeConfig struct {
 	ChannelTypeTimeouts map[string]channelTypeTimeoutConfig
 	LogRetentionDays    int
 	ModelFuzzyMatch     bool
+	BreakerThreshold    int
+	BreakerReset        time.Duration
 }
 
 // loadServerRuntimeConfig 从 ConfigService 加载运行时配置并校验，无效值兜底为默认值
@@ -349,6 +552,17 @@ func loadServerRuntimeConfig(cs *ConfigService) serverRuntimeConfig {
 		log.Print("[INFO] 已启用模型模糊匹配：未命中时进行子串匹配并按版本排序选择最新模型")
 	}
 
+	breakerThreshold := cs.GetInt("circuit_breaker_threshold", 0)
+	if breakerThreshold < 0 {
+		log.Printf("[WARN] 无效的 circuit_breaker_threshold=%d（必须 >= 0，0=禁用），已禁用熔断", breakerThreshold)
+		breakerThreshold = 0
+	}
+	breakerResetMinutes := cs.GetInt("circuit_breaker_reset_minutes", 60)
+	if breakerResetMinutes < 1 {
+		log.Printf("[WARN] 无效的 circuit_breaker_reset_minutes=%d（必须 >= 1），已使用默认值 60", breakerResetMinutes)
+		breakerResetMinutes = 60
+	}
+
 	return serverRuntimeConfig{
 		MaxKeyRetries:       maxKeyRetries,
 		FirstByteTimeout:    firstByteTimeout,
@@ -356,6 +570,8 @@ func loadServerRuntimeConfig(cs *ConfigService) serverRuntimeConfig {
 		ChannelTypeTimeouts: channelTypeTimeouts,
 		LogRetentionDays:    logRetentionDays,
 		ModelFuzzyMatch:     modelFuzzyMatch,
+		BreakerThreshold:    breakerThreshold,
+		BreakerReset:        time.Duration(breakerResetMinutes) * time.Minute,
 	}
 }
 
@@ -483,7 +699,7 @@ func bootstrapCostAndURLStats(store storage.Store, costCache *CostCache, urlSele
 	}
 }
 
-// startBackgroundWorkers 启动 Token 统计 / Token 清理 / 状态清理三个后台协程。
+// startBackgroundWorkers 启动 Token 统计 / Token 清理 / 状态清理 / Key 使用时间落库四个后台协程。
 // 全部纳入 s.wg，Shutdown 时通过 shutdownCh 协调退出。
 func (s *Server) startBackgroundWorkers() {
 	// 启动Token统计Worker（有界队列：性能可控，Shutdown可等待）
@@ -497,6 +713,10 @@ func (s *Server) startBackgroundWorkers() {
 	// [FIX] P1: 启动后台状态清理协程（防止内存泄漏）
 	s.wg.Add(1)
 	go s.stateCleanupLoop()
+
+	// Key 最后使用时间批量落库
+	s.wg.Add(1)
+	go s.keyLastUsedFlushLoop()
 }
 
 // ================== 缓存辅助函数 ==================
@@ -537,6 +757,7 @@ var getHostOverrides = sync.OnceValue(func() map[string]string {
 //   - skipTLSVerify: 是否跳过TLS证书验证
 func buildHTTPTransport(skipTLSVerify bool) *http.Transport {
 	overrides := getHostOverrides()
+	tuning := getTransportTuning()
 	dialer := &net.Dialer{
 		Timeout:   config.HTTPDialTimeout,
 		KeepAlive: config.HTTPKeepAliveInterval,
@@ -553,15 +774,15 @@ func buildHTTPTransport(skipTLSVerify bool) *http.Transport {
 
 	transport := &http.Transport{
 		Proxy:               http.ProxyFromEnvironment, // 支持 HTTPS_PROXY/HTTP_PROXY/NO_PROXY
-		MaxIdleConns:        config.HTTPMaxIdleConns,
-		MaxIdleConnsPerHost: config.HTTPMaxIdleConnsPerHost,
-		IdleConnTimeout:     90 * time.Second, // 空闲连接90秒后关闭，避免僵尸连接
-		MaxConnsPerHost:     config.HTTPMaxConnsPerHost,
+		MaxIdleConns:        tuning.maxIdleConns(),
+		MaxIdleConnsPerHost: tuning.MaxIdleConnsPerHost,
+		IdleConnTimeout:     tuning.IdleConnTimeout,
+		MaxConnsPerHost:     tuning.maxConnsPerHost(),
 		DialContext:         dialCtx,
 		TLSHandshakeTimeout: config.HTTPTLSHandshakeTimeout,
 		DisableCompression:  false,
 		DisableKeepAlives:   false,
-		ForceAttemptHTTP2:   true, // 启用标准库 HTTP/2（HTTPS 自动协商）
+		ForceAttemptHTTP2:   tuning.ForceHTTP2, // 默认启用标准库 HTTP/2（HTTPS 自动协商）
 		TLSClientConfig: &tls.Config{
 			ClientSessionCache: tls.NewLRUClientSessionCache(config.TLSSessionCacheSize),
 			MinVersion:         tls.VersionTLS12,
@@ -569,7 +790,7 @@ func buildHTTPTransport(skipTLSVerify bool) *http.Transport {
 		},
 	}
 
-	return transport // HTTP/2 已通过 ForceAttemptHTTP2 启用
+	return transport
 }
 
 // getClientForChannel 返回渠道对应的 HTTP 客户端。
@@ -772,6 +993,31 @@ func (s *Server) invalidateChannelRelatedCache(channelID int64) {
 	s.invalidateCooldownCache()
 }
 
+// invalidateKeyRelatedCache Key 冷却状态变更后刷新缓存
+// Key 属于 key_group 时冷却会同步到其他渠道的同组 Key，需失效全部渠道的 Key 缓存
+func (s *Server) invalidateKeyRelatedCache(ctx context.Context, channelID int64, keyIndex int) {
+	if s.keyInGroup(ctx, channelID, keyIndex) {
+		s.InvalidateAllAPIKeysCache()
+		s.invalidateCooldownCache()
+		return
+	}
+	s.invalidateChannelRelatedCache(channelID)
+}
+
+// keyInGroup 判断Key是否配置了 key_group（读缓存，失败按未分组处理）
+func (s *Server) keyInGroup(ctx context.Context, channelID int64, keyIndex int) bool {
+	keys, err := s.getAPIKeys(ctx, channelID)
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		if key.KeyIndex == keyIndex {
+			return key.KeyGroup != ""
+		}
+	}
+	return false
+}
+
 // GetWriteTimeout 返回建议的 HTTP WriteTimeout
 // 基于 nonStreamTimeout 动态计算，确保传输层超时 >= 业务层超时
 func (s *Server) GetWriteTimeout() time.Duration {
@@ -796,7 +1042,7 @@ func (s *Server) resolveProtocolTimeouts(cfg *model.Config, plan protocol.Transf
 
 	protocolKey := string(plan.UpstreamProtocol)
 	if protocolKey == "" && cfg != nil {
-		protocolKey = cfg.GetChannelType()
+		protocolKey = cfg.GetProtocol()
 	}
 	if protocolKey == "" {
 		return timeouts
@@ -828,14 +1074,22 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 	// 公开访问的API（代理服务）- 需要 API 认证
 	// 透明代理：统一处理所有 /v1/* 端点，支持所有HTTP方法
 	apiV1 := r.Group("/v1")
+	apiV1.Use(Always200Middleware()) // 兼容模式需覆盖认证失败，放在最外层
 	apiV1.Use(s.authService.RequireAPIAuth())
 	apiV1.Use(captureClientRequestMetadata())
+	if s.compressResponses {
+		apiV1.Use(ProxyCompressionMiddleware())
+	}
 	{
 		apiV1.Any("/*path", s.HandleProxyRequest)
 	}
 	apiV1Beta := r.Group("/v1beta")
+	apiV1Beta.Use(Always200Middleware())
 	apiV1Beta.Use(s.authService.RequireAPIAuth())
 	apiV1Beta.Use(captureClientRequestMetadata())
+	if s.compressResponses {
+		apiV1Beta.Use(ProxyCompressionMiddleware())
+	}
 	{
 		apiV1Beta.Any("/*path", s.HandleProxyRequest)
 	}
@@ -859,7 +1113,7 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 
 	// 需要身份验证的admin APIs（使用Token认证）
 	admin := r.Group("/admin", ZstdMiddleware())
-	admin.Use(s.authService.RequireAdminAuth())
+	admin.Use(s.authService.RequireAdminAuth(), s.auditMiddleware(), s.maintenanceGuard())
 	{
 		// 渠道管理
 		admin.GET("/channels", s.HandleChannels)
@@ -868,6 +1122,8 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 		admin.GET("/channels/export", s.HandleExportChannelsCSV)
 		admin.POST("/channels/import", s.HandleImportChannelsCSV)
 		admin.POST("/channels/check-duplicate", s.HandleCheckDuplicateChannel)
+		admin.GET("/channels/validate", s.HandleValidateChannels)           // 渠道配置检查（只读）
+		admin.POST("/channels/test-all", s.HandleTestAllChannels)           // 批量测试所有启用渠道
 		admin.POST("/channels/batch-priority", s.HandleBatchUpdatePriority) // 批量更新渠道优先级
 		admin.POST("/channels/batch-enabled", s.HandleBatchSetEnabled)      // 批量启用/禁用渠道
 		admin.POST("/channels/batch-delete", s.HandleBatchDeleteChannels)   // 批量删除渠道
@@ -875,34 +1131,48 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 		admin.PUT("/channels/:id", s.HandleChannelByID)
 		admin.DELETE("/channels/:id", s.HandleChannelByID)
 		admin.GET("/channels/:id/keys", s.HandleChannelKeys)
+		admin.PUT("/channels/:id/keys", s.HandleReplaceChannelKeys)
+		admin.POST("/channels/:id/clone", s.HandleCloneChannel)
 		admin.GET("/channels/:id/model-stats", s.HandleChannelModelStats)
 		admin.GET("/channels/:id/url-stats", s.HandleChannelURLStats)
+		admin.GET("/channels/:id/recent-errors", s.HandleChannelRecentErrors)
 		admin.POST("/channels/:id/url-disable", s.HandleURLDisable)
 		admin.POST("/channels/:id/url-enable", s.HandleURLEnable)
 		admin.POST("/channels/:id/key-disable", s.HandleAPIKeyDisable)
 		admin.POST("/channels/:id/key-enable", s.HandleAPIKeyEnable)
 		admin.POST("/channels/models/fetch", s.HandleFetchModelsPreview) // 临时渠道配置获取模型列表
 		admin.POST("/channels/models/refresh-batch", s.HandleBatchRefreshModels)
-		admin.GET("/channels/:id/models/fetch", s.HandleFetchModels) // 获取渠道可用模型列表(新增)
-		admin.POST("/channels/:id/models", s.HandleAddModels)        // 添加渠道模型
-		admin.DELETE("/channels/:id/models", s.HandleDeleteModels)   // 删除渠道模型
+		admin.GET("/channels/:id/models/fetch", s.HandleFetchModels)     // 获取渠道可用模型列表(新增)
+		admin.GET("/channels/:id/remote-models", s.HandleRemoteModels)   // 上游模型列表与已配置模型对比
+		admin.POST("/channels/:id/models", s.HandleAddModels)            // 添加渠道模型
+		admin.DELETE("/channels/:id/models", s.HandleDeleteModels)       // 删除渠道模型
+		admin.DELETE("/channels/:id/models/*model", s.HandleDeleteModel) // 删除渠道单个模型
 		admin.POST("/channels/:id/test", s.HandleChannelTest)
 		admin.POST("/channels/:id/test-url", s.HandleChannelURLTest)
 		admin.POST("/channels/:id/chat", s.HandleChannelChat)
 		admin.POST("/channels/:id/cooldown", s.HandleSetChannelCooldown)
 		admin.POST("/channels/:id/keys/:keyIndex/cooldown", s.HandleSetKeyCooldown)
+		admin.POST("/channels/:id/maintenance", s.HandleSetChannelMaintenance)   // 渠道维护：退出选择并冻结冷却
+		admin.POST("/channels/:id/simulate-error", s.HandleSimulateChannelError) // 仅测试：模拟上游错误触发冷却链路
 		admin.DELETE("/channels/:id/keys/:keyIndex", s.HandleDeleteAPIKey)
 
 		// 统计分析
 		admin.GET("/logs", s.HandleErrors)
 		admin.GET("/logs/bootstrap", s.HandleLogsBootstrap)
+		admin.GET("/logs/export", s.HandleExportLogs)
+		admin.GET("/logs/:id", s.HandleGetLog)
+		admin.GET("/budget", s.HandleGetBudget)
 		admin.POST("/debug-logs/merged-response", s.HandleMergeDebugResponse)
 		admin.GET("/debug-logs/:log_id", s.HandleGetDebugLog)
 		admin.GET("/active-requests", s.HandleActiveRequests) // 进行中请求（内存状态）
 		admin.GET("/active-requests/:request_id/debug-log", s.HandleGetActiveRequestDebugLog)
+		admin.GET("/inflight", s.HandleInflightRequests) // 进行中请求（按总耗时降序）
 		admin.GET("/metrics", s.HandleMetrics)
+		admin.GET("/metrics/stream", s.HandleMetricsStream) // SSE 实时推送最近分桶
 		admin.GET("/stats", s.HandleStats)
 		admin.GET("/stats/filter-options", s.HandleStatsFilterOptions)
+		admin.GET("/stats/history", s.HandleStatsHistory)      // 每日汇总长期趋势
+		admin.GET("/errors/breakdown", s.HandleErrorBreakdown) // 错误按状态码分布
 		admin.GET("/models", s.HandleGetModels)
 
 		// API访问令牌管理
@@ -910,6 +1180,7 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 		admin.POST("/auth-tokens", s.HandleCreateAuthToken)
 		admin.PUT("/auth-tokens/:id", s.HandleUpdateAuthToken)
 		admin.DELETE("/auth-tokens/:id", s.HandleDeleteAuthToken)
+		admin.GET("/auth-tokens/:id/usage", s.HandleGetAuthTokenUsage)
 
 		// 系统配置管理
 		admin.GET("/settings", s.AdminListSettings)
@@ -918,6 +1189,23 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 		admin.POST("/settings/:key/reset", s.AdminResetSetting)
 		admin.POST("/settings/batch", s.AdminBatchUpdateSettings)
 
+		admin.GET("/concurrency", s.HandleConcurrencyStatus)
+		admin.GET("/transport", s.HandleTransportSettings)    // 上游连接池生效配置
+		admin.GET("/storage/sync", s.HandleStorageSyncStatus) // 混合存储同步状态
+		admin.POST("/storage/sync", s.HandleStorageResync)    // 手动全量重同步配置表
+		admin.GET("/backup", s.HandleConfigBackup)            // 全量配置备份（渠道+Key+设置）
+		admin.POST("/restore", s.HandleConfigRestore)         // 从备份恢复
+		admin.GET("/audit", s.HandleAuditLogs)                // 管理操作审计日志
+
+		// 排空模式（滚动发布）
+		admin.GET("/drain", s.HandleDrainStatus)
+		admin.POST("/drain", s.HandleDrain)
+
+		// 维护模式（只读）
+		admin.GET("/maintenance", s.HandleMaintenanceStatus)
+		admin.POST("/maintenance/enable", s.HandleEnableMaintenance)
+		admin.POST("/maintenance/disable", s.HandleDisableMaintenance)
+
 		// 模型指纹
 		admin.GET("/fingerprints", s.HandleListFingerprints)
 		admin.GET("/fingerprints/test-results", s.HandleListFingerprintTestResults)
@@ -940,6 +1228,7 @@ func (s *Server) SetupRoutes(r *gin.Engine) {
 		dashboard.GET("/logs", s.HandleErrors)
 		dashboard.GET("/logs/bootstrap", s.HandleLogsBootstrap)
 		dashboard.GET("/metrics", s.HandleMetrics)
+		dashboard.GET("/metrics/stream", s.HandleMetricsStream) // SSE 实时推送最近分桶
 		dashboard.GET("/stats", s.HandleStats)
 		dashboard.GET("/stats/filter-options", s.HandleStatsFilterOptions)
 		dashboard.GET("/models", s.HandleGetModels)
@@ -1024,16 +1313,22 @@ func (s *Server) AddLogAsync(entry *model.LogEntry) {
 	if entry != nil && entry.LogSource == "" {
 		entry.LogSource = model.LogSourceProxy
 	}
+	truncateLogEntryMessage(entry)
 
 	// 更新成本缓存（用于每日成本限额功能）
 	// 语义：缓存累加倍率后成本（effective），与 daily_cost_limit 直接比较
-	if s.costCache != nil && entry.ChannelID > 0 && entry.Cost > 0 && entry.LogSource == model.LogSourceProxy {
+	if entry != nil && entry.Cost > 0 && entry.LogSource == model.LogSourceProxy {
 		multiplier := entry.CostMultiplier
 		if multiplier < 0 {
 			multiplier = 1
 		}
 		// multiplier == 0 时成本为 0（免费渠道）
-		s.costCache.Add(entry.ChannelID, entry.Cost*multiplier)
+		if s.costCache != nil && entry.ChannelID > 0 {
+			s.costCache.Add(entry.ChannelID, entry.Cost*multiplier)
+		}
+		if s.modelBudget != nil {
+			s.modelBudget.Add(entry.Model, entry.Cost*multiplier)
+		}
 	}
 
 	// 委托给 LogService 处理日志写入
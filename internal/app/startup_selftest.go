@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// startupSelfTestTimeout 启动自检的查询超时
+const startupSelfTestTimeout = 5 * time.Second
+
+// StartupSelfTestResult 启动自检汇总
+type StartupSelfTestResult struct {
+	Channels int      // 渠道总数
+	Enabled  int      // 已启用渠道数
+	Usable   int      // 可用渠道数（已启用、有可用 Key、URL 有效、有模型）
+	Keys     int      // 已启用渠道的可用 Key 总数
+	Models   int      // 已启用渠道覆盖的模型数（去重）
+	Storage  string   // 存储状态（混合存储时包含主库连通性）
+	Problems []string // 已启用渠道的明显问题（如 "name(#1): 无可用 Key"）
+}
+
+// errNoUsableChannels 启动自检未发现任何可用渠道
+var errNoUsableChannels = errors.New("no usable channels")
+
+// RunStartupSelfTest 启动自检：汇总渠道/Key/模型/存储状态并打印日志，列出存在明显问题的已启用渠道。
+// requireChannels=true 时，读取渠道失败或没有任何可用渠道返回错误（由调用方决定是否终止启动）。
+func (s *Server) RunStartupSelfTest(ctx context.Context, requireChannels bool) (*StartupSelfTestResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, startupSelfTestTimeout)
+	defer cancel()
+
+	res := &StartupSelfTestResult{Storage: s.storageSelfTestStatus(ctx)}
+	configs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		log.Printf("[WARN] 启动自检读取渠道失败: %v", err)
+		if requireChannels {
+			return res, fmt.Errorf("list channels: %w", err)
+		}
+		return res, nil
+	}
+
+	res.Channels = len(configs)
+	var zeroKey []string
+	var perChannel []string // 逐条打印的问题（无 Key 渠道在下方汇总打印一次）
+	problem := func(msg string) {
+		res.Problems = append(res.Problems, msg)
+		perChannel = append(perChannel, msg)
+	}
+	models := make(map[string]struct{})
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		res.Enabled++
+		res.Keys += cfg.KeyCount
+
+		label := fmt.Sprintf("%s(#%d)", cfg.Name, cfg.ID)
+		usable := true
+		if cfg.KeyCount == 0 {
+			zeroKey = append(zeroKey, label)
+			res.Problems = append(res.Problems, label+": 无可用 Key")
+			usable = false
+		}
+		if _, err := validateChannelURLs(cfg.URL); err != nil {
+			problem(fmt.Sprintf("%s: URL 无效（%v）", label, err))
+			usable = false
+		}
+		if len(cfg.ModelEntries) == 0 {
+			problem(label + ": 未配置模型")
+			usable = false
+		}
+		for _, m := range cfg.GetModels() {
+			models[m] = struct{}{}
+		}
+		if usable {
+			res.Usable++
+		}
+	}
+	res.Models = len(models)
+
+	log.Printf("[INFO] 启动自检: 渠道 %d 个（启用 %d，可用 %d），Key %d 个，覆盖模型 %d 个，存储: %s",
+		res.Channels, res.Enabled, res.Usable, res.Keys, res.Models, res.Storage)
+	for _, p := range perChannel {
+		log.Printf("[WARN] 启动自检发现问题渠道: %s", p)
+	}
+	if len(zeroKey) > 0 {
+		hint := "设置 CCLOAD_SKIP_ZERO_KEY_CHANNELS=true 可在选择时跳过"
+		if s.skipZeroKeyChannels {
+			hint = "已在选择时跳过"
+		}
+		log.Printf("[WARN] %d 个已启用渠道没有可用的 API Key（%s）: %s", len(zeroKey), hint, strings.Join(zeroKey, ", "))
+	}
+
+	if requireChannels && res.Usable == 0 {
+		return res, errNoUsableChannels
+	}
+	return res, nil
+}
+
+// storageSelfTestStatus 存储状态摘要：混合存储检查主库连通性，其他模式仅标记为单库
+func (s *Server) storageSelfTestStatus(ctx context.Context) string {
+	syncer, ok := s.store.(hybridSyncer)
+	if !ok {
+		return "单库"
+	}
+	st := syncer.SyncStatus(ctx)
+	if !st.PrimaryReachable {
+		return fmt.Sprintf("混合存储（主库不可达: %s）", st.PrimaryError)
+	}
+	return "混合存储（主库正常）"
+}
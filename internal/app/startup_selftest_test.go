@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestRunStartupSelfTest(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	server := &Server{store: store}
+
+	// 空库：不要求渠道时仅汇总，要求时报错
+	res, err := server.RunStartupSelfTest(ctx, false)
+	if err != nil || res.Channels != 0 || res.Usable != 0 {
+		t.Fatalf("empty store: res=%+v err=%v", res, err)
+	}
+	if _, err := server.RunStartupSelfTest(ctx, true); !errors.Is(err, errNoUsableChannels) {
+		t.Fatalf("empty store with require: err=%v, want errNoUsableChannels", err)
+	}
+
+	for _, cfg := range []*model.Config{
+		{Name: "ok", URL: "https://a.example.com", Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}, {Model: "gpt-4o"}}},
+		{Name: "no-key", URL: "https://b.example.com", Enabled: true, ModelEntries: []model.ModelEntry{{Model: "claude-3"}}},
+		{Name: "bad-url", URL: "ftp://c.example.com", Enabled: true, ModelEntries: []model.ModelEntry{{Model: "gpt-4"}}},
+		{Name: "disabled", URL: "https://d.example.com", Enabled: false, ModelEntries: []model.ModelEntry{{Model: "gemini"}}},
+	} {
+		created, err := store.CreateConfig(ctx, cfg)
+		if err != nil {
+			t.Fatalf("CreateConfig: %v", err)
+		}
+		if cfg.Name == "no-key" {
+			continue
+		}
+		if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{{ChannelID: created.ID, KeyIndex: 0, APIKey: "sk-" + cfg.Name, KeyStrategy: model.KeyStrategySequential}}); err != nil {
+			t.Fatalf("CreateAPIKeysBatch: %v", err)
+		}
+	}
+
+	res, err = server.RunStartupSelfTest(ctx, true)
+	if err != nil {
+		t.Fatalf("RunStartupSelfTest: %v", err)
+	}
+	if res.Channels != 4 || res.Enabled != 3 || res.Usable != 1 || res.Keys != 2 || res.Models != 3 {
+		t.Fatalf("summary = %+v, want channels=4 enabled=3 usable=1 keys=2 models=3", res)
+	}
+	if res.Storage != "单库" {
+		t.Fatalf("storage = %q, want 单库", res.Storage)
+	}
+	joined := strings.Join(res.Problems, "\n")
+	if len(res.Problems) != 2 || !strings.Contains(joined, "no-key(#") || !strings.Contains(joined, "bad-url(#") {
+		t.Fatalf("problems = %v, want no-key and bad-url", res.Problems)
+	}
+}
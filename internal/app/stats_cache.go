@@ -239,6 +239,24 @@ func hashFilter(filter *model.LogFilter) string {
 	if filter.ModelLike != "" {
 		parts = append(parts, fmt.Sprintf("model_like:%s", filter.ModelLike))
 	}
+	if filter.PathLike != "" {
+		parts = append(parts, fmt.Sprintf("path_like:%s", filter.PathLike))
+	}
+	if filter.MinDurationMs != nil {
+		parts = append(parts, fmt.Sprintf("min_dur:%d", *filter.MinDurationMs))
+	}
+	if filter.MaxDurationMs != nil {
+		parts = append(parts, fmt.Sprintf("max_dur:%d", *filter.MaxDurationMs))
+	}
+	if filter.MinFirstByteMs != nil {
+		parts = append(parts, fmt.Sprintf("min_fb:%d", *filter.MinFirstByteMs))
+	}
+	if filter.SlowOnly {
+		parts = append(parts, "slow")
+	}
+	if filter.ErrorsOnly {
+		parts = append(parts, "errors")
+	}
 	if filter.AuthTokenID != nil {
 		parts = append(parts, fmt.Sprintf("auth:%d", *filter.AuthTokenID))
 	}
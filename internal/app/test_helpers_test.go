@@ -425,10 +425,12 @@ func newTestAuthService(t testing.TB) *AuthService {
 		authTokenCostLimits: make(map[string]tokenCostLimit),
 		authTokenMaxConns:   make(map[string]int),
 		authTokenActiveReqs: make(map[string]int),
+		authTokenRPMLimits:  make(map[string]int),
 		authTokenHashes:     make(map[int64]string),
 		validTokens:         make(map[string]model.WebSession),
 		lastUsedCh:          make(chan string, 256),
 		done:                make(chan struct{}),
+		tokenRPMLimiter:     newChannelRPMLimiter(nil),
 	}
 	t.Cleanup(s.Close) // 幂等关闭（closeOnce 保护）
 	return s
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ccLoad/internal/config"
+)
+
+// transportTuning 上游 HTTP Transport 连接池参数（仅环境变量，启动时读取）
+type transportTuning struct {
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	ForceHTTP2          bool
+}
+
+// defaultTransportTuning 默认值与未配置时的行为保持一致
+func defaultTransportTuning() transportTuning {
+	return transportTuning{
+		MaxIdleConnsPerHost: config.HTTPMaxIdleConnsPerHost,
+		IdleConnTimeout:     config.HTTPIdleConnTimeout,
+		ForceHTTP2:          true,
+	}
+}
+
+// parseTransportTuning 解析连接池环境变量；非法值记录警告并回退默认值
+//   - CCLOAD_HTTP_MAX_IDLE_CONNS_PER_HOST: 单 host 空闲连接数（正整数）
+//   - CCLOAD_HTTP_IDLE_CONN_TIMEOUT: 空闲连接超时（Go duration，如 90s、2m）
+//   - CCLOAD_HTTP_FORCE_HTTP2: 是否尝试 HTTP/2（0=仅 HTTP/1.1，1=启用）
+func parseTransportTuning(getenv func(string) string) transportTuning {
+	t := defaultTransportTuning()
+
+	if v := strings.TrimSpace(getenv("CCLOAD_HTTP_MAX_IDLE_CONNS_PER_HOST")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			t.MaxIdleConnsPerHost = n
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_HTTP_MAX_IDLE_CONNS_PER_HOST=%s（必须为正整数），使用默认值 %d", v, t.MaxIdleConnsPerHost)
+		}
+	}
+	if v := strings.TrimSpace(getenv("CCLOAD_HTTP_IDLE_CONN_TIMEOUT")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			t.IdleConnTimeout = d
+		} else {
+			log.Printf("[WARN] 无效的 CCLOAD_HTTP_IDLE_CONN_TIMEOUT=%s（必须为正的时长，如 90s），使用默认值 %s", v, t.IdleConnTimeout)
+		}
+	}
+	if v := strings.TrimSpace(getenv("CCLOAD_HTTP_FORCE_HTTP2")); v != "" {
+		switch v {
+		case "1":
+			t.ForceHTTP2 = true
+		case "0":
+			t.ForceHTTP2 = false
+		default:
+			log.Printf("[WARN] 无效的 CCLOAD_HTTP_FORCE_HTTP2=%s（只能为 0 或 1），使用默认值 1", v)
+		}
+	}
+	return t
+}
+
+// maxConnsPerHost 单 host 最大连接数不低于空闲连接数，否则调大的空闲池无法被填满
+func (t transportTuning) maxConnsPerHost() int {
+	return max(config.HTTPMaxConnsPerHost, t.MaxIdleConnsPerHost)
+}
+
+// maxIdleConns 全局空闲池不低于单 host 空闲连接数
+func (t transportTuning) maxIdleConns() int {
+	return max(config.HTTPMaxIdleConns, t.MaxIdleConnsPerHost)
+}
+
+// getTransportTuning 与 getHostOverrides 相同，延迟到首次调用时读取（.env 已加载）
+var getTransportTuning = sync.OnceValue(func() transportTuning {
+	return parseTransportTuning(os.Getenv)
+})
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"ccLoad/internal/config"
+)
+
+func TestParseTransportTuning(t *testing.T) {
+	envOf := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+
+	t.Run("defaults match previous behavior", func(t *testing.T) {
+		got := parseTransportTuning(envOf(nil))
+		if got != defaultTransportTuning() {
+			t.Fatalf("got %+v, want defaults", got)
+		}
+		if got.MaxIdleConnsPerHost != config.HTTPMaxIdleConnsPerHost || got.IdleConnTimeout != 90*time.Second || !got.ForceHTTP2 {
+			t.Fatalf("unexpected defaults: %+v", got)
+		}
+	})
+
+	t.Run("valid overrides", func(t *testing.T) {
+		got := parseTransportTuning(envOf(map[string]string{
+			"CCLOAD_HTTP_MAX_IDLE_CONNS_PER_HOST": "128",
+			"CCLOAD_HTTP_IDLE_CONN_TIMEOUT":       "2m",
+			"CCLOAD_HTTP_FORCE_HTTP2":             "0",
+		}))
+		want := transportTuning{MaxIdleConnsPerHost: 128, IdleConnTimeout: 2 * time.Minute, ForceHTTP2: false}
+		if got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+		if got.maxConnsPerHost() < 128 || got.maxIdleConns() < 128 {
+			t.Fatalf("pool limits must cover per-host idle size: max_conns=%d max_idle=%d", got.maxConnsPerHost(), got.maxIdleConns())
+		}
+	})
+
+	t.Run("invalid values fall back", func(t *testing.T) {
+		got := parseTransportTuning(envOf(map[string]string{
+			"CCLOAD_HTTP_MAX_IDLE_CONNS_PER_HOST": "-1",
+			"CCLOAD_HTTP_IDLE_CONN_TIMEOUT":       "90",
+			"CCLOAD_HTTP_FORCE_HTTP2":             "yes",
+		}))
+		if got != defaultTransportTuning() {
+			t.Fatalf("got %+v, want defaults", got)
+		}
+	})
+}
+
+func TestHandleTransportSettings(t *testing.T) {
+	srv := &Server{
+		client: &http.Client{Transport: &http.Transport{
+			MaxIdleConns:        200,
+			MaxIdleConnsPerHost: 64,
+			MaxConnsPerHost:     64,
+			IdleConnTimeout:     45 * time.Second,
+			ForceAttemptHTTP2:   true,
+		}},
+	}
+
+	c, w := newTestContext(t, newRequest(http.MethodGet, "/admin/transport", nil))
+	srv.HandleTransportSettings(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	resp := mustParseAPIResponse[TransportSettings](t, w.Body.Bytes())
+	want := TransportSettings{MaxIdleConns: 200, MaxIdleConnsPerHost: 64, MaxConnsPerHost: 64, IdleConnTimeoutSec: 45, ForceHTTP2: true}
+	if resp.Data != want {
+		t.Fatalf("got %+v, want %+v", resp.Data, want)
+	}
+}
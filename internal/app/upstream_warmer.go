@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// upstreamWarmTimeout 单次预热请求超时（仅用于建立/保持连接，不关心响应内容）
+	upstreamWarmTimeout = 5 * time.Second
+	// upstreamWarmConcurrency 单轮预热的最大并发
+	upstreamWarmConcurrency = 8
+	// upstreamWarmBodyLimit 预热响应最多读取的字节数（读完小响应体才能让连接回到空闲池）
+	upstreamWarmBodyLimit = 64 << 10
+)
+
+// parseWarmUpstreamsInterval 解析 CCLOAD_WARM_UPSTREAMS（秒，默认0=关闭）
+func parseWarmUpstreamsInterval(raw string) time.Duration {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	sec, err := strconv.Atoi(raw)
+	if err != nil || sec < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_WARM_UPSTREAMS=%s（必须为非负整数秒），已关闭连接预热", raw)
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// startUpstreamWarmerLoop 定期向已启用渠道的上游主机发送 HEAD 请求，保持连接池中的 TCP/TLS 连接处于热状态
+// 间歇性流量下可避免空闲后首个请求重新握手带来的首字节延迟
+func (s *Server) startUpstreamWarmerLoop(interval time.Duration) {
+	if s == nil || interval <= 0 {
+		return
+	}
+	log.Printf("[INFO] 上游连接预热已启用：间隔=%s", interval)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.shutdownCh:
+				log.Print("[INFO] 上游连接预热已停止")
+				return
+			case <-ticker.C:
+				s.warmUpstreams(s.baseCtx)
+			}
+		}
+	}()
+}
+
+// warmUpstreamTarget 预热目标：同一 HTTP 客户端（全局或渠道代理）下的同一上游源站只预热一次
+type warmUpstreamTarget struct {
+	client *http.Client
+	origin string
+}
+
+// warmUpstreams 执行一轮预热，返回成功建立往返的目标数
+// 请求直接走渠道的 HTTP 客户端，不占用渠道 RPM/并发额度，也不影响冷却与健康统计
+func (s *Server) warmUpstreams(ctx context.Context) int {
+	configs, err := s.store.ListConfigs(ctx)
+	if err != nil {
+		log.Printf("[WARN] 上游连接预热：读取渠道失败: %v", err)
+		return 0
+	}
+
+	seen := make(map[warmUpstreamTarget]struct{})
+	targets := make([]warmUpstreamTarget, 0, len(configs))
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		client := s.getClientForChannel(cfg)
+		for _, rawURL := range cfg.GetURLs() {
+			u, err := url.Parse(rawURL)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				continue
+			}
+			target := warmUpstreamTarget{client: client, origin: u.Scheme + "://" + u.Host + "/"}
+			if _, dup := seen[target]; dup {
+				continue
+			}
+			seen[target] = struct{}{}
+			targets = append(targets, target)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		warmed int
+	)
+	sem := make(chan struct{}, upstreamWarmConcurrency)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if warmUpstream(ctx, target) {
+				mu.Lock()
+				warmed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return warmed
+}
+
+// warmUpstream 对源站发送 HEAD 请求；任意状态码都说明连接可用，响应体读完后连接回到空闲池
+func warmUpstream(ctx context.Context, target warmUpstreamTarget) bool {
+	ctx, cancel := context.WithTimeout(ctx, upstreamWarmTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.origin, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := target.client.Do(req)
+	if err != nil {
+		return false
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, upstreamWarmBodyLimit)
+	_ = resp.Body.Close()
+	return true
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestParseWarmUpstreamsInterval(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]time.Duration{
+		"":    0,
+		"0":   0,
+		"30":  30 * time.Second,
+		" 5 ": 5 * time.Second,
+		"-1":  0,
+		"abc": 0,
+	}
+	for raw, want := range cases {
+		if got := parseWarmUpstreamsInterval(raw); got != want {
+			t.Errorf("parseWarmUpstreamsInterval(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestWarmUpstreams_HeadsEachEnabledOriginOnce(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	hits := make(map[string][]string)
+	record := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name] = append(hits[name], r.Method+" "+r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNotFound) // 任意状态码都视为预热成功
+		})
+	}
+	shared := newTestHTTPServer(t, record("shared"))
+	other := newTestHTTPServer(t, record("other"))
+	disabled := newTestHTTPServer(t, record("disabled"))
+
+	srv := newInMemoryServer(t)
+	ctx := context.Background()
+	for _, cfg := range []*model.Config{
+		{Name: "a", URL: shared.URL + "/v1", Enabled: true},
+		{Name: "b", URL: shared.URL + "/openai\n" + other.URL, Enabled: true},
+		{Name: "c", URL: disabled.URL, Enabled: false},
+	} {
+		cfg.Priority = 1
+		cfg.ModelEntries = []model.ModelEntry{{Model: "m"}}
+		if _, err := srv.store.CreateConfig(ctx, cfg); err != nil {
+			t.Fatalf("CreateConfig: %v", err)
+		}
+	}
+
+	if warmed := srv.warmUpstreams(ctx); warmed != 2 {
+		t.Fatalf("warmed=%d, want 2", warmed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := hits["shared"]; len(got) != 1 || got[0] != "HEAD /" {
+		t.Fatalf("shared origin hits=%v, want one HEAD /", got)
+	}
+	if got := hits["other"]; len(got) != 1 {
+		t.Fatalf("other origin hits=%v, want 1", got)
+	}
+	if got := hits["disabled"]; len(got) != 0 {
+		t.Fatalf("disabled channel must not be warmed, hits=%v", got)
+	}
+}
@@ -15,6 +15,8 @@ const webIdentityContextKey = "ccLoad.webIdentity"
 type WebIdentity struct {
 	Role        model.WebRole `json:"role"`
 	AuthTokenID int64         `json:"auth_token_id,omitempty"`
+	// SessionID 会话 Token 哈希前缀，用于审计日志区分不同管理员会话（不可反推 Token）
+	SessionID string `json:"-"`
 }
 
 // WebIdentityFromContext returns the authenticated Web identity.
@@ -64,5 +66,6 @@ func (s *AuthService) HandleWebSession(c *gin.Context) {
 		"cost_used_usd":   token.CostUsedUSD(),
 		"cost_limit_usd":  token.CostLimitUSD(),
 		"max_concurrency": token.MaxConcurrency,
+		"rpm_limit":       token.RPMLimit,
 	})
 }
@@ -16,6 +16,9 @@ const (
 
 	// DefaultMaxImageBodyBytes Images API 默认最大请求体字节数（支持图片上传）
 	DefaultMaxImageBodyBytes = 20 * 1024 * 1024 // 20MB
+
+	// DefaultDrainTimeout 关闭前排空阶段的最长等待时间（可通过 CCLOAD_DRAIN_TIMEOUT 覆盖，单位秒）
+	DefaultDrainTimeout = 30 * time.Second
 )
 
 // HTTP客户端配置常量
@@ -40,6 +43,9 @@ const (
 	// 20：允许更多连接复用，减少连接建立延迟
 	HTTPMaxIdleConnsPerHost = 20
 
+	// HTTPIdleConnTimeout 空闲连接超时（空闲90秒后关闭，避免僵尸连接）
+	HTTPIdleConnTimeout = 90 * time.Second
+
 	// HTTPMaxConnsPerHost 单host最大连接数
 	HTTPMaxConnsPerHost = 50
 
@@ -74,6 +80,14 @@ const (
 
 	// LogFlushRetryBackoff 重试退避基准时间
 	LogFlushRetryBackoff = 100 * time.Millisecond
+
+	// DefaultLogMessageMaxLen 日志 message 默认截断长度（可通过 CCLOAD_LOG_MSG_MAX_LEN 覆盖）
+	DefaultLogMessageMaxLen = 512
+
+	// MinLogMessageMaxLen / MaxLogMessageMaxLen CCLOAD_LOG_MSG_MAX_LEN 允许范围
+	// 上限与 error_detail 截断长度一致：message 再长就失去了"摘要"的意义
+	MinLogMessageMaxLen = 64
+	MaxLogMessageMaxLen = 8192
 )
 
 // Token认证配置常量
@@ -86,6 +100,9 @@ const (
 
 	// TokenCleanupInterval Token清理间隔
 	TokenCleanupInterval = 1 * time.Hour
+
+	// KeyLastUsedFlushInterval Key 最后使用时间批量落库间隔（成功路径只写内存）
+	KeyLastUsedFlushInterval = 30 * time.Second
 )
 
 // Token统计配置常量
@@ -39,6 +39,8 @@ type ErrorInput struct {
 	IsNetworkError bool
 	ModelScoped    bool // 网络错误是否只影响当前实际模型
 	Headers        map[string][]string
+	// ForceRetryChannel 错误体命中渠道 retry_error_substrings：客户端级错误升级为渠道级
+	ForceRetryChannel bool
 }
 
 // ConfigGetter 获取渠道配置的接口（支持缓存）
@@ -53,6 +55,10 @@ type ConfigGetter interface {
 type Manager struct {
 	store        storage.Store
 	configGetter ConfigGetter // 可选：优先使用缓存层（性能提升~60%）
+
+	// 熔断：连续 breakerThreshold 个冷却周期无成功时，冷却延长至 breakerReset（threshold<=0 禁用）
+	breakerThreshold int
+	breakerReset     time.Duration
 }
 
 type cooldownDecision struct {
@@ -77,6 +83,44 @@ func NewManager(store storage.Store, configGetter ConfigGetter) *Manager {
 	}
 }
 
+// SetCircuitBreaker 配置渠道熔断（threshold<=0 或 reset<=0 禁用）
+func (m *Manager) SetCircuitBreaker(threshold int, reset time.Duration) {
+	m.breakerThreshold = threshold
+	m.breakerReset = reset
+}
+
+// CircuitBreakerThreshold 返回熔断阈值（0=禁用；nil Manager 视为禁用）
+func (m *Manager) CircuitBreakerThreshold() int {
+	if m == nil || m.breakerReset <= 0 {
+		return 0
+	}
+	return m.breakerThreshold
+}
+
+// CircuitOpenAt 渠道在 now 时刻是否处于熔断中：熔断启用、连续失败达到阈值且渠道冷却尚未到期。
+// 熔断通过延长渠道冷却实现，冷却到期即视为放行试探，与冷却状态同样按当前时间判定。
+func (m *Manager) CircuitOpenAt(consecutiveFailures int, cooldownUntil, now time.Time) bool {
+	threshold := m.CircuitBreakerThreshold()
+	return threshold > 0 && consecutiveFailures >= threshold && cooldownUntil.After(now)
+}
+
+// tryOpenCircuit 指数退避后检查熔断阈值，达到则将渠道冷却延长至熔断重置时间
+func (m *Manager) tryOpenCircuit(ctx context.Context, channelID int64) {
+	if m.breakerThreshold <= 0 || m.breakerReset <= 0 {
+		return
+	}
+	until := time.Now().Add(m.breakerReset)
+	opened, err := m.store.OpenChannelCircuit(ctx, channelID, m.breakerThreshold, until)
+	if err != nil {
+		log.Printf("[WARN] 打开渠道熔断失败 (channel=%d): %v", channelID, err)
+		return
+	}
+	if opened {
+		log.Printf("[COOLDOWN] 渠道熔断: 渠道=%d 连续 %d 个冷却周期无成功，禁用至 %s（手动测试成功可提前恢复）",
+			channelID, m.breakerThreshold, until.Format("2006-01-02 15:04:05"))
+	}
+}
+
 func (m *Manager) classifyDecision(in ErrorInput) cooldownDecision {
 	var errLevel util.ErrorLevel
 
@@ -141,6 +185,9 @@ func (m *Manager) classifyDecision(in ErrorInput) cooldownDecision {
 				decision.modelCooldownUntil = time.Now().Add(util.DefaultModelCooldownDuration)
 			}
 		}
+		if in.ForceRetryChannel && errLevel == util.ErrorLevelClient {
+			errLevel = util.ErrorLevelChannel
+		}
 	}
 
 	// 2. 仅给出动作决策（不产生副作用）
@@ -260,6 +307,8 @@ func (m *Manager) HandleError(ctx context.Context, in ErrorInput) Action {
 			// 设计原则: 数据库故障不应阻塞用户请求,系统应降级服务
 			// 影响: 可能导致短暂的冷却状态不一致,但总比拒绝服务更好
 			log.Printf("[WARN] 更新渠道冷却失败 (channel=%d): %v", channelID, err)
+		} else {
+			m.tryOpenCircuit(ctx, channelID)
 		}
 		return ActionRetryChannel
 
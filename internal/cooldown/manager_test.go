@@ -70,6 +70,30 @@ func TestHandleError_ClientError(t *testing.T) {
 	}
 }
 
+// TestHandleError_ForceRetryChannel 命中渠道强制重试子串时，客户端级错误升级为渠道级
+func TestHandleError_ForceRetryChannel(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	manager := NewManager(store, nil)
+	ctx := context.Background()
+
+	cfg := createTestChannel(t, store, "test-force-retry")
+	in := ErrorInput{
+		ChannelID:  cfg.ID,
+		KeyIndex:   0,
+		StatusCode: 406,
+		ErrorBody:  []byte(`{"error":"upstream overloaded"}`),
+	}
+
+	if action := manager.DecideAction(ctx, in); action != ActionReturnClient {
+		t.Fatalf("without force: expected ActionReturnClient, got %v", action)
+	}
+	in.ForceRetryChannel = true
+	if action := manager.HandleError(ctx, in); action != ActionRetryChannel {
+		t.Fatalf("with force: expected ActionRetryChannel, got %v", action)
+	}
+}
+
 // TestHandleError_KeyLevelError 测试Key级错误处理
 func TestHandleError_KeyLevelError(t *testing.T) {
 	store, cleanup := setupTestStore(t)
@@ -781,6 +805,88 @@ func TestClearChannelCooldown(t *testing.T) {
 	}
 }
 
+// TestHandleError_CircuitBreakerOpensAfterConsecutiveCycles 连续冷却周期无成功后熔断
+func TestHandleError_CircuitBreakerOpensAfterConsecutiveCycles(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	manager := NewManager(store, nil)
+	manager.SetCircuitBreaker(3, time.Hour)
+	ctx := context.Background()
+
+	cfg := createTestChannel(t, store, "test-circuit-breaker")
+	fail := func() {
+		t.Helper()
+		action := manager.HandleError(ctx, ErrorInput{ChannelID: cfg.ID, KeyIndex: -1, StatusCode: 502})
+		if action != ActionRetryChannel {
+			t.Fatalf("expected ActionRetryChannel, got %v", action)
+		}
+	}
+	expireCooldown := func() {
+		t.Helper()
+		if err := store.SetChannelCooldown(ctx, cfg.ID, time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("expire cooldown: %v", err)
+		}
+	}
+
+	// 同一冷却周期内的重复失败只计一次
+	fail()
+	fail()
+	channelCfg, _ := store.GetConfig(ctx, cfg.ID)
+	if channelCfg.ConsecutiveFailures != 1 {
+		t.Fatalf("ConsecutiveFailures=%d, want 1", channelCfg.ConsecutiveFailures)
+	}
+
+	expireCooldown()
+	fail()
+	channelCfg, _ = store.GetConfig(ctx, cfg.ID)
+	if remaining := time.Until(time.Unix(channelCfg.CooldownUntil, 0)); remaining > 30*time.Minute {
+		t.Fatalf("circuit opened too early: remaining=%v failures=%d", remaining, channelCfg.ConsecutiveFailures)
+	}
+
+	expireCooldown()
+	fail()
+	channelCfg, _ = store.GetConfig(ctx, cfg.ID)
+	if channelCfg.ConsecutiveFailures != 3 {
+		t.Fatalf("ConsecutiveFailures=%d, want 3", channelCfg.ConsecutiveFailures)
+	}
+	if remaining := time.Until(time.Unix(channelCfg.CooldownUntil, 0)); remaining < 59*time.Minute {
+		t.Fatalf("circuit should hold the channel for the reset timeout, remaining=%v", remaining)
+	}
+
+	// 成功（或手动测试通过）清零计数并关闭熔断
+	if err := manager.ClearChannelCooldown(ctx, cfg.ID); err != nil {
+		t.Fatalf("ClearChannelCooldown failed: %v", err)
+	}
+	channelCfg, _ = store.GetConfig(ctx, cfg.ID)
+	if channelCfg.ConsecutiveFailures != 0 || channelCfg.CooldownUntil != 0 {
+		t.Fatalf("expected breaker reset, got failures=%d until=%d", channelCfg.ConsecutiveFailures, channelCfg.CooldownUntil)
+	}
+}
+
+func TestCircuitOpenAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	manager := NewManager(nil, nil)
+	if manager.CircuitOpenAt(5, now.Add(time.Hour), now) {
+		t.Fatal("breaker disabled by default must never report open")
+	}
+
+	manager.SetCircuitBreaker(3, time.Hour)
+	if !manager.CircuitOpenAt(3, now.Add(time.Hour), now) {
+		t.Fatal("expected open circuit while cooldown is active")
+	}
+	if manager.CircuitOpenAt(3, now.Add(-time.Second), now) {
+		t.Fatal("expired cooldown must not report open circuit")
+	}
+	if manager.CircuitOpenAt(2, now.Add(time.Hour), now) {
+		t.Fatal("below threshold must not report open circuit")
+	}
+	if (*Manager)(nil).CircuitOpenAt(3, now.Add(time.Hour), now) {
+		t.Fatal("nil manager must report closed circuit")
+	}
+}
+
 // TestClearKeyCooldown 测试清除Key冷却
 func TestClearKeyCooldown(t *testing.T) {
 	store, cleanup := setupTestStore(t)
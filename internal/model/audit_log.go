@@ -0,0 +1,19 @@
+package model
+
+// AuditChange 单个字段的变更前后值（新建时 Old 为空，删除时 New 为空）
+type AuditChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditLog 管理端变更审计记录
+type AuditLog struct {
+	ID         int64                  `json:"id"`
+	Time       JSONTime               `json:"time"`
+	Actor      string                 `json:"actor"`     // 操作者：admin 会话标识（会话 token 哈希前缀）
+	ClientIP   string                 `json:"client_ip"` // 操作来源IP
+	Action     string                 `json:"action"`    // 方法 + 路由模板，如 "PUT /admin/channels/:id"
+	ChannelID  int64                  `json:"channel_id,omitempty"`
+	StatusCode int                    `json:"status_code"`
+	Changes    map[string]AuditChange `json:"changes,omitempty"` // 字段级 diff（仅渠道/冷却变更记录）
+}
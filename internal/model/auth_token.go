@@ -65,6 +65,7 @@ type AuthToken struct {
 
 	// 并发限制（2026-04新增）
 	MaxConcurrency int `json:"max_concurrency"` // 最大并发请求数，0表示无限制
+	RPMLimit       int `json:"rpm_limit"`       // 每分钟最大请求数（滑动窗口），0表示无限制
 }
 
 // 渠道限制模式常量
@@ -237,6 +238,9 @@ func (t *AuthToken) ValidateUsageLimits() error {
 	if t.MaxConcurrency < 0 {
 		return errors.New("max_concurrency must be >= 0")
 	}
+	if t.RPMLimit < 0 {
+		return errors.New("rpm_limit must be >= 0")
+	}
 	if t.CostLimitMicroUSD > 0 && t.MaxConcurrency <= 0 {
 		return errors.New("cost-limited auth token requires max_concurrency > 0")
 	}
@@ -273,6 +277,7 @@ type authTokenJSON struct {
 	AllowedChannelIDs        []int64   `json:"allowed_channel_ids,omitempty"`
 	ChannelRestrictionMode   string    `json:"channel_restriction_mode,omitempty"`
 	MaxConcurrency           int       `json:"max_concurrency"`
+	RPMLimit                 int       `json:"rpm_limit"`
 }
 
 // MarshalJSON 自定义JSON序列化，将MicroUSD转换为USD浮点数
@@ -311,5 +316,6 @@ func (t AuthToken) MarshalJSON() ([]byte, error) {
 		AllowedChannelIDs:        t.AllowedChannelIDs,
 		ChannelRestrictionMode:   channelRestrictionMode,
 		MaxConcurrency:           t.MaxConcurrency,
+		RPMLimit:                 t.RPMLimit,
 	})
 }
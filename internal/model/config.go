@@ -1,14 +1,19 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	protocolpkg "ccLoad/internal/protocol"
+	"ccLoad/internal/util"
 )
 
 const (
@@ -72,6 +77,7 @@ const (
 	RuleActionRemove   = "remove"
 	RuleActionOverride = "override"
 	RuleActionAppend   = "append"
+	RuleActionDefault  = "default" // 仅 body：客户端未设置该字段时才写入
 )
 
 // CustomHeaderRule 单条自定义 HTTP 请求头规则
@@ -83,7 +89,7 @@ type CustomHeaderRule struct {
 
 // CustomBodyRule 单条自定义 JSON 请求体规则
 type CustomBodyRule struct {
-	Action string          `json:"action"`          // remove | override
+	Action string          `json:"action"`          // remove | override | default
 	Path   string          `json:"path"`            // 点分路径，支持整数数组索引
 	Value  json.RawMessage `json:"value,omitempty"` // remove 时忽略；任意 JSON 字面量
 }
@@ -102,11 +108,14 @@ func (r *CustomRequestRules) IsEmpty() bool {
 	return len(r.Headers) == 0 && len(r.Body) == 0
 }
 
+// ErrChannelNotFound 渠道不存在（区分于存储故障）
+var ErrChannelNotFound = errors.New("channel not found")
+
 // Config 渠道配置
 type Config struct {
 	ID                    int64    `json:"id"`
 	Name                  string   `json:"name"`
-	ChannelType           string   `json:"channel_type"` // 渠道类型: "anthropic" | "codex" | "openai" | "gemini"，默认anthropic
+	ChannelType           string   `json:"channel_type"` // 渠道类型: "anthropic" | "codex" | "openai" | "gemini" | "azure"，默认anthropic
 	ProtocolTransformMode string   `json:"protocol_transform_mode,omitempty"`
 	ProtocolTransforms    []string `json:"protocol_transforms,omitempty"`
 	URL                   string   `json:"url"`
@@ -124,6 +133,15 @@ type Config struct {
 	CooldownUntil      int64 `json:"cooldown_until"`       // Unix秒时间戳，0表示无冷却
 	CooldownDurationMs int64 `json:"cooldown_duration_ms"` // 冷却持续时间（毫秒）
 
+	// 熔断计数：连续进入冷却周期且期间无一次成功的次数（成功或手动测试通过后清零）
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// 维护模式：不参与渠道选择且冻结冷却（不再累加退避），退出维护时清空全部冷却状态
+	Maintenance bool `json:"maintenance"`
+
+	// 最后一次成功代理的时间（Unix毫秒，0=从未使用；批量异步落库，存在分钟级滞后）
+	LastUsedAt int64 `json:"last_used_at"`
+
 	// 每日成本限额
 	DailyCostLimit float64 `json:"daily_cost_limit"` // 每日成本限额（美元），0表示无限制
 
@@ -136,6 +154,37 @@ type Config struct {
 	// 渠道级代理（http/https/socks5/socks5h），空串=环境变量代理
 	ProxyURL string `json:"proxy_url,omitempty"`
 
+	// 允许的客户端 HTTP 方法（大写），空=不限制
+	AllowedMethods []string `json:"allowed_methods,omitempty"`
+
+	// 模型重定向仅用于路由/日志/计费，上游请求保留客户端原始模型名
+	RedirectRoutingOnly bool `json:"redirect_routing_only,omitempty"`
+
+	// 渠道级 token 上限（0=无限制）：估算输入超限的请求跳过该渠道，输出上限用于封顶 max_tokens
+	MaxInputTokens  int `json:"max_input_tokens,omitempty"`
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
+	// 启用上游响应缓存：相同模型+请求体的非流式 POST 请求在 TTL 内直接返回缓存响应
+	Cacheable bool `json:"cacheable,omitempty"`
+
+	// 响应中的 model 字段还原为客户端请求的模型名（模型重定向时生效，流式与非流式均处理）
+	RestoreResponseModel bool `json:"restore_response_model,omitempty"`
+
+	// 视为成功的上游状态码（如 "200-299,404"），空=默认 2xx
+	SuccessCodes string `json:"success_codes,omitempty"`
+
+	// 启用时段（如 "Mon-Fri 22:00-08:00 Asia/Shanghai"），空=全天可用；时段外的渠道不参与选择
+	ActiveSchedule string `json:"active_schedule,omitempty"`
+
+	// 屏蔽的模型（即使模型列表包含也不参与该模型的选择），空=不屏蔽
+	BlockedModels []string `json:"blocked_models,omitempty"`
+
+	// 额外剥离的客户端请求头（在全局默认剥离之外），支持 "X-Internal-*" 前缀匹配，空=仅全局默认
+	StripHeaders []string `json:"strip_headers,omitempty"`
+
+	// 强制重试的上游错误体子串（不区分大小写）：命中时按渠道级错误切换渠道，即使状态码本身不会重试
+	RetryErrorSubstrings []string `json:"retry_error_substrings,omitempty"`
+
 	CreatedAt JSONTime `json:"created_at"` // 使用JSONTime确保序列化格式一致（RFC3339）
 	UpdatedAt JSONTime `json:"updated_at"` // 使用JSONTime确保序列化格式一致（RFC3339）
 
@@ -148,11 +197,27 @@ type Config struct {
 	// 模型查找索引（懒加载，不序列化）
 	modelIndex map[string]*ModelEntry `json:"-"`
 	indexMu    sync.RWMutex           `json:"-"` // 保护索引的并发访问
+
+	// 启用时段/成功状态码解析缓存（按原始字符串懒加载，字段变更后自动重建）
+	scheduleCache     atomic.Pointer[activeScheduleCache] `json:"-"`
+	successCodesCache atomic.Pointer[successCodesCache]   `json:"-"`
+}
+
+// successCodesCache SuccessCodes 的解析结果；ranges 为空表示为空或无法解析（回退 2xx）
+type successCodesCache struct {
+	raw    string
+	ranges []StatusCodeRange
+}
+
+// activeScheduleCache ActiveSchedule 的解析结果；schedule 为 nil 表示为空或无法解析（视为全天可用）
+type activeScheduleCache struct {
+	raw      string
+	schedule *ActiveSchedule
 }
 
 // Clone 返回 Config 的深拷贝。
-// 拷贝所有可变字段（ModelEntries / ProtocolTransforms slice），
-// 重置懒加载索引（modelIndex + indexMu），避免共享 sync.RWMutex 与指向旧 slice 的 map。
+// 拷贝所有可变字段（ModelEntries / ProtocolTransforms / AllowedMethods slice），
+// 重置懒加载索引（modelIndex + indexMu）与解析缓存，避免共享 sync.RWMutex 与指向旧 slice 的 map。
 func (c *Config) Clone() *Config {
 	if c == nil {
 		return nil
@@ -172,10 +237,24 @@ func (c *Config) Clone() *Config {
 		ScheduledCheckModel:   c.ScheduledCheckModel,
 		CooldownUntil:         c.CooldownUntil,
 		CooldownDurationMs:    c.CooldownDurationMs,
+		ConsecutiveFailures:   c.ConsecutiveFailures,
+		Maintenance:           c.Maintenance,
+		LastUsedAt:            c.LastUsedAt,
 		DailyCostLimit:        c.DailyCostLimit,
 		CostMultiplier:        c.CostMultiplier,
 		CustomRequestRules:    c.CustomRequestRules,
 		ProxyURL:              c.ProxyURL,
+		AllowedMethods:        append([]string(nil), c.AllowedMethods...),
+		RedirectRoutingOnly:   c.RedirectRoutingOnly,
+		MaxInputTokens:        c.MaxInputTokens,
+		MaxOutputTokens:       c.MaxOutputTokens,
+		Cacheable:             c.Cacheable,
+		RestoreResponseModel:  c.RestoreResponseModel,
+		SuccessCodes:          c.SuccessCodes,
+		ActiveSchedule:        c.ActiveSchedule,
+		BlockedModels:         append([]string(nil), c.BlockedModels...),
+		StripHeaders:          append([]string(nil), c.StripHeaders...),
+		RetryErrorSubstrings:  append([]string(nil), c.RetryErrorSubstrings...),
 		CreatedAt:             c.CreatedAt,
 		UpdatedAt:             c.UpdatedAt,
 		KeyCount:              c.KeyCount,
@@ -202,7 +281,7 @@ func (c *Config) GetProtocolTransforms() []string {
 	if len(c.ProtocolTransforms) == 0 {
 		return nil
 	}
-	base := c.GetChannelType()
+	base := c.GetProtocol()
 	mode := c.GetProtocolTransformMode()
 	seen := make(map[string]struct{}, len(c.ProtocolTransforms))
 	transforms := make([]string, 0, len(c.ProtocolTransforms))
@@ -237,12 +316,12 @@ func (c *Config) GetProtocolTransformMode() string {
 func (c *Config) ResolveUpstreamProtocol(clientProtocol string) string {
 	clientProtocol = strings.TrimSpace(strings.ToLower(clientProtocol))
 	if clientProtocol == "" {
-		return c.GetChannelType()
+		return c.GetProtocol()
 	}
 	if c.GetProtocolTransformMode() == ProtocolTransformModeUpstream && c.SupportsProtocol(clientProtocol) {
 		return clientProtocol
 	}
-	return c.GetChannelType()
+	return c.GetProtocol()
 }
 
 // SupportsProtocol 检查渠道是否暴露指定客户端协议。
@@ -251,15 +330,217 @@ func (c *Config) SupportsProtocol(protocol string) bool {
 	if protocol == "" {
 		return false
 	}
-	if c.GetChannelType() == protocol {
+	if c.GetProtocol() == protocol {
 		return true
 	}
 	return slices.Contains(c.GetProtocolTransforms(), protocol)
 }
 
+// AllowsMethod 检查渠道是否允许指定客户端 HTTP 方法（AllowedMethods 为空时全部允许）
+func (c *Config) AllowsMethod(method string) bool {
+	if len(c.AllowedMethods) == 0 {
+		return true
+	}
+	return slices.Contains(c.AllowedMethods, strings.ToUpper(strings.TrimSpace(method)))
+}
+
+// BlocksModel 检查模型是否在渠道屏蔽列表中（不区分大小写）
+func (c *Config) BlocksModel(modelName string) bool {
+	for _, blocked := range c.BlockedModels {
+		if strings.EqualFold(blocked, modelName) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeBlockedModels 规范化屏蔽模型列表：去空白、去重（不区分大小写，保留首次出现），保持原顺序
+func NormalizeBlockedModels(models []string) []string {
+	return normalizeFoldedList(models)
+}
+
+// StripsHeader 检查客户端请求头是否在渠道剥离列表中（不区分大小写，"*" 结尾表示前缀匹配）
+func (c *Config) StripsHeader(name string) bool {
+	for _, pattern := range c.StripHeaders {
+		if util.MatchPrefixPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeStripHeaders 规范化剥离请求头列表：去空白、去重（不区分大小写，保留首次出现），保持原顺序
+func NormalizeStripHeaders(headers []string) []string {
+	return normalizeFoldedList(headers)
+}
+
+// MatchesRetryError 上游错误体是否包含任一强制重试子串（不区分大小写）
+func (c *Config) MatchesRetryError(body []byte) bool {
+	if len(c.RetryErrorSubstrings) == 0 || len(body) == 0 {
+		return false
+	}
+	lowerBody := bytes.ToLower(body)
+	for _, sub := range c.RetryErrorSubstrings {
+		if bytes.Contains(lowerBody, []byte(strings.ToLower(sub))) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeRetryErrorSubstrings 规范化强制重试子串：去空白、去重（不区分大小写，保留首次出现），保持原顺序
+func NormalizeRetryErrorSubstrings(items []string) []string {
+	return normalizeFoldedList(items)
+}
+
+// normalizeFoldedList 去空白、按不区分大小写去重（保留首次出现），全部为空时返回 nil
+func normalizeFoldedList(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(items))
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key := strings.ToLower(item)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, item)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// NormalizeHTTPMethods 规范化 HTTP 方法列表：去空白、转大写、去重、排序
+func NormalizeHTTPMethods(methods []string) []string {
+	if len(methods) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(methods))
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m != "" {
+			result = append(result, m)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// StatusCodeRange 状态码闭区间 [Min, Max]
+type StatusCodeRange struct {
+	Min int
+	Max int
+}
+
+// ParseStatusCodeRanges 解析逗号分隔的状态码/范围列表（如 "200-299,404"），空串返回 nil
+func ParseStatusCodeRanges(raw string) ([]StatusCodeRange, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	ranges := make([]StatusCodeRange, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		minCode, err := parseStatusCode(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		maxCode := minCode
+		if isRange {
+			if maxCode, err = parseStatusCode(hi); err != nil || maxCode < minCode {
+				return nil, fmt.Errorf("invalid status code range %q", part)
+			}
+		}
+		ranges = append(ranges, StatusCodeRange{Min: minCode, Max: maxCode})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(raw string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, err
+	}
+	if code < 100 || code > 599 {
+		return 0, errors.New("status code out of range")
+	}
+	return code, nil
+}
+
+// IsSuccessStatus 判断上游状态码是否视为成功：SuccessCodes 为空或无法解析时回退到 2xx
+func (c *Config) IsSuccessStatus(code int) bool {
+	if c != nil && c.SuccessCodes != "" {
+		if ranges := c.parsedSuccessCodes(); len(ranges) > 0 {
+			for _, r := range ranges {
+				if code >= r.Min && code <= r.Max {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return code >= 200 && code < 300
+}
+
+// parsedSuccessCodes 返回缓存的成功状态码解析结果（每个上游响应都会判定，避免重复解析）
+func (c *Config) parsedSuccessCodes() []StatusCodeRange {
+	raw := c.SuccessCodes
+	if cached := c.successCodesCache.Load(); cached != nil && cached.raw == raw {
+		return cached.ranges
+	}
+	ranges, err := ParseStatusCodeRanges(raw)
+	if err != nil {
+		ranges = nil
+	}
+	c.successCodesCache.Store(&successCodesCache{raw: raw, ranges: ranges})
+	return ranges
+}
+
+// IsActiveAt 判断渠道在给定时间是否处于启用时段：ActiveSchedule 为空或无法解析时视为全天可用
+func (c *Config) IsActiveAt(t time.Time) bool {
+	if c == nil || c.ActiveSchedule == "" {
+		return true
+	}
+	sched := c.parsedActiveSchedule()
+	if sched == nil {
+		return true
+	}
+	return sched.Contains(t)
+}
+
+// parsedActiveSchedule 返回缓存的启用时段解析结果（选路热路径上避免重复解析与 time.LoadLocation）
+func (c *Config) parsedActiveSchedule() *ActiveSchedule {
+	raw := c.ActiveSchedule
+	if cached := c.scheduleCache.Load(); cached != nil && cached.raw == raw {
+		return cached.schedule
+	}
+	sched, err := ParseActiveSchedule(raw)
+	if err != nil {
+		sched = nil
+	}
+	c.scheduleCache.Store(&activeScheduleCache{raw: raw, schedule: sched})
+	return sched
+}
+
 // SupportedProtocols 返回渠道对外暴露的全部客户端协议集合。
 func (c *Config) SupportedProtocols() []string {
-	protocols := append([]string{c.GetChannelType()}, c.GetProtocolTransforms()...)
+	protocols := append([]string{c.GetProtocol()}, c.GetProtocolTransforms()...)
 	slices.Sort(protocols)
 	return slices.Compact(protocols)
 }
@@ -345,6 +626,11 @@ func (c *Config) GetChannelType() string {
 	return c.ChannelType
 }
 
+// GetProtocol 返回渠道原生上游协议（azure 渠道走 OpenAI 协议）
+func (c *Config) GetProtocol() string {
+	return util.ChannelTypeProtocol(c.GetChannelType())
+}
+
 // IsCoolingDown 检查渠道是否处于冷却状态
 func (c *Config) IsCoolingDown(now time.Time) bool {
 	return c.CooldownUntil > now.Unix()
@@ -368,6 +654,10 @@ type APIKey struct {
 	KeyIndex  int    `json:"key_index"`
 	APIKey    string `json:"api_key"`
 	Note      string `json:"note"`
+	// KeyGroup 共享冷却分组（跨渠道）：同组任一 Key 冷却时，组内其他 Key 同步冷却
+	KeyGroup string `json:"key_group,omitempty"`
+	// RPMLimit Key级每分钟请求上限（通常来自上游配额，0=不限）；计数仅在内存中按滑动窗口统计
+	RPMLimit int `json:"rpm_limit,omitempty"`
 
 	KeyStrategy string `json:"key_strategy"` // "sequential" | "round_robin"
 	Disabled    bool   `json:"disabled"`
@@ -376,10 +666,20 @@ type APIKey struct {
 	CooldownUntil      int64 `json:"cooldown_until"`
 	CooldownDurationMs int64 `json:"cooldown_duration_ms"`
 
+	// LastUsedAt 最后一次成功代理的时间（Unix毫秒，0=从未使用；批量异步落库，存在分钟级滞后）
+	LastUsedAt int64 `json:"last_used_at"`
+
 	CreatedAt JSONTime `json:"created_at"`
 	UpdatedAt JSONTime `json:"updated_at"`
 }
 
+// KeyLastUsed 单个 Key 的最后使用时间（批量更新 api_keys.last_used_at 用）
+type KeyLastUsed struct {
+	ChannelID  int64
+	KeyIndex   int
+	LastUsedAt int64 // Unix毫秒
+}
+
 // IsCoolingDown 检查密钥是否处于冷却状态
 func (k *APIKey) IsCoolingDown(now time.Time) bool {
 	return k.CooldownUntil > now.Unix()
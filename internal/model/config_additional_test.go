@@ -187,3 +187,138 @@ func TestGetURLs_WhitespaceOnlyReturnsEmpty(t *testing.T) {
 		t.Fatalf("expected empty urls for whitespace-only input, got %v", urls)
 	}
 }
+
+func TestConfig_AllowsMethod(t *testing.T) {
+	t.Parallel()
+
+	unrestricted := &Config{}
+	if !unrestricted.AllowsMethod("DELETE") {
+		t.Fatal("empty allowed_methods should allow all methods")
+	}
+
+	postOnly := &Config{AllowedMethods: NormalizeHTTPMethods([]string{" post "})}
+	if !postOnly.AllowsMethod("POST") || !postOnly.AllowsMethod("post") {
+		t.Fatal("POST should be allowed")
+	}
+	if postOnly.AllowsMethod("GET") {
+		t.Fatal("GET should be rejected")
+	}
+}
+
+func TestConfig_BlocksModel(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{BlockedModels: NormalizeBlockedModels([]string{" gpt-4-0314 ", "GPT-4-0314", "", "claude-2"})}
+	if len(cfg.BlockedModels) != 2 || cfg.BlockedModels[0] != "gpt-4-0314" || cfg.BlockedModels[1] != "claude-2" {
+		t.Fatalf("NormalizeBlockedModels = %v, want [gpt-4-0314 claude-2]", cfg.BlockedModels)
+	}
+	if !cfg.BlocksModel("GPT-4-0314") || !cfg.BlocksModel("claude-2") {
+		t.Fatal("listed models should be blocked (case-insensitive)")
+	}
+	if cfg.BlocksModel("gpt-4") {
+		t.Fatal("unlisted model should not be blocked")
+	}
+	if (&Config{}).BlocksModel("gpt-4") {
+		t.Fatal("empty blocked_models should block nothing")
+	}
+}
+
+func TestNormalizeHTTPMethods(t *testing.T) {
+	t.Parallel()
+
+	got := NormalizeHTTPMethods([]string{"post", " GET", "", "POST"})
+	if len(got) != 2 || got[0] != "GET" || got[1] != "POST" {
+		t.Fatalf("NormalizeHTTPMethods = %v, want [GET POST]", got)
+	}
+	if NormalizeHTTPMethods([]string{" ", ""}) != nil {
+		t.Fatal("blank entries should normalize to nil")
+	}
+}
+
+func TestConfig_IsSuccessStatus(t *testing.T) {
+	t.Parallel()
+
+	defaults := &Config{}
+	if !defaults.IsSuccessStatus(200) || !defaults.IsSuccessStatus(299) || defaults.IsSuccessStatus(404) {
+		t.Fatal("empty success_codes should default to 2xx")
+	}
+
+	custom := &Config{SuccessCodes: "200-201, 404"}
+	for code, want := range map[int]bool{200: true, 201: true, 202: false, 404: true, 500: false} {
+		if got := custom.IsSuccessStatus(code); got != want {
+			t.Fatalf("IsSuccessStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+
+	invalid := &Config{SuccessCodes: "abc"}
+	if !invalid.IsSuccessStatus(204) || invalid.IsSuccessStatus(404) {
+		t.Fatal("unparsable success_codes should fall back to 2xx")
+	}
+
+	// 解析结果按字符串缓存，字段变更后重新解析
+	first := custom.parsedSuccessCodes()
+	if len(first) == 0 || &custom.parsedSuccessCodes()[0] != &first[0] {
+		t.Fatal("parsed success_codes should be cached across calls")
+	}
+	custom.SuccessCodes = "500"
+	if !custom.IsSuccessStatus(500) || custom.IsSuccessStatus(404) {
+		t.Fatal("success_codes change should invalidate the cache")
+	}
+}
+
+func TestParseStatusCodeRanges(t *testing.T) {
+	t.Parallel()
+
+	ranges, err := ParseStatusCodeRanges("200-299,404,")
+	if err != nil || len(ranges) != 2 || ranges[0] != (StatusCodeRange{Min: 200, Max: 299}) || ranges[1] != (StatusCodeRange{Min: 404, Max: 404}) {
+		t.Fatalf("ParseStatusCodeRanges = %v, %v", ranges, err)
+	}
+	if ranges, err := ParseStatusCodeRanges(" "); err != nil || ranges != nil {
+		t.Fatalf("blank input should return nil, got %v, %v", ranges, err)
+	}
+	for _, raw := range []string{"abc", "299-200", "99", "600", "200-"} {
+		if _, err := ParseStatusCodeRanges(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestConfig_StripsHeader(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{StripHeaders: NormalizeStripHeaders([]string{" Cookie ", "cookie", "X-Trace-*", ""})}
+	if len(cfg.StripHeaders) != 2 || cfg.StripHeaders[0] != "Cookie" || cfg.StripHeaders[1] != "X-Trace-*" {
+		t.Fatalf("NormalizeStripHeaders = %v, want [Cookie X-Trace-*]", cfg.StripHeaders)
+	}
+	for _, name := range []string{"cookie", "COOKIE", "X-Trace-Id", "x-trace-span"} {
+		if !cfg.StripsHeader(name) {
+			t.Fatalf("expected %q to be stripped", name)
+		}
+	}
+	for _, name := range []string{"Cookies", "X-Trace", "X-Tracer"} {
+		if cfg.StripsHeader(name) {
+			t.Fatalf("expected %q to pass", name)
+		}
+	}
+	if (&Config{}).StripsHeader("Cookie") {
+		t.Fatal("empty strip_headers should strip nothing")
+	}
+}
+
+func TestConfig_MatchesRetryError(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RetryErrorSubstrings: NormalizeRetryErrorSubstrings([]string{" Overloaded ", "overloaded", "", "try again later"})}
+	if len(cfg.RetryErrorSubstrings) != 2 || cfg.RetryErrorSubstrings[0] != "Overloaded" {
+		t.Fatalf("NormalizeRetryErrorSubstrings = %v, want [Overloaded try again later]", cfg.RetryErrorSubstrings)
+	}
+	if !cfg.MatchesRetryError([]byte(`{"error":{"message":"Server OVERLOADED"}}`)) {
+		t.Fatal("expected case-insensitive match")
+	}
+	if cfg.MatchesRetryError([]byte(`{"error":"invalid request"}`)) {
+		t.Fatal("unexpected match")
+	}
+	if (&Config{}).MatchesRetryError([]byte("overloaded")) {
+		t.Fatal("empty retry_error_substrings should match nothing")
+	}
+}
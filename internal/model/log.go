@@ -83,6 +83,8 @@ type LogEntry struct {
 	BaseURL              string   `json:"base_url,omitempty"`     // 请求使用的上游URL（多URL场景）
 	ServiceTier          string   `json:"service_tier,omitempty"` // OpenAI service_tier: "priority"(2x)/"flex"(0.5x)
 	ThinkingEffort       string   `json:"thinking_effort,omitempty"`
+	RequestID            string   `json:"request_id,omitempty"` // 请求ID（客户端 X-Request-Id 或服务端生成，用于关联客户端与服务端日志）
+	Path                 string   `json:"path,omitempty"`       // 客户端请求路径（不含 query，排查非标准端点路由问题）
 
 	// Token统计（2025-11新增，支持Claude API usage字段）
 	InputTokens              int     `json:"input_tokens"`
@@ -95,6 +97,16 @@ type LogEntry struct {
 	Cost                     float64 `json:"cost"`                        // 请求成本（美元，标准成本）
 	CostMultiplier           float64 `json:"cost_multiplier"`             // 写日志时快照的渠道倍率，默认1
 
+	// 重试位置（2026-10新增）：1=首次尝试/首选渠道，0=历史数据未记录
+	AttemptNumber  int `json:"attempt_number,omitempty"`  // 本请求第几次上游尝试（跨渠道、Key、URL 累计）
+	ChannelAttempt int `json:"channel_attempt,omitempty"` // 本请求第几个渠道
+
+	// 慢请求标记：总耗时超过 CCLOAD_SLOW_REQUEST_MS 阈值（写入时判定，阈值变更不回溯历史日志）
+	IsSlow bool `json:"is_slow,omitempty"`
+
+	// 错误日志的完整上游错误（仅在 message 被截断时写入；列表查询不返回，单条查询 full=true 时返回）
+	ErrorDetail string `json:"error_detail,omitempty"`
+
 	// 瞬态字段：不持久化到 logs 表，仅用于传递 debug 数据到写入管道
 	DebugData *DebugLogEntry `json:"-"`
 }
@@ -110,6 +122,20 @@ type LogFilter struct {
 	ChannelType     string // 渠道类型过滤（anthropic/openai/gemini/codex）
 	AuthTokenID     *int64 // API令牌ID过滤
 	LogSource       string
+	RequestID       string // 请求ID精确匹配
+	PathLike        string // 请求路径子串匹配
+	MinDurationMs   *int64 // 总耗时下限（毫秒，含）
+	MaxDurationMs   *int64 // 总耗时上限（毫秒，含）
+	MinFirstByteMs  *int64 // 首字节耗时下限（毫秒，含；仅流式请求记录首字节时间）
+	SlowOnly        bool   // 仅返回慢请求（is_slow=1）
+	ErrorsOnly      bool   // 仅返回错误请求（状态码非 2xx）
+}
+
+// LogCursor 日志键集分页游标：指向上一页（按 time DESC, id DESC 排序）的最后一行。
+// 用于大批量导出等场景，替代 OFFSET 深分页。
+type LogCursor struct {
+	TimeMs int64
+	ID     int64
 }
 
 // ChannelURLLogStat 是基于持久化日志聚合出的 URL 启动快照。
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActiveSchedule 渠道启用时段（由 Config.ActiveSchedule 解析）
+// 语法：空白分隔的若干段，顺序任意：
+//   - 时间段：逗号分隔的 HH:MM-HH:MM，结束早于开始表示跨午夜（如 22:00-08:00）
+//   - 星期（可选）：逗号分隔的 Mon..Sun 或范围（如 Mon-Fri,Sun），跨午夜时段按开始当天判断
+//   - 时区（可选）：IANA 名称（如 Asia/Shanghai），缺省使用调用方传入时间的时区
+type ActiveSchedule struct {
+	Windows  []ScheduleWindow
+	Weekdays uint8 // 位图：1<<time.Weekday，0 表示每天
+	Location *time.Location
+}
+
+// ScheduleWindow 一天内的时间段（分钟，左闭右开）
+type ScheduleWindow struct {
+	Start int
+	End   int
+}
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseActiveSchedule 解析启用时段表达式，空串返回 nil（表示全天可用）
+func ParseActiveSchedule(raw string) (*ActiveSchedule, error) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	sched := &ActiveSchedule{}
+	for _, field := range fields {
+		switch {
+		case strings.Contains(field, ":"):
+			if len(sched.Windows) > 0 {
+				return nil, fmt.Errorf("duplicate time windows %q", field)
+			}
+			windows, err := parseScheduleWindows(field)
+			if err != nil {
+				return nil, err
+			}
+			sched.Windows = windows
+		case isScheduleWeekdayField(field):
+			if sched.Weekdays != 0 {
+				return nil, fmt.Errorf("duplicate weekdays %q", field)
+			}
+			days, err := parseScheduleWeekdays(field)
+			if err != nil {
+				return nil, err
+			}
+			sched.Weekdays = days
+		default:
+			if sched.Location != nil {
+				return nil, fmt.Errorf("duplicate timezone %q", field)
+			}
+			loc, err := time.LoadLocation(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone %q", field)
+			}
+			sched.Location = loc
+		}
+	}
+	if len(sched.Windows) == 0 {
+		return nil, fmt.Errorf("missing time window (HH:MM-HH:MM)")
+	}
+	return sched, nil
+}
+
+// Contains 判断时间点是否落在启用时段内
+func (s *ActiveSchedule) Contains(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	if s.Location != nil {
+		t = t.In(s.Location)
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range s.Windows {
+		day := t.Weekday()
+		switch {
+		case w.Start < w.End:
+			if minute < w.Start || minute >= w.End {
+				continue
+			}
+		case minute >= w.Start:
+			// 跨午夜时段的前半段
+		case minute < w.End:
+			// 跨午夜时段的后半段：归属前一天
+			day = (day + 6) % 7
+		default:
+			continue
+		}
+		if s.Weekdays == 0 || s.Weekdays&(1<<day) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func parseScheduleWindows(field string) ([]ScheduleWindow, error) {
+	parts := strings.Split(field, ",")
+	windows := make([]ScheduleWindow, 0, len(parts))
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		lo, hi, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time window %q", part)
+		}
+		start, err := parseScheduleClock(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time window %q", part)
+		}
+		end, err := parseScheduleClock(hi)
+		if err != nil || end == start {
+			return nil, fmt.Errorf("invalid time window %q", part)
+		}
+		windows = append(windows, ScheduleWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+// parseScheduleClock 解析 HH:MM（允许 24:00 表示当天结束）
+func parseScheduleClock(raw string) (int, error) {
+	t, err := time.Parse("15:04", raw)
+	if err == nil {
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if raw == "24:00" {
+		return 24 * 60, nil
+	}
+	return 0, err
+}
+
+func isScheduleWeekdayField(field string) bool {
+	first, _, _ := strings.Cut(strings.Split(field, ",")[0], "-")
+	_, ok := scheduleWeekdays[strings.ToLower(first)]
+	return ok
+}
+
+func parseScheduleWeekdays(field string) (uint8, error) {
+	var days uint8
+	for part := range strings.SplitSeq(field, ",") {
+		lo, hi, isRange := strings.Cut(strings.ToLower(part), "-")
+		start, ok := scheduleWeekdays[lo]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday %q", part)
+		}
+		end := start
+		if isRange {
+			if end, ok = scheduleWeekdays[hi]; !ok {
+				return 0, fmt.Errorf("invalid weekday range %q", part)
+			}
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days |= 1 << d
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
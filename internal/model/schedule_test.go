@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseActiveSchedule(t *testing.T) {
+	t.Parallel()
+
+	if sched, err := ParseActiveSchedule("  "); sched != nil || err != nil {
+		t.Fatalf("empty schedule = %v, %v", sched, err)
+	}
+	sched, err := ParseActiveSchedule("Mon-Fri,Sun 09:00-12:00,22:00-02:00 Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("ParseActiveSchedule: %v", err)
+	}
+	if len(sched.Windows) != 2 || sched.Windows[1] != (ScheduleWindow{Start: 22 * 60, End: 2 * 60}) {
+		t.Fatalf("windows = %+v", sched.Windows)
+	}
+	if sched.Weekdays != 0b1111111&^(1<<time.Saturday) || sched.Location.String() != "Asia/Shanghai" {
+		t.Fatalf("weekdays=%07b location=%v", sched.Weekdays, sched.Location)
+	}
+
+	for _, raw := range []string{
+		"Mon-Fri",                  // 缺少时间段
+		"09:00",                    // 非范围
+		"09:00-09:00",              // 空时段
+		"25:00-26:00",              // 非法时间
+		"Mon-Xyz 09:00-10:00",      // 非法星期
+		"09:00-10:00 Mars/Olympus", // 非法时区
+		"09:00-10:00 11:00-12:00",  // 重复时间段
+	} {
+		if _, err := ParseActiveSchedule(raw); err == nil {
+			t.Errorf("ParseActiveSchedule(%q) should fail", raw)
+		}
+	}
+}
+
+func TestActiveScheduleContains(t *testing.T) {
+	t.Parallel()
+
+	utc := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC) // 2026-10-12 为周一
+	}
+
+	sched, err := ParseActiveSchedule("Mon-Fri 22:00-08:00,12:00-24:00")
+	if err != nil {
+		t.Fatalf("ParseActiveSchedule: %v", err)
+	}
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{utc(12, 23, 0), true},   // 周一夜间
+		{utc(13, 7, 59), true},   // 周二凌晨属于周一开始的时段
+		{utc(13, 8, 0), false},   // 时段结束（左闭右开）
+		{utc(13, 12, 0), true},   // 午间时段
+		{utc(13, 23, 59), true},  // 24:00 结束
+		{utc(17, 23, 0), false},  // 周六
+		{utc(18, 3, 0), false},   // 周日凌晨属于周六
+		{utc(12, 3, 0), false},   // 周一凌晨属于周日
+		{utc(17, 3, 0), true},    // 周六凌晨属于周五
+		{utc(14, 10, 30), false}, // 周三上午不在任何时段
+	}
+	for _, tc := range cases {
+		if got := sched.Contains(tc.at); got != tc.want {
+			t.Errorf("Contains(%s %s) = %v, want %v", tc.at.Weekday(), tc.at.Format("15:04"), got, tc.want)
+		}
+	}
+
+	// 显式时区：UTC 01:00 = 上海 09:00
+	shanghai, err := ParseActiveSchedule("09:00-10:00 Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("ParseActiveSchedule: %v", err)
+	}
+	if !shanghai.Contains(utc(12, 1, 30)) || shanghai.Contains(utc(12, 9, 30)) {
+		t.Fatal("explicit timezone should be applied")
+	}
+
+	cfg := &Config{ActiveSchedule: "09:00-10:00"}
+	if !cfg.IsActiveAt(utc(12, 9, 0)) || cfg.IsActiveAt(utc(12, 10, 0)) {
+		t.Fatal("Config.IsActiveAt mismatch")
+	}
+	if !(&Config{}).IsActiveAt(utc(12, 3, 0)) {
+		t.Fatal("empty schedule should always be active")
+	}
+}
+
+func TestConfig_IsActiveAtCachesParsedSchedule(t *testing.T) {
+	t.Parallel()
+
+	utc := func(hour int) time.Time { return time.Date(2026, 1, 12, hour, 0, 0, 0, time.UTC) }
+
+	cfg := &Config{ActiveSchedule: "09:00-10:00 UTC"}
+	if !cfg.IsActiveAt(utc(9)) {
+		t.Fatal("expected active at 09:00")
+	}
+	first := cfg.parsedActiveSchedule()
+	if first == nil || cfg.parsedActiveSchedule() != first {
+		t.Fatal("parsed schedule should be cached across calls")
+	}
+
+	// 字段变更后按新字符串重新解析
+	cfg.ActiveSchedule = "12:00-13:00 UTC"
+	if cfg.IsActiveAt(utc(9)) || !cfg.IsActiveAt(utc(12)) {
+		t.Fatal("schedule change should invalidate the cache")
+	}
+
+	// 无法解析时视为全天可用（同样缓存）
+	cfg.ActiveSchedule = "not a schedule"
+	if !cfg.IsActiveAt(utc(3)) || cfg.parsedActiveSchedule() != nil {
+		t.Fatal("invalid schedule should be treated as always active")
+	}
+}
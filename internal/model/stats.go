@@ -62,6 +62,7 @@ type StatsEntry struct {
 	Success                 int      `json:"success"`
 	Error                   int      `json:"error"`
 	Total                   int      `json:"total"`
+	FirstTrySuccess         int      `json:"first_try_success"`                     // 首次上游尝试即成功的次数（首试成功率 = first_try_success / success）
 	AvgFirstByteTimeSeconds *float64 `json:"avg_first_byte_time_seconds,omitempty"` // 流式请求平均上游首块响应体时间(秒)
 	AvgDurationSeconds      *float64 `json:"avg_duration_seconds,omitempty"`        // 平均总耗时(秒)
 	LastSuccessAt           *int64   `json:"last_success_at,omitempty"`             // 最近一次成功请求时间(毫秒)
@@ -129,3 +130,26 @@ type ChannelNameID struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
 }
+
+// ErrorBreakdownEntry 错误按状态码（可选按渠道）聚合的计数
+type ErrorBreakdownEntry struct {
+	StatusCode  int    `json:"status_code"`
+	ChannelID   int64  `json:"channel_id,omitempty"`
+	ChannelName string `json:"channel_name,omitempty"`
+	Count       int64  `json:"count"`
+}
+
+// DailyStat stats_daily 汇总表中的一行（按天、渠道、模型聚合）
+type DailyStat struct {
+	Day                      string  `json:"day"` // 本地日期 YYYY-MM-DD
+	ChannelID                int64   `json:"channel_id"`
+	ChannelName              string  `json:"channel_name,omitempty"`
+	Model                    string  `json:"model"`
+	SuccessCount             int64   `json:"success_count"`
+	ErrorCount               int64   `json:"error_count"`
+	InputTokens              int64   `json:"input_tokens"`
+	OutputTokens             int64   `json:"output_tokens"`
+	CacheReadInputTokens     int64   `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int64   `json:"cache_creation_input_tokens"`
+	Cost                     float64 `json:"cost"` // 倍率后成本（effective）
+}
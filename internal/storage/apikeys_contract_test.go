@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+// verifyAPIKeysStorageContract 校验 api_keys 表（内联冷却、备注、禁用、共享冷却分组）与渠道熔断字段的存储契约
+// SQLite 在常规测试中运行；MySQL/PostgreSQL 在各自的集成测试中复用，防止方言间 schema 漂移
+func verifyAPIKeysStorageContract(t *testing.T, store Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	newChannel := func(name string) int64 {
+		t.Helper()
+		cfg, err := store.CreateConfig(ctx, &model.Config{
+			Name: name, URL: "https://api.example.com", Priority: 1, ChannelType: "openai", Enabled: true,
+			ModelEntries: []model.ModelEntry{{Model: "gpt-4"}},
+		})
+		if err != nil {
+			t.Fatalf("CreateConfig %s: %v", name, err)
+		}
+		return cfg.ID
+	}
+	ch1 := newChannel("contract-keys-1")
+	ch2 := newChannel("contract-keys-2")
+
+	if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: ch1, KeyIndex: 0, APIKey: "sk-shared", Note: "primary", KeyGroup: "acct"},
+		{ChannelID: ch1, KeyIndex: 1, APIKey: "sk-solo", Disabled: true},
+		{ChannelID: ch2, KeyIndex: 0, APIKey: "sk-shared", KeyGroup: "acct"},
+	}); err != nil {
+		t.Fatalf("CreateAPIKeysBatch: %v", err)
+	}
+
+	keys, err := store.GetAPIKeys(ctx, ch1)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("GetAPIKeys len=%d, want 2", len(keys))
+	}
+	if k := keys[0]; k.Note != "primary" || k.KeyGroup != "acct" || k.KeyStrategy != model.KeyStrategySequential || k.Disabled {
+		t.Fatalf("key 0 round-trip mismatch: %+v", k)
+	}
+	if !keys[1].Disabled {
+		t.Fatalf("key 1 should be disabled: %+v", keys[1])
+	}
+	all, err := store.GetAllAPIKeys(ctx)
+	if err != nil {
+		t.Fatalf("GetAllAPIKeys: %v", err)
+	}
+	if len(all[ch1]) != 2 || len(all[ch2]) != 1 || all[ch2][0].KeyGroup != "acct" {
+		t.Fatalf("GetAllAPIKeys mismatch: %v", all)
+	}
+
+	// Key 级冷却内联在 api_keys，并同步到同组 Key
+	if _, err := store.BumpKeyCooldown(ctx, ch1, 0, time.Now(), 429); err != nil {
+		t.Fatalf("BumpKeyCooldown: %v", err)
+	}
+	cooldowns, err := store.GetAllKeyCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("GetAllKeyCooldowns: %v", err)
+	}
+	if _, ok := cooldowns[ch1][0]; !ok {
+		t.Fatalf("expected key cooldown on channel %d: %v", ch1, cooldowns)
+	}
+	if _, ok := cooldowns[ch2][0]; !ok {
+		t.Fatalf("expected group cooldown propagated to channel %d: %v", ch2, cooldowns)
+	}
+	if err := store.ResetKeyCooldown(ctx, ch1, 0); err != nil {
+		t.Fatalf("ResetKeyCooldown: %v", err)
+	}
+	cooldowns, err = store.GetAllKeyCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("GetAllKeyCooldowns: %v", err)
+	}
+	if len(cooldowns[ch1]) != 0 || len(cooldowns[ch2]) != 0 {
+		t.Fatalf("expected group cooldown cleared: %v", cooldowns)
+	}
+
+	// 元数据独立更新，不重建 Key
+	if err := store.UpdateAPIKeyNotes(ctx, ch1, map[int]string{0: "renamed"}); err != nil {
+		t.Fatalf("UpdateAPIKeyNotes: %v", err)
+	}
+	if err := store.UpdateAPIKeyGroups(ctx, ch1, map[int]string{0: ""}); err != nil {
+		t.Fatalf("UpdateAPIKeyGroups: %v", err)
+	}
+	if err := store.SetAPIKeyDisabled(ctx, ch1, 1, false); err != nil {
+		t.Fatalf("SetAPIKeyDisabled: %v", err)
+	}
+	keys, err = store.GetAPIKeys(ctx, ch1)
+	if err != nil {
+		t.Fatalf("GetAPIKeys: %v", err)
+	}
+	if keys[0].Note != "renamed" || keys[0].KeyGroup != "" || keys[1].Disabled {
+		t.Fatalf("metadata update mismatch: %+v / %+v", keys[0], keys[1])
+	}
+
+	// 渠道级冷却与熔断计数
+	if _, err := store.BumpChannelCooldown(ctx, ch1, time.Now(), 500); err != nil {
+		t.Fatalf("BumpChannelCooldown: %v", err)
+	}
+	opened, err := store.OpenChannelCircuit(ctx, ch1, 1, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("OpenChannelCircuit: %v", err)
+	}
+	if !opened {
+		t.Fatal("circuit should open at threshold 1")
+	}
+	if err := store.ResetAllCooldowns(ctx, ch1); err != nil {
+		t.Fatalf("ResetAllCooldowns: %v", err)
+	}
+	cfg, err := store.GetConfig(ctx, ch1)
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if cfg.CooldownUntil != 0 || cfg.ConsecutiveFailures != 0 {
+		t.Fatalf("expected channel cooldown reset, got until=%d failures=%d", cfg.CooldownUntil, cfg.ConsecutiveFailures)
+	}
+
+	if err := store.DeleteAPIKey(ctx, ch1, 1); err != nil {
+		t.Fatalf("DeleteAPIKey: %v", err)
+	}
+	if keys, err := store.GetAPIKeys(ctx, ch1); err != nil || len(keys) != 1 {
+		t.Fatalf("GetAPIKeys after delete: len=%d err=%v", len(keys), err)
+	}
+
+	// ReplaceAPIKeys: 全量替换，调用方携带的冷却状态原样写入
+	if err := store.ReplaceAPIKeys(ctx, ch1, []*model.APIKey{
+		{ChannelID: ch1, KeyIndex: 0, APIKey: "sk-rotated-a", CooldownUntil: 4102444800, CooldownDurationMs: 1000},
+		{ChannelID: ch1, KeyIndex: 1, APIKey: "sk-rotated-b"},
+	}); err != nil {
+		t.Fatalf("ReplaceAPIKeys: %v", err)
+	}
+	keys, err = store.GetAPIKeys(ctx, ch1)
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("GetAPIKeys after replace: len=%d err=%v", len(keys), err)
+	}
+	if keys[0].APIKey != "sk-rotated-a" || keys[0].CooldownUntil != 4102444800 || keys[1].CooldownUntil != 0 {
+		t.Fatalf("unexpected keys after replace: %+v %+v", keys[0], keys[1])
+	}
+
+	// 保留的 Key 原地更新（行 ID 不变，可换位），移除的删除，新增的插入
+	idA, idB := keys[0].ID, keys[1].ID
+	if err := store.ReplaceAPIKeys(ctx, ch1, []*model.APIKey{
+		{ChannelID: ch1, KeyIndex: 0, APIKey: "sk-rotated-b", Note: "moved"},
+		{ChannelID: ch1, KeyIndex: 1, APIKey: "sk-rotated-c"},
+	}); err != nil {
+		t.Fatalf("ReplaceAPIKeys in place: %v", err)
+	}
+	keys, err = store.GetAPIKeys(ctx, ch1)
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("GetAPIKeys after in-place replace: len=%d err=%v", len(keys), err)
+	}
+	if keys[0].ID != idB || keys[0].APIKey != "sk-rotated-b" || keys[0].Note != "moved" {
+		t.Fatalf("retained key should keep id %d: %+v", idB, keys[0])
+	}
+	if keys[1].APIKey != "sk-rotated-c" || keys[1].ID == idA || keys[1].ID == idB {
+		t.Fatalf("new key should be inserted with a fresh id: %+v", keys[1])
+	}
+	if err := store.ReplaceAPIKeys(ctx, ch1, []*model.APIKey{{ChannelID: ch1 + 1000, APIKey: "sk-wrong"}}); err == nil {
+		t.Fatal("ReplaceAPIKeys should reject keys of another channel")
+	}
+	if keys, _ := store.GetAPIKeys(ctx, ch1); len(keys) != 2 {
+		t.Fatalf("rejected replace must not modify keys, got %d", len(keys))
+	}
+}
+
+func TestSQLite_APIKeysStorageContract(t *testing.T) {
+	t.Parallel()
+
+	store, err := CreateSQLiteStore(filepath.Join(t.TempDir(), "contract.db"))
+	if err != nil {
+		t.Fatalf("CreateSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	verifyAPIKeysStorageContract(t, store)
+}
@@ -105,6 +105,7 @@ func NewStore() (Store, error) {
 	}
 
 	hybrid := NewHybridStore(sqlite, primary)
+	hybrid.recordSync(nil)
 	log.Printf("[INFO] 混合存储已启用（主库=%s, logs 恢复天数: %d）", primaryName, logDays)
 	return hybrid, nil
 }
@@ -43,6 +43,25 @@ type HybridStore struct {
 	// 静默降级在生产中难以察觉，计数器 + 采样告警让运维可见，不改一致性语义
 	sqliteSyncFailCount atomic.Uint64 // SQLite 缓存同步失败累计
 	syncQueueDropCount  atomic.Uint64 // MySQL 同步队列满丢弃累计
+
+	// 最近一次配置表全量同步（启动恢复或手动重同步）
+	resyncMu    sync.Mutex // 串行化手动重同步，同时保护下面两个字段
+	lastSyncAt  time.Time
+	lastSyncErr string
+}
+
+// HybridSyncStatus 混合存储同步状态（主库 → SQLite 缓存）
+type HybridSyncStatus struct {
+	Enabled            bool      `json:"enabled"`
+	PrimaryReachable   bool      `json:"primary_reachable"`
+	PrimaryError       string    `json:"primary_error,omitempty"`
+	LastSyncAt         time.Time `json:"last_sync_at"`
+	LastSyncError      string    `json:"last_sync_error,omitempty"`
+	PrimaryChannels    int64     `json:"primary_channels"`
+	CacheChannels      int64     `json:"cache_channels"`
+	SQLiteSyncFailures uint64    `json:"sqlite_sync_failures"`
+	LogSyncQueueLen    int       `json:"log_sync_queue_len"`
+	LogSyncDropped     uint64    `json:"log_sync_dropped"`
 }
 
 // syncTask 同步任务
@@ -81,6 +100,52 @@ func NewHybridStore(sqlite, mysql *sqlstore.SQLStore) *HybridStore {
 	return h
 }
 
+// recordSync 记录一次配置表全量同步结果（调用方持有 resyncMu 或处于初始化阶段）
+func (h *HybridStore) recordSync(err error) {
+	h.lastSyncAt = time.Now()
+	h.lastSyncErr = ""
+	if err != nil {
+		h.lastSyncErr = err.Error()
+	}
+}
+
+// SyncStatus 查询同步状态：主库连通性、渠道数对比、降级计数器
+func (h *HybridStore) SyncStatus(ctx context.Context) HybridSyncStatus {
+	h.resyncMu.Lock()
+	status := HybridSyncStatus{
+		Enabled:            true,
+		LastSyncAt:         h.lastSyncAt,
+		LastSyncError:      h.lastSyncErr,
+		SQLiteSyncFailures: h.sqliteSyncFailCount.Load(),
+		LogSyncQueueLen:    len(h.syncCh),
+		LogSyncDropped:     h.syncQueueDropCount.Load(),
+	}
+	h.resyncMu.Unlock()
+
+	if err := h.mysql.Ping(ctx); err != nil {
+		status.PrimaryError = err.Error()
+	} else if err := h.mysql.QueryRowContext(ctx, "SELECT COUNT(*) FROM channels").Scan(&status.PrimaryChannels); err != nil {
+		status.PrimaryError = err.Error()
+	} else {
+		status.PrimaryReachable = true
+	}
+	if err := h.sqlite.QueryRowContext(ctx, "SELECT COUNT(*) FROM channels").Scan(&status.CacheChannels); err != nil {
+		log.Printf("[WARN] 统计 SQLite 渠道数失败: %v", err)
+	}
+	return status
+}
+
+// ResyncConfig 同步执行一次配置表全量恢复（主库 → SQLite），覆盖缓存中的所有配置行。
+// 并发调用串行执行；日志表不参与（由异步队列持续同步）。
+func (h *HybridStore) ResyncConfig(ctx context.Context) error {
+	h.resyncMu.Lock()
+	defer h.resyncMu.Unlock()
+
+	err := NewSyncManager(h.mysql, h.sqlite).RestoreConfigTables(ctx)
+	h.recordSync(err)
+	return err
+}
+
 // syncToSQLite 同步更新 SQLite 缓存
 // SQLite 是本地库，启动时已验证可写，运行时通常不会失败
 // 但磁盘空间不足等极端情况仍可能导致写入失败，记录日志以便排查
@@ -254,6 +319,48 @@ func (h *HybridStore) UpdateChannelEnabled(ctx context.Context, id int64, enable
 	return result, nil
 }
 
+func (h *HybridStore) UpdateChannelMaintenance(ctx context.Context, id int64, maintenance bool) (*model.Config, error) {
+	result, err := h.mysql.UpdateChannelMaintenance(ctx, id, maintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	h.syncToSQLite("UpdateChannelMaintenance", func() error {
+		_, err := h.sqlite.UpdateChannelMaintenance(ctx, id, maintenance)
+		return err
+	})
+
+	return result, nil
+}
+
+func (h *HybridStore) AddChannelModels(ctx context.Context, channelID int64, entries []model.ModelEntry) (*model.Config, error) {
+	result, err := h.mysql.AddChannelModels(ctx, channelID, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	h.syncToSQLite("AddChannelModels", func() error {
+		_, err := h.sqlite.UpdateConfig(ctx, channelID, result)
+		return err
+	})
+
+	return result, nil
+}
+
+func (h *HybridStore) RemoveChannelModels(ctx context.Context, channelID int64, models []string) (*model.Config, error) {
+	result, err := h.mysql.RemoveChannelModels(ctx, channelID, models)
+	if err != nil {
+		return nil, err
+	}
+
+	h.syncToSQLite("RemoveChannelModels", func() error {
+		_, err := h.sqlite.UpdateConfig(ctx, channelID, result)
+		return err
+	})
+
+	return result, nil
+}
+
 func (h *HybridStore) DeleteConfig(ctx context.Context, id int64) error {
 	if err := h.mysql.DeleteConfig(ctx, id); err != nil {
 		return err
@@ -299,6 +406,18 @@ func (h *HybridStore) BatchUpdatePriority(ctx context.Context, updates []struct
 	return affected, nil
 }
 
+func (h *HybridStore) UpdateChannelsLastUsed(ctx context.Context, lastUsed map[int64]int64) error {
+	if err := h.mysql.UpdateChannelsLastUsed(ctx, lastUsed); err != nil {
+		return err
+	}
+
+	h.syncToSQLite("UpdateChannelsLastUsed", func() error {
+		return h.sqlite.UpdateChannelsLastUsed(ctx, lastUsed)
+	})
+
+	return nil
+}
+
 // === Channel URL Runtime State ===
 
 func (h *HybridStore) LoadDisabledURLs(ctx context.Context) (map[int64][]string, error) {
@@ -389,6 +508,42 @@ func (h *HybridStore) UpdateAPIKeyNotes(ctx context.Context, channelID int64, no
 	return nil
 }
 
+func (h *HybridStore) UpdateAPIKeysLastUsed(ctx context.Context, updates []model.KeyLastUsed) error {
+	if err := h.mysql.UpdateAPIKeysLastUsed(ctx, updates); err != nil {
+		return err
+	}
+
+	h.syncToSQLite("UpdateAPIKeysLastUsed", func() error {
+		return h.sqlite.UpdateAPIKeysLastUsed(ctx, updates)
+	})
+
+	return nil
+}
+
+func (h *HybridStore) UpdateAPIKeyGroups(ctx context.Context, channelID int64, groupsByIndex map[int]string) error {
+	if err := h.mysql.UpdateAPIKeyGroups(ctx, channelID, groupsByIndex); err != nil {
+		return err
+	}
+
+	h.syncToSQLite("UpdateAPIKeyGroups", func() error {
+		return h.sqlite.UpdateAPIKeyGroups(ctx, channelID, groupsByIndex)
+	})
+
+	return nil
+}
+
+func (h *HybridStore) UpdateAPIKeyRPMLimits(ctx context.Context, channelID int64, limitsByIndex map[int]int) error {
+	if err := h.mysql.UpdateAPIKeyRPMLimits(ctx, channelID, limitsByIndex); err != nil {
+		return err
+	}
+
+	h.syncToSQLite("UpdateAPIKeyRPMLimits", func() error {
+		return h.sqlite.UpdateAPIKeyRPMLimits(ctx, channelID, limitsByIndex)
+	})
+
+	return nil
+}
+
 func (h *HybridStore) DeleteAPIKey(ctx context.Context, channelID int64, keyIndex int) error {
 	if err := h.mysql.DeleteAPIKey(ctx, channelID, keyIndex); err != nil {
 		return err
@@ -425,6 +580,18 @@ func (h *HybridStore) DeleteAllAPIKeys(ctx context.Context, channelID int64) err
 	return nil
 }
 
+func (h *HybridStore) ReplaceAPIKeys(ctx context.Context, channelID int64, keys []*model.APIKey) error {
+	if err := h.mysql.ReplaceAPIKeys(ctx, channelID, keys); err != nil {
+		return err
+	}
+
+	h.syncToSQLite("ReplaceAPIKeys", func() error {
+		return h.sqlite.ReplaceAPIKeys(ctx, channelID, keys)
+	})
+
+	return nil
+}
+
 // === Cooldown Management ===
 
 func (h *HybridStore) GetAllChannelCooldowns(ctx context.Context) (map[int64]time.Time, error) {
@@ -481,6 +648,20 @@ func (h *HybridStore) SetChannelCooldown(ctx context.Context, channelID int64, u
 	return nil
 }
 
+func (h *HybridStore) OpenChannelCircuit(ctx context.Context, channelID int64, threshold int, until time.Time) (bool, error) {
+	opened, err := h.mysql.OpenChannelCircuit(ctx, channelID, threshold, until)
+	if err != nil {
+		return false, err
+	}
+
+	h.syncToSQLite("OpenChannelCircuit", func() error {
+		_, err := h.sqlite.OpenChannelCircuit(ctx, channelID, threshold, until)
+		return err
+	})
+
+	return opened, nil
+}
+
 func (h *HybridStore) GetAllKeyCooldowns(ctx context.Context) (map[int64]map[int]time.Time, error) {
 	return h.sqlite.GetAllKeyCooldowns(ctx)
 }
@@ -605,10 +786,18 @@ func (h *HybridStore) ListLogsRangeWithCount(ctx context.Context, since, until t
 	return h.sqlite.ListLogsRangeWithCount(ctx, since, until, limit, offset, filter)
 }
 
+func (h *HybridStore) GetLog(ctx context.Context, id int64, full bool) (*model.LogEntry, error) {
+	return h.sqlite.GetLog(ctx, id, full)
+}
+
 func (h *HybridStore) CountLogs(ctx context.Context, since time.Time, filter *model.LogFilter) (int, error) {
 	return h.sqlite.CountLogs(ctx, since, filter)
 }
 
+func (h *HybridStore) ListLogsByCursor(ctx context.Context, since, until time.Time, cursor *model.LogCursor, limit int, filter *model.LogFilter) ([]*model.LogEntry, error) {
+	return h.sqlite.ListLogsByCursor(ctx, since, until, cursor, limit, filter)
+}
+
 func (h *HybridStore) CountLogsRange(ctx context.Context, since, until time.Time, filter *model.LogFilter) (int, error) {
 	return h.sqlite.CountLogsRange(ctx, since, until, filter)
 }
@@ -657,12 +846,42 @@ func (h *HybridStore) GetChannelSuccessRates(ctx context.Context, since time.Tim
 	return h.sqlite.GetChannelSuccessRates(ctx, since)
 }
 
+func (h *HybridStore) GetErrorBreakdown(ctx context.Context, since time.Time, byChannel bool) ([]model.ErrorBreakdownEntry, error) {
+	return h.sqlite.GetErrorBreakdown(ctx, since, byChannel)
+}
+
+// RollupDailyStats 汇总表需长期保留，直接在 MySQL 上基于全量日志计算和写入
+func (h *HybridStore) RollupDailyStats(ctx context.Context, day time.Time) error {
+	return h.mysql.RollupDailyStats(ctx, day)
+}
+
+// AddDailyStats 实时用量计数直接累加到 MySQL（SQLite 不保存 stats_daily 数据）
+func (h *HybridStore) AddDailyStats(ctx context.Context, deltas []model.DailyStat) error {
+	return h.mysql.AddDailyStats(ctx, deltas)
+}
+
+// ListMissingDailyStatDays 基于 MySQL 全量日志与汇总查询
+func (h *HybridStore) ListMissingDailyStatDays(ctx context.Context, since, until time.Time) ([]time.Time, error) {
+	return h.mysql.ListMissingDailyStatDays(ctx, since, until)
+}
+
+// ListDailyStats 从 MySQL 读取每日汇总（SQLite 不保存 stats_daily 数据）
+func (h *HybridStore) ListDailyStats(ctx context.Context, sinceDay string) ([]model.DailyStat, error) {
+	return h.mysql.ListDailyStats(ctx, sinceDay)
+}
+
 func (h *HybridStore) GetHealthTimeline(ctx context.Context, params model.HealthTimelineParams) ([]model.HealthTimelineRow, error) {
 	return h.sqlite.GetHealthTimeline(ctx, params)
 }
 
+// GetTodayChannelCosts 成本来自 stats_daily 实时计数，仅 MySQL 保存
 func (h *HybridStore) GetTodayChannelCosts(ctx context.Context, todayStart time.Time) (map[int64]float64, error) {
-	return h.sqlite.GetTodayChannelCosts(ctx, todayStart)
+	return h.mysql.GetTodayChannelCosts(ctx, todayStart)
+}
+
+// GetModelCosts 成本来自 stats_daily 实时计数，仅 MySQL 保存
+func (h *HybridStore) GetModelCosts(ctx context.Context, since time.Time) (map[string]float64, error) {
+	return h.mysql.GetModelCosts(ctx, since)
 }
 
 // === Auth Token Management ===
@@ -871,6 +1090,18 @@ func (h *HybridStore) ClearFingerprintChannelID(ctx context.Context, channelID i
 	return nil
 }
 
+// === Audit Log ===
+
+// AddAuditLog 审计记录需长期保留，只写 MySQL（SQLite 缓存不保存 audit_logs）
+func (h *HybridStore) AddAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	return h.mysql.AddAuditLog(ctx, entry)
+}
+
+// ListAuditLogs 从 MySQL 读取审计记录
+func (h *HybridStore) ListAuditLogs(ctx context.Context, since time.Time, channelID int64, limit int) ([]*model.AuditLog, error) {
+	return h.mysql.ListAuditLogs(ctx, since, channelID, limit)
+}
+
 // === Fingerprint Test Results ===
 
 func (h *HybridStore) CreateFingerprintTestResult(ctx context.Context, rec *model.FingerprintTestRecord) error {
@@ -576,3 +576,57 @@ func hasFingerprintTestResult(results []*model.FingerprintTestRecord, id int64,
 	}
 	return false
 }
+
+func TestHybridStore_ResyncConfigRepairsDriftedCache(t *testing.T) {
+	mysql := createTestSQLiteStore(t)
+	sqlite := createTestSQLiteStore(t)
+	defer func() {
+		_ = sqlite.Close()
+		_ = mysql.Close()
+	}()
+
+	hybrid := NewHybridStore(sqlite, mysql)
+	defer func() { _ = hybrid.Close() }()
+
+	ctx := context.Background()
+
+	// 绕过 HybridStore 直接写主库，模拟 SQLite 缓存同步失败后的漂移
+	if _, err := mysql.CreateConfig(ctx, &model.Config{
+		Name: "drifted", ChannelType: "openai", URL: "https://api.example.com", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	}); err != nil {
+		t.Fatalf("CreateConfig: %v", err)
+	}
+	stale, err := sqlite.CreateConfig(ctx, &model.Config{
+		Name: "stale", ChannelType: "openai", URL: "https://stale.example.com", Enabled: true,
+		ModelEntries: []model.ModelEntry{{Model: "gpt-4o"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateConfig stale: %v", err)
+	}
+	if err := sqlite.CreateAuthToken(ctx, &model.AuthToken{Token: "stale-token-hash", Description: "stale", IsActive: true}); err != nil {
+		t.Fatalf("CreateAuthToken stale: %v", err)
+	}
+
+	before := hybrid.SyncStatus(ctx)
+	if !before.Enabled || !before.PrimaryReachable || before.PrimaryChannels != 1 || !before.LastSyncAt.IsZero() {
+		t.Fatalf("unexpected status before resync: %+v", before)
+	}
+
+	if err := hybrid.ResyncConfig(ctx); err != nil {
+		t.Fatalf("ResyncConfig: %v", err)
+	}
+
+	after := hybrid.SyncStatus(ctx)
+	if after.CacheChannels != 1 || after.PrimaryChannels != 1 || after.LastSyncAt.IsZero() || after.LastSyncError != "" {
+		t.Fatalf("unexpected status after resync: %+v", after)
+	}
+	cached, err := sqlite.GetConfig(ctx, stale.ID)
+	if err != nil || cached.Name != "drifted" {
+		t.Fatalf("stale cache row should be replaced by primary data: %v %+v", err, cached)
+	}
+	tokens, err := sqlite.ListAuthTokens(ctx)
+	if err != nil || len(tokens) != 0 {
+		t.Fatalf("empty primary table must clear cache: %v %d", err, len(tokens))
+	}
+}
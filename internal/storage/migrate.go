@@ -65,6 +65,8 @@ func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
 		schema.DefineDebugLogsTable,
 		schema.DefineModelFingerprintsTable,
 		schema.DefineFingerprintTestResultsTable,
+		schema.DefineStatsDailyTable,
+		schema.DefineAuditLogsTable,
 	}
 
 	// 一次性预查全库索引，避免每张表单独 SELECT 网络往返
@@ -110,6 +112,21 @@ func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
 			if err := ensureLogsCostMultiplier(ctx, db, dialect); err != nil {
 				return fmt.Errorf("migrate logs cost_multiplier: %w", err)
 			}
+			if err := ensureLogsRequestID(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate logs request_id: %w", err)
+			}
+			if err := ensureLogsPath(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate logs path: %w", err)
+			}
+			if err := ensureLogsErrorDetail(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate logs error_detail: %w", err)
+			}
+			if err := ensureLogsAttemptNumber(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate logs attempt_number: %w", err)
+			}
+			if err := ensureLogsIsSlow(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate logs is_slow: %w", err)
+			}
 		}
 
 		// 增量迁移：确保channels表有daily_cost_limit字段（2026-01新增）
@@ -141,6 +158,45 @@ func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
 			if err := ensureChannelsProxyURL(ctx, db, dialect); err != nil {
 				return fmt.Errorf("migrate channels proxy_url: %w", err)
 			}
+			if err := ensureChannelsAllowedMethods(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels allowed_methods: %w", err)
+			}
+			if err := ensureChannelsRedirectRoutingOnly(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels redirect_routing_only: %w", err)
+			}
+			if err := ensureChannelsTokenLimits(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels token limits: %w", err)
+			}
+			if err := ensureChannelsCacheable(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels cacheable: %w", err)
+			}
+			if err := ensureChannelsRestoreResponseModel(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels restore_response_model: %w", err)
+			}
+			if err := ensureChannelsSuccessCodes(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels success_codes: %w", err)
+			}
+			if err := ensureChannelsActiveSchedule(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels active_schedule: %w", err)
+			}
+			if err := ensureChannelsBlockedModels(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels blocked_models: %w", err)
+			}
+			if err := ensureChannelsStripHeaders(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels strip_headers: %w", err)
+			}
+			if err := ensureChannelsRetryErrorSubstrings(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels retry_error_substrings: %w", err)
+			}
+			if err := ensureChannelsConsecutiveFailures(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels consecutive_failures: %w", err)
+			}
+			if err := ensureChannelsMaintenance(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels maintenance: %w", err)
+			}
+			if err := ensureChannelsLastUsedAt(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate channels last_used_at: %w", err)
+			}
 			// 增量迁移：将url字段从VARCHAR(191)扩展为TEXT（支持多URL存储）
 			if err := migrateChannelsURLToText(ctx, db, dialect); err != nil {
 				return fmt.Errorf("migrate channels url to text: %w", err)
@@ -158,6 +214,15 @@ func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
 			if err := ensureAPIKeysNote(ctx, db, dialect); err != nil {
 				return fmt.Errorf("migrate api_keys note: %w", err)
 			}
+			if err := ensureAPIKeysKeyGroup(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate api_keys key_group: %w", err)
+			}
+			if err := ensureAPIKeysRPMLimit(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate api_keys rpm_limit: %w", err)
+			}
+			if err := ensureAPIKeysLastUsedAt(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate api_keys last_used_at: %w", err)
+			}
 		}
 
 		// 增量迁移：确保auth_tokens表有缓存token字段（2025-12新增）
@@ -195,6 +260,9 @@ func migrate(ctx context.Context, db *sql.DB, dialect Dialect) error {
 			if err := validateAuthTokensMaxConcurrency(ctx, db); err != nil {
 				return fmt.Errorf("validate auth_tokens max_concurrency: %w", err)
 			}
+			if err := ensureAuthTokensRPMLimit(ctx, db, dialect); err != nil {
+				return fmt.Errorf("migrate auth_tokens rpm_limit: %w", err)
+			}
 		}
 
 		// 增量迁移：channel_models表添加redirect_model字段，迁移数据后删除channels冗余字段
@@ -380,6 +448,9 @@ func initDefaultSettings(ctx context.Context, db *sql.DB, dialect Dialect) error
 		{"auto_update_interval_hours", "12", "int", "自动更新检测间隔(小时整数,0=关闭,启用时最低1小时)", "12"},
 		{"log_channel_click_action", "edit", "string", "日志页点击渠道名行为(edit=打开编辑器,navigate=跳转到渠道管理定位)", "edit"},
 		{"channel_stats_range", "today", "string", "渠道管理费用统计范围", "today"},
+		// 模型月度预算
+		{"model_monthly_budgets", "{}", "string", "模型月度预算(JSON,如{\"gpt-4o\":100},单位USD,按倍率后成本统计)", "{}"},
+		{"model_budget_enforce", "false", "bool", "模型超出月度预算时拒绝请求(返回402)", "false"},
 		// 健康度排序配置
 		{"enable_health_score", "false", "bool", "启用基于健康度的渠道动态排序", "false"},
 		{"success_rate_penalty_weight", "100", "int", "成功率惩罚权重(乘以失败率)", "100"},
@@ -392,6 +463,11 @@ func initDefaultSettings(ctx context.Context, db *sql.DB, dialect Dialect) error
 		{"ttfb_min_confident_sample", "10", "int", "首字置信样本量阈值", "10"},
 		// 冷却兜底配置
 		{"cooldown_fallback_enabled", "true", "bool", "所有渠道冷却时选最优渠道兜底(关闭则直接拒绝请求)", "true"},
+		{"channel_pin_fallback", "false", "bool", "X-CCLoad-Channel 固定的渠道不可用时回退正常选路(关闭则返回503,修改后重启生效)", "false"},
+		{"circuit_breaker_threshold", "0", "int", "渠道熔断阈值(连续N个冷却周期无成功则熔断,0=禁用,修改后重启生效)", "0"},
+		{"circuit_breaker_reset_minutes", "60", "int", "渠道熔断持续时间(分钟,到期后放行试探请求;手动测试成功立即恢复,修改后重启生效)", "60"},
+		// 维护模式
+		{"maintenance_mode", "false", "bool", "维护模式(只读:代理照常,管理端写操作返回503;建议通过维护模式接口切换)", "false"},
 		// Debug日志配置
 		{"debug_log_enabled", "false", "bool", "启用Debug日志(记录上游请求/响应原始数据)", "false"},
 		{"debug_log_retention_minutes", "2", "int", "Debug日志保留时长(分钟,1-1440)", "2"},
@@ -470,6 +546,7 @@ func initDefaultSettings(ctx context.Context, db *sql.DB, dialect Dialect) error
 
 	obsoleteKeys := []string{
 		"88code_free_only", // 2026-01移除：88code免费订阅限制功能已删除
+		"default_model",    // 改为环境变量 CCLOAD_DEFAULT_MODEL
 	}
 	for _, key := range obsoleteKeys {
 		_ = deleteSystemSetting(ctx, db, dialect, key)
@@ -450,6 +450,41 @@ func ensureLogsCostMultiplier(ctx context.Context, db *sql.DB, dialect Dialect)
 		"REAL NOT NULL DEFAULT 1")
 }
 
+// ensureLogsRequestID 确保logs表有request_id字段（2026-10新增，X-Request-Id 关联）
+func ensureLogsRequestID(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "logs", "request_id",
+		"VARCHAR(64) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureLogsPath 确保logs表有path字段（2026-10新增，记录客户端请求路径）
+func ensureLogsPath(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "logs", "path",
+		"VARCHAR(512) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureLogsErrorDetail 确保logs表有error_detail字段（2026-10新增，错误日志完整上游错误）
+func ensureLogsErrorDetail(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "logs", "error_detail", "TEXT", "TEXT")
+}
+
+// ensureLogsAttemptNumber 确保logs表有attempt_number/channel_attempt字段（2026-10新增，重试位置分析）
+func ensureLogsAttemptNumber(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	if err := ensureColumn(ctx, db, dialect, "logs", "attempt_number",
+		"INT NOT NULL DEFAULT 0", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return ensureColumn(ctx, db, dialect, "logs", "channel_attempt",
+		"INT NOT NULL DEFAULT 0", "INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureLogsIsSlow 确保logs表有is_slow字段（2026-10新增，慢请求标记）
+func ensureLogsIsSlow(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "logs", "is_slow",
+		"TINYINT NOT NULL DEFAULT 0", "INTEGER NOT NULL DEFAULT 0")
+}
+
 // ensureAuthTokensCacheFields 确保auth_tokens表有缓存token字段(2025-12新增,支持MySQL和SQLite)
 func ensureAuthTokensCacheFields(ctx context.Context, db *sql.DB, dialect Dialect) error {
 	switch dialect {
@@ -561,6 +596,13 @@ func ensureChannelsRPMLimit(ctx context.Context, db *sql.DB, dialect Dialect) er
 		"INTEGER NOT NULL DEFAULT 0")
 }
 
+// ensureAuthTokensRPMLimit 确保auth_tokens表有令牌RPM限制字段（0=无限制）。
+func ensureAuthTokensRPMLimit(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "auth_tokens", "rpm_limit",
+		"INT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
 // ensureChannelsMaxConcurrency 确保channels表有max_concurrency字段（0=无限制）。
 func ensureChannelsMaxConcurrency(ctx context.Context, db *sql.DB, dialect Dialect) error {
 	return ensureColumn(ctx, db, dialect, "channels", "max_concurrency",
@@ -598,6 +640,101 @@ func ensureChannelsProxyURL(ctx context.Context, db *sql.DB, dialect Dialect) er
 		"TEXT NOT NULL DEFAULT ''")
 }
 
+func ensureChannelsAllowedMethods(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "allowed_methods",
+		"VARCHAR(64) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsRedirectRoutingOnly 模型重定向仅用于路由匹配，不改写上游请求中的模型名
+func ensureChannelsRedirectRoutingOnly(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "redirect_routing_only",
+		"TINYINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsTokenLimits 渠道级输入/输出 token 上限（0=无限制）
+func ensureChannelsTokenLimits(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	if err := ensureColumn(ctx, db, dialect, "channels", "max_input_tokens",
+		"INT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return ensureColumn(ctx, db, dialect, "channels", "max_output_tokens",
+		"INT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsCacheable 渠道级上游响应缓存开关
+func ensureChannelsCacheable(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "cacheable",
+		"TINYINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsRestoreResponseModel 渠道级响应模型名还原开关（重定向后将响应 model 改回客户端请求的模型）
+func ensureChannelsRestoreResponseModel(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "restore_response_model",
+		"TINYINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsSuccessCodes 渠道级成功状态码范围（空=默认 2xx）
+func ensureChannelsSuccessCodes(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "success_codes",
+		"VARCHAR(255) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsActiveSchedule 渠道启用时段（空=全天可用）
+func ensureChannelsActiveSchedule(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "active_schedule",
+		"VARCHAR(255) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsBlockedModels 渠道级模型屏蔽列表（逗号分隔，空=不屏蔽）
+func ensureChannelsBlockedModels(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "blocked_models",
+		"VARCHAR(1024) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsStripHeaders 渠道级额外剥离的客户端请求头（逗号分隔，支持 * 后缀前缀匹配，空=仅全局默认）
+func ensureChannelsStripHeaders(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "strip_headers",
+		"VARCHAR(1024) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsRetryErrorSubstrings 渠道级强制重试的上游错误体子串（换行分隔，空=不启用）
+func ensureChannelsRetryErrorSubstrings(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "retry_error_substrings",
+		"VARCHAR(1024) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureChannelsConsecutiveFailures 熔断计数：连续冷却周期内无一次成功的次数
+func ensureChannelsConsecutiveFailures(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "consecutive_failures",
+		"INT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsMaintenance 渠道维护模式开关（维护中不参与选择且冻结冷却）
+func ensureChannelsMaintenance(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "maintenance",
+		"TINYINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureChannelsLastUsedAt 确保channels表有last_used_at字段（最后成功代理时间，Unix毫秒；LRU选择用）
+func ensureChannelsLastUsedAt(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "channels", "last_used_at",
+		"BIGINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
 // migrateChannelsURLToText 将channels.url从VARCHAR(191)扩展为TEXT
 // 支持多URL存储（换行分隔）
 func migrateChannelsURLToText(ctx context.Context, db *sql.DB, dialect Dialect) error {
@@ -694,6 +831,27 @@ func ensureAPIKeysNote(ctx context.Context, db *sql.DB, dialect Dialect) error {
 		"TEXT NOT NULL DEFAULT ''")
 }
 
+// ensureAPIKeysKeyGroup 确保api_keys表有key_group字段（跨渠道共享冷却分组）
+func ensureAPIKeysKeyGroup(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "api_keys", "key_group",
+		"VARCHAR(64) NOT NULL DEFAULT ''",
+		"TEXT NOT NULL DEFAULT ''")
+}
+
+// ensureAPIKeysRPMLimit 确保api_keys表有rpm_limit字段（Key级每分钟请求上限，0=不限）
+func ensureAPIKeysRPMLimit(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "api_keys", "rpm_limit",
+		"INT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
+// ensureAPIKeysLastUsedAt 确保api_keys表有last_used_at字段（最后成功使用时间，Unix毫秒）
+func ensureAPIKeysLastUsedAt(ctx context.Context, db *sql.DB, dialect Dialect) error {
+	return ensureColumn(ctx, db, dialect, "api_keys", "last_used_at",
+		"BIGINT NOT NULL DEFAULT 0",
+		"INTEGER NOT NULL DEFAULT 0")
+}
+
 // ensureAuthTokensEffectiveCost 确保auth_tokens表有effective_cost_usd字段（2026-07新增）
 func ensureAuthTokensEffectiveCost(ctx context.Context, db *sql.DB, dialect Dialect) error {
 	if err := ensureColumn(ctx, db, dialect, "auth_tokens", "effective_cost_usd",
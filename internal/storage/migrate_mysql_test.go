@@ -140,7 +140,7 @@ func cleanupMySQLTables(t *testing.T, db *sql.DB) {
 	_, _ = db.Exec("SET FOREIGN_KEY_CHECKS = 0")
 	defer func() { _, _ = db.Exec("SET FOREIGN_KEY_CHECKS = 1") }()
 
-	tables := []string{"fingerprint_test_results", "model_fingerprints", "logs", "web_sessions", "admin_sessions", "system_settings", "auth_tokens", "channel_models", "api_keys", "channels", "schema_migrations"}
+	tables := []string{"stats_daily", "fingerprint_test_results", "model_fingerprints", "logs", "web_sessions", "admin_sessions", "system_settings", "auth_tokens", "channel_models", "api_keys", "channels", "schema_migrations"}
 	for _, table := range tables {
 		_, _ = db.Exec("DROP TABLE IF EXISTS " + table)
 	}
@@ -188,6 +188,18 @@ func TestMySQL(t *testing.T) {
 		verifyFingerprintStorageContract(t, store)
 	})
 
+	t.Run("APIKeysStorageContract", func(t *testing.T) {
+		cleanupMySQLTables(t, env.db)
+
+		store, err := CreateMySQLStoreForTest(env.dsn)
+		if err != nil {
+			t.Fatalf("迁移失败: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+
+		verifyAPIKeysStorageContract(t, store)
+	})
+
 	t.Run("Idempotent", func(t *testing.T) {
 		cleanupMySQLTables(t, env.db)
 
@@ -245,9 +257,22 @@ func TestMySQL(t *testing.T) {
 			t.Logf("列 auth_tokens.%s 存在", col)
 		}
 
+		// 验证 api_keys 表的新增列
+		for _, col := range []string{"note", "disabled", "key_group"} {
+			var columnName string
+			err := env.db.QueryRow(
+				"SELECT COLUMN_NAME FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'api_keys' AND COLUMN_NAME = ?",
+				testMySQLDB, col,
+			).Scan(&columnName)
+			if err != nil {
+				t.Fatalf("列 api_keys.%s 不存在: %v", col, err)
+			}
+			t.Logf("列 api_keys.%s 存在", col)
+		}
+
 		// 验证 channels 表的新增列
 		var columnName string
-		for _, col := range []string{"daily_cost_limit", "scheduled_check_model"} {
+		for _, col := range []string{"daily_cost_limit", "scheduled_check_model", "consecutive_failures"} {
 			err = env.db.QueryRow(
 				"SELECT COLUMN_NAME FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'channels' AND COLUMN_NAME = ?",
 				testMySQLDB, col,
@@ -370,6 +395,17 @@ func TestMySQL(t *testing.T) {
 			t.Fatalf("api_keys.api_key 可空性错误: got=%s want=NO", isNullable)
 		}
 
+		// 旧表缺失的 api_keys 元数据列应被补齐
+		for _, col := range []string{"note", "disabled", "key_group"} {
+			var columnName string
+			if err := env.db.QueryRow(
+				"SELECT COLUMN_NAME FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'api_keys' AND COLUMN_NAME = ?",
+				col,
+			).Scan(&columnName); err != nil {
+				t.Fatalf("旧表未补齐列 api_keys.%s: %v", col, err)
+			}
+		}
+
 		longKey := "sk-" + strings.Repeat("x", 197) // 长度 200，验证迁移后的 VARCHAR(255) 契约
 		created, updated, err := store.ImportChannelBatch(context.Background(), []*model.ChannelWithKeys{
 			{
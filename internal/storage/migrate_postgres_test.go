@@ -125,7 +125,7 @@ func cleanupPostgresTables(t *testing.T, db *sql.DB) {
 	t.Helper()
 
 	tables := []string{
-		"fingerprint_test_results", "model_fingerprints", "debug_logs", "logs", "web_sessions", "admin_sessions", "system_settings",
+		"stats_daily", "fingerprint_test_results", "model_fingerprints", "debug_logs", "logs", "web_sessions", "admin_sessions", "system_settings",
 		"auth_tokens", "channel_models", "channel_model_cooldowns", "channel_protocol_transforms", "api_keys", "channel_url_states",
 		"channels", "schema_migrations", "key_rr",
 	}
@@ -217,9 +217,24 @@ func TestPostgres(t *testing.T) {
 		for _, col := range []string{"allowed_models", "cost_used_microusd", "cost_limit_microusd"} {
 			checkCol("auth_tokens", col)
 		}
-		for _, col := range []string{"daily_cost_limit", "scheduled_check_model", "cost_multiplier"} {
+		for _, col := range []string{"daily_cost_limit", "scheduled_check_model", "cost_multiplier", "consecutive_failures"} {
 			checkCol("channels", col)
 		}
+		for _, col := range []string{"note", "disabled", "key_group"} {
+			checkCol("api_keys", col)
+		}
+	})
+
+	t.Run("APIKeysStorageContract", func(t *testing.T) {
+		cleanupPostgresTables(t, env.db)
+
+		store, err := CreatePostgresStoreForTest(env.dsn)
+		if err != nil {
+			t.Fatalf("迁移失败: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+
+		verifyAPIKeysStorageContract(t, store)
 	})
 
 	t.Run("CRUD_Settings_Channel_Token_Log", func(t *testing.T) {
@@ -727,6 +727,10 @@ func TestInitDefaultSettings_SQLite(t *testing.T) {
 		"health_score_update_interval",
 		"health_min_confident_sample",
 		"cooldown_fallback_enabled",
+		"channel_pin_fallback",
+		"maintenance_mode",
+		"circuit_breaker_threshold",
+		"circuit_breaker_reset_minutes",
 	}
 
 	for _, key := range expectedKeys {
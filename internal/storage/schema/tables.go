@@ -16,10 +16,24 @@ func DefineChannelsTable() *TableBuilder {
 		Column("scheduled_check_model VARCHAR(191) NOT NULL DEFAULT ''").
 		Column("cooldown_until BIGINT NOT NULL DEFAULT 0").
 		Column("cooldown_duration_ms BIGINT NOT NULL DEFAULT 0").
+		Column("consecutive_failures INT NOT NULL DEFAULT 0").
+		Column("maintenance TINYINT NOT NULL DEFAULT 0").
+		Column("last_used_at BIGINT NOT NULL DEFAULT 0").
 		Column("daily_cost_limit DOUBLE NOT NULL DEFAULT 0").
 		Column("cost_multiplier DOUBLE NOT NULL DEFAULT 1").
 		Column("custom_request_rules TEXT").
 		Column("proxy_url VARCHAR(255) NOT NULL DEFAULT ''").
+		Column("allowed_methods VARCHAR(64) NOT NULL DEFAULT ''").
+		Column("redirect_routing_only TINYINT NOT NULL DEFAULT 0").
+		Column("max_input_tokens INT NOT NULL DEFAULT 0").
+		Column("max_output_tokens INT NOT NULL DEFAULT 0").
+		Column("cacheable TINYINT NOT NULL DEFAULT 0").
+		Column("restore_response_model TINYINT NOT NULL DEFAULT 0").
+		Column("success_codes VARCHAR(255) NOT NULL DEFAULT ''").
+		Column("active_schedule VARCHAR(255) NOT NULL DEFAULT ''").
+		Column("blocked_models VARCHAR(1024) NOT NULL DEFAULT ''").
+		Column("strip_headers VARCHAR(1024) NOT NULL DEFAULT ''").
+		Column("retry_error_substrings VARCHAR(1024) NOT NULL DEFAULT ''").
 		Column("created_at BIGINT NOT NULL").
 		Column("updated_at BIGINT NOT NULL").
 		Index("idx_channels_enabled", "enabled").
@@ -36,16 +50,20 @@ func DefineAPIKeysTable() *TableBuilder {
 		Column("key_index INT NOT NULL").
 		Column("api_key VARCHAR(255) NOT NULL").
 		Column("note VARCHAR(512) NOT NULL DEFAULT ''").
+		Column("key_group VARCHAR(64) NOT NULL DEFAULT ''").
+		Column("rpm_limit INT NOT NULL DEFAULT 0").
 		Column("key_strategy VARCHAR(32) NOT NULL DEFAULT 'sequential'").
 		Column("cooldown_until BIGINT NOT NULL DEFAULT 0").
 		Column("cooldown_duration_ms BIGINT NOT NULL DEFAULT 0").
 		Column("disabled TINYINT NOT NULL DEFAULT 0").
+		Column("last_used_at BIGINT NOT NULL DEFAULT 0").
 		Column("created_at BIGINT NOT NULL").
 		Column("updated_at BIGINT NOT NULL").
 		Column("UNIQUE KEY uk_channel_key (channel_id, key_index)").
 		Column("FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE").
 		Index("idx_api_keys_cooldown", "cooldown_until").
-		Index("idx_api_keys_channel_cooldown", "channel_id, cooldown_until")
+		Index("idx_api_keys_channel_cooldown", "channel_id, cooldown_until").
+		Index("idx_api_keys_key_group", "key_group")
 }
 
 // DefineChannelModelsTable 定义channel_models表结构
@@ -97,6 +115,23 @@ func DefineChannelURLStatesTable() *TableBuilder {
 		Column("FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE")
 }
 
+// DefineStatsDailyTable 定义stats_daily表结构（按天/渠道/模型的日志汇总，不受日志保留期影响）
+func DefineStatsDailyTable() *TableBuilder {
+	return NewTable("stats_daily").
+		Column("day VARCHAR(10) NOT NULL").
+		Column("channel_id INT NOT NULL").
+		Column("model VARCHAR(191) NOT NULL DEFAULT ''").
+		Column("success_count BIGINT NOT NULL DEFAULT 0").
+		Column("error_count BIGINT NOT NULL DEFAULT 0").
+		Column("input_tokens BIGINT NOT NULL DEFAULT 0").
+		Column("output_tokens BIGINT NOT NULL DEFAULT 0").
+		Column("cache_read_input_tokens BIGINT NOT NULL DEFAULT 0").
+		Column("cache_creation_input_tokens BIGINT NOT NULL DEFAULT 0").
+		Column("cost DOUBLE NOT NULL DEFAULT 0.0").
+		Column("updated_at BIGINT NOT NULL").
+		Column("PRIMARY KEY (day, channel_id, model)")
+}
+
 // DefineAuthTokensTable 定义auth_tokens表结构
 func DefineAuthTokensTable() *TableBuilder {
 	return NewTable("auth_tokens").
@@ -125,6 +160,7 @@ func DefineAuthTokensTable() *TableBuilder {
 		Column("allowed_channel_ids VARCHAR(2000) NOT NULL DEFAULT ''").
 		Column("channel_restriction_mode VARCHAR(16) NOT NULL DEFAULT 'allow'").
 		Column("max_concurrency INT NOT NULL DEFAULT 0").
+		Column("rpm_limit INT NOT NULL DEFAULT 0").
 		Index("idx_auth_tokens_active", "is_active").
 		Index("idx_auth_tokens_expires", "expires_at")
 }
@@ -180,6 +216,8 @@ func DefineLogsTable() *TableBuilder {
 		Column("base_url VARCHAR(500) NOT NULL DEFAULT ''").    // 请求使用的上游URL（多URL场景）
 		Column("service_tier VARCHAR(20) NOT NULL DEFAULT ''"). // OpenAI service_tier: priority/flex
 		Column("thinking_effort VARCHAR(32) NOT NULL DEFAULT ''").
+		Column("request_id VARCHAR(64) NOT NULL DEFAULT ''"). // 请求ID（X-Request-Id，用于关联客户端与服务端日志）
+		Column("path VARCHAR(512) NOT NULL DEFAULT ''").      // 客户端请求路径（不含 query）
 		Column("input_tokens INT NOT NULL DEFAULT 0").
 		Column("output_tokens INT NOT NULL DEFAULT 0").
 		Column("reasoning_tokens INT NOT NULL DEFAULT 0").
@@ -189,6 +227,10 @@ func DefineLogsTable() *TableBuilder {
 		Column("cache_1h_input_tokens INT NOT NULL DEFAULT 0").       // 1小时缓存写入Token数（新增2025-12）
 		Column("cost DOUBLE NOT NULL DEFAULT 0.0").
 		Column("cost_multiplier DOUBLE NOT NULL DEFAULT 1").
+		Column("error_detail TEXT").                      // 错误日志的完整上游错误（message 截断至512字符，此处保留至8KB；列表查询不读取）
+		Column("attempt_number INT NOT NULL DEFAULT 0").  // 本请求第几次上游尝试（1=首次，0=历史数据未知）
+		Column("channel_attempt INT NOT NULL DEFAULT 0"). // 本请求第几个渠道（1=首选渠道）
+		Column("is_slow TINYINT NOT NULL DEFAULT 0").     // 慢请求标记（CCLOAD_SLOW_REQUEST_MS）
 		Index("idx_logs_time_model", "time, model").
 		Index("idx_logs_time_status", "time, status_code").
 		Index("idx_logs_time_channel_model", "time, channel_id, model").
@@ -199,7 +241,8 @@ func DefineLogsTable() *TableBuilder {
 		Index("idx_logs_time_auth_token", "time, auth_token_id").  // 按时间+令牌查询
 		Index("idx_logs_time_actual_model", "time, actual_model"). // 按时间+实际模型查询
 		Index("idx_logs_source_time", "log_source, time").
-		Index("idx_logs_source_minute", "log_source, minute_bucket")
+		Index("idx_logs_source_minute", "log_source, minute_bucket").
+		Index("idx_logs_request_id", "request_id")
 }
 
 // DefineModelFingerprintsTable 定义model_fingerprints表结构（模型指纹基线）
@@ -241,6 +284,21 @@ func DefineFingerprintTestResultsTable() *TableBuilder {
 		Index("idx_fp_test_results_created", "created_at DESC")
 }
 
+// DefineAuditLogsTable 定义audit_logs表结构（管理端变更审计，长期保留）
+func DefineAuditLogsTable() *TableBuilder {
+	return NewTable("audit_logs").
+		Column("id INT PRIMARY KEY AUTO_INCREMENT").
+		Column("time BIGINT NOT NULL"). // Unix毫秒
+		Column("actor VARCHAR(64) NOT NULL DEFAULT ''").
+		Column("client_ip VARCHAR(45) NOT NULL DEFAULT ''").
+		Column("action VARCHAR(191) NOT NULL DEFAULT ''").
+		Column("channel_id INT NOT NULL DEFAULT 0").
+		Column("status_code INT NOT NULL DEFAULT 0").
+		Column("changes TEXT").
+		Index("idx_audit_logs_time", "time").
+		Index("idx_audit_logs_channel_time", "channel_id, time")
+}
+
 // DefineDebugLogsTable 定义debug_logs表结构（上游请求/响应原始数据）
 // log_id 与 logs.id 1:1 对应，直接作为主键，无需独立自增ID
 func DefineDebugLogsTable() *TableBuilder {
@@ -18,7 +18,7 @@ import (
 func (s *SQLStore) GetAPIKeys(ctx context.Context, channelID int64) ([]*model.APIKey, error) {
 	query := `
 		SELECT id, channel_id, key_index, api_key, key_strategy,
-		       note, cooldown_until, cooldown_duration_ms, disabled, created_at, updated_at
+		       note, key_group, rpm_limit, cooldown_until, cooldown_duration_ms, last_used_at, disabled, created_at, updated_at
 		FROM api_keys
 		WHERE channel_id = ?
 		ORDER BY key_index ASC
@@ -42,8 +42,11 @@ func (s *SQLStore) GetAPIKeys(ctx context.Context, channelID int64) ([]*model.AP
 			&key.APIKey,
 			&key.KeyStrategy,
 			&key.Note,
+			&key.KeyGroup,
+			&key.RPMLimit,
 			&key.CooldownUntil,
 			&key.CooldownDurationMs,
+			&key.LastUsedAt,
 			&disabled,
 			&createdAt,
 			&updatedAt,
@@ -72,7 +75,7 @@ func (s *SQLStore) GetAPIKeys(ctx context.Context, channelID int64) ([]*model.AP
 func (s *SQLStore) GetAPIKey(ctx context.Context, channelID int64, keyIndex int) (*model.APIKey, error) {
 	query := `
 		SELECT id, channel_id, key_index, api_key, key_strategy,
-		       note, cooldown_until, cooldown_duration_ms, disabled, created_at, updated_at
+		       note, key_group, rpm_limit, cooldown_until, cooldown_duration_ms, last_used_at, disabled, created_at, updated_at
 		FROM api_keys
 		WHERE channel_id = ? AND key_index = ?
 	`
@@ -89,8 +92,11 @@ func (s *SQLStore) GetAPIKey(ctx context.Context, channelID int64, keyIndex int)
 		&key.APIKey,
 		&key.KeyStrategy,
 		&key.Note,
+		&key.KeyGroup,
+		&key.RPMLimit,
 		&key.CooldownUntil,
 		&key.CooldownDurationMs,
+		&key.LastUsedAt,
 		&disabled,
 		&createdAt,
 		&updatedAt,
@@ -115,8 +121,6 @@ func (s *SQLStore) CreateAPIKeysBatch(ctx context.Context, keys []*model.APIKey)
 		return nil
 	}
 
-	nowUnix := timeToUnix(time.Now())
-
 	// 使用事务确保原子性
 	tx, err := s.BeginTx(ctx, nil)
 	if err != nil {
@@ -124,7 +128,114 @@ func (s *SQLStore) CreateAPIKeysBatch(ctx context.Context, keys []*model.APIKey)
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// 构建批量插入语句（每批最多100条，避免SQL语句过长）
+	if err := s.insertAPIKeysTx(ctx, tx, keys); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceAPIKeys 单事务将渠道的 API Keys 同步为 keys（供 Key 轮换与渠道编辑使用）
+// 按明文匹配已有记录：保留的 Key 原地更新（行 ID 不变，Key 级 RPM 计数不丢失），仅插入新增 Key、删除已移除 Key。
+// 调用方负责设置连续的 key_index 以及需要保留的冷却/禁用状态。
+func (s *SQLStore) ReplaceAPIKeys(ctx context.Context, channelID int64, keys []*model.APIKey) error {
+	for _, key := range keys {
+		if key.ChannelID != channelID {
+			return fmt.Errorf("api key index %d belongs to channel %d, want %d", key.KeyIndex, key.ChannelID, channelID)
+		}
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin replace api keys transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, s.q(`SELECT id, api_key FROM api_keys WHERE channel_id = ? ORDER BY key_index ASC`), channelID)
+	if err != nil {
+		return fmt.Errorf("query existing api keys: %w", err)
+	}
+	existing := make(map[string][]int64)
+	for rows.Next() {
+		var id int64
+		var apiKey string
+		if err := rows.Scan(&id, &apiKey); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan existing api key: %w", err)
+		}
+		existing[apiKey] = append(existing[apiKey], id)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("close existing api keys: %w", err)
+	}
+
+	retainedIDs := make([]int64, len(keys)) // 0 表示新增
+	var inserts []*model.APIKey
+	for i, key := range keys {
+		if ids := existing[key.APIKey]; len(ids) > 0 {
+			retainedIDs[i] = ids[0]
+			existing[key.APIKey] = ids[1:]
+			continue
+		}
+		inserts = append(inserts, key)
+	}
+
+	for _, ids := range existing {
+		for _, id := range ids {
+			if _, err := s.execTx(ctx, tx, `DELETE FROM api_keys WHERE id = ?`, id); err != nil {
+				return fmt.Errorf("delete api key %d: %w", id, err)
+			}
+		}
+	}
+
+	// 两阶段改写 key_index：先挪到负数临时位（-id 互不冲突），再写入目标值，避免 (channel_id, key_index) 唯一约束冲突
+	for _, id := range retainedIDs {
+		if id == 0 {
+			continue
+		}
+		if _, err := s.execTx(ctx, tx, `UPDATE api_keys SET key_index = ? WHERE id = ?`, -id, id); err != nil {
+			return fmt.Errorf("park api key %d: %w", id, err)
+		}
+	}
+	nowUnix := timeToUnix(time.Now())
+	for i, id := range retainedIDs {
+		if id == 0 {
+			continue
+		}
+		key := keys[i]
+		strategy := key.KeyStrategy
+		if strategy == "" {
+			strategy = model.KeyStrategySequential
+		}
+		if _, err := s.execTx(ctx, tx, `
+			UPDATE api_keys
+			SET key_index = ?, note = ?, key_group = ?, rpm_limit = ?, key_strategy = ?,
+			    cooldown_until = ?, cooldown_duration_ms = ?, last_used_at = ?, disabled = ?, updated_at = ?
+			WHERE id = ?
+		`, key.KeyIndex, key.Note, key.KeyGroup, key.RPMLimit, strategy,
+			key.CooldownUntil, key.CooldownDurationMs, key.LastUsedAt, boolToInt(key.Disabled), nowUnix, id); err != nil {
+			return fmt.Errorf("update api key %d: %w", id, err)
+		}
+	}
+
+	if err := s.insertAPIKeysTx(ctx, tx, inserts); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit replace api keys: %w", err)
+	}
+	return nil
+}
+
+// insertAPIKeysTx 在事务内批量插入 API Keys（每批最多100条，避免SQL语句过长）
+func (s *SQLStore) insertAPIKeysTx(ctx context.Context, tx *sql.Tx, keys []*model.APIKey) error {
+	nowUnix := timeToUnix(time.Now())
+
 	const batchSize = 100
 	for i := 0; i < len(keys); i += batchSize {
 		end := i + batchSize
@@ -135,33 +246,28 @@ func (s *SQLStore) CreateAPIKeysBatch(ctx context.Context, keys []*model.APIKey)
 
 		// 构建 VALUES 部分
 		var sb strings.Builder
-		sb.WriteString(`INSERT INTO api_keys (channel_id, key_index, api_key, note, key_strategy,
-		                      cooldown_until, cooldown_duration_ms, disabled, created_at, updated_at) VALUES `)
+		sb.WriteString(`INSERT INTO api_keys (channel_id, key_index, api_key, note, key_group, rpm_limit, key_strategy,
+		                      cooldown_until, cooldown_duration_ms, last_used_at, disabled, created_at, updated_at) VALUES `)
 
-		args := make([]any, 0, len(batch)*10)
+		args := make([]any, 0, len(batch)*13)
 		for j, key := range batch {
 			if j > 0 {
 				sb.WriteString(",")
 			}
-			sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 
 			strategy := key.KeyStrategy
 			if strategy == "" {
 				strategy = model.KeyStrategySequential
 			}
-			args = append(args, key.ChannelID, key.KeyIndex, key.APIKey, key.Note, strategy,
-				key.CooldownUntil, key.CooldownDurationMs, boolToInt(key.Disabled), nowUnix, nowUnix)
+			args = append(args, key.ChannelID, key.KeyIndex, key.APIKey, key.Note, key.KeyGroup, key.RPMLimit, strategy,
+				key.CooldownUntil, key.CooldownDurationMs, key.LastUsedAt, boolToInt(key.Disabled), nowUnix, nowUnix)
 		}
 
 		if _, err := s.execTx(ctx, tx, sb.String(), args...); err != nil {
 			return fmt.Errorf("batch insert api keys: %w", err)
 		}
 	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -221,6 +327,110 @@ func (s *SQLStore) UpdateAPIKeyNotes(ctx context.Context, channelID int64, notes
 	return nil
 }
 
+// UpdateAPIKeysLastUsed 批量更新 Key 最后使用时间（仅向前推进，不修改 updated_at）
+func (s *SQLStore) UpdateAPIKeysLastUsed(ctx context.Context, updates []model.KeyLastUsed) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update api keys last used transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := s.prepareTx(ctx, tx, `
+		UPDATE api_keys
+		SET last_used_at = ?
+		WHERE channel_id = ? AND key_index = ? AND last_used_at < ?
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare update api keys last used: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, u := range updates {
+		if _, err := stmt.ExecContext(ctx, u.LastUsedAt, u.ChannelID, u.KeyIndex, u.LastUsedAt); err != nil {
+			return fmt.Errorf("update api key last used channel %d index %d: %w", u.ChannelID, u.KeyIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update api keys last used: %w", err)
+	}
+	return nil
+}
+
+// UpdateAPIKeyGroups 按 key_index 更新已有 Key 的共享冷却分组（空字符串表示不分组）
+func (s *SQLStore) UpdateAPIKeyGroups(ctx context.Context, channelID int64, groupsByIndex map[int]string) error {
+	if len(groupsByIndex) == 0 {
+		return nil
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update api key groups transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := s.prepareTx(ctx, tx, `
+		UPDATE api_keys
+		SET key_group = ?, updated_at = ?
+		WHERE channel_id = ? AND key_index = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare update api key groups: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	updatedAtUnix := timeToUnix(time.Now())
+	for keyIndex, group := range groupsByIndex {
+		if _, err := stmt.ExecContext(ctx, group, updatedAtUnix, channelID, keyIndex); err != nil {
+			return fmt.Errorf("update api key group index %d: %w", keyIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update api key groups: %w", err)
+	}
+	return nil
+}
+
+// UpdateAPIKeyRPMLimits 按 key_index 更新已有 Key 的RPM上限（0表示不限）
+func (s *SQLStore) UpdateAPIKeyRPMLimits(ctx context.Context, channelID int64, limitsByIndex map[int]int) error {
+	if len(limitsByIndex) == 0 {
+		return nil
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update api key rpm limits transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := s.prepareTx(ctx, tx, `
+		UPDATE api_keys
+		SET rpm_limit = ?, updated_at = ?
+		WHERE channel_id = ? AND key_index = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare update api key rpm limits: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	updatedAtUnix := timeToUnix(time.Now())
+	for keyIndex, limit := range limitsByIndex {
+		if _, err := stmt.ExecContext(ctx, limit, updatedAtUnix, channelID, keyIndex); err != nil {
+			return fmt.Errorf("update api key rpm limit index %d: %w", keyIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update api key rpm limits: %w", err)
+	}
+	return nil
+}
+
 // DeleteAPIKey 删除指定的 API Key
 func (s *SQLStore) DeleteAPIKey(ctx context.Context, channelID int64, keyIndex int) error {
 	_, err := s.ExecContext(ctx, `
@@ -394,9 +604,9 @@ func (s *SQLStore) ImportChannelBatch(ctx context.Context, channels []*model.Cha
 
 		// 预编译API Key插入语句
 		keyStmt, err := s.prepareTx(ctx, tx, `
-			INSERT INTO api_keys (channel_id, key_index, api_key, note, key_strategy,
-			                      cooldown_until, cooldown_duration_ms, disabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO api_keys (channel_id, key_index, api_key, note, key_group, rpm_limit, key_strategy,
+			                      cooldown_until, cooldown_duration_ms, last_used_at, disabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`)
 		if err != nil {
 			return fmt.Errorf("prepare api key statement: %w", err)
@@ -468,8 +678,8 @@ func (s *SQLStore) ImportChannelBatch(ctx context.Context, channels []*model.Cha
 				cwk.APIKeys[i].ChannelID = channelID
 				key := cwk.APIKeys[i]
 				_, err := keyStmt.ExecContext(ctx,
-					channelID, key.KeyIndex, key.APIKey, key.Note, key.KeyStrategy,
-					key.CooldownUntil, key.CooldownDurationMs, boolToInt(key.Disabled), nowUnix, nowUnix)
+					channelID, key.KeyIndex, key.APIKey, key.Note, key.KeyGroup, key.RPMLimit, key.KeyStrategy,
+					key.CooldownUntil, key.CooldownDurationMs, key.LastUsedAt, boolToInt(key.Disabled), nowUnix, nowUnix)
 				if err != nil {
 					return fmt.Errorf("insert api key %d for channel %d: %w", key.KeyIndex, channelID, err)
 				}
@@ -508,7 +718,7 @@ func (s *SQLStore) ImportChannelBatch(ctx context.Context, channels []*model.Cha
 func (s *SQLStore) GetAllAPIKeys(ctx context.Context) (map[int64][]*model.APIKey, error) {
 	query := `
 		SELECT id, channel_id, key_index, api_key, key_strategy,
-		       note, cooldown_until, cooldown_duration_ms, disabled, created_at, updated_at
+		       note, key_group, rpm_limit, cooldown_until, cooldown_duration_ms, last_used_at, disabled, created_at, updated_at
 		FROM api_keys
 		ORDER BY channel_id ASC, key_index ASC
 	`
@@ -531,8 +741,11 @@ func (s *SQLStore) GetAllAPIKeys(ctx context.Context) (map[int64][]*model.APIKey
 			&key.APIKey,
 			&key.KeyStrategy,
 			&key.Note,
+			&key.KeyGroup,
+			&key.RPMLimit,
 			&key.CooldownUntil,
 			&key.CooldownDurationMs,
+			&key.LastUsedAt,
 			&disabled,
 			&createdAt,
 			&updatedAt,
@@ -116,6 +116,80 @@ func TestAPIKey_NotesPersistAndUpdate(t *testing.T) {
 	}
 }
 
+func TestAPIKey_RPMLimitPersistAndUpdate(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "rpm-limit.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "rpm-limit-channel")
+
+	keys := []*model.APIKey{
+		{ChannelID: channelID, KeyIndex: 0, APIKey: "sk-key-0", RPMLimit: 60, KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: channelID, KeyIndex: 1, APIKey: "sk-key-1", KeyStrategy: model.KeyStrategySequential},
+	}
+	if err := store.CreateAPIKeysBatch(ctx, keys); err != nil {
+		t.Fatalf("create api keys batch: %v", err)
+	}
+
+	got, err := store.GetAPIKey(ctx, channelID, 0)
+	if err != nil {
+		t.Fatalf("get api key: %v", err)
+	}
+	if got.RPMLimit != 60 {
+		t.Fatalf("rpm_limit after create = %d, want 60", got.RPMLimit)
+	}
+
+	if err := store.UpdateAPIKeyRPMLimits(ctx, channelID, map[int]int{0: 0, 1: 30}); err != nil {
+		t.Fatalf("update api key rpm limits: %v", err)
+	}
+
+	all, err := store.GetAllAPIKeys(ctx)
+	if err != nil {
+		t.Fatalf("get all api keys: %v", err)
+	}
+	if ks := all[channelID]; ks[0].RPMLimit != 0 || ks[1].RPMLimit != 30 {
+		t.Fatalf("rpm_limit after update = [%d, %d], want [0, 30]", ks[0].RPMLimit, ks[1].RPMLimit)
+	}
+}
+
+func TestAPIKey_UpdateLastUsedOnlyMovesForward(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "last_used.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "last-used-channel")
+
+	keys := []*model.APIKey{
+		{ChannelID: channelID, KeyIndex: 0, APIKey: "sk-key-0", KeyStrategy: model.KeyStrategySequential},
+		{ChannelID: channelID, KeyIndex: 1, APIKey: "sk-key-1", KeyStrategy: model.KeyStrategySequential},
+	}
+	if err := store.CreateAPIKeysBatch(ctx, keys); err != nil {
+		t.Fatalf("create api keys batch: %v", err)
+	}
+
+	if err := store.UpdateAPIKeysLastUsed(ctx, []model.KeyLastUsed{
+		{ChannelID: channelID, KeyIndex: 0, LastUsedAt: 2000},
+	}); err != nil {
+		t.Fatalf("update last used: %v", err)
+	}
+	// 较旧的时间戳不应覆盖
+	if err := store.UpdateAPIKeysLastUsed(ctx, []model.KeyLastUsed{
+		{ChannelID: channelID, KeyIndex: 0, LastUsedAt: 1000},
+	}); err != nil {
+		t.Fatalf("update last used (older): %v", err)
+	}
+
+	got, err := store.GetAPIKeys(ctx, channelID)
+	if err != nil {
+		t.Fatalf("get api keys: %v", err)
+	}
+	if got[0].LastUsedAt != 2000 || got[1].LastUsedAt != 0 {
+		t.Fatalf("last_used_at = [%d %d], want [2000 0]", got[0].LastUsedAt, got[1].LastUsedAt)
+	}
+}
+
 func TestAPIKey_Delete(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,80 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+// AddAuditLog 写入一条管理端审计记录
+func (s *SQLStore) AddAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	ts := entry.Time.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	var changes sql.NullString
+	if len(entry.Changes) > 0 {
+		data, err := marshalJSON("changes", entry.Changes)
+		if err != nil {
+			return err
+		}
+		changes = sql.NullString{String: data, Valid: true}
+	}
+	if _, err := s.ExecContext(ctx, `
+		INSERT INTO audit_logs (time, actor, client_ip, action, channel_id, status_code, changes)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, ts.UnixMilli(), entry.Actor, entry.ClientIP, entry.Action, entry.ChannelID, entry.StatusCode, changes); err != nil {
+		return fmt.Errorf("insert audit_logs: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLogs 查询 since 之后的审计记录，按时间倒序；channelID=0 表示不按渠道过滤
+func (s *SQLStore) ListAuditLogs(ctx context.Context, since time.Time, channelID int64, limit int) ([]*model.AuditLog, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	query := `
+		SELECT id, time, actor, client_ip, action, channel_id, status_code, changes
+		FROM audit_logs
+		WHERE time >= ?`
+	args := []any{since.UnixMilli()}
+	if channelID > 0 {
+		query += ` AND channel_id = ?`
+		args = append(args, channelID)
+	}
+	query += ` ORDER BY time DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit_logs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := make([]*model.AuditLog, 0)
+	for rows.Next() {
+		var entry model.AuditLog
+		var ts int64
+		var changes sql.NullString
+		if err := rows.Scan(&entry.ID, &ts, &entry.Actor, &entry.ClientIP, &entry.Action,
+			&entry.ChannelID, &entry.StatusCode, &changes); err != nil {
+			return nil, fmt.Errorf("scan audit_logs row: %w", err)
+		}
+		entry.Time = model.JSONTime{Time: time.UnixMilli(ts)}
+		if changes.Valid && changes.String != "" {
+			if err := json.Unmarshal([]byte(changes.String), &entry.Changes); err != nil {
+				return nil, fmt.Errorf("unmarshal audit_logs changes id=%d: %w", entry.ID, err)
+			}
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit_logs: %w", err)
+	}
+	return entries, nil
+}
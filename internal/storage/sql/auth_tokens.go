@@ -21,7 +21,7 @@ const authTokenSelectColumns = `
 	id, token, description, created_at, expires_at, last_used_at, is_active,
 	success_count, failure_count, stream_avg_ttfb, non_stream_avg_rt, stream_count, non_stream_count,
 	prompt_tokens_total, completion_tokens_total, cache_read_tokens_total, cache_creation_tokens_total, total_cost_usd, effective_cost_usd,
-	cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency
+	cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency, rpm_limit
 `
 
 func marshalJSONList[T any](field string, values []T) (string, error) {
@@ -123,6 +123,7 @@ func scanAuthToken(scanner interface {
 		&allowedChannelIDsJSON,
 		&channelRestrictionMode,
 		&token.MaxConcurrency,
+		&token.RPMLimit,
 	); err != nil {
 		return nil, err
 	}
@@ -212,9 +213,9 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 				id, token, description, created_at, expires_at, last_used_at, is_active,
 				success_count, failure_count, stream_avg_ttfb, non_stream_avg_rt, stream_count, non_stream_count,
 				prompt_tokens_total, completion_tokens_total, cache_read_tokens_total, cache_creation_tokens_total, total_cost_usd, effective_cost_usd,
-				cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency
+				cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency, rpm_limit
 			)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(id) DO UPDATE SET
 				token = excluded.token,
 				description = excluded.description,
@@ -239,7 +240,8 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 				allowed_models = excluded.allowed_models,
 				allowed_channel_ids = excluded.allowed_channel_ids,
 				channel_restriction_mode = excluded.channel_restriction_mode,
-				max_concurrency = excluded.max_concurrency`
+				max_concurrency = excluded.max_concurrency,
+				rpm_limit = excluded.rpm_limit`
 		args := []any{
 			token.ID,
 			token.Token,
@@ -266,6 +268,7 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 			allowedChannelIDsJSON,
 			channelRestrictionMode,
 			token.MaxConcurrency,
+			token.RPMLimit,
 		}
 		if s.IsPostgres() {
 			err = s.withPostgresExplicitIDTx(ctx, "auth_tokens", func(tx *sql.Tx) error {
@@ -286,9 +289,9 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 			id, token, description, created_at, expires_at, last_used_at, is_active,
 			success_count, failure_count, stream_avg_ttfb, non_stream_avg_rt, stream_count, non_stream_count,
 			prompt_tokens_total, completion_tokens_total, cache_read_tokens_total, cache_creation_tokens_total, total_cost_usd, effective_cost_usd,
-			cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency
+			cost_used_microusd, cost_limit_microusd, allowed_models, allowed_channel_ids, channel_restriction_mode, max_concurrency, rpm_limit
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE
 			token = VALUES(token),
 			description = VALUES(description),
@@ -313,7 +316,8 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 			allowed_models = VALUES(allowed_models),
 			allowed_channel_ids = VALUES(allowed_channel_ids),
 			channel_restriction_mode = VALUES(channel_restriction_mode),
-			max_concurrency = VALUES(max_concurrency)
+			max_concurrency = VALUES(max_concurrency),
+			rpm_limit = VALUES(rpm_limit)
 	`,
 		token.ID,
 		token.Token,
@@ -340,6 +344,7 @@ func (s *SQLStore) UpsertAuthTokenAllFields(ctx context.Context, token *model.Au
 		allowedChannelIDsJSON,
 		channelRestrictionMode,
 		token.MaxConcurrency,
+		token.RPMLimit,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert auth token all fields: %w", err)
@@ -357,9 +362,9 @@ const (
 	authTokenInsertCommonCols = `token, description, created_at, expires_at, last_used_at, is_active,
 		success_count, failure_count, stream_avg_ttfb, non_stream_avg_rt, stream_count, non_stream_count,
 		prompt_tokens_total, completion_tokens_total, total_cost_usd, effective_cost_usd, allowed_models, allowed_channel_ids,
-		channel_restriction_mode, cost_used_microusd, cost_limit_microusd, max_concurrency`
+		channel_restriction_mode, cost_used_microusd, cost_limit_microusd, max_concurrency, rpm_limit`
 
-	authTokenInsertCommonValues = `?, ?, ?, ?, ?, ?, 0, 0, 0.0, 0.0, 0, 0, 0, 0, 0.0, 0.0, ?, ?, ?, 0, ?, ?`
+	authTokenInsertCommonValues = `?, ?, ?, ?, ?, ?, 0, 0, 0.0, 0.0, 0, 0, 0, 0, 0.0, 0.0, ?, ?, ?, 0, ?, ?, ?`
 )
 
 // authTokenInsertCommonArgs builds auth_tokens INSERT arguments.
@@ -407,7 +412,7 @@ func authTokenInsertCommonArgs(token *model.AuthToken) ([]any, error) {
 		expiresAt, lastUsedAt, boolToInt(token.IsActive),
 		allowedModelsJSON, allowedChannelIDsJSON,
 		channelRestrictionMode,
-		token.CostLimitMicroUSD, token.MaxConcurrency,
+		token.CostLimitMicroUSD, token.MaxConcurrency, token.RPMLimit,
 	}, nil
 }
 
@@ -701,9 +706,10 @@ func (s *SQLStore) UpdateAuthToken(ctx context.Context, token *model.AuthToken)
 		    allowed_models = ?,
 		    allowed_channel_ids = ?,
 		    channel_restriction_mode = ?,
-		    max_concurrency = ?
+		    max_concurrency = ?,
+		    rpm_limit = ?
 		WHERE id = ?
-	`, token.Description, expiresAt, lastUsedAt, boolToInt(token.IsActive), token.CostLimitMicroUSD, allowedModelsJSON, allowedChannelIDsJSON, channelRestrictionMode, token.MaxConcurrency, token.ID)
+	`, token.Description, expiresAt, lastUsedAt, boolToInt(token.IsActive), token.CostLimitMicroUSD, allowedModelsJSON, allowedChannelIDsJSON, channelRestrictionMode, token.MaxConcurrency, token.RPMLimit, token.ID)
 
 	if err != nil {
 		return fmt.Errorf("update auth token: %w", err)
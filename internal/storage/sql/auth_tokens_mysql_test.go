@@ -97,7 +97,7 @@ func (r *foundRowsRows) Columns() []string {
 		"id", "token", "description", "created_at", "expires_at", "last_used_at", "is_active",
 		"success_count", "failure_count", "stream_avg_ttfb", "non_stream_avg_rt", "stream_count", "non_stream_count",
 		"prompt_tokens_total", "completion_tokens_total", "cache_read_tokens_total", "cache_creation_tokens_total", "total_cost_usd", "effective_cost_usd",
-		"cost_used_microusd", "cost_limit_microusd", "allowed_models", "allowed_channel_ids", "channel_restriction_mode", "max_concurrency",
+		"cost_used_microusd", "cost_limit_microusd", "allowed_models", "allowed_channel_ids", "channel_restriction_mode", "max_concurrency", "rpm_limit",
 	}
 }
 
@@ -145,6 +145,7 @@ func authTokenDriverValues(token *model.AuthToken) []driver.Value {
 		`[42]`,
 		mode,
 		token.MaxConcurrency,
+		token.RPMLimit,
 	}
 }
 
@@ -187,6 +188,7 @@ func TestEnsureAuthToken_MySQLClientFoundRowsBackfillsExistingToken(t *testing.T
 		AllowedModels:     []string{"gpt-4o"},
 		AllowedChannelIDs: []int64{42},
 		MaxConcurrency:    2,
+		RPMLimit:          30,
 	}
 	store := newFoundRowsTestStore(t, &foundRowsState{
 		tokenHash: tokenHash,
@@ -211,6 +213,7 @@ func TestEnsureAuthToken_MySQLClientFoundRowsBackfillsExistingToken(t *testing.T
 		token.Description != existing.Description ||
 		token.CostLimitMicroUSD != existing.CostLimitMicroUSD ||
 		token.MaxConcurrency != existing.MaxConcurrency ||
+		token.RPMLimit != existing.RPMLimit ||
 		len(token.AllowedModels) != 1 || token.AllowedModels[0] != "gpt-4o" ||
 		len(token.AllowedChannelIDs) != 1 || token.AllowedChannelIDs[0] != 42 {
 		t.Fatalf("token was not backfilled from existing row: %+v", token)
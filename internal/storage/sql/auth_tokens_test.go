@@ -29,6 +29,7 @@ func TestAuthToken_CreateAndGet(t *testing.T) {
 		AllowedModels:     []string{"gpt-4", "claude-3"},
 		AllowedChannelIDs: []int64{11, 22},
 		MaxConcurrency:    3,
+		RPMLimit:          60,
 		CreatedAt:         time.Now(),
 	}
 	if err := store.CreateAuthToken(ctx, token); err != nil {
@@ -52,6 +53,9 @@ func TestAuthToken_CreateAndGet(t *testing.T) {
 	if got.MaxConcurrency != 3 {
 		t.Fatalf("max_concurrency: got %d, want 3", got.MaxConcurrency)
 	}
+	if got.RPMLimit != 60 {
+		t.Fatalf("rpm_limit: got %d, want 60", got.RPMLimit)
+	}
 
 	// 通过 Token 值获取
 	gotByValue, err := store.GetAuthTokenByValue(ctx, "test-token-hash")
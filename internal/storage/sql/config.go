@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"ccLoad/internal/model"
+	"ccLoad/internal/util"
 )
 
 // ==================== Config CRUD 实现 ====================
@@ -22,7 +23,7 @@ func (s *SQLStore) ListConfigs(ctx context.Context) ([]*model.Config, error) {
 	query := `
 			SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency, c.channel_type, c.protocol_transform_mode, c.enabled,
 			       c.scheduled_check_enabled, c.scheduled_check_model,
-			       c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+			       c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 			       SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 			       c.created_at, c.updated_at
 			FROM channels c
@@ -57,7 +58,7 @@ func (s *SQLStore) GetConfig(ctx context.Context, id int64) (*model.Config, erro
 	query := `
 			SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency, c.channel_type, c.protocol_transform_mode, c.enabled,
 			       c.scheduled_check_enabled, c.scheduled_check_model,
-			       c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+			       c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 			       SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 			       c.created_at, c.updated_at
 			FROM channels c
@@ -95,7 +96,7 @@ func (s *SQLStore) GetEnabledChannelsByModel(ctx context.Context, modelName stri
 		query = `
 	            SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency,
 	                   c.channel_type, c.protocol_transform_mode, c.enabled, c.scheduled_check_enabled, c.scheduled_check_model,
-	                   c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+	                   c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 	                   SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 	                   c.created_at, c.updated_at
 	            FROM channels c
@@ -109,7 +110,7 @@ func (s *SQLStore) GetEnabledChannelsByModel(ctx context.Context, modelName stri
 		query = `
 	            SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency,
 	                   c.channel_type, c.protocol_transform_mode, c.enabled, c.scheduled_check_enabled, c.scheduled_check_model,
-	                   c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+	                   c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 	                   SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 	                   c.created_at, c.updated_at
 	            FROM channels c
@@ -149,7 +150,7 @@ func (s *SQLStore) GetEnabledChannelsByType(ctx context.Context, channelType str
 	query := `
 			SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency,
 			       c.channel_type, c.protocol_transform_mode, c.enabled, c.scheduled_check_enabled, c.scheduled_check_model,
-			       c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+			       c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 			       SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 			       c.created_at, c.updated_at
 			FROM channels c
@@ -187,18 +188,25 @@ func (s *SQLStore) GetEnabledChannelsByModelAndProtocol(ctx context.Context, mod
 		return s.GetEnabledChannelsByModel(ctx, modelName)
 	}
 
-	args := []any{protocol, protocol}
+	nativeTypes := util.ChannelTypesForProtocol(protocol)
+	typePlaceholders := make([]string, len(nativeTypes))
+	args := make([]any, 0, len(nativeTypes)+1)
+	for i, t := range nativeTypes {
+		typePlaceholders[i] = "?"
+		args = append(args, t)
+	}
+	args = append(args, protocol)
 	query := `
 		SELECT c.id, c.name, c.url, c.priority, c.rpm_limit, c.max_concurrency,
 		       c.channel_type, c.protocol_transform_mode, c.enabled, c.scheduled_check_enabled, c.scheduled_check_model,
-		       c.cooldown_until, c.cooldown_duration_ms, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url,
+		       c.cooldown_until, c.cooldown_duration_ms, c.consecutive_failures, c.last_used_at, c.daily_cost_limit, c.cost_multiplier, c.custom_request_rules, c.proxy_url, c.allowed_methods, c.redirect_routing_only, c.max_input_tokens, c.max_output_tokens, c.cacheable, c.restore_response_model, c.success_codes, c.active_schedule, c.blocked_models, c.strip_headers, c.retry_error_substrings, c.maintenance,
 		       SUM(CASE WHEN k.id IS NOT NULL AND k.disabled = 0 THEN 1 ELSE 0 END) as key_count,
 		       c.created_at, c.updated_at
 		FROM channels c
 		LEFT JOIN api_keys k ON c.id = k.channel_id
 		WHERE c.enabled = 1
 		  AND (
-		      c.channel_type = ?
+		      c.channel_type IN (` + strings.Join(typePlaceholders, ",") + `)
 		      OR EXISTS (
 		          SELECT 1
 		          FROM channel_protocol_transforms cpt
@@ -275,20 +283,20 @@ func (s *SQLStore) CreateConfig(ctx context.Context, c *model.Config) (*model.Co
 			// 插入渠道记录（数据库生成自增 id）
 			if s.IsPostgres() {
 				err := s.queryRowTx(ctx, tx, `
-					INSERT INTO channels(name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, created_at, updated_at)
-					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					INSERT INTO channels(name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, allowed_methods, redirect_routing_only, max_input_tokens, max_output_tokens, cacheable, restore_response_model, success_codes, active_schedule, blocked_models, strip_headers, retry_error_substrings, created_at, updated_at)
+					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 					RETURNING id
 				`, c.Name, c.URL, c.Priority, c.RPMLimit, c.MaxConcurrency, channelType, protocolTransformMode,
-					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, nowUnix, nowUnix).Scan(&id)
+					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, marshalAllowedMethods(c.AllowedMethods), boolToInt(c.RedirectRoutingOnly), c.MaxInputTokens, c.MaxOutputTokens, boolToInt(c.Cacheable), boolToInt(c.RestoreResponseModel), c.SuccessCodes, c.ActiveSchedule, marshalBlockedModels(c.BlockedModels), marshalStripHeaders(c.StripHeaders), marshalRetryErrorSubstrings(c.RetryErrorSubstrings), nowUnix, nowUnix).Scan(&id)
 				if err != nil {
 					return err
 				}
 			} else {
 				res, err := s.execTx(ctx, tx, `
-					INSERT INTO channels(name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, created_at, updated_at)
-					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					INSERT INTO channels(name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, allowed_methods, redirect_routing_only, max_input_tokens, max_output_tokens, cacheable, restore_response_model, success_codes, active_schedule, blocked_models, strip_headers, retry_error_substrings, created_at, updated_at)
+					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 				`, c.Name, c.URL, c.Priority, c.RPMLimit, c.MaxConcurrency, channelType, protocolTransformMode,
-					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, nowUnix, nowUnix)
+					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, marshalAllowedMethods(c.AllowedMethods), boolToInt(c.RedirectRoutingOnly), c.MaxInputTokens, c.MaxOutputTokens, boolToInt(c.Cacheable), boolToInt(c.RestoreResponseModel), c.SuccessCodes, c.ActiveSchedule, marshalBlockedModels(c.BlockedModels), marshalStripHeaders(c.StripHeaders), marshalRetryErrorSubstrings(c.RetryErrorSubstrings), nowUnix, nowUnix)
 				if err != nil {
 					return err
 				}
@@ -301,17 +309,17 @@ func (s *SQLStore) CreateConfig(ctx context.Context, c *model.Config) (*model.Co
 			// 显式主键：用于混合存储同步/恢复，保证两端主键一致
 			if s.supportsONConflict() {
 				_, err := s.execTx(ctx, tx, `
-					INSERT INTO channels(id, name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, created_at, updated_at)
-					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					INSERT INTO channels(id, name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, allowed_methods, redirect_routing_only, max_input_tokens, max_output_tokens, cacheable, restore_response_model, success_codes, active_schedule, blocked_models, strip_headers, retry_error_substrings, created_at, updated_at)
+					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 				`, id, c.Name, c.URL, c.Priority, c.RPMLimit, c.MaxConcurrency, channelType, protocolTransformMode,
-					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, nowUnix, nowUnix)
+					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, marshalAllowedMethods(c.AllowedMethods), boolToInt(c.RedirectRoutingOnly), c.MaxInputTokens, c.MaxOutputTokens, boolToInt(c.Cacheable), boolToInt(c.RestoreResponseModel), c.SuccessCodes, c.ActiveSchedule, marshalBlockedModels(c.BlockedModels), marshalStripHeaders(c.StripHeaders), marshalRetryErrorSubstrings(c.RetryErrorSubstrings), nowUnix, nowUnix)
 				if err != nil {
 					return err
 				}
 			} else {
 				_, err := s.execTx(ctx, tx, `
-					INSERT INTO channels(id, name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, created_at, updated_at)
-					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					INSERT INTO channels(id, name, url, priority, rpm_limit, max_concurrency, channel_type, protocol_transform_mode, enabled, scheduled_check_enabled, scheduled_check_model, daily_cost_limit, cost_multiplier, custom_request_rules, proxy_url, allowed_methods, redirect_routing_only, max_input_tokens, max_output_tokens, cacheable, restore_response_model, success_codes, active_schedule, blocked_models, strip_headers, retry_error_substrings, created_at, updated_at)
+					VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 					ON DUPLICATE KEY UPDATE
 						name = VALUES(name),
 						url = VALUES(url),
@@ -327,9 +335,20 @@ func (s *SQLStore) CreateConfig(ctx context.Context, c *model.Config) (*model.Co
 						cost_multiplier = VALUES(cost_multiplier),
 						custom_request_rules = VALUES(custom_request_rules),
 						proxy_url = VALUES(proxy_url),
+						allowed_methods = VALUES(allowed_methods),
+						redirect_routing_only = VALUES(redirect_routing_only),
+						max_input_tokens = VALUES(max_input_tokens),
+						max_output_tokens = VALUES(max_output_tokens),
+						cacheable = VALUES(cacheable),
+						restore_response_model = VALUES(restore_response_model),
+						success_codes = VALUES(success_codes),
+						active_schedule = VALUES(active_schedule),
+						blocked_models = VALUES(blocked_models),
+						strip_headers = VALUES(strip_headers),
+						retry_error_substrings = VALUES(retry_error_substrings),
 						updated_at = VALUES(updated_at)
 				`, id, c.Name, c.URL, c.Priority, c.RPMLimit, c.MaxConcurrency, channelType, protocolTransformMode,
-					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, nowUnix, nowUnix)
+					boolToInt(c.Enabled), boolToInt(c.ScheduledCheckEnabled), c.ScheduledCheckModel, c.DailyCostLimit, normalizeCostMultiplier(c.CostMultiplier), customRules, c.ProxyURL, marshalAllowedMethods(c.AllowedMethods), boolToInt(c.RedirectRoutingOnly), c.MaxInputTokens, c.MaxOutputTokens, boolToInt(c.Cacheable), boolToInt(c.RestoreResponseModel), c.SuccessCodes, c.ActiveSchedule, marshalBlockedModels(c.BlockedModels), marshalStripHeaders(c.StripHeaders), marshalRetryErrorSubstrings(c.RetryErrorSubstrings), nowUnix, nowUnix)
 				if err != nil {
 					return err
 				}
@@ -392,10 +411,10 @@ func (s *SQLStore) UpdateConfig(ctx context.Context, id int64, upd *model.Config
 		// 更新渠道记录
 		_, err := s.execTx(ctx, tx, `
 			UPDATE channels
-			SET name=?, url=?, priority=?, rpm_limit=?, max_concurrency=?, channel_type=?, protocol_transform_mode=?, enabled=?, scheduled_check_enabled=?, scheduled_check_model=?, daily_cost_limit=?, cost_multiplier=?, custom_request_rules=?, proxy_url=?, updated_at=?
+			SET name=?, url=?, priority=?, rpm_limit=?, max_concurrency=?, channel_type=?, protocol_transform_mode=?, enabled=?, scheduled_check_enabled=?, scheduled_check_model=?, daily_cost_limit=?, cost_multiplier=?, custom_request_rules=?, proxy_url=?, allowed_methods=?, redirect_routing_only=?, max_input_tokens=?, max_output_tokens=?, cacheable=?, restore_response_model=?, success_codes=?, active_schedule=?, blocked_models=?, strip_headers=?, retry_error_substrings=?, updated_at=?
 			WHERE id=?
 		`, name, url, upd.Priority, upd.RPMLimit, upd.MaxConcurrency, channelType, protocolTransformMode,
-			boolToInt(upd.Enabled), boolToInt(upd.ScheduledCheckEnabled), upd.ScheduledCheckModel, upd.DailyCostLimit, normalizeCostMultiplier(upd.CostMultiplier), customRules, upd.ProxyURL, updatedAtUnix, id)
+			boolToInt(upd.Enabled), boolToInt(upd.ScheduledCheckEnabled), upd.ScheduledCheckModel, upd.DailyCostLimit, normalizeCostMultiplier(upd.CostMultiplier), customRules, upd.ProxyURL, marshalAllowedMethods(upd.AllowedMethods), boolToInt(upd.RedirectRoutingOnly), upd.MaxInputTokens, upd.MaxOutputTokens, boolToInt(upd.Cacheable), boolToInt(upd.RestoreResponseModel), upd.SuccessCodes, upd.ActiveSchedule, marshalBlockedModels(upd.BlockedModels), marshalStripHeaders(upd.StripHeaders), marshalRetryErrorSubstrings(upd.RetryErrorSubstrings), updatedAtUnix, id)
 		if err != nil {
 			return err
 		}
@@ -453,6 +472,113 @@ func (s *SQLStore) UpdateChannelEnabled(ctx context.Context, id int64, enabled b
 	return config, nil
 }
 
+// UpdateChannelMaintenance 仅更新维护模式标记（与 enabled 开关一样走轻量更新路径）
+func (s *SQLStore) UpdateChannelMaintenance(ctx context.Context, id int64, maintenance bool) (*model.Config, error) {
+	_, err := s.ExecContext(ctx, `
+		UPDATE channels
+		SET maintenance = ?, updated_at = ?
+		WHERE id = ?
+	`, boolToInt(maintenance), timeToUnix(time.Now()), id)
+	if err != nil {
+		return nil, fmt.Errorf("update channel maintenance: %w", err)
+	}
+	return s.GetConfig(ctx, id)
+}
+
+// AddChannelModels 向渠道追加模型（大小写不敏感去重，已存在的模型保持不变）
+// 先更新渠道行的 updated_at 取得行锁，串行化同一渠道的并发模型编辑，避免整表读改写的竞态
+func (s *SQLStore) AddChannelModels(ctx context.Context, channelID int64, entries []model.ModelEntry) (*model.Config, error) {
+	err := s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if err := s.touchChannelTx(ctx, tx, channelID); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, s.q(`SELECT model FROM channel_models WHERE channel_id = ?`), channelID)
+		if err != nil {
+			return fmt.Errorf("query channel models: %w", err)
+		}
+		existing := make(map[string]bool)
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("scan channel model: %w", err)
+			}
+			existing[strings.ToLower(name)] = true
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		baseCreatedAt := time.Now().UnixMilli()
+		for i, entry := range entries {
+			key := strings.ToLower(entry.Model)
+			if existing[key] {
+				continue
+			}
+			existing[key] = true
+			if _, err := s.execTx(ctx, tx,
+				`INSERT INTO channel_models (channel_id, model, redirect_model, created_at) VALUES (?, ?, ?, ?)`,
+				channelID, entry.Model, entry.RedirectModel, baseCreatedAt+int64(i),
+			); err != nil {
+				return fmt.Errorf("insert channel model %s: %w", entry.Model, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetConfig(ctx, channelID)
+}
+
+// RemoveChannelModels 从渠道删除指定模型（大小写不敏感，不存在的模型忽略）
+func (s *SQLStore) RemoveChannelModels(ctx context.Context, channelID int64, models []string) (*model.Config, error) {
+	err := s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if err := s.touchChannelTx(ctx, tx, channelID); err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		placeholders := make([]string, len(models))
+		args := make([]any, 0, len(models)+1)
+		args = append(args, channelID)
+		for i, m := range models {
+			placeholders[i] = "?"
+			args = append(args, strings.ToLower(m))
+		}
+		query := fmt.Sprintf(`DELETE FROM channel_models WHERE channel_id = ? AND LOWER(model) IN (%s)`, strings.Join(placeholders, ","))
+		if _, err := s.execTx(ctx, tx, query, args...); err != nil {
+			return fmt.Errorf("delete channel models: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetConfig(ctx, channelID)
+}
+
+// touchChannelTx 更新渠道 updated_at（同时取得行锁）；渠道不存在时返回 model.ErrChannelNotFound
+func (s *SQLStore) touchChannelTx(ctx context.Context, tx *sql.Tx, channelID int64) error {
+	result, err := s.execTx(ctx, tx, `UPDATE channels SET updated_at = ? WHERE id = ?`, timeToUnix(time.Now()), channelID)
+	if err != nil {
+		return fmt.Errorf("lock channel: %w", err)
+	}
+	// MySQL 对值未变化的行（同一秒内重复更新）报告 0 行受影响，需再确认渠道是否存在
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		var exists int
+		if err := s.queryRowTx(ctx, tx, `SELECT 1 FROM channels WHERE id = ?`, channelID).Scan(&exists); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrChannelNotFound
+			}
+			return fmt.Errorf("check channel: %w", err)
+		}
+	}
+	return nil
+}
+
 // DeleteConfig 删除渠道配置
 func (s *SQLStore) DeleteConfig(ctx context.Context, id int64) error {
 	// 检查记录是否存在，但不存在也继续清理残留子数据。
@@ -554,6 +680,40 @@ func (s *SQLStore) BatchUpdatePriority(ctx context.Context, updates []struct {
 	return rowsAffected, nil
 }
 
+// UpdateChannelsLastUsed 批量更新渠道最后使用时间（Unix毫秒，仅向前推进，不修改 updated_at）
+func (s *SQLStore) UpdateChannelsLastUsed(ctx context.Context, lastUsed map[int64]int64) error {
+	if len(lastUsed) == 0 {
+		return nil
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update channels last used transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := s.prepareTx(ctx, tx, `
+		UPDATE channels
+		SET last_used_at = ?
+		WHERE id = ? AND last_used_at < ?
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare update channels last used: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for id, ms := range lastUsed {
+		if _, err := stmt.ExecContext(ctx, ms, id, ms); err != nil {
+			return fmt.Errorf("update channel %d last used: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update channels last used: %w", err)
+	}
+	return nil
+}
+
 // ==================== ModelEntries 辅助方法 ====================
 
 // loadModelEntriesForConfigs 批量加载多个渠道的模型数据
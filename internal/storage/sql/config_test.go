@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -128,6 +129,52 @@ func TestConfig_ListConfigs(t *testing.T) {
 	}
 }
 
+func TestConfig_UpdateChannelMaintenance(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "update-maintenance.db")
+	ctx := context.Background()
+
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "maintenance-only",
+		URL:          "https://api.example.com",
+		Priority:     7,
+		Enabled:      true,
+		ModelEntries: []model.ModelEntry{{Model: "claude-3"}},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	if created.Maintenance {
+		t.Fatal("new channel should not be in maintenance")
+	}
+
+	updated, err := store.UpdateChannelMaintenance(ctx, created.ID, true)
+	if err != nil {
+		t.Fatalf("update maintenance: %v", err)
+	}
+	if !updated.Maintenance || !updated.Enabled || updated.Priority != 7 {
+		t.Fatalf("unexpected config after maintenance on: %+v", updated)
+	}
+
+	// 完整编辑不携带维护标记，必须保留
+	updated.Name = "maintenance-renamed"
+	if _, err := store.UpdateConfig(ctx, created.ID, updated); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	channels, err := store.GetEnabledChannelsByModel(ctx, "claude-3")
+	if err != nil || len(channels) != 1 || !channels[0].Maintenance {
+		t.Fatalf("maintenance flag lost: %+v, err=%v", channels, err)
+	}
+
+	if updated, err = store.UpdateChannelMaintenance(ctx, created.ID, false); err != nil || updated.Maintenance {
+		t.Fatalf("update maintenance off: %+v, err=%v", updated, err)
+	}
+	if _, err := store.UpdateChannelMaintenance(ctx, 9999, true); err == nil {
+		t.Fatal("expected error for missing channel")
+	}
+}
+
 func TestConfig_UpdateChannelEnabledOnlyTouchesEnabled(t *testing.T) {
 	t.Parallel()
 
@@ -259,6 +306,253 @@ func TestConfig_UpdateConfig(t *testing.T) {
 	}
 }
 
+func TestConfig_UpdateChannelsLastUsedOnlyMovesForward(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "channel_last_used.db")
+	ctx := context.Background()
+	idA := createTestChannel(t, ctx, store, "lru-a")
+	idB := createTestChannel(t, ctx, store, "lru-b")
+
+	if err := store.UpdateChannelsLastUsed(ctx, map[int64]int64{idA: 2000}); err != nil {
+		t.Fatalf("update last used: %v", err)
+	}
+	// 较旧的时间戳不应覆盖
+	if err := store.UpdateChannelsLastUsed(ctx, map[int64]int64{idA: 1000}); err != nil {
+		t.Fatalf("update last used (older): %v", err)
+	}
+
+	a, err := store.GetConfig(ctx, idA)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	b, err := store.GetConfig(ctx, idB)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if a.LastUsedAt != 2000 || b.LastUsedAt != 0 {
+		t.Fatalf("last_used_at = [%d %d], want [2000 0]", a.LastUsedAt, b.LastUsedAt)
+	}
+}
+
+func TestConfig_AllowedMethodsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	store, err := storage.CreateSQLiteStore(filepath.Join(tmp, "allowed_methods.db"))
+	if err != nil {
+		t.Fatalf("create sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:           "post-only",
+		URL:            "https://api.example.com",
+		Enabled:        true,
+		ModelEntries:   []model.ModelEntry{{Model: "m1"}},
+		AllowedMethods: []string{"post", "POST", " get "},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	if got := strings.Join(created.AllowedMethods, ","); got != "GET,POST" {
+		t.Fatalf("allowed methods after create: got %q, want %q", got, "GET,POST")
+	}
+
+	created.AllowedMethods = nil
+	if _, err := store.UpdateConfig(ctx, created.ID, created); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	got, err := store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if len(got.AllowedMethods) != 0 {
+		t.Fatalf("allowed methods after clearing: got %v, want empty", got.AllowedMethods)
+	}
+}
+
+func TestConfig_BlockedModelsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "blocked_models.db")
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:          "blocked",
+		URL:           "https://api.example.com",
+		Enabled:       true,
+		ModelEntries:  []model.ModelEntry{{Model: "m1"}, {Model: "m2"}},
+		BlockedModels: []string{" m2 ", "M2", "m3"},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	got, err := store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if joined := strings.Join(got.BlockedModels, ","); joined != "m2,m3" {
+		t.Fatalf("blocked models after create: got %q, want %q", joined, "m2,m3")
+	}
+
+	got.BlockedModels = nil
+	if _, err := store.UpdateConfig(ctx, got.ID, got); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	got, err = store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if len(got.BlockedModels) != 0 {
+		t.Fatalf("blocked models after clearing: got %v, want empty", got.BlockedModels)
+	}
+}
+
+func TestConfig_StripHeadersRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "strip_headers.db")
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "strip",
+		URL:          "https://api.example.com",
+		Enabled:      true,
+		ModelEntries: []model.ModelEntry{{Model: "m1"}},
+		StripHeaders: []string{" Cookie ", "cookie", "X-Internal-*"},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	got, err := store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if joined := strings.Join(got.StripHeaders, ","); joined != "Cookie,X-Internal-*" {
+		t.Fatalf("strip headers after create: got %q, want %q", joined, "Cookie,X-Internal-*")
+	}
+
+	got.StripHeaders = nil
+	if _, err := store.UpdateConfig(ctx, got.ID, got); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	got, err = store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if len(got.StripHeaders) != 0 {
+		t.Fatalf("strip headers after clearing: got %v, want empty", got.StripHeaders)
+	}
+}
+
+func TestConfig_RetryErrorSubstringsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "retry_error_substrings.db")
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:                 "retry-substr",
+		URL:                  "https://api.example.com",
+		Enabled:              true,
+		ModelEntries:         []model.ModelEntry{{Model: "m1"}},
+		RetryErrorSubstrings: []string{" overloaded, try later ", "OVERLOADED, try later", "quota_exceeded"},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	got, err := store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if joined := strings.Join(got.RetryErrorSubstrings, "|"); joined != "overloaded, try later|quota_exceeded" {
+		t.Fatalf("retry substrings after create: got %q", joined)
+	}
+
+	got.RetryErrorSubstrings = nil
+	if _, err := store.UpdateConfig(ctx, got.ID, got); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	got, err = store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if len(got.RetryErrorSubstrings) != 0 {
+		t.Fatalf("retry substrings after clearing: got %v, want empty", got.RetryErrorSubstrings)
+	}
+}
+
+func TestConfig_AddAndRemoveChannelModelsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	store, err := storage.CreateSQLiteStore(filepath.Join(tmp, "channel_models.db"))
+	if err != nil {
+		t.Fatalf("create sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	ctx := context.Background()
+	created, err := store.CreateConfig(ctx, &model.Config{
+		Name:         "models",
+		URL:          "https://api.example.com",
+		Enabled:      true,
+		ModelEntries: []model.ModelEntry{{Model: "base"}},
+	})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+
+	// 并发追加互不覆盖（整渠道读改写会丢失部分模型）
+	const workers = 8
+	errCh := make(chan error, workers)
+	for i := range workers {
+		go func() {
+			_, err := store.AddChannelModels(ctx, created.ID, []model.ModelEntry{{Model: fmt.Sprintf("m%d", i)}, {Model: "BASE"}})
+			errCh <- err
+		}()
+	}
+	for range workers {
+		if err := <-errCh; err != nil {
+			t.Fatalf("AddChannelModels: %v", err)
+		}
+	}
+	got, err := store.GetConfig(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if len(got.ModelEntries) != workers+1 {
+		t.Fatalf("models=%d, want %d: %+v", len(got.ModelEntries), workers+1, got.ModelEntries)
+	}
+
+	got, err = store.RemoveChannelModels(ctx, created.ID, []string{"M0", "missing"})
+	if err != nil {
+		t.Fatalf("RemoveChannelModels: %v", err)
+	}
+	if len(got.ModelEntries) != workers || got.SupportsModel("m0") {
+		t.Fatalf("unexpected models after remove: %+v", got.ModelEntries)
+	}
+
+	if _, err := store.AddChannelModels(ctx, 99999, []model.ModelEntry{{Model: "x"}}); !errors.Is(err, model.ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound for missing channel, got %v", err)
+	}
+	if _, err := store.RemoveChannelModels(ctx, 99999, []string{"x"}); !errors.Is(err, model.ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound for missing channel, got %v", err)
+	}
+
+	// 同一秒内连续增删（updated_at 不变）不能误报渠道不存在
+	for i := range 3 {
+		if _, err := store.AddChannelModels(ctx, created.ID, []model.ModelEntry{{Model: fmt.Sprintf("burst%d", i)}}); err != nil {
+			t.Fatalf("AddChannelModels burst %d: %v", i, err)
+		}
+		if _, err := store.RemoveChannelModels(ctx, created.ID, []string{fmt.Sprintf("burst%d", i)}); err != nil {
+			t.Fatalf("RemoveChannelModels burst %d: %v", i, err)
+		}
+	}
+}
+
 func TestConfig_DeleteConfig(t *testing.T) {
 	t.Parallel()
 
@@ -21,6 +21,7 @@ func (s *SQLStore) cooldownSelectLockClause() string {
 }
 
 // BumpChannelCooldown 渠道级冷却：指数退避策略（认证错误5分钟起，其他1秒起，最大30分钟）
+// 上一冷却周期已结束（或无冷却）时开启新周期，consecutive_failures 加一
 func (s *SQLStore) BumpChannelCooldown(ctx context.Context, channelID int64, now time.Time, statusCode int) (time.Duration, error) {
 	// 使用事务保护Read-Modify-Write操作,防止并发竞态
 	// 问题场景同BumpKeyCooldown,多个并发请求可能导致指数退避计算错误
@@ -47,13 +48,17 @@ func (s *SQLStore) BumpChannelCooldown(ctx context.Context, channelID int64, now
 		until := unixToTime(cooldownUntil)
 		nextDuration = util.CalculateBackoffDuration(cooldownDurationMs, until, now, &statusCode)
 		newUntil := now.Add(nextDuration)
+		failureIncrement := 0
+		if !until.After(now) {
+			failureIncrement = 1
+		}
 
 		// 3. 更新 channels 表(事务内)
 		_, err = s.execTx(ctx, tx, `
 			UPDATE channels
-			SET cooldown_until = ?, cooldown_duration_ms = ?, updated_at = ?
+			SET cooldown_until = ?, cooldown_duration_ms = ?, consecutive_failures = consecutive_failures + ?, updated_at = ?
 			WHERE id = ?
-		`, timeToUnix(newUntil), int64(nextDuration/time.Millisecond), timeToUnix(now), channelID)
+		`, timeToUnix(newUntil), int64(nextDuration/time.Millisecond), failureIncrement, timeToUnix(now), channelID)
 
 		if err != nil {
 			return fmt.Errorf("update channel cooldown: %w", err)
@@ -65,13 +70,13 @@ func (s *SQLStore) BumpChannelCooldown(ctx context.Context, channelID int64, now
 	return nextDuration, err
 }
 
-// ResetChannelCooldown 重置渠道冷却状态
-// 优化：仅更新实际处于冷却中的记录，避免无谓的写入
+// ResetChannelCooldown 重置渠道冷却状态（同时清零熔断计数）
+// 优化：仅更新实际处于冷却中或有熔断计数的记录，避免无谓的写入
 func (s *SQLStore) ResetChannelCooldown(ctx context.Context, channelID int64) error {
 	_, err := s.ExecContext(ctx, `
 		UPDATE channels
-		SET cooldown_until = 0, cooldown_duration_ms = 0, updated_at = ?
-		WHERE id = ? AND cooldown_until > 0
+		SET cooldown_until = 0, cooldown_duration_ms = 0, consecutive_failures = 0, updated_at = ?
+		WHERE id = ? AND (cooldown_until > 0 OR consecutive_failures > 0)
 	`, timeToUnix(time.Now()), channelID)
 
 	if err != nil {
@@ -88,8 +93,8 @@ func (s *SQLStore) ResetAllCooldowns(ctx context.Context, channelID int64) error
 	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
 		if _, err := s.execTx(ctx, tx, `
 			UPDATE channels
-			SET cooldown_until = 0, cooldown_duration_ms = 0, updated_at = ?
-			WHERE id = ? AND (cooldown_until > 0 OR cooldown_duration_ms > 0)
+			SET cooldown_until = 0, cooldown_duration_ms = 0, consecutive_failures = 0, updated_at = ?
+			WHERE id = ? AND (cooldown_until > 0 OR cooldown_duration_ms > 0 OR consecutive_failures > 0)
 		`, now, channelID); err != nil {
 			return fmt.Errorf("reset channel cooldown: %w", err)
 		}
@@ -131,6 +136,27 @@ func (s *SQLStore) SetChannelCooldown(ctx context.Context, channelID int64, unti
 	return nil
 }
 
+// OpenChannelCircuit 熔断：consecutive_failures 达到阈值时将冷却延长至 until
+// 返回是否实际打开熔断（未达阈值或冷却已长于 until 时不修改）
+func (s *SQLStore) OpenChannelCircuit(ctx context.Context, channelID int64, threshold int, until time.Time) (bool, error) {
+	now := time.Now()
+	durationMs := util.CalculateCooldownDuration(until, now)
+
+	res, err := s.ExecContext(ctx, `
+		UPDATE channels
+		SET cooldown_until = ?, cooldown_duration_ms = ?, updated_at = ?
+		WHERE id = ? AND consecutive_failures >= ? AND cooldown_until < ?
+	`, timeToUnix(until), durationMs, timeToUnix(now), channelID, threshold, timeToUnix(until))
+	if err != nil {
+		return false, fmt.Errorf("open channel circuit: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("open channel circuit: %w", err)
+	}
+	return affected > 0, nil
+}
+
 // GetAllChannelCooldowns 批量查询所有渠道冷却状态（从 channels 表读取）
 func (s *SQLStore) GetAllChannelCooldowns(ctx context.Context) (map[int64]time.Time, error) {
 	now := timeToUnix(time.Now())
@@ -318,6 +344,7 @@ func (s *SQLStore) GetAllKeyCooldowns(ctx context.Context) (map[int64]map[int]ti
 }
 
 // BumpKeyCooldown Key级别冷却：指数退避策略（认证错误5分钟起，其他1秒起，最大30分钟）
+// Key 属于 key_group 时，冷却同步到同组所有渠道的 Key
 func (s *SQLStore) BumpKeyCooldown(ctx context.Context, configID int64, keyIndex int, now time.Time, statusCode int) (time.Duration, error) {
 	// 使用事务保护Read-Modify-Write操作,防止并发竞态
 	// 问题场景:
@@ -333,11 +360,12 @@ func (s *SQLStore) BumpKeyCooldown(ctx context.Context, configID int64, keyIndex
 	err := s.WithTransaction(ctx, func(tx *sql.Tx) error {
 		// 1. 读取当前冷却状态。MySQL 必须显式 FOR UPDATE 锁行，否则两个事务可读到同一旧值。
 		var cooldownUntil, cooldownDurationMs int64
+		var keyGroup string
 		err := s.queryRowTx(ctx, tx, `
-			SELECT cooldown_until, cooldown_duration_ms
+			SELECT cooldown_until, cooldown_duration_ms, key_group
 			FROM api_keys
 			WHERE channel_id = ? AND key_index = ?
-		`+s.cooldownSelectLockClause(), configID, keyIndex).Scan(&cooldownUntil, &cooldownDurationMs)
+		`+s.cooldownSelectLockClause(), configID, keyIndex).Scan(&cooldownUntil, &cooldownDurationMs, &keyGroup)
 
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -362,35 +390,95 @@ func (s *SQLStore) BumpKeyCooldown(ctx context.Context, configID int64, keyIndex
 			return fmt.Errorf("update key cooldown: %w", err)
 		}
 
-		return nil
+		return s.propagateKeyGroupCooldownTx(ctx, tx, keyGroup, newUntil, int64(nextDuration/time.Millisecond), now)
 	})
 
 	return nextDuration, err
 }
 
-// SetKeyCooldown 设置指定Key的冷却截止时间（操作 api_keys 表）
-func (s *SQLStore) SetKeyCooldown(ctx context.Context, configID int64, keyIndex int, until time.Time) error {
-	now := time.Now()
-	durationMs := util.CalculateCooldownDuration(until, now)
+// keyGroupTx 查询Key所属的 key_group（不存在或未分组返回空串）
+func (s *SQLStore) keyGroupTx(ctx context.Context, tx *sql.Tx, configID int64, keyIndex int) (string, error) {
+	var keyGroup string
+	err := s.queryRowTx(ctx, tx, `
+		SELECT key_group FROM api_keys WHERE channel_id = ? AND key_index = ?
+	`, configID, keyIndex).Scan(&keyGroup)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("query key group: %w", err)
+	}
+	return keyGroup, nil
+}
 
-	_, err := s.ExecContext(ctx, `
+// propagateKeyGroupCooldownTx 将冷却同步到同组Key（只延长不缩短，避免覆盖更长的冷却）
+func (s *SQLStore) propagateKeyGroupCooldownTx(ctx context.Context, tx *sql.Tx, keyGroup string, until time.Time, durationMs int64, now time.Time) error {
+	if keyGroup == "" {
+		return nil
+	}
+	if _, err := s.execTx(ctx, tx, `
 		UPDATE api_keys
 		SET cooldown_until = ?, cooldown_duration_ms = ?, updated_at = ?
-		WHERE channel_id = ? AND key_index = ?
-	`, timeToUnix(until), durationMs, timeToUnix(now), configID, keyIndex)
+		WHERE key_group = ? AND cooldown_until < ?
+	`, timeToUnix(until), durationMs, timeToUnix(now), keyGroup, timeToUnix(until)); err != nil {
+		return fmt.Errorf("propagate key group cooldown: %w", err)
+	}
+	return nil
+}
 
-	return err
+// SetKeyCooldown 设置指定Key的冷却截止时间（操作 api_keys 表，同步到同组Key）
+func (s *SQLStore) SetKeyCooldown(ctx context.Context, configID int64, keyIndex int, until time.Time) error {
+	now := time.Now()
+	durationMs := util.CalculateCooldownDuration(until, now)
+
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := s.execTx(ctx, tx, `
+			UPDATE api_keys
+			SET cooldown_until = ?, cooldown_duration_ms = ?, updated_at = ?
+			WHERE channel_id = ? AND key_index = ?
+		`, timeToUnix(until), durationMs, timeToUnix(now), configID, keyIndex); err != nil {
+			return err
+		}
+		keyGroup, err := s.keyGroupTx(ctx, tx, configID, keyIndex)
+		if err != nil {
+			return err
+		}
+		return s.propagateKeyGroupCooldownTx(ctx, tx, keyGroup, until, durationMs, now)
+	})
 }
 
 // ResetKeyCooldown 重置指定Key的冷却状态（操作 api_keys 表）
-// 优化：仅更新实际处于冷却中的记录，避免无谓的写入
+// Key 确实处于冷却且属于 key_group 时，同组Key一并恢复（同一底层Key已验证可用）
+// 优化：仅更新实际处于冷却中的记录，避免无谓的写入（成功请求的热路径只有一条 UPDATE）
 func (s *SQLStore) ResetKeyCooldown(ctx context.Context, configID int64, keyIndex int) error {
-	_, err := s.ExecContext(ctx, `
+	now := timeToUnix(time.Now())
+
+	res, err := s.ExecContext(ctx, `
 		UPDATE api_keys
 		SET cooldown_until = 0, cooldown_duration_ms = 0, updated_at = ?
 		WHERE channel_id = ? AND key_index = ? AND cooldown_until > 0
-	`, timeToUnix(time.Now()), configID, keyIndex)
+	`, now, configID, keyIndex)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
 
+	var keyGroup string
+	if err := s.QueryRowContext(ctx, `
+		SELECT key_group FROM api_keys WHERE channel_id = ? AND key_index = ?
+	`, configID, keyIndex).Scan(&keyGroup); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("query key group: %w", err)
+	}
+	if keyGroup == "" {
+		return nil
+	}
+	_, err = s.ExecContext(ctx, `
+		UPDATE api_keys
+		SET cooldown_until = 0, cooldown_duration_ms = 0, updated_at = ?
+		WHERE key_group = ? AND cooldown_until > 0
+	`, now, keyGroup)
 	return err
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"ccLoad/internal/model"
 	"ccLoad/internal/storage"
 )
 
@@ -296,3 +297,114 @@ func TestCooldown_AuthErrorBackoff(t *testing.T) {
 		t.Errorf("expected auth error to have longer cooldown (>=5m), got %v", duration)
 	}
 }
+
+func TestCooldown_OpenChannelCircuit(t *testing.T) {
+	t.Parallel()
+
+	store, err := storage.CreateSQLiteStore(filepath.Join(t.TempDir(), "circuit.db"))
+	if err != nil {
+		t.Fatalf("create sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "test-channel-circuit")
+	until := time.Now().Add(time.Hour)
+
+	if _, err := store.BumpChannelCooldown(ctx, channelID, time.Now(), 500); err != nil {
+		t.Fatalf("bump channel cooldown: %v", err)
+	}
+	opened, err := store.OpenChannelCircuit(ctx, channelID, 2, until)
+	if err != nil {
+		t.Fatalf("open circuit: %v", err)
+	}
+	if opened {
+		t.Fatal("circuit should stay closed below threshold")
+	}
+
+	// 上一周期结束后再次失败，计数达到阈值
+	if _, err := store.BumpChannelCooldown(ctx, channelID, time.Now().Add(time.Hour), 500); err != nil {
+		t.Fatalf("bump channel cooldown: %v", err)
+	}
+	opened, err = store.OpenChannelCircuit(ctx, channelID, 2, until.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("open circuit: %v", err)
+	}
+	if !opened {
+		t.Fatal("circuit should open at threshold")
+	}
+
+	if err := store.ResetChannelCooldown(ctx, channelID); err != nil {
+		t.Fatalf("reset channel cooldown: %v", err)
+	}
+	cfg, err := store.GetConfig(ctx, channelID)
+	if err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	if cfg.ConsecutiveFailures != 0 || cfg.CooldownUntil != 0 {
+		t.Fatalf("expected reset, got failures=%d until=%d", cfg.ConsecutiveFailures, cfg.CooldownUntil)
+	}
+}
+
+func TestCooldown_KeyGroupSharesCooldown(t *testing.T) {
+	t.Parallel()
+
+	store, err := storage.CreateSQLiteStore(filepath.Join(t.TempDir(), "key_group.db"))
+	if err != nil {
+		t.Fatalf("create sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	ctx := context.Background()
+	ch1 := createTestChannel(t, ctx, store, "test-key-group-1")
+	ch2 := createTestChannel(t, ctx, store, "test-key-group-2")
+	if err := store.CreateAPIKeysBatch(ctx, []*model.APIKey{
+		{ChannelID: ch1, KeyIndex: 0, APIKey: "sk-shared", KeyGroup: "acct-a"},
+		{ChannelID: ch1, KeyIndex: 1, APIKey: "sk-solo"},
+		{ChannelID: ch2, KeyIndex: 0, APIKey: "sk-shared", KeyGroup: "acct-a"},
+	}); err != nil {
+		t.Fatalf("create api keys: %v", err)
+	}
+
+	if _, err := store.BumpKeyCooldown(ctx, ch1, 0, time.Now(), 429); err != nil {
+		t.Fatalf("bump key cooldown: %v", err)
+	}
+	cooldowns, err := store.GetAllKeyCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("get all key cooldowns: %v", err)
+	}
+	if _, ok := cooldowns[ch2][0]; !ok {
+		t.Fatal("expected group peer in another channel to be cooled")
+	}
+	if _, ok := cooldowns[ch1][1]; ok {
+		t.Fatal("ungrouped key must not be cooled")
+	}
+
+	// 更短的冷却不应缩短同组Key已有的更长冷却
+	long := time.Now().Add(10 * time.Minute)
+	if err := store.SetKeyCooldown(ctx, ch2, 0, long); err != nil {
+		t.Fatalf("set key cooldown: %v", err)
+	}
+	if err := store.SetKeyCooldown(ctx, ch1, 0, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("set key cooldown: %v", err)
+	}
+	key, err := store.GetAPIKey(ctx, ch2, 0)
+	if err != nil {
+		t.Fatalf("get api key: %v", err)
+	}
+	if key.KeyGroup != "acct-a" || key.CooldownUntil != long.Unix() {
+		t.Fatalf("expected group=acct-a until=%d, got group=%q until=%d", long.Unix(), key.KeyGroup, key.CooldownUntil)
+	}
+
+	// 任一同组Key成功后整组恢复
+	if err := store.ResetKeyCooldown(ctx, ch1, 0); err != nil {
+		t.Fatalf("reset key cooldown: %v", err)
+	}
+	cooldowns, err = store.GetAllKeyCooldowns(ctx)
+	if err != nil {
+		t.Fatalf("get all key cooldowns: %v", err)
+	}
+	if len(cooldowns[ch1]) > 0 || len(cooldowns[ch2]) > 0 {
+		t.Fatalf("expected group cooldown cleared, got %v", cooldowns)
+	}
+}
@@ -30,17 +30,22 @@ func scanLogEntry(scanner interface {
 	var actualModel sql.NullString
 	var serviceTier sql.NullString
 	var thinkingEffort sql.NullString
+	var requestID sql.NullString
+	var requestPath sql.NullString
 	var inputTokens, outputTokens, reasoningTokens, cacheReadTokens, cacheCreationTokens, cache5mTokens, cache1hTokens sql.NullInt64
 	var cost sql.NullFloat64
 	var costMultiplier sql.NullFloat64
+	var isSlowInt int
 
 	if err := scanner.Scan(&e.ID, &timeMs, &e.Model, &actualModel, &logSource, &e.ChannelID,
-		&e.StatusCode, &e.Message, &duration, &isStreamingInt, &firstByteTime, &apiKeyUsed, &apiKeyHash, &e.AuthTokenID, &clientIP, &baseURL, &serviceTier, &thinkingEffort,
-		&inputTokens, &outputTokens, &reasoningTokens, &cacheReadTokens, &cacheCreationTokens, &cache5mTokens, &cache1hTokens, &cost, &costMultiplier); err != nil {
+		&e.StatusCode, &e.Message, &duration, &isStreamingInt, &firstByteTime, &apiKeyUsed, &apiKeyHash, &e.AuthTokenID, &clientIP, &baseURL, &serviceTier, &thinkingEffort, &requestID, &requestPath,
+		&inputTokens, &outputTokens, &reasoningTokens, &cacheReadTokens, &cacheCreationTokens, &cache5mTokens, &cache1hTokens, &cost, &costMultiplier,
+		&e.AttemptNumber, &e.ChannelAttempt, &isSlowInt); err != nil {
 		return nil, err
 	}
 
 	e.Time = model.JSONTime{Time: time.UnixMilli(timeMs)}
+	e.Message = decodeLogText(e.Message)
 
 	if actualModel.Valid {
 		e.ActualModel = actualModel.String
@@ -50,15 +55,16 @@ func scanLogEntry(scanner interface {
 		e.Duration = duration.Float64
 	}
 	e.IsStreaming = isStreamingInt != 0
+	e.IsSlow = isSlowInt != 0
 	if firstByteTime.Valid {
 		e.FirstByteTime = firstByteTime.Float64
 	}
-	if apiKeyUsed.Valid && apiKeyUsed.String != "" {
-		e.APIKeyUsed = util.MaskAPIKey(apiKeyUsed.String)
-	}
 	if apiKeyHash.Valid {
 		e.APIKeyHash = apiKeyHash.String
 	}
+	if apiKeyUsed.Valid && apiKeyUsed.String != "" {
+		e.APIKeyUsed = util.MaskStoredAPIKey(apiKeyUsed.String, e.APIKeyHash)
+	}
 	if clientIP.Valid {
 		e.ClientIP = clientIP.String
 	}
@@ -71,6 +77,12 @@ func scanLogEntry(scanner interface {
 	if thinkingEffort.Valid {
 		e.ThinkingEffort = thinkingEffort.String
 	}
+	if requestID.Valid {
+		e.RequestID = requestID.String
+	}
+	if requestPath.Valid {
+		e.Path = requestPath.String
+	}
 	if inputTokens.Valid {
 		e.InputTokens = int(inputTokens.Int64)
 	}
@@ -159,15 +171,9 @@ func (s *SQLStore) AddLog(ctx context.Context, e *model.LogEntry) error {
 		e.Time = model.JSONTime{Time: time.Now()}
 	}
 	if e.DebugData != nil {
-		tx, err := s.BeginTx(ctx, nil)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = tx.Rollback() }()
-		if err := insertLogsWithDebug(ctx, s, tx, []*model.LogEntry{e}); err != nil {
-			return err
-		}
-		return tx.Commit()
+		return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+			return insertLogsWithDebug(ctx, s, tx, []*model.LogEntry{e})
+		})
 	}
 
 	// 复用 logRowArgs 统一构造参数（脱敏、时间标准化等逻辑集中维护）
@@ -175,12 +181,12 @@ func (s *SQLStore) AddLog(ctx context.Context, e *model.LogEntry) error {
 	return err
 }
 
-const logsInsertColumns = `INSERT INTO logs(time, minute_bucket, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort,
-			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier) VALUES `
+const logsInsertColumns = `INSERT INTO logs(time, minute_bucket, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow, error_detail) VALUES `
 
-const logRowPlaceholders = `(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+const logRowPlaceholders = `(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-const logRowParams = 27
+const logRowParams = 33
 
 // BatchAddLogs 批量写入日志（单事务，多值 INSERT 提升刷盘吞吐）
 // 设计：
@@ -320,21 +326,24 @@ func logRowArgs(e *model.LogEntry) []any {
 	timeMs := t.Round(0).UnixMilli()
 	minuteBucket := timeMs / minuteMs
 
-	maskedKey := e.APIKeyUsed
+	maskedKey := util.MaskLogAPIKey(e.APIKeyUsed)
 	apiKeyHash := util.HashAPIKey(e.APIKeyUsed)
-	if maskedKey != "" {
-		maskedKey = util.MaskAPIKey(maskedKey)
-	}
+
+	compress := compressLogMessages()
+	isError := e.StatusCode < 200 || e.StatusCode >= 300
+	errorDetail := encodeLogText(e.ErrorDetail, compress, isError)
 
 	return []any{
 		timeMs, minuteBucket, e.Model, e.ActualModel,
 		model.NormalizeStoredLogSource(e.LogSource),
-		e.ChannelID, e.StatusCode, e.Message, e.Duration,
+		e.ChannelID, e.StatusCode, encodeLogText(e.Message, compress, isError), e.Duration,
 		boolToInt(e.IsStreaming), e.FirstByteTime, maskedKey, apiKeyHash,
-		e.AuthTokenID, e.ClientIP, e.BaseURL, e.ServiceTier, e.ThinkingEffort,
+		e.AuthTokenID, e.ClientIP, e.BaseURL, e.ServiceTier, e.ThinkingEffort, e.RequestID, e.Path,
 		e.InputTokens, e.OutputTokens, e.ReasoningTokens, e.CacheReadInputTokens, e.CacheCreationInputTokens,
 		e.Cache5mInputTokens, e.Cache1hInputTokens, e.Cost,
 		normalizeCostMultiplier(e.CostMultiplier),
+		e.AttemptNumber, e.ChannelAttempt, boolToInt(e.IsSlow),
+		sql.NullString{String: errorDetail, Valid: errorDetail != ""},
 	}
 }
 
@@ -343,8 +352,8 @@ func (s *SQLStore) ListLogs(ctx context.Context, since time.Time, limit, offset
 	// 使用查询构建器构建复杂查询
 	// 消除 N+1：渠道过滤/名称解析用一次批量查询完成
 	baseQuery := `
-			SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort,
-				input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier
+			SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+				input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow
 			FROM logs`
 
 	// time字段现在是BIGINT毫秒时间戳，需要转换为Unix毫秒进行比较
@@ -398,6 +407,44 @@ func (s *SQLStore) ListLogs(ctx context.Context, since time.Time, limit, offset
 	return out, nil
 }
 
+// GetLog 按ID查询单条日志；full=true 时额外读取 error_detail（完整上游错误）
+// 日志不存在时返回 sql.ErrNoRows
+func (s *SQLStore) GetLog(ctx context.Context, id int64, full bool) (*model.LogEntry, error) {
+	row := s.QueryRowContext(ctx, `
+		SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow, error_detail
+		FROM logs
+		WHERE id = ?`, id)
+
+	var errorDetail sql.NullString
+	e, err := scanLogEntry(scanWithExtra{row: row, extra: []any{&errorDetail}})
+	if err != nil {
+		return nil, err
+	}
+	if full && errorDetail.Valid {
+		e.ErrorDetail = decodeLogText(errorDetail.String)
+	}
+
+	channelIDsToFetch := make(map[int64]bool)
+	if e.ChannelID != 0 {
+		channelIDsToFetch[e.ChannelID] = true
+	}
+	entries := []*model.LogEntry{e}
+	s.fillLogChannelNames(ctx, entries, channelIDsToFetch)
+	s.fillLogAuthTokenDescriptions(ctx, entries)
+	return e, nil
+}
+
+// scanWithExtra 在 scanLogEntry 的标准列之后追加扫描额外列
+type scanWithExtra struct {
+	row   *sql.Row
+	extra []any
+}
+
+func (s scanWithExtra) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
+
 // CountLogs 返回符合条件的日志总数（用于分页）
 func (s *SQLStore) CountLogs(ctx context.Context, since time.Time, filter *model.LogFilter) (int, error) {
 	baseQuery := `SELECT COUNT(*) FROM logs`
@@ -425,8 +472,8 @@ func (s *SQLStore) CountLogs(ctx context.Context, since time.Time, filter *model
 // ListLogsRange 查询指定时间范围内的日志（支持精确日期范围如"昨日"）
 func (s *SQLStore) ListLogsRange(ctx context.Context, since, until time.Time, limit, offset int, filter *model.LogFilter) ([]*model.LogEntry, error) {
 	baseQuery := `
-		SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort,
-			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier
+		SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow
 		FROM logs`
 
 	sinceMs := since.UnixMilli()
@@ -505,6 +552,60 @@ func (s *SQLStore) CountLogsRange(ctx context.Context, since, until time.Time, f
 	return count, err
 }
 
+// ListLogsByCursor 按键集游标查询指定时间范围内的日志（time DESC, id DESC）
+// cursor 为 nil 表示第一页；后续页传入上一页最后一行，避免 OFFSET 深分页的性能塌陷。
+func (s *SQLStore) ListLogsByCursor(ctx context.Context, since, until time.Time, cursor *model.LogCursor, limit int, filter *model.LogFilter) ([]*model.LogEntry, error) {
+	baseQuery := `
+		SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow
+		FROM logs`
+
+	qb := NewQueryBuilder(baseQuery).
+		Where("time >= ?", since.UnixMilli()).
+		Where("time <= ?", until.UnixMilli())
+	if cursor != nil {
+		qb.Where("(time < ? OR (time = ? AND id < ?))", cursor.TimeMs, cursor.TimeMs, cursor.ID)
+	}
+
+	if isEmpty, err := s.applyChannelFilter(ctx, qb, filter); err != nil {
+		return nil, err
+	} else if isEmpty {
+		return []*model.LogEntry{}, nil
+	}
+
+	qb.ApplyFilter(filter)
+
+	query, args := qb.BuildWithSuffix("ORDER BY time DESC, id DESC LIMIT ?")
+	args = append(args, limit)
+
+	rows, err := s.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*model.LogEntry{}
+	channelIDsToFetch := make(map[int64]bool)
+	for rows.Next() {
+		e, err := scanLogEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		if e.ChannelID != 0 {
+			channelIDsToFetch[e.ChannelID] = true
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	s.fillLogChannelNames(ctx, out, channelIDsToFetch)
+	s.fillLogAuthTokenDescriptions(ctx, out)
+
+	return out, nil
+}
+
 // GetTodayChannelURLStats 聚合当日全部渠道的 URL 级日志统计，用于启动时回填 URLSelector 内存态。
 func (s *SQLStore) GetTodayChannelURLStats(ctx context.Context, dayStart time.Time) ([]model.ChannelURLLogStat, error) {
 	sinceMs := dayStart.UnixMilli()
@@ -594,8 +695,8 @@ func (s *SQLStore) ListLogsRangeWithCount(ctx context.Context, since, until time
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		qb := NewQueryBuilder(`SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort,
-			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier
+		qb := NewQueryBuilder(`SELECT id, time, model, actual_model, log_source, channel_id, status_code, message, duration, is_streaming, first_byte_time, api_key_used, api_key_hash, auth_token_id, client_ip, base_url, service_tier, thinking_effort, request_id, path,
+			input_tokens, output_tokens, reasoning_tokens, cache_read_input_tokens, cache_creation_input_tokens, cache_5m_input_tokens, cache_1h_input_tokens, cost, cost_multiplier, attempt_number, channel_attempt, is_slow
 			FROM logs`).
 			Where("time >= ?", sinceMs).
 			Where("time <= ?", untilMs)
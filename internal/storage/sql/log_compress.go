@@ -0,0 +1,69 @@
+package sql
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"ccLoad/internal/util"
+)
+
+const (
+	// compressedLogMarker 压缩日志文本的首字节标记（正常日志文本不会以控制字符 \x01 开头）
+	compressedLogMarker = "\x01"
+	// minCompressLogLen 低于该长度的文本不压缩（压缩+base64 后通常不会更短）
+	minCompressLogLen = 256
+	// maxPlainLogLen 成功（2xx）日志的文本仅在达到该长度时才压缩，普通行保持明文便于阅读与搜索
+	maxPlainLogLen = 4096
+)
+
+// compressLogMessages 延迟解析 CCLOAD_COMPRESS_LOG_MESSAGES（默认关闭）。
+// 关闭后仍可读取已压缩的历史行，开关可随时切换。
+var compressLogMessages = sync.OnceValue(func() bool {
+	return util.ParseBoolDefault(os.Getenv("CCLOAD_COMPRESS_LOG_MESSAGES"), false)
+})
+
+// encodeLogText 写入前压缩日志文本（deflate + base64，带标记字节；TEXT 列需保持合法 UTF-8）。
+// 仅压缩错误日志中的较长文本与超过 maxPlainLogLen 的文本；压缩后不更短时原样写入。
+func encodeLogText(s string, compress, isError bool) string {
+	if !compress || len(s) < minCompressLogLen || (!isError && len(s) < maxPlainLogLen) {
+		return s
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return s
+	}
+	if _, err := io.WriteString(w, s); err != nil {
+		return s
+	}
+	if err := w.Close(); err != nil {
+		return s
+	}
+	encoded := compressedLogMarker + base64.RawStdEncoding.EncodeToString(buf.Bytes())
+	if len(encoded) >= len(s) {
+		return s
+	}
+	return encoded
+}
+
+// decodeLogText 读取时透明解压；无标记的历史行原样返回，损坏数据也原样返回避免查询失败
+func decodeLogText(s string) string {
+	payload, ok := strings.CutPrefix(s, compressedLogMarker)
+	if !ok {
+		return s
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return s
+	}
+	data, err := io.ReadAll(flate.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		return s
+	}
+	return string(data)
+}
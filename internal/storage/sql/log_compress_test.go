@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"ccLoad/internal/model"
+)
+
+func TestEncodeDecodeLogText(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat(`{"error":{"type":"overloaded_error","message":"Overloaded"}} `, 40)
+	encoded := encodeLogText(long, true, true)
+	if !strings.HasPrefix(encoded, compressedLogMarker) || len(encoded) >= len(long) {
+		t.Fatalf("expected compressed text, got %d bytes (orig %d)", len(encoded), len(long))
+	}
+	if got := decodeLogText(encoded); got != long {
+		t.Fatalf("round trip mismatch: %q", got)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		in       string
+		compress bool
+		isError  bool
+	}{
+		{name: "disabled", in: long, compress: false, isError: true},
+		{name: "short", in: "upstream status 500", compress: true, isError: true},
+		{name: "incompressible", in: randomLikeText(400), compress: true, isError: true},
+		{name: "success below plain limit", in: long, compress: true, isError: false},
+	} {
+		if got := encodeLogText(tc.in, tc.compress, tc.isError); got != tc.in {
+			t.Errorf("%s: expected text stored as-is", tc.name)
+		}
+	}
+
+	// 历史未压缩行与损坏数据原样返回
+	for _, s := range []string{"", "plain message", compressedLogMarker + "!!not-base64!!"} {
+		if got := decodeLogText(s); got != s {
+			t.Errorf("decodeLogText(%q) = %q", s, got)
+		}
+	}
+}
+
+func TestLogRowArgs_CompressesMessageAndErrorDetail(t *testing.T) {
+	orig := compressLogMessages
+	compressLogMessages = func() bool { return true }
+	t.Cleanup(func() { compressLogMessages = orig })
+
+	long := strings.Repeat("upstream error body ", 50)
+	args := logRowArgs(&model.LogEntry{StatusCode: 502, Message: long, ErrorDetail: long + long})
+
+	message, ok := args[7].(string)
+	if !ok || !strings.HasPrefix(message, compressedLogMarker) || decodeLogText(message) != long {
+		t.Fatalf("message arg not compressed: %#v", args[7])
+	}
+	detail, ok := args[len(args)-1].(sql.NullString)
+	if !ok || !detail.Valid || decodeLogText(detail.String) != long+long {
+		t.Fatalf("error_detail arg not compressed: %#v", args[len(args)-1])
+	}
+}
+
+func TestLogRowArgs_KeepsSuccessMessagePlain(t *testing.T) {
+	orig := compressLogMessages
+	compressLogMessages = func() bool { return true }
+	t.Cleanup(func() { compressLogMessages = orig })
+
+	diag := strings.Repeat("stream diag ", 40)
+	args := logRowArgs(&model.LogEntry{StatusCode: 200, Message: diag})
+	if message, ok := args[7].(string); !ok || message != diag {
+		t.Fatalf("2xx message should stay plain for search, got %#v", args[7])
+	}
+
+	huge := strings.Repeat("stream diag ", 400)
+	args = logRowArgs(&model.LogEntry{StatusCode: 200, Message: huge})
+	if message, ok := args[7].(string); !ok || !strings.HasPrefix(message, compressedLogMarker) || decodeLogText(message) != huge {
+		t.Fatalf("2xx message over %d bytes should be compressed, got %#v", maxPlainLogLen, args[7])
+	}
+}
+
+// randomLikeText 生成压缩率很低的文本（线性同余序列映射到 base64 字符集）
+func randomLikeText(n int) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	var b strings.Builder
+	x := uint32(2463534242)
+	for range n {
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		b.WriteByte(alphabet[x%64])
+	}
+	return b.String()
+}
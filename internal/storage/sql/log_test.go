@@ -1,8 +1,14 @@
 package sql_test
 
 import (
+	"bytes"
+	"compress/flate"
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +89,190 @@ func TestLog_AddAndListPersistsReasoningTokens(t *testing.T) {
 	}
 }
 
+func TestLog_ListLogsFiltersByRequestID(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_request_id.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-request-id-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok", RequestID: "req-a"},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 502, Message: "fail", RequestID: "req-b"},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{RequestID: "req-b"})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].RequestID != "req-b" || logs[0].StatusCode != 502 {
+		t.Fatalf("unexpected logs: %+v", logs)
+	}
+}
+
+func TestLog_PathPersistsAndFiltersBySubstring(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_path.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-path-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok", Path: "/v1/chat/completions"},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 404, Message: "fail", Path: "/v1/responses"},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{PathLike: "responses"})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Path != "/v1/responses" || logs[0].StatusCode != 404 {
+		t.Fatalf("unexpected logs: %+v", logs)
+	}
+}
+
+func TestLog_FiltersByDurationRange(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_duration.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-duration-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "fast", Duration: 0.4},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "slow", Duration: 12.5},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "slow-stream", Duration: 20, IsStreaming: true, FirstByteTime: 3.2},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "fast-stream", Duration: 2, IsStreaming: true, FirstByteTime: 0.3},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	minDur, maxDur := int64(10000), int64(15000)
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{MinDurationMs: &minDur, MaxDurationMs: &maxDur})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "slow" {
+		t.Fatalf("unexpected duration-range logs: %+v", logs)
+	}
+
+	minFB := int64(1000)
+	logs, err = store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{MinFirstByteMs: &minFB})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "slow-stream" {
+		t.Fatalf("unexpected first-byte logs: %+v", logs)
+	}
+
+	count, err := store.CountLogs(ctx, now.Add(-time.Hour), &model.LogFilter{MinDurationMs: &minDur})
+	if err != nil {
+		t.Fatalf("count logs: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("CountLogs(min_duration_ms=10000)=%d, want 2", count)
+	}
+}
+
+func TestLog_AttemptNumberPersistsAndFeedsFirstTryStats(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_attempt_number.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-attempt-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok", RequestID: "first", AttemptNumber: 1, ChannelAttempt: 1},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 502, Message: "fail", RequestID: "retry", AttemptNumber: 1, ChannelAttempt: 1},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok", RequestID: "retry", AttemptNumber: 2, ChannelAttempt: 1},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok", RequestID: "legacy"},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{RequestID: "retry"})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	got := map[int]int{}
+	for _, e := range logs {
+		got[e.AttemptNumber] = e.StatusCode
+		if e.ChannelAttempt != 1 {
+			t.Fatalf("channel_attempt=%d, want 1", e.ChannelAttempt)
+		}
+	}
+	if len(logs) != 2 || got[1] != 502 || got[2] != 200 {
+		t.Fatalf("unexpected attempts: %+v", got)
+	}
+
+	stats, err := store.GetStats(ctx, now.Add(-time.Hour), now.Add(time.Hour), nil, false)
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("len(stats)=%d, want 1", len(stats))
+	}
+	// 历史数据（attempt_number=0）与重试后成功都不计入首试成功
+	if stats[0].Success != 3 || stats[0].FirstTrySuccess != 1 {
+		t.Fatalf("success=%d first_try_success=%d, want 3/1", stats[0].Success, stats[0].FirstTrySuccess)
+	}
+}
+
+func TestLog_GetLogReturnsErrorDetailOnlyWhenFull(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_get.db")
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-get-channel")
+
+	detail := strings.Repeat("x", 2000)
+	if err := store.AddLog(ctx, &model.LogEntry{
+		Time: newJSONTime(time.Now()), Model: "gpt-4", ChannelID: channelID, StatusCode: 502,
+		Message: detail[:512], ErrorDetail: detail,
+	}); err != nil {
+		t.Fatalf("add log: %v", err)
+	}
+	logs, err := store.ListLogs(ctx, time.Now().Add(-time.Hour), 10, 0, nil)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("list logs: %v (n=%d)", err, len(logs))
+	}
+	if logs[0].ErrorDetail != "" {
+		t.Fatal("list queries must not load error_detail")
+	}
+
+	got, err := store.GetLog(ctx, logs[0].ID, false)
+	if err != nil {
+		t.Fatalf("get log: %v", err)
+	}
+	if got.ErrorDetail != "" || got.ChannelName != "log-get-channel" {
+		t.Fatalf("unexpected entry without full: detail=%d channel=%q", len(got.ErrorDetail), got.ChannelName)
+	}
+
+	got, err = store.GetLog(ctx, logs[0].ID, true)
+	if err != nil {
+		t.Fatalf("get log full: %v", err)
+	}
+	if got.ErrorDetail != detail {
+		t.Fatalf("error_detail len=%d, want %d", len(got.ErrorDetail), len(detail))
+	}
+
+	if _, err := store.GetLog(ctx, logs[0].ID+100, true); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("missing log err=%v, want sql.ErrNoRows", err)
+	}
+}
+
 func TestLog_AddLogPersistsDebugData(t *testing.T) {
 	t.Parallel()
 
@@ -336,3 +526,183 @@ func TestLog_ListRangeWithCount_PreservesZeroCostMultiplier(t *testing.T) {
 		t.Fatalf("cost_multiplier=%v, want 0", logs[0].CostMultiplier)
 	}
 }
+
+func TestLog_ListLogsByCursorWalksAllRowsWithTies(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_cursor.db")
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-cursor-channel")
+
+	base := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	entries := make([]*model.LogEntry, 0, 7)
+	for i := range 7 {
+		// 每两条共享同一毫秒时间戳，验证 (time, id) 复合游标不丢不重
+		entries = append(entries, &model.LogEntry{
+			Time:       newJSONTime(base.Add(time.Duration(i/2) * time.Millisecond)),
+			Model:      "gpt-4",
+			ChannelID:  channelID,
+			StatusCode: http.StatusOK,
+			Message:    "ok",
+			APIKeyUsed: "sk-abcdefghijklmnop",
+		})
+	}
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	since, until := base.Add(-time.Second), time.Now()
+	seen := make(map[int64]bool)
+	var cursor *model.LogCursor
+	var prev *model.LogEntry
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("cursor pagination did not terminate")
+		}
+		page, err := store.ListLogsByCursor(ctx, since, until, cursor, 3, nil)
+		if err != nil {
+			t.Fatalf("list logs by cursor: %v", err)
+		}
+		for _, e := range page {
+			if seen[e.ID] {
+				t.Fatalf("duplicate log id %d", e.ID)
+			}
+			seen[e.ID] = true
+			if prev != nil && (e.Time.UnixMilli() > prev.Time.UnixMilli() ||
+				(e.Time.UnixMilli() == prev.Time.UnixMilli() && e.ID > prev.ID)) {
+				t.Fatalf("order violated: %d@%d after %d@%d", e.ID, e.Time.UnixMilli(), prev.ID, prev.Time.UnixMilli())
+			}
+			if e.APIKeyUsed == "sk-abcdefghijklmnop" {
+				t.Fatalf("api key must be masked, got %q", e.APIKeyUsed)
+			}
+			prev = e
+		}
+		if len(page) < 3 {
+			break
+		}
+		last := page[len(page)-1]
+		cursor = &model.LogCursor{TimeMs: last.Time.UnixMilli(), ID: last.ID}
+	}
+	if len(seen) != len(entries) {
+		t.Fatalf("seen %d logs, want %d", len(seen), len(entries))
+	}
+}
+
+func TestLog_ReadsCompressedMessageRows(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_compressed.db")
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "compressed-log-channel")
+
+	now := time.Now()
+	if err := store.AddLog(ctx, &model.LogEntry{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 500, Message: "placeholder"}); err != nil {
+		t.Fatalf("add log: %v", err)
+	}
+
+	// 模拟 CCLOAD_COMPRESS_LOG_MESSAGES 开启时写入的行：\x01 + base64(deflate)
+	original := strings.Repeat("upstream overloaded; ", 30)
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	_, _ = w.Write([]byte(original))
+	_ = w.Close()
+	compressed := "\x01" + base64.RawStdEncoding.EncodeToString(buf.Bytes())
+
+	execer, ok := store.(interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	})
+	if !ok {
+		t.Fatalf("store does not expose ExecContext: %T", store)
+	}
+	if _, err := execer.ExecContext(ctx, "UPDATE logs SET message = ?, error_detail = ?", compressed, compressed); err != nil {
+		t.Fatalf("update message: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, nil)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("list logs: %v (%d)", err, len(logs))
+	}
+	if logs[0].Message != original {
+		t.Fatalf("ListLogs message not decompressed: %q", logs[0].Message)
+	}
+	full, err := store.GetLog(ctx, logs[0].ID, true)
+	if err != nil {
+		t.Fatalf("get log: %v", err)
+	}
+	if full.Message != original || full.ErrorDetail != original {
+		t.Fatalf("GetLog not decompressed: message=%q detail=%q", full.Message, full.ErrorDetail)
+	}
+}
+
+func TestLog_FiltersSlowOnly(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_slow.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-slow-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "fast", Duration: 0.4},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "slow", Duration: 12.5, IsSlow: true},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+	if err := store.AddLog(ctx, &model.LogEntry{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 502, Message: "slow-error", Duration: 30, IsSlow: true}); err != nil {
+		t.Fatalf("add log: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{SlowOnly: true})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 slow logs, got %+v", logs)
+	}
+	for _, l := range logs {
+		if !l.IsSlow || l.Message == "fast" {
+			t.Fatalf("unexpected slow log: %+v", l)
+		}
+	}
+
+	count, err := store.CountLogs(ctx, now.Add(-time.Hour), &model.LogFilter{SlowOnly: true})
+	if err != nil {
+		t.Fatalf("count logs: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("CountLogs(slow_only)=%d, want 2", count)
+	}
+}
+
+func TestLog_FiltersErrorsOnly(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, "logs_errors_only.db")
+
+	ctx := context.Background()
+	channelID := createTestChannel(t, ctx, store, "log-errors-channel")
+
+	now := time.Now()
+	if err := store.BatchAddLogs(ctx, []*model.LogEntry{
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 200, Message: "ok"},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 204, Message: "no-content"},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 429, Message: "rate-limited"},
+		{Time: newJSONTime(now), Model: "gpt-4", ChannelID: channelID, StatusCode: 502, Message: "bad-gateway"},
+	}); err != nil {
+		t.Fatalf("batch add logs: %v", err)
+	}
+
+	logs, err := store.ListLogs(ctx, now.Add(-time.Hour), 10, 0, &model.LogFilter{ChannelID: &channelID, ErrorsOnly: true})
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 error logs, got %+v", logs)
+	}
+	for _, l := range logs {
+		if l.StatusCode >= 200 && l.StatusCode < 300 {
+			t.Fatalf("unexpected success log: %+v", l)
+		}
+	}
+}
@@ -27,6 +27,7 @@ func (s *SQLStore) executeStatsQuery(ctx context.Context, startTime, endTime tim
 			SUM(CASE WHEN status_code >= 200 AND status_code < 300 THEN 1 ELSE 0 END) AS success,
 			SUM(CASE WHEN (status_code < 200 OR status_code >= 300) AND status_code != 499 THEN 1 ELSE 0 END) AS error,
 			SUM(CASE WHEN status_code != 499 THEN 1 ELSE 0 END) AS total,
+			SUM(CASE WHEN status_code >= 200 AND status_code < 300 AND attempt_number = 1 THEN 1 ELSE 0 END) AS first_try_success,
 			ROUND(
 				AVG(CASE WHEN is_streaming = 1 AND first_byte_time > 0 AND status_code >= 200 AND status_code < 300 THEN first_byte_time ELSE NULL END),
 				3
@@ -82,7 +83,7 @@ func (s *SQLStore) executeStatsQuery(ctx context.Context, startTime, endTime tim
 
 		scanArgs := []any{
 			&entry.ChannelID, &entry.Model,
-			&entry.Success, &entry.Error, &entry.Total,
+			&entry.Success, &entry.Error, &entry.Total, &entry.FirstTrySuccess,
 			&avgFirstByteTime, &avgDuration,
 		}
 		if withLastSuccess {
@@ -363,7 +364,7 @@ func (s *SQLStore) fillStatsLastRequests(ctx context.Context, stats []model.Stat
 				statusValue := int(status.Int64)
 				stats[idx].LastRequestStatus = &statusValue
 			}
-			stats[idx].LastRequestMessage = message.String
+			stats[idx].LastRequestMessage = decodeLogText(message.String)
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -421,7 +422,7 @@ func (s *SQLStore) fillStatsLastRequestsByEntry(ctx context.Context, stats []mod
 			statusValue := int(status.Int64)
 			stats[idx].LastRequestStatus = &statusValue
 		}
-		stats[idx].LastRequestMessage = message.String
+		stats[idx].LastRequestMessage = decodeLogText(message.String)
 	}
 	if err := rows.Err(); err != nil {
 		return err
@@ -921,17 +922,15 @@ func (s *SQLStore) GetChannelSuccessRates(ctx context.Context, since time.Time)
 }
 
 // GetTodayChannelCosts 获取今日各渠道倍率后成本（effective）
-// 语义：与 CostCache 保持一致——累加 cost * cost_multiplier，用于每日限额检查
+// 语义：与 CostCache 保持一致——读 stats_daily 实时计数，不受日志采样影响，用于每日限额检查
 func (s *SQLStore) GetTodayChannelCosts(ctx context.Context, todayStart time.Time) (map[int64]float64, error) {
-	todayStartMs := todayStart.UnixMilli()
-
 	query := `
-		SELECT channel_id, COALESCE(SUM(COALESCE(cost, 0.0) * COALESCE(cost_multiplier, 1)), 0) as total_cost
-		FROM logs
-		WHERE time >= ? AND channel_id > 0 AND log_source = ?
+		SELECT channel_id, COALESCE(SUM(cost), 0) as total_cost
+		FROM stats_daily
+		WHERE day >= ? AND channel_id > 0
 		GROUP BY channel_id`
 
-	rows, err := s.QueryContext(ctx, query, todayStartMs, model.LogSourceProxy)
+	rows, err := s.QueryContext(ctx, query, todayStart.Format(statsDayLayout))
 	if err != nil {
 		return nil, err
 	}
@@ -949,3 +948,68 @@ func (s *SQLStore) GetTodayChannelCosts(ctx context.Context, todayStart time.Tim
 
 	return result, rows.Err()
 }
+
+// GetModelCosts 获取 since 所在自然日起各模型倍率后成本（effective）
+// 语义与 GetTodayChannelCosts 一致，按请求模型分组，用于模型月度预算
+func (s *SQLStore) GetModelCosts(ctx context.Context, since time.Time) (map[string]float64, error) {
+	query := `
+		SELECT model, COALESCE(SUM(cost), 0) as total_cost
+		FROM stats_daily
+		WHERE day >= ? AND model != ''
+		GROUP BY model`
+
+	rows, err := s.QueryContext(ctx, query, since.Format(statsDayLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string]float64)
+	for rows.Next() {
+		var modelName string
+		var totalCost float64
+		if err := rows.Scan(&modelName, &totalCost); err != nil {
+			return nil, err
+		}
+		result[modelName] = totalCost
+	}
+
+	return result, rows.Err()
+}
+
+// GetErrorBreakdown 统计 since 起非 2xx 代理请求按状态码（byChannel=true 时再按渠道）分组的数量
+// 单次 GROUP BY 聚合，按数量降序返回
+func (s *SQLStore) GetErrorBreakdown(ctx context.Context, since time.Time, byChannel bool) ([]model.ErrorBreakdownEntry, error) {
+	groupCols := "status_code"
+	if byChannel {
+		groupCols = "status_code, channel_id"
+	}
+	query := `
+		SELECT ` + groupCols + `, COUNT(*) AS cnt
+		FROM logs
+		WHERE time >= ? AND log_source = ? AND (status_code < 200 OR status_code >= 300)
+		GROUP BY ` + groupCols + `
+		ORDER BY cnt DESC, status_code ASC`
+
+	rows, err := s.QueryContext(ctx, query, since.UnixMilli(), model.LogSourceProxy)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make([]model.ErrorBreakdownEntry, 0)
+	for rows.Next() {
+		var e model.ErrorBreakdownEntry
+		dest := []any{&e.StatusCode}
+		if byChannel {
+			dest = append(dest, &e.ChannelID)
+		}
+		dest = append(dest, &e.Count)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	return result, rows.Err()
+}
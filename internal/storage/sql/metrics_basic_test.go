@@ -195,7 +195,12 @@ func TestMetrics_BasicQueriesAndFilters(t *testing.T) {
 		t.Fatalf("manual test stats=%+v, want one success record", manualStats)
 	}
 
-	// GetTodayChannelCosts：覆盖今日成本聚合
+	// GetTodayChannelCosts：覆盖今日成本聚合（读 stats_daily 实时计数）
+	if err := store.AddDailyStats(ctx, []model.DailyStat{
+		{Day: now.Format("2006-01-02"), ChannelID: openaiCfg.ID, Model: "gpt-4o", SuccessCount: 1, Cost: 0.85},
+	}); err != nil {
+		t.Fatalf("AddDailyStats failed: %v", err)
+	}
 	costs, err := store.GetTodayChannelCosts(ctx, now.Add(-24*time.Hour))
 	if err != nil {
 		t.Fatalf("GetTodayChannelCosts failed: %v", err)
@@ -874,3 +879,80 @@ func TestGetStats_PreservesZeroCostMultiplierForFreeChannels(t *testing.T) {
 		t.Fatalf("expected effective_cost=0, got %v", *stats[0].EffectiveCost)
 	}
 }
+
+func TestGetModelCosts_SumsDailyStatsByModel(t *testing.T) {
+	store := newTestStore(t, "model_costs.db")
+	ctx := context.Background()
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	deltas := []model.DailyStat{
+		{Day: today, Model: "gpt-4o", ChannelID: 1, SuccessCount: 1, Cost: 0.5},
+		{Day: today, Model: "gpt-4o", ChannelID: 2, SuccessCount: 1, Cost: 2},
+		{Day: today, Model: "claude-sonnet-4", ChannelID: 1, SuccessCount: 1, Cost: 3},
+		// since 之前的日期不计入
+		{Day: now.AddDate(0, 0, -2).Format("2006-01-02"), Model: "gpt-4o", ChannelID: 1, SuccessCount: 1, Cost: 50},
+	}
+	if err := store.AddDailyStats(ctx, deltas); err != nil {
+		t.Fatalf("AddDailyStats failed: %v", err)
+	}
+	// 同一主键重复写入为累加
+	if err := store.AddDailyStats(ctx, deltas[:1]); err != nil {
+		t.Fatalf("AddDailyStats (again) failed: %v", err)
+	}
+
+	costs, err := store.GetModelCosts(ctx, now)
+	if err != nil {
+		t.Fatalf("GetModelCosts failed: %v", err)
+	}
+	if len(costs) != 2 || costs["gpt-4o"] != 3 || costs["claude-sonnet-4"] != 3 {
+		t.Fatalf("costs=%v, want gpt-4o=3 claude-sonnet-4=3", costs)
+	}
+}
+
+func TestGetErrorBreakdown_GroupsNon2xxByStatusAndChannel(t *testing.T) {
+	store := newTestStore(t, "error_breakdown.db")
+	ctx := context.Background()
+
+	now := time.Now()
+	entries := []*model.LogEntry{
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 200, Message: "ok"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 429, Message: "rl"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 2, StatusCode: 429, Message: "rl"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 2, StatusCode: 429, Message: "rl"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 2, StatusCode: 401, Message: "auth"},
+		{Time: model.JSONTime{Time: now.Add(-48 * time.Hour)}, Model: "m", ChannelID: 1, StatusCode: 500, Message: "old"},
+		{Time: model.JSONTime{Time: now}, Model: "m", ChannelID: 1, StatusCode: 502, Message: "check", LogSource: model.LogSourceScheduledCheck},
+	}
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+
+	since := now.Add(-24 * time.Hour)
+	byStatus, err := store.GetErrorBreakdown(ctx, since, false)
+	if err != nil {
+		t.Fatalf("GetErrorBreakdown: %v", err)
+	}
+	want := []model.ErrorBreakdownEntry{{StatusCode: 429, Count: 3}, {StatusCode: 401, Count: 1}}
+	if len(byStatus) != len(want) || byStatus[0] != want[0] || byStatus[1] != want[1] {
+		t.Fatalf("by status=%+v, want %+v", byStatus, want)
+	}
+
+	byChannel, err := store.GetErrorBreakdown(ctx, since, true)
+	if err != nil {
+		t.Fatalf("GetErrorBreakdown byChannel: %v", err)
+	}
+	wantCh := []model.ErrorBreakdownEntry{
+		{StatusCode: 429, ChannelID: 2, Count: 2},
+		{StatusCode: 401, ChannelID: 2, Count: 1},
+		{StatusCode: 429, ChannelID: 1, Count: 1},
+	}
+	if len(byChannel) != len(wantCh) {
+		t.Fatalf("by channel=%+v, want %+v", byChannel, wantCh)
+	}
+	for i := range wantCh {
+		if byChannel[i] != wantCh[i] {
+			t.Fatalf("by channel[%d]=%+v, want %+v", i, byChannel[i], wantCh[i])
+		}
+	}
+}
@@ -78,6 +78,28 @@ func (wb *WhereBuilder) ApplyLogFilter(filter *model.LogFilter) *WhereBuilder {
 	if filter.AuthTokenID != nil {
 		wb.AddCondition("auth_token_id = ?", *filter.AuthTokenID)
 	}
+	if filter.RequestID != "" {
+		wb.AddCondition("request_id = ?", filter.RequestID)
+	}
+	if filter.PathLike != "" {
+		wb.AddCondition("path LIKE ?", "%"+filter.PathLike+"%")
+	}
+	// duration/first_byte_time 以秒存储，毫秒阈值换算后比较
+	if filter.MinDurationMs != nil {
+		wb.AddCondition("duration >= ?", float64(*filter.MinDurationMs)/1000)
+	}
+	if filter.MaxDurationMs != nil {
+		wb.AddCondition("duration <= ?", float64(*filter.MaxDurationMs)/1000)
+	}
+	if filter.MinFirstByteMs != nil {
+		wb.AddCondition("is_streaming = 1 AND first_byte_time >= ?", float64(*filter.MinFirstByteMs)/1000)
+	}
+	if filter.SlowOnly {
+		wb.AddCondition("is_slow = 1")
+	}
+	if filter.ErrorsOnly {
+		wb.AddCondition("(status_code < 200 OR status_code >= 300)")
+	}
 	switch filter.LogSource {
 	case model.LogSourceAll:
 	case model.LogSourceDetection:
@@ -124,13 +146,21 @@ func (cs *ConfigScanner) ScanConfig(scanner interface {
 	var scheduledCheckEnabledInt int
 	var scheduledCheckModel string
 	var customRequestRules sql.NullString
+	var allowedMethods string
+	var blockedModels string
+	var stripHeaders string
+	var retryErrorSubstrings string
+	var maintenanceInt int
+	var redirectRoutingOnlyInt int
+	var cacheableInt int
+	var restoreResponseModelInt int
 	var createdAtRaw, updatedAtRaw any // 使用any接受任意类型（兼容字符串、整数或RFC3339）
 
 	// 扫描key_count字段（从JOIN查询获取）
 	// 注意：不再包含 models 和 model_redirects 字段
 	if err := scanner.Scan(&c.ID, &c.Name, &c.URL, &c.Priority,
 		&c.RPMLimit, &c.MaxConcurrency, &c.ChannelType, &c.ProtocolTransformMode, &enabledInt, &scheduledCheckEnabledInt, &scheduledCheckModel,
-		&c.CooldownUntil, &c.CooldownDurationMs, &c.DailyCostLimit, &c.CostMultiplier, &customRequestRules, &c.ProxyURL, &c.KeyCount,
+		&c.CooldownUntil, &c.CooldownDurationMs, &c.ConsecutiveFailures, &c.LastUsedAt, &c.DailyCostLimit, &c.CostMultiplier, &customRequestRules, &c.ProxyURL, &allowedMethods, &redirectRoutingOnlyInt, &c.MaxInputTokens, &c.MaxOutputTokens, &cacheableInt, &restoreResponseModelInt, &c.SuccessCodes, &c.ActiveSchedule, &blockedModels, &stripHeaders, &retryErrorSubstrings, &maintenanceInt, &c.KeyCount,
 		&createdAtRaw, &updatedAtRaw); err != nil {
 		return nil, err
 	}
@@ -139,6 +169,14 @@ func (cs *ConfigScanner) ScanConfig(scanner interface {
 	c.ScheduledCheckEnabled = scheduledCheckEnabledInt != 0
 	c.ScheduledCheckModel = scheduledCheckModel
 	c.CustomRequestRules = parseCustomRequestRules(c.ID, customRequestRules)
+	c.AllowedMethods = parseAllowedMethods(allowedMethods)
+	c.BlockedModels = parseBlockedModels(blockedModels)
+	c.StripHeaders = parseStripHeaders(stripHeaders)
+	c.RetryErrorSubstrings = parseRetryErrorSubstrings(retryErrorSubstrings)
+	c.RedirectRoutingOnly = redirectRoutingOnlyInt != 0
+	c.Cacheable = cacheableInt != 0
+	c.Maintenance = maintenanceInt != 0
+	c.RestoreResponseModel = restoreResponseModelInt != 0
 	if c.CostMultiplier < 0 {
 		c.CostMultiplier = 1
 	}
@@ -309,3 +347,55 @@ func marshalCustomRequestRules(rules *model.CustomRequestRules) (sql.NullString,
 	}
 	return sql.NullString{String: string(data), Valid: true}, nil
 }
+
+// parseAllowedMethods 解析 channels.allowed_methods（逗号分隔），空串表示不限制
+func parseAllowedMethods(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	return model.NormalizeHTTPMethods(strings.Split(raw, ","))
+}
+
+// marshalAllowedMethods 将允许的 HTTP 方法序列化为逗号分隔字符串
+func marshalAllowedMethods(methods []string) string {
+	return strings.Join(model.NormalizeHTTPMethods(methods), ",")
+}
+
+// parseBlockedModels 解析 channels.blocked_models（逗号分隔），空串表示不屏蔽
+func parseBlockedModels(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	return model.NormalizeBlockedModels(strings.Split(raw, ","))
+}
+
+// marshalBlockedModels 将屏蔽模型列表序列化为逗号分隔字符串
+func marshalBlockedModels(models []string) string {
+	return strings.Join(model.NormalizeBlockedModels(models), ",")
+}
+
+// parseStripHeaders 解析 channels.strip_headers（逗号分隔），空串表示不额外剥离
+func parseStripHeaders(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	return model.NormalizeStripHeaders(strings.Split(raw, ","))
+}
+
+// marshalStripHeaders 将剥离请求头列表序列化为逗号分隔字符串
+func marshalStripHeaders(headers []string) string {
+	return strings.Join(model.NormalizeStripHeaders(headers), ",")
+}
+
+// parseRetryErrorSubstrings 解析 channels.retry_error_substrings（换行分隔，子串本身可能包含逗号）
+func parseRetryErrorSubstrings(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	return model.NormalizeRetryErrorSubstrings(strings.Split(raw, "\n"))
+}
+
+// marshalRetryErrorSubstrings 将强制重试子串序列化为换行分隔字符串
+func marshalRetryErrorSubstrings(items []string) string {
+	return strings.Join(model.NormalizeRetryErrorSubstrings(items), "\n")
+}
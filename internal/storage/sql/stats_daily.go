@@ -0,0 +1,210 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+// statsDayLayout stats_daily.day 的日期格式（本地时区）
+const statsDayLayout = "2006-01-02"
+
+// RollupDailyStats 将 day 所在本地自然日的代理日志按渠道+模型汇总写入 stats_daily（幂等，DELETE + INSERT）
+// 语义与 GetStats 一致：499（客户端取消）不计入错误，成本为倍率后成本
+func (s *SQLStore) RollupDailyStats(ctx context.Context, day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	dayStr := start.Format(statsDayLayout)
+
+	query := `
+		SELECT
+			channel_id,
+			COALESCE(model, '') AS model,
+			SUM(CASE WHEN status_code >= 200 AND status_code < 300 THEN 1 ELSE 0 END) AS success,
+			SUM(CASE WHEN (status_code < 200 OR status_code >= 300) AND status_code != 499 THEN 1 ELSE 0 END) AS error,
+			SUM(COALESCE(input_tokens, 0)),
+			SUM(COALESCE(output_tokens, 0)),
+			SUM(COALESCE(cache_read_input_tokens, 0)),
+			SUM(COALESCE(cache_creation_input_tokens, 0)),
+			SUM(COALESCE(cost, 0.0) * COALESCE(cost_multiplier, 1))
+		FROM logs
+		WHERE time >= ? AND time < ? AND channel_id > 0 AND log_source = ?
+		GROUP BY channel_id, model`
+
+	rows, err := s.QueryContext(ctx, query, start.UnixMilli(), end.UnixMilli(), model.LogSourceProxy)
+	if err != nil {
+		return err
+	}
+	stats := make([]model.DailyStat, 0)
+	for rows.Next() {
+		var st model.DailyStat
+		if err := rows.Scan(&st.ChannelID, &st.Model, &st.SuccessCount, &st.ErrorCount,
+			&st.InputTokens, &st.OutputTokens, &st.CacheReadInputTokens, &st.CacheCreationInputTokens, &st.Cost); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		stats = append(stats, st)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	_ = rows.Close()
+
+	now := time.Now().UnixMilli()
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := s.execTx(ctx, tx, `DELETE FROM stats_daily WHERE day = ?`, dayStr); err != nil {
+			return err
+		}
+		for _, st := range stats {
+			if _, err := s.execTx(ctx, tx, `
+				INSERT INTO stats_daily (day, channel_id, model, success_count, error_count,
+					input_tokens, output_tokens, cache_read_input_tokens, cache_creation_input_tokens, cost, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				dayStr, st.ChannelID, st.Model, st.SuccessCount, st.ErrorCount,
+				st.InputTokens, st.OutputTokens, st.CacheReadInputTokens, st.CacheCreationInputTokens, st.Cost, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddDailyStats 将实时用量增量累加到 stats_daily（按 day+channel_id+model 合并）
+// 计数在日志采样之前累加，采样丢弃的成功日志同样计入
+func (s *SQLStore) AddDailyStats(ctx context.Context, deltas []model.DailyStat) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO stats_daily (day, channel_id, model, success_count, error_count,
+			input_tokens, output_tokens, cache_read_input_tokens, cache_creation_input_tokens, cost, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(day, channel_id, model) DO UPDATE SET
+			success_count = stats_daily.success_count + excluded.success_count,
+			error_count = stats_daily.error_count + excluded.error_count,
+			input_tokens = stats_daily.input_tokens + excluded.input_tokens,
+			output_tokens = stats_daily.output_tokens + excluded.output_tokens,
+			cache_read_input_tokens = stats_daily.cache_read_input_tokens + excluded.cache_read_input_tokens,
+			cache_creation_input_tokens = stats_daily.cache_creation_input_tokens + excluded.cache_creation_input_tokens,
+			cost = stats_daily.cost + excluded.cost,
+			updated_at = excluded.updated_at`
+	if s.IsMySQL() {
+		query = `
+		INSERT INTO stats_daily (day, channel_id, model, success_count, error_count,
+			input_tokens, output_tokens, cache_read_input_tokens, cache_creation_input_tokens, cost, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			success_count = success_count + VALUES(success_count),
+			error_count = error_count + VALUES(error_count),
+			input_tokens = input_tokens + VALUES(input_tokens),
+			output_tokens = output_tokens + VALUES(output_tokens),
+			cache_read_input_tokens = cache_read_input_tokens + VALUES(cache_read_input_tokens),
+			cache_creation_input_tokens = cache_creation_input_tokens + VALUES(cache_creation_input_tokens),
+			cost = cost + VALUES(cost),
+			updated_at = VALUES(updated_at)`
+	}
+
+	now := time.Now().UnixMilli()
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		for _, st := range deltas {
+			if _, err := s.execTx(ctx, tx, query,
+				st.Day, st.ChannelID, st.Model, st.SuccessCount, st.ErrorCount,
+				st.InputTokens, st.OutputTokens, st.CacheReadInputTokens, st.CacheCreationInputTokens, st.Cost, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListMissingDailyStatDays 返回 [since, until) 内有代理日志但没有 stats_daily 行的本地自然日（按日期升序）
+// 起点早于 since 的自然日不返回（其日志可能已被部分清理）；since 为零值时从最早的日志开始
+func (s *SQLStore) ListMissingDailyStatDays(ctx context.Context, since, until time.Time) ([]time.Time, error) {
+	var minTime sql.NullInt64
+	if err := s.QueryRowContext(ctx, `
+		SELECT MIN(time) FROM logs
+		WHERE time >= ? AND time < ? AND channel_id > 0 AND log_source = ?`,
+		since.UnixMilli(), until.UnixMilli(), model.LogSourceProxy).Scan(&minTime); err != nil {
+		return nil, err
+	}
+	if !minTime.Valid {
+		return nil, nil
+	}
+	first := time.UnixMilli(minTime.Int64).In(until.Location())
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	if day.Before(since) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	rows, err := s.QueryContext(ctx, `SELECT DISTINCT day FROM stats_daily WHERE day >= ?`, day.Format(statsDayLayout))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		existing[d] = true
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	_ = rows.Close()
+
+	var missing []time.Time
+	for ; day.Before(until); day = day.AddDate(0, 0, 1) {
+		if existing[day.Format(statsDayLayout)] {
+			continue
+		}
+		var one int
+		err := s.QueryRowContext(ctx, `
+			SELECT 1 FROM logs
+			WHERE time >= ? AND time < ? AND channel_id > 0 AND log_source = ?
+			LIMIT 1`,
+			day.UnixMilli(), day.AddDate(0, 0, 1).UnixMilli(), model.LogSourceProxy).Scan(&one)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, day)
+	}
+	return missing, nil
+}
+
+// ListDailyStats 读取 sinceDay（含，格式 YYYY-MM-DD）起的每日汇总，按日期、渠道、模型升序
+func (s *SQLStore) ListDailyStats(ctx context.Context, sinceDay string) ([]model.DailyStat, error) {
+	query := `
+		SELECT day, channel_id, model, success_count, error_count,
+			input_tokens, output_tokens, cache_read_input_tokens, cache_creation_input_tokens, cost
+		FROM stats_daily
+		WHERE day >= ?
+		ORDER BY day ASC, channel_id ASC, model ASC`
+
+	rows, err := s.QueryContext(ctx, query, sinceDay)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make([]model.DailyStat, 0)
+	for rows.Next() {
+		var st model.DailyStat
+		if err := rows.Scan(&st.Day, &st.ChannelID, &st.Model, &st.SuccessCount, &st.ErrorCount,
+			&st.InputTokens, &st.OutputTokens, &st.CacheReadInputTokens, &st.CacheCreationInputTokens, &st.Cost); err != nil {
+			return nil, err
+		}
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,119 @@
+package sql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ccLoad/internal/model"
+)
+
+func TestRollupDailyStats_AggregatesDayAndIsIdempotent(t *testing.T) {
+	store := newTestStore(t, "stats_daily.db")
+	ctx := context.Background()
+
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	noon := day.Add(12 * time.Hour)
+	entries := []*model.LogEntry{
+		{Time: model.JSONTime{Time: noon}, Model: "m", ChannelID: 1, StatusCode: 200, InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 3, Cost: 0.5, CostMultiplier: 2},
+		{Time: model.JSONTime{Time: noon}, Model: "m", ChannelID: 1, StatusCode: 200, InputTokens: 20, OutputTokens: 7, Cost: 0.25, CostMultiplier: 1},
+		{Time: model.JSONTime{Time: noon}, Model: "m", ChannelID: 1, StatusCode: 502},
+		{Time: model.JSONTime{Time: noon}, Model: "m", ChannelID: 1, StatusCode: 499},
+		{Time: model.JSONTime{Time: noon}, Model: "x", ChannelID: 2, StatusCode: 200, InputTokens: 1},
+		{Time: model.JSONTime{Time: noon}, Model: "m", ChannelID: 1, StatusCode: 200, LogSource: model.LogSourceScheduledCheck},
+		{Time: model.JSONTime{Time: day.Add(-time.Minute)}, Model: "m", ChannelID: 1, StatusCode: 200},
+		{Time: model.JSONTime{Time: day.AddDate(0, 0, 1)}, Model: "m", ChannelID: 1, StatusCode: 200},
+	}
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+
+	for range 2 {
+		if err := store.RollupDailyStats(ctx, noon); err != nil {
+			t.Fatalf("RollupDailyStats: %v", err)
+		}
+	}
+
+	got, err := store.ListDailyStats(ctx, day.Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("ListDailyStats: %v", err)
+	}
+	dayStr := day.Format("2006-01-02")
+	want := []model.DailyStat{
+		{Day: dayStr, ChannelID: 1, Model: "m", SuccessCount: 2, ErrorCount: 1, InputTokens: 30, OutputTokens: 12, CacheReadInputTokens: 3, Cost: 1.25},
+		{Day: dayStr, ChannelID: 2, Model: "x", SuccessCount: 1, InputTokens: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("row[%d]=%+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// 日志被清理后，已汇总的数据仍然保留
+	if err := store.CleanupLogsBefore(ctx, now); err != nil {
+		t.Fatalf("CleanupLogsBefore: %v", err)
+	}
+	got, err = store.ListDailyStats(ctx, dayStr)
+	if err != nil {
+		t.Fatalf("ListDailyStats after cleanup: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("after cleanup got %+v, want %+v", got, want)
+	}
+
+	if later, err := store.ListDailyStats(ctx, day.AddDate(0, 0, 1).Format("2006-01-02")); err != nil || len(later) != 0 {
+		t.Fatalf("ListDailyStats(since tomorrow)=%+v, err=%v", later, err)
+	}
+}
+
+func TestListMissingDailyStatDays_FindsMultiDayGap(t *testing.T) {
+	store := newTestStore(t, "stats_daily_gap.db")
+	ctx := context.Background()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayAt := func(offset int) time.Time { return today.AddDate(0, 0, offset).Add(12 * time.Hour) }
+	var entries []*model.LogEntry
+	for _, offset := range []int{-6, -5, -3, -2, 0} {
+		entries = append(entries, &model.LogEntry{Time: model.JSONTime{Time: dayAt(offset)}, Model: "m", ChannelID: 1, StatusCode: 200})
+	}
+	// 非代理日志不构成缺失
+	entries = append(entries, &model.LogEntry{Time: model.JSONTime{Time: dayAt(-4)}, Model: "m", ChannelID: 1, StatusCode: 200, LogSource: model.LogSourceManualTest})
+	if err := store.BatchAddLogs(ctx, entries); err != nil {
+		t.Fatalf("BatchAddLogs: %v", err)
+	}
+	if err := store.AddDailyStats(ctx, []model.DailyStat{{Day: dayAt(-3).Format("2006-01-02"), ChannelID: 1, Model: "m", SuccessCount: 1}}); err != nil {
+		t.Fatalf("AddDailyStats: %v", err)
+	}
+
+	days, err := store.ListMissingDailyStatDays(ctx, time.Time{}, today)
+	if err != nil {
+		t.Fatalf("ListMissingDailyStatDays: %v", err)
+	}
+	var got []string
+	for _, d := range days {
+		got = append(got, d.Format("2006-01-02"))
+	}
+	want := []string{dayAt(-6).Format("2006-01-02"), dayAt(-5).Format("2006-01-02"), dayAt(-2).Format("2006-01-02")}
+	if len(got) != len(want) {
+		t.Fatalf("missing=%v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("missing=%v, want %v", got, want)
+		}
+	}
+
+	// since 落在自然日中间：该日日志可能已被部分清理，不回填
+	days, err = store.ListMissingDailyStatDays(ctx, dayAt(-6), today)
+	if err != nil {
+		t.Fatalf("ListMissingDailyStatDays(since): %v", err)
+	}
+	if len(days) != 2 || days[0].Format("2006-01-02") != want[1] {
+		t.Fatalf("missing since=%v, want %v", days, want[1:])
+	}
+}
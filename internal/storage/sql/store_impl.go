@@ -258,7 +258,13 @@ func (s *SQLStore) QueryRowContext(ctx context.Context, query string, args ...an
 
 // ExecContext 执行非查询语句
 func (s *SQLStore) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return s.db.ExecContext(ctx, s.q(query), args...)
+	if !s.IsSQLite() {
+		return s.db.ExecContext(ctx, s.q(query), args...)
+	}
+	// SQLite 高并发写入即使有 busy_timeout 仍可能返回 database is locked，短暂退避后重试
+	return execWithRetry(ctx, defaultExecRetryPolicy, func() (sql.Result, error) {
+		return s.db.ExecContext(ctx, s.q(query), args...)
+	})
 }
 
 // BeginTx 开启事务
@@ -66,7 +66,34 @@ func withTransactionWithRetryPolicy(ctx context.Context, db *sql.DB, fn func(*sq
 	//
 	// 注意：实际等待时间有 50%-99.5% 的随机抖动，避免惊群效应
 	// 注意：如果 context 有 deadline，会在到达 deadline 时提前退出
+	return retryOnSQLiteBusy(ctx, "transaction", policy, func() error {
+		return executeSingleTransaction(ctx, db, fn)
+	})
+}
+
+// defaultExecRetryPolicy 单条写语句的 BUSY/LOCKED 重试策略（比事务更短：5 次，约 10ms 起步）
+// 单条语句失败时未生效，重试是安全的
+var defaultExecRetryPolicy = transactionRetryPolicy{
+	maxRetries: 5,
+	delay: func(attempt int) time.Duration {
+		return calculateBackoffDelay(attempt, 10*time.Millisecond)
+	},
+}
+
+// execWithRetry 执行单条写语句，遇到 SQLite BUSY/LOCKED 错误时短暂退避后重试
+func execWithRetry(ctx context.Context, policy transactionRetryPolicy, exec func() (sql.Result, error)) (sql.Result, error) {
+	var res sql.Result
+	err := retryOnSQLiteBusy(ctx, "exec", policy, func() error {
+		var err error
+		res, err = exec()
+		return err
+	})
+	return res, err
+}
 
+// retryOnSQLiteBusy 执行 op，遇到 BUSY/LOCKED 错误时按 policy 指数退避重试
+// label 用于错误信息（transaction / exec）
+func retryOnSQLiteBusy(ctx context.Context, label string, policy transactionRetryPolicy, op func() error) error {
 	// 检查 context 是否有 deadline（用于限制总重试时间）
 	deadline, hasDeadline := ctx.Deadline()
 
@@ -80,7 +107,7 @@ func withTransactionWithRetryPolicy(ctx context.Context, db *sql.DB, fn func(*sq
 	}
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		err := executeSingleTransaction(ctx, db, fn)
+		err := op()
 
 		// 成功或非BUSY错误,立即返回
 		if err == nil || !isSQLiteBusyError(err) {
@@ -96,7 +123,7 @@ func withTransactionWithRetryPolicy(ctx context.Context, db *sql.DB, fn func(*sq
 			if hasDeadline {
 				// 预估下次重试后是否会超过 deadline
 				if time.Now().Add(nextDelay).After(deadline) {
-					return fmt.Errorf("transaction aborted: context deadline would be exceeded (attempted %d retries): %w", attempt+1, err)
+					return fmt.Errorf("%s aborted: context deadline would be exceeded (attempted %d retries): %w", label, attempt+1, err)
 				}
 			}
 
@@ -107,7 +134,7 @@ func withTransactionWithRetryPolicy(ctx context.Context, db *sql.DB, fn func(*sq
 				if !timer.Stop() {
 					<-timer.C
 				}
-				return fmt.Errorf("transaction cancelled after %d retries: %w", attempt+1, ctx.Err())
+				return fmt.Errorf("%s cancelled after %d retries: %w", label, attempt+1, ctx.Err())
 			case <-timer.C:
 				// 等待完成，继续重试
 			}
@@ -115,7 +142,7 @@ func withTransactionWithRetryPolicy(ctx context.Context, db *sql.DB, fn func(*sq
 		}
 
 		// 所有重试都失败
-		return fmt.Errorf("transaction failed after %d retries: %w", maxRetries, err)
+		return fmt.Errorf("%s failed after %d retries: %w", label, maxRetries, err)
 	}
 
 	return fmt.Errorf("unexpected: retry loop exited without result")
@@ -201,3 +201,39 @@ func TestWithTransaction_DefaultRetryPolicyUsesExponentialBackoff(t *testing.T)
 		})
 	}
 }
+
+// TestExecWithRetry 单条写语句遇到 BUSY/LOCKED 时重试，其他错误立即返回
+func TestExecWithRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	policy := transactionRetryPolicy{maxRetries: 5, delay: func(int) time.Duration { return 0 }}
+
+	attempts := 0
+	if _, err := execWithRetry(ctx, policy, func() (sql.Result, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("database is locked (5) (SQLITE_BUSY)")
+		}
+		return nil, nil
+	}); err != nil || attempts != 3 {
+		t.Fatalf("locked then success: err=%v attempts=%d, want nil/3", err, attempts)
+	}
+
+	attempts = 0
+	if _, err := execWithRetry(ctx, policy, func() (sql.Result, error) {
+		attempts++
+		return nil, errors.New("UNIQUE constraint failed")
+	}); err == nil || attempts != 1 {
+		t.Fatalf("non-busy error: err=%v attempts=%d, want error/1", err, attempts)
+	}
+
+	attempts = 0
+	_, err := execWithRetry(ctx, policy, func() (sql.Result, error) {
+		attempts++
+		return nil, errors.New("database is locked")
+	})
+	if err == nil || attempts != 5 || !isSQLiteBusyError(err) {
+		t.Fatalf("always locked: err=%v attempts=%d, want wrapped busy error/5", err, attempts)
+	}
+}
@@ -20,6 +20,9 @@ type Store interface {
 	CreateConfig(ctx context.Context, c *model.Config) (*model.Config, error)
 	UpdateConfig(ctx context.Context, id int64, upd *model.Config) (*model.Config, error)
 	UpdateChannelEnabled(ctx context.Context, id int64, enabled bool) (*model.Config, error)
+	UpdateChannelMaintenance(ctx context.Context, id int64, maintenance bool) (*model.Config, error)
+	AddChannelModels(ctx context.Context, channelID int64, entries []model.ModelEntry) (*model.Config, error)
+	RemoveChannelModels(ctx context.Context, channelID int64, models []string) (*model.Config, error)
 	DeleteConfig(ctx context.Context, id int64) error
 	GetEnabledChannelsByModel(ctx context.Context, modelName string) ([]*model.Config, error)
 	GetEnabledChannelsByModelAndProtocol(ctx context.Context, modelName, protocol string) ([]*model.Config, error)
@@ -29,6 +32,7 @@ type Store interface {
 		ID       int64
 		Priority int
 	}) (int64, error)
+	UpdateChannelsLastUsed(ctx context.Context, lastUsed map[int64]int64) error
 
 	// === Channel URL Runtime State ===
 	// 持久化URL级运行态（当前仅记录手动禁用），重启后由URLSelector回填
@@ -43,10 +47,14 @@ type Store interface {
 	CreateAPIKeysBatch(ctx context.Context, keys []*model.APIKey) error
 	UpdateAPIKeysStrategy(ctx context.Context, channelID int64, strategy string) error
 	UpdateAPIKeyNotes(ctx context.Context, channelID int64, notesByIndex map[int]string) error
+	UpdateAPIKeyGroups(ctx context.Context, channelID int64, groupsByIndex map[int]string) error
+	UpdateAPIKeyRPMLimits(ctx context.Context, channelID int64, limitsByIndex map[int]int) error
+	UpdateAPIKeysLastUsed(ctx context.Context, updates []model.KeyLastUsed) error
 	SetAPIKeyDisabled(ctx context.Context, channelID int64, keyIndex int, disabled bool) error
 	DeleteAPIKey(ctx context.Context, channelID int64, keyIndex int) error
 	CompactKeyIndices(ctx context.Context, channelID int64, removedIndex int) error
 	DeleteAllAPIKeys(ctx context.Context, channelID int64) error
+	ReplaceAPIKeys(ctx context.Context, channelID int64, keys []*model.APIKey) error
 
 	// === Cooldown Management ===
 	// Channel-level cooldown
@@ -55,6 +63,7 @@ type Store interface {
 	ResetChannelCooldown(ctx context.Context, channelID int64) error
 	ResetAllCooldowns(ctx context.Context, channelID int64) error
 	SetChannelCooldown(ctx context.Context, channelID int64, until time.Time) error
+	OpenChannelCircuit(ctx context.Context, channelID int64, threshold int, until time.Time) (bool, error)
 	// Key-level cooldown
 	GetAllKeyCooldowns(ctx context.Context) (map[int64]map[int]time.Time, error)
 	BumpKeyCooldown(ctx context.Context, channelID int64, keyIndex int, now time.Time, statusCode int) (time.Duration, error)
@@ -71,6 +80,8 @@ type Store interface {
 	ListLogs(ctx context.Context, since time.Time, limit, offset int, filter *model.LogFilter) ([]*model.LogEntry, error)
 	ListLogsRange(ctx context.Context, since, until time.Time, limit, offset int, filter *model.LogFilter) ([]*model.LogEntry, error)
 	ListLogsRangeWithCount(ctx context.Context, since, until time.Time, limit, offset int, filter *model.LogFilter) ([]*model.LogEntry, int, error)
+	ListLogsByCursor(ctx context.Context, since, until time.Time, cursor *model.LogCursor, limit int, filter *model.LogFilter) ([]*model.LogEntry, error)
+	GetLog(ctx context.Context, id int64, full bool) (*model.LogEntry, error)
 	CountLogs(ctx context.Context, since time.Time, filter *model.LogFilter) (int, error)
 	CountLogsRange(ctx context.Context, since, until time.Time, filter *model.LogFilter) (int, error)
 	GetTodayChannelURLStats(ctx context.Context, dayStart time.Time) ([]model.ChannelURLLogStat, error)
@@ -91,7 +102,13 @@ type Store interface {
 	GetRPMStats(ctx context.Context, startTime, endTime time.Time, filter *model.LogFilter, isToday bool) (*model.RPMStats, error)
 	GetChannelSuccessRates(ctx context.Context, since time.Time) (map[int64]model.ChannelHealthStats, error)
 	GetHealthTimeline(ctx context.Context, params model.HealthTimelineParams) ([]model.HealthTimelineRow, error)
-	GetTodayChannelCosts(ctx context.Context, todayStart time.Time) (map[int64]float64, error) // 获取今日各渠道成本（启动时加载）
+	GetTodayChannelCosts(ctx context.Context, todayStart time.Time) (map[int64]float64, error) // 获取今日各渠道成本（读 stats_daily，启动时加载）
+	GetModelCosts(ctx context.Context, since time.Time) (map[string]float64, error)            // 获取 since 起各模型成本（读 stats_daily，模型月度预算）
+	GetErrorBreakdown(ctx context.Context, since time.Time, byChannel bool) ([]model.ErrorBreakdownEntry, error)
+	RollupDailyStats(ctx context.Context, day time.Time) error                                 // 汇总 day 所在自然日日志到 stats_daily
+	AddDailyStats(ctx context.Context, deltas []model.DailyStat) error                         // 累加实时用量计数到 stats_daily
+	ListMissingDailyStatDays(ctx context.Context, since, until time.Time) ([]time.Time, error) // [since, until) 内有代理日志但无 stats_daily 的自然日
+	ListDailyStats(ctx context.Context, sinceDay string) ([]model.DailyStat, error)
 
 	// === Auth Token Management ===
 	CreateAuthToken(ctx context.Context, token *model.AuthToken) error
@@ -133,6 +150,10 @@ type Store interface {
 	ListFingerprintTestResults(ctx context.Context, limit int) ([]*model.FingerprintTestRecord, error)
 	DeleteFingerprintTestResult(ctx context.Context, id int64) error
 
+	// === Audit Log ===
+	AddAuditLog(ctx context.Context, entry *model.AuditLog) error
+	ListAuditLogs(ctx context.Context, since time.Time, channelID int64, limit int) ([]*model.AuditLog, error) // channelID=0 不过滤
+
 	// === Batch Operations ===
 	ImportChannelBatch(ctx context.Context, channels []*model.ChannelWithKeys) (created, updated int, err error)
 
@@ -44,27 +44,10 @@ func (sm *SyncManager) RestoreOnStartup(ctx context.Context, logDays int) error
 	start := time.Now()
 
 	// 第一步：恢复配置表（快速，<1 秒）
-	configTables := []string{
-		"system_settings",
-		"channels",
-		"channel_models",
-		"channel_model_cooldowns",
-		"channel_protocol_transforms",
-		"api_keys",
-		"auth_tokens",
-		"model_fingerprints",
-		"fingerprint_test_results",
+	if err := sm.RestoreConfigTables(ctx); err != nil {
+		return err
 	}
 
-	log.Printf("[INFO] 开始恢复配置表（共 %d 个表）...", len(configTables))
-	for _, table := range configTables {
-		if err := sm.restoreTable(ctx, table); err != nil {
-			return fmt.Errorf("恢复表 %s 失败: %w", table, err)
-		}
-	}
-
-	log.Printf("[INFO] 配置表恢复完成，耗时: %v", time.Since(start))
-
 	// 第二步：恢复 logs 表（可选，按天数）
 	// logDays: -1=全量, 0=不恢复, >0=恢复指定天数
 	if logDays != 0 {
@@ -81,6 +64,32 @@ func (sm *SyncManager) RestoreOnStartup(ctx context.Context, logDays int) error
 	return nil
 }
 
+// configTables 需要从主库全量恢复到 SQLite 的配置表
+var configTables = []string{
+	"system_settings",
+	"channels",
+	"channel_models",
+	"channel_model_cooldowns",
+	"channel_protocol_transforms",
+	"api_keys",
+	"auth_tokens",
+	"model_fingerprints",
+	"fingerprint_test_results",
+}
+
+// RestoreConfigTables 全量恢复配置表（启动恢复与运行时手动重同步共用）
+func (sm *SyncManager) RestoreConfigTables(ctx context.Context) error {
+	start := time.Now()
+	log.Printf("[INFO] 开始恢复配置表（共 %d 个表）...", len(configTables))
+	for _, table := range configTables {
+		if err := sm.restoreTable(ctx, table); err != nil {
+			return fmt.Errorf("恢复表 %s 失败: %w", table, err)
+		}
+	}
+	log.Printf("[INFO] 配置表恢复完成，耗时: %v", time.Since(start))
+	return nil
+}
+
 // restoreTable 恢复单表（幂等，DELETE + INSERT）
 // 配置表数据量限制：最多 10000 行，超过则报错（防止内存溢出）
 //
@@ -171,11 +180,6 @@ func (sm *SyncManager) restoreTable(ctx context.Context, tableName string) error
 		return fmt.Errorf("读取数据失败: %w", err)
 	}
 
-	if len(records) == 0 {
-		log.Printf("[INFO] 表 %s 为空，跳过恢复", tableName)
-		return nil
-	}
-
 	// 7. 清空 + 插入必须在同一个事务里，保证原子性
 	tx, err := sm.sqlite.BeginTx(ctx, nil)
 	if err != nil {
@@ -188,6 +192,15 @@ func (sm *SyncManager) restoreTable(ctx context.Context, tableName string) error
 		return fmt.Errorf("清空 SQLite 表失败: %w", err)
 	}
 
+	// 主库为空也要清空本地缓存：运行时重同步不能残留主库已删除的行
+	if len(records) == 0 {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %w", err)
+		}
+		log.Printf("[INFO] 表 %s 为空，已清空本地缓存", tableName)
+		return nil
+	}
+
 	// 8. 批量插入 SQLite（显式指定列名）
 	// 构建 INSERT 语句（显式列名）
 	colNames := strings.Join(commonCols, ", ")
@@ -4,9 +4,22 @@ package util
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"log"
+	"os"
 	"strings"
+	"sync"
 )
 
+// 日志中 API Key 的脱敏方式（CCLOAD_KEY_MASK_MODE）
+const (
+	KeyMaskPartial = "partial" // 前3位 + . + 后3位（默认）
+	KeyMaskFull    = "full"    // 固定 ****，不保留任何明文
+	KeyMaskHash    = "hash"    // # + SHA256 前8位，可用于关联同一 key 而不暴露明文片段
+)
+
+// keyMaskHashLen hash 模式保留的 SHA256 十六进制前缀长度
+const keyMaskHashLen = 8
+
 // ParseAPIKeys 解析 API Key 字符串（支持逗号分隔的多个 Key）
 // 设计原则（DRY）：统一的Key解析逻辑，供多个模块复用
 func ParseAPIKeys(apiKey string) []string {
@@ -32,6 +45,71 @@ func MaskAPIKey(key string) string {
 	return key[:3] + "." + key[len(key)-3:]
 }
 
+// ParseKeyMaskMode 解析脱敏方式，空串或无效值返回 partial
+func ParseKeyMaskMode(raw string) string {
+	switch mode := strings.ToLower(strings.TrimSpace(raw)); mode {
+	case KeyMaskPartial, KeyMaskFull, KeyMaskHash:
+		return mode
+	case "":
+		return KeyMaskPartial
+	default:
+		log.Printf("[WARN] 无效的 CCLOAD_KEY_MASK_MODE=%s（可选 partial|full|hash），使用默认值 partial", raw)
+		return KeyMaskPartial
+	}
+}
+
+// keyMaskMode 延迟解析 CCLOAD_KEY_MASK_MODE（.env 加载后首次使用时读取）
+var keyMaskMode = sync.OnceValue(func() string {
+	return ParseKeyMaskMode(os.Getenv("CCLOAD_KEY_MASK_MODE"))
+})
+
+// MaskAPIKeyWithMode 按指定方式脱敏明文 API Key
+func MaskAPIKeyWithMode(key, mode string) string {
+	if key == "" {
+		return ""
+	}
+	switch mode {
+	case KeyMaskFull:
+		return "****"
+	case KeyMaskHash:
+		return "#" + HashAPIKey(key)[:keyMaskHashLen]
+	default:
+		return MaskAPIKey(key)
+	}
+}
+
+// MaskLogAPIKey 按 CCLOAD_KEY_MASK_MODE 脱敏写入日志的 API Key
+func MaskLogAPIKey(key string) string {
+	return MaskAPIKeyWithMode(key, keyMaskMode())
+}
+
+// MaskStoredAPIKeyWithMode 读取日志时再次脱敏已存储的值（兼容切换模式前写入的历史行）
+// hash 模式优先由 api_key_hash 列推导，与新写入行的短哈希保持一致；无哈希的历史行退化为 ****
+func MaskStoredAPIKeyWithMode(stored, keyHash, mode string) string {
+	if stored == "" {
+		return ""
+	}
+	switch mode {
+	case KeyMaskFull:
+		return "****"
+	case KeyMaskHash:
+		if len(keyHash) >= keyMaskHashLen {
+			return "#" + keyHash[:keyMaskHashLen]
+		}
+		if strings.HasPrefix(stored, "#") && len(stored) == keyMaskHashLen+1 {
+			return stored
+		}
+		return "****"
+	default:
+		return MaskAPIKey(stored)
+	}
+}
+
+// MaskStoredAPIKey 按 CCLOAD_KEY_MASK_MODE 脱敏从日志表读取的 API Key
+func MaskStoredAPIKey(stored, keyHash string) string {
+	return MaskStoredAPIKeyWithMode(stored, keyHash, keyMaskMode())
+}
+
 // HashAPIKey 计算API Key的SHA256哈希（十六进制字符串）
 // 用于日志中稳定标识 key，不存储明文。
 func HashAPIKey(key string) string {
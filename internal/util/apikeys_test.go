@@ -95,3 +95,67 @@ func TestHashAPIKey(t *testing.T) {
 		t.Fatalf("HashAPIKey(\"\") = %q, want empty string", got)
 	}
 }
+
+func TestParseKeyMaskMode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":        KeyMaskPartial,
+		"partial": KeyMaskPartial,
+		" FULL ":  KeyMaskFull,
+		"hash":    KeyMaskHash,
+		"bogus":   KeyMaskPartial,
+	}
+	for raw, want := range cases {
+		if got := ParseKeyMaskMode(raw); got != want {
+			t.Errorf("ParseKeyMaskMode(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestMaskAPIKeyWithMode(t *testing.T) {
+	t.Parallel()
+
+	const key = "sk-test-key"
+	tests := []struct {
+		mode     string
+		expected string
+	}{
+		{mode: KeyMaskPartial, expected: "sk-.key"},
+		{mode: KeyMaskFull, expected: "****"},
+		{mode: KeyMaskHash, expected: "#0d62f396"},
+	}
+	for _, tt := range tests {
+		if got := MaskAPIKeyWithMode(key, tt.mode); got != tt.expected {
+			t.Errorf("MaskAPIKeyWithMode(%q) = %q, want %q", tt.mode, got, tt.expected)
+		}
+		if got := MaskAPIKeyWithMode("", tt.mode); got != "" {
+			t.Errorf("%s: empty key should stay empty, got %q", tt.mode, got)
+		}
+	}
+}
+
+func TestMaskStoredAPIKeyWithMode(t *testing.T) {
+	t.Parallel()
+
+	keyHash := HashAPIKey("sk-test-key")
+	tests := []struct {
+		name     string
+		stored   string
+		hash     string
+		mode     string
+		expected string
+	}{
+		{name: "partial幂等", stored: "sk-.key", hash: keyHash, mode: KeyMaskPartial, expected: "sk-.key"},
+		{name: "full隐藏历史行", stored: "sk-.key", hash: keyHash, mode: KeyMaskFull, expected: "****"},
+		{name: "hash由哈希列推导", stored: "sk-.key", hash: keyHash, mode: KeyMaskHash, expected: "#0d62f396"},
+		{name: "hash保留已写入的短哈希", stored: "#0d62f396", mode: KeyMaskHash, expected: "#0d62f396"},
+		{name: "hash无哈希列的历史行", stored: "sk-.key", mode: KeyMaskHash, expected: "****"},
+		{name: "空值", stored: "", hash: keyHash, mode: KeyMaskFull, expected: ""},
+	}
+	for _, tt := range tests {
+		if got := MaskStoredAPIKeyWithMode(tt.stored, tt.hash, tt.mode); got != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -31,6 +31,11 @@ var ChannelTypes = []ChannelTypeConfig{
 		DisplayName: "Google Gemini",
 		Description: "Google Gemini API",
 	},
+	{
+		Value:       ChannelTypeAzure,
+		DisplayName: "Azure OpenAI",
+		Description: "Azure OpenAI（OpenAI协议，按部署名转发，api-key认证）",
+	},
 }
 
 // IsValidChannelType 验证渠道类型是否有效（替代models.go中的硬编码）
@@ -66,4 +71,27 @@ const (
 	ChannelTypeCodex     = "codex"
 	ChannelTypeOpenAI    = "openai"
 	ChannelTypeGemini    = "gemini"
+	ChannelTypeAzure     = "azure"
 )
+
+// ChannelTypeProtocol 返回渠道类型对应的上游协议（azure 使用 OpenAI 协议，其余类型与协议同名）
+func ChannelTypeProtocol(channelType string) string {
+	if channelType == ChannelTypeAzure {
+		return ChannelTypeOpenAI
+	}
+	return channelType
+}
+
+// ChannelTypesForProtocol 返回以 protocol 为原生协议的全部渠道类型
+func ChannelTypesForProtocol(protocol string) []string {
+	types := make([]string, 0, 2)
+	for _, ct := range ChannelTypes {
+		if ChannelTypeProtocol(ct.Value) == protocol {
+			types = append(types, ct.Value)
+		}
+	}
+	if len(types) == 0 {
+		types = append(types, protocol)
+	}
+	return types
+}
@@ -12,6 +12,7 @@ func TestChannelTypeConstants(t *testing.T) {
 		{ChannelTypeCodex, "codex"},
 		{ChannelTypeOpenAI, "openai"},
 		{ChannelTypeGemini, "gemini"},
+		{ChannelTypeAzure, "azure"},
 	}
 
 	for _, tt := range tests {
@@ -22,8 +23,8 @@ func TestChannelTypeConstants(t *testing.T) {
 }
 
 func TestChannelTypesConfiguration(t *testing.T) {
-	if len(ChannelTypes) != 4 {
-		t.Errorf("Expected 4 channel types, got %d", len(ChannelTypes))
+	if len(ChannelTypes) != 5 {
+		t.Errorf("Expected 5 channel types, got %d", len(ChannelTypes))
 	}
 
 	expectedValues := map[string]bool{
@@ -31,6 +32,7 @@ func TestChannelTypesConfiguration(t *testing.T) {
 		ChannelTypeCodex:     true,
 		ChannelTypeOpenAI:    true,
 		ChannelTypeGemini:    true,
+		ChannelTypeAzure:     true,
 	}
 
 	for _, ct := range ChannelTypes {
@@ -51,6 +53,20 @@ func TestChannelTypesConfiguration(t *testing.T) {
 	}
 }
 
+// TestChannelTypeProtocol azure 走 OpenAI 协议，其余类型与协议同名
+func TestChannelTypeProtocol(t *testing.T) {
+	if got := ChannelTypeProtocol(ChannelTypeAzure); got != ChannelTypeOpenAI {
+		t.Errorf("ChannelTypeProtocol(azure)=%q, want openai", got)
+	}
+	if got := ChannelTypeProtocol(ChannelTypeGemini); got != ChannelTypeGemini {
+		t.Errorf("ChannelTypeProtocol(gemini)=%q, want gemini", got)
+	}
+	got := ChannelTypesForProtocol(ChannelTypeOpenAI)
+	if len(got) != 2 || got[0] != ChannelTypeOpenAI || got[1] != ChannelTypeAzure {
+		t.Errorf("ChannelTypesForProtocol(openai)=%v, want [openai azure]", got)
+	}
+}
+
 // TestIsValidChannelType 测试渠道类型验证
 func TestIsValidChannelType(t *testing.T) {
 	tests := []struct {
@@ -23,6 +23,9 @@ var ErrUpstreamFirstByteTimeout = errors.New("upstream first byte timeout")
 // ErrUpstreamEmptyResponse 是上游 200 但无响应体的统一错误标识。
 var ErrUpstreamEmptyResponse = errors.New("upstream returned empty response")
 
+// ErrUpstreamDecompression 是上游 gzip 响应体解压失败（损坏或截断）的统一错误标识。
+var ErrUpstreamDecompression = errors.New("upstream response decompression failed")
+
 // resetTime1308Regex 匹配1308错误 message 中的重置时间（不依赖具体语言文案）
 // 格式示例: 2025-12-09 18:08:11
 var resetTime1308Regex = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}`)
@@ -910,6 +913,11 @@ func ClassifyError(err error) (statusCode int, errorLevel ErrorLevel, shouldRetr
 		return http.StatusBadGateway, ErrorLevelChannel, true
 	}
 
+	// 快速路径1.3：上游压缩响应体损坏，换渠道重试（仅在客户端尚未收到字节时由调用方返回此错误）
+	if errors.Is(err, ErrUpstreamDecompression) {
+		return http.StatusBadGateway, ErrorLevelChannel, true
+	}
+
 	// 快速路径1.5：协议转换明确声明为客户端请求结构不支持
 	if errors.Is(err, protocol.ErrUnsupportedRequestShape) {
 		return http.StatusBadRequest, ErrorLevelClient, false
@@ -333,6 +333,14 @@ func TestClassifyError_EmptyResponse(t *testing.T) {
 			expectedRetry:  true,
 			reason:         "无Content-Length的200空体也应视为上游故障，不能依赖错误文案",
 		},
+		{
+			name:           "decompression_sentinel",
+			err:            fmt.Errorf("%w: gzip: invalid header", ErrUpstreamDecompression),
+			expectedStatus: 502,
+			expectedLevel:  ErrorLevelChannel,
+			expectedRetry:  true,
+			reason:         "上游gzip响应体损坏应切换渠道",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// CustomModelPrice 自定义定价文件中的单个模型价格（单位：美元/百万tokens，与内置定价表一致）
+type CustomModelPrice struct {
+	Input      float64  `json:"input"`
+	Output     float64  `json:"output"`
+	CacheRead  *float64 `json:"cache_read,omitempty"`  // 缺省时按模型系列倍率回退计算
+	PerRequest float64  `json:"per_request,omitempty"` // 按次计费（token 成本为 0 时生效）
+}
+
+// customModelPricing 自定义定价覆盖（精确模型名，小写），优先于内置表与 models.dev 目录
+var customModelPricing atomic.Pointer[map[string]ModelPricing]
+
+// ParseCustomModelPricing 解析自定义定价 JSON：{"模型名": {"input": 3, "output": 15, "cache_read": 0.3}}
+func ParseCustomModelPricing(r io.Reader) (map[string]ModelPricing, error) {
+	var raw map[string]CustomModelPrice
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode custom pricing: %w", err)
+	}
+	pricing := make(map[string]ModelPricing, len(raw))
+	for model, price := range raw {
+		id := strings.ToLower(strings.TrimSpace(model))
+		if id == "" {
+			return nil, fmt.Errorf("custom pricing contains empty model name")
+		}
+		if _, dup := pricing[id]; dup {
+			return nil, fmt.Errorf("duplicate custom pricing model %q", model)
+		}
+		if !validCustomPrice(price.Input) || !validCustomPrice(price.Output) || !validCustomPrice(price.PerRequest) ||
+			(price.CacheRead != nil && !validCustomPrice(*price.CacheRead)) {
+			return nil, fmt.Errorf("custom pricing model %q has invalid price", model)
+		}
+		entry := ModelPricing{
+			InputPrice:          price.Input,
+			OutputPrice:         price.Output,
+			FixedCostPerRequest: price.PerRequest,
+		}
+		if price.CacheRead != nil {
+			entry.CacheReadPrice = *price.CacheRead
+			entry.HasCacheReadPrice = true
+		}
+		pricing[id] = entry
+	}
+	return pricing, nil
+}
+
+func validCustomPrice(v float64) bool {
+	return v >= 0 && !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// SetCustomModelPricing 原子替换自定义定价覆盖；nil 或空表表示清除
+func SetCustomModelPricing(pricing map[string]ModelPricing) {
+	if len(pricing) == 0 {
+		customModelPricing.Store(nil)
+		return
+	}
+	customModelPricing.Store(&pricing)
+}
+
+// lookupCustomPricing 查询自定义定价（model 已小写）
+func lookupCustomPricing(model string) (ModelPricing, bool) {
+	table := customModelPricing.Load()
+	if table == nil {
+		return ModelPricing{}, false
+	}
+	pricing, ok := (*table)[model]
+	return pricing, ok
+}
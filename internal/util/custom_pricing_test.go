@@ -0,0 +1,61 @@
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseCustomModelPricing(t *testing.T) {
+	t.Parallel()
+
+	pricing, err := ParseCustomModelPricing(strings.NewReader(`{
+		" My-Private-Model ": {"input": 2, "output": 8, "cache_read": 0.5},
+		"image-bot": {"input": 0, "output": 0, "per_request": 0.04}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseCustomModelPricing: %v", err)
+	}
+	got := pricing["my-private-model"]
+	if got.InputPrice != 2 || got.OutputPrice != 8 || !got.HasCacheReadPrice || got.CacheReadPrice != 0.5 {
+		t.Fatalf("my-private-model = %+v", got)
+	}
+	if got := pricing["image-bot"]; got.FixedCostPerRequest != 0.04 || got.HasCacheReadPrice {
+		t.Fatalf("image-bot = %+v", got)
+	}
+
+	for name, raw := range map[string]string{
+		"负价格":    `{"m": {"input": -1, "output": 1}}`,
+		"空模型名":   `{" ": {"input": 1, "output": 1}}`,
+		"重复模型":   `{"M": {"input": 1, "output": 1}, "m": {"input": 2, "output": 2}}`,
+		"未知字段":   `{"m": {"inputs": 1}}`,
+		"非法JSON": `[1,2]`,
+	} {
+		if _, err := ParseCustomModelPricing(strings.NewReader(raw)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCustomModelPricing_UsedForCost(t *testing.T) {
+	const model = "ccload-custom-pricing-test-model"
+	if HasModelPricing(model) {
+		t.Fatalf("%s should not have built-in pricing", model)
+	}
+
+	SetCustomModelPricing(map[string]ModelPricing{model: {InputPrice: 2, OutputPrice: 8}})
+	t.Cleanup(func() { SetCustomModelPricing(nil) })
+
+	if !HasModelPricing(strings.ToUpper(model)) {
+		t.Fatal("custom pricing should be resolvable case-insensitively")
+	}
+	// 1M 输入 × $2 + 0.5M 输出 × $8 = $6
+	if cost := CalculateCostDetailed(model, 1_000_000, 500_000, 0, 0, 0); math.Abs(cost-6) > 1e-9 {
+		t.Fatalf("cost = %v, want 6", cost)
+	}
+
+	SetCustomModelPricing(nil)
+	if HasModelPricing(model) {
+		t.Fatal("clearing custom pricing should remove the model")
+	}
+}
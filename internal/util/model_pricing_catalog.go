@@ -847,7 +847,7 @@ func buildPrefixBuckets(pricing map[string]ModelPricing, aliases map[string]stri
 	return buckets
 }
 
-// getPricing 获取模型定价（先查别名再查基础表）。
+// getPricing 获取模型定价（先查自定义定价，再查别名与基础表）。
 // 支持 Ollama/vLLM 风格 size tag：gpt-oss:120b → gpt-oss-120b（精确冒号条目如 gpt-oss-120b:exacto 优先保留）。
 func getPricing(model string) (ModelPricing, bool) {
 	snapshot := activeModelPricing.Load()
@@ -858,6 +858,10 @@ func getPricing(model string) (ModelPricing, bool) {
 	if model == "" {
 		return ModelPricing{}, false
 	}
+	// 自定义定价（CCLOAD_PRICING_FILE）优先：覆盖内置价格或补充私有/自建模型
+	if pricing, ok := lookupCustomPricing(model); ok {
+		return pricing, true
+	}
 	if pricing, ok := lookupPricingInSnapshot(snapshot, model); ok {
 		return pricing, true
 	}
@@ -0,0 +1,12 @@
+package util
+
+import "strings"
+
+// MatchPrefixPattern 判断 name 是否匹配 pattern（不区分大小写）
+// pattern 以 "*" 结尾时按前缀匹配（"*" 单独出现匹配任意值），否则精确匹配
+func MatchPrefixPattern(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix)
+	}
+	return strings.EqualFold(pattern, name)
+}
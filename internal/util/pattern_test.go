@@ -0,0 +1,29 @@
+package util
+
+import "testing"
+
+func TestMatchPrefixPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"gpt-4o", "gpt-4o", true},
+		{"gpt-4o", "GPT-4O", true},
+		{"gpt-4o", "gpt-4o-mini", false},
+		{"claude-*", "claude-sonnet-4", true},
+		{"claude-*", "Claude-Opus", true},
+		{"claude-*", "claude", false},
+		{"X-Debug-*", "x-debug-trace", true},
+		{"*", "anything", true},
+		{"*", "", true},
+		{"", "", true},
+		{"", "x", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPrefixPattern(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchPrefixPattern(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
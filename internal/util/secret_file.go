@@ -0,0 +1,176 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIKeyFilePrefix 文件引用型 API Key 前缀（如 file:/run/secrets/key1）
+// 数据库只保存文件路径，明文 Key 在选 Key 时从挂载的密钥文件读取
+const APIKeyFilePrefix = "file:"
+
+// secretFileRecheckInterval 缓存命中后重新 stat 文件的最小间隔
+// 文件 mtime/size 变化即视为轮换，重新读取
+const secretFileRecheckInterval = 5 * time.Second
+
+// secretFileMaxBytes 密钥文件大小上限（防止误指向大文件）
+const secretFileMaxBytes = 64 * 1024
+
+// IsAPIKeyFileRef 判断 Key 是否为文件引用
+func IsAPIKeyFileRef(key string) bool {
+	return strings.HasPrefix(key, APIKeyFilePrefix)
+}
+
+// normalizeSecretsDir 将密钥目录规范化为绝对路径（非绝对路径视为未配置）
+func normalizeSecretsDir(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || !filepath.IsAbs(raw) {
+		return ""
+	}
+	return filepath.Clean(raw)
+}
+
+// ValidateAPIKeyFileRef 校验文件引用：必须为绝对路径且位于 CCLOAD_SECRETS_DIR 内（不检查文件是否存在）
+func ValidateAPIKeyFileRef(key string) error {
+	_, err := cleanAPIKeyFileRef(secretFileCache().dir, key)
+	return err
+}
+
+// cleanAPIKeyFileRef 返回清理后的引用路径；dir 为空或路径不在 dir 内时报错
+func cleanAPIKeyFileRef(dir, key string) (string, error) {
+	path := strings.TrimPrefix(key, APIKeyFilePrefix)
+	if path == "" || !filepath.IsAbs(path) {
+		return "", fmt.Errorf("api key file reference %q must use an absolute path", key)
+	}
+	if dir == "" {
+		return "", fmt.Errorf("api key file references are disabled (set CCLOAD_SECRETS_DIR)")
+	}
+	path = filepath.Clean(path)
+	if !pathWithinDir(dir, path) {
+		return "", fmt.Errorf("api key file reference %q is outside CCLOAD_SECRETS_DIR", key)
+	}
+	return path, nil
+}
+
+// pathWithinDir 判断 path 是否位于 dir 之下（不含 dir 本身；两者均须已 Clean）
+func pathWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." {
+		return false
+	}
+	return !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+type secretFileEntry struct {
+	value     string
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+}
+
+// SecretFileCache 密钥文件读取缓存
+// 每个路径缓存一次读取结果，超过 recheck 间隔后 stat 文件，mtime/size 变化时失效重读
+type SecretFileCache struct {
+	mu      sync.Mutex
+	entries map[string]*secretFileEntry
+	recheck time.Duration
+	dir     string // 允许读取的密钥目录（已 Clean），空=拒绝所有文件引用
+}
+
+// NewSecretFileCache 创建密钥文件缓存，只允许读取 dir 内的文件
+func NewSecretFileCache(dir string, recheck time.Duration) *SecretFileCache {
+	return &SecretFileCache{
+		entries: make(map[string]*secretFileEntry),
+		recheck: recheck,
+		dir:     normalizeSecretsDir(dir),
+	}
+}
+
+// Resolve 解析 API Key：非文件引用原样返回，文件引用返回文件内容（去除首尾空白）
+// 解析符号链接后目标仍须位于密钥目录内，防止借软链接读取任意文件
+func (c *SecretFileCache) Resolve(key string) (string, error) {
+	if !IsAPIKeyFileRef(key) {
+		return key, nil
+	}
+	path, err := cleanAPIKeyFileRef(c.dir, key)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if ok && now.Sub(entry.checkedAt) < c.recheck {
+		return entry.value, nil
+	}
+
+	realPath, err := c.resolveRealPath(path)
+	if err != nil {
+		delete(c.entries, path)
+		return "", err
+	}
+	info, err := os.Stat(realPath)
+	if err != nil {
+		delete(c.entries, path)
+		return "", fmt.Errorf("read api key file %s: %w", path, err)
+	}
+	if ok && info.ModTime().Equal(entry.modTime) && info.Size() == entry.size {
+		entry.checkedAt = now
+		return entry.value, nil
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("api key file %s is not a regular file", path)
+	}
+	if info.Size() > secretFileMaxBytes {
+		return "", fmt.Errorf("api key file %s is too large (%d bytes)", path, info.Size())
+	}
+
+	data, err := os.ReadFile(realPath) //nolint:gosec // G304: 路径已限制在 CCLOAD_SECRETS_DIR 内
+	if err != nil {
+		delete(c.entries, path)
+		return "", fmt.Errorf("read api key file %s: %w", path, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("api key file %s is empty", path)
+	}
+	c.entries[path] = &secretFileEntry{
+		value:     value,
+		modTime:   info.ModTime(),
+		size:      info.Size(),
+		checkedAt: now,
+	}
+	return value, nil
+}
+
+// resolveRealPath 解析符号链接，并确认真实路径仍在（同样解析过符号链接的）密钥目录内
+func (c *SecretFileCache) resolveRealPath(path string) (string, error) {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("read api key file %s: %w", path, err)
+	}
+	realDir, err := filepath.EvalSymlinks(c.dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve CCLOAD_SECRETS_DIR %s: %w", c.dir, err)
+	}
+	if !pathWithinDir(realDir, realPath) {
+		return "", fmt.Errorf("api key file %s resolves outside CCLOAD_SECRETS_DIR", path)
+	}
+	return realPath, nil
+}
+
+// secretFileCache 进程级密钥文件缓存（目录取自 CCLOAD_SECRETS_DIR，首次使用时读取）
+var secretFileCache = sync.OnceValue(func() *SecretFileCache {
+	return NewSecretFileCache(os.Getenv("CCLOAD_SECRETS_DIR"), secretFileRecheckInterval)
+})
+
+// ResolveAPIKey 使用进程级缓存解析 API Key（非文件引用原样返回）
+func ResolveAPIKey(key string) (string, error) {
+	return secretFileCache().Resolve(key)
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSecretFileCache_Resolve(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key1")
+	if err := os.WriteFile(path, []byte("sk-from-file\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cache := NewSecretFileCache(dir, 0)
+	if got, err := cache.Resolve("sk-plain"); err != nil || got != "sk-plain" {
+		t.Fatalf("plain key: got=%q err=%v", got, err)
+	}
+	got, err := cache.Resolve(APIKeyFilePrefix + path)
+	if err != nil || got != "sk-from-file" {
+		t.Fatalf("file key: got=%q err=%v", got, err)
+	}
+
+	// 轮换：内容与 mtime 变化后重新读取
+	if err := os.WriteFile(path, []byte("sk-rotated-key"), 0o600); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got, err := cache.Resolve(APIKeyFilePrefix + path); err != nil || got != "sk-rotated-key" {
+		t.Fatalf("rotated key: got=%q err=%v", got, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := cache.Resolve(APIKeyFilePrefix + path); err == nil {
+		t.Fatal("expected error for removed file")
+	}
+}
+
+func TestSecretFileCache_CachesWithinRecheckInterval(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key1")
+	if err := os.WriteFile(path, []byte("sk-first"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cache := NewSecretFileCache(dir, time.Hour)
+	if _, err := cache.Resolve(APIKeyFilePrefix + path); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if got, err := cache.Resolve(APIKeyFilePrefix + path); err != nil || got != "sk-first" {
+		t.Fatalf("cached key: got=%q err=%v", got, err)
+	}
+}
+
+func TestValidateAPIKeyFileRef(t *testing.T) {
+	orig := secretFileCache
+	t.Cleanup(func() { secretFileCache = orig })
+	setSecretsDir := func(dir string) {
+		cache := NewSecretFileCache(dir, secretFileRecheckInterval)
+		secretFileCache = func() *SecretFileCache { return cache }
+	}
+
+	// 未配置密钥目录：拒绝所有文件引用
+	setSecretsDir("")
+	if err := ValidateAPIKeyFileRef("file:/run/secrets/key1"); err == nil {
+		t.Fatal("expected error when CCLOAD_SECRETS_DIR is unset")
+	}
+
+	setSecretsDir("/run/secrets")
+	if err := ValidateAPIKeyFileRef("file:/run/secrets/key1"); err != nil {
+		t.Fatalf("path inside secrets dir rejected: %v", err)
+	}
+	for _, ref := range []string{
+		"file:",
+		"file:relative/key",
+		"file:/run/secrets",
+		"file:/run/secrets/../../etc/passwd",
+		"file:/run/secrets-other/key",
+		"file:/proc/self/environ",
+	} {
+		if err := ValidateAPIKeyFileRef(ref); err == nil {
+			t.Fatalf("expected error for %q", ref)
+		}
+	}
+}
+
+func TestSecretFileCache_RejectsOutsideSecretsDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "db.sqlite")
+	if err := os.WriteFile(outside, []byte("private"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cache := NewSecretFileCache(dir, 0)
+	if _, err := cache.Resolve(APIKeyFilePrefix + outside); err == nil {
+		t.Fatal("expected error for file outside secrets dir")
+	}
+
+	// 目录内的软链接指向目录外：解析后拒绝
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlink not supported: %v", err)
+	}
+	if _, err := cache.Resolve(APIKeyFilePrefix + link); err == nil {
+		t.Fatal("expected error for symlink escaping secrets dir")
+	}
+
+	// 未配置目录：拒绝所有文件引用
+	if _, err := NewSecretFileCache("", 0).Resolve(APIKeyFilePrefix + outside); err == nil {
+		t.Fatal("expected error when secrets dir is unset")
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"ccLoad/internal/app"
+	"ccLoad/internal/config"
 	"ccLoad/internal/storage"
 	"ccLoad/internal/util"
 	"ccLoad/internal/version"
@@ -80,6 +82,21 @@ func getTrustedProxies() []string {
 	return proxies
 }
 
+// getDrainTimeout 获取关闭前排空阶段的最长等待时间
+// 环境变量 CCLOAD_DRAIN_TIMEOUT: 秒数，0 表示跳过排空阶段；未设置或无效时使用默认值
+func getDrainTimeout() time.Duration {
+	v := os.Getenv("CCLOAD_DRAIN_TIMEOUT")
+	if v == "" {
+		return config.DefaultDrainTimeout
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		log.Printf("[WARN] 无效的 CCLOAD_DRAIN_TIMEOUT=%s，使用默认值 %v", v, config.DefaultDrainTimeout)
+		return config.DefaultDrainTimeout
+	}
+	return time.Duration(secs) * time.Second
+}
+
 func main() {
 	// 打印启动 Banner
 	version.PrintBanner()
@@ -109,6 +126,11 @@ func main() {
 	// 渠道仅从数据库管理与读取；不再从本地文件初始化。
 
 	srv := app.NewServer(store)
+
+	// 启动自检：汇总渠道/Key/模型/存储状态；CCLOAD_REQUIRE_CHANNELS=true 时无可用渠道则拒绝启动
+	if _, err := srv.RunStartupSelfTest(context.Background(), util.ParseBoolDefault(os.Getenv("CCLOAD_REQUIRE_CHANNELS"), false)); err != nil {
+		log.Fatalf("[FATAL] 启动自检失败（CCLOAD_REQUIRE_CHANNELS=true）: %v", err)
+	}
 	srv.StartModelCatalogSync()
 
 	// 注入重启函数（避免循环依赖）
@@ -203,6 +225,17 @@ func main() {
 
 	log.Println("收到关闭信号，正在优雅关闭服务器...")
 
+	// 排空阶段：拒绝新代理请求，等待进行中请求（含流式）完成
+	if drainTimeout := getDrainTimeout(); drainTimeout > 0 {
+		srv.StartDrain()
+		log.Printf("[INFO] 进入排空模式，等待 %d 个进行中请求完成（最长 %v）", srv.InFlightProxyRequests(), drainTimeout)
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+		if err := srv.WaitDrained(drainCtx); err != nil {
+			log.Printf("[WARN] 排空超时，仍有 %d 个进行中请求，继续关闭", srv.InFlightProxyRequests())
+		}
+		drainCancel()
+	}
+
 	// 设置5秒超时用于HTTP服务器关闭
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
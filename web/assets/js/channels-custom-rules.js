@@ -19,7 +19,7 @@
   const AUTH_BLACKLIST = new Set(['authorization', 'x-api-key', 'x-goog-api-key']);
 
   const HEADER_ACTIONS = ['override', 'append', 'remove'];
-  const BODY_ACTIONS = ['override', 'remove'];
+  const BODY_ACTIONS = ['override', 'default', 'remove'];
 
   const hasWindow = typeof window !== 'undefined';
   const hasDocument = typeof document !== 'undefined';
@@ -146,7 +146,7 @@
         errors.push(`${label} ${t('channels.customRules.errInvalid', 'Invalid rule')}`);
         return;
       }
-      if (!['remove', 'override'].includes(rule.action)) {
+      if (!BODY_ACTIONS.includes(rule.action)) {
         errors.push(`${label} ${t('channels.customRules.errAction', 'Invalid action')}`);
         return;
       }
@@ -161,6 +161,9 @@
       if (!PATH_REGEX.test(path)) {
         errors.push(`${label} ${t('channels.customRules.errPathChars', 'Invalid path characters')}`);
       }
+      if (rule.action === 'default' && path === 'stream') {
+        errors.push(`${label} ${t('channels.customRules.errDefaultStream', 'stream cannot use a default value')}`);
+      }
       if (rule.action !== 'remove') {
         const val = typeof rule.value === 'string' ? rule.value : '';
         if (!val) {
@@ -463,7 +466,7 @@
     return 'Rewrite HTTP headers sent to upstream.\nActions: remove / override / append.\nremove: empty value deletes the header; non-empty value removes only that comma-separated token (e.g. remove "context-1m-2025-08-07" from Anthropic-Beta).\nAuth headers (Authorization / x-api-key / x-goog-api-key) are protected.';
   }
   function defaultHelpBody() {
-    return 'Rewrite JSON body fields.\nActions: remove / override.\nPath uses dots + integer indices (messages.0.role).\nValues are JSON literals — strings need quotes.';
+    return 'Rewrite JSON body fields.\nActions: remove / override / default (only when the client omitted the field).\nPath uses dots + integer indices (messages.0.role).\nValues are JSON literals — strings need quotes.';
   }
 
   function bindTabDelegation() {
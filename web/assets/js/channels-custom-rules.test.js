@@ -159,3 +159,17 @@ test('collectCustomRulesForSubmit remove 头带值表示 token 精确移除', ()
   assert.equal(payload.headers[1].name, 'User-Agent');
   assert.ok(!('value' in payload.headers[1]), 'remove + 空值不应包含 value');
 });
+
+test('validateRulesLocally 接受 default 动作但拒绝 stream', () => {
+  const ok = validateRulesLocally({
+    headers: [],
+    body: [{ action: 'default', path: 'temperature', value: '0.7' }]
+  });
+  assert.deepEqual(ok, []);
+
+  const errors = validateRulesLocally({
+    headers: [],
+    body: [{ action: 'default', path: 'stream', value: 'true' }]
+  });
+  assert.equal(errors.length, 1);
+});
@@ -249,6 +249,16 @@ function renderVirtualRows(tbody, visibleStart, visibleEnd, filteredIndices) {
  */
 function buildCooldownHtml(index) {
   const keyCooldown = currentChannelKeyCooldowns.find(kc => kc.key_index === index);
+  const html = buildKeyStatusBadgeHtml(keyCooldown);
+  if (!keyCooldown) return html;
+  // 悬停显示最后成功使用时间，便于识别长期闲置的 Key
+  const lastUsedTitle = keyCooldown.last_used_at > 0
+    ? window.t('channels.keyLastUsed', { time: new Date(keyCooldown.last_used_at).toLocaleString() })
+    : window.t('channels.keyNeverUsed');
+  return `<span title="${escapeHtml(lastUsedTitle)}">${html}</span>`;
+}
+
+function buildKeyStatusBadgeHtml(keyCooldown) {
   if (keyCooldown && keyCooldown.disabled) {
     // 与 URL 表的禁用徽章保持一致：橙色圆点 + 文字
     return '<span class="inline-url-status-badge inline-url-status-badge--disabled">'
@@ -815,7 +825,8 @@ async function refreshKeyCooldownStatus() {
       const cooldownUntilMs = Number.isFinite(cooldownUntilSeconds) ? cooldownUntilSeconds * 1000 : 0;
       const remainingMs = Math.max(0, cooldownUntilMs - now);
       const disabled = apiKey && typeof apiKey === 'object' ? Boolean(apiKey.disabled) : false;
-      metaByKey.set(key, { remainingMs, disabled });
+      const lastUsedAt = apiKey && typeof apiKey === 'object' ? Number(apiKey.last_used_at || 0) : 0;
+      metaByKey.set(key, { remainingMs, disabled, lastUsedAt });
     });
 
     currentChannelKeyCooldowns = getInlineKeyRows().map((row, index) => {
@@ -824,7 +835,8 @@ async function refreshKeyCooldownStatus() {
       return {
         key_index: index,
         cooldown_remaining_ms: meta ? meta.remainingMs : 0,
-        disabled: meta ? meta.disabled : false
+        disabled: meta ? meta.disabled : false,
+        last_used_at: meta ? meta.lastUsedAt : 0
       };
     });
 
@@ -59,7 +59,7 @@ function renderProtocolTransformOptions(channelType, selectedValues = []) {
   const container = document.getElementById('protocolTransformsContainer');
   if (!container) return;
 
-  const currentType = window.ChannelProtocolConfig.normalizeProtocol(channelType) || 'anthropic';
+  const currentType = window.ChannelProtocolConfig.channelTypeProtocol(channelType);
   const selected = new Set(normalizeProtocolTransformSelection(currentType, selectedValues));
   const options = window.ChannelProtocolConfig.getProtocolTransformRenderOptions(currentType);
   container.innerHTML = options.map((protocol) => {
@@ -469,6 +469,8 @@ async function editChannel(id) {
   document.getElementById('channelPriority').value = channel.priority;
   document.getElementById('channelRPMLimit').value = channel.rpm_limit || 0;
   document.getElementById('channelMaxConcurrency').value = String(channel.max_concurrency || 0);
+  document.getElementById('channelMaxInputTokens').value = String(channel.max_input_tokens || 0);
+  document.getElementById('channelMaxOutputTokens').value = String(channel.max_output_tokens || 0);
   document.getElementById('channelDailyCostLimit').value = channel.daily_cost_limit || 0;
   document.getElementById('channelCostMultiplier').value = (Number(channel.cost_multiplier) >= 0 ? Number(channel.cost_multiplier) : 1);
   document.getElementById('channelEnabled').checked = channel.enabled;
@@ -506,6 +508,26 @@ async function editChannel(id) {
   const proxyUrlInput = document.getElementById('channelProxyURL');
   if (proxyUrlInput) proxyUrlInput.value = channel.proxy_url || '';
 
+  const allowedMethodsInput = document.getElementById('channelAllowedMethods');
+  if (allowedMethodsInput) allowedMethodsInput.value = (channel.allowed_methods || []).join(',');
+
+  const redirectRoutingOnlyInput = document.getElementById('channelRedirectRoutingOnly');
+  if (redirectRoutingOnlyInput) redirectRoutingOnlyInput.checked = !!channel.redirect_routing_only;
+  const cacheableInput = document.getElementById('channelCacheable');
+  if (cacheableInput) cacheableInput.checked = !!channel.cacheable;
+  const restoreResponseModelInput = document.getElementById('channelRestoreResponseModel');
+  if (restoreResponseModelInput) restoreResponseModelInput.checked = !!channel.restore_response_model;
+  const successCodesInput = document.getElementById('channelSuccessCodes');
+  if (successCodesInput) successCodesInput.value = channel.success_codes || '';
+  const activeScheduleInput = document.getElementById('channelActiveSchedule');
+  if (activeScheduleInput) activeScheduleInput.value = channel.active_schedule || '';
+  const blockedModelsInput = document.getElementById('channelBlockedModels');
+  if (blockedModelsInput) blockedModelsInput.value = (channel.blocked_models || []).join(',');
+  const stripHeadersInput = document.getElementById('channelStripHeaders');
+  if (stripHeadersInput) stripHeadersInput.value = (channel.strip_headers || []).join(',');
+  const retryErrorSubstringsInput = document.getElementById('channelRetryErrorSubstrings');
+  if (retryErrorSubstringsInput) retryErrorSubstringsInput.value = (channel.retry_error_substrings || []).join('|');
+
   resetChannelFormDirty();
   document.getElementById('channelModal').classList.add('show');
   scheduleChannelEditorTableSizingSync();
@@ -736,6 +758,8 @@ async function saveChannel(event) {
     priority: parseInt(document.getElementById('channelPriority').value) || 0,
     rpm_limit: parseInt(document.getElementById('channelRPMLimit').value) || 0,
     max_concurrency: parseInt(document.getElementById('channelMaxConcurrency').value) || 0,
+    max_input_tokens: parseInt(document.getElementById('channelMaxInputTokens').value) || 0,
+    max_output_tokens: parseInt(document.getElementById('channelMaxOutputTokens').value) || 0,
     daily_cost_limit: parseFloat(document.getElementById('channelDailyCostLimit').value) || 0,
     cost_multiplier: (function () {
       const v = parseFloat(document.getElementById('channelCostMultiplier').value);
@@ -746,7 +770,20 @@ async function saveChannel(event) {
     scheduled_check_enabled: document.getElementById('channelScheduledCheckEnabled').checked,
     scheduled_check_model: document.getElementById('channelScheduledCheckModel').value.trim(),
     custom_request_rules: invokeChannelEditorAction('collectCustomRulesForSubmit') || null,
-    proxy_url: (document.getElementById('channelProxyURL')?.value || '').trim()
+    proxy_url: (document.getElementById('channelProxyURL')?.value || '').trim(),
+    allowed_methods: (document.getElementById('channelAllowedMethods')?.value || '')
+      .split(',').map(m => m.trim().toUpperCase()).filter(Boolean),
+    redirect_routing_only: !!document.getElementById('channelRedirectRoutingOnly')?.checked,
+    cacheable: !!document.getElementById('channelCacheable')?.checked,
+    restore_response_model: !!document.getElementById('channelRestoreResponseModel')?.checked,
+    success_codes: (document.getElementById('channelSuccessCodes')?.value || '').trim(),
+    active_schedule: (document.getElementById('channelActiveSchedule')?.value || '').trim(),
+    blocked_models: (document.getElementById('channelBlockedModels')?.value || '')
+      .split(',').map(m => m.trim()).filter(Boolean),
+    strip_headers: (document.getElementById('channelStripHeaders')?.value || '')
+      .split(',').map(h => h.trim()).filter(Boolean),
+    retry_error_substrings: (document.getElementById('channelRetryErrorSubstrings')?.value || '')
+      .split('|').map(s => s.trim()).filter(Boolean)
   };
 
   if (!formData.name || !formData.url || !formData.api_key || formData.models.length === 0) {
@@ -1427,6 +1464,8 @@ async function copyChannel(id, name) {
   document.getElementById('channelPriority').value = channel.priority;
   document.getElementById('channelRPMLimit').value = channel.rpm_limit || 0;
   document.getElementById('channelMaxConcurrency').value = String(channel.max_concurrency || 0);
+  document.getElementById('channelMaxInputTokens').value = String(channel.max_input_tokens || 0);
+  document.getElementById('channelMaxOutputTokens').value = String(channel.max_output_tokens || 0);
   document.getElementById('channelDailyCostLimit').value = channel.daily_cost_limit || 0;
   document.getElementById('channelCostMultiplier').value = (Number(channel.cost_multiplier) >= 0 ? Number(channel.cost_multiplier) : 1);
   document.getElementById('channelEnabled').checked = true;
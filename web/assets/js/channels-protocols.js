@@ -1,6 +1,8 @@
 (function initChannelProtocolConfig(global) {
   const ALL_PROTOCOLS = Object.freeze(['anthropic', 'codex', 'openai', 'gemini']);
   const PROTOCOL_TRANSFORM_MODES = Object.freeze(['upstream', 'local']);
+  // 渠道类型 → 原生上游协议（未列出的类型与协议同名）
+  const CHANNEL_TYPE_PROTOCOLS = Object.freeze({ azure: 'openai' });
   const SUPPORTED_TRANSFORMS_BY_CHANNEL_TYPE = Object.freeze(
     Object.fromEntries(
       ALL_PROTOCOLS.map((protocol) => [
@@ -14,12 +16,17 @@
     return String(value || '').trim().toLowerCase();
   }
 
+  function channelTypeProtocol(channelType) {
+    const type = normalizeProtocol(channelType) || 'anthropic';
+    return CHANNEL_TYPE_PROTOCOLS[type] || type;
+  }
+
   function normalizeProtocolTransformMode(value) {
     return String(value || '').trim().toLowerCase() === 'local' ? 'local' : 'upstream';
   }
 
   function getSupportedProtocolTransforms(channelType) {
-    const baseType = normalizeProtocol(channelType) || 'anthropic';
+    const baseType = channelTypeProtocol(channelType);
     return [...(SUPPORTED_TRANSFORMS_BY_CHANNEL_TYPE[baseType] || [])];
   }
 
@@ -28,7 +35,7 @@
   }
 
   function normalizeProtocolTransformsForChannel(channelType, selectedValues) {
-    const baseType = normalizeProtocol(channelType) || 'anthropic';
+    const baseType = channelTypeProtocol(channelType);
     const allowed = new Set(getSupportedProtocolTransforms(baseType));
     const selected = new Set();
 
@@ -48,6 +55,7 @@
       Object.entries(SUPPORTED_TRANSFORMS_BY_CHANNEL_TYPE).map(([key, values]) => [key, [...values]])
     ),
     normalizeProtocol,
+    channelTypeProtocol,
     normalizeProtocolTransformMode,
     getSupportedProtocolTransforms,
     getProtocolTransformRenderOptions,
@@ -344,7 +344,10 @@ function inlineCooldownBadge(c) {
   const ms = c.cooldown_remaining_ms || 0;
   if (!ms || ms <= 0) return '';
   const text = humanizeMS(ms);
-  return `<span style="display: inline-flex; align-items: center; color: #dc2626; font-size: 0.68rem; font-weight: 600; line-height: 1; background: linear-gradient(135deg, #fee2e2 0%, #fecaca 100%); padding: 1px 6px; border-radius: 4px; border: 1px solid #fca5a5; vertical-align: middle;">${window.t('channels.cooldownBadge', { time: text })}</span>`;
+  const label = c.circuit_open
+    ? window.t('channels.circuitOpenBadge', { time: text, count: c.consecutive_failures || 0 })
+    : window.t('channels.cooldownBadge', { time: text });
+  return `<span style="display: inline-flex; align-items: center; color: #dc2626; font-size: 0.68rem; font-weight: 600; line-height: 1; background: linear-gradient(135deg, #fee2e2 0%, #fecaca 100%); padding: 1px 6px; border-radius: 4px; border: 1px solid #fca5a5; vertical-align: middle;">${label}</span>`;
 }
 
 /**
@@ -377,6 +380,12 @@ function getChannelTypeConfig(channelType) {
       color: '#2563eb',
       bgColor: '#dbeafe',
       borderColor: '#93c5fd'
+    },
+    'azure': {
+      text: 'Azure',
+      color: '#0369a1',
+      bgColor: '#e0f2fe',
+      borderColor: '#7dd3fc'
     }
   };
   const type = (channelType || '').toLowerCase();
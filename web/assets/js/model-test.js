@@ -1269,7 +1269,9 @@ function getModelName(entry) {
 }
 
 function getChannelType(channel) {
-  return normalizeProtocol(channel?.channel_type) || 'anthropic';
+  const type = normalizeProtocol(channel?.channel_type) || 'anthropic';
+  // azure 渠道走 OpenAI 协议
+  return type === 'azure' ? 'openai' : type;
 }
 
 function getAvailableChannelTypes() {
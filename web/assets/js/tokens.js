@@ -529,6 +529,14 @@
     }
 
     function parseMaxConcurrencyInput(rawValue) {
+      return parseNonNegativeIntInput(rawValue, 'tokens.msg.maxConcurrencyInteger');
+    }
+
+    function parseRPMLimitInput(rawValue) {
+      return parseNonNegativeIntInput(rawValue, 'tokens.msg.rpmLimitInteger');
+    }
+
+    function parseNonNegativeIntInput(rawValue, errorKey) {
       const normalized = String(rawValue ?? '').trim();
       if (normalized === '') {
         return { value: 0 };
@@ -536,7 +544,7 @@
 
       const parsed = Number(normalized);
       if (!Number.isFinite(parsed) || !Number.isInteger(parsed) || parsed < 0) {
-        return { error: t('tokens.msg.maxConcurrencyInteger') };
+        return { error: t(errorKey) };
       }
 
       return { value: parsed };
@@ -630,6 +638,7 @@
       document.getElementById('tokenExpiry').value = 'never';
       document.getElementById('tokenCostLimitUSD').value = 0;
       document.getElementById('tokenMaxConcurrency').value = 0;
+      document.getElementById('tokenRPMLimit').value = 0;
       document.getElementById('tokenActive').checked = true;
       document.getElementById('customExpiryContainer').style.display = 'none';
       document.getElementById('createModal').style.display = 'block';
@@ -664,6 +673,7 @@
       const isActive = document.getElementById('tokenActive').checked;
       const costLimitUSD = parseFloat(document.getElementById('tokenCostLimitUSD').value) || 0;
       const maxConcurrencyResult = parseMaxConcurrencyInput(document.getElementById('tokenMaxConcurrency').value);
+      const rpmLimitResult = parseRPMLimitInput(document.getElementById('tokenRPMLimit').value);
       if (costLimitUSD < 0) {
         window.showNotification(t('tokens.msg.costLimitNegative'), 'error');
         return;
@@ -673,13 +683,18 @@
         return;
       }
       const maxConcurrency = maxConcurrencyResult.value;
+      if (rpmLimitResult.error) {
+        window.showNotification(rpmLimitResult.error, 'error');
+        return;
+      }
+      const rpmLimit = rpmLimitResult.value;
       try {
         const data = await fetchDataWithAuth(`${API_BASE}/auth-tokens`, {
           method: 'POST',
           headers: {
             'Content-Type': 'application/json'
           },
-          body: JSON.stringify({ description, expires_at: expiresAt, is_active: isActive, cost_limit_usd: costLimitUSD, max_concurrency: maxConcurrency })
+          body: JSON.stringify({ description, expires_at: expiresAt, is_active: isActive, cost_limit_usd: costLimitUSD, max_concurrency: maxConcurrency, rpm_limit: rpmLimit })
         });
 
         closeCreateModal();
@@ -740,6 +755,7 @@
 
       const maxConcurrencyInput = document.getElementById('editMaxConcurrency');
       maxConcurrencyInput.value = token.max_concurrency || 0;
+      document.getElementById('editRPMLimit').value = token.rpm_limit || 0;
 
       // 初始化模型限制状态（2026-01新增）
       editAllowedModels = (token.allowed_models || []).slice();
@@ -786,6 +802,7 @@
       const expiryType = document.getElementById('editTokenExpiry').value;
       const costLimitUSD = parseFloat(document.getElementById('editCostLimitUSD').value) || 0;
       const maxConcurrencyResult = parseMaxConcurrencyInput(document.getElementById('editMaxConcurrency').value);
+      const rpmLimitResult = parseRPMLimitInput(document.getElementById('editRPMLimit').value);
       if (costLimitUSD < 0) {
         window.showNotification(t('tokens.msg.costLimitNegative'), 'error');
         return;
@@ -795,6 +812,11 @@
         return;
       }
       const maxConcurrency = maxConcurrencyResult.value;
+      if (rpmLimitResult.error) {
+        window.showNotification(rpmLimitResult.error, 'error');
+        return;
+      }
+      const rpmLimit = rpmLimitResult.value;
       let expiresAt = null;
       if (expiryType !== 'never') {
         if (expiryType === 'custom') {
@@ -823,7 +845,8 @@
             channel_restriction_mode: normalizeChannelRestrictionMode(editChannelRestrictionMode),
             allowed_models: editAllowedModels,  // 2026-01新增：模型限制
             cost_limit_usd: costLimitUSD,        // 2026-01新增：费用上限
-            max_concurrency: maxConcurrency      // 2026-04新增：并发上限
+            max_concurrency: maxConcurrency,     // 2026-04新增：并发上限
+            rpm_limit: rpmLimit                  // 每分钟请求上限
           })
         });
         closeEditModal();
@@ -297,6 +297,7 @@
 
         updateChannelFilter();
         renderChart();
+        startMetricsStream(new URLSearchParams(metricsParams));
 
         // 更新分桶提示
         const iv = document.getElementById('bucket-interval');
@@ -315,6 +316,75 @@
       }
     }
 
+    // ─── 实时指标流 ─────────────────────────────────────────────────────────
+    // 服务端每 5 秒推送最近两个桶（update 事件），按 ts 合并进 trendData；范围不含当前时刻时服务端直接结束。
+    // EventSource 不能带 Authorization，用 fetch + ReadableStream。
+    let metricsStreamAbort = null;
+
+    function stopMetricsStream() {
+      if (metricsStreamAbort) {
+        metricsStreamAbort.abort();
+        metricsStreamAbort = null;
+      }
+    }
+
+    async function startMetricsStream(params) {
+      stopMetricsStream();
+      const controller = new AbortController();
+      metricsStreamAbort = controller;
+      params.set('snapshot', 'false');
+
+      try {
+        const resp = await fetchWithAuth('/dashboard/metrics/stream?' + params.toString(), { signal: controller.signal });
+        if (!resp.ok || !resp.body) return;
+
+        const reader = resp.body.getReader();
+        const decoder = new TextDecoder();
+        let buf = '';
+        while (true) {
+          const { done, value } = await reader.read();
+          if (done) break;
+          buf += decoder.decode(value, { stream: true });
+
+          let idx;
+          while ((idx = buf.indexOf('\n\n')) !== -1) {
+            const block = buf.slice(0, idx);
+            buf = buf.slice(idx + 2);
+            let event = '';
+            let payload = '';
+            for (const line of block.split('\n')) {
+              if (line.startsWith('event:')) event = line.slice(6).trim();
+              else if (line.startsWith('data:')) payload = line.slice(5).trim();
+            }
+            if (event !== 'update' || !payload) continue;
+            try {
+              mergeTrendPoints(JSON.parse(payload));
+            } catch (_) { /* 忽略单条坏事件 */ }
+          }
+        }
+      } catch (err) {
+        if (!controller.signal.aborted) console.warn('实时指标流中断:', err);
+      }
+    }
+
+    // mergeTrendPoints 按 ts 覆盖已有桶，晚于末尾的新桶追加
+    function mergeTrendPoints(points) {
+      if (!Array.isArray(points) || points.length === 0 || !Array.isArray(window.trendData)) return;
+      const data = window.trendData;
+      const indexByTs = new Map(data.map((p, i) => [Date.parse(p.ts), i]));
+      for (const point of points) {
+        const ts = Date.parse(point.ts);
+        const i = indexByTs.get(ts);
+        if (i !== undefined) {
+          data[i] = point;
+        } else if (data.length === 0 || ts > Date.parse(data[data.length - 1].ts)) {
+          indexByTs.set(ts, data.push(point) - 1);
+        }
+      }
+      buildChannelDataCache(data);
+      renderChart();
+    }
+
     function computeBucketMin(hours) {
       if (hours <= 1) return 1; // 1分钟
       if (hours <= 6) return 2; // 2分钟
@@ -252,10 +252,37 @@ window.I18N_LOCALES['en'] = {
   'channels.rpmLimitPlaceholder': '0=No limit',
   'channels.maxConcurrency': 'Concurrency Limit',
   'channels.maxConcurrencyPlaceholder': '0=No limit',
+  'channels.maxInputTokens': 'Max Input',
+  'channels.maxInputTokensHint': 'Requests whose estimated input tokens exceed this value skip the channel',
+  'channels.maxOutputTokens': 'Max Output',
+  'channels.maxOutputTokensHint': 'Caps max_tokens in the request; injected when missing',
   'channels.costMultiplier': 'Cost Multiplier',
   'channels.costMultiplierPlaceholder': 'Default 1',
   'channels.proxyURL': 'Proxy',
   'channels.proxyURLPlaceholder': 'http:// | socks5://',
+  'channels.allowedMethods': 'Allowed methods',
+  'channels.allowedMethodsPlaceholder': 'POST,GET (empty = allow all)',
+  'channels.redirectRoutingOnly': 'Redirect for routing only',
+  'channels.redirectRoutingOnlyHint': 'Model redirects only affect matching, logs and billing; the upstream request keeps the client model name',
+  'channels.cacheable': 'Cache responses',
+  'channels.cacheableHint': 'Identical non-streaming POST requests (same model and body) are answered from an in-memory cache within the TTL, e.g. embeddings',
+  'channels.restoreResponseModel': 'Restore model in responses',
+  'channels.restoreResponseModelHint': 'When a model redirect applies, rewrite the model field in streaming and non-streaming responses back to the model the client requested',
+  'channels.successCodes': 'Success codes',
+  'channels.successCodesPlaceholder': '200-299,404 (empty = 2xx)',
+  'channels.successCodesHint': 'Upstream status codes treated as success; anything else is retried or cooled down',
+  'channels.activeSchedule': 'Active schedule',
+  'channels.activeSchedulePlaceholder': 'Mon-Fri 22:00-08:00 Asia/Shanghai (empty = always)',
+  'channels.activeScheduleHint': 'Only select this channel inside these time windows; weekdays and timezone are optional',
+  'channels.blockedModels': 'Blocked models',
+  'channels.blockedModelsPlaceholder': 'gpt-4-0314,claude-2 (empty = none)',
+  'channels.blockedModelsHint': 'Never select this channel for these requested models, even if the model list contains them',
+  'channels.stripHeaders': 'Strip headers',
+  'channels.stripHeadersPlaceholder': 'Cookie,X-Internal-* (empty = defaults only)',
+  'channels.stripHeadersHint': 'Client request headers never forwarded to this channel, in addition to the global defaults; a trailing * matches a prefix',
+  'channels.retryErrorSubstrings': 'Retry on errors',
+  'channels.retryErrorSubstringsPlaceholder': 'overloaded|try again later (separate with |)',
+  'channels.retryErrorSubstringsHint': 'If an upstream error body contains any of these substrings (case-insensitive), switch to another channel even when the status code would normally be returned to the client',
 
   // Delete Confirmation (flattened keys)
   'channels.confirmDeleteTitle': 'Confirm Delete',
@@ -471,6 +498,8 @@ window.I18N_LOCALES['en'] = {
   'tokens.zeroUnlimitedHint': '0 means unlimited',
   'tokens.maxConcurrencyLabel': 'Concurrency Limit',
   'tokens.maxConcurrencyPlaceholder': '0 means unlimited',
+  'tokens.rpmLimitLabel': 'RPM Limit',
+  'tokens.rpmLimitHint': 'Requests per minute, 0 means unlimited',
   'tokens.enableToken': 'Enable token',
   'tokens.createBtn': 'Create',
   // Token result modal
@@ -574,6 +603,7 @@ window.I18N_LOCALES['en'] = {
   'tokens.msg.costLimitNegative': 'Cost limit cannot be negative',
   'tokens.msg.maxConcurrencyNegative': 'Concurrency limit cannot be negative',
   'tokens.msg.maxConcurrencyInteger': 'Concurrency limit must be a non-negative integer',
+  'tokens.msg.rpmLimitInteger': 'RPM limit must be a non-negative integer',
   'tokens.msg.createSuccess': 'Token created successfully',
   'tokens.msg.createFailed': 'Failed to create',
   'tokens.msg.updateSuccess': 'Update successful',
@@ -1067,6 +1097,8 @@ window.I18N_LOCALES['en'] = {
   'settings.desc.channel_check_interval_hours': 'Scheduled channel check interval (hours, decimals ok e.g. 0.5 = 30 min, 0 = disabled, restart required)',
   'settings.desc.auto_update_interval_hours': 'Auto-update check interval (integer hours, 0 = disabled, minimum 1 hour when enabled)',
   'settings.desc.channel_stats_range': 'Channel stats cost time range',
+  'settings.desc.model_monthly_budgets': 'Per-model monthly budget (JSON, e.g. {"gpt-4o":100}, USD, effective cost after multiplier)',
+  'settings.desc.model_budget_enforce': 'Reject requests for models over their monthly budget (returns 402)',
   'settings.desc.enable_health_score': 'Enable dynamic channel sorting based on health score',
   'settings.desc.success_rate_penalty_weight': 'Success rate penalty weight (multiplied by failure rate)',
   'settings.desc.health_score_window_minutes': 'Success rate statistics window (minutes)',
@@ -1077,7 +1109,11 @@ window.I18N_LOCALES['en'] = {
   'settings.desc.ttfb_max_slow_ratio': 'Max relative TTFB slowness ratio (s-1)',
   'settings.desc.ttfb_min_confident_sample': 'TTFB confidence sample threshold',
   'settings.desc.cooldown_fallback_enabled': 'Use best cooldown channel as fallback when all channels in cooldown (otherwise reject request)',
+  'settings.desc.channel_pin_fallback': 'Fall back to normal routing when the channel pinned via X-CCLoad-Channel is unavailable (off = return 503; restart required)',
+  'settings.desc.circuit_breaker_threshold': 'Channel circuit breaker threshold (open after N consecutive cooldown cycles without success, 0 = disabled; restart required)',
+  'settings.desc.circuit_breaker_reset_minutes': 'Channel circuit breaker duration (minutes; a probe request is allowed afterwards, a successful manual test closes it immediately; restart required)',
   'settings.desc.log_channel_click_action': 'Log page channel click action (edit=open editor, navigate=jump to channel list position)',
+  'settings.desc.maintenance_mode': 'Maintenance mode (read-only: proxy keeps serving, admin mutations return 503; prefer toggling via the maintenance endpoints)',
   'settings.desc.debug_log_enabled': 'Enable debug logging (record raw upstream request/response data)',
   'settings.desc.debug_log_retention_minutes': 'Debug log retention duration (minutes, 1-1440)',
   'settings.desc.auto_refresh_interval_seconds': 'Page auto-refresh interval (seconds, 0 = disabled, recommended ≥30; skipped while a modal is open)',
@@ -1131,7 +1167,10 @@ window.I18N_LOCALES['en'] = {
   // Status and Badges
   'channels.cooldownStatus': 'Cooldown',
   'channels.statusNormal': 'Normal',
+  'channels.keyLastUsed': 'Last used: {time}',
+  'channels.keyNeverUsed': 'Never used',
   'channels.cooldownBadge': '⚠️ Cooldown·{time}',
+  'channels.circuitOpenBadge': '⛔ Circuit open·{time} ({count} failed cycles)',
   'channels.testThisUrl': 'Test this URL',
   'channels.statusEnabled': 'Enabled',
   'channels.statusDisabled': 'Disabled',
@@ -1372,6 +1411,7 @@ window.I18N_LOCALES['en'] = {
   'channels.customRules.action_remove': 'Remove',
   'channels.customRules.action_override': 'Override',
   'channels.customRules.action_append': 'Append',
+  'channels.customRules.action_default': 'Default (if unset)',
   'channels.customRules.empty': 'No rules yet. Click the button below to add one.',
   'channels.customRules.errMaxHeaders': 'Too many header rules (max 32)',
   'channels.customRules.errMaxBody': 'Too many body rules (max 32)',
@@ -1386,10 +1426,11 @@ window.I18N_LOCALES['en'] = {
   'channels.customRules.errPath': 'Path required',
   'channels.customRules.errPathTooLong': 'Path too long (max 256)',
   'channels.customRules.errPathChars': 'Path may only contain letters, digits, _, -, .',
+  'channels.customRules.errDefaultStream': 'stream cannot use a default value',
   'channels.customRules.errBodyValueEmpty': 'Value required',
   'channels.customRules.errBodyValueJSON': 'Value must be a valid JSON literal (strings need quotes)',
   'channels.customRules.helpHeaders': 'Rewrite HTTP headers sent to the upstream.\n\nActions:\n • Remove: empty value deletes the entire header; non-empty value removes only that comma-separated token (e.g. remove context-1m-2025-08-07 from Anthropic-Beta while keeping other flags)\n • Override: set or replace the header value\n • Append: add another value for multi-valued headers\n\nExamples:\n 1. Remove User-Agent → action=remove, name=User-Agent, value empty\n 2. Remove a single flag from Anthropic-Beta → action=remove, name=Anthropic-Beta, value=context-1m-2025-08-07\n 3. Force API version → action=override, name=X-Api-Version, value=2025-08-07\n 4. Append Accept → action=append, name=Accept, value=application/xml\n\nNote: Authorization / x-api-key / x-goog-api-key are auth headers and cannot be customized.',
-  'channels.customRules.helpBody': 'Rewrite fields in the JSON request body sent to the upstream (JSON bodies only; binary/form requests are skipped).\n\nActions:\n • Remove: delete a field by path\n • Override: set a value by path (creates missing parents)\n\nPath syntax: dotted paths with integer array indices\n • top-level: temperature\n • nested: thinking.budget_tokens\n • array: messages.0.role\n\nValues accept any JSON literal:\n • number: 0.7\n • boolean: true\n • string: must be quoted "claude-opus-4-5"\n • object: {"type":"adaptive"}\n • array: ["a","b"]\n\nExamples:\n 1. Enable adaptive thinking → action=override, path=thinking, value={"type":"adaptive"}\n 2. Cap max_tokens → action=override, path=max_tokens, value=4096\n 3. Remove stop_sequences → action=remove, path=stop_sequences',
+  'channels.customRules.helpBody': 'Rewrite fields in the JSON request body sent to the upstream (JSON bodies only; binary/form requests are skipped).\n\nActions:\n • Remove: delete a field by path\n • Override: set a value by path (creates missing parents)\n • Default: set a value only when the client did not send the field (an existing object is kept as-is, not merged; stream cannot be defaulted)\n\nPath syntax: dotted paths with integer array indices\n • top-level: temperature\n • nested: thinking.budget_tokens\n • array: messages.0.role\n\nValues accept any JSON literal:\n • number: 0.7\n • boolean: true\n • string: must be quoted "claude-opus-4-5"\n • object: {"type":"adaptive"}\n • array: ["a","b"]\n\nExamples:\n 1. Enable adaptive thinking → action=override, path=thinking, value={"type":"adaptive"}\n 2. Cap max_tokens → action=override, path=max_tokens, value=4096\n 3. Remove stop_sequences → action=remove, path=stop_sequences\n 4. Default temperature → action=default, path=temperature, value=0.7',
   'channels.customRules.anyrouterHintTitle': 'System auto-injected rules (anyrouter channel)',
   'channels.customRules.anyrouterHintBeta': 'Append header anthropic-beta: context-1m-2025-08-07 (can be overridden or removed by your rules below)',
   'channels.customRules.anyrouterHintThinking': 'For /v1/messages, missing thinking gets thinking.type=adaptive; legacy thinking.type=enabled is normalized to output_config.effort'
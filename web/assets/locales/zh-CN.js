@@ -252,10 +252,37 @@ window.I18N_LOCALES['zh-CN'] = {
   'channels.rpmLimitPlaceholder': '0=无限制',
   'channels.maxConcurrency': '并发限制',
   'channels.maxConcurrencyPlaceholder': '0=无限制',
+  'channels.maxInputTokens': '输入上限',
+  'channels.maxInputTokensHint': '估算输入 token 超过该值的请求跳过此渠道',
+  'channels.maxOutputTokens': '输出上限',
+  'channels.maxOutputTokensHint': '封顶请求中的 max_tokens，未携带时自动注入',
   'channels.costMultiplier': '成本倍率',
   'channels.costMultiplierPlaceholder': '默认1',
   'channels.proxyURL': '代理',
   'channels.proxyURLPlaceholder': 'http:// | socks5://',
+  'channels.allowedMethods': '允许方法',
+  'channels.allowedMethodsPlaceholder': 'POST,GET（留空=不限制）',
+  'channels.redirectRoutingOnly': '重定向仅用于路由',
+  'channels.redirectRoutingOnlyHint': '模型重定向只影响匹配、日志与计费，上游请求保留客户端原始模型名',
+  'channels.cacheable': '缓存响应',
+  'channels.cacheableHint': '相同模型与请求体的非流式 POST 请求在 TTL 内直接返回内存缓存（适合 embeddings 等确定性请求）',
+  'channels.restoreResponseModel': '响应还原模型名',
+  'channels.restoreResponseModelHint': '发生模型重定向时，将流式与非流式响应中的 model 字段改回客户端请求的模型名',
+  'channels.successCodes': '成功状态码',
+  'channels.successCodesPlaceholder': '200-299,404（留空=2xx）',
+  'channels.successCodesHint': '视为成功的上游状态码，其余状态码按错误重试/冷却',
+  'channels.activeSchedule': '启用时段',
+  'channels.activeSchedulePlaceholder': 'Mon-Fri 22:00-08:00 Asia/Shanghai（留空=全天）',
+  'channels.activeScheduleHint': '仅在这些时段内选择该渠道；星期与时区可省略',
+  'channels.blockedModels': '屏蔽模型',
+  'channels.blockedModelsPlaceholder': 'gpt-4-0314,claude-2（留空=不屏蔽）',
+  'channels.blockedModelsHint': '请求这些模型时不选择该渠道，即使模型列表中包含',
+  'channels.stripHeaders': '剥离请求头',
+  'channels.stripHeadersPlaceholder': 'Cookie,X-Internal-*（留空=仅全局默认）',
+  'channels.stripHeadersHint': '在全局默认之外，不转发给该渠道的客户端请求头；以 * 结尾表示前缀匹配',
+  'channels.retryErrorSubstrings': '强制重试错误',
+  'channels.retryErrorSubstringsPlaceholder': 'overloaded|try again later（用 | 分隔）',
+  'channels.retryErrorSubstringsHint': '上游错误体包含任一子串（不区分大小写）时切换到其他渠道重试，即使该状态码通常会直接返回客户端',
 
   // 删除确认（扁平化键名）
   'channels.confirmDeleteTitle': '确认删除',
@@ -470,6 +497,8 @@ window.I18N_LOCALES['zh-CN'] = {
   'tokens.zeroUnlimitedHint': '0 表示无限制',
   'tokens.maxConcurrencyLabel': '并发上限',
   'tokens.maxConcurrencyPlaceholder': '0 表示无限制',
+  'tokens.rpmLimitLabel': 'RPM 上限',
+  'tokens.rpmLimitHint': '每分钟请求数，0 表示无限制',
   'tokens.enableToken': '启用令牌',
   'tokens.createBtn': '创建',
   // 令牌结果对话框
@@ -573,6 +602,7 @@ window.I18N_LOCALES['zh-CN'] = {
   'tokens.msg.costLimitNegative': '费用上限不能为负数',
   'tokens.msg.maxConcurrencyNegative': '并发上限不能为负数',
   'tokens.msg.maxConcurrencyInteger': '并发上限必须是大于等于 0 的整数',
+  'tokens.msg.rpmLimitInteger': 'RPM 上限必须是大于等于 0 的整数',
   'tokens.msg.createSuccess': '令牌创建成功',
   'tokens.msg.createFailed': '创建失败',
   'tokens.msg.updateSuccess': '更新成功',
@@ -1066,6 +1096,8 @@ window.I18N_LOCALES['zh-CN'] = {
   'settings.desc.channel_check_interval_hours': '渠道定时检测间隔(小时,支持小数如0.5=30分钟,0=关闭,修改后重启生效)',
   'settings.desc.auto_update_interval_hours': '自动更新检测间隔(小时整数,0=关闭,启用时最低1小时)',
   'settings.desc.channel_stats_range': '渠道管理费用统计范围',
+  'settings.desc.model_monthly_budgets': '模型月度预算(JSON,如{"gpt-4o":100},单位USD,按倍率后成本统计)',
+  'settings.desc.model_budget_enforce': '模型超出月度预算时拒绝请求(返回402)',
   'settings.desc.enable_health_score': '启用基于健康度的渠道动态排序',
   'settings.desc.success_rate_penalty_weight': '成功率惩罚权重(乘以失败率)',
   'settings.desc.health_score_window_minutes': '成功率统计时间窗口(分钟)',
@@ -1076,7 +1108,11 @@ window.I18N_LOCALES['zh-CN'] = {
   'settings.desc.ttfb_max_slow_ratio': '首字相对慢速比(s-1)上限',
   'settings.desc.ttfb_min_confident_sample': '首字置信样本量阈值',
   'settings.desc.cooldown_fallback_enabled': '所有渠道冷却时选最优渠道兜底(关闭则直接拒绝请求)',
+  'settings.desc.channel_pin_fallback': 'X-CCLoad-Channel 固定的渠道不可用时回退正常选路(关闭则返回503,修改后重启生效)',
+  'settings.desc.circuit_breaker_threshold': '渠道熔断阈值(连续N个冷却周期无成功则熔断,0=禁用,修改后重启生效)',
+  'settings.desc.circuit_breaker_reset_minutes': '渠道熔断持续时间(分钟,到期后放行试探请求;手动测试成功立即恢复,修改后重启生效)',
   'settings.desc.log_channel_click_action': '日志页点击渠道名行为(edit=打开编辑器,navigate=跳转到渠道管理定位)',
+  'settings.desc.maintenance_mode': '维护模式(只读:代理照常,管理端写操作返回503;建议通过维护模式接口切换)',
   'settings.desc.debug_log_enabled': '启用Debug日志(记录上游请求/响应原始数据)',
   'settings.desc.debug_log_retention_minutes': 'Debug日志保留时长(分钟,1-1440)',
   'settings.desc.auto_refresh_interval_seconds': '页面自动刷新间隔(秒,0=禁用,建议≥30;有对话框打开时跳过本次刷新)',
@@ -1130,7 +1166,10 @@ window.I18N_LOCALES['zh-CN'] = {
   // 状态与徽章
   'channels.cooldownStatus': '冷却中',
   'channels.statusNormal': '正常',
+  'channels.keyLastUsed': '最后使用：{time}',
+  'channels.keyNeverUsed': '从未使用',
   'channels.cooldownBadge': '⚠️ 冷却中·{time}',
+  'channels.circuitOpenBadge': '⛔ 熔断中·{time}（连续 {count} 个周期失败）',
   'channels.testThisUrl': '测试此URL',
   'channels.statusEnabled': '已启用',
   'channels.statusDisabled': '已禁用',
@@ -1371,6 +1410,7 @@ window.I18N_LOCALES['zh-CN'] = {
   'channels.customRules.action_remove': '移除',
   'channels.customRules.action_override': '覆盖',
   'channels.customRules.action_append': '追加',
+  'channels.customRules.action_default': '默认值（未设置时）',
   'channels.customRules.empty': '暂无规则，点击下方按钮添加',
   'channels.customRules.errMaxHeaders': '请求头规则不能超过 32 条',
   'channels.customRules.errMaxBody': '请求参数规则不能超过 32 条',
@@ -1385,10 +1425,11 @@ window.I18N_LOCALES['zh-CN'] = {
   'channels.customRules.errPath': '路径不能为空',
   'channels.customRules.errPathTooLong': '路径过长（最大 256）',
   'channels.customRules.errPathChars': '路径只允许字母/数字/下划线/连字符/点',
+  'channels.customRules.errDefaultStream': 'stream 不能设置默认值',
   'channels.customRules.errBodyValueEmpty': '值不能为空',
   'channels.customRules.errBodyValueJSON': '值必须是合法 JSON 字面量（字符串需带引号）',
   'channels.customRules.helpHeaders': '用于在发送给上游前改写 HTTP 请求头。\n\n支持三种动作：\n • 移除 (remove)：值为空时删除整个 Header；值非空时仅按逗号拆分精确移除该 token（例如从 Anthropic-Beta 中移除 context-1m-2025-08-07 而保留其他 flag）\n • 覆盖 (override)：设置或替换 Header 的值\n • 追加 (append)：对多值 Header 追加一个值\n\n示例：\n 1. 移除 User-Agent → 动作=移除, 名称=User-Agent, 值留空\n 2. 从 Anthropic-Beta 精确移除一个 flag → 动作=移除, 名称=Anthropic-Beta, 值=context-1m-2025-08-07\n 3. 强制指定 API 版本 → 动作=覆盖, 名称=X-Api-Version, 值=2025-08-07\n 4. 追加 Accept → 动作=追加, 名称=Accept, 值=application/xml\n\n注意：Authorization / x-api-key / x-goog-api-key 为认证头，不可改写。',
-  'channels.customRules.helpBody': '用于改写发送给上游的 JSON 请求体字段（仅对 JSON body 生效，二进制/表单请求自动跳过）。\n\n支持两种动作：\n • 移除 (remove)：按路径删除字段\n • 覆盖 (override)：按路径设置值（不存在则创建）\n\n路径语法：点分路径 + 数字数组索引\n • 顶层字段：temperature\n • 嵌套字段：thinking.budget_tokens\n • 数组元素：messages.0.role\n\n值支持任意 JSON 字面量：\n • 数字：0.7\n • 布尔：true\n • 字符串：必须带引号 "claude-opus-4-5"\n • 对象：{"type":"adaptive"}\n • 数组：["a","b"]\n\n示例：\n 1. 强制开启自适应思考 → 动作=覆盖, 路径=thinking, 值={"type":"adaptive"}\n 2. 限制 max_tokens → 动作=覆盖, 路径=max_tokens, 值=4096\n 3. 移除 stop_sequences → 动作=移除, 路径=stop_sequences',
+  'channels.customRules.helpBody': '用于改写发送给上游的 JSON 请求体字段（仅对 JSON body 生效，二进制/表单请求自动跳过）。\n\n支持三种动作：\n • 移除 (remove)：按路径删除字段\n • 覆盖 (override)：按路径设置值（不存在则创建）\n • 默认值 (default)：仅当客户端未传该字段时写入（已有对象整体保留，不做合并；不能作用于 stream）\n\n路径语法：点分路径 + 数字数组索引\n • 顶层字段：temperature\n • 嵌套字段：thinking.budget_tokens\n • 数组元素：messages.0.role\n\n值支持任意 JSON 字面量：\n • 数字：0.7\n • 布尔：true\n • 字符串：必须带引号 "claude-opus-4-5"\n • 对象：{"type":"adaptive"}\n • 数组：["a","b"]\n\n示例：\n 1. 强制开启自适应思考 → 动作=覆盖, 路径=thinking, 值={"type":"adaptive"}\n 2. 限制 max_tokens → 动作=覆盖, 路径=max_tokens, 值=4096\n 3. 移除 stop_sequences → 动作=移除, 路径=stop_sequences\n 4. 默认 temperature → 动作=默认值, 路径=temperature, 值=0.7',
   'channels.customRules.anyrouterHintTitle': '系统自动注入规则（anyrouter 渠道）',
   'channels.customRules.anyrouterHintBeta': '请求头追加 anthropic-beta: context-1m-2025-08-07（可被下方自定义规则覆盖或移除）',
   'channels.customRules.anyrouterHintThinking': '/v1/messages 缺失 thinking 时补 thinking.type=adaptive；旧 thinking.type=enabled 会归一为 output_config.effort'